@@ -0,0 +1,110 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+)
+
+// State databases which can be opened at any stored height
+const (
+	ConsensusStateDBType = iota
+	TransactionStateDBType
+	FeatureStateDBType
+	RewardStateDBType
+	SlashStateDBType
+)
+
+// checkHistoricalStateAllowed keeps non archive nodes from serving state older than NonArchiveStateHistory blocks below final view.
+// Archive node (--archive) serves state of every height it has root hashes for.
+func (blockchain *BlockChain) checkHistoricalStateAllowed(finalHeight uint64, height uint64) error {
+	if blockchain.config.ArchiveMode {
+		return nil
+	}
+	if height+NonArchiveStateHistory < finalHeight {
+		return NewBlockChainError(GetHistoricalStateError, fmt.Errorf("state at height %+v is older than %+v blocks from final height %+v, only archive node serves it", height, NonArchiveStateHistory, finalHeight))
+	}
+	return nil
+}
+
+// GetBeaconStateDBAtHeight opens a read-only copy of a beacon state database from the root hash stored for the beacon block at this height
+func (blockchain *BlockChain) GetBeaconStateDBAtHeight(stateDBType int, height uint64) (*statedb.StateDB, error) {
+	if err := blockchain.checkHistoricalStateAllowed(blockchain.BeaconChain.GetFinalView().GetHeight(), height); err != nil {
+		return nil, err
+	}
+	rootsHash, err := blockchain.GetBeaconRootsHash(nil, height)
+	if err != nil {
+		return nil, NewBlockChainError(GetHistoricalStateError, fmt.Errorf("beacon root hash at height %+v not found, error %+v", height, err))
+	}
+	var rootHash common.Hash
+	switch stateDBType {
+	case ConsensusStateDBType:
+		rootHash = rootsHash.ConsensusStateDBRootHash
+	case FeatureStateDBType:
+		rootHash = rootsHash.FeatureStateDBRootHash
+	case RewardStateDBType:
+		rootHash = rootsHash.RewardStateDBRootHash
+	case SlashStateDBType:
+		rootHash = rootsHash.SlashStateDBRootHash
+	default:
+		return nil, NewBlockChainError(GetHistoricalStateError, fmt.Errorf("beacon has no state db type %+v", stateDBType))
+	}
+	return statedb.NewWithPrefixTrie(rootHash, statedb.NewDatabaseAccessWarper(blockchain.GetBeaconChainDatabase()))
+}
+
+// GetShardStateDBAtHeight opens a read-only copy of a shard state database from the root hash stored for the shard block at this height
+func (blockchain *BlockChain) GetShardStateDBAtHeight(shardID byte, stateDBType int, height uint64) (*statedb.StateDB, error) {
+	if int(shardID) >= len(blockchain.ShardChain) {
+		return nil, NewBlockChainError(GetHistoricalStateError, fmt.Errorf("shard %+v not found", shardID))
+	}
+	if err := blockchain.checkHistoricalStateAllowed(blockchain.ShardChain[shardID].GetFinalView().GetHeight(), height); err != nil {
+		return nil, err
+	}
+	rootsHash, err := blockchain.GetShardRootsHash(nil, shardID, height)
+	if err != nil {
+		return nil, NewBlockChainError(GetHistoricalStateError, fmt.Errorf("shard %+v root hash at height %+v not found, error %+v", shardID, height, err))
+	}
+	var rootHash common.Hash
+	switch stateDBType {
+	case ConsensusStateDBType:
+		rootHash = rootsHash.ConsensusStateDBRootHash
+	case TransactionStateDBType:
+		rootHash = rootsHash.TransactionStateDBRootHash
+	case FeatureStateDBType:
+		rootHash = rootsHash.FeatureStateDBRootHash
+	case RewardStateDBType:
+		rootHash = rootsHash.RewardStateDBRootHash
+	case SlashStateDBType:
+		rootHash = rootsHash.SlashStateDBRootHash
+	default:
+		return nil, NewBlockChainError(GetHistoricalStateError, fmt.Errorf("shard has no state db type %+v", stateDBType))
+	}
+	return statedb.NewWithPrefixTrie(rootHash, statedb.NewDatabaseAccessWarper(blockchain.GetShardChainDatabase(shardID)))
+}
+
+// GetShardHeightsAtBeaconHeight returns, for each shard, the latest shard height confirmed by beacon up to beaconHeight.
+// It walks back beacon blocks until every shard state is found.
+func (blockchain *BlockChain) GetShardHeightsAtBeaconHeight(beaconHeight uint64) (map[byte]uint64, error) {
+	activeShards := blockchain.GetBeaconBestState().ActiveShards
+	res := make(map[byte]uint64)
+	for height := beaconHeight; height >= 1 && len(res) < activeShards; height-- {
+		beaconBlock, err := blockchain.GetBeaconBlockByHeightV1(height)
+		if err != nil {
+			return nil, NewBlockChainError(GetHistoricalStateError, err)
+		}
+		for shardID, shardStates := range beaconBlock.Body.ShardState {
+			if _, ok := res[shardID]; ok || len(shardStates) == 0 {
+				continue
+			}
+			res[shardID] = shardStates[len(shardStates)-1].Height
+		}
+	}
+	// shard without any confirmed block is still at genesis
+	for shardID := 0; shardID < activeShards; shardID++ {
+		if _, ok := res[byte(shardID)]; !ok {
+			res[byte(shardID)] = 1
+		}
+	}
+	return res, nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
@@ -192,7 +193,7 @@ func (blockchain *BlockChain) ValidateResponseTransactionFromBeaconInstructions(
 	shardID byte,
 ) error {
 	//mainnet have two block return double when height < REPLACE_STAKINGTX
-	if len(beaconBlocks) > 0 && beaconBlocks[0].GetHeight() < blockchain.config.ChainParams.ReplaceStakingTxHeight {
+	if len(beaconBlocks) > 0 && !blockchain.IsForkActive(forks.ReplaceStakingTx, beaconBlocks[0].GetHeight()) {
 		return nil
 	}
 	return blockchain.ValidateReturnStakingTxFromBeaconInstructions(
@@ -332,6 +333,31 @@ func (blockchain *BlockChain) GetListOutputCoinsByKeyset(keyset *incognitokey.Ke
 	return results, nil
 }
 
+// GetListOutputCoinsByKeysetFromView - same as GetListOutputCoinsByKeyset but read from the transaction state of a given shard view
+// (e.g. final view) instead of the best view, memcache is not used because it only caches best view data
+func (blockchain *BlockChain) GetListOutputCoinsByKeysetFromView(shardView *ShardBestState, keyset *incognitokey.KeySet, tokenID *common.Hash) ([]*privacy.OutputCoin, error) {
+	if keyset == nil {
+		return nil, NewBlockChainError(GetListOutputCoinsByKeysetError, fmt.Errorf("invalid key set, got keyset %+v", keyset))
+	}
+	shardID := shardView.ShardID
+	transactionStateDB := shardView.GetCopiedTransactionStateDB()
+	outCointsInBytes, err := statedb.GetOutcoinsByPubkey(transactionStateDB, *tokenID, keyset.PaymentAddress.Pk[:], shardID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*privacy.OutputCoin, 0)
+	for _, item := range outCointsInBytes {
+		outcoin := &privacy.OutputCoin{}
+		outcoin.Init()
+		outcoin.SetBytes(item)
+		decryptedOut := DecryptOutputCoinByKey(transactionStateDB, outcoin, keyset, tokenID, shardID)
+		if decryptedOut != nil {
+			results = append(results, decryptedOut)
+		}
+	}
+	return results, nil
+}
+
 // CreateAndSaveTxViewPointFromBlock - fetch data from block, put into txviewpoint variable and save into db
 // still storage full data of commitments, serial number, snderivator to check double spend
 // this function only work for transaction transfer token/prv within shard
@@ -24,6 +24,7 @@ type BeaconChain struct {
 	Ready       bool //when has peerstate
 
 	insertLock sync.Mutex
+	readyLock  sync.RWMutex
 }
 
 func NewBeaconChain(multiView *multiview.MultiView, blockGen *BlockGenerator, blockchain *BlockChain, chainName string) *BeaconChain {
@@ -94,7 +95,7 @@ func (s *BeaconChain) GetBestViewHash() string {
 }
 
 func (s *BeaconChain) GetFinalViewHash() string {
-	return s.GetBestView().(*BeaconBestState).BestBlockHash.String()
+	return s.GetFinalView().(*BeaconBestState).BestBlockHash.String()
 }
 
 func (chain *BeaconChain) GetLastBlockTimeStamp() int64 {
@@ -110,10 +111,14 @@ func (chain *BeaconChain) GetMaxBlkCreateTime() time.Duration {
 }
 
 func (chain *BeaconChain) IsReady() bool {
+	chain.readyLock.RLock()
+	defer chain.readyLock.RUnlock()
 	return chain.Ready
 }
 
 func (chain *BeaconChain) SetReady(ready bool) {
+	chain.readyLock.Lock()
+	defer chain.readyLock.Unlock()
 	chain.Ready = ready
 }
 
@@ -125,6 +130,12 @@ func (chain *BeaconChain) GetCommittee() []incognitokey.CommitteePublicKey {
 	return chain.multiView.GetBestView().(*BeaconBestState).GetBeaconCommittee()
 }
 
+// ChangesCommittee returns true if block changes the beacon committee, which
+// is swapped at the last block of an epoch
+func (chain *BeaconChain) ChangesCommittee(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) bool {
+	return block.GetHeight()%chain.Blockchain.config.ChainParams.Epoch == 0
+}
+
 func (chain *BeaconChain) GetCommitteeByHeight(h uint64) ([]incognitokey.CommitteePublicKey, error) {
 	bcStateRootHash := chain.GetBestView().(*BeaconBestState).ConsensusStateDBRootHash
 	bcDB := chain.Blockchain.GetBeaconChainDatabase()
@@ -243,6 +254,17 @@ func (chain *BeaconChain) ValidateBlockSignatures(block common.BlockInterface, c
 	return nil
 }
 
+// BatchValidateBlockSignatures validates the signatures of blocks signed by
+// committee, the committee signatures are verified at once
+func (chain *BeaconChain) BatchValidateBlockSignatures(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	for _, block := range blocks {
+		if err := chain.Blockchain.config.ConsensusEngine.ValidateProducerSig(block, chain.GetConsensusType()); err != nil {
+			return err
+		}
+	}
+	return chain.Blockchain.config.ConsensusEngine.BatchValidateBlockCommitteeSig(blocks, committee)
+}
+
 func (chain *BeaconChain) GetConsensusType() string {
 	return chain.multiView.GetBestView().(*BeaconBestState).ConsensusAlgorithm
 }
@@ -30,6 +30,9 @@ type BeaconHeader struct {
 	//for version 2
 	Proposer    string `json:"Proposer"`
 	ProposeTime int64  `json:"ProposeTime"`
+
+	// upgrade signalling of the producer, see versionbits.go
+	VersionBits uint32 `json:"VersionBits,omitempty"`
 }
 
 func (beaconHeader *BeaconHeader) toString() string {
@@ -53,6 +56,10 @@ func (beaconHeader *BeaconHeader) toString() string {
 		res += beaconHeader.Proposer
 		res += fmt.Sprintf("%v", beaconHeader.ProposeTime)
 	}
+	// not hashed when no deployment is signalled, so hashes of older blocks are unchanged
+	if beaconHeader.VersionBits != 0 {
+		res += fmt.Sprintf("%v", beaconHeader.VersionBits)
+	}
 	return res
 }
 
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/blockchain/btc"
 
 	"github.com/incognitochain/incognito-chain/common"
@@ -78,6 +79,11 @@ func (blockchain *BlockChain) NewBlockBeacon(curView *BeaconBestState, version i
 	beaconBlock.Header.Epoch = epoch
 	beaconBlock.Header.Round = round
 	beaconBlock.Header.PreviousBlockHash = beaconBestState.BestBlockHash
+	// signal readiness for the deployments this node knows
+	beaconBlock.Header.VersionBits, err = blockchain.calcNextVersionBits(curView)
+	if err != nil {
+		return nil, NewBlockChainError(UnExpectedError, err)
+	}
 	BLogger.log.Infof("Producing block: %d (epoch %d)", beaconBlock.Header.Height, beaconBlock.Header.Epoch)
 	//=====END Build Header Essential Data=====
 	//============Build body===================
@@ -643,7 +649,7 @@ func (beaconBestState *BeaconBestState) GenerateInstruction(
 		if err != nil {
 			Logger.log.Error(err)
 		}
-		if common.IndexOfUint64(newBeaconHeight/chainParamEpoch, blockchain.config.ChainParams.EpochBreakPointSwapNewKey) > -1 {
+		if blockchain.IsForkActive(forks.SwapNewKey, newBeaconHeight) {
 			epoch := newBeaconHeight / chainParamEpoch
 			swapBeaconInstructions, _, beaconCommittee := CreateBeaconSwapActionForKeyListV2(blockchain.config.GenesisParams, beaconPendingValidatorStr, beaconCommitteeStr, beaconBestState.MinBeaconCommitteeSize, epoch)
 			instructions = append(instructions, swapBeaconInstructions)
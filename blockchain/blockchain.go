@@ -9,6 +9,7 @@ import (
 	"sort"
 
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/multiview"
 
 	"github.com/incognitochain/incognito-chain/blockchain/btc"
@@ -36,7 +37,8 @@ type BlockChain struct {
 
 	IsTest bool
 
-	beaconViewCache *lru.Cache
+	beaconViewCache  *lru.Cache
+	deploymentCaches *thresholdCaches
 }
 
 // Config is a descriptor which specifies the blockchain instance configuration.
@@ -62,6 +64,7 @@ type Config struct {
 	Server            Server
 	ConsensusEngine   ConsensusEngine
 	Highway           Highway
+	ArchiveMode       bool
 }
 
 func NewBlockChain(config *Config, isTest bool) *BlockChain {
@@ -92,6 +95,10 @@ func (blockchain *BlockChain) Init(config *Config) error {
 	blockchain.config.IsBlockGenStarted = false
 	blockchain.IsTest = false
 	blockchain.beaconViewCache, _ = lru.New(100)
+	if err := checkDeployments(config.ChainParams.Deployments); err != nil {
+		return NewBlockChainError(UnExpectedError, err)
+	}
+	blockchain.deploymentCaches = newThresholdCaches(len(config.ChainParams.Deployments))
 	// Initialize the chain state from the passed database.  When the db
 	// does not yet contain any chain state, both it and the chain state
 	// will be initialized to contain only the genesis block.
@@ -108,7 +115,9 @@ func (blockchain *BlockChain) Init(config *Config) error {
 func (blockchain *BlockChain) InitChainState() error {
 	// Determine the state of the chain database. We may need to initialize
 	// everything from scratch or upgrade certain buckets.
-	blockchain.BeaconChain = NewBeaconChain(multiview.NewMultiView(), blockchain.config.BlockGen, blockchain, common.BeaconChainKey)
+	beaconMultiView := multiview.NewMultiView()
+	beaconMultiView.SetPubSubManager(-1, blockchain.config.PubSubManager)
+	blockchain.BeaconChain = NewBeaconChain(beaconMultiView, blockchain.config.BlockGen, blockchain, common.BeaconChainKey)
 	var err error
 	blockchain.BeaconChain.hashHistory, err = lru.New(1000)
 	if err != nil {
@@ -130,7 +139,9 @@ func (blockchain *BlockChain) InitChainState() error {
 	blockchain.ShardChain = make([]*ShardChain, blockchain.GetBeaconBestState().ActiveShards)
 	for shard := 1; shard <= blockchain.GetBeaconBestState().ActiveShards; shard++ {
 		shardID := byte(shard - 1)
-		blockchain.ShardChain[shardID] = NewShardChain(shard-1, multiview.NewMultiView(), blockchain.config.BlockGen, blockchain, common.GetShardChainKey(shardID))
+		shardMultiView := multiview.NewMultiView()
+		shardMultiView.SetPubSubManager(int(shardID), blockchain.config.PubSubManager)
+		blockchain.ShardChain[shardID] = NewShardChain(shard-1, shardMultiView, blockchain.config.BlockGen, blockchain, common.GetShardChainKey(shardID))
 		blockchain.ShardChain[shardID].hashHistory, err = lru.New(1000)
 		if err != nil {
 			return err
@@ -692,13 +703,13 @@ func (blockchain *BlockChain) GetBeaconViewStateDataFromBlockHash(blockHash comm
 }
 
 // GetFixedRandomForShardIDCommitment returns the fixed randomness for shardID commitments
-// if bc height is greater than or equal to BCHeightBreakPointFixRandShardCM
+// if fork FixRandShardCommitment is active at beacon height
 // otherwise, return nil
 func (blockchain *BlockChain) GetFixedRandomForShardIDCommitment(beaconHeight uint64) *privacy.Scalar {
 	if beaconHeight == 0 {
 		beaconHeight = blockchain.GetBeaconBestState().GetHeight()
 	}
-	if beaconHeight >= blockchain.GetConfig().ChainParams.BCHeightBreakPointFixRandShardCM {
+	if blockchain.IsForkActive(forks.FixRandShardCommitment, beaconHeight) {
 		return privacy.FixedRandomnessShardID
 	}
 
@@ -0,0 +1,159 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// GenesisValidator is a validator of the genesis committees
+type GenesisValidator struct {
+	PaymentAddress     string
+	CommitteePublicKey string
+}
+
+// ChainParamsFile describes a private network, it is written by `chainctl --cmd devnetinit`
+// and loaded by the node with --chainparams. Params which are not in the file are
+// the ones of testnet.
+type ChainParamsFile struct {
+	Name             string
+	Net              uint32
+	DefaultPort      string
+	GenesisBlockTime string // e.g. 2020-01-01T00:00:00.000Z
+
+	ActiveShards           int
+	Epoch                  uint64
+	RandomTime             uint64
+	MinBeaconCommitteeSize int
+	MaxBeaconCommitteeSize int
+	MinShardCommitteeSize  int
+	MaxShardCommitteeSize  int
+	BeaconBlockInterval    string // minimum beacon block interval, e.g. 10s
+	ShardBlockInterval     string // minimum shard block interval, e.g. 10s
+	StakingAmountShard     uint64
+	BasicReward            uint64
+
+	// Beacon and Shard are the genesis committees, every shard committee holds
+	// exactly MinShardCommitteeSize validators
+	Beacon []GenesisValidator
+	Shard  map[int][]GenesisValidator
+
+	// InitialIncognito holds the serialized txs of the genesis shard block which
+	// allocate the initial PRV and privacy tokens
+	InitialIncognito []string
+
+	Forks forks.Schedule
+}
+
+// LoadChainParams reads the chain params file at path and builds the params of the network
+func LoadChainParams(path string) (*Params, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &ChainParamsFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, err
+	}
+	return file.ToParams()
+}
+
+// ToParams checks the chain params file and builds the params of the network
+func (file *ChainParamsFile) ToParams() (*Params, error) {
+	if file.Name == "" || file.Name == TestnetName || file.Name == Testnet2Name || file.Name == MainetName {
+		return nil, fmt.Errorf("network name %v is empty or reserved", file.Name)
+	}
+	if file.Net == Mainnet || file.Net == Testnet || file.Net == Testnet2 {
+		return nil, fmt.Errorf("net %#x is reserved", file.Net)
+	}
+	if file.ActiveShards <= 0 || file.ActiveShards > common.MaxShardNumber {
+		return nil, fmt.Errorf("active shards must be in range [1, %d], got %d", common.MaxShardNumber, file.ActiveShards)
+	}
+	if file.Epoch <= file.RandomTime || file.RandomTime == 0 {
+		return nil, fmt.Errorf("random time %d must be in range [1, epoch %d)", file.RandomTime, file.Epoch)
+	}
+	if file.MinBeaconCommitteeSize <= 0 || file.MaxBeaconCommitteeSize < file.MinBeaconCommitteeSize {
+		return nil, fmt.Errorf("invalid beacon committee size [%d, %d]", file.MinBeaconCommitteeSize, file.MaxBeaconCommitteeSize)
+	}
+	if file.MinShardCommitteeSize <= 0 || file.MaxShardCommitteeSize < file.MinShardCommitteeSize {
+		return nil, fmt.Errorf("invalid shard committee size [%d, %d]", file.MinShardCommitteeSize, file.MaxShardCommitteeSize)
+	}
+	if _, err := time.Parse("2006-01-02T15:04:05.000Z", file.GenesisBlockTime); err != nil {
+		return nil, fmt.Errorf("invalid genesis block time %v, %v", file.GenesisBlockTime, err)
+	}
+	beaconBlockInterval, err := time.ParseDuration(file.BeaconBlockInterval)
+	if err != nil || beaconBlockInterval <= 0 {
+		return nil, fmt.Errorf("invalid beacon block interval %v", file.BeaconBlockInterval)
+	}
+	shardBlockInterval, err := time.ParseDuration(file.ShardBlockInterval)
+	if err != nil || shardBlockInterval <= 0 {
+		return nil, fmt.Errorf("invalid shard block interval %v", file.ShardBlockInterval)
+	}
+	if err := file.Forks.Validate(); err != nil {
+		return nil, err
+	}
+
+	genesisParams := &GenesisParams{
+		PreSelectBeaconNodeSerializedPubkey:         []string{},
+		PreSelectBeaconNodeSerializedPaymentAddress: []string{},
+		PreSelectShardNodeSerializedPubkey:          []string{},
+		PreSelectShardNodeSerializedPaymentAddress:  []string{},
+		SelectBeaconNodeSerializedPubkeyV2:          make(map[uint64][]string),
+		SelectBeaconNodeSerializedPaymentAddressV2:  make(map[uint64][]string),
+		SelectShardNodeSerializedPubkeyV2:           make(map[uint64][]string),
+		SelectShardNodeSerializedPaymentAddressV2:   make(map[uint64][]string),
+		InitialIncognito:                            file.InitialIncognito,
+		ConsensusAlgorithm:                          common.BlsConsensus,
+	}
+	if len(file.Beacon) < file.MinBeaconCommitteeSize || len(file.Beacon) > file.MaxBeaconCommitteeSize {
+		return nil, fmt.Errorf("beacon committee has %d validators, expected [%d, %d]", len(file.Beacon), file.MinBeaconCommitteeSize, file.MaxBeaconCommitteeSize)
+	}
+	for _, validator := range file.Beacon {
+		genesisParams.PreSelectBeaconNodeSerializedPubkey = append(genesisParams.PreSelectBeaconNodeSerializedPubkey, validator.CommitteePublicKey)
+		genesisParams.PreSelectBeaconNodeSerializedPaymentAddress = append(genesisParams.PreSelectBeaconNodeSerializedPaymentAddress, validator.PaymentAddress)
+	}
+	// genesis shard committees are assigned by slices of MinShardCommitteeSize, see InitShardState
+	for shardID := 0; shardID < file.ActiveShards; shardID++ {
+		if len(file.Shard[shardID]) != file.MinShardCommitteeSize {
+			return nil, fmt.Errorf("shard %d committee has %d validators, expected %d", shardID, len(file.Shard[shardID]), file.MinShardCommitteeSize)
+		}
+		for _, validator := range file.Shard[shardID] {
+			genesisParams.PreSelectShardNodeSerializedPubkey = append(genesisParams.PreSelectShardNodeSerializedPubkey, validator.CommitteePublicKey)
+			genesisParams.PreSelectShardNodeSerializedPaymentAddress = append(genesisParams.PreSelectShardNodeSerializedPaymentAddress, validator.PaymentAddress)
+		}
+	}
+
+	params := ChainTestParam
+	params.Name = file.Name
+	params.Net = file.Net
+	if file.DefaultPort != "" {
+		params.DefaultPort = file.DefaultPort
+	}
+	params.GenesisParams = genesisParams
+	params.ActiveShards = file.ActiveShards
+	params.Epoch = file.Epoch
+	params.RandomTime = file.RandomTime
+	params.MinBeaconCommitteeSize = file.MinBeaconCommitteeSize
+	params.MaxBeaconCommitteeSize = file.MaxBeaconCommitteeSize
+	params.MinShardCommitteeSize = file.MinShardCommitteeSize
+	params.MaxShardCommitteeSize = file.MaxShardCommitteeSize
+	// keep the ratio of block creation time to block interval of testnet
+	params.MinBeaconBlockInterval = beaconBlockInterval
+	params.MaxBeaconBlockCreation = time.Duration(float64(beaconBlockInterval) * float64(TestNetMaxBeaconBlkCreation) / float64(TestNetMinBeaconBlkInterval))
+	params.MinShardBlockInterval = shardBlockInterval
+	params.MaxShardBlockCreation = time.Duration(float64(shardBlockInterval) * float64(TestNetMaxShardBlkCreation) / float64(TestNetMinShardBlkInterval))
+	if file.StakingAmountShard != 0 {
+		params.StakingAmountShard = file.StakingAmountShard
+	}
+	if file.BasicReward != 0 {
+		params.BasicReward = file.BasicReward
+	}
+	params.Forks = file.Forks
+	params.GenesisBeaconBlock = CreateBeaconGenesisBlock(1, uint16(file.Net), file.GenesisBlockTime, genesisParams)
+	params.GenesisShardBlock = CreateShardGenesisBlock(1, uint16(file.Net), file.GenesisBlockTime, genesisParams)
+	return &params, nil
+}
@@ -33,6 +33,7 @@ const (
 	ValidateTimeForSpamRequestTxs = 1581565837 // GMT: Thursday, February 13, 2020 3:50:37 AM. From this time, block will be checked spam request-reward tx
 	TransactionBatchSize          = 30
 	SpareTime                     = 1000 // in mili-second
+	NonArchiveStateHistory        = 1000 // number of blocks below final view whose state is served by non archive node
 )
 
 // burning addresses
@@ -54,6 +55,9 @@ const (
 	MainnetSwapOffset       = 4
 	MainnetAssignOffset     = 8
 
+	MainnetRuleChangeActivationThreshold = 1916
+	MainnetVersionBitsWindow             = 2016
+
 	MainNetShardCommitteeSize     = 32
 	MainNetMinShardCommitteeSize  = 22
 	MainNetBeaconCommitteeSize    = 32
@@ -114,6 +118,9 @@ const (
 	TestnetSwapOffset       = 1
 	TestnetAssignOffset     = 2
 
+	TestnetRuleChangeActivationThreshold = 1512
+	TestnetVersionBitsWindow             = 2016
+
 	TestNetShardCommitteeSize     = 32
 	TestNetMinShardCommitteeSize  = 4
 	TestNetBeaconCommitteeSize    = 4
@@ -156,6 +163,9 @@ const (
 	Testnet2SwapOffset       = 1
 	Testnet2AssignOffset     = 2
 
+	Testnet2RuleChangeActivationThreshold = 1512
+	Testnet2VersionBitsWindow             = 2016
+
 	TestNet2ShardCommitteeSize     = 32
 	TestNet2MinShardCommitteeSize  = 4
 	TestNet2BeaconCommitteeSize    = 4
@@ -250,13 +260,15 @@ func init() {
 		panic(err)
 	}
 	if IsTestNet {
-		for i := 0; i < TestNetMinBeaconCommitteeSize; i++ {
+		// the keylist of a devnet may hold fewer keys, its genesis committees are
+		// loaded with --chainparams
+		for i := 0; i < TestNetMinBeaconCommitteeSize && i < len(keylist.Beacon); i++ {
 			PreSelectBeaconNodeTestnetSerializedPubkey = append(PreSelectBeaconNodeTestnetSerializedPubkey, keylist.Beacon[i].CommitteePublicKey)
 			PreSelectBeaconNodeTestnetSerializedPaymentAddress = append(PreSelectBeaconNodeTestnetSerializedPaymentAddress, keylist.Beacon[i].PaymentAddress)
 		}
 
 		for i := 0; i < TestNetActiveShards; i++ {
-			for j := 0; j < TestNetMinShardCommitteeSize; j++ {
+			for j := 0; j < TestNetMinShardCommitteeSize && j < len(keylist.Shard[i]); j++ {
 				PreSelectShardNodeTestnetSerializedPubkey = append(PreSelectShardNodeTestnetSerializedPubkey, keylist.Shard[i][j].CommitteePublicKey)
 				PreSelectShardNodeTestnetSerializedPaymentAddress = append(PreSelectShardNodeTestnetSerializedPaymentAddress, keylist.Shard[i][j].PaymentAddress)
 			}
@@ -21,3 +21,8 @@ var (
 	beaconStoreBlockTimer                   = metrics.NewRegisteredTimer("beacon/storeblock", nil)
 	beaconUpdateBestStateTimer              = metrics.NewRegisteredTimer("beacon/updatebeststate", nil)
 )
+
+// insertBlockTimer times the insertion of blocks into a chain, by chain key
+func insertBlockTimer(chainKey string) metrics.Timer {
+	return metrics.GetOrRegisterTimer(metrics.LabeledName("chain/insert", "chain", chainKey), nil)
+}
@@ -182,6 +182,7 @@ const (
 	GetShardBlockHeightByHashError
 	GetShardBlockByHashError
 	ResponsedTransactionFromBeaconInstructionsError
+	GetHistoricalStateError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -349,6 +350,7 @@ var ErrCodeMessage = map[int]struct {
 	GetListOutputCoinsByKeysetError:                   {-2000, "Get List Output Coins By Keyset Error"},
 	GetTotalLockedCollateralError:                     {-3000, "Get Total Locked Collateral Error"},
 	ResponsedTransactionFromBeaconInstructionsError:   {-3100, "Build Transaction Response From Beacon Instructions Error"},
+	GetHistoricalStateError:                           {-3200, "Get Historical State Error"},
 }
 
 type BlockChainError struct {
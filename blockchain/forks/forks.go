@@ -0,0 +1,180 @@
+package forks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// Fork names a consensus rule change scheduled at a beacon height or epoch
+type Fork string
+
+const (
+	// BurnAddressV2 switches the burning address to the second one
+	BurnAddressV2 Fork = "BurnAddressV2"
+	// FixRandShardCommitment uses a fixed randomness for shardID commitments
+	FixRandShardCommitment Fork = "FixRandShardCommitment"
+	// ReplaceStakingTx validates the return staking txs of shard blocks
+	ReplaceStakingTx Fork = "ReplaceStakingTx"
+	// StakingTxRoot verifies the StakingTxRoot of shard blocks, it was checked
+	// from the beacon height after ReplaceStakingTx
+	StakingTxRoot Fork = "StakingTxRoot"
+	// SwapNewKey replaces committees with new keys at the listed epochs
+	SwapNewKey Fork = "SwapNewKey"
+	// ConsensusV2 switches the consensus engine to blsbft v2
+	ConsensusV2 Fork = "ConsensusV2"
+	// MinTxFeesOnTokenRequirement requires a minimum PRV pool size to pay fees in tokens
+	MinTxFeesOnTokenRequirement Fork = "MinTxFeesOnTokenRequirement"
+	// TimeLock accepts the txs valid within a window of beacon heights
+	TimeLock Fork = "TimeLock"
+)
+
+// All lists the known forks in activation order
+var All = []Fork{
+	BurnAddressV2,
+	MinTxFeesOnTokenRequirement,
+	ReplaceStakingTx,
+	StakingTxRoot,
+	FixRandShardCommitment,
+	SwapNewKey,
+	ConsensusV2,
+	TimeLock,
+}
+
+// Activation tells when a fork applies, at most one field is set and a fork
+// with no field set is never active
+type Activation struct {
+	// Height is the first beacon height where the fork is active
+	Height uint64 `json:"Height,omitempty"`
+	// Epoch is the first epoch where the fork is active
+	Epoch uint64 `json:"Epoch,omitempty"`
+	// Epochs lists the epochs where a one-time fork applies, an epoch is counted
+	// as beacon height / epoch length for these forks
+	Epochs []uint64 `json:"Epochs,omitempty"`
+}
+
+// Schedule is the activation of each fork on a network
+type Schedule map[Fork]Activation
+
+// IsActive returns whether fork applies to the block at beaconHeight
+func (schedule Schedule) IsActive(fork Fork, beaconHeight uint64, epochLength uint64) bool {
+	activation, ok := schedule[fork]
+	if !ok {
+		return false
+	}
+	switch {
+	case len(activation.Epochs) > 0:
+		return epochLength > 0 && common.IndexOfUint64(beaconHeight/epochLength, activation.Epochs) > -1
+	case activation.Epoch > 0:
+		return beaconHeight > 0 && epochLength > 0 && (beaconHeight-1)/epochLength+1 >= activation.Epoch
+	case activation.Height > 0:
+		return beaconHeight >= activation.Height
+	}
+	return false
+}
+
+// IsActiveAtEpoch returns whether fork applies during epoch, a height activated
+// fork applies to an epoch if it is active at the first block of the epoch
+func (schedule Schedule) IsActiveAtEpoch(fork Fork, epoch uint64, epochLength uint64) bool {
+	activation, ok := schedule[fork]
+	if !ok {
+		return false
+	}
+	switch {
+	case len(activation.Epochs) > 0:
+		return common.IndexOfUint64(epoch, activation.Epochs) > -1
+	case activation.Epoch > 0:
+		return epoch >= activation.Epoch
+	case activation.Height > 0:
+		if epoch == 0 {
+			return false
+		}
+		return (epoch-1)*epochLength+1 >= activation.Height
+	}
+	return false
+}
+
+// ActivationHeight returns the first beacon height where fork is active, one-time
+// and never active forks have no activation height
+func (schedule Schedule) ActivationHeight(fork Fork, epochLength uint64) (uint64, bool) {
+	activation, ok := schedule[fork]
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case len(activation.Epochs) > 0:
+		return 0, false
+	case activation.Epoch > 0:
+		return (activation.Epoch-1)*epochLength + 1, true
+	case activation.Height > 0:
+		return activation.Height, true
+	}
+	return 0, false
+}
+
+// Validate checks every fork of the schedule is known and has a single kind of activation
+func (schedule Schedule) Validate() error {
+	for fork, activation := range schedule {
+		if !isKnown(fork) {
+			return fmt.Errorf("unknown fork %v", fork)
+		}
+		set := 0
+		if activation.Height > 0 {
+			set++
+		}
+		if activation.Epoch > 0 {
+			set++
+		}
+		if len(activation.Epochs) > 0 {
+			set++
+		}
+		if set > 1 {
+			return fmt.Errorf("fork %v must be activated by only one of Height, Epoch or Epochs", fork)
+		}
+	}
+	return nil
+}
+
+// Override returns a copy of the schedule where the activation of forks in
+// overrides replaces the original one
+func (schedule Schedule) Override(overrides Schedule) (Schedule, error) {
+	if err := overrides.Validate(); err != nil {
+		return nil, err
+	}
+	result := Schedule{}
+	for fork, activation := range schedule {
+		result[fork] = activation
+	}
+	for fork, activation := range overrides {
+		result[fork] = activation
+	}
+	return result, nil
+}
+
+// LoadSchedule reads a schedule from a json file mapping fork names to their activation,
+// e.g. {"ConsensusV2": {"Epoch": 2}, "BurnAddressV2": {"Height": 1}}
+func LoadSchedule(path string) (Schedule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schedule := Schedule{}
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, err
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func isKnown(fork Fork) bool {
+	for _, known := range All {
+		if known == fork {
+			return true
+		}
+	}
+	return false
+}
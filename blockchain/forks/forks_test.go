@@ -0,0 +1,85 @@
+package forks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSchedule = Schedule{
+	BurnAddressV2:          {Height: 101},
+	ConsensusV2:            {Epoch: 3},
+	SwapNewKey:             {Epochs: []uint64{2, 5}},
+	FixRandShardCommitment: {},
+}
+
+func TestSchedule_IsActive(t *testing.T) {
+	epochLength := uint64(10)
+	assert.False(t, testSchedule.IsActive(BurnAddressV2, 100, epochLength))
+	assert.True(t, testSchedule.IsActive(BurnAddressV2, 101, epochLength))
+
+	// epoch 3 starts at beacon height 21
+	assert.False(t, testSchedule.IsActive(ConsensusV2, 20, epochLength))
+	assert.True(t, testSchedule.IsActive(ConsensusV2, 21, epochLength))
+	assert.False(t, testSchedule.IsActive(ConsensusV2, 0, epochLength))
+
+	assert.False(t, testSchedule.IsActive(SwapNewKey, 19, epochLength))
+	assert.True(t, testSchedule.IsActive(SwapNewKey, 20, epochLength))
+	assert.True(t, testSchedule.IsActive(SwapNewKey, 29, epochLength))
+	assert.False(t, testSchedule.IsActive(SwapNewKey, 30, epochLength))
+
+	assert.False(t, testSchedule.IsActive(FixRandShardCommitment, 1000, epochLength))
+	assert.False(t, testSchedule.IsActive(ReplaceStakingTx, 1000, epochLength))
+}
+
+func TestSchedule_IsActiveAtEpoch(t *testing.T) {
+	epochLength := uint64(10)
+	assert.False(t, testSchedule.IsActiveAtEpoch(ConsensusV2, 2, epochLength))
+	assert.True(t, testSchedule.IsActiveAtEpoch(ConsensusV2, 3, epochLength))
+	// epoch 11 starts at beacon height 101
+	assert.False(t, testSchedule.IsActiveAtEpoch(BurnAddressV2, 10, epochLength))
+	assert.True(t, testSchedule.IsActiveAtEpoch(BurnAddressV2, 11, epochLength))
+	assert.True(t, testSchedule.IsActiveAtEpoch(SwapNewKey, 5, epochLength))
+	assert.False(t, testSchedule.IsActiveAtEpoch(SwapNewKey, 6, epochLength))
+}
+
+func TestSchedule_ActivationHeight(t *testing.T) {
+	height, ok := testSchedule.ActivationHeight(ConsensusV2, 10)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(21), height)
+	_, ok = testSchedule.ActivationHeight(SwapNewKey, 10)
+	assert.False(t, ok)
+	_, ok = testSchedule.ActivationHeight(ReplaceStakingTx, 10)
+	assert.False(t, ok)
+}
+
+func TestSchedule_Override(t *testing.T) {
+	schedule, err := testSchedule.Override(Schedule{BurnAddressV2: {Height: 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), schedule[BurnAddressV2].Height)
+	assert.Equal(t, uint64(101), testSchedule[BurnAddressV2].Height)
+	assert.Equal(t, uint64(3), schedule[ConsensusV2].Epoch)
+
+	_, err = testSchedule.Override(Schedule{"Unknown": {Height: 1}})
+	assert.NotNil(t, err)
+	_, err = testSchedule.Override(Schedule{ConsensusV2: {Height: 1, Epoch: 1}})
+	assert.NotNil(t, err)
+}
+
+func TestLoadSchedule(t *testing.T) {
+	dir, err := ioutil.TempDir("", "forks")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "forks.json")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"ConsensusV2": {"Epoch": 2}, "SwapNewKey": {"Epochs": [4]}}`), 0644))
+	schedule, err := LoadSchedule(path)
+	assert.Nil(t, err)
+	assert.Equal(t, Schedule{ConsensusV2: {Epoch: 2}, SwapNewKey: {Epochs: []uint64{4}}}, schedule)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"ConsensusV3": {"Epoch": 2}}`), 0644))
+	_, err = LoadSchedule(path)
+	assert.NotNil(t, err)
+}
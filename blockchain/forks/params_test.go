@@ -0,0 +1,31 @@
+package forks_test
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStakingTxBoundaries pins the heights of the two staking tx checks: the
+// return staking txs are validated from ReplaceStakingTx (accessor_transaction.go)
+// and the StakingTxRoot of shard blocks is verified one beacon height later
+// (shardprocess.go), as before the fork schedule
+func TestStakingTxBoundaries(t *testing.T) {
+	for _, test := range []struct {
+		params *blockchain.Params
+		height uint64
+	}{
+		{&blockchain.ChainMainParam, 559380},
+		{&blockchain.ChainTestParam, 1},
+		{&blockchain.ChainTest2Param, 1},
+	} {
+		params := test.params
+		assert.False(t, params.IsForkActive(forks.ReplaceStakingTx, test.height-1), params.Name)
+		assert.True(t, params.IsForkActive(forks.ReplaceStakingTx, test.height), params.Name)
+
+		assert.False(t, params.IsForkActive(forks.StakingTxRoot, test.height), params.Name)
+		assert.True(t, params.IsForkActive(forks.StakingTxRoot, test.height+1), params.Name)
+	}
+}
@@ -74,6 +74,24 @@ func generateHashFromMapByteString(maps1 map[byte][]string, maps2 map[byte][]str
 	return generateHashFromStringArray(append(shardPendingValidator, shardValidator...))
 }
 
+// CommitteeRoot returns the root committed in block headers for lists of
+// committee keys in base58, as the BeaconCommitteeAndValidatorRoot of the
+// beacon committee and pending validators, or the CommitteeRoot of a shard
+// committee
+func CommitteeRoot(keys ...[]string) (common.Hash, error) {
+	strs := []string{}
+	for _, k := range keys {
+		strs = append(strs, k...)
+	}
+	return generateHashFromStringArray(strs)
+}
+
+// ShardCommitteeRoot returns the ShardCommitteeAndValidatorRoot of beacon
+// headers for the pending validators and committees of the shards
+func ShardCommitteeRoot(shardPendingValidator map[byte][]string, shardCommittee map[byte][]string) (common.Hash, error) {
+	return generateHashFromMapByteString(shardPendingValidator, shardCommittee)
+}
+
 func generateHashFromMapStringString(maps1 map[string]string) (common.Hash, error) {
 	var keys []string
 	var res []string
@@ -42,6 +42,7 @@ type ConsensusEngine interface {
 	ValidateProducerPosition(blk common.BlockInterface, lastProposerIdx int, committee []incognitokey.CommitteePublicKey, minCommitteeSize int) error
 	ValidateProducerSig(block common.BlockInterface, consensusType string) error
 	ValidateBlockCommitteSig(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) error
+	BatchValidateBlockCommitteeSig(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error
 	GetCurrentMiningPublicKey() (string, string)
 	GetMiningPublicKeyByConsensus(consensusName string) (string, error)
 	GetUserLayer() (string, int)
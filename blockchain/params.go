@@ -3,6 +3,7 @@ package blockchain
 import (
 	"time"
 
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/common"
 )
 
@@ -25,6 +26,24 @@ type PortalParams struct {
 	MinPercentRedeemFee                  float64
 }
 
+// ConsensusDeployment defines a consensus rule change voted by beacon producers
+// with the version bits of the beacon header, see versionbits.go
+type ConsensusDeployment struct {
+	Name string
+
+	// BitNumber defines the specific bit number within the header version
+	// bits this particular soft-fork deployment refers to.
+	BitNumber uint8
+
+	// StartHeight is the beacon height from which voting on the rule change
+	// starts (at the next window).
+	StartHeight uint64
+
+	// ExpireHeight is the beacon height after which the attempted rule change
+	// fails if it has not already been locked in.
+	ExpireHeight uint64
+}
+
 /*
 Params defines a network by its component. These component may be used by Applications
 to differentiate network as well as addresses and keys for one network
@@ -56,11 +75,10 @@ type Params struct {
 	SwapOffset                       int    // is used for case that good producers length is equal to max committee size
 	IncognitoDAOAddress              string
 	CentralizedWebsitePaymentAddress string //centralized website's pubkey
-	CheckForce                       bool   // true on testnet and false on mainnet
-	ChainVersion                     string
+	RuleChangeActivationThreshold    uint64 // number of beacon blocks of a window signalling a deployment to lock it in
+	VersionBitsWindow                uint64 // number of beacon blocks in each threshold state window
+	Deployments                      []ConsensusDeployment
 	AssignOffset                     int
-	ConsensusV2Epoch                 uint64
-	BeaconHeightBreakPointBurnAddr   uint64
 	BNBRelayingHeaderChainID         string
 	BTCRelayingHeaderChainID         string
 	BTCDataFolderName                string
@@ -69,11 +87,19 @@ type Params struct {
 	BNBFullNodePort                  string
 	PortalParams                     map[uint64]PortalParams
 	PortalFeederAddress              string
-	EpochBreakPointSwapNewKey        []uint64
 	IsBackup                         bool
 	PreloadAddress                   string
-	ReplaceStakingTxHeight           uint64
-	BCHeightBreakPointFixRandShardCM uint64
+	Forks                            forks.Schedule // activation of consensus rule changes
+}
+
+// IsForkActive returns whether fork applies to the beacon block at beaconHeight
+func (params *Params) IsForkActive(fork forks.Fork, beaconHeight uint64) bool {
+	return params.Forks.IsActive(fork, beaconHeight, params.Epoch)
+}
+
+// IsForkActiveAtEpoch returns whether fork applies during epoch
+func (params *Params) IsForkActiveAtEpoch(fork forks.Fork, epoch uint64) bool {
+	return params.Forks.IsActiveAtEpoch(fork, epoch, params.Epoch)
 }
 
 type GenesisParams struct {
@@ -149,17 +175,16 @@ func init() {
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
 		},
-		CheckForce:                     false,
-		ChainVersion:                   "version-chain-test.json",
-		ConsensusV2Epoch:               16930,
-		BeaconHeightBreakPointBurnAddr: 250000,
-		BNBRelayingHeaderChainID:       TestnetBNBChainID,
-		BTCRelayingHeaderChainID:       TestnetBTCChainID,
-		BTCDataFolderName:              TestnetBTCDataFolderName,
-		BNBFullNodeProtocol:            TestnetBNBFullNodeProtocol,
-		BNBFullNodeHost:                TestnetBNBFullNodeHost,
-		BNBFullNodePort:                TestnetBNBFullNodePort,
-		PortalFeederAddress:            TestnetPortalFeeder,
+		RuleChangeActivationThreshold: TestnetRuleChangeActivationThreshold,
+		VersionBitsWindow:             TestnetVersionBitsWindow,
+		Deployments:                   []ConsensusDeployment{},
+		BNBRelayingHeaderChainID:      TestnetBNBChainID,
+		BTCRelayingHeaderChainID:      TestnetBTCChainID,
+		BTCDataFolderName:             TestnetBTCDataFolderName,
+		BNBFullNodeProtocol:           TestnetBNBFullNodeProtocol,
+		BNBFullNodeHost:               TestnetBNBFullNodeHost,
+		BNBFullNodePort:               TestnetBNBFullNodePort,
+		PortalFeederAddress:           TestnetPortalFeeder,
 		PortalParams: map[uint64]PortalParams{
 			0: {
 				TimeOutCustodianReturnPubToken:       1 * time.Hour,
@@ -176,11 +201,18 @@ func init() {
 				MinPercentRedeemFee:                  0.01,
 			},
 		},
-		EpochBreakPointSwapNewKey:        TestnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:           1,
-		IsBackup:                         false,
-		PreloadAddress:                   "",
-		BCHeightBreakPointFixRandShardCM: 2070000,
+		IsBackup:       false,
+		PreloadAddress: "",
+		Forks: forks.Schedule{
+			forks.BurnAddressV2:               {Height: 250001},
+			forks.MinTxFeesOnTokenRequirement: {Height: 87301},
+			forks.ReplaceStakingTx:            {Height: 1},
+			forks.StakingTxRoot:               {Height: 2},
+			forks.FixRandShardCommitment:      {Height: 2070000},
+			forks.SwapNewKey:                  {Epochs: TestnetReplaceCommitteeEpoch},
+			forks.ConsensusV2:                 {Epoch: 16930},
+			forks.TimeLock:                    {Epoch: 1e9},
+		},
 	}
 	// END TESTNET
 
@@ -231,17 +263,16 @@ func init() {
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
 		},
-		CheckForce:                     false,
-		ChainVersion:                   "version-chain-test-2.json",
-		ConsensusV2Epoch:               1e9,
-		BeaconHeightBreakPointBurnAddr: 1,
-		BNBRelayingHeaderChainID:       Testnet2BNBChainID,
-		BTCRelayingHeaderChainID:       Testnet2BTCChainID,
-		BTCDataFolderName:              Testnet2BTCDataFolderName,
-		BNBFullNodeProtocol:            Testnet2BNBFullNodeProtocol,
-		BNBFullNodeHost:                Testnet2BNBFullNodeHost,
-		BNBFullNodePort:                Testnet2BNBFullNodePort,
-		PortalFeederAddress:            Testnet2PortalFeeder,
+		RuleChangeActivationThreshold: Testnet2RuleChangeActivationThreshold,
+		VersionBitsWindow:             Testnet2VersionBitsWindow,
+		Deployments:                   []ConsensusDeployment{},
+		BNBRelayingHeaderChainID:      Testnet2BNBChainID,
+		BTCRelayingHeaderChainID:      Testnet2BTCChainID,
+		BTCDataFolderName:             Testnet2BTCDataFolderName,
+		BNBFullNodeProtocol:           Testnet2BNBFullNodeProtocol,
+		BNBFullNodeHost:               Testnet2BNBFullNodeHost,
+		BNBFullNodePort:               Testnet2BNBFullNodePort,
+		PortalFeederAddress:           Testnet2PortalFeeder,
 		PortalParams: map[uint64]PortalParams{
 			0: {
 				TimeOutCustodianReturnPubToken:       1 * time.Hour,
@@ -258,11 +289,18 @@ func init() {
 				MinPercentRedeemFee:                  0.01,
 			},
 		},
-		EpochBreakPointSwapNewKey:        TestnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:           1,
-		IsBackup:                         false,
-		PreloadAddress:                   "",
-		BCHeightBreakPointFixRandShardCM: 120000,
+		IsBackup:       false,
+		PreloadAddress: "",
+		Forks: forks.Schedule{
+			forks.BurnAddressV2:               {Height: 2},
+			forks.MinTxFeesOnTokenRequirement: {Height: 87301},
+			forks.ReplaceStakingTx:            {Height: 1},
+			forks.StakingTxRoot:               {Height: 2},
+			forks.FixRandShardCommitment:      {Height: 120000},
+			forks.SwapNewKey:                  {Epochs: TestnetReplaceCommitteeEpoch},
+			forks.ConsensusV2:                 {Epoch: 1e9},
+			forks.TimeLock:                    {Epoch: 1e9},
+		},
 	}
 	// END TESTNET-2
 
@@ -311,17 +349,16 @@ func init() {
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
 		},
-		CheckForce:                     false,
-		ChainVersion:                   "version-chain-main.json",
-		ConsensusV2Epoch:               1e9,
-		BeaconHeightBreakPointBurnAddr: 150500,
-		BNBRelayingHeaderChainID:       MainnetBNBChainID,
-		BTCRelayingHeaderChainID:       MainnetBTCChainID,
-		BTCDataFolderName:              MainnetBTCDataFolderName,
-		BNBFullNodeProtocol:            MainnetBNBFullNodeProtocol,
-		BNBFullNodeHost:                MainnetBNBFullNodeHost,
-		BNBFullNodePort:                MainnetBNBFullNodePort,
-		PortalFeederAddress:            MainnetPortalFeeder,
+		RuleChangeActivationThreshold: MainnetRuleChangeActivationThreshold,
+		VersionBitsWindow:             MainnetVersionBitsWindow,
+		Deployments:                   []ConsensusDeployment{},
+		BNBRelayingHeaderChainID:      MainnetBNBChainID,
+		BTCRelayingHeaderChainID:      MainnetBTCChainID,
+		BTCDataFolderName:             MainnetBTCDataFolderName,
+		BNBFullNodeProtocol:           MainnetBNBFullNodeProtocol,
+		BNBFullNodeHost:               MainnetBNBFullNodeHost,
+		BNBFullNodePort:               MainnetBNBFullNodePort,
+		PortalFeederAddress:           MainnetPortalFeeder,
 		PortalParams: map[uint64]PortalParams{
 			0: {
 				TimeOutCustodianReturnPubToken:       24 * time.Hour,
@@ -339,11 +376,18 @@ func init() {
 			},
 		},
 
-		EpochBreakPointSwapNewKey:        MainnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:           559380,
-		IsBackup:                         false,
-		PreloadAddress:                   "",
-		BCHeightBreakPointFixRandShardCM: 644000,
+		IsBackup:       false,
+		PreloadAddress: "",
+		Forks: forks.Schedule{
+			forks.BurnAddressV2:               {Height: 150501},
+			forks.MinTxFeesOnTokenRequirement: {Height: 87301},
+			forks.ReplaceStakingTx:            {Height: 559380},
+			forks.StakingTxRoot:               {Height: 559381},
+			forks.FixRandShardCommitment:      {Height: 644000},
+			forks.SwapNewKey:                  {Epochs: MainnetReplaceCommitteeEpoch},
+			forks.ConsensusV2:                 {Epoch: 1e9},
+			forks.TimeLock:                    {Epoch: 1e9},
+		},
 	}
 	if IsTestNet {
 		if !IsTestNet2 {
@@ -1,5 +1,9 @@
 package blockchain
 
+import (
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+)
+
 func (blockchain *BlockChain) GetStakingAmountShard() uint64 {
 	return blockchain.config.ChainParams.StakingAmountShard
 }
@@ -20,16 +24,16 @@ func (blockchain *BlockChain) GetCentralizedWebsitePaymentAddress(beaconHeight u
 	return ""
 }
 
-func (blockchain *BlockChain) GetBeaconHeightBreakPointBurnAddr() uint64 {
-	return blockchain.config.ChainParams.BeaconHeightBreakPointBurnAddr
+// IsForkActive returns whether the consensus rule change fork applies at beaconHeight
+func (blockchain *BlockChain) IsForkActive(fork forks.Fork, beaconHeight uint64) bool {
+	return blockchain.config.ChainParams.IsForkActive(fork, beaconHeight)
 }
 
 func (blockchain *BlockChain) GetBurningAddress(beaconHeight uint64) string {
-	breakPoint := blockchain.GetBeaconHeightBreakPointBurnAddr()
 	if beaconHeight == 0 {
 		beaconHeight = blockchain.BeaconChain.GetFinalViewHeight()
 	}
-	if beaconHeight <= breakPoint {
+	if !blockchain.IsForkActive(forks.BurnAddressV2, beaconHeight) {
 		return burningAddress
 	}
 
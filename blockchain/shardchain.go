@@ -25,6 +25,7 @@ type ShardChain struct {
 	Ready       bool
 
 	insertLock sync.Mutex
+	readyLock  sync.RWMutex
 }
 
 func NewShardChain(shardID int, multiView *multiview.MultiView, blockGen *BlockGenerator, blockchain *BlockChain, chainName string) *ShardChain {
@@ -84,7 +85,7 @@ func (s *ShardChain) GetBestViewHash() string {
 }
 
 func (s *ShardChain) GetFinalViewHash() string {
-	return s.GetBestState().Hash().String()
+	return s.GetFinalView().GetHash().String()
 }
 func (chain *ShardChain) GetLastBlockTimeStamp() int64 {
 	return chain.GetBestState().BestBlock.Header.Timestamp
@@ -99,10 +100,14 @@ func (chain *ShardChain) GetMaxBlkCreateTime() time.Duration {
 }
 
 func (chain *ShardChain) IsReady() bool {
+	chain.readyLock.RLock()
+	defer chain.readyLock.RUnlock()
 	return chain.Ready
 }
 
 func (chain *ShardChain) SetReady(ready bool) {
+	chain.readyLock.Lock()
+	defer chain.readyLock.Unlock()
 	chain.Ready = ready
 }
 
@@ -115,6 +120,22 @@ func (chain *ShardChain) GetCommittee() []incognitokey.CommitteePublicKey {
 	return append(result, chain.GetBestState().ShardCommittee...)
 }
 
+// ChangesCommittee returns true if block, signed by committee, changes the
+// shard committee: the committee root of its header is of the committee after
+// the block
+func (chain *ShardChain) ChangesCommittee(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) bool {
+	shardBlock, ok := block.(*ShardBlock)
+	if !ok {
+		return true
+	}
+	committeeStr, err := incognitokey.CommitteeKeyListToString(committee)
+	if err != nil {
+		return true
+	}
+	_, ok = verifyHashFromStringArray(committeeStr, shardBlock.Header.CommitteeRoot)
+	return !ok
+}
+
 func (chain *ShardChain) GetCommitteeByHeight(h uint64) ([]incognitokey.CommitteePublicKey, error) {
 	bcStateRootHash := chain.Blockchain.GetBeaconBestState().ConsensusStateDBRootHash
 	bcDB := chain.Blockchain.GetBeaconChainDatabase()
@@ -203,6 +224,17 @@ func (chain *ShardChain) ValidateBlockSignatures(block common.BlockInterface, co
 	return nil
 }
 
+// BatchValidateBlockSignatures validates the signatures of blocks signed by
+// committee, the committee signatures are verified at once
+func (chain *ShardChain) BatchValidateBlockSignatures(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	for _, block := range blocks {
+		if err := chain.Blockchain.config.ConsensusEngine.ValidateProducerSig(block, chain.GetConsensusType()); err != nil {
+			return err
+		}
+	}
+	return chain.Blockchain.config.ConsensusEngine.BatchValidateBlockCommitteeSig(blocks, committee)
+}
+
 func (chain *ShardChain) InsertBlk(block common.BlockInterface, shouldValidate bool) error {
 	err := chain.Blockchain.InsertShardBlock(block.(*ShardBlock), shouldValidate)
 	if err != nil {
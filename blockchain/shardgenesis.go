@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -34,6 +35,15 @@ func CreateShardGenesisBlock(
 	}
 
 	for _, tx := range icoParams.InitialIncognito {
+		// privacy token init txs allocate tokens at genesis, see chainparams.go
+		txType := struct{ Type string }{}
+		json.Unmarshal([]byte(tx), &txType)
+		if txType.Type == common.TxCustomTokenPrivacyType {
+			initTokenTx := transaction.TxCustomTokenPrivacy{}
+			initTokenTx.UnmarshalJSON([]byte(tx))
+			body.Transactions = append(body.Transactions, &initTokenTx)
+			continue
+		}
 		testSalaryTX := transaction.Tx{}
 		testSalaryTX.UnmarshalJSON([]byte(tx))
 		body.Transactions = append(body.Transactions, &testSalaryTX)
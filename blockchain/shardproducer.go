@@ -8,12 +8,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/privacy"
 	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/txtracker"
 )
 
 // NewBlockShard Create New block Shard:
@@ -557,7 +559,7 @@ func (blockchain *BlockChain) generateInstruction(view *ShardBestState, shardID
 		}
 
 		badProducersWithPunishment := blockchain.buildBadProducersWithPunishment(false, int(shardID), shardCommittee)
-		if common.IndexOfUint64(beaconHeight/blockchain.config.ChainParams.Epoch, blockchain.config.ChainParams.EpochBreakPointSwapNewKey) > -1 {
+		if blockchain.IsForkActive(forks.SwapNewKey, beaconHeight) {
 			epoch := beaconHeight / blockchain.config.ChainParams.Epoch
 			swapInstruction, shardPendingValidator, shardCommittee = CreateShardSwapActionForKeyListV2(blockchain.config.GenesisParams, shardPendingValidator, backupShardCommittee, NumberOfFixedBlockValidators, blockchain.config.ChainParams.ActiveShards, shardID, epoch)
 		} else {
@@ -706,6 +708,7 @@ func (blockGenerator *BlockGenerator) getPendingTransaction(
 					}
 					tempTxDesc, err := blockGenerator.chain.config.TempTxPool.MaybeAcceptTransactionForBlockProducing(tx2, int64(beaconHeight), curView)
 					if err != nil {
+						txtracker.Evicted(tx2, "invalid for new block: "+err.Error())
 						txToRemove = append(txToRemove, tx2)
 						continue
 					}
@@ -738,6 +741,9 @@ func (blockGenerator *BlockGenerator) getPendingTransaction(
 	}
 	Logger.log.Criticalf(" 🔎 %+v transactions for New Block from pool \n", len(txsToAdd))
 	blockGenerator.chain.config.TempTxPool.EmptyPool()
+	for _, tx := range txsToAdd {
+		txtracker.Picked(tx, shardID, curView.ShardHeight+1)
+	}
 	return txsToAdd, txToRemove, totalFee
 }
 
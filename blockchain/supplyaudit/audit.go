@@ -0,0 +1,320 @@
+// Package supplyaudit checks that the pTokens minted on Incognito match what
+// is locked for them: it replays the issuing and burning instructions of the
+// beacon blocks and compares the totals with the bridge token amounts of the
+// beacon feature StateDB, the public tokens held by the portal custodians and,
+// when a provider is given, the balances of the external vaults.
+package supplyaudit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/pkg/errors"
+)
+
+const DefaultRangeSize = 1000
+
+// Checks compared by the audit
+const (
+	// CheckStateDB compares the amount of a token in the feature StateDB with
+	// its amount at the start of the audit plus the amounts minted and burned
+	CheckStateDB = "statedb"
+	// CheckCustodian compares the public tokens held by the custodians and
+	// the liquidation pool with the supply of a portal token plus the redeems
+	// no custodian is matched to yet
+	CheckCustodian = "custodian"
+	// CheckExternal compares the balance of an external vault with the
+	// supply of a bridge token
+	CheckExternal = "external"
+)
+
+// ErrStopped is returned by Audit when its Stop channel is closed
+var ErrStopped = errors.New("supply audit stopped")
+
+// ErrNoBalance is returned by an ExternalBalanceProvider for the tokens it
+// has no balance of, they are not checked
+var ErrNoBalance = errors.New("no external balance for token")
+
+// ExternalBalanceProvider returns the amounts locked out of Incognito for the
+// bridge tokens, e.g. the balances of the Ethereum vault. Amounts are in the
+// unit of the pToken: the provider converts the decimals of the external
+// token.
+type ExternalBalanceProvider interface {
+	Balance(tokenID common.Hash, externalTokenID []byte, isCentralized bool) (uint64, error)
+}
+
+// Balances is an ExternalBalanceProvider of fixed balances by pToken id, e.g.
+// read from a file written from the vault contracts
+type Balances map[string]uint64
+
+func (balances Balances) Balance(tokenID common.Hash, externalTokenID []byte, isCentralized bool) (uint64, error) {
+	balance, ok := balances[tokenID.String()]
+	if !ok {
+		return 0, ErrNoBalance
+	}
+	return balance, nil
+}
+
+// Source reads the beacon chain, it is ChainSource on a node
+type Source interface {
+	FinalBeaconHeight() uint64
+	BeaconInstructions(height uint64) ([][]string, error)
+	// State returns the bridge token and portal state after the beacon block
+	// at height
+	State(height uint64) (*State, error)
+}
+
+type TokenState struct {
+	TokenID         common.Hash
+	ExternalTokenID []byte
+	Amount          uint64
+	IsCentralized   bool
+}
+
+// State is the state of the bridge tokens after a beacon block
+type State struct {
+	Tokens []TokenState
+	// CustodianTokens are the public tokens held by the custodians and the
+	// liquidation pool, by token id
+	CustodianTokens map[string]uint64
+	// RedeemingTokens are the amounts of the redeem requests no custodian is
+	// matched to yet: burned on Incognito but still held by the custodians,
+	// the amounts matched are no longer counted in the custodian holdings
+	RedeemingTokens map[string]uint64
+}
+
+type Config struct {
+	Source   Source
+	External ExternalBalanceProvider
+	// RangeSize is the number of beacon blocks between two comparisons
+	RangeSize uint64
+	// Stop stops the audit when it is closed, e.g. when the RPC client goes
+	// away
+	Stop <-chan struct{}
+}
+
+type Discrepancy struct {
+	TokenID       string `json:"TokenID"`
+	IsCentralized bool   `json:"IsCentralized"`
+	Check         string `json:"Check"`
+	Expected      uint64 `json:"Expected"`
+	Actual        uint64 `json:"Actual"`
+}
+
+type RangeReport struct {
+	FromHeight    uint64         `json:"FromHeight"`
+	ToHeight      uint64         `json:"ToHeight"`
+	Discrepancies []*Discrepancy `json:"Discrepancies"`
+}
+
+type TokenReport struct {
+	TokenID         string `json:"TokenID"`
+	ExternalTokenID string `json:"ExternalTokenID"`
+	IsCentralized   bool   `json:"IsCentralized"`
+	Portal          bool   `json:"Portal"`
+	// OpeningAmount is the amount in the feature StateDB before the audit
+	OpeningAmount uint64 `json:"OpeningAmount"`
+	Minted        uint64 `json:"Minted"`
+	Burned        uint64 `json:"Burned"`
+	StateAmount   uint64 `json:"StateAmount"`
+	// CustodianAmount is only set for the portal tokens and ExternalAmount
+	// for the tokens the provider has a balance of
+	CustodianAmount *uint64 `json:"CustodianAmount,omitempty"`
+	ExternalAmount  *uint64 `json:"ExternalAmount,omitempty"`
+}
+
+type Report struct {
+	FromHeight    uint64         `json:"FromHeight"`
+	ToHeight      uint64         `json:"ToHeight"`
+	Ranges        []*RangeReport `json:"Ranges"`
+	Tokens        []*TokenReport `json:"Tokens"`
+	Discrepancies int            `json:"Discrepancies"`
+}
+
+// Audit replays the beacon blocks from fromHeight to toHeight, 0 is the final
+// beacon block. The feature StateDB and the custodians are compared at the end
+// of every range of blocks, the external balances at the end of the audit
+// only: they are the current balances.
+func Audit(config Config, fromHeight, toHeight uint64) (*Report, error) {
+	if config.Source == nil {
+		return nil, errors.New("supply audit needs a source")
+	}
+	if config.RangeSize == 0 {
+		config.RangeSize = DefaultRangeSize
+	}
+	final := config.Source.FinalBeaconHeight()
+	if toHeight == 0 || toHeight > final {
+		toHeight = final
+	}
+	if fromHeight == 0 {
+		fromHeight = 1
+	}
+	if fromHeight > toHeight {
+		return nil, fmt.Errorf("invalid beacon height range %v-%v, final beacon height is %v", fromHeight, toHeight, final)
+	}
+
+	tokens := map[tokenKey]*TokenReport{}
+	if fromHeight > 1 {
+		opening, err := config.Source.State(fromHeight - 1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get state at beacon height %v", fromHeight-1)
+		}
+		for _, token := range opening.Tokens {
+			report := tokenReport(tokens, tokenKey{tokenID: token.TokenID, isCentralized: token.IsCentralized}, token.ExternalTokenID)
+			report.OpeningAmount = token.Amount
+		}
+	}
+
+	report := &Report{FromHeight: fromHeight, ToHeight: toHeight, Ranges: []*RangeReport{}}
+	for start := fromHeight; start <= toHeight; start += config.RangeSize {
+		end := start + config.RangeSize - 1
+		if end > toHeight || end < start {
+			end = toHeight
+		}
+		for height := start; height <= end; height++ {
+			select {
+			case <-config.Stop:
+				return nil, ErrStopped
+			default:
+			}
+			insts, err := config.Source.BeaconInstructions(height)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot get beacon block %v", height)
+			}
+			for _, inst := range insts {
+				f, ok := instructionFlow(inst)
+				if !ok {
+					continue
+				}
+				token := tokenReport(tokens, f.key, f.externalTokenID)
+				token.Portal = token.Portal || f.portal
+				token.Minted += f.minted
+				token.Burned += f.burned
+			}
+		}
+
+		state, err := config.Source.State(end)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get state at beacon height %v", end)
+		}
+		rangeReport := &RangeReport{FromHeight: start, ToHeight: end, Discrepancies: compareState(tokens, state)}
+		report.Ranges = append(report.Ranges, rangeReport)
+		if end == toHeight {
+			break
+		}
+	}
+
+	if config.External != nil {
+		last := report.Ranges[len(report.Ranges)-1]
+		for _, key := range sortedKeys(tokens) {
+			token := tokens[key]
+			if token.Portal {
+				continue
+			}
+			externalTokenID, _ := hex.DecodeString(token.ExternalTokenID)
+			balance, err := config.External.Balance(key.tokenID, externalTokenID, key.isCentralized)
+			if err == ErrNoBalance {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot get external balance of token %v", token.TokenID)
+			}
+			token.ExternalAmount = &balance
+			if balance != token.StateAmount {
+				last.Discrepancies = append(last.Discrepancies, &Discrepancy{
+					TokenID:       token.TokenID,
+					IsCentralized: key.isCentralized,
+					Check:         CheckExternal,
+					Expected:      token.StateAmount,
+					Actual:        balance,
+				})
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(tokens) {
+		report.Tokens = append(report.Tokens, tokens[key])
+	}
+	for _, r := range report.Ranges {
+		report.Discrepancies += len(r.Discrepancies)
+	}
+	return report, nil
+}
+
+// compareState checks the state at the end of a range with the amounts
+// replayed so far and updates the amounts of the token reports
+func compareState(tokens map[tokenKey]*TokenReport, state *State) []*Discrepancy {
+	discrepancies := []*Discrepancy{}
+	stateAmounts := map[tokenKey]uint64{}
+	for _, token := range state.Tokens {
+		key := tokenKey{tokenID: token.TokenID, isCentralized: token.IsCentralized}
+		tokenReport(tokens, key, token.ExternalTokenID)
+		stateAmounts[key] = token.Amount
+	}
+	for tokenID := range state.CustodianTokens {
+		if id, err := (common.Hash{}).NewHashFromStr(tokenID); err == nil {
+			tokenReport(tokens, tokenKey{tokenID: *id}, nil).Portal = true
+		}
+	}
+
+	for _, key := range sortedKeys(tokens) {
+		token := tokens[key]
+		token.StateAmount = stateAmounts[key]
+		// the beacon does not let an amount go below zero, neither do we
+		expected := uint64(0)
+		if token.OpeningAmount+token.Minted > token.Burned {
+			expected = token.OpeningAmount + token.Minted - token.Burned
+		}
+		if expected != token.StateAmount {
+			discrepancies = append(discrepancies, &Discrepancy{
+				TokenID:       token.TokenID,
+				IsCentralized: key.isCentralized,
+				Check:         CheckStateDB,
+				Expected:      expected,
+				Actual:        token.StateAmount,
+			})
+		}
+		if !token.Portal || key.isCentralized {
+			continue
+		}
+		held := state.CustodianTokens[token.TokenID]
+		token.CustodianAmount = &held
+		if expected := token.StateAmount + state.RedeemingTokens[token.TokenID]; held != expected {
+			discrepancies = append(discrepancies, &Discrepancy{
+				TokenID:  token.TokenID,
+				Check:    CheckCustodian,
+				Expected: expected,
+				Actual:   held,
+			})
+		}
+	}
+	return discrepancies
+}
+
+func tokenReport(tokens map[tokenKey]*TokenReport, key tokenKey, externalTokenID []byte) *TokenReport {
+	token, ok := tokens[key]
+	if !ok {
+		token = &TokenReport{TokenID: key.tokenID.String(), IsCentralized: key.isCentralized}
+		tokens[key] = token
+	}
+	if token.ExternalTokenID == "" && len(externalTokenID) > 0 {
+		token.ExternalTokenID = hex.EncodeToString(externalTokenID)
+	}
+	return token
+}
+
+func sortedKeys(tokens map[tokenKey]*TokenReport) []tokenKey {
+	keys := make([]tokenKey, 0, len(tokens))
+	for key := range tokens {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].tokenID != keys[j].tokenID {
+			return keys[i].tokenID.String() < keys[j].tokenID.String()
+		}
+		return !keys[i].isCentralized && keys[j].isCentralized
+	})
+	return keys
+}
@@ -0,0 +1,184 @@
+package supplyaudit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+
+	rCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSource struct {
+	insts  map[uint64][][]string
+	states map[uint64]*State
+	final  uint64
+}
+
+func (s *testSource) FinalBeaconHeight() uint64 {
+	return s.final
+}
+
+func (s *testSource) BeaconInstructions(height uint64) ([][]string, error) {
+	return s.insts[height], nil
+}
+
+func (s *testSource) State(height uint64) (*State, error) {
+	if state, ok := s.states[height]; ok {
+		return state, nil
+	}
+	return &State{}, nil
+}
+
+var (
+	pETH  = common.HashH([]byte("pETH"))
+	pBNB  = common.HashH([]byte("pBNB"))
+	pBTC  = common.HashH([]byte("pBTC"))
+	ethID = rCommon.HexToAddress(common.EthAddrStr).Bytes()
+)
+
+func base64Content(t *testing.T, content interface{}) string {
+	contentBytes, err := json.Marshal(content)
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(contentBytes)
+}
+
+func issuingETHInst(t *testing.T, amount uint64) []string {
+	content := metadata.IssuingETHAcceptedInst{IssuingAmount: amount, IncTokenID: pETH, ExternalTokenID: ethID}
+	return []string{strconv.Itoa(metadata.IssuingETHRequestMeta), "1", "accepted", base64Content(t, content)}
+}
+
+func issuingInst(t *testing.T, amount uint64) []string {
+	content := metadata.IssuingAcceptedInst{DepositedAmount: amount, IncTokenID: pBNB}
+	return []string{strconv.Itoa(metadata.IssuingRequestMeta), "1", "accepted", base64Content(t, content)}
+}
+
+// burningConfirmInst returns a burning confirm of pETH, amounts are in wei
+func burningConfirmInst(weiAmount uint64) []string {
+	amount := new(big.Int).SetUint64(weiAmount)
+	return []string{
+		strconv.Itoa(metadata.BurningConfirmMeta),
+		"1",
+		base58.Base58Check{}.Encode(ethID, 0x00),
+		"remote",
+		base58.Base58Check{}.Encode(amount.Bytes(), 0x00),
+		common.HashH([]byte{1}).String(),
+		base58.Base58Check{}.Encode(pETH[:], 0x00),
+		base58.Base58Check{}.Encode(big.NewInt(1).Bytes(), 0x00),
+	}
+}
+
+func portalInst(t *testing.T, metaType int, status string, content interface{}) []string {
+	contentBytes, err := json.Marshal(content)
+	assert.NoError(t, err)
+	return []string{strconv.Itoa(metaType), "1", status, string(contentBytes)}
+}
+
+func TestInstructionFlow(t *testing.T) {
+	f, ok := instructionFlow(issuingETHInst(t, 100))
+	assert.True(t, ok)
+	assert.Equal(t, flow{key: tokenKey{tokenID: pETH}, externalTokenID: ethID, minted: 100}, *f)
+
+	f, ok = instructionFlow(issuingInst(t, 5))
+	assert.True(t, ok)
+	assert.Equal(t, flow{key: tokenKey{tokenID: pBNB, isCentralized: true}, minted: 5}, *f)
+
+	// the burns of ETH are moved from wei to nano
+	f, ok = instructionFlow(burningConfirmInst(30 * 1e9))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(30), f.burned)
+	assert.Equal(t, tokenKey{tokenID: pETH}, f.key)
+
+	f, ok = instructionFlow(portalInst(t, metadata.PortalRedeemRequestMeta, common.PortalRedeemReqCancelledByLiquidationChainStatus,
+		metadata.PortalRedeemRequestContent{TokenID: pBTC.String(), RedeemAmount: 7}))
+	assert.True(t, ok)
+	assert.Equal(t, flow{key: tokenKey{tokenID: pBTC}, portal: true, minted: 7}, *f)
+
+	for _, inst := range [][]string{
+		{strconv.Itoa(metadata.IssuingETHRequestMeta), "1", "rejected", common.HashH(nil).String()},
+		{strconv.Itoa(metadata.IssuingRequestMeta), "1", "accepted", "invalid"},
+		burningConfirmInst(1)[:7],
+		portalInst(t, metadata.PortalUserRequestPTokenMeta, "rejected", metadata.PortalRequestPTokensContent{TokenID: pBTC.String(), PortingAmount: 1}),
+		{strconv.Itoa(metadata.BeaconSwapConfirmMeta), "1"},
+	} {
+		_, ok := instructionFlow(inst)
+		assert.False(t, ok, inst)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	source := &testSource{
+		final: 5,
+		insts: map[uint64][][]string{
+			2: {issuingETHInst(t, 100), issuingInst(t, 5)},
+			3: {burningConfirmInst(30 * 1e9), portalInst(t, metadata.PortalUserRequestPTokenMeta, common.PortalReqPTokensAcceptedChainStatus,
+				metadata.PortalRequestPTokensContent{TokenID: pBTC.String(), PortingAmount: 50})},
+			5: {portalInst(t, metadata.PortalRedeemRequestMeta, common.PortalRedeemRequestAcceptedChainStatus,
+				metadata.PortalRedeemRequestContent{TokenID: pBTC.String(), RedeemAmount: 20})},
+		},
+		states: map[uint64]*State{
+			1: {Tokens: []TokenState{{TokenID: pETH, ExternalTokenID: ethID, Amount: 10}}},
+			4: {
+				Tokens: []TokenState{
+					{TokenID: pETH, ExternalTokenID: ethID, Amount: 80},
+					{TokenID: pBNB, Amount: 5, IsCentralized: true},
+					{TokenID: pBTC, Amount: 50},
+				},
+				CustodianTokens: map[string]uint64{pBTC.String(): 50},
+			},
+			// the redeem is not matched yet, the custodians hold its tokens
+			5: {
+				Tokens: []TokenState{
+					{TokenID: pETH, ExternalTokenID: ethID, Amount: 80},
+					{TokenID: pBNB, Amount: 6, IsCentralized: true},
+					{TokenID: pBTC, Amount: 30},
+				},
+				CustodianTokens: map[string]uint64{pBTC.String(): 50},
+				RedeemingTokens: map[string]uint64{pBTC.String(): 20},
+			},
+		},
+	}
+	report, err := Audit(Config{Source: source, External: Balances{pETH.String(): 70}, RangeSize: 3}, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), report.FromHeight)
+	assert.Equal(t, uint64(5), report.ToHeight)
+	assert.Len(t, report.Ranges, 2)
+	assert.Empty(t, report.Ranges[0].Discrepancies)
+	assert.Equal(t, []*Discrepancy{
+		{TokenID: pBNB.String(), IsCentralized: true, Check: CheckStateDB, Expected: 5, Actual: 6},
+		{TokenID: pETH.String(), Check: CheckExternal, Expected: 80, Actual: 70},
+	}, report.Ranges[1].Discrepancies)
+	assert.Equal(t, 2, report.Discrepancies)
+
+	tokens := map[string]*TokenReport{}
+	for _, token := range report.Tokens {
+		tokens[token.TokenID] = token
+	}
+	assert.Equal(t, uint64(10), tokens[pETH.String()].OpeningAmount)
+	assert.Equal(t, uint64(100), tokens[pETH.String()].Minted)
+	assert.Equal(t, uint64(30), tokens[pETH.String()].Burned)
+	assert.Equal(t, uint64(70), *tokens[pETH.String()].ExternalAmount)
+	assert.True(t, tokens[pBTC.String()].Portal)
+	assert.Equal(t, uint64(50), *tokens[pBTC.String()].CustodianAmount)
+	assert.Nil(t, tokens[pBNB.String()].ExternalAmount)
+
+	// the custodians lost tokens
+	source.states[5].CustodianTokens[pBTC.String()] = 40
+	report, err = Audit(Config{Source: source, RangeSize: 10}, 0, 5)
+	assert.NoError(t, err)
+	assert.Len(t, report.Ranges, 1)
+	assert.Contains(t, report.Ranges[0].Discrepancies, &Discrepancy{TokenID: pBTC.String(), Check: CheckCustodian, Expected: 50, Actual: 40})
+
+	_, err = Audit(Config{Source: source}, 6, 5)
+	assert.Error(t, err)
+
+	stop := make(chan struct{})
+	close(stop)
+	_, err = Audit(Config{Source: source, Stop: stop}, 0, 5)
+	assert.Equal(t, ErrStopped, err)
+}
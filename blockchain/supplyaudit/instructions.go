@@ -0,0 +1,149 @@
+package supplyaudit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	rCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// flow is the amount of a pToken minted or burned by a beacon instruction
+type flow struct {
+	key             tokenKey
+	externalTokenID []byte
+	portal          bool
+	minted          uint64
+	burned          uint64
+}
+
+// tokenKey identifies a bridge token as the feature StateDB does, a token id
+// can be both a centralized and a decentralized bridge token
+type tokenKey struct {
+	tokenID       common.Hash
+	isCentralized bool
+}
+
+// instructionFlow returns the flow of an instruction changing the amount of a
+// bridge token, it mirrors processBridgeInstructions and
+// processPortalInstructions of the blockchain package: the instructions
+// skipped by the beacon are skipped here
+func instructionFlow(inst []string) (*flow, bool) {
+	if len(inst) < 2 {
+		return nil, false
+	}
+	switch inst[0] {
+	case strconv.Itoa(metadata.IssuingETHRequestMeta):
+		if len(inst) != 4 || inst[2] == "rejected" {
+			return nil, false
+		}
+		var content metadata.IssuingETHAcceptedInst
+		if !decodeBase64Content(inst[3], &content) {
+			return nil, false
+		}
+		return &flow{
+			key:             tokenKey{tokenID: content.IncTokenID},
+			externalTokenID: content.ExternalTokenID,
+			minted:          content.IssuingAmount,
+		}, true
+
+	case strconv.Itoa(metadata.IssuingRequestMeta):
+		if len(inst) != 4 || inst[2] == "rejected" {
+			return nil, false
+		}
+		var content metadata.IssuingAcceptedInst
+		if !decodeBase64Content(inst[3], &content) {
+			return nil, false
+		}
+		return &flow{key: tokenKey{tokenID: content.IncTokenID, isCentralized: true}, minted: content.DepositedAmount}, true
+
+	case strconv.Itoa(metadata.ContractingRequestMeta):
+		if len(inst) != 4 || inst[2] == "rejected" {
+			return nil, false
+		}
+		var content metadata.ContractingReqAction
+		if !decodeBase64Content(inst[3], &content) {
+			return nil, false
+		}
+		return &flow{key: tokenKey{tokenID: content.Meta.TokenID, isCentralized: true}, burned: content.Meta.BurnedAmount}, true
+
+	case strconv.Itoa(metadata.BurningConfirmMeta), strconv.Itoa(metadata.BurningConfirmForDepositToSCMeta):
+		if len(inst) < 8 {
+			return nil, false
+		}
+		externalTokenID, _, errExtToken := base58.Base58Check{}.Decode(inst[2])
+		incTokenIDBytes, _, errIncToken := base58.Base58Check{}.Decode(inst[6])
+		amountBytes, _, errAmount := base58.Base58Check{}.Decode(inst[4])
+		if common.CheckError(errExtToken, errIncToken, errAmount) != nil {
+			return nil, false
+		}
+		incTokenID, err := common.Hash{}.NewHash(incTokenIDBytes)
+		if err != nil {
+			return nil, false
+		}
+		amount := big.NewInt(0).SetBytes(amountBytes)
+		// the burning confirms of ETH are in wei, the pETH amounts in nano
+		if bytes.Equal(externalTokenID, rCommon.HexToAddress(common.EthAddrStr).Bytes()) {
+			amount.Div(amount, big.NewInt(1000000000))
+		}
+		return &flow{key: tokenKey{tokenID: *incTokenID}, externalTokenID: externalTokenID, burned: amount.Uint64()}, true
+
+	case strconv.Itoa(metadata.PortalUserRequestPTokenMeta):
+		if len(inst) != 4 || inst[2] != common.PortalReqPTokensAcceptedChainStatus {
+			return nil, false
+		}
+		var content metadata.PortalRequestPTokensContent
+		if json.Unmarshal([]byte(inst[3]), &content) != nil {
+			return nil, false
+		}
+		return portalFlow(content.TokenID, content.PortingAmount, 0)
+
+	case strconv.Itoa(metadata.PortalRedeemRequestMeta):
+		if len(inst) != 4 {
+			return nil, false
+		}
+		var content metadata.PortalRedeemRequestContent
+		if json.Unmarshal([]byte(inst[3]), &content) != nil {
+			return nil, false
+		}
+		switch inst[2] {
+		case common.PortalRedeemRequestAcceptedChainStatus:
+			return portalFlow(content.TokenID, 0, content.RedeemAmount)
+		case common.PortalRedeemReqCancelledByLiquidationChainStatus:
+			// the pTokens of the redeem are returned to the user
+			return portalFlow(content.TokenID, content.RedeemAmount, 0)
+		}
+
+	case strconv.Itoa(metadata.PortalRedeemLiquidateExchangeRatesMeta):
+		if len(inst) != 4 || inst[2] != common.PortalRedeemLiquidateExchangeRatesSuccessChainStatus {
+			return nil, false
+		}
+		var content metadata.PortalRedeemLiquidateExchangeRatesContent
+		if json.Unmarshal([]byte(inst[3]), &content) != nil {
+			return nil, false
+		}
+		return portalFlow(content.TokenID, 0, content.RedeemAmount)
+	}
+	return nil, false
+}
+
+func portalFlow(tokenIDStr string, minted, burned uint64) (*flow, bool) {
+	tokenID, err := common.Hash{}.NewHashFromStr(tokenIDStr)
+	if err != nil {
+		return nil, false
+	}
+	return &flow{key: tokenKey{tokenID: *tokenID}, portal: true, minted: minted, burned: burned}, true
+}
+
+func decodeBase64Content(content string, action interface{}) bool {
+	contentBytes, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(contentBytes, action) == nil
+}
@@ -0,0 +1,84 @@
+package supplyaudit
+
+import (
+	"encoding/json"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+)
+
+// ChainSource reads the beacon blocks and the beacon feature StateDB of a
+// node
+type ChainSource struct {
+	blockChain *blockchain.BlockChain
+}
+
+func NewChainSource(bc *blockchain.BlockChain) *ChainSource {
+	return &ChainSource{blockChain: bc}
+}
+
+func (source *ChainSource) FinalBeaconHeight() uint64 {
+	return source.blockChain.BeaconChain.GetFinalViewState().BeaconHeight
+}
+
+func (source *ChainSource) BeaconInstructions(height uint64) ([][]string, error) {
+	beaconBlocks, err := blockchain.FetchBeaconBlockFromHeight(source.blockChain, height, height)
+	if err != nil {
+		return nil, err
+	}
+	return beaconBlocks[0].Body.Instructions, nil
+}
+
+func (source *ChainSource) State(height uint64) (*State, error) {
+	stateDB, err := source.blockChain.GetBestStateBeaconFeatureStateDBByHeight(height, source.blockChain.GetBeaconChainDatabase())
+	if err != nil {
+		return nil, err
+	}
+	allBridgeTokensBytes, err := statedb.GetAllBridgeTokens(stateDB)
+	if err != nil {
+		return nil, err
+	}
+	allBridgeTokens := []*rawdbv2.BridgeTokenInfo{}
+	if err := json.Unmarshal(allBridgeTokensBytes, &allBridgeTokens); err != nil {
+		return nil, err
+	}
+	portalState, err := blockchain.InitCurrentPortalStateFromDB(stateDB)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{CustodianTokens: map[string]uint64{}, RedeemingTokens: map[string]uint64{}}
+	for _, token := range allBridgeTokens {
+		state.Tokens = append(state.Tokens, TokenState{
+			TokenID:         *token.TokenID,
+			ExternalTokenID: token.ExternalTokenID,
+			Amount:          token.Amount,
+			IsCentralized:   token.IsCentralized,
+		})
+	}
+	for _, custodian := range portalState.CustodianPoolState {
+		for tokenID, amount := range custodian.GetHoldingPublicTokens() {
+			state.CustodianTokens[tokenID] += amount
+		}
+	}
+	for _, pool := range portalState.LiquidationPool {
+		for tokenID, detail := range pool.Rates() {
+			state.CustodianTokens[tokenID] += detail.PubTokenAmount
+		}
+	}
+	for _, redeems := range []map[string]*statedb.RedeemRequest{portalState.WaitingRedeemRequests, portalState.MatchedRedeemRequests} {
+		for _, redeem := range redeems {
+			unmatched := redeem.GetRedeemAmount()
+			for _, custodian := range redeem.GetCustodians() {
+				if custodian.GetAmount() > unmatched {
+					unmatched = 0
+					break
+				}
+				unmatched -= custodian.GetAmount()
+			}
+			state.RedeemingTokens[redeem.GetTokenID()] += unmatched
+		}
+	}
+	return state, nil
+}
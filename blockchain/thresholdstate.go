@@ -0,0 +1,266 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// ThresholdState define the various threshold states used when voting on
+// consensus changes.
+type ThresholdState byte
+
+// These constants are used to identify specific threshold states.
+const (
+	// ThresholdDefined is the first state for each deployment and is the
+	// state for the genesis block has by definition for all deployments.
+	ThresholdDefined ThresholdState = iota
+
+	// ThresholdStarted is the state for a deployment once its start height
+	// has been reached.
+	ThresholdStarted
+
+	// ThresholdLockedIn is the state for a deployment during the window
+	// which is after the ThresholdStarted state window and the number of
+	// beacon blocks that have signalled for the deployment equal or exceed
+	// the required number of votes for the deployment.
+	ThresholdLockedIn
+
+	// ThresholdActive is the state for a deployment for all blocks after a
+	// window in which the deployment was in the ThresholdLockedIn state.
+	ThresholdActive
+
+	// ThresholdFailed is the state for a deployment once its expire height
+	// has been reached and it did not reach the ThresholdLockedIn state.
+	ThresholdFailed
+)
+
+// thresholdStateStrings is a map of ThresholdState values back to their
+// constant names for pretty printing.
+var thresholdStateStrings = map[ThresholdState]string{
+	ThresholdDefined:  "ThresholdDefined",
+	ThresholdStarted:  "ThresholdStarted",
+	ThresholdLockedIn: "ThresholdLockedIn",
+	ThresholdActive:   "ThresholdActive",
+	ThresholdFailed:   "ThresholdFailed",
+}
+
+// String returns the ThresholdState as a human-readable name.
+func (t ThresholdState) String() string {
+	if s := thresholdStateStrings[t]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown ThresholdState (%d)", int(t))
+}
+
+// thresholdConditionChecker provides a generic interface that is invoked to
+// determine when a consensus rule change threshold should be changed.
+type thresholdConditionChecker interface {
+	// StartHeight returns the beacon height from which voting on a rule
+	// change starts (at the next window).
+	StartHeight() uint64
+
+	// ExpireHeight returns the beacon height after which an attempted rule
+	// change fails if it has not already been locked in or activated.
+	ExpireHeight() uint64
+
+	// RuleChangeActivationThreshold is the number of blocks for which the
+	// condition must be true in order to lock in a rule change.
+	RuleChangeActivationThreshold() uint64
+
+	// ConfirmationWindow is the number of beacon blocks in each threshold
+	// state window.
+	ConfirmationWindow() uint64
+
+	// Condition returns whether or not the rule change activation condition
+	// has been met by the given beacon header.
+	Condition(*BeaconHeader) bool
+}
+
+// thresholdStateCache provides a type to cache the threshold states of each
+// threshold window, keyed by the hash of the last beacon block of the window.
+type thresholdStateCache struct {
+	entries map[common.Hash]ThresholdState
+}
+
+// Lookup returns the threshold state associated with the given hash along with
+// a boolean that indicates whether or not it is valid.
+func (c *thresholdStateCache) Lookup(hash common.Hash) (ThresholdState, bool) {
+	state, ok := c.entries[hash]
+	return state, ok
+}
+
+// Update updates the cache to contain the provided hash to threshold state
+// mapping.
+func (c *thresholdStateCache) Update(hash common.Hash, state ThresholdState) {
+	c.entries[hash] = state
+}
+
+// thresholdCaches holds one cache per deployment, the lock must be held while
+// computing a threshold state.
+type thresholdCaches struct {
+	lock   sync.Mutex
+	caches []thresholdStateCache
+}
+
+// newThresholdCaches returns a new array of caches to be used when calculating
+// threshold states.
+func newThresholdCaches(numCaches int) *thresholdCaches {
+	caches := make([]thresholdStateCache, numCaches)
+	for i := 0; i < len(caches); i++ {
+		caches[i] = thresholdStateCache{
+			entries: make(map[common.Hash]ThresholdState),
+		}
+	}
+	return &thresholdCaches{caches: caches}
+}
+
+// beaconHeaderSource provides the beacon blocks the threshold states are
+// computed from.
+type beaconHeaderSource interface {
+	// HashByHeight returns the hash of the beacon block at the given height.
+	HashByHeight(height uint64) (*common.Hash, error)
+
+	// HeaderByHash returns the header of the beacon block of the given hash.
+	HeaderByHash(hash common.Hash) (*BeaconHeader, error)
+}
+
+// viewHeaderSource provides the beacon blocks of the branch of a view.
+type viewHeaderSource struct {
+	blockchain *BlockChain
+	view       *BeaconBestState
+}
+
+// HashByHeight returns the hash of the beacon block at the given height on the
+// branch of the view.
+func (s viewHeaderSource) HashByHeight(height uint64) (*common.Hash, error) {
+	return s.blockchain.GetBeaconBlockHashByHeight(s.blockchain.BeaconChain.GetFinalView(), s.view, height)
+}
+
+// HeaderByHash returns the header of the beacon block of the given hash.
+func (s viewHeaderSource) HeaderByHash(hash common.Hash) (*BeaconHeader, error) {
+	block, _, err := s.blockchain.GetBeaconBlockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &block.Header, nil
+}
+
+// windowEnd is the last beacon block of a threshold window
+type windowEnd struct {
+	hash   common.Hash
+	height uint64
+}
+
+// thresholdState returns the current rule change threshold state for the block
+// AFTER the given view. The cache is used to ensure the threshold states for
+// previous windows are only calculated once.
+//
+// This function MUST be called with the threshold caches lock held.
+func (blockchain *BlockChain) thresholdState(prevView *BeaconBestState, checker thresholdConditionChecker, cache *thresholdStateCache) (ThresholdState, error) {
+	if prevView == nil {
+		return ThresholdDefined, nil
+	}
+	return calcThresholdState(prevView.BeaconHeight, viewHeaderSource{blockchain: blockchain, view: prevView}, checker, cache)
+}
+
+// calcThresholdState returns the rule change threshold state for the block
+// after the beacon block at prevHeight, the blocks are looked up in headers.
+//
+// This function MUST be called with the threshold caches lock held.
+func calcThresholdState(prevHeight uint64, headers beaconHeaderSource, checker thresholdConditionChecker, cache *thresholdStateCache) (ThresholdState, error) {
+	// The threshold state for the first window is defined by definition.
+	window := checker.ConfirmationWindow()
+	if window == 0 || prevHeight < window {
+		return ThresholdDefined, nil
+	}
+
+	// Beacon heights start at 1 so window k holds heights [k*window+1, (k+1)*window],
+	// the state is the same for all blocks within a given window and is
+	// computed at the last block of the previous window.
+	endHeight := prevHeight - prevHeight%window
+
+	// Iterate backwards through each of the previous windows to find the most
+	// recently cached threshold state.
+	var neededStates []windowEnd
+	state := ThresholdDefined
+	for endHeight >= window {
+		hash, err := headers.HashByHeight(endHeight)
+		if err != nil {
+			return ThresholdFailed, err
+		}
+		if cachedState, ok := cache.Lookup(*hash); ok {
+			state = cachedState
+			break
+		}
+		// The state is simply defined if the voting has not started yet.
+		if endHeight < checker.StartHeight() {
+			cache.Update(*hash, ThresholdDefined)
+			break
+		}
+		neededStates = append(neededStates, windowEnd{hash: *hash, height: endHeight})
+		endHeight -= window
+	}
+
+	// Since each threshold state depends on the state of the previous window,
+	// iterate starting from the oldest unknown window.
+	for i := len(neededStates) - 1; i >= 0; i-- {
+		end := neededStates[i]
+		switch state {
+		case ThresholdDefined:
+			if end.height >= checker.ExpireHeight() {
+				state = ThresholdFailed
+				break
+			}
+			if end.height >= checker.StartHeight() {
+				state = ThresholdStarted
+			}
+
+		case ThresholdStarted:
+			if end.height >= checker.ExpireHeight() {
+				state = ThresholdFailed
+				break
+			}
+			count, err := countWindowSignals(headers, end, window, checker)
+			if err != nil {
+				return ThresholdFailed, err
+			}
+			// The state is locked in if the number of blocks in the window
+			// that voted for the change exceeds the activation threshold.
+			if count >= checker.RuleChangeActivationThreshold() {
+				state = ThresholdLockedIn
+			}
+
+		case ThresholdLockedIn:
+			// The new rule becomes active when its previous state was
+			// locked in.
+			state = ThresholdActive
+
+		// Nothing to do if the previous state is active or failed since
+		// they are both terminal states.
+		case ThresholdActive:
+		case ThresholdFailed:
+		}
+		cache.Update(end.hash, state)
+	}
+	return state, nil
+}
+
+// countWindowSignals returns the number of beacon blocks of the window ending at
+// end which meet the checker condition.
+func countWindowSignals(headers beaconHeaderSource, end windowEnd, window uint64, checker thresholdConditionChecker) (uint64, error) {
+	count := uint64(0)
+	hash := end.hash
+	for i := uint64(0); i < window; i++ {
+		header, err := headers.HeaderByHash(hash)
+		if err != nil {
+			return 0, err
+		}
+		if checker.Condition(header) {
+			count++
+		}
+		hash = header.PreviousBlockHash
+	}
+	return count, nil
+}
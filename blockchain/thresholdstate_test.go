@@ -0,0 +1,182 @@
+package blockchain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// testHeaderSource is a beacon chain of the given height whose blocks signal
+// the version bits of versionBits
+type testHeaderSource struct {
+	hashes  map[uint64]common.Hash
+	headers map[common.Hash]*BeaconHeader
+}
+
+func newTestHeaderSource(height uint64, versionBits map[uint64]uint32) *testHeaderSource {
+	s := &testHeaderSource{hashes: map[uint64]common.Hash{}, headers: map[common.Hash]*BeaconHeader{}}
+	prevHash := common.Hash{}
+	for h := uint64(1); h <= height; h++ {
+		header := &BeaconHeader{Version: 1, Height: h, PreviousBlockHash: prevHash, VersionBits: versionBits[h]}
+		prevHash = header.Hash()
+		s.hashes[h] = prevHash
+		s.headers[prevHash] = header
+	}
+	return s
+}
+
+func (s *testHeaderSource) HashByHeight(height uint64) (*common.Hash, error) {
+	hash, ok := s.hashes[height]
+	if !ok {
+		return nil, fmt.Errorf("no block at height %d", height)
+	}
+	return &hash, nil
+}
+
+func (s *testHeaderSource) HeaderByHash(hash common.Hash) (*BeaconHeader, error) {
+	header, ok := s.headers[hash]
+	if !ok {
+		return nil, fmt.Errorf("no block of hash %v", hash)
+	}
+	return header, nil
+}
+
+// signalling returns the version bits of a chain whose blocks at heights signal
+// versionBits
+func signalling(versionBits uint32, heights ...uint64) map[uint64]uint32 {
+	res := map[uint64]uint32{}
+	for _, h := range heights {
+		res[h] = versionBits
+	}
+	return res
+}
+
+func TestThresholdStateTransitions(t *testing.T) {
+	// windows of 4 blocks: [1,4] [5,8] [9,12] [13,16]..., 3 signalling blocks
+	// lock a deployment in
+	const bit = 1
+	signal := uint32(vbTopBits | 1<<bit)
+	type check struct {
+		prevHeight uint64
+		want       ThresholdState
+	}
+	tests := []struct {
+		name        string
+		start       uint64
+		expire      uint64
+		versionBits map[uint64]uint32
+		checks      []check
+	}{
+		{
+			name:   "defined until the start height",
+			start:  12,
+			expire: 100,
+			checks: []check{{0, ThresholdDefined}, {3, ThresholdDefined}, {4, ThresholdDefined}, {8, ThresholdDefined}, {11, ThresholdDefined}, {12, ThresholdStarted}},
+		},
+		{
+			name:   "started without signals",
+			start:  4,
+			expire: 100,
+			checks: []check{{3, ThresholdDefined}, {4, ThresholdStarted}, {7, ThresholdStarted}, {8, ThresholdStarted}, {20, ThresholdStarted}},
+		},
+		{
+			name:        "locked in then active",
+			start:       4,
+			expire:      100,
+			versionBits: signalling(signal, 5, 6, 7, 8),
+			checks:      []check{{7, ThresholdStarted}, {8, ThresholdLockedIn}, {11, ThresholdLockedIn}, {12, ThresholdActive}, {30, ThresholdActive}},
+		},
+		{
+			name:        "locked in at the threshold",
+			start:       4,
+			expire:      100,
+			versionBits: signalling(signal, 5, 7, 8),
+			checks:      []check{{8, ThresholdLockedIn}, {12, ThresholdActive}},
+		},
+		{
+			name:        "started below the threshold",
+			start:       4,
+			expire:      100,
+			versionBits: signalling(signal, 5, 8, 9, 10, 11),
+			checks:      []check{{8, ThresholdStarted}, {12, ThresholdLockedIn}, {16, ThresholdActive}},
+		},
+		{
+			name:        "signals of the previous window are not counted",
+			start:       8,
+			expire:      100,
+			versionBits: signalling(signal, 5, 6, 7, 8),
+			checks:      []check{{8, ThresholdStarted}, {12, ThresholdStarted}},
+		},
+		{
+			name:        "other bits do not signal",
+			start:       4,
+			expire:      100,
+			versionBits: signalling(vbTopBits|1<<(bit+1), 5, 6, 7, 8),
+			checks:      []check{{8, ThresholdStarted}},
+		},
+		{
+			name:        "bits without the top bits do not signal",
+			start:       4,
+			expire:      100,
+			versionBits: signalling(1<<bit, 5, 6, 7, 8),
+			checks:      []check{{8, ThresholdStarted}},
+		},
+		{
+			name:   "failed at the expire height",
+			start:  4,
+			expire: 12,
+			checks: []check{{8, ThresholdStarted}, {11, ThresholdStarted}, {12, ThresholdFailed}, {20, ThresholdFailed}},
+		},
+		{
+			name:        "failed before signalling in the expire window",
+			start:       4,
+			expire:      12,
+			versionBits: signalling(signal, 9, 10, 11, 12),
+			checks:      []check{{12, ThresholdFailed}, {16, ThresholdFailed}},
+		},
+		{
+			name:   "failed when expiring before starting",
+			start:  4,
+			expire: 4,
+			checks: []check{{3, ThresholdDefined}, {4, ThresholdFailed}, {8, ThresholdFailed}},
+		},
+		{
+			name:        "active after the expire height once locked in",
+			start:       4,
+			expire:      9,
+			versionBits: signalling(signal, 5, 6, 7),
+			checks:      []check{{8, ThresholdLockedIn}, {12, ThresholdActive}, {16, ThresholdActive}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockchain := &BlockChain{config: Config{ChainParams: &Params{RuleChangeActivationThreshold: 3, VersionBitsWindow: 4}}}
+			checker := deploymentChecker{deployment: &ConsensusDeployment{Name: "test", BitNumber: bit, StartHeight: tt.start, ExpireHeight: tt.expire}, chain: blockchain}
+			headers := newTestHeaderSource(40, tt.versionBits)
+			shared := newThresholdCaches(1)
+			for _, c := range tt.checks {
+				// the states are the same computed from scratch or from the
+				// states cached at the previous checks
+				for _, cache := range []*thresholdStateCache{&newThresholdCaches(1).caches[0], &shared.caches[0]} {
+					state, err := calcThresholdState(c.prevHeight, headers, checker, cache)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if state != c.want {
+						t.Fatalf("state after height %d is %v, want %v", c.prevHeight, state, c.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestThresholdStateMissingBlock(t *testing.T) {
+	blockchain := &BlockChain{config: Config{ChainParams: &Params{RuleChangeActivationThreshold: 3, VersionBitsWindow: 4}}}
+	checker := deploymentChecker{deployment: &ConsensusDeployment{Name: "test", BitNumber: 1, StartHeight: 4, ExpireHeight: 100}, chain: blockchain}
+	headers := newTestHeaderSource(10, nil)
+	if _, err := calcThresholdState(12, headers, checker, &newThresholdCaches(1).caches[0]); err == nil {
+		t.Fatal("state computed without the block at the end of the window")
+	}
+}
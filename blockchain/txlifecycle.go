@@ -0,0 +1,59 @@
+package blockchain
+
+import (
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	zkp "github.com/incognitochain/incognito-chain/privacy/zeroknowledge"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/txtracker"
+)
+
+// trackShardBlockTxs records the inclusion of the txs of a shard block and
+// the delivery of the cross shard outputs it receives in the tx tracker
+func trackShardBlockTxs(shardBlock *ShardBlock) {
+	shardID := shardBlock.Header.ShardID
+	txHashes := []common.Hash{}
+	crossShards := [][]byte{}
+	for _, tx := range shardBlock.Body.Transactions {
+		txHashes = append(txHashes, *tx.Hash())
+		crossShards = append(crossShards, getTxCrossShards(tx, shardID))
+	}
+	txtracker.Default.Included(shardID, shardBlock.Header.Height, txHashes, crossShards)
+	for fromShard, crossTransactions := range shardBlock.Body.CrossTransactions {
+		for _, crossTransaction := range crossTransactions {
+			txtracker.Default.CrossShardDelivered(fromShard, crossTransaction.BlockHeight, shardID, shardBlock.Header.Height)
+		}
+	}
+}
+
+// getTxCrossShards returns the shards other than shardID receiving output
+// coins of a tx, like getCrossShardData
+func getTxCrossShards(tx metadata.Transaction, shardID byte) []byte {
+	shards := map[byte]bool{}
+	proofs := []*zkp.PaymentProof{}
+	if tx.GetProof() != nil {
+		proofs = append(proofs, tx.GetProof())
+	}
+	if tx.GetType() == common.TxCustomTokenPrivacyType {
+		customTokenPrivacyTx, ok := tx.(*transaction.TxCustomTokenPrivacy)
+		if ok && customTokenPrivacyTx.TxPrivacyTokenData.TxNormal.GetProof() != nil {
+			proofs = append(proofs, customTokenPrivacyTx.TxPrivacyTokenData.TxNormal.GetProof())
+		}
+	}
+	for _, proof := range proofs {
+		for _, outCoin := range proof.GetOutputCoins() {
+			receiverShardID := common.GetShardIDFromLastByte(outCoin.CoinDetails.GetPubKeyLastByte())
+			if receiverShardID != shardID {
+				shards[receiverShardID] = true
+			}
+		}
+	}
+	res := []byte{}
+	for receiverShardID := range shards {
+		res = append(res, receiverShardID)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
@@ -0,0 +1,194 @@
+package blockchain
+
+import (
+	"fmt"
+)
+
+const (
+	// vbTopBits defines the bits to set in the header version bits to
+	// signal that the version bits scheme is being used.
+	vbTopBits = 0x20000000
+
+	// vbTopMask is the bitmask to use to determine whether or not the
+	// version bits scheme is in use.
+	vbTopMask = 0xe0000000
+
+	// vbNumBits is the total number of bits available for use with the
+	// version bits scheme.
+	vbNumBits = 29
+)
+
+// deploymentChecker provides a thresholdConditionChecker which can be used to
+// test a specific deployment rule.
+type deploymentChecker struct {
+	deployment *ConsensusDeployment
+	chain      *BlockChain
+}
+
+// Ensure the deploymentChecker type implements the thresholdConditionChecker
+// interface.
+var _ thresholdConditionChecker = deploymentChecker{}
+
+// StartHeight returns the beacon height from which voting on a rule change
+// starts (at the next window).
+//
+// This implementation returns the value defined by the specific deployment the
+// checker is associated with.
+//
+// This is part of the thresholdConditionChecker interface implementation.
+func (c deploymentChecker) StartHeight() uint64 {
+	return c.deployment.StartHeight
+}
+
+// ExpireHeight returns the beacon height after which an attempted rule change
+// fails if it has not already been locked in or activated.
+//
+// This implementation returns the value defined by the specific deployment the
+// checker is associated with.
+//
+// This is part of the thresholdConditionChecker interface implementation.
+func (c deploymentChecker) ExpireHeight() uint64 {
+	return c.deployment.ExpireHeight
+}
+
+// RuleChangeActivationThreshold is the number of blocks for which the condition
+// must be true in order to lock in a rule change.
+//
+// This implementation returns the value defined by the chain params the checker
+// is associated with.
+//
+// This is part of the thresholdConditionChecker interface implementation.
+func (c deploymentChecker) RuleChangeActivationThreshold() uint64 {
+	return c.chain.config.ChainParams.RuleChangeActivationThreshold
+}
+
+// ConfirmationWindow is the number of beacon blocks in each threshold state
+// window.
+//
+// This implementation returns the value defined by the chain params the checker
+// is associated with.
+//
+// This is part of the thresholdConditionChecker interface implementation.
+func (c deploymentChecker) ConfirmationWindow() uint64 {
+	return c.chain.config.ChainParams.VersionBitsWindow
+}
+
+// Condition returns true when the specific bit defined by the deployment
+// associated with the checker is set.
+//
+// This is part of the thresholdConditionChecker interface implementation.
+func (c deploymentChecker) Condition(header *BeaconHeader) bool {
+	conditionMask := uint32(1) << c.deployment.BitNumber
+	return header.VersionBits&vbTopMask == vbTopBits && header.VersionBits&conditionMask != 0
+}
+
+// checkDeployments checks every deployment uses a distinct bit of the version bits scheme
+func checkDeployments(deployments []ConsensusDeployment) error {
+	usedBits := map[uint8]string{}
+	for _, deployment := range deployments {
+		if deployment.BitNumber >= vbNumBits {
+			return fmt.Errorf("deployment %v uses bit %d, only %d bits are available", deployment.Name, deployment.BitNumber, vbNumBits)
+		}
+		if name, ok := usedBits[deployment.BitNumber]; ok {
+			return fmt.Errorf("deployments %v and %v use the same bit %d", name, deployment.Name, deployment.BitNumber)
+		}
+		usedBits[deployment.BitNumber] = deployment.Name
+	}
+	return nil
+}
+
+// deploymentState returns the current rule change threshold state of the given
+// deployment for the block AFTER the given view.
+func (blockchain *BlockChain) deploymentState(prevView *BeaconBestState, deploymentID int) (ThresholdState, error) {
+	deployments := blockchain.config.ChainParams.Deployments
+	if deploymentID < 0 || deploymentID >= len(deployments) {
+		return ThresholdFailed, fmt.Errorf("deployment ID %d does not exist", deploymentID)
+	}
+	caches := blockchain.deploymentCaches
+	if caches == nil {
+		return ThresholdFailed, fmt.Errorf("deployment caches are not initialized")
+	}
+	checker := deploymentChecker{deployment: &deployments[deploymentID], chain: blockchain}
+	caches.lock.Lock()
+	defer caches.lock.Unlock()
+	return blockchain.thresholdState(prevView, checker, &caches.caches[deploymentID])
+}
+
+// DeploymentState returns the current rule change threshold state of the
+// deployment with the given name for the block AFTER the given view.
+func (blockchain *BlockChain) DeploymentState(prevView *BeaconBestState, name string) (ThresholdState, error) {
+	for deploymentID, deployment := range blockchain.config.ChainParams.Deployments {
+		if deployment.Name == name {
+			return blockchain.deploymentState(prevView, deploymentID)
+		}
+	}
+	return ThresholdFailed, fmt.Errorf("deployment %v does not exist", name)
+}
+
+// IsDeploymentActive returns whether the deployment with the given name is
+// active for the block AFTER the given view.
+func (blockchain *BlockChain) IsDeploymentActive(prevView *BeaconBestState, name string) (bool, error) {
+	state, err := blockchain.DeploymentState(prevView, name)
+	if err != nil {
+		return false, err
+	}
+	return state == ThresholdActive, nil
+}
+
+// calcNextVersionBits calculates the version bits a beacon producer signals in
+// the block after the given view: the bit of every known deployment which is
+// started or locked in. It returns 0 when nothing is signalled so the header
+// hash of the block is computed as before the version bits scheme.
+func (blockchain *BlockChain) calcNextVersionBits(prevView *BeaconBestState) (uint32, error) {
+	versionBits := uint32(vbTopBits)
+	signalled := false
+	for deploymentID, deployment := range blockchain.config.ChainParams.Deployments {
+		state, err := blockchain.deploymentState(prevView, deploymentID)
+		if err != nil {
+			return 0, err
+		}
+		if state == ThresholdStarted || state == ThresholdLockedIn {
+			versionBits |= uint32(1) << deployment.BitNumber
+			signalled = true
+		}
+	}
+	if !signalled {
+		return 0, nil
+	}
+	return versionBits, nil
+}
+
+// checkVersionBits warns about bits signalled in the header which do not belong
+// to any known deployment, this node may need to be upgraded, and logs the
+// state of the known deployments at the end of each window.
+func (blockchain *BlockChain) checkVersionBits(view *BeaconBestState, header *BeaconHeader) {
+	if header.VersionBits != 0 {
+		unknownBits := header.VersionBits &^ uint32(vbTopMask)
+		if header.VersionBits&vbTopMask != vbTopBits {
+			unknownBits = header.VersionBits
+		}
+		for _, deployment := range blockchain.config.ChainParams.Deployments {
+			unknownBits &^= uint32(1) << deployment.BitNumber
+		}
+		if unknownBits != 0 {
+			Logger.log.Warnf("Beacon block %v signals unknown version bits %#x, a new version may be required, please check the release notes", header.Height, unknownBits)
+		}
+	}
+	window := blockchain.config.ChainParams.VersionBitsWindow
+	if window == 0 || view.BeaconHeight%window != 0 {
+		return
+	}
+	for deploymentID, deployment := range blockchain.config.ChainParams.Deployments {
+		state, err := blockchain.deploymentState(view, deploymentID)
+		if err != nil {
+			Logger.log.Errorf("Get state of deployment %v failed, error %+v", deployment.Name, err)
+			continue
+		}
+		switch state {
+		case ThresholdLockedIn:
+			Logger.log.Infof("Deployment %v is locked in, it activates at beacon height %v", deployment.Name, view.BeaconHeight+window+1)
+		case ThresholdActive:
+			Logger.log.Infof("Deployment %v is active", deployment.Name)
+		}
+	}
+}
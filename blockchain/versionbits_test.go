@@ -0,0 +1,59 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+func TestBeaconHeaderVersionBitsHash(t *testing.T) {
+	newHeader := func(version int, versionBits uint32) BeaconHeader {
+		return BeaconHeader{
+			Version:               version,
+			Height:                10,
+			Epoch:                 1,
+			Round:                 1,
+			Timestamp:             1600000000,
+			PreviousBlockHash:     common.Hash{1},
+			InstructionHash:       common.Hash{2},
+			InstructionMerkleRoot: common.Hash{3},
+			Proposer:              "proposer",
+			ProposeTime:           1600000000,
+			VersionBits:           versionBits,
+		}
+	}
+	tests := []struct {
+		name        string
+		version     int
+		versionBits uint32
+		// hash of the header before the version bits were added, empty if it
+		// must differ
+		legacyHash string
+	}{
+		{"version 1 without signal", 1, 0, "75e4a1ca728832d47d3ad7a4903ec99d52c4b50ed3c62c0e0cfe1d927769475e"},
+		{"version 2 without signal", 2, 0, "fbca431d57d58dc69ca51e861aa82eb2a1c080cac44cb89d25e5b8394660e792"},
+		{"version 1 signalling", 1, vbTopBits | 1, ""},
+		{"version 2 signalling", 2, vbTopBits | 1, ""},
+		{"version 2 signalling another bit", 2, vbTopBits | 2, ""},
+	}
+	hashes := map[common.Hash]string{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := newHeader(tt.version, tt.versionBits)
+			hash := header.Hash()
+			if tt.legacyHash != "" && hash.String() != tt.legacyHash {
+				t.Fatalf("hash is %v, want the hash %v of the header without version bits", hash.String(), tt.legacyHash)
+			}
+			if tt.legacyHash == "" {
+				unsignalled := newHeader(tt.version, 0)
+				if hash == unsignalled.Hash() {
+					t.Fatal("version bits are not hashed")
+				}
+			}
+			if name, ok := hashes[hash]; ok {
+				t.Fatalf("same hash as %v", name)
+			}
+			hashes[hash] = tt.name
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"time"
+
+	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+)
+
+// directPeer is a node which other nodes can gossip with when no highway is
+// reachable
+type directPeer struct {
+	rpcclient.DirectPeer
+	lastPing time.Time
+}
+
+// AddOrUpdateDirectPeer saves a node announced by peerv2.ConnManager and
+// returns the other direct peers
+func (rpcServer *RpcServer) AddOrUpdateDirectPeer(p rpcclient.DirectPeer) []rpcclient.DirectPeer {
+	rpcServer.peersMtx.Lock()
+	defer rpcServer.peersMtx.Unlock()
+	others := []rpcclient.DirectPeer{}
+	for addr, known := range rpcServer.directPeers {
+		if addr != p.Libp2pAddr {
+			others = append(others, known.DirectPeer)
+		}
+	}
+	if p.Libp2pAddr != "" {
+		rpcServer.directPeers[p.Libp2pAddr] = &directPeer{DirectPeer: p, lastPing: time.Now().Local()}
+	}
+	return others
+}
+
+// removeStaleDirectPeers forgets the direct peers which did not announce
+// themselves for heartbeatTimeout seconds
+func (rpcServer *RpcServer) removeStaleDirectPeers(now time.Time, heartbeatTimeout int) {
+	rpcServer.peersMtx.Lock()
+	defer rpcServer.peersMtx.Unlock()
+	for addr, p := range rpcServer.directPeers {
+		if now.Sub(p.lastPing).Seconds() > float64(heartbeatTimeout) {
+			delete(rpcServer.directPeers, addr)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"fmt"
 
+	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
 	"github.com/incognitochain/incognito-chain/wire"
 )
 
@@ -40,3 +41,10 @@ func (s Handler) Ping(args *PingArgs, responseMessagePeers *[]wire.RawPeer) erro
 	fmt.Println("Response", *responseMessagePeers)
 	return nil
 }
+
+// AnnounceDirectPeer - handler func which receive a node reachable without
+// highway and response the other ones
+func (s Handler) AnnounceDirectPeer(args rpcclient.DirectPeer, responseDirectPeers *[]rpcclient.DirectPeer) error {
+	*responseDirectPeers = s.rpcServer.AddOrUpdateDirectPeer(args)
+	return nil
+}
@@ -31,10 +31,11 @@ type peer struct {
 
 // rpcServer provides a concurrent safe RPC server to a bootnode server.
 type RpcServer struct {
-	peers    map[string]*peer // list peers which are still pinging to bootnode continuously
-	peersMtx sync.Mutex
-	server   *rpc.Server
-	Config   RpcServerConfig // config for RPC server
+	peers       map[string]*peer       // list peers which are still pinging to bootnode continuously
+	directPeers map[string]*directPeer // list peers reachable without highway, by libp2p address
+	peersMtx    sync.Mutex
+	server      *rpc.Server
+	Config      RpcServerConfig // config for RPC server
 }
 
 type RpcServerConfig struct {
@@ -45,6 +46,7 @@ func (rpcServer *RpcServer) Init(config *RpcServerConfig) {
 	// get config and init list Peers
 	rpcServer.Config = *config
 	rpcServer.peers = make(map[string]*peer)
+	rpcServer.directPeers = make(map[string]*directPeer)
 	rpcServer.server = rpc.NewServer()
 	// start go routin hertbeat to check invalid peers
 	go rpcServer.PeerHeartBeat(heartbeatTimeout)
@@ -131,6 +133,7 @@ func (rpcServer *RpcServer) CombineID(rawAddress string, publicKey string) strin
 func (rpcServer *RpcServer) PeerHeartBeat(heartbeatTimeout int) {
 	for {
 		now := time.Now().Local()
+		rpcServer.removeStaleDirectPeers(now, heartbeatTimeout)
 		if len(rpcServer.peers) > 0 {
 		loop:
 			for publicKey, peer := range rpcServer.peers {
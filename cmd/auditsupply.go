@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/supplyaudit"
+)
+
+// auditSupply replays the beacon chain from fromHeight to toHeight and writes
+// the supply audit report in outDataDir, or prints it when outDataDir is empty
+func auditSupply(bc *blockchain.BlockChain, fromHeight, toHeight, rangeSize uint64, balanceFile, outDataDir, fileName string) error {
+	config := supplyaudit.Config{Source: supplyaudit.NewChainSource(bc), RangeSize: rangeSize}
+	if balanceFile != "" {
+		balances, err := readBalanceFile(balanceFile)
+		if err != nil {
+			return err
+		}
+		config.External = balances
+	}
+	report, err := supplyaudit.Audit(config, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	for _, r := range report.Ranges {
+		for _, d := range r.Discrepancies {
+			log.Printf("Beacon heights %v-%v: %v check of token %v (centralized %v) expected %v, actual %v",
+				r.FromHeight, r.ToHeight, d.Check, d.TokenID, d.IsCentralized, d.Expected, d.Actual)
+		}
+	}
+	log.Printf("Audited %v tokens from beacon height %v to %v, found %v discrepancies", len(report.Tokens), report.FromHeight, report.ToHeight, report.Discrepancies)
+
+	result, err := parseToJsonString(report)
+	if err != nil {
+		return err
+	}
+	if outDataDir == "" {
+		fmt.Println(string(result))
+		return nil
+	}
+	if fileName == "" {
+		fileName = fmt.Sprintf("supply-audit-%v-%v.json", report.FromHeight, report.ToHeight)
+	}
+	file := filepath.Join(outDataDir, fileName)
+	if err := ioutil.WriteFile(file, result, 0644); err != nil {
+		return err
+	}
+	log.Printf("Supply audit report written to %+v", file)
+	return nil
+}
+
+// readBalanceFile reads the external balances of the pTokens, e.g.
+// {"<pToken id>": 1000}
+func readBalanceFile(balanceFile string) (supplyaudit.Balances, error) {
+	data, err := ioutil.ReadFile(balanceFile)
+	if err != nil {
+		return nil, err
+	}
+	balances := supplyaudit.Balances{}
+	if err := json.Unmarshal(data, &balances); err != nil {
+		return nil, fmt.Errorf("invalid balance file %v: %v", balanceFile, err)
+	}
+	return balances, nil
+}
@@ -15,6 +15,14 @@ const (
 	defaultConfigFilename = "component.conf"
 	defaultDataDirname    = "data"
 	defaultLogDirname     = "logs"
+	defaultDBEngine       = "leveldb"
+
+	defaultDevnetNumBeacon          = 4
+	defaultDevnetNumShards          = 2
+	defaultDevnetShardCommitteeSize = 4
+	defaultDevnetEpoch              = 20
+	defaultDevnetBlockTime          = "10s"
+	defaultDevnetInitAmount         = 1000000 * 1e9 // 1M PRV
 )
 
 var (
@@ -39,15 +47,59 @@ type params struct {
 	ChainDataDir string `long:"chaindatadir" description:"Directory of Stored Blockchain Database"`
 	OutDataDir   string `long:"outdatadir" description:"Directory of Export Blockchain Data"`
 	FileName     string `long:"filename" description:"Filename of Backup Blockchin Data"`
+	DBEngine     string `long:"dbengine" description:"Storage engine of Stored Blockchain Database, default is leveldb"`
+	ToDBEngine   string `long:"todbengine" description:"Storage engine of migrated Blockchain Database in outdatadir"`
 	// wallet
 	WalletName        string `long:"wallet" description:"Wallet Database Name file, default is 'wallet'"`
 	WalletPassphrase  string `long:"walletpassphrase" description:"Wallet passphrase"`
 	WalletAccountName string `long:"walletaccountname" description:"Wallet account name"`
 	ShardID           int8   `long:"shardid" description:"Process Shard Chain with ShardID"`
 
+	// wallet passphrase
+	NewWalletPassphrase string `long:"newwalletpassphrase" description:"New wallet passphrase"`
+	KDF                 string `long:"kdf" description:"Key derivation function of the wallet file, argon2id (default) or scrypt"`
+	KDFTime             uint32 `long:"kdftime" description:"Number of passes of argon2id"`
+	KDFMemory           uint32 `long:"kdfmemory" description:"Memory of argon2id in KiB"`
+	KDFThreads          uint8  `long:"kdfthreads" description:"Number of threads of argon2id"`
+	ScryptN             int    `long:"scryptn" description:"CPU/memory cost of scrypt, a power of 2"`
+	ScryptR             int    `long:"scryptr" description:"Block size of scrypt"`
+	ScryptP             int    `long:"scryptp" description:"Parallelization of scrypt"`
+
+	// wallet accounts
+	Mnemonic       string `long:"mnemonic" description:"Mnemonic of the wallet to restore"`
+	Account        uint32 `long:"account" description:"Account number of the derivation path m/44'/587'/account'/index"`
+	NumAccounts    uint32 `long:"numaccounts" description:"Number of accounts derived when restoring a wallet"`
+	PaymentAddress string `long:"paymentaddress" description:"Payment address of a watch-only account"`
+	ReadonlyKey    string `long:"readonlykey" description:"Readonly key of a watch-only account"`
+
 	// pToken
 	PNetwork string `long:"pNetwork" description:"Bridge network"`
 	PToken   string `long:"pToken" description:"Bridge token"`
+
+	// devnet
+	NumBeacon            int    `long:"numbeacon" description:"Number of beacon validators of the devnet"`
+	NumShards            int    `long:"numshards" description:"Number of shards of the devnet"`
+	ShardCommitteeSize   int    `long:"shardcommitteesize" description:"Number of validators of each shard of the devnet"`
+	Epoch                uint64 `long:"epoch" description:"Number of beacon blocks of an epoch of the devnet"`
+	BlockTime            string `long:"blocktime" description:"Minimum interval between two blocks of the devnet, e.g. 10s"`
+	InitAmount           uint64 `long:"initamount" description:"Nano PRV allocated at genesis to every validator of the devnet"`
+	AllocFile            string `long:"allocfile" description:"Json file of additional PRV and token allocations of the devnet genesis"`
+	ForkSchedule         string `long:"forkschedule" description:"Json file overriding the fork schedule of the devnet"`
+	Seed                 string `long:"seed" description:"Seed of the devnet validator keys, random when empty"`
+	DiscoverPeersAddress string `long:"discoverpeersaddress" description:"Discover peers address written in the devnet node configs"`
+
+	// supply audit
+	FromHeight  uint64 `long:"fromheight" description:"First beacon height of the supply audit, default is the genesis block"`
+	ToHeight    uint64 `long:"toheight" description:"Last beacon height of the supply audit, default is the final beacon block"`
+	RangeSize   uint64 `long:"rangesize" description:"Number of beacon blocks between two comparisons of the supply audit"`
+	BalanceFile string `long:"balancefile" description:"Json file of the external vault balances by pToken id, in the unit of the pToken"`
+
+	// multisig
+	Threshold        int    `long:"threshold" description:"Number of participants needed to spend the coins of a multisig group"`
+	PaymentAddresses string `long:"paymentaddresses" description:"Comma separated payment addresses of the participants of a multisig key generation"`
+	MultiSigAccount  string `long:"multisigaccount" description:"Name of the multisig account of the wallet"`
+	PartialFiles     string `long:"partialfiles" description:"Comma separated files of the messages sent by the participants of a multisig session"`
+	OutFile          string `long:"outfile" description:"File written by a multisig command"`
 }
 
 // newConfigParser returns a new command line flags parser.
@@ -58,8 +110,16 @@ func newConfigParser(cfg *params, options flags.Options) *flags.Parser {
 
 func loadParams() (*params, error) {
 	cfg := params{
-		DataDir: defaultDataDir,
-		TestNet: false,
+		DataDir:  defaultDataDir,
+		TestNet:  false,
+		DBEngine: defaultDBEngine,
+
+		NumBeacon:          defaultDevnetNumBeacon,
+		NumShards:          defaultDevnetNumShards,
+		ShardCommitteeSize: defaultDevnetShardCommitteeSize,
+		Epoch:              defaultDevnetEpoch,
+		BlockTime:          defaultDevnetBlockTime,
+		InitAmount:         defaultDevnetInitAmount,
 	}
 
 	preParser := newConfigParser(&cfg, flags.HelpFlag)
@@ -5,9 +5,23 @@ const (
 	listWalletAccountCmd   = "listaccounts"
 	getWalletAccountCmd    = "getaccount"
 	createWalletAccountCmd = "createaccount"
+	changePassPhraseCmd    = "changepassphrase"
+	createPathAccountCmd   = "createpathaccount"
+	restoreWalletCmd       = "restorewallet"
+	importWatchOnlyCmd     = "importwatchonly"
 	getPrivacyTokenID      = "getprivacytokenid"
 	backupChain            = "backupchain"
 	restoreChain           = "restorechain"
+	migrateDB              = "migratedb"
+	devnetInitCmd          = "devnetinit"
+	auditSupplyCmd         = "auditsupply"
+
+	multiSigNewKeyGenCmd    = "multisignewkeygen"
+	multiSigKeyGenCmd       = "multisigkeygen"
+	multiSigMergeKeyGenCmd  = "multisigmergekeygen"
+	multiSigSignCmd         = "multisigsign"
+	multiSigMergeSignCmd    = "multisigmergesign"
+	multiSigListAccountsCmd = "multisiglistaccounts"
 )
 
 var CmdList = []string{
@@ -15,7 +29,20 @@ var CmdList = []string{
 	listWalletAccountCmd,
 	getWalletAccountCmd,
 	createWalletAccountCmd,
+	changePassPhraseCmd,
+	createPathAccountCmd,
+	restoreWalletCmd,
+	importWatchOnlyCmd,
 	getPrivacyTokenID,
 	backupChain,
 	restoreChain,
+	migrateDB,
+	devnetInitCmd,
+	auditSupplyCmd,
+	multiSigNewKeyGenCmd,
+	multiSigKeyGenCmd,
+	multiSigMergeKeyGenCmd,
+	multiSigSignCmd,
+	multiSigMergeSignCmd,
+	multiSigListAccountsCmd,
 }
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+const (
+	devnetName                 = "devnet"
+	devnetNet                  = 0x64
+	devnetChainParamsFilename  = "chainparams.json"
+	devnetNodeConfigDirname    = "nodes"
+	devnetDiscoverPeersAddress = "127.0.0.1:9330"
+	devnetBaseListenPort       = 9434
+	devnetBaseRPCPort          = 9334
+	devnetBaseWSPort           = 19334
+)
+
+// devnetParams are the options of devnetinit
+type devnetParams struct {
+	NumBeacon            int
+	NumShards            int
+	ShardCommitteeSize   int
+	Epoch                uint64
+	BlockTime            string
+	InitAmount           uint64
+	AllocFile            string
+	ForkSchedule         string
+	Seed                 string
+	DiscoverPeersAddress string
+	OutDir               string
+}
+
+// devnetAccount is a generated validator key, it is written to keylist.json in
+// the format read by the node at startup
+type devnetAccount struct {
+	PrivateKey         string
+	PaymentAddress     string
+	CommitteePublicKey string
+	ValidatorKey       string // base58 mining key, derived from the private key as the node does with --privatekey
+}
+
+// devnetAllocations lists the PRV and privacy tokens minted in the genesis shard block
+type devnetAllocations struct {
+	PRV    []devnetAllocation
+	Tokens []devnetTokenAllocation
+}
+
+type devnetAllocation struct {
+	PaymentAddress string
+	Amount         uint64
+}
+
+type devnetTokenAllocation struct {
+	TokenID   string // optional, derived from Symbol when empty
+	Name      string
+	Symbol    string
+	Receivers []devnetAllocation
+}
+
+// devnetInit generates the validator keys, the genesis and the chain params file of a
+// private network in params.OutDir, together with one node config file per validator
+func devnetInit(params devnetParams) error {
+	if params.NumBeacon <= 0 || params.NumShards <= 0 || params.NumShards > common.MaxShardNumber || params.ShardCommitteeSize <= 0 {
+		return fmt.Errorf("expect at least 1 beacon validator, 1 to %d shards and 1 validator per shard", common.MaxShardNumber)
+	}
+	if params.Epoch < 2 {
+		return fmt.Errorf("epoch must be at least 2 beacon blocks")
+	}
+	if params.OutDir == "" {
+		return fmt.Errorf("output directory is empty")
+	}
+	seed := []byte(params.Seed)
+	if len(seed) == 0 {
+		seed = make([]byte, 32)
+		if _, err := rand.Read(seed); err != nil {
+			return err
+		}
+	}
+	masterKey, err := wallet.NewMasterKey(seed)
+	if err != nil {
+		return err
+	}
+
+	// validator keys
+	childIdx := uint32(0)
+	newAccount := func() (*devnetAccount, *wallet.KeyWallet, error) {
+		child, err := masterKey.NewChildKey(childIdx)
+		if err != nil {
+			return nil, nil, err
+		}
+		childIdx++
+		account, err := newDevnetAccount(child)
+		return account, child, err
+	}
+	keySets := map[string]*wallet.KeyWallet{}
+	beacon := []devnetAccount{}
+	for i := 0; i < params.NumBeacon; i++ {
+		account, child, err := newAccount()
+		if err != nil {
+			return err
+		}
+		beacon = append(beacon, *account)
+		keySets[account.PaymentAddress] = child
+	}
+	shard := map[int][]devnetAccount{}
+	for shardID := 0; shardID < params.NumShards; shardID++ {
+		for i := 0; i < params.ShardCommitteeSize; i++ {
+			account, child, err := newAccount()
+			if err != nil {
+				return err
+			}
+			shard[shardID] = append(shard[shardID], *account)
+			keySets[account.PaymentAddress] = child
+		}
+	}
+
+	// genesis allocations, every validator receives InitAmount to pay fees and stake
+	allocations := devnetAllocations{}
+	if params.AllocFile != "" {
+		data, err := ioutil.ReadFile(params.AllocFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &allocations); err != nil {
+			return fmt.Errorf("invalid allocation file %v, %v", params.AllocFile, err)
+		}
+	}
+	if params.InitAmount > 0 {
+		validators := append([]devnetAccount{}, beacon...)
+		for shardID := 0; shardID < params.NumShards; shardID++ {
+			validators = append(validators, shard[shardID]...)
+		}
+		for _, validator := range validators {
+			allocations.PRV = append(allocations.PRV, devnetAllocation{PaymentAddress: validator.PaymentAddress, Amount: params.InitAmount})
+		}
+	}
+	initTxs, err := buildGenesisTxs(allocations, keySets, &masterKey.KeySet.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	schedule := forks.Schedule{
+		forks.BurnAddressV2:               {Height: 1},
+		forks.MinTxFeesOnTokenRequirement: {Height: 1},
+		forks.ReplaceStakingTx:            {Height: 1},
+		forks.StakingTxRoot:               {Height: 2},
+		forks.FixRandShardCommitment:      {Height: 1},
+		forks.ConsensusV2:                 {Epoch: 1},
+		forks.TimeLock:                    {Height: 1},
+	}
+	if params.ForkSchedule != "" {
+		overrides, err := forks.LoadSchedule(params.ForkSchedule)
+		if err != nil {
+			return err
+		}
+		if schedule, err = schedule.Override(overrides); err != nil {
+			return err
+		}
+	}
+
+	chainParamsFile := blockchain.ChainParamsFile{
+		Name:                   devnetName,
+		Net:                    devnetNet,
+		GenesisBlockTime:       time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+		ActiveShards:           params.NumShards,
+		Epoch:                  params.Epoch,
+		RandomTime:             params.Epoch / 2,
+		MinBeaconCommitteeSize: params.NumBeacon,
+		MaxBeaconCommitteeSize: params.NumBeacon,
+		MinShardCommitteeSize:  params.ShardCommitteeSize,
+		MaxShardCommitteeSize:  params.ShardCommitteeSize,
+		BeaconBlockInterval:    params.BlockTime,
+		ShardBlockInterval:     params.BlockTime,
+		Shard:                  map[int][]blockchain.GenesisValidator{},
+		InitialIncognito:       initTxs,
+		Forks:                  schedule,
+	}
+	for _, account := range beacon {
+		chainParamsFile.Beacon = append(chainParamsFile.Beacon, blockchain.GenesisValidator{PaymentAddress: account.PaymentAddress, CommitteePublicKey: account.CommitteePublicKey})
+	}
+	for shardID, accounts := range shard {
+		for _, account := range accounts {
+			chainParamsFile.Shard[shardID] = append(chainParamsFile.Shard[shardID], blockchain.GenesisValidator{PaymentAddress: account.PaymentAddress, CommitteePublicKey: account.CommitteePublicKey})
+		}
+	}
+	// fail now rather than when the nodes start
+	if _, err := chainParamsFile.ToParams(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(params.OutDir, devnetNodeConfigDirname), 0755); err != nil {
+		return err
+	}
+	chainParamsPath, err := filepath.Abs(filepath.Join(params.OutDir, devnetChainParamsFilename))
+	if err != nil {
+		return err
+	}
+	if err := writeJSONFile(chainParamsPath, chainParamsFile); err != nil {
+		return err
+	}
+	// the node reads the key lists in its working directory at startup
+	keyList := struct {
+		Shard  map[int][]devnetAccount
+		Beacon []devnetAccount
+	}{shard, beacon}
+	if err := writeJSONFile(filepath.Join(params.OutDir, "keylist.json"), keyList); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(params.OutDir, "keylist-v2.json"), []struct{}{}); err != nil {
+		return err
+	}
+
+	discoverPeersAddress := params.DiscoverPeersAddress
+	if discoverPeersAddress == "" {
+		discoverPeersAddress = devnetDiscoverPeersAddress
+	}
+	nodeIdx := 0
+	writeNodeConfig := func(name string, account devnetAccount) error {
+		options := [][2]string{
+			{"testnet", "1"},
+			{"chainparams", chainParamsPath},
+			{"privatekey", account.PrivateKey},
+			{"nodemode", "auto"},
+			{"datadir", filepath.Join("data", name)},
+			{"logdir", filepath.Join("logs", name)},
+			{"listen", fmt.Sprintf("0.0.0.0:%d", devnetBaseListenPort+nodeIdx)},
+			{"externaladdress", fmt.Sprintf("127.0.0.1:%d", devnetBaseListenPort+nodeIdx)},
+			{"discoverpeersaddress", discoverPeersAddress},
+			{"norpcauth", "1"},
+			{"rpclisten", fmt.Sprintf("0.0.0.0:%d", devnetBaseRPCPort+nodeIdx)},
+			{"rpcwslisten", fmt.Sprintf("0.0.0.0:%d", devnetBaseWSPort+nodeIdx)},
+		}
+		nodeIdx++
+		content := "[Application Options]\n"
+		for _, option := range options {
+			content += fmt.Sprintf("%s=%s\n", option[0], option[1])
+		}
+		return ioutil.WriteFile(filepath.Join(params.OutDir, devnetNodeConfigDirname, name+".conf"), []byte(content), 0600)
+	}
+	for i, account := range beacon {
+		if err := writeNodeConfig(fmt.Sprintf("beacon-%d", i), account); err != nil {
+			return err
+		}
+	}
+	for shardID := 0; shardID < params.NumShards; shardID++ {
+		for i, account := range shard[shardID] {
+			if err := writeNodeConfig(fmt.Sprintf("shard%d-%d", shardID, i), account); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newDevnetAccount(key *wallet.KeyWallet) (*devnetAccount, error) {
+	validatorKey := common.HashB(common.HashB(key.KeySet.PrivateKey))
+	committeeKey, err := incognitokey.NewCommitteeKeyFromSeed(validatorKey, key.KeySet.PaymentAddress.Pk)
+	if err != nil {
+		return nil, err
+	}
+	committeeKeyStr, err := incognitokey.CommitteeKeyListToString([]incognitokey.CommitteePublicKey{committeeKey})
+	if err != nil {
+		return nil, err
+	}
+	return &devnetAccount{
+		PrivateKey:         key.Base58CheckSerialize(wallet.PriKeyType),
+		PaymentAddress:     key.Base58CheckSerialize(wallet.PaymentAddressType),
+		CommitteePublicKey: committeeKeyStr[0],
+		ValidatorKey:       base58.Base58Check{}.Encode(validatorKey, 0x0),
+	}, nil
+}
+
+// buildGenesisTxs creates the serialized init txs of the allocations. A tx is signed by
+// the private key of its receiver when it is one of the generated keys, by
+// genesisKey otherwise: txs of the genesis block are never validated.
+func buildGenesisTxs(allocations devnetAllocations, keySets map[string]*wallet.KeyWallet, genesisKey *privacy.PrivateKey) ([]string, error) {
+	// the transaction package logs while building txs, chainctl has no log backend
+	transaction.Logger.Init(common.NewBackend(nil).Logger("Transaction log ", true))
+	db, err := incdb.Open("memdb")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	stateDB, err := statedb.NewWithPrefixTrie(common.EmptyRoot, statedb.NewDatabaseAccessWarper(db))
+	if err != nil {
+		return nil, err
+	}
+	receiverOf := func(allocation devnetAllocation) (*privacy.PaymentAddress, *privacy.PrivateKey, error) {
+		key, err := wallet.Base58CheckDeserialize(allocation.PaymentAddress)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid payment address %v, %v", allocation.PaymentAddress, err)
+		}
+		if allocation.Amount == 0 {
+			return nil, nil, fmt.Errorf("allocation of %v is 0", allocation.PaymentAddress)
+		}
+		signer := genesisKey
+		if keySet, ok := keySets[allocation.PaymentAddress]; ok {
+			signer = &keySet.KeySet.PrivateKey
+		}
+		return &key.KeySet.PaymentAddress, signer, nil
+	}
+
+	initTxs := []string{}
+	for _, allocation := range allocations.PRV {
+		receiver, signer, err := receiverOf(allocation)
+		if err != nil {
+			return nil, err
+		}
+		tx := transaction.Tx{}
+		if err := tx.InitTxSalary(allocation.Amount, receiver, signer, stateDB, nil); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(tx)
+		if err != nil {
+			return nil, err
+		}
+		initTxs = append(initTxs, string(data))
+	}
+	for _, token := range allocations.Tokens {
+		if token.Symbol == "" {
+			return nil, fmt.Errorf("token %v has no symbol", token.Name)
+		}
+		tokenID := common.HashH([]byte(strings.ToUpper(token.Symbol)))
+		if token.TokenID != "" {
+			id, err := common.Hash{}.NewHashFromStr(token.TokenID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid token id %v, %v", token.TokenID, err)
+			}
+			tokenID = *id
+		}
+		for _, allocation := range token.Receivers {
+			receiver, signer, err := receiverOf(allocation)
+			if err != nil {
+				return nil, err
+			}
+			// the token id is given so every receiver shares the same token
+			tokenParams := &transaction.CustomTokenPrivacyParamTx{
+				PropertyID:     tokenID.String(),
+				PropertyName:   token.Name,
+				PropertySymbol: token.Symbol,
+				Amount:         allocation.Amount,
+				TokenTxType:    transaction.CustomTokenInit,
+				Receiver:       []*privacy.PaymentInfo{{PaymentAddress: *receiver, Amount: allocation.Amount}},
+				TokenInput:     []*privacy.InputCoin{},
+				Mintable:       true,
+			}
+			shardID := common.GetShardIDFromLastByte(receiver.Pk[len(receiver.Pk)-1])
+			tx := transaction.TxCustomTokenPrivacy{}
+			err = tx.Init(transaction.NewTxPrivacyTokenInitParams(signer,
+				[]*privacy.PaymentInfo{},
+				nil,
+				0,
+				tokenParams,
+				stateDB,
+				nil,
+				false,
+				false,
+				shardID,
+				nil,
+				stateDB))
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(tx)
+			if err != nil {
+				return nil, err
+			}
+			initTxs = append(initTxs, string(data))
+		}
+	}
+	return initTxs, nil
+}
+
+func writeJSONFile(path string, data interface{}) error {
+	content, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevnetInit(t *testing.T) {
+	outDir, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(outDir)
+
+	receiver := "12S5Lrs1XeQLbqN4ySyKtjAjd2d7sBP2tjFijzmp6avrrkQCNFMpkXm3FPzj2Wcu2ZNqJEmh9JriVuRErVwhuQnLmWSaggobEWsBEci"
+	allocations := devnetAllocations{
+		PRV: []devnetAllocation{{PaymentAddress: receiver, Amount: 1000}},
+		Tokens: []devnetTokenAllocation{
+			{Name: "Tether", Symbol: "USDT", Receivers: []devnetAllocation{{PaymentAddress: receiver, Amount: 500}}},
+		},
+	}
+	allocFile := filepath.Join(outDir, "alloc.json")
+	assert.Nil(t, writeJSONFile(allocFile, allocations))
+	forkFile := filepath.Join(outDir, "forks.json")
+	assert.Nil(t, ioutil.WriteFile(forkFile, []byte(`{"ConsensusV2": {"Epoch": 3}}`), 0644))
+
+	err = devnetInit(devnetParams{
+		NumBeacon:          2,
+		NumShards:          2,
+		ShardCommitteeSize: 3,
+		Epoch:              10,
+		BlockTime:          "5s",
+		InitAmount:         100,
+		AllocFile:          allocFile,
+		ForkSchedule:       forkFile,
+		Seed:               "devnet",
+		OutDir:             outDir,
+	})
+	assert.Nil(t, err)
+
+	params, err := blockchain.LoadChainParams(filepath.Join(outDir, devnetChainParamsFilename))
+	assert.Nil(t, err)
+	assert.Equal(t, devnetName, params.Name)
+	assert.Equal(t, 2, params.ActiveShards)
+	assert.Equal(t, uint64(10), params.Epoch)
+	assert.Equal(t, 3, params.MinShardCommitteeSize)
+	assert.Equal(t, 2, len(params.GenesisParams.PreSelectBeaconNodeSerializedPubkey))
+	assert.Equal(t, 6, len(params.GenesisParams.PreSelectShardNodeSerializedPubkey))
+	assert.Equal(t, uint64(3), params.Forks[forks.ConsensusV2].Epoch)
+	assert.True(t, params.IsForkActive(forks.BurnAddressV2, 1))
+
+	// 8 validators and 1 extra receiver of PRV, 1 receiver of token
+	txs := params.GenesisShardBlock.Body.Transactions
+	assert.Equal(t, 10, len(txs))
+	tokenTx, ok := txs[9].(*transaction.TxCustomTokenPrivacy)
+	assert.True(t, ok)
+	assert.Equal(t, common.HashH([]byte("USDT")), tokenTx.TxPrivacyTokenData.PropertyID)
+	assert.Equal(t, uint64(500), tokenTx.TxPrivacyTokenData.Amount)
+	assert.Equal(t, uint64(1000), txs[0].(*transaction.Tx).Proof.GetOutputCoins()[0].CoinDetails.GetValue())
+
+	keyList := struct {
+		Shard  map[int][]devnetAccount
+		Beacon []devnetAccount
+	}{}
+	data, err := ioutil.ReadFile(filepath.Join(outDir, "keylist.json"))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(data, &keyList))
+	assert.Equal(t, params.GenesisParams.PreSelectBeaconNodeSerializedPubkey[1], keyList.Beacon[1].CommitteePublicKey)
+	assert.Equal(t, params.GenesisParams.PreSelectShardNodeSerializedPubkey[3], keyList.Shard[1][0].CommitteePublicKey)
+
+	configs, err := ioutil.ReadDir(filepath.Join(outDir, devnetNodeConfigDirname))
+	assert.Nil(t, err)
+	assert.Equal(t, 8, len(configs))
+	config, err := ioutil.ReadFile(filepath.Join(outDir, devnetNodeConfigDirname, "shard1-0.conf"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(config), "privatekey="+keyList.Shard[1][0].PrivateKey)
+	assert.Contains(t, string(config), "rpclisten=0.0.0.0:9339")
+
+	// the same seed gives the same keys
+	otherDir := filepath.Join(outDir, "other")
+	assert.Nil(t, devnetInit(devnetParams{NumBeacon: 2, NumShards: 2, ShardCommitteeSize: 3, Epoch: 10, BlockTime: "5s", Seed: "devnet", OutDir: otherDir}))
+	otherParams, err := blockchain.LoadChainParams(filepath.Join(otherDir, devnetChainParamsFilename))
+	assert.Nil(t, err)
+	assert.Equal(t, params.GenesisParams.PreSelectShardNodeSerializedPubkey, otherParams.GenesisParams.PreSelectShardNodeSerializedPubkey)
+	assert.Equal(t, 0, len(otherParams.GenesisShardBlock.Body.Transactions))
+
+	assert.NotNil(t, devnetInit(devnetParams{NumBeacon: 2, NumShards: 9, ShardCommitteeSize: 3, Epoch: 10, BlockTime: "5s", OutDir: otherDir}))
+	assert.NotNil(t, devnetInit(devnetParams{NumBeacon: 2, NumShards: 2, ShardCommitteeSize: 3, Epoch: 10, BlockTime: "soon", OutDir: otherDir}))
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+)
+
+// viewRootsHash holds the state root hashes of a beacon or shard view as stored by BackupBeaconViews/BackupShardViews
+type viewRootsHash struct {
+	BestBlockHash              common.Hash
+	ConsensusStateDBRootHash   common.Hash
+	TransactionStateDBRootHash common.Hash
+	FeatureStateDBRootHash     common.Hash
+	RewardStateDBRootHash      common.Hash
+	SlashStateDBRootHash       common.Hash
+}
+
+type migrateStats struct {
+	Keys     int
+	Bytes    int
+	Duration time.Duration
+}
+
+func (stats migrateStats) String() string {
+	seconds := stats.Duration.Seconds()
+	if seconds == 0 {
+		seconds = 1
+	}
+	return fmt.Sprintf("%d keys, %.2f MB in %v (%.0f keys/s, %.2f MB/s)",
+		stats.Keys, float64(stats.Bytes)/1024/1024, stats.Duration.Round(time.Millisecond),
+		float64(stats.Keys)/seconds, float64(stats.Bytes)/1024/1024/seconds)
+}
+
+// migrateDatabase copies beacon and shard databases in fromDir (opened with fromEngine) to toDir (opened with toEngine),
+// then checks every state trie referenced by the stored views can be fully read from the new database
+func migrateDatabase(fromEngine string, fromDir string, toEngine string, toDir string) error {
+	if fromEngine == toEngine && filepath.Clean(fromDir) == filepath.Clean(toDir) {
+		return fmt.Errorf("source and target database are the same")
+	}
+	total := migrateStats{}
+	for chainID := common.BeaconChainDataBaseID; chainID < common.MaxShardNumber; chainID++ {
+		dirName := common.BeaconChainDatabaseDirectory
+		if chainID != common.BeaconChainDataBaseID {
+			dirName = common.ShardChainDatabaseDirectory + strconv.Itoa(chainID)
+		}
+		fromPath := filepath.Join(fromDir, dirName)
+		if _, err := os.Stat(fromPath); os.IsNotExist(err) {
+			log.Printf("Skip %+v, no database at %+v", dirName, fromPath)
+			continue
+		}
+		stats, err := migrateChainDatabase(chainID, fromEngine, fromPath, toEngine, filepath.Join(toDir, dirName))
+		if err != nil {
+			return fmt.Errorf("migrate %+v failed, error %+v", dirName, err)
+		}
+		log.Printf("Migrate %+v: %+v", dirName, stats)
+		total.Keys += stats.Keys
+		total.Bytes += stats.Bytes
+		total.Duration += stats.Duration
+	}
+	log.Printf("Migrate from %+v to %+v successfully: %+v", fromEngine, toEngine, total)
+	return nil
+}
+
+func migrateChainDatabase(chainID int, fromEngine string, fromPath string, toEngine string, toPath string) (*migrateStats, error) {
+	src, err := incdb.Open(fromEngine, fromPath)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	dst, err := incdb.Open(toEngine, toPath)
+	if err != nil {
+		return nil, err
+	}
+	defer dst.Close()
+	stats, err := copyDatabase(src, dst)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyStateRootsHash(chainID, src, dst); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// copyDatabase writes every key of src to dst in batches of incdb.IdealBatchSize
+func copyDatabase(src incdb.Database, dst incdb.Database) (*migrateStats, error) {
+	start := time.Now()
+	stats := &migrateStats{}
+	iter := src.NewIterator()
+	defer iter.Release()
+	batch := dst.NewBatch()
+	for iter.Next() {
+		if err := batch.Put(iter.Key(), iter.Value()); err != nil {
+			return nil, err
+		}
+		stats.Keys++
+		stats.Bytes += len(iter.Key()) + len(iter.Value())
+		if batch.ValueSize() >= incdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
+
+// verifyStateRootsHash walks every state trie of the stored views in both databases and compares them node by node,
+// a node missing or different in dst means the migration is broken
+func verifyStateRootsHash(chainID int, src incdb.Database, dst incdb.Database) error {
+	var viewsBytes []byte
+	var err error
+	if chainID == common.BeaconChainDataBaseID {
+		viewsBytes, err = rawdbv2.GetBeaconViews(dst)
+	} else {
+		viewsBytes, err = rawdbv2.GetShardBestState(dst, byte(chainID))
+	}
+	if err != nil {
+		// chain has never stored its views, there is no state to verify
+		log.Printf("No stored views for chain %+v, skip state verification", chainID)
+		return nil
+	}
+	views := []*viewRootsHash{}
+	if err := json.Unmarshal(viewsBytes, &views); err != nil {
+		return err
+	}
+	for _, view := range views {
+		for _, root := range []common.Hash{
+			view.ConsensusStateDBRootHash,
+			view.TransactionStateDBRootHash,
+			view.FeatureStateDBRootHash,
+			view.RewardStateDBRootHash,
+			view.SlashStateDBRootHash,
+		} {
+			if err := compareStateTrie(root, src, dst); err != nil {
+				return fmt.Errorf("view %+v state root %+v, error %+v", view.BestBlockHash.String(), root.String(), err)
+			}
+		}
+	}
+	log.Printf("Verify state root hash of %+v views of chain %+v successfully", len(views), chainID)
+	return nil
+}
+
+func compareStateTrie(root common.Hash, src incdb.Database, dst incdb.Database) error {
+	srcTrie, err := statedb.NewDatabaseAccessWarper(src).OpenPrefixTrie(root)
+	if err != nil {
+		return err
+	}
+	dstTrie, err := statedb.NewDatabaseAccessWarper(dst).OpenPrefixTrie(root)
+	if err != nil {
+		return err
+	}
+	srcIter, dstIter := srcTrie.NodeIterator(nil), dstTrie.NodeIterator(nil)
+	for srcIter.Next(true) {
+		if !dstIter.Next(true) {
+			if dstIter.Error() != nil {
+				return dstIter.Error()
+			}
+			return fmt.Errorf("node %+v is missing", srcIter.Hash().String())
+		}
+		if srcIter.Hash() != dstIter.Hash() || srcIter.Leaf() != dstIter.Leaf() {
+			return fmt.Errorf("node %+v differs from %+v", srcIter.Hash().String(), dstIter.Hash().String())
+		}
+		if srcIter.Leaf() && !bytes.Equal(srcIter.LeafBlob(), dstIter.LeafBlob()) {
+			return fmt.Errorf("leaf %+v differs", srcIter.Hash().String())
+		}
+	}
+	if srcIter.Error() != nil {
+		return srcIter.Error()
+	}
+	if dstIter.Next(true) {
+		return fmt.Errorf("node %+v does not exist in source", dstIter.Hash().String())
+	}
+	return dstIter.Error()
+}
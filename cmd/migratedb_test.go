@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateDatabase(t *testing.T) {
+	fromDir, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(fromDir)
+	toDir, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(toDir)
+
+	// beacon database with one committed state trie referenced by the stored views
+	db, err := incdb.Open("leveldb", filepath.Join(fromDir, common.BeaconChainDatabaseDirectory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateDB, err := statedb.NewWithPrefixTrie(common.EmptyRoot, statedb.NewDatabaseAccessWarper(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		key := common.HashH([]byte{byte(i)})
+		assert.Equal(t, nil, stateDB.SetStateObject(statedb.TestObjectType, key, key[:]))
+	}
+	root, err := stateDB.Commit(true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, stateDB.Database().TrieDB().Commit(root, false))
+	views, _ := json.Marshal([]*viewRootsHash{{ConsensusStateDBRootHash: root}})
+	assert.Equal(t, nil, rawdbv2.StoreBeaconViews(db, views))
+	assert.Equal(t, nil, db.Close())
+
+	err = migrateDatabase("leveldb", fromDir, "badgerdb", toDir)
+	assert.Equal(t, nil, err)
+
+	migrated, err := incdb.Open("badgerdb", filepath.Join(toDir, common.BeaconChainDatabaseDirectory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer migrated.Close()
+	migratedStateDB, err := statedb.NewWithPrefixTrie(root, statedb.NewDatabaseAccessWarper(migrated))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, root, migratedStateDB.IntermediateRoot(true))
+
+	// a missing trie node must fail the verification
+	empty, err := incdb.Open("memdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, nil, compareStateTrie(root, migrated, empty))
+	assert.Equal(t, nil, compareStateTrie(root, migrated, migrated))
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+// splitList splits a comma separated flag
+func splitList(list string) []string {
+	result := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// multiSigNewKeyGen writes the session of a threshold-of-n group key between
+// the owners of paymentAddresses
+func multiSigNewKeyGen(threshold int, paymentAddresses []string, outFile string) error {
+	transmissionKeys := make([][]byte, len(paymentAddresses))
+	for i, paymentAddress := range paymentAddresses {
+		key, err := wallet.Base58CheckDeserialize(paymentAddress)
+		if err != nil {
+			return err
+		}
+		transmissionKeys[i] = key.KeySet.PaymentAddress.Tk
+	}
+	session, err := multisig.NewKeyGenSession(threshold, transmissionKeys)
+	if err != nil {
+		return err
+	}
+	if err := wallet.WriteExchangeFile(outFile, session); err != nil {
+		return err
+	}
+	log.Printf("Key generation %s of %d of %d participants written to %s", session.ID, threshold, len(transmissionKeys), outFile)
+	return nil
+}
+
+// multiSigKeyGen answers the round 1 of the key generation of sessionFile with
+// the account accountName, once the session is complete it stores the share
+// of the wallet as the multisig account multiSigAccountName
+func multiSigKeyGen(sessionFile, accountName, multiSigAccountName, outFile string) error {
+	walletObj, err := loadWallet()
+	if err != nil {
+		return err
+	}
+	session := new(multisig.KeyGenSession)
+	if err := wallet.ReadExchangeFile(sessionFile, session); err != nil {
+		return err
+	}
+	if session.IsComplete() {
+		account, err := walletObj.FinishMultiSigKeyGen(session, multiSigAccountName, cfg.WalletPassphrase)
+		if err != nil {
+			return err
+		}
+		log.Printf("Multisig account '%s' created, participant %d of a %d of %d group", account.Name, account.Share.Index, account.Share.Threshold, len(account.Share.PublicShares))
+		log.Printf("Payment address: %s", account.PaymentAddress())
+		log.Printf("Readonly key: %s", account.ReadonlyKey())
+		return nil
+	}
+	if outFile == "" {
+		return errors.New("expect outfile")
+	}
+	round1, err := walletObj.StartMultiSigKeyGen(session, accountName, cfg.WalletPassphrase)
+	if err != nil {
+		return err
+	}
+	if err := wallet.WriteExchangeFile(outFile, round1); err != nil {
+		return err
+	}
+	log.Printf("Round 1 message of participant %d written to %s", round1.Index, outFile)
+	return nil
+}
+
+// multiSigMergeKeyGen adds the round 1 messages of partialFiles to the key
+// generation of sessionFile and writes it to outFile
+func multiSigMergeKeyGen(sessionFile string, partialFiles []string, outFile string) error {
+	session := new(multisig.KeyGenSession)
+	if err := wallet.ReadExchangeFile(sessionFile, session); err != nil {
+		return err
+	}
+	for _, partialFile := range partialFiles {
+		round1 := new(multisig.KeyGenRound1)
+		if err := wallet.ReadExchangeFile(partialFile, round1); err != nil {
+			return err
+		}
+		if err := session.AddRound1(round1); err != nil {
+			return fmt.Errorf("%s: %v", partialFile, err)
+		}
+	}
+	if err := wallet.WriteExchangeFile(outFile, session); err != nil {
+		return err
+	}
+	if !session.IsComplete() {
+		log.Printf("Key generation %s written to %s, waiting for more participants", session.ID, outFile)
+		return nil
+	}
+	group, err := session.GroupKey()
+	if err != nil {
+		return err
+	}
+	key := wallet.KeyWallet{}
+	key.KeySet.PaymentAddress = group.PaymentAddress()
+	log.Printf("Key generation %s is complete and written to %s, every participant runs multisigkeygen on it", session.ID, outFile)
+	log.Printf("Payment address: %s", key.Base58CheckSerialize(wallet.PaymentAddressType))
+	return nil
+}
+
+// multiSigSign checks the multisig tx of txFile, prints what it transfers and
+// writes the message of the wallet for the current round to outFile
+func multiSigSign(txFile, multiSigAccountName, outFile string) error {
+	walletObj, err := loadWallet()
+	if err != nil {
+		return err
+	}
+	account, err := walletObj.GetMultiSigAccount(multiSigAccountName)
+	if err != nil {
+		return err
+	}
+	multiSigTx := new(transaction.MultiSigTx)
+	if err := wallet.ReadExchangeFile(txFile, multiSigTx); err != nil {
+		return err
+	}
+	logMultiSigTx(multiSigTx)
+	state := walletObj.MultiSigSignerState(multiSigTx.Session.ID)
+	msg, err := multiSigTx.Sign(&account.Share, state)
+	if err != nil {
+		return err
+	}
+	if err := walletObj.SaveMultiSigSignerState(state, cfg.WalletPassphrase); err != nil {
+		return err
+	}
+	if err := wallet.WriteExchangeFile(outFile, msg); err != nil {
+		return err
+	}
+	log.Printf("Round %d message of participant %d written to %s", msg.Round, msg.Index, outFile)
+	return nil
+}
+
+// logMultiSigTx prints the transfers of a multisig tx for its signers to
+// review, the change back to the group is marked
+func logMultiSigTx(multiSigTx *transaction.MultiSigTx) {
+	if multiSigTx.Session == nil {
+		return
+	}
+	tx := multiSigTx.Tx
+	tokenID := common.PRVCoinID.String()
+	if multiSigTx.TokenTx != nil {
+		tx = &multiSigTx.TokenTx.TxPrivacyTokenData.TxNormal
+		tokenID = multiSigTx.TokenTx.TxPrivacyTokenData.PropertyID.String()
+		log.Printf("Fee: %d nano PRV", multiSigTx.TokenTx.Tx.Fee)
+	}
+	if tx == nil || tx.Proof == nil {
+		return
+	}
+	log.Printf("Session %s at round %d, token %s, fee %d", multiSigTx.Session.ID, multiSigTx.Session.Round(), tokenID, tx.Fee)
+	for _, coin := range tx.Proof.GetOutputCoins() {
+		publicKey := coin.CoinDetails.GetPublicKey().ToBytesS()
+		receiver := base58.Base58Check{}.Encode(publicKey, common.ZeroByte)
+		if string(publicKey) == string(multiSigTx.Session.Group.PublicKey) {
+			receiver += " (change)"
+		}
+		log.Printf("Transfer %d to public key %s", coin.CoinDetails.GetValue(), receiver)
+	}
+}
+
+// multiSigMergeSign adds the messages of partialFiles to the multisig tx of
+// txFile and writes it to outFile, it prints the signed tx once complete
+func multiSigMergeSign(txFile string, partialFiles []string, outFile string) error {
+	multiSigTx := new(transaction.MultiSigTx)
+	if err := wallet.ReadExchangeFile(txFile, multiSigTx); err != nil {
+		return err
+	}
+	for _, partialFile := range partialFiles {
+		msg := new(multisig.SigningMessage)
+		if err := wallet.ReadExchangeFile(partialFile, msg); err != nil {
+			return err
+		}
+		if err := multiSigTx.AddMessage(msg); err != nil {
+			return fmt.Errorf("%s: %v", partialFile, err)
+		}
+	}
+	if err := wallet.WriteExchangeFile(outFile, multiSigTx); err != nil {
+		return err
+	}
+	if !multiSigTx.Session.IsComplete() {
+		log.Printf("Session %s written to %s, signers answer round %d", multiSigTx.Session.ID, outFile, multiSigTx.Session.Round())
+		return nil
+	}
+	tx, err := multiSigTx.SignedTx()
+	if err != nil {
+		return err
+	}
+	txBytes, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	spent := make([]string, len(multiSigTx.Session.SNDerivators))
+	for i, snd := range multiSigTx.Session.SNDerivators {
+		spent[i] = base58.Base58Check{}.Encode(snd, common.ZeroByte)
+	}
+	log.Printf("Tx %s is signed, send it with %s", tx.Hash().String(), map[bool]string{true: "sendrawprivacycustomtokentransaction", false: "sendtransaction"}[multiSigTx.TokenTx != nil])
+	log.Printf("SNDerivators of the spent coins: %s", strings.Join(spent, ","))
+	fmt.Println(base58.Base58Check{}.Encode(txBytes, common.ZeroByte))
+	return nil
+}
+
+// multiSigListAccounts returns the multisig accounts of the wallet with the
+// group key the createmultisigtransaction RPC expects
+func multiSigListAccounts() (interface{}, error) {
+	walletObj, err := loadWallet()
+	if err != nil {
+		return nil, err
+	}
+	result := []map[string]interface{}{}
+	for _, account := range walletObj.ListMultiSigAccounts() {
+		result = append(result, map[string]interface{}{
+			"Name":           account.Name,
+			"Index":          account.Share.Index,
+			"PaymentAddress": account.PaymentAddress(),
+			"ReadonlyKey":    account.ReadonlyKey(),
+			"GroupKey":       account.Share.GroupKey,
+		})
+	}
+	return result, nil
+}
@@ -124,13 +124,73 @@ func processCmd() {
 			}
 			log.Println(string(result))
 		}
+	case changePassPhraseCmd:
+		{
+			if cfg.WalletPassphrase == "" || cfg.WalletName == "" || cfg.NewWalletPassphrase == "" {
+				log.Println("Wrong param")
+				return
+			}
+			params, err := changePassPhrase(cfg.NewWalletPassphrase)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			params.Salt = nil
+			result, err := parseToJsonString(params)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			log.Printf("Wallet %s encrypted with the new passphrase and the key derivation %s", cfg.WalletName, string(result))
+		}
+	case createPathAccountCmd:
+		{
+			if cfg.WalletPassphrase == "" || cfg.WalletName == "" {
+				log.Println("Wrong param")
+				return
+			}
+			var shardID *byte
+			if cfg.ShardID > -1 {
+				temp := byte(cfg.ShardID)
+				shardID = &temp
+			}
+			err := createPathAccount(cfg.WalletAccountName, cfg.Account, shardID)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case restoreWalletCmd:
+		{
+			if cfg.WalletPassphrase == "" || cfg.WalletName == "" || cfg.Mnemonic == "" {
+				log.Println("Wrong param")
+				return
+			}
+			err := restoreWallet(cfg.Mnemonic, cfg.NumAccounts)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case importWatchOnlyCmd:
+		{
+			if cfg.WalletPassphrase == "" || cfg.WalletName == "" || cfg.WalletAccountName == "" || cfg.PaymentAddress == "" || cfg.ReadonlyKey == "" {
+				log.Println("Wrong param")
+				return
+			}
+			err := importWatchOnly(cfg.PaymentAddress, cfg.ReadonlyKey, cfg.WalletAccountName)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
 	case backupChain:
 		{
 			if cfg.Beacon == false && cfg.ShardIDs == "" {
 				log.Println("No Expected Params")
 				return
 			}
-			bc, err := makeBlockChain(cfg.ChainDataDir, cfg.TestNet)
+			bc, err := makeBlockChain(cfg.DBEngine, cfg.ChainDataDir, cfg.TestNet)
 			if err != nil {
 				log.Println("Error create blockchain variable ", err)
 				return
@@ -193,7 +253,7 @@ func processCmd() {
 				log.Println("No Backup File to Process")
 				return
 			}
-			bc, err := makeBlockChain(cfg.ChainDataDir, cfg.TestNet)
+			bc, err := makeBlockChain(cfg.DBEngine, cfg.ChainDataDir, cfg.TestNet)
 			if err != nil {
 				log.Println("Error create blockchain variable ", err)
 				return
@@ -213,5 +273,143 @@ func processCmd() {
 				}
 			}
 		}
+	case migrateDB:
+		{
+			if cfg.ChainDataDir == "" || cfg.OutDataDir == "" || cfg.ToDBEngine == "" {
+				log.Println("Wrong param, expect chaindatadir, outdatadir and todbengine")
+				return
+			}
+			err := migrateDatabase(cfg.DBEngine, cfg.ChainDataDir, cfg.ToDBEngine, cfg.OutDataDir)
+			if err != nil {
+				log.Printf("Migrate database failed, err %+v", err)
+			}
+		}
+	case devnetInitCmd:
+		{
+			if cfg.OutDataDir == "" {
+				log.Println("Wrong param, expect outdatadir")
+				return
+			}
+			err := devnetInit(devnetParams{
+				NumBeacon:            cfg.NumBeacon,
+				NumShards:            cfg.NumShards,
+				ShardCommitteeSize:   cfg.ShardCommitteeSize,
+				Epoch:                cfg.Epoch,
+				BlockTime:            cfg.BlockTime,
+				InitAmount:           cfg.InitAmount,
+				AllocFile:            cfg.AllocFile,
+				ForkSchedule:         cfg.ForkSchedule,
+				Seed:                 cfg.Seed,
+				DiscoverPeersAddress: cfg.DiscoverPeersAddress,
+				OutDir:               cfg.OutDataDir,
+			})
+			if err != nil {
+				log.Printf("Init devnet failed, err %+v", err)
+				return
+			}
+			log.Printf("Devnet written to %+v, start each node from this directory with --configfile %+v/<node>.conf", cfg.OutDataDir, devnetNodeConfigDirname)
+		}
+	case auditSupplyCmd:
+		{
+			if cfg.ChainDataDir == "" {
+				log.Println("Wrong param, expect chaindatadir")
+				return
+			}
+			bc, err := makeBlockChain(cfg.DBEngine, cfg.ChainDataDir, cfg.TestNet)
+			if err != nil {
+				log.Println("Error create blockchain variable ", err)
+				return
+			}
+			err = auditSupply(bc, cfg.FromHeight, cfg.ToHeight, cfg.RangeSize, cfg.BalanceFile, cfg.OutDataDir, cfg.FileName)
+			if err != nil {
+				log.Printf("Audit supply failed, err %+v", err)
+			}
+		}
+	case multiSigNewKeyGenCmd:
+		{
+			if cfg.Threshold <= 0 || cfg.PaymentAddresses == "" || cfg.OutFile == "" {
+				log.Println("Wrong param")
+				return
+			}
+			err := multiSigNewKeyGen(cfg.Threshold, splitList(cfg.PaymentAddresses), cfg.OutFile)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case multiSigKeyGenCmd:
+		{
+			if cfg.WalletPassphrase == "" || cfg.WalletName == "" || cfg.FileName == "" {
+				log.Println("Wrong param")
+				return
+			}
+			err := multiSigKeyGen(cfg.FileName, cfg.WalletAccountName, cfg.MultiSigAccount, cfg.OutFile)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case multiSigMergeKeyGenCmd:
+		{
+			if cfg.FileName == "" || cfg.PartialFiles == "" {
+				log.Println("Wrong param")
+				return
+			}
+			outFile := cfg.OutFile
+			if outFile == "" {
+				outFile = cfg.FileName
+			}
+			err := multiSigMergeKeyGen(cfg.FileName, splitList(cfg.PartialFiles), outFile)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case multiSigSignCmd:
+		{
+			if cfg.WalletPassphrase == "" || cfg.WalletName == "" || cfg.MultiSigAccount == "" || cfg.FileName == "" || cfg.OutFile == "" {
+				log.Println("Wrong param")
+				return
+			}
+			err := multiSigSign(cfg.FileName, cfg.MultiSigAccount, cfg.OutFile)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case multiSigMergeSignCmd:
+		{
+			if cfg.FileName == "" || cfg.PartialFiles == "" {
+				log.Println("Wrong param")
+				return
+			}
+			outFile := cfg.OutFile
+			if outFile == "" {
+				outFile = cfg.FileName
+			}
+			err := multiSigMergeSign(cfg.FileName, splitList(cfg.PartialFiles), outFile)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case multiSigListAccountsCmd:
+		{
+			if cfg.WalletPassphrase == "" || cfg.WalletName == "" {
+				log.Println("Wrong param")
+				return
+			}
+			accounts, err := multiSigListAccounts()
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			result, err := parseToJsonString(accounts)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			log.Println(string(result))
+		}
 	}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
@@ -75,9 +76,15 @@ func getAccount(accountName string) (interface{}, error) {
 		if accountName == account.Name {
 			result := make(map[string]interface{})
 			result["Name"] = accountName
-			result["PrivateKey"] = account.Key.Base58CheckSerialize(wallet.PriKeyType)
+			if !account.IsWatchOnly {
+				result["PrivateKey"] = account.Key.Base58CheckSerialize(wallet.PriKeyType)
+			}
 			result["PaymentAddress"] = account.Key.Base58CheckSerialize(wallet.PaymentAddressType)
 			result["ReadonlyKey"] = account.Key.Base58CheckSerialize(wallet.ReadonlyKeyType)
+			if account.Path != "" {
+				result["Path"] = account.Path
+			}
+			result["IsWatchOnly"] = account.IsWatchOnly
 			return result, nil
 		}
 	}
@@ -110,3 +117,108 @@ func createAccount(accountName string, shardID *byte) (interface{}, error) {
 	}
 	return nil, errors.New("Can not load wallet")
 }
+
+// changePassPhrase encrypts the wallet with the new passphrase and a key
+// derived with the kdf params of the flags, the default params of the kdf
+// are used for the params not set
+func changePassPhrase(newPassPhrase string) (*wallet.KDFParams, error) {
+	walletObj, err := loadWallet()
+	if err != nil {
+		return nil, err
+	}
+	var params wallet.KDFParams
+	switch cfg.KDF {
+	case "", wallet.KDFArgon2id:
+		params = wallet.DefaultKDFParams()
+		if cfg.KDFTime > 0 {
+			params.Time = cfg.KDFTime
+		}
+		if cfg.KDFMemory > 0 {
+			params.Memory = cfg.KDFMemory
+		}
+		if cfg.KDFThreads > 0 {
+			params.Threads = cfg.KDFThreads
+		}
+	case wallet.KDFScrypt:
+		params = wallet.DefaultScryptKDFParams()
+		if cfg.ScryptN > 0 {
+			params.N = cfg.ScryptN
+		}
+		if cfg.ScryptR > 0 {
+			params.R = cfg.ScryptR
+		}
+		if cfg.ScryptP > 0 {
+			params.P = cfg.ScryptP
+		}
+	default:
+		return nil, fmt.Errorf("unknown kdf %s, expect %s or %s", cfg.KDF, wallet.KDFArgon2id, wallet.KDFScrypt)
+	}
+	if err := walletObj.ChangePassPhrase(cfg.WalletPassphrase, newPassPhrase, params); err != nil {
+		return nil, err
+	}
+	return walletObj.KDFParams(), nil
+}
+
+// createPathAccount adds the account of the next index of the account number
+// of the path m/44'/587'/account'/index, with a payment address in shardID
+// when it is not nil
+func createPathAccount(accountName string, account uint32, shardID *byte) error {
+	walletObj, err := loadWallet()
+	if err != nil {
+		return err
+	}
+	accountWallet, err := walletObj.CreatePathAccount(accountName, account, shardID, cfg.WalletPassphrase)
+	if err != nil {
+		return err
+	}
+	log.Printf("Create account '%s' at %s successfully", accountWallet.Name, accountWallet.Path)
+	log.Printf("Private key: %s", accountWallet.Key.Base58CheckSerialize(wallet.PriKeyType))
+	log.Printf("Payment address: %s", accountWallet.Key.Base58CheckSerialize(wallet.PaymentAddressType))
+	log.Printf("Readonly key: %s", accountWallet.Key.Base58CheckSerialize(wallet.ReadonlyKeyType))
+	return nil
+}
+
+// restoreWallet creates the wallet of mnemonic, with the accounts of the first
+// numAccounts indexes of the account 0. The accounts which received coins are
+// added by the discoveraccounts RPC of a node loading the wallet
+func restoreWallet(mnemonic string, numAccounts uint32) error {
+	walletObj := &wallet.Wallet{}
+	walletObj.SetConfig(&wallet.WalletConfig{
+		DataDir:        cfg.DataDir,
+		DataFile:       cfg.WalletName,
+		DataPath:       filepath.Join(cfg.DataDir, cfg.WalletName),
+		IncrementalFee: 0,
+	})
+	if _, err := os.Stat(walletObj.GetConfig().DataPath); !os.IsNotExist(err) {
+		return fmt.Errorf("Exist wallet with name %s", cfg.WalletName)
+	}
+	if err := walletObj.InitFromMnemonic(mnemonic, cfg.WalletPassphrase, cfg.WalletName); err != nil {
+		return err
+	}
+	for i := uint32(0); i < numAccounts; i++ {
+		if _, err := walletObj.DerivePathAccount("", wallet.NewAccountPath(0, i), cfg.WalletPassphrase); err != nil {
+			return err
+		}
+	}
+	if err := walletObj.Save(cfg.WalletPassphrase); err != nil {
+		return err
+	}
+	log.Printf("Restore wallet successfully with name: %s and %d accounts", cfg.WalletName, numAccounts)
+	return nil
+}
+
+// importWatchOnly adds the watch-only account of paymentAddress and
+// readonlyKey
+func importWatchOnly(paymentAddress, readonlyKey, accountName string) error {
+	walletObj, err := loadWallet()
+	if err != nil {
+		return err
+	}
+	account, err := walletObj.ImportWatchOnlyAccount(paymentAddress, readonlyKey, accountName, cfg.WalletPassphrase)
+	if err != nil {
+		return err
+	}
+	log.Printf("Import watch-only account '%s' successfully", account.Name)
+	log.Printf("Payment address: %s", account.Key.Base58CheckSerialize(wallet.PaymentAddressType))
+	return nil
+}
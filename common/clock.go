@@ -0,0 +1,18 @@
+package common
+
+import "time"
+
+// Clock tells the current time, consensus reads time slots from it so tests
+// can move time forward without waiting
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the wall clock used by nodes
+var SystemClock Clock = systemClock{}
@@ -68,6 +68,7 @@ const (
 	NodeModeShard  = "shard"
 	NodeModeAuto   = "auto"
 	NodeModeBeacon = "beacon"
+	NodeModeLight  = "light"
 
 	BeaconRole     = "beacon"
 	ShardRole      = "shard"
@@ -87,6 +88,7 @@ const (
 const (
 	BeaconChainKey = "beacon"
 	ShardChainKey  = "shard"
+	BeaconChainID  = -1 // chain id of the beacon chain, shards are 0 to 255
 )
 
 const (
@@ -134,8 +136,7 @@ const (
 	PDEFeeWithdrawalAcceptedStatus = 1
 	PDEFeeWithdrawalRejectedStatus = 2
 
-	MinTxFeesOnTokenRequirement                             = 10000000000000 // 10000 prv, this requirement is applied from beacon height 87301 mainnet
-	BeaconBlockHeighMilestoneForMinTxFeesOnTokenRequirement = 87301          // milestone of beacon height, when apply min fee on token requirement
+	MinTxFeesOnTokenRequirement = 10000000000000 // 10000 prv, this requirement is applied from fork MinTxFeesOnTokenRequirement
 
 	//portal
 	PortalCustodianDepositAcceptedStatus = 1
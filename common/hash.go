@@ -21,10 +21,10 @@ func (hashObj Hash) MarshalText() ([]byte, error) {
 	return []byte(hashObj.String()), nil
 }
 
-// UnmarshalText reverts bytes array to hashObj
-func (hashObj Hash) UnmarshalText(text []byte) error {
-	copy(hashObj[:], text)
-	return nil
+// UnmarshalText decodes the hash string of MarshalText into hashObj, json uses
+// it for the keys of maps indexed by hash
+func (hashObj *Hash) UnmarshalText(text []byte) error {
+	return hashObj.Decode(hashObj, string(text))
 }
 
 // UnmarshalJSON unmarshal json data to hashObj
@@ -2,6 +2,7 @@ package common
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -250,3 +251,22 @@ func TestHashHashArrayInterfaceWithInvalidInterface(t *testing.T) {
 		assert.Equal(t, errors.New("interface input is not an array"), err)
 	}
 }
+
+/*
+	Unit test for MarshalText and UnmarshalText functions
+ */
+
+func TestHashMapKeyJSON(t *testing.T) {
+	data := map[Hash]uint64{
+		PRVCoinID:        10,
+		HashH([]byte{1}): 20,
+	}
+
+	dataBytes, err := json.Marshal(data)
+	assert.Equal(t, nil, err)
+
+	res := make(map[Hash]uint64)
+	err = json.Unmarshal(dataBytes, &res)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, data, res)
+}
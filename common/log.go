@@ -2,6 +2,7 @@ package common
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -181,8 +182,15 @@ type Backend struct {
 	w    io.Writer
 	mu   sync.Mutex // ensures atomic writes
 	flag uint32
+	json uint32 // atomic, entries are written as json objects when 1
 }
 
+// Formats of the log entries of a Backend
+const (
+	LogFormatText = "text" // colored lines with a header
+	LogFormatJSON = "json" // one json object per line with the fields as keys
+)
+
 // BackendOption is a function used to modify the behavior of a Backend.
 type BackendOption func(b *Backend)
 
@@ -195,6 +203,31 @@ func WithFlags(flags uint32) BackendOption {
 	}
 }
 
+// WithFormat configures a Backend to write its entries in the given format.
+func WithFormat(format string) BackendOption {
+	return func(b *Backend) {
+		b.SetFormat(format)
+	}
+}
+
+// SetFormat changes the format of the entries written by b, it returns an
+// error for an unknown format.
+func (b *Backend) SetFormat(format string) error {
+	switch format {
+	case LogFormatText:
+		atomic.StoreUint32(&b.json, 0)
+	case LogFormatJSON:
+		atomic.StoreUint32(&b.json, 1)
+	default:
+		return fmt.Errorf("unknown log format %v, expected %v or %v", format, LogFormatText, LogFormatJSON)
+	}
+	return nil
+}
+
+func (b *Backend) isJSON() bool {
+	return atomic.LoadUint32(&b.json) == 1
+}
+
 // bufferPool defines a concurrent safe free list of byte slices used to provide
 // temporary buffers for formatting log messages prior to outputting them.
 var bufferPool = sync.Pool{
@@ -305,21 +338,25 @@ func callsite(flag uint32) (string, int) {
 // creating a prefix for the given level and tag according to the formatHeader
 // function and formatting the provided arguments using the default formatting
 // rules.
-func (b *Backend) print(lvl, tag string, args ...interface{}) {
+func (b *Backend) print(lvl, tag string, fields Fields, args ...interface{}) {
 	t := time.Now() // get as early as possible
 
-	bytebuf := buffer()
-
 	var file string
 	var line int
 	if b.flag&(Lshortfile|Llongfile) != 0 {
 		file, line = callsite(b.flag)
 	}
+	if b.isJSON() {
+		msg := fmt.Sprintln(args...)
+		b.printJSON(t, lvl, tag, file, line, msg[:len(msg)-1], fields)
+		return
+	}
 
+	bytebuf := buffer()
 	formatHeader(bytebuf, t, lvl, tag, file, line)
 	buf := bytes.NewBuffer(*bytebuf)
 	fmt.Fprintln(buf, args...)
-	*bytebuf = buf.Bytes()
+	*bytebuf = appendFields(buf.Bytes(), fields)
 
 	b.colorPrint(lvl, *bytebuf)
 	// @hunghd SEND LOG TO AGGREGATION LOG SERVER
@@ -338,20 +375,24 @@ func (b *Backend) print(lvl, tag string, args ...interface{}) {
 // creating a prefix for the given level and tag according to the formatHeader
 // function and formatting the provided arguments according to the given format
 // specifier.
-func (b *Backend) printf(lvl, tag string, format string, args ...interface{}) {
+func (b *Backend) printf(lvl, tag string, fields Fields, format string, args ...interface{}) {
 	t := time.Now() // get as early as possible
 
-	bytebuf := buffer()
-
 	var file string
 	var line int
 	if b.flag&(Lshortfile|Llongfile) != 0 {
 		file, line = callsite(b.flag)
 	}
+	if b.isJSON() {
+		b.printJSON(t, lvl, tag, file, line, fmt.Sprintf(format, args...), fields)
+		return
+	}
+
+	bytebuf := buffer()
 	formatHeader(bytebuf, t, lvl, tag, file, line)
 	buf := bytes.NewBuffer(*bytebuf)
 	fmt.Fprintf(buf, format, args...)
-	*bytebuf = append(buf.Bytes(), '\n')
+	*bytebuf = appendFields(append(buf.Bytes(), '\n'), fields)
 
 	b.colorPrint(lvl, *bytebuf)
 	// @hunghd SEND LOG TO AGGREGATION LOG SERVER
@@ -366,6 +407,49 @@ func (b *Backend) printf(lvl, tag string, format string, args ...interface{}) {
 	recycleBuffer(bytebuf)
 }
 
+// printJSON writes an entry as a json object on one line, the fields of the
+// logger are keys of the object next to the time, level, subsystem and
+// message.
+func (b *Backend) printJSON(t time.Time, lvl, tag, file string, line int, msg string, fields Fields) {
+	bytebuf := buffer()
+	*bytebuf = append(*bytebuf, `{"time":`...)
+	*bytebuf = appendJSON(*bytebuf, t.Format(time.RFC3339Nano))
+	*bytebuf = append(*bytebuf, `,"level":`...)
+	*bytebuf = appendJSON(*bytebuf, lvl)
+	*bytebuf = append(*bytebuf, `,"subsystem":`...)
+	*bytebuf = appendJSON(*bytebuf, strings.TrimSpace(tag))
+	if file != "" {
+		*bytebuf = append(*bytebuf, `,"caller":`...)
+		*bytebuf = appendJSON(*bytebuf, fmt.Sprintf("%v:%v", file, line))
+	}
+	*bytebuf = append(*bytebuf, `,"msg":`...)
+	*bytebuf = appendJSON(*bytebuf, msg)
+	for _, key := range fields.keys() {
+		*bytebuf = append(*bytebuf, ',')
+		*bytebuf = appendJSON(*bytebuf, key)
+		*bytebuf = append(*bytebuf, ':')
+		*bytebuf = appendJSON(*bytebuf, fieldValue(fields[key]))
+	}
+	*bytebuf = append(*bytebuf, "}\n"...)
+
+	b.mu.Lock()
+	b.w.Write(*bytebuf)
+	b.mu.Unlock()
+	// @hunghd SEND LOG TO AGGREGATION LOG SERVER
+	if isAggregationLogMode() {
+		HandleCaptureMessage(msg, lvl)
+	}
+	recycleBuffer(bytebuf)
+}
+
+func appendJSON(buf []byte, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return append(buf, data...)
+}
+
 func (b *Backend) colorPrint(lvl string, bytebuf []byte) {
 	b.mu.Lock()
 	switch lvl {
@@ -399,7 +483,7 @@ func (b *Backend) colorPrint(lvl string, bytebuf []byte) {
 // Backend b.  A tag describes the subsystem and is included in all log
 // messages.  The logger uses the info verbosity level by default.
 func (b *Backend) Logger(subsystemTag string, disable bool) Logger {
-	return &slog{LevelInfo, subsystemTag, b, disable}
+	return &slog{lvl: LevelInfo, tag: subsystemTag, b: b, disable: disable}
 }
 
 // slog is a subsystem logger for a Backend.  Implements the Logger interface.
@@ -408,6 +492,8 @@ type slog struct {
 	tag     string
 	b       *Backend
 	disable bool
+	fields  Fields
+	root    *slog // subsystem logger of a logger with fields, keeping the level
 }
 
 // Trace formats message using the default formats for its operands, prepends
@@ -421,7 +507,7 @@ func (l *slog) Trace(args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelTrace {
 		if !l.disable {
-			l.b.print("TRC", l.tag, args...)
+			l.b.print("TRC", l.tag, l.fields, args...)
 		}
 	}
 }
@@ -437,7 +523,7 @@ func (l *slog) Tracef(format string, args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelTrace {
 		if !l.disable {
-			l.b.printf("TRC", l.tag, format, args...)
+			l.b.printf("TRC", l.tag, l.fields, format, args...)
 		}
 	}
 }
@@ -453,7 +539,7 @@ func (l *slog) Debug(args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelDebug {
 		if !l.disable {
-			l.b.print("DBG", l.tag, args...)
+			l.b.print("DBG", l.tag, l.fields, args...)
 		}
 	}
 }
@@ -469,7 +555,7 @@ func (l *slog) Debugf(format string, args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelDebug {
 		if !l.disable {
-			l.b.printf("DBG", l.tag, format, args...)
+			l.b.printf("DBG", l.tag, l.fields, format, args...)
 		}
 	}
 }
@@ -485,7 +571,7 @@ func (l *slog) Info(args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelInfo {
 		if !l.disable {
-			l.b.print("INF", l.tag, args...)
+			l.b.print("INF", l.tag, l.fields, args...)
 		}
 	}
 }
@@ -501,7 +587,7 @@ func (l *slog) Infof(format string, args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelInfo {
 		if !l.disable {
-			l.b.printf("INF", l.tag, format, args...)
+			l.b.printf("INF", l.tag, l.fields, format, args...)
 		}
 	}
 }
@@ -517,7 +603,7 @@ func (l *slog) Warn(args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelWarn {
 		if !l.disable {
-			l.b.print("WRN", l.tag, args...)
+			l.b.print("WRN", l.tag, l.fields, args...)
 		}
 	}
 }
@@ -533,7 +619,7 @@ func (l *slog) Warnf(format string, args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelWarn {
 		if !l.disable {
-			l.b.printf("WRN", l.tag, format, args...)
+			l.b.printf("WRN", l.tag, l.fields, format, args...)
 		}
 	}
 }
@@ -549,7 +635,7 @@ func (l *slog) Error(args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelError {
 		if !l.disable {
-			l.b.print("ERR", l.tag, args...)
+			l.b.print("ERR", l.tag, l.fields, args...)
 		}
 	}
 }
@@ -565,7 +651,7 @@ func (l *slog) Errorf(format string, args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelError {
 		if !l.disable {
-			l.b.printf("ERR", l.tag, format, args...)
+			l.b.printf("ERR", l.tag, l.fields, format, args...)
 		}
 	}
 }
@@ -581,7 +667,7 @@ func (l *slog) Critical(args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelCritical {
 		if !l.disable {
-			l.b.print("CRT", l.tag, args...)
+			l.b.print("CRT", l.tag, l.fields, args...)
 		}
 	}
 }
@@ -597,7 +683,7 @@ func (l *slog) Criticalf(format string, args ...interface{}) {
 	lvl := l.Level()
 	if lvl <= LevelCritical {
 		if !l.disable {
-			l.b.printf("CRT", l.tag, format, args...)
+			l.b.printf("CRT", l.tag, l.fields, format, args...)
 		}
 	}
 }
@@ -606,6 +692,9 @@ func (l *slog) Criticalf(format string, args ...interface{}) {
 //
 // This is part of the Logger interface implementation.
 func (l *slog) Level() Level {
+	if l.root != nil {
+		return l.root.Level()
+	}
 	return Level(atomic.LoadUint32((*uint32)(&l.lvl)))
 }
 
@@ -613,6 +702,10 @@ func (l *slog) Level() Level {
 //
 // This is part of the Logger interface implementation.
 func (l *slog) SetLevel(level Level) {
+	if l.root != nil {
+		l.root.SetLevel(level)
+		return
+	}
 	atomic.StoreUint32((*uint32)(&l.lvl), uint32(level))
 }
 
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogJSONFormat(t *testing.T) {
+	w := &bytes.Buffer{}
+	b := NewBackend(w, WithFormat(LogFormatJSON))
+	logger := b.Logger("Syncker log ", false)
+	blockLog := WithFields(logger, BlockFields(BeaconChainID, 10, "abc"))
+	blockLog.Infof("Insert block %v", 10)
+	WithFields(blockLog, Fields{FieldPeerID: "peer1"}).Error(errors.New("bad block"))
+
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	entry := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "INF", entry["level"])
+	assert.Equal(t, "Syncker log", entry["subsystem"])
+	assert.Equal(t, "Insert block 10", entry["msg"])
+	assert.Equal(t, float64(-1), entry[FieldChainID])
+	assert.Equal(t, float64(10), entry[FieldHeight])
+	assert.Equal(t, "abc", entry[FieldBlockHash])
+	assert.NotContains(t, entry, FieldPeerID)
+
+	entry = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "ERR", entry["level"])
+	assert.Equal(t, "bad block", entry["msg"])
+	assert.Equal(t, "peer1", entry[FieldPeerID])
+	assert.Equal(t, "abc", entry[FieldBlockHash])
+
+	assert.Error(t, b.SetFormat("xml"))
+}
+
+func TestLogTextFields(t *testing.T) {
+	w := &bytes.Buffer{}
+	b := NewBackend(w)
+	logger := WithFields(b.Logger("Mempool log", false), Fields{FieldTxHash: "h1", "reason": "pool full"})
+	logger.Warn("Reject tx")
+	assert.True(t, strings.HasSuffix(w.String(), `Reject tx reason="pool full" txHash=h1`+"\n"), w.String())
+}
+
+func TestLogFieldsLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	logger := NewBackend(w).Logger("Consensus log", false)
+	child := WithFields(logger, Fields{FieldRound: 1})
+
+	// the level of the loggers with fields follows their parent
+	logger.SetLevel(LevelError)
+	assert.Equal(t, LevelError, child.Level())
+	child.Info("hidden")
+	assert.Empty(t, w.String())
+
+	child.SetLevel(LevelDebug)
+	assert.Equal(t, LevelDebug, logger.Level())
+	child.Debug("shown")
+	assert.Contains(t, w.String(), "shown round=1")
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields are the context of log entries, like the chain and height of the
+// block they are about. They are keys of the entries in the json format and
+// key=value pairs after the message in the text format.
+type Fields map[string]interface{}
+
+// Keys of the fields logged by the block, consensus, sync and mempool code
+// paths, for the entries about one block or tx to be found across them
+const (
+	FieldChainID   = "chainID"   // int, -1 for the beacon chain
+	FieldHeight    = "height"    // uint64
+	FieldBlockHash = "blockHash" // string
+	FieldTxHash    = "txHash"    // string
+	FieldPeerID    = "peerID"    // string
+	FieldTimeSlot  = "timeSlot"  // int64
+	FieldRound     = "round"     // int
+	FieldValidator = "validator" // string, mining key of a consensus vote
+)
+
+// BlockFields returns the fields of a block of a chain
+func BlockFields(chainID int, height uint64, blockHash string) Fields {
+	return Fields{FieldChainID: chainID, FieldHeight: height, FieldBlockHash: blockHash}
+}
+
+// WithFields returns a logger writing the fields with all its entries, next
+// to the fields of l; its level is the level of l. Loggers not created by a
+// Backend are returned as is.
+func WithFields(l Logger, fields Fields) Logger {
+	parent, ok := l.(*slog)
+	if !ok || parent == nil {
+		return l
+	}
+	merged := Fields{}
+	for key, value := range parent.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	root := parent
+	if parent.root != nil {
+		root = parent.root
+	}
+	return &slog{tag: parent.tag, b: parent.b, disable: parent.disable, fields: merged, root: root}
+}
+
+// keys returns the keys of the fields in order
+func (fields Fields) keys() []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldValue returns the value written for a field, the string of errors and
+// values with a String method
+func fieldValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return v
+}
+
+// appendFields appends the fields as key=value pairs to a text entry, before
+// its line break
+func appendFields(buf []byte, fields Fields) []byte {
+	if len(fields) == 0 {
+		return buf
+	}
+	buf = buf[:len(buf)-1]
+	for _, key := range fields.keys() {
+		value := fmt.Sprint(fieldValue(fields[key]))
+		if strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		buf = append(buf, ' ')
+		buf = append(buf, key...)
+		buf = append(buf, '=')
+		buf = append(buf, value...)
+	}
+	return append(buf, '\n')
+}
@@ -13,9 +13,13 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/txtracker"
 	"github.com/jessevdk/go-flags"
 )
 
@@ -24,10 +28,14 @@ const (
 	DefaultConfigFilename              = "config.conf"
 	DefaultDataDirname                 = "data"
 	DefaultDatabaseDirname             = "block"
+	DefaultDatabaseEngine              = "leveldb"
+	DefaultCompactThrottle             = time.Second
 	DefaultDatabaseMempoolDirname      = "mempool"
 	DefaultLogLevel                    = "info"
+	DefaultLogFormat                   = common.LogFormatText
 	DefaultLogDirname                  = "logs"
 	DefaultLogFilename                 = "log.log"
+	DefaultDirectPeersFilename         = "directpeers.json"
 	DefaultMaxPeers                    = 1000
 	DefaultMaxPeersSameShard           = 300
 	DefaultMaxPeersOtherShard          = 600
@@ -39,6 +47,10 @@ const (
 	DefaultRPCLimitErrorRequestPerHour = 0 // 0: unlimited
 	DefaultMaxRPCWsClients             = 200
 	DefaultMetricUrl                   = ""
+	DefaultMetricInterval              = 10 * time.Second
+	DefaultTxTrackerSize               = txtracker.DefaultCapacity
+	DefaultReadyMaxSyncLag             = 10
+	DefaultReadyMaxBlockAge            = 5 * time.Minute
 	SampleConfigFilename               = "sample-config.conf"
 	DefaultDisableRpcTLS               = true
 	DefaultFastStartup                 = true
@@ -46,6 +58,8 @@ const (
 	DefaultEnableMining                = true
 	DefaultTxPoolTTL                   = uint(15 * 60) // 15 minutes
 	DefaultTxPoolMaxTx                 = uint64(100000)
+	DefaultTxPoolHeldTTL               = uint(7 * 24 * 60 * 60) // 7 days
+	DefaultTxPoolMaxHeldTx             = uint64(10000)
 	DefaultLimitFee                    = uint64(1) // 1 nano PRV = 10^-9 PRV
 	//DefaultLimitFee = uint64(100000) // 100000 nano PRV = 100000 * 10^-9 PRV
 	// For wallet
@@ -76,8 +90,10 @@ type config struct {
 	DataDir            string `short:"D" long:"datadir" description:"Directory to store data"`
 	DatabaseDir        string `short:"d" long:"datapre" description:"Database dir"`
 	DatabaseMempoolDir string `short:"m" long:"datamempool" description:"Mempool Database Dir"`
+	DatabaseEngine     string `long:"dbengine" description:"Storage engine of chain database {leveldb, badgerdb, memdb}, use chainctl migratedb to move data between engines"`
 	LogDir             string `short:"l" long:"logdir" description:"Directory to log output."`
 	LogLevel           string `long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+	LogFormat          string `long:"logformat" description:"Format of the log entries {text, json}"`
 
 	AddPeers             []string `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
 	ConnectPeers         []string `short:"c" long:"connect" description:"Connect only to the specified peers at startup"`
@@ -122,7 +138,7 @@ type config struct {
 	TestNet        string `long:"testnet" description:"Use the test network"`
 	TestNetVersion string `long:"testnetversion" description:"Use the test network"`
 
-	NodeMode    string `long:"nodemode" description:"Role of this node (beacon/shard/wallet/relay | default role is 'relay' (relayshards must be set to run), 'auto' mode will switch between 'beacon' and 'shard')"`
+	NodeMode    string `long:"nodemode" description:"Role of this node (beacon/shard/wallet/relay/light | default role is 'relay' (relayshards must be set to run), 'auto' mode will switch between 'beacon' and 'shard', 'light' only verifies beacon headers and shard proofs (lightrpc must be set to run))"`
 	RelayShards string `long:"relayshards" description:"set relay shards of this node when in 'relay' mode if noderole is auto then it only sync shard data when user is a shard producer/validator"`
 	// For Wallet
 	Wallet           bool   `long:"enablewallet" description:"Enable wallet"`
@@ -132,10 +148,38 @@ type config struct {
 	WalletShardID    int    `long:"walletshardid" description:"ShardID which wallet use to create account"`
 
 	FastStartup bool `long:"faststartup" description:"Load existed shard/chain dependencies instead of rebuild from block data"`
+	Archive     bool `long:"archive" description:"Archive node mode, serve state queries at any height instead of recent heights only"`
 
-	TxPoolTTL   uint   `long:"txpoolttl" description:"Set Time To Live (TTL) Value for transaction that enter pool"`
-	TxPoolMaxTx uint64 `long:"txpoolmaxtx" description:"Set Maximum number of transaction in pool"`
-	LimitFee    uint64 `long:"limitfee" description:"Limited fee for tx(per Kb data), default is 0.00 PRV"`
+	CompactInterval time.Duration `long:"compactinterval" description:"Compact chain and mempool databases in background at this interval (e.g. 24h), 0 disables scheduled compaction, it can still be started by RPC"`
+	CompactThrottle time.Duration `long:"compactthrottle" description:"Pause between two key ranges of a background compaction"`
+
+	MetricsListen  string        `long:"metricslisten" description:"Interface/port serving the metrics in the Prometheus text format on /metrics and the /healthz and /readyz probes (e.g. 127.0.0.1:9090), disabled when empty"`
+	MetricInterval time.Duration `long:"metricinterval" description:"Interval of the pushes of the metrics to metricurl"`
+
+	ReadyMaxSyncLag  uint64        `long:"readymaxsynclag" description:"Max number of blocks a chain may be behind its peers for /readyz to succeed, 0 disables the check"`
+	ReadyMaxBlockAge time.Duration `long:"readymaxblockage" description:"Max age of the best block of a chain for /readyz to succeed, 0 disables the check"`
+
+	TxTrackerSize int    `long:"txtrackersize" description:"Number of txs whose lifecycle is kept for gettxlifecycle"`
+	OTLPURL       string `long:"otlpurl" description:"Optional URL of an OpenTelemetry collector, the tx lifecycles are pushed to it as spans with OTLP/HTTP"`
+
+	RelayerEthURL      string `long:"relayerethurl" description:"URL of an Ethereum node, enables the bridge relayer submitting the proofs of the burns to the vault and of the committee swaps to the incognito proxy"`
+	RelayerKeyFile     string `long:"relayerkeyfile" description:"File holding the hex encoded Ethereum private key paying the txs of the relayer"`
+	RelayerVault       string `long:"relayervault" description:"Address of the vault contract"`
+	RelayerProxy       string `long:"relayerproxy" description:"Address of the incognito proxy contract"`
+	RelayerStartHeight uint64 `long:"relayerstartheight" description:"First beacon height relayed on the first start, the relayer starts after the final beacon block when 0"`
+	RelayerMaxGasPrice uint64 `long:"relayermaxgasprice" description:"Max gas price of the txs of the relayer in gwei, 0 for no cap"`
+
+	ChainParams  string `long:"chainparams" description:"Path to a chain params file of a private network generated by chainctl devnetinit, it replaces the params of testnet"`
+	ForkSchedule string `long:"forkschedule" description:"Path to a json file overriding the fork schedule of a test network, e.g. {\"ConsensusV2\": {\"Epoch\": 2}}"`
+
+	UpgradeNoticeURL    string `long:"upgradenoticeurl" description:"Optional URL of a signed release notice, a warning is logged when it announces another version"`
+	UpgradeNoticePubKey string `long:"upgradenoticepubkey" description:"Hex encoded ed25519 public key the release notice must be signed with, required by --upgradenoticeurl"`
+
+	TxPoolTTL       uint   `long:"txpoolttl" description:"Set Time To Live (TTL) Value for transaction that enter pool"`
+	TxPoolMaxTx     uint64 `long:"txpoolmaxtx" description:"Set Maximum number of transaction in pool"`
+	TxPoolHeldTTL   uint   `long:"txpoolheldttl" description:"Set Time To Live (TTL) Value for time-locked transaction held in pool until its window opens"`
+	TxPoolMaxHeldTx uint64 `long:"txpoolmaxheldtx" description:"Set Maximum number of time-locked transaction held in pool, not counted in txpoolmaxtx"`
+	LimitFee        uint64 `long:"limitfee" description:"Limited fee for tx(per Kb data), default is 0.00 PRV"`
 
 	LoadMempool       bool   `long:"loadmempool" description:"Load transactions from Mempool database"`
 	PersistMempool    bool   `long:"persistmempool" description:"Persistence transaction in memepool database"`
@@ -152,6 +196,9 @@ type config struct {
 
 	// Highway
 	Libp2pPrivateKey string `long:"libp2pprivatekey" description:"Private key used to create node's PeerID, empty to generate random key each run"`
+	DirectFallback   bool   `long:"directfallback" description:"Gossip and sync directly with other nodes when no highway is reachable"`
+	DirectBootnode   string `long:"directbootnode" description:"Address of the bootnode keeping the nodes reachable without highway, used with --directfallback"`
+	LightRPC         string `long:"lightrpc" description:"RPC address of the full node serving committee lists and proofs in light mode"`
 
 	//backup
 	PreloadAddress string `long:"preloadaddress" description:"Endpoint of fullnode to download backup database"`
@@ -307,6 +354,7 @@ func loadConfig() (*config, []string, error) {
 	cfg := config{
 		ConfigFile:                  defaultConfigFile,
 		LogLevel:                    DefaultLogLevel,
+		LogFormat:                   DefaultLogFormat,
 		MaxOutPeers:                 DefaultMaxPeers,
 		MaxInPeers:                  DefaultMaxPeers,
 		MaxPeers:                    DefaultMaxPeers,
@@ -321,6 +369,8 @@ func loadConfig() (*config, []string, error) {
 		RPCLimitRequestErrorPerHour: DefaultRPCLimitErrorRequestPerHour,
 		DataDir:                     defaultDataDir,
 		DatabaseDir:                 DefaultDatabaseDirname,
+		DatabaseEngine:              DefaultDatabaseEngine,
+		CompactThrottle:             DefaultCompactThrottle,
 		DatabaseMempoolDir:          DefaultDatabaseMempoolDirname,
 		LogDir:                      defaultLogDir,
 		RPCKey:                      defaultRPCKeyFile,
@@ -339,9 +389,15 @@ func loadConfig() (*config, []string, error) {
 		FastStartup:                 DefaultFastStartup,
 		TxPoolTTL:                   DefaultTxPoolTTL,
 		TxPoolMaxTx:                 DefaultTxPoolMaxTx,
+		TxPoolHeldTTL:               DefaultTxPoolHeldTTL,
+		TxPoolMaxHeldTx:             DefaultTxPoolMaxHeldTx,
 		PersistMempool:              DefaultPersistMempool,
 		LimitFee:                    DefaultLimitFee,
 		MetricUrl:                   DefaultMetricUrl,
+		MetricInterval:              DefaultMetricInterval,
+		TxTrackerSize:               DefaultTxTrackerSize,
+		ReadyMaxSyncLag:             DefaultReadyMaxSyncLag,
+		ReadyMaxBlockAge:            DefaultReadyMaxBlockAge,
 		BtcClient:                   DefaultBtcClient,
 		BtcClientPort:               DefaultBtcClientPort,
 		EnableMining:                DefaultEnableMining,
@@ -451,6 +507,29 @@ func loadConfig() (*config, []string, error) {
 		os.Exit(common.ExitCodeUnknow)
 	}
 
+	// --chainparams runs a private network on top of the testnet params.
+	if cfg.ChainParams != "" {
+		if activeNetParams == &mainNetParams {
+			str := "%s: --chainparams is only allowed with --testnet"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		chainParams, err := blockchain.LoadChainParams(common.CleanAndExpandPath(cfg.ChainParams, defaultHomeDir))
+		if err != nil {
+			str := "%s: failed to load chain params %s, %v"
+			err := fmt.Errorf(str, funcName, cfg.ChainParams, err)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		activeNetParams = &params{
+			Params:  chainParams,
+			rpcPort: TestnetRpcServerPort,
+			wsPort:  TestnetWsServerPort,
+		}
+		blockchain.GenesisParam = chainParams.GenesisParams
+	}
+
 	// Append the network type to the data directory so it is "namespaced"
 	// per network.  In addition to the block database, there are other
 	// pieces of data that are saved to disk such as address manager state.
@@ -482,6 +561,50 @@ func loadConfig() (*config, []string, error) {
 		fmt.Fprintln(os.Stderr, usageMessage)
 		return nil, nil, err
 	}
+	if err := backendLog.SetFormat(cfg.LogFormat); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err.Error())
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --forkschedule can not change the rules of mainnet.
+	if cfg.ForkSchedule != "" {
+		if activeNetParams == &mainNetParams {
+			str := "%s: --forkschedule is only allowed on test networks"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		overrides, err := forks.LoadSchedule(common.CleanAndExpandPath(cfg.ForkSchedule, defaultHomeDir))
+		if err == nil {
+			activeNetParams.Forks, err = activeNetParams.Forks.Override(overrides)
+		}
+		if err != nil {
+			str := "%s: failed to load fork schedule %s, %v"
+			err := fmt.Errorf(str, funcName, cfg.ForkSchedule, err)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+
+	// --upgradenoticeurl is only trusted with a valid public key.
+	if cfg.UpgradeNoticeURL != "" {
+		if _, err := parseUpgradeNoticePubKey(cfg.UpgradeNoticePubKey); err != nil {
+			str := "%s: --upgradenoticeurl requires a valid --upgradenoticepubkey, %v"
+			err := fmt.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+
+	if err := checkRelayerConfig(&cfg); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
 
 	// --addPeer and --connect do not mix.
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
@@ -647,7 +770,21 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
-	if cfg.MiningKeys == "" && cfg.PrivateKey == "" && cfg.NodeMode != common.NodeModeRelay {
+	if cfg.DirectFallback && cfg.DirectBootnode == "" {
+		str := "%s: the --directfallback option requires --directbootnode"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	if cfg.NodeMode == common.NodeModeLight && cfg.LightRPC == "" {
+		str := "%s: the light nodemode requires --lightrpc"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	if cfg.MiningKeys == "" && cfg.PrivateKey == "" && cfg.NodeMode != common.NodeModeRelay && cfg.NodeMode != common.NodeModeLight {
 		return nil, nil, errors.New("MiningKeys can't be empty if nodemode isn't relay")
 	}
 
@@ -28,6 +28,7 @@ type BLSBFT_V2 struct {
 	isStarted    bool
 	StopCh       chan struct{}
 	Logger       common.Logger
+	Clock        common.Clock
 
 	currentTime      int64
 	currentTimeSlot  int64
@@ -38,21 +39,22 @@ type BLSBFT_V2 struct {
 	receiveBlockByHeight map[uint64][]*ProposeBlockInfo   //blockHeight -> blockInfo
 	receiveBlockByHash   map[string]*ProposeBlockInfo     //blockHash -> blockInfo
 	voteHistory          map[uint64]common.BlockInterface // bestview height (previsous height )-> block
+	metrics              *bftMetrics
 }
 
-func (e BLSBFT_V2) GetChainKey() string {
+func (e *BLSBFT_V2) GetChainKey() string {
 	return e.ChainKey
 }
 
-func (e BLSBFT_V2) GetChainID() int {
+func (e *BLSBFT_V2) GetChainID() int {
 	return e.ChainID
 }
 
-func (e BLSBFT_V2) IsOngoing() bool {
+func (e *BLSBFT_V2) IsOngoing() bool {
 	return e.isStarted
 }
 
-func (e BLSBFT_V2) IsStarted() bool {
+func (e *BLSBFT_V2) IsStarted() bool {
 	return e.isStarted
 }
 
@@ -93,6 +95,7 @@ func (e *BLSBFT_V2) Start() error {
 	e.receiveBlockByHash = make(map[string]*ProposeBlockInfo)
 	e.receiveBlockByHeight = make(map[uint64][]*ProposeBlockInfo)
 	e.voteHistory = make(map[uint64]common.BlockInterface)
+	e.metrics = newBFTMetrics(e.ChainKey)
 	var err error
 	e.proposeHistory, err = lru.New(1000)
 	if err != nil {
@@ -125,7 +128,7 @@ func (e *BLSBFT_V2) Start() error {
 						votes:      make(map[string]BFTVote),
 						hasNewVote: false,
 					}
-					e.Logger.Info("Receive block ", block.Hash().String(), "height", block.GetHeight(), ",block timeslot ", common.CalculateTimeSlot(block.GetProposeTime()))
+					e.blockLogger(block).Info("Receive block ", block.Hash().String(), "height", block.GetHeight(), ",block timeslot ", common.CalculateTimeSlot(block.GetProposeTime()))
 					e.receiveBlockByHeight[block.GetHeight()] = append(e.receiveBlockByHeight[block.GetHeight()], e.receiveBlockByHash[blkHash])
 				} else {
 					e.receiveBlockByHash[blkHash].block = block
@@ -138,7 +141,7 @@ func (e *BLSBFT_V2) Start() error {
 
 				proposeView := e.Chain.GetViewByHash(block.GetPrevHash())
 				if proposeView == nil {
-					e.Logger.Infof("Request sync block from node %s from %s to %s", proposeMsg.PeerID, block.GetPrevHash().String(), block.GetPrevHash().Bytes())
+					common.WithFields(e.blockLogger(block), common.Fields{common.FieldPeerID: proposeMsg.PeerID}).Infof("Request sync block from node %s from %s to %s", proposeMsg.PeerID, block.GetPrevHash().String(), block.GetPrevHash().Bytes())
 					e.Node.RequestMissingViewViaStream(proposeMsg.PeerID, [][]byte{block.GetPrevHash().Bytes()}, e.Chain.GetShardID(), e.Chain.GetChainName())
 				}
 
@@ -147,7 +150,8 @@ func (e *BLSBFT_V2) Start() error {
 				if b, ok := e.receiveBlockByHash[voteMsg.BlockHash]; ok { //if receiveblock is already initiated
 					if _, ok := b.votes[voteMsg.Validator]; !ok { // and not receive validatorA vote
 						b.votes[voteMsg.Validator] = voteMsg // store it
-						e.Logger.Infof("Receive vote for block %s (%d) from %v", voteMsg.BlockHash, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.Validator)
+						e.metrics.votesReceived.Inc(1)
+						e.voteLogger(voteMsg).Infof("Receive vote for block %s (%d) from %v", voteMsg.BlockHash, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.Validator)
 						b.hasNewVote = true
 					}
 				} else {
@@ -157,7 +161,8 @@ func (e *BLSBFT_V2) Start() error {
 					}
 					if _, ok := e.receiveBlockByHash[voteMsg.BlockHash].votes[voteMsg.Validator]; !ok {
 						e.receiveBlockByHash[voteMsg.BlockHash].votes[voteMsg.Validator] = voteMsg
-						e.Logger.Infof("[Monitor] receive vote for block %s (%d) from %v", voteMsg.BlockHash, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.Validator)
+						e.metrics.votesReceived.Inc(1)
+						e.voteLogger(voteMsg).Infof("[Monitor] receive vote for block %s (%d) from %v", voteMsg.BlockHash, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.Validator)
 					}
 				}
 				// e.Logger.Infof("receive vote for block %s (%d)", voteMsg.BlockHash, len(e.receiveBlockByHash[voteMsg.BlockHash].votes))
@@ -166,11 +171,12 @@ func (e *BLSBFT_V2) Start() error {
 				if !e.Chain.IsReady() {
 					continue
 				}
-				e.currentTime = time.Now().Unix()
+				e.currentTime = e.Clock.Now().Unix()
 
 				newTimeSlot := false
 				if e.currentTimeSlot != common.CalculateTimeSlot(e.currentTime) {
 					newTimeSlot = true
+					e.metrics.rounds.Inc(1)
 
 				}
 
@@ -212,11 +218,13 @@ func (e *BLSBFT_V2) Start() error {
 						}
 
 						if createdBlk, err := e.proposeBlock(proposerPk, proposeBlock); err != nil {
-							e.Logger.Critical(UnExpectedError, errors.New("can't propose block"))
-							e.Logger.Critical(err)
+							proposeLog := common.WithFields(e.Logger, common.Fields{common.FieldChainID: e.ChainID, common.FieldHeight: bestView.GetHeight() + 1, common.FieldTimeSlot: e.currentTimeSlot})
+							proposeLog.Critical(UnExpectedError, errors.New("can't propose block"))
+							proposeLog.Critical(err)
 
 						} else {
-							e.Logger.Infof("proposer block %v round %v time slot %v blockTimeSlot %v with hash %v", createdBlk.GetHeight(), createdBlk.GetRound(), e.currentTimeSlot, common.CalculateTimeSlot(createdBlk.GetProduceTime()), createdBlk.Hash().String())
+							e.metrics.proposals.Inc(1)
+							e.blockLogger(createdBlk).Infof("proposer block %v round %v time slot %v blockTimeSlot %v with hash %v", createdBlk.GetHeight(), createdBlk.GetRound(), e.currentTimeSlot, common.CalculateTimeSlot(createdBlk.GetProduceTime()), createdBlk.Hash().String())
 						}
 					}
 				}
@@ -279,9 +287,30 @@ func NewInstance(chain ChainInterface, chainKey string, chainID int, node NodeIn
 	newInstance.ChainID = chainID
 	newInstance.Node = node
 	newInstance.Logger = logger
+	newInstance.Clock = common.SystemClock
 	return newInstance
 }
 
+// blockLogger returns the logger of the entries about a block, with the
+// current time slot
+func (e *BLSBFT_V2) blockLogger(block common.BlockInterface) common.Logger {
+	fields := common.BlockFields(e.ChainID, block.GetHeight(), block.Hash().String())
+	fields[common.FieldTimeSlot] = e.currentTimeSlot
+	fields[common.FieldRound] = block.GetRound()
+	return common.WithFields(e.Logger, fields)
+}
+
+// voteLogger returns the logger of the entries about a vote, by the validator
+// which sent it
+func (e *BLSBFT_V2) voteLogger(vote BFTVote) common.Logger {
+	return common.WithFields(e.Logger, common.Fields{
+		common.FieldChainID:   e.ChainID,
+		common.FieldBlockHash: vote.BlockHash,
+		common.FieldTimeSlot:  e.currentTimeSlot,
+		common.FieldValidator: vote.Validator,
+	})
+}
+
 func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBlockInfo) {
 	//no vote
 	if v.hasNewVote == false {
@@ -335,7 +364,7 @@ func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBloc
 	//e.Logger.Debug(validVote, len(view.GetCommittee()), errVote)
 	v.hasNewVote = false
 	if validVote > 2*len(view.GetCommittee())/3 {
-		e.Logger.Infof("Commit block %v , height: %v", blockHash, v.block.GetHeight())
+		e.blockLogger(v.block).Infof("Commit block %v , height: %v", blockHash, v.block.GetHeight())
 		committeeBLSString, err := incognitokey.ExtractPublickeysFromCommitteeKeyList(view.GetCommittee(), common.BlsConsensus)
 		//fmt.Println(committeeBLSString)
 		if err != nil {
@@ -364,6 +393,7 @@ func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBloc
 			return
 		}
 
+		e.metrics.commits.Inc(1)
 		go e.Chain.InsertAndBroadcastBlock(v.block)
 
 		delete(e.receiveBlockByHash, blockHash)
@@ -371,11 +401,12 @@ func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBloc
 }
 
 func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
+	voteLog := e.blockLogger(v.block)
 	//not connected
-	e.Logger.Info("validateAndVote")
+	voteLog.Info("validateAndVote")
 	view := e.Chain.GetViewByHash(v.block.GetPrevHash())
 	if view == nil {
-		e.Logger.Info("view is null")
+		voteLog.Info("view is null")
 		return errors.New("View not connect")
 	}
 
@@ -384,7 +415,7 @@ func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
 	defer cancel()
 
 	if err := e.Chain.ValidatePreSignBlock(v.block); err != nil {
-		e.Logger.Error(err)
+		voteLog.Error(err)
 		return err
 	}
 
@@ -402,14 +433,14 @@ func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
 
 	blsSig, err := e.UserKeySet.BLSSignData(v.block.Hash().GetBytes(), selfIdx, bytelist)
 	if err != nil {
-		e.Logger.Error(err)
+		voteLog.Error(err)
 		return NewConsensusError(UnExpectedError, err)
 	}
 	bridgeSig := []byte{}
 	if metadata.HasBridgeInstructions(v.block.GetInstructions()) {
 		bridgeSig, err = e.UserKeySet.BriSignData(v.block.Hash().GetBytes())
 		if err != nil {
-			e.Logger.Error(err)
+			voteLog.Error(err)
 			return NewConsensusError(UnExpectedError, err)
 		}
 	}
@@ -422,19 +453,20 @@ func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
 	Vote.PrevBlockHash = v.block.GetPrevHash().String()
 	err = Vote.signVote(e.UserKeySet)
 	if err != nil {
-		e.Logger.Error(err)
+		voteLog.Error(err)
 		return NewConsensusError(UnExpectedError, err)
 	}
 
 	msg, err := MakeBFTVoteMsg(Vote, e.ChainKey, e.currentTimeSlot, v.block.GetHeight())
 	if err != nil {
-		e.Logger.Error(err)
+		voteLog.Error(err)
 		return NewConsensusError(UnExpectedError, err)
 	}
 
 	v.isValid = true
 	e.voteHistory[v.block.GetHeight()] = v.block
-	e.Logger.Info("sending vote...")
+	voteLog.Info("sending vote...")
+	e.metrics.votesSent.Inc(1)
 	go e.Node.PushMessageToChain(msg, e.Chain)
 	//go func() {
 	//	e.VoteMessageCh <- *Vote
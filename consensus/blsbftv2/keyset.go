@@ -106,7 +106,7 @@ func (e *BLSBFT_V2) GetUserPublicKey() *incognitokey.CommitteePublicKey {
 	return nil
 }
 
-func (e BLSBFT_V2) SignData(data []byte) (string, error) {
+func (e *BLSBFT_V2) SignData(data []byte) (string, error) {
 	result, err := e.UserKeySet.BriSignData(data) //, 0, []blsmultisig.PublicKey{e.UserKeySet.PubKey[common.BlsConsensus]})
 	if err != nil {
 		return "", NewConsensusError(SignDataError, err)
@@ -0,0 +1,28 @@
+package blsbftv2
+
+import (
+	"github.com/incognitochain/incognito-chain/metrics"
+)
+
+// bftMetrics counts the consensus rounds of a chain and the votes of its
+// validators
+type bftMetrics struct {
+	rounds        metrics.Counter // time slots the chain went through
+	proposals     metrics.Counter // blocks proposed by the node
+	votesSent     metrics.Counter
+	votesReceived metrics.Counter
+	commits       metrics.Counter // blocks committed with 2/3 of the votes
+}
+
+func newBFTMetrics(chainKey string) *bftMetrics {
+	counter := func(name string) metrics.Counter {
+		return metrics.GetOrRegisterCounter(metrics.LabeledName(name, "chain", chainKey), nil)
+	}
+	return &bftMetrics{
+		rounds:        counter("consensus/rounds"),
+		proposals:     counter("consensus/proposals"),
+		votesSent:     counter("consensus/votes/sent"),
+		votesReceived: counter("consensus/votes/received"),
+		commits:       counter("consensus/commits"),
+	}
+}
@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"fmt"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/metrics/monitor"
 	"sync"
 	"time"
@@ -32,21 +33,47 @@ type Engine struct {
 	version int
 
 	lock *sync.Mutex
+	// stateLock guards the mining state, the current mining process and
+	// IsEnabled, which WatchCommitteeChange updates
+	stateLock sync.RWMutex
 }
 
 func (engine *Engine) GetUserLayer() (string, int) {
+	engine.stateLock.RLock()
+	defer engine.stateLock.RUnlock()
 	return engine.curringMiningState.layer, engine.curringMiningState.chainID
 }
 
 func (s *Engine) GetUserRole() (string, string, int) {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
 	return s.curringMiningState.layer, s.curringMiningState.role, s.curringMiningState.chainID
 }
 
+func (engine *Engine) getCurrentMiningProcess() ConsensusInterface {
+	engine.stateLock.RLock()
+	defer engine.stateLock.RUnlock()
+	return engine.currentMiningProcess
+}
+
+func (engine *Engine) setCurrentMiningProcess(process ConsensusInterface) {
+	engine.stateLock.Lock()
+	defer engine.stateLock.Unlock()
+	engine.currentMiningProcess = process
+}
+
+func (engine *Engine) isEnabled() bool {
+	engine.stateLock.RLock()
+	defer engine.stateLock.RUnlock()
+	return engine.IsEnabled != 0
+}
+
 func (engine *Engine) IsOngoing(chainName string) bool {
-	if engine.currentMiningProcess == nil {
+	currentMiningProcess := engine.getCurrentMiningProcess()
+	if currentMiningProcess == nil {
 		return false
 	}
-	return engine.currentMiningProcess.IsOngoing()
+	return currentMiningProcess.IsOngoing()
 }
 
 //TODO: remove all places use this function
@@ -61,12 +88,13 @@ func (s *Engine) WatchCommitteeChange() {
 	}()
 
 	//check if enable
-	if s.IsEnabled == 0 || s.config == nil {
+	if !s.isEnabled() || s.config == nil {
 		return
 	}
 
 	//extract role, layer, chainID
 	role, chainID := s.config.Node.GetUserMiningState()
+	s.stateLock.Lock()
 	if s.curringMiningState.role != role || s.curringMiningState.chainID != chainID {
 		Logger.Log.Infof("Node state role: %v chainID: %v", role, chainID)
 	}
@@ -77,18 +105,26 @@ func (s *Engine) WatchCommitteeChange() {
 	if chainID == -2 {
 		s.curringMiningState.role = ""
 		s.curringMiningState.layer = ""
+	} else if chainID == -1 {
+		s.curringMiningState.layer = "beacon"
+	} else if chainID >= 0 {
+		s.curringMiningState.layer = "shard"
+	} else {
+		s.stateLock.Unlock()
+		panic("User Mining State Error")
+	}
+	miningState := s.curringMiningState
+	s.stateLock.Unlock()
+
+	if chainID == -2 {
 		s.NotifyBeaconRole(false)
 		s.NotifyShardRole(-2)
 	} else if chainID == -1 {
-		s.curringMiningState.layer = "beacon"
 		s.NotifyBeaconRole(true)
 		s.NotifyShardRole(-1)
-	} else if chainID >= 0 {
-		s.curringMiningState.layer = "shard"
+	} else {
 		s.NotifyBeaconRole(false)
 		s.NotifyShardRole(chainID)
-	} else {
-		panic("User Mining State Error")
 	}
 
 	for _, BFTProcess := range s.BFTProcess {
@@ -97,8 +133,8 @@ func (s *Engine) WatchCommitteeChange() {
 		}
 	}
 
-	monitor.SetGlobalParam("Role", s.curringMiningState.role)
-	monitor.SetGlobalParam("Layer", s.curringMiningState.layer)
+	monitor.SetGlobalParam("Role", miningState.role)
+	monitor.SetGlobalParam("Layer", miningState.layer)
 
 	var miningProcess ConsensusInterface = nil
 	//TODO: optimize - if in pending start to listen propose block, but not vote
@@ -130,15 +166,17 @@ func (s *Engine) WatchCommitteeChange() {
 			}
 		}
 
-		s.BFTProcess[chainID].Start()
 		miningProcess = s.BFTProcess[chainID]
-		s.currentMiningProcess = s.BFTProcess[chainID]
-		if err := s.LoadMiningKeys(s.userKeyListString); err != nil {
-			panic(err)
+		s.setCurrentMiningProcess(miningProcess)
+		// the keys are loaded before the process starts using them
+		if miningProcess.GetUserPublicKey() == nil {
+			if err := s.LoadMiningKeys(s.userKeyListString); err != nil {
+				panic(err)
+			}
 		}
-
+		miningProcess.Start()
 	}
-	s.currentMiningProcess = miningProcess
+	s.setCurrentMiningProcess(miningProcess)
 }
 
 func NewConsensusEngine() *Engine {
@@ -162,11 +200,16 @@ func (engine *Engine) initProcess(chainID int, chainName string) {
 			engine.BFTProcess[chainID] = blsbft.NewInstance(engine.config.Blockchain.ShardChain[chainID], chainName, chainID, engine.config.Node, Logger.Log)
 		}
 	} else {
+		var process *blsbft2.BLSBFT_V2
 		if chainID == -1 {
-			engine.BFTProcess[chainID] = blsbft2.NewInstance(engine.config.Blockchain.BeaconChain, chainName, chainID, engine.config.Node, Logger.Log)
+			process = blsbft2.NewInstance(engine.config.Blockchain.BeaconChain, chainName, chainID, engine.config.Node, Logger.Log)
 		} else {
-			engine.BFTProcess[chainID] = blsbft2.NewInstance(engine.config.Blockchain.ShardChain[chainID], chainName, chainID, engine.config.Node, Logger.Log)
+			process = blsbft2.NewInstance(engine.config.Blockchain.ShardChain[chainID], chainName, chainID, engine.config.Node, Logger.Log)
+		}
+		if engine.config.Clock != nil {
+			process.Clock = engine.config.Clock
 		}
+		engine.BFTProcess[chainID] = process
 	}
 }
 
@@ -178,7 +221,7 @@ func (engine *Engine) updateVersion(chainID int) {
 		chainEpoch = engine.config.Blockchain.ShardChain[chainID].GetEpoch()
 	}
 
-	if chainEpoch >= engine.config.Blockchain.GetConfig().ChainParams.ConsensusV2Epoch {
+	if engine.config.Blockchain.GetConfig().ChainParams.IsForkActiveAtEpoch(forks.ConsensusV2, chainEpoch) {
 		engine.version = 2
 	}
 }
@@ -203,12 +246,16 @@ func (engine *Engine) Start() error {
 	if err != nil {
 		panic(err)
 	}
+	engine.stateLock.Lock()
 	engine.IsEnabled = 1
+	engine.stateLock.Unlock()
 	return nil
 }
 
 func (engine *Engine) Stop() error {
 	Logger.Log.Infof("CONSENSUS: Stop")
+	engine.stateLock.Lock()
+	defer engine.stateLock.Unlock()
 	for _, BFTProcess := range engine.BFTProcess {
 		BFTProcess.Stop()
 		engine.currentMiningProcess = nil
@@ -218,12 +265,13 @@ func (engine *Engine) Stop() error {
 }
 
 func (engine *Engine) OnBFTMsg(msg *wire.MessageBFT) {
-	if engine.currentMiningProcess == nil {
+	currentMiningProcess := engine.getCurrentMiningProcess()
+	if currentMiningProcess == nil {
 		Logger.Log.Warnf("Current mining process still nil")
 		return
 	}
-	if engine.currentMiningProcess.GetChainKey() == msg.ChainKey {
-		engine.currentMiningProcess.ProcessBFTMsg(msg)
+	if currentMiningProcess.GetChainKey() == msg.ChainKey {
+		currentMiningProcess.ProcessBFTMsg(msg)
 	}
 }
 
@@ -13,6 +13,8 @@ type EngineConfig struct {
 	Node          NodeInterface
 	Blockchain    *blockchain.BlockChain
 	PubSubManager *pubsub.PubSubManager
+	// Clock gives the time slots of bls-bft v2, it is the system clock when nil
+	Clock common.Clock
 }
 
 type NodeInterface interface {
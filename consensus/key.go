@@ -10,6 +10,7 @@ import (
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/consensus/blsbft"
 	"github.com/incognitochain/incognito-chain/consensus/blsbftv2"
+	"github.com/incognitochain/incognito-chain/consensus/signatureschemes/blsmultisig"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 )
 
@@ -26,15 +27,13 @@ func (engine *Engine) LoadMiningKeys(keysString string) error {
 					keyConsensus = keyParts[1]
 				}
 
-				var f ConsensusInterface
-				if engine.currentMiningProcess == nil {
+				f := engine.getCurrentMiningProcess()
+				if f == nil {
 					if engine.version == 1 {
 						f = &blsbft.BLSBFT{}
 					} else {
 						f = &blsbftv2.BLSBFT_V2{}
 					}
-				} else {
-					f = engine.currentMiningProcess
 				}
 
 				err := f.LoadUserKey(keyConsensus)
@@ -59,8 +58,8 @@ func (engine *Engine) GetCurrentMiningPublicKey() (publickey string, keyType str
 
 func (engine *Engine) GetMiningPublicKeyByConsensus(consensusName string) (publickey string, err error) {
 	keyBytes := map[string][]byte{}
-	if engine != nil && engine.currentMiningProcess != nil {
-		keytype := engine.currentMiningProcess.GetConsensusName()
+	if engine != nil && engine.getCurrentMiningProcess() != nil {
+		keytype := engine.getCurrentMiningProcess().GetConsensusName()
 		lightweightKey, exist := engine.GetMiningPublicKeys().MiningPubKey[common.BridgeConsensus]
 		if !exist {
 			return "", blsbft.NewConsensusError(blsbft.LoadKeyError, errors.New("Lightweight key not found"))
@@ -116,13 +115,16 @@ func (engine *Engine) SignDataWithCurrentMiningKey(
 	publicKeyStr = ""
 	publicKeyType = ""
 	signature = ""
-	if engine != nil && engine.currentMiningProcess != nil {
+	if engine == nil {
+		return
+	}
+	if currentMiningProcess := engine.getCurrentMiningProcess(); currentMiningProcess != nil {
 		publicKeyType = engine.config.Blockchain.GetBeaconBestState().ConsensusAlgorithm
 		publicKeyStr, err = engine.GetMiningPublicKeyByConsensus(publicKeyType)
 		if err != nil {
 			return
 		}
-		signature, err = engine.currentMiningProcess.SignData(data)
+		signature, err = currentMiningProcess.SignData(data)
 	}
 	return
 }
@@ -133,7 +135,7 @@ func (engine *Engine) VerifyData(data []byte, sig string, publicKey string, cons
 	if err != nil {
 		return blsbft.NewConsensusError(blsbft.LoadKeyError, err)
 	}
-	return engine.currentMiningProcess.ValidateData(data, sig, string(mapPublicKey[common.BridgeConsensus]))
+	return engine.getCurrentMiningProcess().ValidateData(data, sig, string(mapPublicKey[common.BridgeConsensus]))
 }
 
 func (engine *Engine) ValidateProducerPosition(blk common.BlockInterface, lastProposerIdx int, committee []incognitokey.CommitteePublicKey, minCommitteeSize int) error {
@@ -191,6 +193,48 @@ func (engine *Engine) ValidateBlockCommitteSig(block common.BlockInterface, comm
 	return fmt.Errorf("Wrong block version: %v", block.GetVersion())
 }
 
+// BatchValidateBlockCommitteeSig validates the committee signatures of
+// blocks signed by the same committee at once
+func (engine *Engine) BatchValidateBlockCommitteeSig(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	committeeBLSKeys := []blsmultisig.PublicKey{}
+	for _, member := range committee {
+		committeeBLSKeys = append(committeeBLSKeys, member.MiningPubKey[common.BlsConsensus])
+	}
+	sigs, hashes, validatorsIdx, committees := [][]byte{}, [][]byte{}, [][]int{}, [][]blsmultisig.PublicKey{}
+	for _, block := range blocks {
+		var aggSig []byte
+		var idx []int
+		switch block.GetVersion() {
+		case 1:
+			valData, err := blsbft.DecodeValidationData(block.GetValidationField())
+			if err != nil {
+				return err
+			}
+			aggSig, idx = valData.AggSig, valData.ValidatiorsIdx
+		case 2:
+			valData, err := blsbftv2.DecodeValidationData(block.GetValidationField())
+			if err != nil {
+				return err
+			}
+			aggSig, idx = valData.AggSig, valData.ValidatiorsIdx
+		default:
+			return fmt.Errorf("Wrong block version: %v", block.GetVersion())
+		}
+		sigs = append(sigs, aggSig)
+		hashes = append(hashes, block.Hash().GetBytes())
+		validatorsIdx = append(validatorsIdx, idx)
+		committees = append(committees, committeeBLSKeys)
+	}
+	ok, err := blsmultisig.BatchVerify(sigs, hashes, validatorsIdx, committees)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("Invalid committee signature in batch")
+	}
+	return nil
+}
+
 func (engine *Engine) GenMiningKeyFromPrivateKey(privateKey string) (string, error) {
 	var keyList string
 	var key string
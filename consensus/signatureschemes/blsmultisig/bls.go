@@ -2,6 +2,7 @@ package blsmultisig
 
 import (
 	"bytes"
+	"crypto/rand"
 	"errors"
 	"math/big"
 	"sync"
@@ -99,6 +100,69 @@ func Verify(sig, data []byte, signersIdx []int, committee []PublicKey) (bool, er
 	return true, nil
 }
 
+// BatchVerify verifies at once the aggregated BLS sigs on datas, signed by
+// the signersIdx of committees. It checks
+//   e(sum(r_i * sig_i), g2) = prod(e(r_i * H(data_i), apk_i))
+// for random r_i, with a single final exponentiation, so it is much faster
+// than Verify on each signature. It returns false if any signature is
+// invalid, without telling which one.
+func BatchVerify(sigs, datas [][]byte, signersIdx [][]int, committees [][]PublicKey) (bool, error) {
+	if len(datas) != len(sigs) || len(signersIdx) != len(sigs) || len(committees) != len(sigs) {
+		return false, NewBLSSignatureError(InvalidInputParamsSizeErr, errors.New(ErrCodeMessage[InvalidInputParamsSizeErr].Message))
+	}
+	if len(sigs) == 0 {
+		return true, nil
+	}
+	for i := range sigs {
+		for _, idx := range signersIdx[i] {
+			if (idx < 0) || (idx >= len(committees[i])) {
+				return false, NewBLSSignatureError(InvalidCommitteeInfoErr, errors.New(ErrCodeMessage[InvalidCommitteeInfoErr].Message))
+			}
+		}
+		if len(signersIdx[i]) < 1 || len(signersIdx[i]) > len(committees[i]) {
+			return false, NewBLSSignatureError(InvalidCommitteeInfoErr, errors.New(ErrCodeMessage[InvalidCommitteeInfoErr].Message))
+		}
+	}
+
+	sumSig := new(bn256.G1)
+	sumSig.ScalarBaseMult(big.NewInt(0))
+	// signatures of the same signers share their pairing
+	dataPnByApk := map[string]*bn256.G1{}
+	apks := map[string]*bn256.G2{}
+	for i := range sigs {
+		r, err := rand.Int(rand.Reader, bn256.Order)
+		if err != nil {
+			return false, err
+		}
+		sigPn, err := DecmprG1(sigs[i])
+		if err != nil {
+			return false, NewBLSSignatureError(DecompressFromByteErr, err)
+		}
+		sumSig.Add(sumSig, sigPn.ScalarMult(sigPn, r))
+
+		apk := APKGen(committees[i], signersIdx[i])
+		key := string(apk.Marshal())
+		dataPn := B2G1P(datas[i])
+		dataPn.ScalarMult(dataPn, r)
+		if sum, ok := dataPnByApk[key]; ok {
+			sum.Add(sum, dataPn)
+		} else {
+			dataPnByApk[key] = dataPn
+			apks[key] = apk
+		}
+	}
+
+	gG2Pn := new(bn256.G2)
+	gG2Pn.ScalarBaseMult(big.NewInt(1))
+	g1Pns := []*bn256.G1{new(bn256.G1).Neg(sumSig)}
+	g2Pns := []*bn256.G2{gG2Pn}
+	for key, dataPn := range dataPnByApk {
+		g1Pns = append(g1Pns, dataPn)
+		g2Pns = append(g2Pns, apks[key])
+	}
+	return bn256.PairingCheck(g1Pns, g2Pns), nil
+}
+
 // Verify verify BLS sig on given data and list public key
 // func SingleVerify(sig, data []byte, signersIdx []int, committee []PublicKey) (bool, error) {
 // 	// if len(skBytes) != CSKSz {
@@ -294,3 +294,51 @@ func Test_SpecialCase(t *testing.T) {
 	// }
 	fmt.Println(Verify(sig, blkHash, []int{1, 2, 3}, committee))
 }
+
+func Test_BatchVerify(t *testing.T) {
+	committeeSize := 8
+	err := genKey([]byte{5, 6, 7, 8}, committeeSize)
+	assert.Nil(t, err)
+
+	sigs, datas, subsets, committees := [][]byte{}, [][]byte{}, [][]int{}, [][]PublicKey{}
+	for i := 0; i < 6; i++ {
+		data := common.HashB([]byte{byte(i)})
+		// two batches share their signers
+		subset := genSubset4Test(committeeSize-i%3, committeeSize)
+		partSigs, err := sign(data, subset)
+		assert.Nil(t, err)
+		cSig, err := combine(partSigs)
+		assert.Nil(t, err)
+		sigs = append(sigs, cSig)
+		datas = append(datas, data)
+		subsets = append(subsets, subset)
+		committees = append(committees, listPKsBytes)
+	}
+
+	result, err := BatchVerify(sigs, datas, subsets, committees)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = BatchVerify(nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	// a signature on other data
+	badSigs := append([][]byte{}, sigs...)
+	badSigs[2], badSigs[3] = sigs[3], sigs[2]
+	result, err = BatchVerify(badSigs, datas, subsets, committees)
+	assert.Nil(t, err)
+	assert.False(t, result)
+
+	// a signer left out
+	badSubsets := append([][]int{}, subsets...)
+	badSubsets[4] = subsets[4][1:]
+	result, err = BatchVerify(sigs, datas, badSubsets, committees)
+	assert.Nil(t, err)
+	assert.False(t, result)
+
+	_, err = BatchVerify(sigs, datas[1:], subsets, committees)
+	assert.NotNil(t, err)
+	_, err = BatchVerify(sigs[:1], datas[:1], [][]int{{committeeSize}}, committees[:1])
+	assert.NotNil(t, err)
+}
@@ -34,7 +34,8 @@ func PKGen(sk *big.Int) *bn256.G2 {
 	return pk
 }
 
-var memCache *memoryCache
+// memCache is created once, AKGen is called by several goroutines at a time
+var memCache = New()
 
 // AKGen take a seed and return BLS secret key
 func AKGen(idxPKByte []byte, combinedPKBytes []byte) (*bn256.G2, *big.Int) {
@@ -48,9 +49,6 @@ func AKGen(idxPKByte []byte, combinedPKBytes []byte) (*bn256.G2, *big.Int) {
 	akBInt := B2I(akByte)
 
 	// cache pkPn
-	if memCache == nil {
-		memCache = New()
-	}
 	cachedResult, err := memCache.get(akByte)
 	if err == nil {
 		return &cachedResult, akBInt
@@ -38,6 +38,48 @@ var (
 	splitter                           = []byte("-[-]-")
 )
 
+// SchemaPrefix names a key prefix of the schema, it is used to report storage usage by prefix
+type SchemaPrefix struct {
+	Name   string
+	Prefix []byte
+}
+
+// GetSchemaPrefixes returns all key prefixes of the schema,
+// keys without any of these prefixes are state trie nodes
+func GetSchemaPrefixes() []SchemaPrefix {
+	return []SchemaPrefix{
+		{"LastShardBlock", lastShardBlockKey},
+		{"LastBeaconBlock", lastBeaconBlockKey},
+		{"BeaconViews", beaconViewsPrefix},
+		{"ShardViews", shardBestStatePrefix},
+		{"ShardHashToBlock", shardHashToBlockPrefix},
+		{"View", viewPrefix},
+		{"ShardIndexToBlockHash", shardIndexToBlockHashPrefix},
+		{"ShardBlockHashToIndex", shardBlockHashToIndexPrefix},
+		{"BeaconHashToBlock", beaconHashToBlockPrefix},
+		{"BeaconIndexToBlockHash", beaconIndexToBlockHashPrefix},
+		{"BeaconBlockHashToIndex", beaconBlockHashToIndexPrefix},
+		{"TxHash", txHashPrefix},
+		{"CrossShardNextHeight", crossShardNextHeightPrefix},
+		{"LastBeaconHeightConfirmCrossShard", lastBeaconHeightConfirmCrossShard},
+		{"FeeEstimator", feeEstimatorPrefix},
+		{"TxByPublicKey", txByPublicKeyPrefix},
+		{"RootHash", rootHashPrefix},
+		{"ShardRootHash", shardRootHashPrefix},
+		{"BeaconRootHash", beaconRootHashPrefix},
+		{"BeaconConsensusRootHash", beaconConsensusRootHashPrefix},
+		{"BeaconRewardRequestRootHash", beaconRewardRequestRootHashPrefix},
+		{"BeaconFeatureRootHash", beaconFeatureRootHashPrefix},
+		{"BeaconSlashRootHash", beaconSlashRootHashPrefix},
+		{"ShardCommitteeRewardRootHash", shardCommitteeRewardRootHashPrefix},
+		{"ShardConsensusRootHash", shardConsensusRootHashPrefix},
+		{"ShardTransactionRootHash", shardTransactionRootHashPrefix},
+		{"ShardSlashRootHash", shardSlashRootHashPrefix},
+		{"ShardFeatureRootHash", shardFeatureRootHashPrefix},
+		{"PreviousBestState", previousBestStatePrefix},
+	}
+}
+
 func GetLastShardBlockKey(shardID byte) []byte {
 	temp := make([]byte, 0, len(lastShardBlockKey))
 	temp = append(temp, lastShardBlockKey...)
@@ -74,13 +74,18 @@ type StateDB struct {
 //	}, nil
 //}
 
+// the hashing time of the statedbs is measured, set once since several chains
+// open statedbs at a time
+func init() {
+	metrics.EnabledExpensive = true
+}
+
 // New return a new statedb attach with a state root
 func NewWithPrefixTrie(root common.Hash, db DatabaseAccessWarper) (*StateDB, error) {
 	tr, err := db.OpenPrefixTrie(root)
 	if err != nil {
 		return nil, err
 	}
-	metrics.EnabledExpensive = true
 	return &StateDB{
 		db:                  db,
 		trie:                tr,
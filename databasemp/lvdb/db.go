@@ -4,10 +4,12 @@ import (
 	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 type db struct {
-	lvdb *leveldb.DB
+	dbPath string
+	lvdb   *leveldb.DB
 }
 
 func open(dbPath string) (databasemp.DatabaseInterface, error) {
@@ -15,7 +17,32 @@ func open(dbPath string) (databasemp.DatabaseInterface, error) {
 	if err != nil {
 		return nil, databasemp.NewDatabaseMempoolError(databasemp.OpenDbErr, errors.Wrapf(err, "levelvdb.OpenFile %s", dbPath))
 	}
-	return &db{lvdb: lvdb}, nil
+	return &db{dbPath: dbPath, lvdb: lvdb}, nil
+}
+
+// Path returns the path to the database directory.
+func (db *db) Path() string {
+	return db.dbPath
+}
+
+// Stat returns a particular internal stat of the database.
+func (db *db) Stat(property string) (string, error) {
+	return db.lvdb.GetProperty(property)
+}
+
+// ApproximateSize returns the approximate file system space used by keys in
+// range [start, limit).
+func (db *db) ApproximateSize(start []byte, limit []byte) (uint64, error) {
+	sizes, err := db.lvdb.SizeOf([]util.Range{{Start: start, Limit: limit}})
+	if err != nil {
+		return 0, err
+	}
+	return uint64(sizes.Sum()), nil
+}
+
+// Compact flattens the underlying data store for the given key range.
+func (db *db) Compact(start []byte, limit []byte) error {
+	return db.lvdb.CompactRange(util.Range{Start: start, Limit: limit})
 }
 
 func (db *db) Close() error {
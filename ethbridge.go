@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/relaying/ethbridge"
+	"github.com/incognitochain/incognito-chain/rpcserver"
+)
+
+const relayerDialTimeout = 30 * time.Second
+
+// checkRelayerConfig checks the flags of the bridge relayer, it is enabled
+// by --relayerethurl
+func checkRelayerConfig(cfg *config) error {
+	if cfg.RelayerEthURL == "" {
+		return nil
+	}
+	if cfg.NodeMode == common.NodeModeLight {
+		return errors.New("the bridge relayer needs the blocks of a full node")
+	}
+	if cfg.RelayerKeyFile == "" {
+		return errors.New("--relayerethurl requires --relayerkeyfile")
+	}
+	for flag, addr := range map[string]string{"relayervault": cfg.RelayerVault, "relayerproxy": cfg.RelayerProxy} {
+		if !ethcommon.IsHexAddress(addr) {
+			return fmt.Errorf("--relayerethurl requires a valid --%s, got %q", flag, addr)
+		}
+	}
+	return nil
+}
+
+// newBridgeRelayer returns the relayer submitting the bridge proofs of bc to
+// the Ethereum node of --relayerethurl
+func newBridgeRelayer(bc *blockchain.BlockChain, ce rpcserver.ConsensusEngine) (*ethbridge.Relayer, error) {
+	key, err := crypto.LoadECDSA(common.CleanAndExpandPath(cfg.RelayerKeyFile, defaultHomeDir))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load relayer key: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), relayerDialTimeout)
+	defer cancel()
+	client, err := ethclient.DialContext(ctx, cfg.RelayerEthURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s: %v", cfg.RelayerEthURL, err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get chain id of %s: %v", cfg.RelayerEthURL, err)
+	}
+
+	transactorConfig := ethbridge.DefaultTransactorConfig
+	if cfg.RelayerMaxGasPrice > 0 {
+		transactorConfig.MaxGasPrice = new(big.Int).Mul(new(big.Int).SetUint64(cfg.RelayerMaxGasPrice), big.NewInt(1e9))
+	}
+	transactor := ethbridge.NewEthTransactor(client, key, chainID, transactorConfig)
+	Logger.log.Infof("Bridge relayer sends from %s on chain %v", transactor.From().Hex(), chainID)
+	return ethbridge.NewRelayer(ethbridge.Config{
+		Source:      rpcserver.NewBridgeProofSource(bc, ce),
+		Transactor:  transactor,
+		Vault:       ethcommon.HexToAddress(cfg.RelayerVault),
+		Proxy:       ethcommon.HexToAddress(cfg.RelayerProxy),
+		StartHeight: cfg.RelayerStartHeight,
+		StateFile:   filepath.Join(cfg.DataDir, "ethbridge-relayer"),
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metrics/health"
+	"github.com/incognitochain/incognito-chain/syncker"
+)
+
+// healthKey is read from the databases by their healthcheck
+var healthKey = []byte("healthcheck")
+
+// registerHealthchecks registers the checks of /healthz, /readyz and the
+// getsyncstatus RPC: the node is live while it reaches its databases, it is
+// ready while it reaches a highway and its chains are in sync with its peers.
+// A highway outage is not a fault of the node, restarting it would not help.
+func (serverObj *Server) registerHealthchecks() {
+	if serverObj.highway != nil {
+		highway := serverObj.highway
+		health.Register(nil, health.ReadyPrefix+"highway", func() error {
+			if highway.IsHighwayConnected() || (highway.IsDirect() && highway.PeerCount() > 0) {
+				return nil
+			}
+			return errors.New("not connected to a highway")
+		})
+	}
+	for chainID, db := range serverObj.dataBase {
+		db := db
+		health.Register(nil, health.LivePrefix+"db/"+chainKey(chainID), func() error {
+			_, err := db.Has(healthKey)
+			return err
+		})
+	}
+	if cfg.NodeMode == common.NodeModeLight {
+		return
+	}
+
+	chainStatus := func(chainID int) (syncker.ChainSyncStatus, bool) {
+		for _, status := range serverObj.syncker.GetChainSyncStatus() {
+			if status.ChainID == chainID {
+				return status, true
+			}
+		}
+		return syncker.ChainSyncStatus{}, false
+	}
+	for _, chainID := range append([]int{common.BeaconChainID}, shardIDs(serverObj.chainParams.ActiveShards)...) {
+		chainID := chainID
+		health.Register(nil, health.ReadyPrefix+"sync/"+chainKey(chainID), func() error {
+			status, ok := chainStatus(chainID)
+			if !ok {
+				return errors.New("chain is not synced")
+			}
+			return checkChainSync(status, cfg.ReadyMaxSyncLag, cfg.ReadyMaxBlockAge, time.Now())
+		})
+	}
+}
+
+// checkChainSync returns an error when a chain is more than maxLag blocks
+// behind its peers or its best block is older than maxBlockAge, 0 disables a
+// threshold. Only the beacon chain must have peers, the node does not follow
+// the shards none of its peers announce.
+func checkChainSync(status syncker.ChainSyncStatus, maxLag uint64, maxBlockAge time.Duration, now time.Time) error {
+	if status.PeerHeight == 0 {
+		if status.ChainID == common.BeaconChainID {
+			return errors.New("no peer announced the chain")
+		}
+		return nil
+	}
+	if maxLag > 0 && status.Lag > maxLag {
+		return fmt.Errorf("%v blocks behind peers", status.Lag)
+	}
+	if age := now.Sub(time.Unix(status.LastBlockTime, 0)); maxBlockAge > 0 && age > maxBlockAge {
+		return fmt.Errorf("last block is %v old", age.Truncate(time.Second))
+	}
+	return nil
+}
+
+func chainKey(chainID int) string {
+	if chainID == common.BeaconChainID {
+		return common.BeaconChainKey
+	}
+	return common.GetShardChainKey(byte(chainID))
+}
+
+func shardIDs(activeShards int) []int {
+	res := []int{}
+	for shardID := 0; shardID < activeShards; shardID++ {
+		res = append(res, shardID)
+	}
+	return res
+}
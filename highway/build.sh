@@ -0,0 +1,11 @@
+echo "Start build highway"
+
+echo "go get"
+go get -d
+
+APP_NAME="incognito-highway"
+
+echo "go build -o $APP_NAME"
+go build -o $APP_NAME
+
+echo "Build highway success!"
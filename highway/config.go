@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// See loadConfig for details on the configuration load process.
+type config struct {
+	Listen         string `long:"listen" short:"l" description:"Libp2p listen address of the nodes, ip:port"`
+	PublicIP       string `long:"publicip" description:"IP given to the nodes, the first address of the host when empty"`
+	PrivateKey     string `long:"libp2pprivatekey" description:"Private key of the highway PeerID, empty to generate random key each run"`
+	DiscoverListen string `long:"discoverlisten" short:"p" description:"Listen address of the discover RPC, the --discoverpeersaddress of the nodes"`
+	NumShards      int    `long:"numshards" description:"Number of shards of the network"`
+}
+
+// newConfigParser returns a new command line flags parser.
+func newConfigParser(cfg *config, options flags.Options) *flags.Parser {
+	parser := flags.NewParser(cfg, options)
+	return parser
+}
+
+// loadConfig
+// - set default config
+// - read config from cmd line params
+// - return config object
+func loadConfig() (*config, error) {
+	// create config object from default values
+	cfg := config{
+		Listen:         defaultListen,
+		DiscoverListen: defaultDiscoverListen,
+		NumShards:      defaultNumShards,
+	}
+
+	preParser := newConfigParser(&cfg, flags.HelpFlag)
+	_, err := preParser.Parse()
+	if err != nil {
+		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,8 @@
+package main
+
+const (
+	version               = "0.1.0"
+	defaultListen         = "0.0.0.0:7337"
+	defaultDiscoverListen = "0.0.0.0:9330"
+	defaultNumShards      = 8
+)
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighwayLoadConfig(t *testing.T) {
+	config, err := loadConfig()
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, nil, config)
+	assert.Equal(t, defaultDiscoverListen, config.DiscoverListen)
+	assert.Equal(t, defaultNumShards, config.NumShards)
+}
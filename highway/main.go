@@ -0,0 +1,50 @@
+//+build !test
+
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/incognitochain/incognito-chain/highway/server"
+)
+
+// Highway is a minimal highway for private networks and CI: the nodes find it
+// through their --discoverpeersaddress, register to it and exchange their
+// messages and blocks through it
+func main() {
+	// Show Version at startup.
+	log.Printf("Version %s\n", version)
+
+	// Load config
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Println("Parse config error", err.Error())
+		return
+	}
+
+	hw, err := server.New(server.Config{
+		Listen:         cfg.Listen,
+		PublicIP:       cfg.PublicIP,
+		PrivateKey:     cfg.PrivateKey,
+		DiscoverListen: cfg.DiscoverListen,
+		NumShards:      cfg.NumShards,
+	})
+	if err != nil {
+		log.Println("Create highway error", err.Error())
+		return
+	}
+	if err := hw.Start(); err != nil {
+		log.Println("Start highway error", err.Error())
+		hw.Stop()
+		return
+	}
+	log.Printf("Highway %s, discover rpc %s\n", hw.Libp2pAddr(), hw.DiscoverAddr())
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	<-interrupt
+	hw.Stop()
+}
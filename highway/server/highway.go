@@ -0,0 +1,385 @@
+// Package server is a minimal highway: it hands out the pubsub topics of the
+// nodes in answer to their Register calls, relays the topics and forwards the
+// block requests of a node to the nodes which keep the blocks. It lets a private
+// network or a CI run without the separate highway project.
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/rpc"
+	"sync"
+
+	p2pgrpc "github.com/incognitochain/go-libp2p-grpc"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	grpcpeer "google.golang.org/grpc/peer"
+)
+
+type Config struct {
+	Listen         string // libp2p listen address, ip:port
+	PublicIP       string // ip given to the nodes, the first address of the host when empty
+	PrivateKey     string // libp2p private key encoded as --libp2pprivatekey of a node, a new key when empty
+	DiscoverListen string // listen address of the Handler.GetPeers rpc of peerv2.AddrKeeper, ip:port
+	NumShards      int
+}
+
+// registration is the last Register request of a node
+type registration struct {
+	pubkey       string
+	role         string
+	committeeIDs []byte
+}
+
+type Highway struct {
+	config Config
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	host     host.Host
+	grpc     *p2pgrpc.GRPCProtocol
+	ps       *pubsub.PubSub
+	listener net.Listener
+
+	mtx       sync.RWMutex
+	nodes     map[peer.ID]*registration
+	topics    map[string]*pubsub.Subscription
+	committee *incognitokey.ChainCommittee
+
+	connsMtx sync.Mutex
+	conns    map[peer.ID]*grpc.ClientConn
+
+	stopOnce sync.Once
+}
+
+func New(config Config) (*Highway, error) {
+	var privKey crypto.PrivKey
+	var err error
+	if config.PrivateKey == "" {
+		privKey, _, err = crypto.GenerateKeyPair(crypto.ECDSA, 2048)
+	} else {
+		var b []byte
+		if b, err = crypto.ConfigDecodeKey(config.PrivateKey); err == nil {
+			privKey, err = crypto.UnmarshalPrivateKey(b)
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid libp2p private key")
+	}
+	ip, port := peerv2.ParseListenner(config.Listen, "127.0.0.1", 0)
+	listenAddr, err := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", ip, port))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid listen address %v", config.Listen)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p2pHost, err := libp2p.New(ctx,
+		libp2p.ConnectionManager(nil),
+		libp2p.ListenAddrs(listenAddr),
+		libp2p.Identity(privKey),
+	)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "create libp2p host")
+	}
+	// NOTE: the nodes use floodsub, the highway must subscribe to a topic to relay it
+	ps, err := pubsub.NewFloodSub(ctx, p2pHost)
+	if err != nil {
+		cancel()
+		p2pHost.Close()
+		return nil, errors.Wrap(err, "create pubsub")
+	}
+
+	hw := &Highway{
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+		host:   p2pHost,
+		grpc:   p2pgrpc.NewGRPCProtocol(ctx, p2pHost),
+		ps:     ps,
+		nodes:  map[peer.ID]*registration{},
+		topics: map[string]*pubsub.Subscription{},
+		conns:  map[peer.ID]*grpc.ClientConn{},
+	}
+	p2pHost.Network().Notify(&network.NotifyBundle{
+		DisconnectedF: func(_ network.Network, conn network.Conn) {
+			hw.disconnected(conn.RemotePeer())
+		},
+	})
+	return hw, nil
+}
+
+// Start serves the Register rpc and the block requests of the nodes, relays the
+// committee broadcasts and starts the discover rpc when Config.DiscoverListen is set
+func (hw *Highway) Start() error {
+	proto.RegisterHighwayServiceServer(hw.grpc.GetGRPCServer(), &service{hw: hw})
+	go hw.grpc.Serve() // NOTE: must serve after registering all services
+
+	if err := hw.subscribe(committeeTopic); err != nil {
+		return err
+	}
+
+	if hw.config.DiscoverListen == "" {
+		return nil
+	}
+	server := rpc.NewServer()
+	if err := server.Register(&Handler{hw: hw}); err != nil {
+		return errors.WithStack(err)
+	}
+	listener, err := net.Listen("tcp", hw.config.DiscoverListen)
+	if err != nil {
+		return errors.Wrapf(err, "listen %v", hw.config.DiscoverListen)
+	}
+	hw.listener = listener
+	go server.Accept(listener)
+	return nil
+}
+
+// Stop closes the connections of the highway, the nodes look for a highway again
+func (hw *Highway) Stop() {
+	hw.stopOnce.Do(func() {
+		if hw.listener != nil {
+			hw.listener.Close()
+		}
+		hw.cancel()
+		hw.grpc.GetGRPCServer().Stop()
+		hw.connsMtx.Lock()
+		for pid, conn := range hw.conns {
+			conn.Close()
+			delete(hw.conns, pid)
+		}
+		hw.connsMtx.Unlock()
+		if err := hw.host.Close(); err != nil {
+			log.Printf("Close highway host: %v", err)
+		}
+	})
+}
+
+func (hw *Highway) PeerID() peer.ID {
+	return hw.host.ID()
+}
+
+// Libp2pAddr returns the address the nodes connect to
+func (hw *Highway) Libp2pAddr() string {
+	addrs := hw.host.Addrs()
+	if len(addrs) == 0 {
+		return ""
+	}
+	if hw.config.PublicIP != "" {
+		port, _ := addrs[0].ValueForProtocol(multiaddr.P_TCP)
+		return fmt.Sprintf("/ip4/%s/tcp/%s/p2p/%s", hw.config.PublicIP, port, hw.host.ID().Pretty())
+	}
+	return fmt.Sprintf("%s/p2p/%s", addrs[0], hw.host.ID().Pretty())
+}
+
+// DiscoverAddr returns the address of the discover rpc, the --discoverpeersaddress of the nodes
+func (hw *Highway) DiscoverAddr() string {
+	if hw.listener == nil {
+		return ""
+	}
+	return hw.listener.Addr().String()
+}
+
+// Topics returns the topics relayed by the highway
+func (hw *Highway) Topics() []string {
+	hw.mtx.RLock()
+	defer hw.mtx.RUnlock()
+	topics := []string{}
+	for topic := range hw.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// DropTopic stops relaying topic until a node registers for it again, as a
+// highway which lost its subscriptions
+func (hw *Highway) DropTopic(topic string) {
+	hw.mtx.Lock()
+	defer hw.mtx.Unlock()
+	if sub, ok := hw.topics[topic]; ok {
+		sub.Cancel()
+		delete(hw.topics, topic)
+	}
+}
+
+// Committee returns the last committee broadcast by a master node, nil if none
+func (hw *Highway) Committee() *incognitokey.ChainCommittee {
+	hw.mtx.RLock()
+	defer hw.mtx.RUnlock()
+	return hw.committee
+}
+
+// subscribe joins topic so the highway relays it
+func (hw *Highway) subscribe(topic string) error {
+	hw.mtx.Lock()
+	defer hw.mtx.Unlock()
+	if _, ok := hw.topics[topic]; ok {
+		return nil
+	}
+	sub, err := hw.ps.Subscribe(topic)
+	if err != nil {
+		return errors.Wrapf(err, "subscribe %v", topic)
+	}
+	hw.topics[topic] = sub
+	go hw.readTopic(topic, sub)
+	return nil
+}
+
+// readTopic drains the messages of a subscription, only the committee
+// broadcasts are read by the highway itself
+func (hw *Highway) readTopic(topic string, sub *pubsub.Subscription) {
+	for {
+		msg, err := sub.Next(hw.ctx)
+		if err != nil { // stopped or dropped
+			return
+		}
+		if topic != committeeTopic {
+			continue
+		}
+		committee, err := incognitokey.ChainCommitteeFromByte(msg.Data)
+		if err != nil {
+			log.Printf("Invalid committee from %v: %v", msg.GetFrom().Pretty(), err)
+			continue
+		}
+		hw.mtx.Lock()
+		if hw.committee == nil || committee.Epoch >= hw.committee.Epoch {
+			hw.committee = committee
+		}
+		hw.mtx.Unlock()
+	}
+}
+
+func (hw *Highway) register(pid peer.ID, req *proto.RegisterRequest) {
+	hw.mtx.Lock()
+	defer hw.mtx.Unlock()
+	hw.nodes[pid] = &registration{
+		pubkey:       req.CommitteePublicKey,
+		role:         req.Role,
+		committeeIDs: req.CommitteeID,
+	}
+}
+
+func (hw *Highway) disconnected(pid peer.ID) {
+	if hw.host.Network().Connectedness(pid) == network.Connected {
+		return
+	}
+	hw.mtx.Lock()
+	delete(hw.nodes, pid)
+	hw.mtx.Unlock()
+
+	hw.connsMtx.Lock()
+	defer hw.connsMtx.Unlock()
+	if conn, ok := hw.conns[pid]; ok {
+		conn.Close()
+		delete(hw.conns, pid)
+	}
+}
+
+// callerID finds the node of a gRPC call by the address of its libp2p connection
+func (hw *Highway) callerID(ctx context.Context) peer.ID {
+	p, ok := grpcpeer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	for _, conn := range hw.host.Network().Conns() {
+		ip, err := conn.RemoteMultiaddr().ValueForProtocol(multiaddr.P_IP4)
+		if err != nil {
+			continue
+		}
+		port, err := conn.RemoteMultiaddr().ValueForProtocol(multiaddr.P_TCP)
+		if err != nil {
+			continue
+		}
+		if net.JoinHostPort(ip, port) == p.Addr.String() {
+			return conn.RemotePeer()
+		}
+	}
+	return ""
+}
+
+// providers returns the connected nodes which keep the blocks of the committee
+// cID, other than caller: syncFrom first, then the members of the committee.
+// Every node keeps the beacon chain.
+func (hw *Highway) providers(cID byte, syncFrom string, caller peer.ID) []peer.ID {
+	hw.mtx.RLock()
+	defer hw.mtx.RUnlock()
+	first, members, others := []peer.ID{}, []peer.ID{}, []peer.ID{}
+	for pid, node := range hw.nodes {
+		if pid == caller || hw.host.Network().Connectedness(pid) != network.Connected {
+			continue
+		}
+		if pid.Pretty() == syncFrom {
+			first = append(first, pid)
+		} else if containsCommittee(node.committeeIDs, cID) {
+			members = append(members, pid)
+		} else if cID == peerv2.HighwayBeaconID {
+			others = append(others, pid)
+		}
+	}
+	rand.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+	rand.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+	return append(append(first, members...), others...)
+}
+
+// client returns a gRPC client of the block provider of a node
+func (hw *Highway) client(pid peer.ID) (proto.HighwayServiceClient, error) {
+	hw.connsMtx.Lock()
+	defer hw.connsMtx.Unlock()
+	if conn, ok := hw.conns[pid]; ok {
+		if state := conn.GetState(); state != connectivity.Shutdown && state != connectivity.TransientFailure {
+			return proto.NewHighwayServiceClient(conn), nil
+		}
+		conn.Close()
+		delete(hw.conns, pid)
+	}
+	ctx, cancel := context.WithTimeout(hw.ctx, peerv2.DialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(
+		ctx,
+		pid.Pretty(),
+		hw.grpc.GetDialOption(hw.ctx),
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "dial %v", pid.Pretty())
+	}
+	hw.conns[pid] = conn
+	return proto.NewHighwayServiceClient(conn), nil
+}
+
+// forward calls fn with the client of every provider of cID until one succeeds
+func (hw *Highway) forward(ctx context.Context, cID byte, syncFrom string, fn func(proto.HighwayServiceClient) error) error {
+	providers := hw.providers(cID, syncFrom, hw.callerID(ctx))
+	if len(providers) == 0 {
+		return errors.Errorf("no node keeps the blocks of committee %v", cID)
+	}
+	var err error
+	for _, pid := range providers {
+		var client proto.HighwayServiceClient
+		if client, err = hw.client(pid); err == nil {
+			if err = fn(client); err == nil {
+				return nil
+			}
+		}
+		if ctx.Err() != nil { // the caller is gone
+			return ctx.Err()
+		}
+		log.Printf("Forward request of committee %v to %v: %v", cID, pid.Pretty(), err)
+	}
+	return err
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	bootserver "github.com/incognitochain/incognito-chain/bootnode/server"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/peer"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/peerv2/wrapper"
+	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConsensusData struct {
+	layer   string
+	shardID int
+}
+
+func (cd *testConsensusData) GetUserRole() (string, string, int) {
+	return cd.layer, common.CommitteeRole, cd.shardID
+}
+
+func (cd *testConsensusData) GetCurrentMiningPublicKey() (string, string) {
+	return "pubkey", common.BlsConsensus
+}
+
+// testBlock stands for a block, the highway does not decode the blocks it relays
+type testBlock struct {
+	ShardID byte
+	Height  uint64
+}
+
+// testNetSync serves blocks of the heights 1 to height
+type testNetSync struct {
+	height uint64
+}
+
+func (ns *testNetSync) GetBlockShardByHash(blkHashes []common.Hash) []wire.Message {
+	return nil
+}
+
+func (ns *testNetSync) GetBlockBeaconByHash(blkHashes []common.Hash) []wire.Message {
+	return nil
+}
+
+func (ns *testNetSync) StreamBlockByHeight(fromPool bool, req *proto.BlockByHeightRequest) chan interface{} {
+	blkCh := make(chan interface{}, ns.height)
+	for height := req.Heights[0]; height <= req.Heights[len(req.Heights)-1] && height <= ns.height; height++ {
+		blkCh <- &testBlock{ShardID: byte(req.From), Height: height}
+	}
+	close(blkCh)
+	return blkCh
+}
+
+func (ns *testNetSync) StreamBlockByHash(fromPool bool, req *proto.BlockByHashRequest) chan interface{} {
+	blkCh := make(chan interface{})
+	close(blkCh)
+	return blkCh
+}
+
+type testNode struct {
+	cm  *peerv2.ConnManager
+	bft chan *wire.MessageBFT
+}
+
+func newTestNode(discoverAddr string, layer string, shardID int, height uint64) *testNode {
+	node := createTestNode(discoverAddr, layer, shardID)
+	go node.cm.Start(&testNetSync{height: height})
+	return node
+}
+
+// newDirectTestNode starts a node falling back to direct mode, its peer table
+// is saved in dir
+func newDirectTestNode(t *testing.T, discoverAddr, bootnode, dir string, shardID int, height uint64) *testNode {
+	node := createTestNode(discoverAddr, common.ShardRole, shardID)
+	err := node.cm.EnableDirectFallback(peerv2.DirectConfig{
+		BootnodeAddress: bootnode,
+		PeerTablePath:   filepath.Join(dir, "directpeers.json"),
+		NumShards:       2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go node.cm.Start(&testNetSync{height: height})
+	return node
+}
+
+func createTestNode(discoverAddr string, layer string, shardID int) *testNode {
+	node := &testNode{bft: make(chan *wire.MessageBFT, 100)}
+	host := peerv2.NewHost("test", "127.0.0.1", 0, "")
+	dispatcher := &peerv2.Dispatcher{
+		MessageListeners: &peerv2.MessageListeners{
+			OnBFTMsg: func(_ *peer.PeerConn, msg wire.Message) {
+				node.bft <- msg.(*wire.MessageBFT)
+			},
+		},
+	}
+	node.cm = peerv2.NewConnManager(
+		host,
+		discoverAddr,
+		&incognitokey.CommitteePublicKey{},
+		&testConsensusData{layer: layer, shardID: shardID},
+		dispatcher,
+		common.NodeModeAuto,
+		nil,
+	)
+	return node
+}
+
+// waitBFT publishes msg from sender until receiver gets it
+func waitBFT(sender, receiver *testNode, msg *wire.MessageBFT, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case got := <-receiver.bft:
+			if got.ChainKey == msg.ChainKey {
+				return true
+			}
+		case <-ticker.C:
+			sender.cm.PublishMessageToShard(msg, 0)
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+func newTestKey(t *testing.T) string {
+	privKey, _, err := crypto.GenerateKeyPair(crypto.ECDSA, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := crypto.MarshalPrivateKey(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.ConfigEncodeKey(b)
+}
+
+func TestHighwayRelaysMessagesAndBlocks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping highway test in short mode")
+	}
+	key := newTestKey(t)
+	listen, discoverListen := freeAddr(t), freeAddr(t)
+	hw, err := New(Config{Listen: listen, PrivateKey: key, DiscoverListen: discoverListen, NumShards: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hw.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	nodeA := newTestNode(hw.DiscoverAddr(), common.ShardRole, 0, 0)
+	nodeB := newTestNode(hw.DiscoverAddr(), common.ShardRole, 0, 10)
+	assert.True(t, waitBFT(nodeA, nodeB, &wire.MessageBFT{ChainKey: "first"}, 30*time.Second))
+	assert.Contains(t, hw.Topics(), peerv2.TopicName(wire.CmdBFT, 0))
+
+	// node A has no block, the highway streams the blocks of node B
+	assert.Equal(t, []uint64{2, 3, 4, 5}, streamHeights(t, nodeA, 2, 5))
+
+	// a restarted highway gets the registrations of the nodes again
+	hw.Stop()
+	time.Sleep(time.Second)
+	hw, err = New(Config{Listen: listen, PrivateKey: key, DiscoverListen: discoverListen, NumShards: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer hw.Stop()
+	assert.True(t, waitBFT(nodeA, nodeB, &wire.MessageBFT{ChainKey: "restarted"}, 60*time.Second))
+}
+
+// streamHeights returns the heights of the shard blocks node gets by
+// requesting heights from to to
+func streamHeights(t *testing.T, node *testNode, from, to uint64) []uint64 {
+	var stream proto.HighwayService_StreamBlockByHeightClient
+	var err error
+	for i := 0; i < 50 && stream == nil; i++ {
+		stream, err = node.cm.Requester.StreamBlockByHeight(context.Background(), &proto.BlockByHeightRequest{
+			Type:    proto.BlkType_BlkShard,
+			Heights: []uint64{from, to},
+			From:    0,
+			To:      0,
+		})
+		if err != nil {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	if !assert.NotNil(t, stream) {
+		return nil
+	}
+	heights := []uint64{}
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			return heights
+		}
+		if !assert.Nil(t, err) {
+			return heights
+		}
+		assert.Equal(t, byte(proto.BlkType_BlkShard), data.Data[0])
+		blk := &testBlock{}
+		assert.Nil(t, wrapper.DeCom(data.Data[1:], blk))
+		heights = append(heights, blk.Height)
+	}
+}
+
+// waitMode waits until every node is in direct mode, or in highway mode
+func waitMode(direct bool, timeout time.Duration, nodes ...*testNode) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		switched := true
+		for _, node := range nodes {
+			switched = switched && node.cm.IsDirect() == direct
+		}
+		if switched {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
+
+func TestDirectFallback(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping highway test in short mode")
+	}
+	dir, err := ioutil.TempDir("", "directfallback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bootnodeAddr := freeAddr(t)
+	_, port, _ := net.SplitHostPort(bootnodeAddr)
+	bootnodePort, _ := strconv.Atoi(port)
+	bootnode := &bootserver.RpcServer{}
+	bootnode.Init(&bootserver.RpcServerConfig{Port: bootnodePort})
+	go bootnode.Start()
+
+	// no highway is up, the nodes find each other through the bootnode
+	listen, discoverListen := freeAddr(t), freeAddr(t)
+	nodeA := newDirectTestNode(t, discoverListen, bootnodeAddr, filepath.Join(dir, "a"), 0, 0)
+	nodeB := newDirectTestNode(t, discoverListen, bootnodeAddr, filepath.Join(dir, "b"), 0, 10)
+	if !assert.True(t, waitMode(true, 30*time.Second, nodeA, nodeB)) {
+		return
+	}
+	assert.True(t, waitBFT(nodeA, nodeB, &wire.MessageBFT{ChainKey: "direct"}, 30*time.Second))
+	assert.Equal(t, []uint64{2, 3, 4, 5}, streamHeights(t, nodeA, 2, 5))
+	assert.Len(t, nodeA.cm.DirectPeers(), 1)
+	assert.FileExists(t, filepath.Join(dir, "a", "directpeers.json"))
+
+	// the nodes go back to the highway once it is up
+	hw, err := New(Config{Listen: listen, PrivateKey: newTestKey(t), DiscoverListen: discoverListen, NumShards: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hw.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer hw.Stop()
+	if !assert.True(t, waitMode(false, 30*time.Second, nodeA, nodeB)) {
+		return
+	}
+	assert.Empty(t, nodeA.cm.DirectPeers())
+	assert.True(t, waitBFT(nodeA, nodeB, &wire.MessageBFT{ChainKey: "highway"}, 30*time.Second))
+	assert.Contains(t, hw.Topics(), peerv2.TopicName(wire.CmdBFT, 0))
+}
+
+// freeAddr returns a local address nothing listens to
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func init() {
+	peerv2.Logger.Init(common.NewBackend(nil).Logger("test", true))
+	peerv2.RegisterTimestep = 100 * time.Millisecond
+	peerv2.ReconnectHighwayTimestep = 100 * time.Millisecond
+	peerv2.RequesterDialTimestep = 100 * time.Millisecond
+	peerv2.DirectFallbackTimeout = time.Second
+	peerv2.DirectPeerTimestep = 200 * time.Millisecond
+}
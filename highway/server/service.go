@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// service answers the gRPC calls of peerv2.BlockRequester, the block requests
+// are forwarded to the peerv2.BlockProvider of a node keeping the blocks
+type service struct {
+	proto.UnimplementedHighwayServiceServer
+	hw *Highway
+}
+
+func (s *service) Register(ctx context.Context, req *proto.RegisterRequest) (*proto.RegisterResponse, error) {
+	pid, err := peer.IDB58Decode(req.PeerID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid peer id %v", req.PeerID)
+	}
+	pairs := peerv2.TopicPairs(req.WantedMessages, req.CommitteeID, s.hw.config.NumShards)
+	for _, pair := range pairs {
+		for _, topic := range pair.Topic {
+			if err := s.hw.subscribe(topic); err != nil {
+				return nil, err
+			}
+		}
+	}
+	s.hw.register(pid, req)
+	log.Printf("Registered %v, role %v, committees %v, messages %v", pid.Pretty(), req.Role, req.CommitteeID, req.WantedMessages)
+
+	role := &proto.UserRole{Role: req.Role, Shard: -1}
+	for _, cID := range req.CommitteeID {
+		if cID == peerv2.HighwayBeaconID {
+			role.Layer = common.BeaconRole
+		} else if role.Layer == "" {
+			role.Layer = common.ShardRole
+			role.Shard = int32(cID)
+		}
+	}
+	return &proto.RegisterResponse{Pair: pairs, Role: role}, nil
+}
+
+func (s *service) GetBlockShardByHash(ctx context.Context, req *proto.GetBlockShardByHashRequest) (*proto.GetBlockShardByHashResponse, error) {
+	var resp *proto.GetBlockShardByHashResponse
+	req.CallDepth++
+	err := s.hw.forward(ctx, byte(req.Shard), "", func(client proto.HighwayServiceClient) (err error) {
+		resp, err = client.GetBlockShardByHash(ctx, req, grpc.MaxCallRecvMsgSize(peerv2.MaxCallRecvMsgSize))
+		return err
+	})
+	return resp, err
+}
+
+func (s *service) GetBlockBeaconByHash(ctx context.Context, req *proto.GetBlockBeaconByHashRequest) (*proto.GetBlockBeaconByHashResponse, error) {
+	var resp *proto.GetBlockBeaconByHashResponse
+	req.CallDepth++
+	err := s.hw.forward(ctx, peerv2.HighwayBeaconID, "", func(client proto.HighwayServiceClient) (err error) {
+		resp, err = client.GetBlockBeaconByHash(ctx, req, grpc.MaxCallRecvMsgSize(peerv2.MaxCallRecvMsgSize))
+		return err
+	})
+	return resp, err
+}
+
+func (s *service) GetBlockCrossShardByHash(ctx context.Context, req *proto.GetBlockCrossShardByHashRequest) (*proto.GetBlockCrossShardByHashResponse, error) {
+	var resp *proto.GetBlockCrossShardByHashResponse
+	req.CallDepth++
+	err := s.hw.forward(ctx, byte(req.FromShard), "", func(client proto.HighwayServiceClient) (err error) {
+		resp, err = client.GetBlockCrossShardByHash(ctx, req, grpc.MaxCallRecvMsgSize(peerv2.MaxCallRecvMsgSize))
+		return err
+	})
+	return resp, err
+}
+
+// StreamBlockByHeight streams the blocks of a node of the committee which
+// produces them, req.From, to the requester
+func (s *service) StreamBlockByHeight(req *proto.BlockByHeightRequest, stream proto.HighwayService_StreamBlockByHeightServer) error {
+	req.CallDepth++
+	return s.hw.forward(stream.Context(), byte(req.From), req.SyncFromPeer, func(client proto.HighwayServiceClient) error {
+		upstream, err := client.StreamBlockByHeight(stream.Context(), req, grpc.MaxCallRecvMsgSize(peerv2.MaxCallRecvMsgSize))
+		if err != nil {
+			return err
+		}
+		return relay(upstream, stream)
+	})
+}
+
+// StreamBlockByHash streams the blocks of a node of the committee which
+// produces them, req.From, to the requester
+func (s *service) StreamBlockByHash(req *proto.BlockByHashRequest, stream proto.HighwayService_StreamBlockByHashServer) error {
+	req.CallDepth++
+	return s.hw.forward(stream.Context(), byte(req.From), req.SyncFromPeer, func(client proto.HighwayServiceClient) error {
+		upstream, err := client.StreamBlockByHash(stream.Context(), req, grpc.MaxCallRecvMsgSize(peerv2.MaxCallRecvMsgSize))
+		if err != nil {
+			return err
+		}
+		return relay(upstream, stream)
+	})
+}
+
+type blockReceiver interface {
+	Recv() (*proto.BlockData, error)
+}
+
+type blockSender interface {
+	Send(*proto.BlockData) error
+}
+
+// relay copies the blocks of a provider to the requester. It fails only if the
+// provider fails before the first block, the requester asks again for the
+// blocks it misses after that.
+func relay(upstream blockReceiver, stream blockSender) error {
+	sent := 0
+	for {
+		blk, err := upstream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if sent > 0 {
+				log.Printf("Relay stopped after %v blocks: %v", sent, err)
+				return nil
+			}
+			return err
+		}
+		if err := stream.Send(blk); err != nil {
+			return err
+		}
+		sent++
+	}
+}
+
+// Handler answers the Handler.GetPeers calls of peerv2.AddrKeeper, the highway
+// is the only highway of every shard
+type Handler struct {
+	hw *Highway
+}
+
+func (h *Handler) GetPeers(req rpcclient.Request, res *rpcclient.Response) error {
+	addr := rpcclient.HighwayAddr{
+		Libp2pAddr: h.hw.Libp2pAddr(),
+		RPCUrl:     h.hw.DiscoverAddr(),
+	}
+	res.PeerPerShard = map[string][]rpcclient.HighwayAddr{}
+	for _, shard := range req.Shard {
+		res.PeerPerShard[shard] = []rpcclient.HighwayAddr{addr}
+	}
+	return nil
+}
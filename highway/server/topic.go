@@ -0,0 +1,13 @@
+package server
+
+// committeeTopic carries the committees broadcast by peerv2.ConnManager.BroadcastCommittee
+const committeeTopic = "chain_committee"
+
+func containsCommittee(committeeIDs []byte, cID byte) bool {
+	for _, id := range committeeIDs {
+		if id == cID {
+			return true
+		}
+	}
+	return false
+}
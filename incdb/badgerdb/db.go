@@ -0,0 +1,395 @@
+package badgerdb
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+
+	"github.com/dgraph-io/badger"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/pkg/errors"
+)
+
+// gcDiscardRatio is the ratio of stale data in a value log file above which
+// Compact rewrites the file
+const gcDiscardRatio = 0.5
+
+type db struct {
+	fn     string // filename for reporting
+	dbPath string
+	bdb    *badger.DB
+}
+
+func init() {
+	driver := incdb.Driver{
+		DbType: "badgerdb",
+		Open:   openDriver,
+	}
+	if err := incdb.RegisterDriver(driver); err != nil {
+		panic("failed to register db driver")
+	}
+}
+
+func openDriver(args ...interface{}) (incdb.Database, error) {
+	if len(args) != 1 {
+		return nil, errors.New("invalid arguments")
+	}
+	dbPath, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("expected db path")
+	}
+	return open(dbPath)
+}
+
+func open(dbPath string) (incdb.Database, error) {
+	// truncate a value log corrupted by a crash instead of refusing to open, same as leveldb recover
+	opts := badger.DefaultOptions(dbPath).WithTruncate(true).WithLogger(&logger{})
+	bdb, err := badger.Open(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "badger.Open %s", dbPath)
+	}
+	return &db{fn: dbPath, bdb: bdb, dbPath: dbPath}, nil
+}
+
+func (db *db) GetPath() string {
+	return db.fn
+}
+
+func (db *db) Close() error {
+	return errors.Wrap(db.bdb.Close(), "db.bdb.Close")
+}
+
+func (db *db) Has(key []byte) (bool, error) {
+	err := db.bdb.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(key)
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (db *db) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := db.bdb.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (db *db) Put(key, value []byte) error {
+	return db.bdb.Update(func(txn *badger.Txn) error {
+		return txn.Set(common.CopyBytes(key), common.CopyBytes(value))
+	})
+}
+
+func (db *db) Delete(key []byte) error {
+	return db.bdb.Update(func(txn *badger.Txn) error {
+		return txn.Delete(common.CopyBytes(key))
+	})
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (db *db) NewBatch() incdb.Batch {
+	return &batch{
+		db: db.bdb,
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the badger database.
+func (db *db) NewIterator() incdb.Iterator {
+	return db.newIterator(nil, nil)
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// database content starting at a particular initial key (or after, if it does
+// not exist).
+func (db *db) NewIteratorWithStart(start []byte) incdb.Iterator {
+	return db.newIterator(nil, start)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix.
+func (db *db) NewIteratorWithPrefix(prefix []byte) incdb.Iterator {
+	return db.newIterator(prefix, prefix)
+}
+
+func (db *db) newIterator(prefix []byte, start []byte) incdb.Iterator {
+	txn := db.bdb.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	return &iterator{
+		txn:    txn,
+		it:     txn.NewIterator(opts),
+		prefix: prefix,
+		start:  start,
+	}
+}
+
+// Stat returns the size of the LSM tree and value log, badger has no
+// leveldb-like properties so the same summary is returned for any property.
+func (db *db) Stat(property string) (string, error) {
+	lsm, vlog := db.bdb.Size()
+	return fmt.Sprintf("badger lsm size: %d bytes, vlog size: %d bytes, tables: %d", lsm, vlog, len(db.bdb.Tables(false))), nil
+}
+
+// ApproximateSize returns the estimated storage of keys in range [start, limit),
+// it walks the keys without fetching values from the value log.
+func (db *db) ApproximateSize(start []byte, limit []byte) (uint64, error) {
+	size := uint64(0)
+	err := db.bdb.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Seek(start); it.Valid(); it.Next() {
+			item := it.Item()
+			if limit != nil && bytes.Compare(item.Key(), limit) >= 0 {
+				break
+			}
+			size += uint64(item.EstimatedSize())
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Compact flattens the LSM tree and garbage collects the value log. Badger can
+// not compact a key range, start and limit are ignored and the entire data store
+// is compacted.
+func (db *db) Compact(start []byte, limit []byte) error {
+	if err := db.bdb.Flatten(runtime.NumCPU()); err != nil {
+		return err
+	}
+	for {
+		err := db.bdb.RunValueLogGC(gcDiscardRatio)
+		if err == badger.ErrNoRewrite {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// CompactsWhole reports that Compact compacts the entire data store, the
+// compactor runs it once instead of once per key range.
+func (db *db) CompactsWhole() bool {
+	return true
+}
+
+// Path returns the path to the database directory.
+func (db *db) Path() string {
+	return db.fn
+}
+
+// keyvalue is a key-value tuple tagged with a deletion field, badger write
+// batches can not be replayed so the batch keeps its own copy of the writes.
+type keyvalue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch is a write-only badger batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	db     *badger.DB
+	writes []keyvalue
+	size   int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), nil, true})
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk. Badger splits the write batch in
+// as many transactions as needed.
+func (b *batch) Write() error {
+	wb := b.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, keyvalue := range b.writes {
+		var err error
+		if keyvalue.delete {
+			err = wb.Delete(keyvalue.key)
+		} else {
+			err = wb.Set(keyvalue.key, keyvalue.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w incdb.KeyValueWriter) error {
+	for _, keyvalue := range b.writes {
+		if keyvalue.delete {
+			if err := w.Delete(keyvalue.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(keyvalue.key, keyvalue.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterator adapts the badger seek/valid/next iterator to the leveldb-like
+// Next-first incdb.Iterator. It holds a read-only transaction until released.
+type iterator struct {
+	txn     *badger.Txn
+	it      *badger.Iterator
+	prefix  []byte
+	start   []byte
+	started bool
+	key     []byte
+	value   []byte
+	err     error
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.err != nil || it.it == nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		it.it.Seek(it.start)
+	} else {
+		it.it.Next()
+	}
+	return it.load(it.it)
+}
+
+// Last moves the iterator to the last key/value pair, later calls of Next
+// return false.
+func (it *iterator) Last() bool {
+	if it.err != nil || it.it == nil {
+		return false
+	}
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = it.prefix
+	opts.Reverse = true
+	reverse := it.txn.NewIterator(opts)
+	defer reverse.Close()
+	// seek to the greatest key having the prefix
+	reverse.Seek(append(common.CopyBytes(it.prefix), 0xff, 0xff, 0xff, 0xff))
+	if !it.load(reverse) {
+		return false
+	}
+	if len(it.start) > 0 && string(it.key) < string(it.start) {
+		it.key, it.value = nil, nil
+		return false
+	}
+	it.it.Close()
+	it.it = nil
+	return true
+}
+
+func (it *iterator) load(bit *badger.Iterator) bool {
+	if !bit.ValidForPrefix(it.prefix) {
+		it.key, it.value = nil, nil
+		return false
+	}
+	item := bit.Item()
+	it.key = item.KeyCopy(nil)
+	it.value, it.err = item.ValueCopy(nil)
+	return it.err == nil
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	if it.it != nil {
+		it.it.Close()
+		it.it = nil
+	}
+	if it.txn != nil {
+		it.txn.Discard()
+		it.txn = nil
+	}
+}
+
+// logger forwards badger logs to the incdb logger
+type logger struct{}
+
+func (l *logger) Errorf(format string, params ...interface{}) {
+	if incdb.Logger.Log != nil {
+		incdb.Logger.Log.Errorf(format, params...)
+	}
+}
+
+func (l *logger) Warningf(format string, params ...interface{}) {
+	if incdb.Logger.Log != nil {
+		incdb.Logger.Log.Warnf(format, params...)
+	}
+}
+
+func (l *logger) Infof(format string, params ...interface{}) {
+	if incdb.Logger.Log != nil {
+		incdb.Logger.Log.Infof(format, params...)
+	}
+}
+
+func (l *logger) Debugf(format string, params ...interface{}) {
+	if incdb.Logger.Log != nil {
+		incdb.Logger.Log.Debugf(format, params...)
+	}
+}
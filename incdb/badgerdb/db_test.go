@@ -0,0 +1,116 @@
+package badgerdb_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/badgerdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDb_Base(t *testing.T) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dbPath)
+	db, err := incdb.Open("badgerdb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	assert.Equal(t, nil, db.Put([]byte("a"), []byte{1}))
+	result, err := db.Get([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte{1}, result)
+	has, err := db.Has([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, has)
+	assert.Equal(t, nil, db.Delete([]byte("a")))
+	assert.Equal(t, nil, db.Delete([]byte("b")))
+	has, err = db.Has([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, has)
+	_, err = db.Get([]byte("a"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDb_BatchAndIterator(t *testing.T) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dbPath)
+	db, err := incdb.Open("badgerdb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	batch := db.NewBatch()
+	for _, key := range []string{"b2", "a1", "b1", "c1", "b3"} {
+		assert.Equal(t, nil, batch.Put([]byte(key), []byte("v"+key)))
+	}
+	assert.Equal(t, nil, batch.Delete([]byte("b3")))
+	// nothing is visible before Write
+	has, _ := db.Has([]byte("a1"))
+	assert.Equal(t, false, has)
+	assert.Equal(t, nil, batch.Write())
+
+	keys := []string{}
+	iter := db.NewIterator()
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+		assert.Equal(t, "v"+string(iter.Key()), string(iter.Value()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{"a1", "b1", "b2", "c1"}, keys)
+
+	keys = []string{}
+	iter = db.NewIteratorWithPrefix([]byte("b"))
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{"b1", "b2"}, keys)
+
+	keys = []string{}
+	iter = db.NewIteratorWithStart([]byte("b2"))
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{"b2", "c1"}, keys)
+
+	iter = db.NewIteratorWithPrefix([]byte("b"))
+	assert.Equal(t, true, iter.Last())
+	assert.Equal(t, "b2", string(iter.Key()))
+	iter.Release()
+}
+
+func TestDb_ReopenAndCompact(t *testing.T) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dbPath)
+	db, err := incdb.Open("badgerdb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, nil, db.Put([]byte("key"), []byte("value")))
+	assert.Equal(t, nil, db.Compact(nil, nil))
+	_, err = db.Stat("")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, db.Close())
+
+	db, err = incdb.Open("badgerdb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	value, err := db.Get([]byte("key"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "value", string(value))
+}
@@ -0,0 +1,200 @@
+package incdb
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+)
+
+// compactionRanges is the number of key ranges each database is split into, the
+// compactor pauses between two ranges so foreground reads and writes are not
+// starved by a full compaction. The databases which can only compact their
+// entire data store are compacted once.
+const compactionRanges = 16
+
+var (
+	compactionRunningGauge  = metrics.NewRegisteredGauge("incdb/compaction/running", nil)
+	compactionProgressGauge = metrics.NewRegisteredGaugeFloat64("incdb/compaction/progress", nil)
+	compactionRangeTimer    = metrics.NewRegisteredTimer("incdb/compaction/range", nil)
+	compactionErrorCounter  = metrics.NewRegisteredCounter("incdb/compaction/error", nil)
+)
+
+// CompactionTarget is a named database compacted by a Compactor
+type CompactionTarget struct {
+	Name string
+	DB   Compacter
+}
+
+// CompactionStatus reports the progress of the current (or last) compaction run
+type CompactionStatus struct {
+	Running   bool
+	Current   string  // name of the database being compacted
+	Progress  float64 // percent of key ranges of all databases done
+	StartTime int64
+	EndTime   int64
+	LastError string
+}
+
+// Compactor compacts a set of databases in background, one key range at a time.
+// A run can be started and cancelled at any time, at most one run is active.
+type Compactor struct {
+	targets  []CompactionTarget
+	throttle time.Duration
+	lock     sync.RWMutex
+	status   CompactionStatus
+	cancel   chan struct{}
+}
+
+// NewCompactor creates a compactor which waits throttle between two key ranges
+func NewCompactor(targets []CompactionTarget, throttle time.Duration) *Compactor {
+	return &Compactor{
+		targets:  targets,
+		throttle: throttle,
+	}
+}
+
+// Start runs a compaction of all targets in background
+func (compactor *Compactor) Start() error {
+	compactor.lock.Lock()
+	defer compactor.lock.Unlock()
+	if compactor.status.Running {
+		return errors.New("compaction is already running")
+	}
+	compactor.cancel = make(chan struct{})
+	compactor.status = CompactionStatus{
+		Running:   true,
+		StartTime: time.Now().Unix(),
+	}
+	compactionRunningGauge.Update(1)
+	compactionProgressGauge.Update(0)
+	go compactor.run(compactor.cancel)
+	return nil
+}
+
+// Stop cancels the running compaction, the key range being compacted is finished first
+// and the compaction is running until then
+func (compactor *Compactor) Stop() error {
+	compactor.lock.Lock()
+	defer compactor.lock.Unlock()
+	if !compactor.status.Running {
+		return errors.New("compaction is not running")
+	}
+	select {
+	case <-compactor.cancel:
+		return errors.New("compaction is already stopping")
+	default:
+	}
+	close(compactor.cancel)
+	return nil
+}
+
+// Status returns a copy of the current compaction status
+func (compactor *Compactor) Status() CompactionStatus {
+	compactor.lock.RLock()
+	defer compactor.lock.RUnlock()
+	return compactor.status
+}
+
+// Schedule starts a compaction every interval until stop is closed, a tick is skipped
+// if the previous run is still going on
+func (compactor *Compactor) Schedule(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := compactor.Start(); err != nil {
+				Logger.Log.Infof("Skip scheduled compaction, %+v", err)
+			}
+		}
+	}
+}
+
+func (compactor *Compactor) run(cancel chan struct{}) {
+	total := 0
+	for _, target := range compactor.targets {
+		total += targetRanges(target.DB)
+	}
+	done := 0
+	var err error
+	defer func() {
+		compactor.lock.Lock()
+		defer compactor.lock.Unlock()
+		if err != nil {
+			compactionErrorCounter.Inc(1)
+		}
+		compactor.status.Running = false
+		compactor.status.EndTime = time.Now().Unix()
+		if err != nil {
+			compactor.status.LastError = err.Error()
+		}
+		compactionRunningGauge.Update(0)
+	}()
+	for _, target := range compactor.targets {
+		compactor.setCurrent(target.Name, done, total)
+		ranges := targetRanges(target.DB)
+		for i := 0; i < ranges; i++ {
+			select {
+			case <-cancel:
+				Logger.Log.Infof("Compaction cancelled at %+v", target.Name)
+				return
+			default:
+			}
+			start, limit := compactionRange(i, ranges)
+			startTime := time.Now()
+			if err = target.DB.Compact(start, limit); err != nil {
+				Logger.Log.Errorf("Compact %+v failed, error %+v", target.Name, err)
+				return
+			}
+			compactionRangeTimer.UpdateSince(startTime)
+			done++
+			compactor.setCurrent(target.Name, done, total)
+			select {
+			case <-cancel:
+				Logger.Log.Infof("Compaction cancelled at %+v", target.Name)
+				return
+			case <-time.After(compactor.throttle):
+			}
+		}
+	}
+	Logger.Log.Infof("Compaction of %+v databases finished", len(compactor.targets))
+}
+
+// setCurrent updates the status of the run
+func (compactor *Compactor) setCurrent(name string, done int, total int) {
+	progress := float64(100)
+	if total > 0 {
+		progress = float64(done) * 100 / float64(total)
+	}
+	compactor.lock.Lock()
+	defer compactor.lock.Unlock()
+	compactor.status.Current = name
+	compactor.status.Progress = progress
+	compactionProgressGauge.Update(progress)
+}
+
+// targetRanges returns the number of key ranges db is compacted in
+func targetRanges(db Compacter) int {
+	if whole, ok := db.(WholeCompacter); ok && whole.CompactsWhole() {
+		return 1
+	}
+	return compactionRanges
+}
+
+// compactionRange splits the key space in ranges by the first key byte, the first
+// range starts before all keys and the last one ends after all keys
+func compactionRange(i int, ranges int) ([]byte, []byte) {
+	step := 256 / ranges
+	var start, limit []byte
+	if i > 0 {
+		start = []byte{byte(i * step)}
+	}
+	if i < ranges-1 {
+		limit = []byte{byte((i + 1) * step)}
+	}
+	return start, limit
+}
@@ -0,0 +1,122 @@
+package incdb
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCompacter struct {
+	lock   sync.Mutex
+	ranges [][2][]byte
+	err    error
+	delay  time.Duration
+	// started is signaled when a range is compacted, which waits for release
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *fakeCompacter) Compact(start []byte, limit []byte) error {
+	if c.started != nil {
+		select {
+		case c.started <- struct{}{}:
+		default:
+		}
+		<-c.release
+	}
+	time.Sleep(c.delay)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.ranges = append(c.ranges, [2][]byte{start, limit})
+	return c.err
+}
+
+// fakeWholeCompacter compacts its entire data store whatever the range
+type fakeWholeCompacter struct {
+	fakeCompacter
+}
+
+func (c *fakeWholeCompacter) CompactsWhole() bool {
+	return true
+}
+
+func (c *fakeCompacter) count() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.ranges)
+}
+
+func waitCompactionDone(t *testing.T, compactor *Compactor) CompactionStatus {
+	for i := 0; i < 200; i++ {
+		if status := compactor.Status(); !status.Running {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("compaction does not finish")
+	return CompactionStatus{}
+}
+
+func TestCompactor(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	beacon, shard := &fakeCompacter{}, &fakeCompacter{}
+	compactor := NewCompactor([]CompactionTarget{{"beacon", beacon}, {"shard0", shard}}, 0)
+	assert.Equal(t, nil, compactor.Start())
+	status := waitCompactionDone(t, compactor)
+	assert.Equal(t, float64(100), status.Progress)
+	assert.Equal(t, "", status.LastError)
+	assert.Equal(t, compactionRanges, beacon.count())
+	assert.Equal(t, compactionRanges, shard.count())
+	// ranges cover the whole key space without gap
+	assert.Nil(t, beacon.ranges[0][0])
+	assert.Nil(t, beacon.ranges[compactionRanges-1][1])
+	for i := 1; i < compactionRanges; i++ {
+		assert.Equal(t, beacon.ranges[i-1][1], beacon.ranges[i][0])
+	}
+
+	// a database which can not compact a key range is compacted once
+	whole, shard := &fakeWholeCompacter{}, &fakeCompacter{}
+	compactor = NewCompactor([]CompactionTarget{{"beacon", whole}, {"shard0", shard}}, 0)
+	assert.Equal(t, nil, compactor.Start())
+	status = waitCompactionDone(t, compactor)
+	assert.Equal(t, float64(100), status.Progress)
+	assert.Equal(t, [][2][]byte{{nil, nil}}, whole.ranges)
+	assert.Equal(t, compactionRanges, shard.count())
+}
+
+func TestCompactorStopAndError(t *testing.T) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	slow := &fakeCompacter{delay: 20 * time.Millisecond}
+	compactor := NewCompactor([]CompactionTarget{{"beacon", slow}}, 0)
+	assert.Equal(t, nil, compactor.Start())
+	assert.NotEqual(t, nil, compactor.Start())
+	assert.Equal(t, nil, compactor.Stop())
+	assert.NotEqual(t, nil, compactor.Stop())
+	waitCompactionDone(t, compactor)
+	assert.True(t, slow.count() < compactionRanges)
+
+	// the range being compacted is finished before another run can start
+	blocked := &fakeCompacter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	compactor = NewCompactor([]CompactionTarget{{"beacon", blocked}}, 0)
+	assert.Equal(t, nil, compactor.Start())
+	<-blocked.started
+	assert.Equal(t, nil, compactor.Stop())
+	assert.True(t, compactor.Status().Running)
+	assert.NotEqual(t, nil, compactor.Start())
+	close(blocked.release)
+	waitCompactionDone(t, compactor)
+	assert.Equal(t, 1, blocked.count())
+	assert.Equal(t, nil, compactor.Start())
+	waitCompactionDone(t, compactor)
+
+	broken := &fakeCompacter{err: errors.New("disk error")}
+	compactor = NewCompactor([]CompactionTarget{{"beacon", broken}}, 0)
+	assert.Equal(t, nil, compactor.Start())
+	status := waitCompactionDone(t, compactor)
+	assert.Equal(t, "disk error", status.LastError)
+	assert.Equal(t, 1, broken.count())
+}
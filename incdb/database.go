@@ -45,7 +45,22 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
-// KeyValueStore contains all the methods required to allow handling different
+// WholeCompacter is implemented by the data stores which can not compact a key
+// range: their Compact compacts the entire data store whatever the range.
+type WholeCompacter interface {
+	// CompactsWhole reports whether Compact ignores its key range.
+	CompactsWhole() bool
+}
+
+// Sizer wraps the ApproximateSize method of a backing data store.
+type Sizer interface {
+	// ApproximateSize returns the approximate storage used by the keys in range
+	// [start, limit). A nil start is treated as a key before all keys in the data
+	// store; a nil limit is treated as a key after all keys in the data store.
+	ApproximateSize(start []byte, limit []byte) (uint64, error)
+}
+
+// Database contains all the methods required to allow handling different
 // key-value data stores backing the high level database.
 type Database interface {
 	KeyValueReader
@@ -55,10 +70,29 @@ type Database interface {
 	Stater
 	Compacter
 	io.Closer
-	RemoveBackup(string)
-	Backup(backupFolder string) error
+}
+
+// Backuper wraps the epoch backup methods of a data store which keeps its data
+// in a directory on disk. Engines without such directory (e.g. memdb) do not
+// implement it, callers must check with a type assertion.
+type Backuper interface {
+	// Backup compresses the database directory into backupFile, relative to
+	// the database directory.
+	Backup(backupFile string) error
+
+	// RemoveBackup removes a backup file created by Backup.
+	RemoveBackup(backupFile string)
+
+	// LatestBackup returns the latest backup epoch and its file path in backupFolder.
 	LatestBackup(backupFolder string) (int, string)
+
+	// PreloadBackup replaces the database directory by the content of backupFile.
+	// The database must be closed before and reopened after.
 	PreloadBackup(backupFile string) error
+
+	// ReOpen opens the database again after it was closed.
 	ReOpen() error
+
+	// Clear removes all files of the database directory.
 	Clear() error
 }
@@ -160,6 +160,16 @@ func (db *db) Stat(property string) (string, error) {
 	return db.lvdb.GetProperty(property)
 }
 
+// ApproximateSize returns the approximate file system space used by keys in
+// range [start, limit).
+func (db *db) ApproximateSize(start []byte, limit []byte) (uint64, error) {
+	sizes, err := db.lvdb.SizeOf([]util.Range{{Start: start, Limit: limit}})
+	if err != nil {
+		return 0, err
+	}
+	return uint64(sizes.Sum()), nil
+}
+
 // Compact flattens the underlying data store for the given key range. In essence,
 // deleted and overwritten versions are discarded, and the data is rearranged to
 // reduce the cost of operations needed to access them.
@@ -0,0 +1,50 @@
+package incdb
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// PrefixRange returns the key range [start, limit) holding all keys with the given prefix
+func PrefixRange(prefix []byte) ([]byte, []byte) {
+	var limit []byte
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			limit = make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i]++
+			break
+		}
+	}
+	return prefix, limit
+}
+
+// PrefixSize returns the storage used by keys with the given prefix.
+// Engines implementing Sizer give an estimation, the others are iterated.
+func PrefixSize(db Database, prefix []byte) (uint64, error) {
+	if sizer, ok := db.(Sizer); ok {
+		return sizer.ApproximateSize(PrefixRange(prefix))
+	}
+	size := uint64(0)
+	iter := db.NewIteratorWithPrefix(prefix)
+	defer iter.Release()
+	for iter.Next() {
+		size += uint64(len(iter.Key()) + len(iter.Value()))
+	}
+	return size, iter.Error()
+}
+
+// DirSize returns the total size of the files in a database directory
+func DirSize(path string) (uint64, error) {
+	size := uint64(0)
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size, err
+}
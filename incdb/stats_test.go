@@ -0,0 +1,17 @@
+package incdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixRange(t *testing.T) {
+	start, limit := PrefixRange([]byte("tx-h"))
+	assert.Equal(t, []byte("tx-h"), start)
+	assert.Equal(t, []byte("tx-i"), limit)
+	_, limit = PrefixRange([]byte{1, 0xff, 0xff})
+	assert.Equal(t, []byte{2}, limit)
+	_, limit = PrefixRange([]byte{0xff})
+	assert.Nil(t, limit)
+}
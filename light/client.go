@@ -0,0 +1,466 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+var (
+	ErrHeaderLink       = errors.New("header does not follow the previous block")
+	ErrCommitteeRoot    = errors.New("committee lists do not match the header")
+	ErrShardCommittee   = errors.New("shard committee does not match the previous block")
+	ErrUnknownBeacon    = errors.New("beacon block not verified by the light client")
+	ErrTxProof          = errors.New("invalid transaction proof")
+	ErrCrossShardProof  = errors.New("invalid cross shard outputs proof")
+	ErrInstructionProof = errors.New("invalid instruction proof")
+)
+
+var (
+	BeaconBatchSize  = uint64(350)      // Beacon headers requested and verified at once
+	MaxBeaconRecords = 65536            // Beacon headers remembered to verify shard headers
+	SyncInterval     = 10 * time.Second // Time between beacon syncs of Run
+)
+
+// Verifier verifies the signatures of blocks, as consensus.Engine
+type Verifier interface {
+	ValidateProducerSig(block common.BlockInterface, consensusType string) error
+	BatchValidateBlockCommitteeSig(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error
+}
+
+// Checkpoint is the beacon header the light client trusts and syncs from,
+// with the committee lists after it
+type Checkpoint struct {
+	Header     *blockchain.BeaconBlock
+	Committees *CommitteeLists
+}
+
+// CheckpointFromBestState returns the best block of a beacon state as
+// checkpoint
+func CheckpointFromBestState(state *blockchain.BeaconBestState) (*Checkpoint, error) {
+	header := state.BestBlock
+	lists, err := NewCommitteeLists(state.BeaconHeight, state.BeaconCommittee, state.BeaconPendingValidator, state.ShardCommittee, state.ShardPendingValidator)
+	if err != nil {
+		return nil, err
+	}
+	return &Checkpoint{Header: &header, Committees: lists}, nil
+}
+
+// beaconRecord is what the light client keeps of a verified beacon header
+type beaconRecord struct {
+	hash            common.Hash
+	shardRoot       common.Hash
+	instructionRoot common.Hash
+}
+
+// Client is a light client. It follows the beacon chain by its headers,
+// verifying their signatures and fetching the committee lists when a header
+// commits to new ones, and verifies shard headers, transactions and
+// instructions against the beacon headers without keeping any state.
+type Client struct {
+	source   Source
+	verifier Verifier
+
+	syncLock sync.Mutex // held to add beacon headers
+
+	lock          sync.RWMutex
+	header        *blockchain.BeaconBlock // last verified beacon header
+	committee     []incognitokey.CommitteePublicKey
+	committeeRoot common.Hash
+	records       map[uint64]beaconRecord
+	lowest        uint64
+}
+
+// NewClient returns a light client syncing from checkpoint. Signatures are
+// verified by consensus.Engine if verifier is nil.
+func NewClient(checkpoint *Checkpoint, source Source, verifier Verifier) (*Client, error) {
+	// the genesis block commits to no committee lists, they are trusted
+	// with it
+	lists := checkpoint.Committees
+	height := checkpoint.Header.Header.Height
+	if height != 1 {
+		if err := lists.Verify(checkpoint.Header); err != nil {
+			return nil, err
+		}
+	} else if lists.BeaconHeight != height {
+		return nil, fmt.Errorf("committee lists of beacon height %v for block %v", lists.BeaconHeight, height)
+	}
+	beaconRoot, err := lists.BeaconRoot()
+	if err != nil {
+		return nil, err
+	}
+	shardRoot, err := lists.ShardRoot()
+	if err != nil {
+		return nil, err
+	}
+	committee, err := lists.beaconCommittee()
+	if err != nil {
+		return nil, err
+	}
+	if verifier == nil {
+		verifier = &consensus.Engine{}
+	}
+	c := &Client{
+		source:   source,
+		verifier: verifier,
+		records:  map[uint64]beaconRecord{},
+		lowest:   height,
+	}
+	c.accept([]*blockchain.BeaconBlock{checkpoint.Header}, committee)
+	c.committeeRoot = beaconRoot
+	record := c.records[height]
+	record.shardRoot = shardRoot
+	c.records[height] = record
+	return c, nil
+}
+
+// accept adds verified headers, after which committee signs the beacon
+// blocks if not nil
+func (c *Client) accept(headers []*blockchain.BeaconBlock, committee []incognitokey.CommitteePublicKey) {
+	if len(headers) == 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, header := range headers {
+		c.records[header.Header.Height] = beaconRecord{
+			hash:            *header.Hash(),
+			shardRoot:       header.Header.ShardCommitteeAndValidatorRoot,
+			instructionRoot: header.Header.InstructionMerkleRoot,
+		}
+		for len(c.records) > MaxBeaconRecords {
+			delete(c.records, c.lowest)
+			c.lowest++
+		}
+	}
+	c.header = headers[len(headers)-1]
+	if committee != nil {
+		c.committee = committee
+		c.committeeRoot = c.header.Header.BeaconCommitteeAndValidatorRoot
+	}
+}
+
+// AddBeaconHeaders verifies headers following the last verified beacon header
+// and adds them. The committee signatures of the headers signed by the same
+// committee are verified in one batch. It returns the number of headers added,
+// from the first one, and the error of the first header rejected.
+func (c *Client) AddBeaconHeaders(ctx context.Context, headers []*blockchain.BeaconBlock) (int, error) {
+	c.syncLock.Lock()
+	defer c.syncLock.Unlock()
+
+	added := 0
+	n, linkErr := linkedHeaders(c.header, headers)
+	headers = headers[:n]
+	for len(headers) > 0 {
+		// the header committing to new committee lists is still signed by
+		// the previous committee
+		end := len(headers)
+		for i, header := range headers {
+			if !header.Header.BeaconCommitteeAndValidatorRoot.IsEqual(&c.committeeRoot) {
+				end = i + 1
+				break
+			}
+		}
+		verified, err := c.verifySignatures(headers[:end], c.committee)
+		var committee []incognitokey.CommitteePublicKey
+		if err == nil && !headers[end-1].Header.BeaconCommitteeAndValidatorRoot.IsEqual(&c.committeeRoot) {
+			committee, err = c.fetchCommittee(ctx, headers[end-1])
+			if err != nil {
+				verified = verified[:len(verified)-1]
+			}
+		}
+		c.accept(verified, committee)
+		added += len(verified)
+		if err != nil {
+			return added, err
+		}
+		headers = headers[end:]
+	}
+	return added, linkErr
+}
+
+// linkedHeaders returns the number of headers following prev and each other
+func linkedHeaders(prev *blockchain.BeaconBlock, headers []*blockchain.BeaconBlock) (int, error) {
+	for i, header := range headers {
+		if header.Header.Height != prev.Header.Height+1 || !header.Header.PreviousBlockHash.IsEqual(prev.Hash()) {
+			return i, fmt.Errorf("%v: beacon block %v", ErrHeaderLink, header.Header.Height)
+		}
+		prev = header
+	}
+	return len(headers), nil
+}
+
+// verifySignatures returns the headers signed by their producer and
+// committee, from the first one
+func (c *Client) verifySignatures(headers []*blockchain.BeaconBlock, committee []incognitokey.CommitteePublicKey) ([]*blockchain.BeaconBlock, error) {
+	var producerErr error
+	blocks := []common.BlockInterface{}
+	for _, header := range headers {
+		if err := c.verifier.ValidateProducerSig(header, header.Header.ConsensusType); err != nil {
+			producerErr = fmt.Errorf("beacon block %v: %v", header.Header.Height, err)
+			break
+		}
+		blocks = append(blocks, header)
+	}
+	headers = headers[:len(blocks)]
+	if len(blocks) == 0 {
+		return nil, producerErr
+	}
+	if err := c.verifier.BatchValidateBlockCommitteeSig(blocks, committee); err != nil {
+		// Find the first header which is not signed
+		for i, blk := range blocks {
+			if err := c.verifier.BatchValidateBlockCommitteeSig([]common.BlockInterface{blk}, committee); err != nil {
+				return headers[:i], fmt.Errorf("beacon block %v: %v", blk.GetHeight(), err)
+			}
+		}
+	}
+	return headers, producerErr
+}
+
+// fetchCommittee returns the beacon committee after header, fetching the
+// committee lists it commits to
+func (c *Client) fetchCommittee(ctx context.Context, header *blockchain.BeaconBlock) ([]incognitokey.CommitteePublicKey, error) {
+	lists, err := c.source.Committees(ctx, header.Header.Height)
+	if err != nil {
+		return nil, err
+	}
+	if err := lists.Verify(header); err != nil {
+		return nil, err
+	}
+	Logger.Infof("Light client got the committee lists of beacon block %v", header.Header.Height)
+	return lists.beaconCommittee()
+}
+
+// SyncBeacon requests and adds the beacon headers after the last verified
+// one, until the source has no more
+func (c *Client) SyncBeacon(ctx context.Context) error {
+	for {
+		from := c.BeaconHeight() + 1
+		headers, err := c.source.BeaconHeaders(ctx, from, from+BeaconBatchSize-1)
+		if len(headers) == 0 {
+			return err
+		}
+		n, err := c.AddBeaconHeaders(ctx, headers)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+	}
+}
+
+// Run syncs the beacon headers every SyncInterval until quit is closed
+func (c *Client) Run(quit <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-quit
+		cancel()
+	}()
+	ticker := time.NewTicker(SyncInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.SyncBeacon(ctx); err != nil {
+			Logger.Errorf("Light client sync beacon error: %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// BeaconHeight returns the height of the last verified beacon header
+func (c *Client) BeaconHeight() uint64 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.header.Header.Height
+}
+
+// Status is the beacon chain as followed by the light client
+type Status struct {
+	BeaconHeight  uint64
+	BeaconHash    common.Hash
+	Epoch         uint64
+	CommitteeSize int
+	LowestHeight  uint64 // lowest height shard headers can refer to
+}
+
+func (c *Client) Status() *Status {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return &Status{
+		BeaconHeight:  c.header.Header.Height,
+		BeaconHash:    *c.header.Hash(),
+		Epoch:         c.header.Header.Epoch,
+		CommitteeSize: len(c.committee),
+		LowestHeight:  c.lowest,
+	}
+}
+
+func (c *Client) record(height uint64) (beaconRecord, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	record, ok := c.records[height]
+	if !ok {
+		return record, fmt.Errorf("%v: height %v", ErrUnknownBeacon, height)
+	}
+	return record, nil
+}
+
+// VerifyShardHeader requests the header of the shard block at height and
+// verifies it. The shard committee signing it is the one after the previous
+// block, whose header commits to it by its CommitteeRoot, and is given by the
+// committee lists of the beacon block the previous block refers to.
+func (c *Client) VerifyShardHeader(ctx context.Context, shardID byte, height uint64) (*blockchain.ShardBlock, error) {
+	if height < 2 {
+		return nil, fmt.Errorf("shard block %v has no previous block", height)
+	}
+	headers, err := c.source.ShardHeaders(ctx, shardID, height-1, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) != 2 || headers[0].Header.Height != height-1 || headers[1].Header.Height != height ||
+		headers[0].Header.ShardID != shardID || headers[1].Header.ShardID != shardID {
+		return nil, fmt.Errorf("no header of shard %v block %v", shardID, height)
+	}
+	prev, header := headers[0], headers[1]
+	if !header.Header.PreviousBlockHash.IsEqual(prev.Hash()) {
+		return nil, fmt.Errorf("%v: shard %v block %v", ErrHeaderLink, shardID, height)
+	}
+	record, err := c.record(header.Header.BeaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	if !record.hash.IsEqual(&header.Header.BeaconHash) {
+		return nil, fmt.Errorf("%v: shard %v block %v refers to beacon %v", ErrUnknownBeacon, shardID, height, header.Header.BeaconHash)
+	}
+
+	prevRecord, err := c.record(prev.Header.BeaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := c.source.Committees(ctx, prev.Header.BeaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	if lists.BeaconHeight != prev.Header.BeaconHeight {
+		return nil, fmt.Errorf("committee lists of beacon height %v instead of %v", lists.BeaconHeight, prev.Header.BeaconHeight)
+	}
+	if err := lists.verifyShards(prevRecord.shardRoot); err != nil {
+		return nil, err
+	}
+	// the genesis block does not commit to a committee
+	if prev.Header.Height > 1 {
+		root, err := blockchain.CommitteeRoot(lists.ShardCommittee[shardID])
+		if err != nil {
+			return nil, err
+		}
+		if !root.IsEqual(&prev.Header.CommitteeRoot) {
+			return nil, fmt.Errorf("%v: shard %v block %v", ErrShardCommittee, shardID, height-1)
+		}
+	}
+	committee, err := lists.shardCommittee(shardID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verifier.ValidateProducerSig(header, header.Header.ConsensusType); err != nil {
+		return nil, err
+	}
+	if err := c.verifier.BatchValidateBlockCommitteeSig([]common.BlockInterface{header}, committee); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// VerifiedTx is a transaction proven to be in a verified shard block
+type VerifiedTx struct {
+	ShardID     byte
+	BlockHeight uint64
+	BlockHash   common.Hash
+	Tx          metadata.Transaction
+}
+
+// VerifyTx requests the proof of a transaction and verifies it against the
+// header of its shard block
+func (c *Client) VerifyTx(ctx context.Context, txHash common.Hash) (*VerifiedTx, error) {
+	proof, err := c.source.TxProof(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if !proof.TxHash.IsEqual(&txHash) {
+		return nil, fmt.Errorf("%v: proof of transaction %v", ErrTxProof, proof.TxHash)
+	}
+	header, err := c.VerifyShardHeader(ctx, proof.ShardID, proof.BlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	if !header.Hash().IsEqual(&proof.BlockHash) {
+		return nil, fmt.Errorf("%v: block %v", ErrTxProof, proof.BlockHash)
+	}
+	if err := proof.Verify(header.Header.TxRoot); err != nil {
+		return nil, err
+	}
+	tx, err := proof.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	return &VerifiedTx{
+		ShardID:     proof.ShardID,
+		BlockHeight: proof.BlockHeight,
+		BlockHash:   proof.BlockHash,
+		Tx:          tx,
+	}, nil
+}
+
+// VerifyCrossShardOutputs verifies that the outputs of a cross shard block
+// are committed to by the ShardTxRoot of its verified shard block
+func (c *Client) VerifyCrossShardOutputs(ctx context.Context, block *blockchain.CrossShardBlock) error {
+	header, err := c.VerifyShardHeader(ctx, block.Header.ShardID, block.Header.Height)
+	if err != nil {
+		return err
+	}
+	if !header.Hash().IsEqual(block.Hash()) {
+		return fmt.Errorf("%v: header of block %v", ErrCrossShardProof, block.Header.Height)
+	}
+	if !blockchain.VerifyCrossShardBlockUTXO(block, block.MerklePathShard) {
+		return ErrCrossShardProof
+	}
+	return nil
+}
+
+// VerifyBeaconInstruction verifies the proof of an instruction of the beacon
+// block at height against its InstructionMerkleRoot
+func (c *Client) VerifyBeaconInstruction(height uint64, inst []byte, path [][]byte, left []bool) error {
+	record, err := c.record(height)
+	if err != nil {
+		return err
+	}
+	if !VerifyInstruction(inst, path, left, record.instructionRoot) {
+		return ErrInstructionProof
+	}
+	return nil
+}
+
+// VerifyShardInstruction verifies the proof of an instruction of a shard
+// block against its InstructionMerkleRoot
+func (c *Client) VerifyShardInstruction(ctx context.Context, shardID byte, height uint64, inst []byte, path [][]byte, left []bool) error {
+	header, err := c.VerifyShardHeader(ctx, shardID, height)
+	if err != nil {
+		return err
+	}
+	if !VerifyInstruction(inst, path, left, header.Header.InstructionMerkleRoot) {
+		return ErrInstructionProof
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/stretchr/testify/assert"
+)
+
+// testKeys returns a committee of size keys whose first key identifies it
+func testKeys(id byte, size int) []incognitokey.CommitteePublicKey {
+	keys := []incognitokey.CommitteePublicKey{}
+	for i := 0; i < size; i++ {
+		keys = append(keys, incognitokey.CommitteePublicKey{IncPubKey: []byte{id, byte(i)}})
+	}
+	return keys
+}
+
+// testVerifier accepts the blocks signed by the committee of the id in
+// signers, by height for beacon blocks and by shard for shard blocks
+type testVerifier struct {
+	beaconSigners map[uint64]byte
+	shardSigners  map[byte]byte
+	badProducer   map[uint64]bool
+	batches       int
+}
+
+func (v *testVerifier) ValidateProducerSig(block common.BlockInterface, consensusType string) error {
+	if _, ok := block.(*blockchain.BeaconBlock); ok && v.badProducer[block.GetHeight()] {
+		return errors.New("bad producer")
+	}
+	return nil
+}
+
+func (v *testVerifier) BatchValidateBlockCommitteeSig(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	v.batches++
+	for _, blk := range blocks {
+		signer := v.beaconSigners[blk.GetHeight()]
+		if blk, ok := blk.(*blockchain.ShardBlock); ok {
+			signer = v.shardSigners[blk.Header.ShardID]
+		}
+		if len(committee) == 0 || committee[0].IncPubKey[0] != signer {
+			return errors.New("not signed")
+		}
+	}
+	return nil
+}
+
+// testSource is a beacon chain whose committee changes at the heights of
+// changes, and a shard 0 chain
+type testSource struct {
+	beacon     []*blockchain.BeaconBlock
+	lists      []*CommitteeLists // by beacon height
+	shard      []*blockchain.ShardBlock
+	committees int
+	badLists   bool
+}
+
+func newTestSource(t *testing.T, height uint64, changes map[uint64]byte, verifier *testVerifier) *testSource {
+	s := &testSource{}
+	committee := byte(1)
+	prevHash := common.Hash{}
+	for h := uint64(0); h <= height; h++ {
+		verifier.beaconSigners[h] = committee
+		if id, ok := changes[h]; ok {
+			committee = id
+		}
+		lists, err := NewCommitteeLists(h, testKeys(committee, 4), testKeys(committee+50, 1),
+			map[byte][]incognitokey.CommitteePublicKey{0: testKeys(100, 4)}, map[byte][]incognitokey.CommitteePublicKey{})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		blk := &blockchain.BeaconBlock{}
+		blk.Header.Height = h
+		blk.Header.PreviousBlockHash = prevHash
+		blk.Header.BeaconCommitteeAndValidatorRoot, _ = lists.BeaconRoot()
+		blk.Header.ShardCommitteeAndValidatorRoot, _ = lists.ShardRoot()
+		prevHash = *blk.Hash()
+		s.beacon = append(s.beacon, blk)
+		s.lists = append(s.lists, lists)
+	}
+	return s
+}
+
+func (s *testSource) checkpoint() *Checkpoint {
+	return &Checkpoint{Header: s.beacon[0], Committees: s.lists[0]}
+}
+
+func (s *testSource) BeaconHeaders(ctx context.Context, from, to uint64) ([]*blockchain.BeaconBlock, error) {
+	headers := []*blockchain.BeaconBlock{}
+	for h := from; h <= to && h < uint64(len(s.beacon)); h++ {
+		headers = append(headers, s.beacon[h])
+	}
+	return headers, nil
+}
+
+func (s *testSource) ShardHeaders(ctx context.Context, shardID byte, from, to uint64) ([]*blockchain.ShardBlock, error) {
+	headers := []*blockchain.ShardBlock{}
+	for h := from; h <= to && h < uint64(len(s.shard)); h++ {
+		headers = append(headers, s.shard[h])
+	}
+	return headers, nil
+}
+
+func (s *testSource) Committees(ctx context.Context, beaconHeight uint64) (*CommitteeLists, error) {
+	s.committees++
+	lists := *s.lists[beaconHeight]
+	if s.badLists {
+		lists.BeaconCommittee = s.lists[0].BeaconCommittee
+	}
+	return &lists, nil
+}
+
+func (s *testSource) TxProof(ctx context.Context, txHash common.Hash) (*TxProof, error) {
+	for _, blk := range s.shard {
+		for i, tx := range blk.Body.Transactions {
+			if tx.Hash().IsEqual(&txHash) {
+				return BuildTxProof(blk, i)
+			}
+		}
+	}
+	return nil, errors.New("no transaction")
+}
+
+func newTestVerifier() *testVerifier {
+	return &testVerifier{beaconSigners: map[uint64]byte{}, shardSigners: map[byte]byte{0: 100}, badProducer: map[uint64]bool{}}
+}
+
+func setBeaconBatchSize(size uint64) func() {
+	old := BeaconBatchSize
+	BeaconBatchSize = size
+	return func() { BeaconBatchSize = old }
+}
+
+func TestClientFollowsCommitteeChanges(t *testing.T) {
+	defer setBeaconBatchSize(7)()
+	verifier := newTestVerifier()
+	source := newTestSource(t, 40, map[uint64]byte{10: 2, 25: 3}, verifier)
+	client, err := NewClient(source.checkpoint(), source, verifier)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, client.SyncBeacon(context.Background()))
+	status := client.Status()
+	assert.Equal(t, uint64(40), status.BeaconHeight)
+	assert.Equal(t, *source.beacon[40].Hash(), status.BeaconHash)
+	assert.Equal(t, 4, status.CommitteeSize)
+	// the lists are fetched once for each header committing to new ones
+	assert.Equal(t, 2, source.committees)
+	// batches of 7 headers at most, split after heights 10 and 25
+	assert.Equal(t, 8, verifier.batches)
+}
+
+func TestClientRejectsBadHeaders(t *testing.T) {
+	verifier := newTestVerifier()
+	source := newTestSource(t, 30, map[uint64]byte{10: 2}, verifier)
+	client, _ := NewClient(source.checkpoint(), source, verifier)
+
+	// a header signed by the previous committee
+	verifier.beaconSigners[15] = 1
+	n, err := client.AddBeaconHeaders(context.Background(), source.beacon[1:])
+	assert.Error(t, err)
+	assert.Equal(t, 14, n)
+	assert.Equal(t, uint64(14), client.BeaconHeight())
+
+	// a header not following the last one
+	n, err = client.AddBeaconHeaders(context.Background(), source.beacon[16:])
+	assert.Equal(t, 0, n)
+	assert.Contains(t, err.Error(), ErrHeaderLink.Error())
+
+	// a header whose producer did not sign it
+	verifier.beaconSigners[15] = 2
+	verifier.badProducer[20] = true
+	n, err = client.AddBeaconHeaders(context.Background(), source.beacon[15:])
+	assert.Error(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, uint64(19), client.BeaconHeight())
+}
+
+func TestClientRejectsBadCommittees(t *testing.T) {
+	verifier := newTestVerifier()
+	source := newTestSource(t, 20, map[uint64]byte{10: 2}, verifier)
+	source.badLists = true
+	client, _ := NewClient(source.checkpoint(), source, verifier)
+
+	// the header committing to the lists is not added until they are verified
+	err := client.SyncBeacon(context.Background())
+	assert.Contains(t, err.Error(), ErrCommitteeRoot.Error())
+	assert.Equal(t, uint64(9), client.BeaconHeight())
+
+	source.badLists = false
+	assert.NoError(t, client.SyncBeacon(context.Background()))
+	assert.Equal(t, uint64(20), client.BeaconHeight())
+
+	// the checkpoint must commit to its lists
+	checkpoint := source.checkpoint()
+	checkpoint.Committees = source.lists[10]
+	_, err = NewClient(checkpoint, source, verifier)
+	assert.Error(t, err)
+}
+
+// addShardBlocks adds blocks to shard 0 of source, referring to the beacon
+// block at the same height
+func (s *testSource) addShardBlocks(height uint64) {
+	committeeRoot, _ := blockchain.CommitteeRoot(s.lists[0].ShardCommittee[0])
+	prevHash := common.Hash{}
+	for h := uint64(0); h <= height; h++ {
+		blk := &blockchain.ShardBlock{}
+		blk.Header.Height = h
+		blk.Header.PreviousBlockHash = prevHash
+		blk.Header.BeaconHeight = h
+		blk.Header.BeaconHash = *s.beacon[h].Hash()
+		if h > 1 {
+			blk.Header.CommitteeRoot = committeeRoot
+		}
+		blk.Body.Transactions = newTestTxs(int(h)+1, int64(h*100))
+		tree := blockchain.Merkle{}.BuildMerkleTreeStore(blk.Body.Transactions)
+		blk.Header.TxRoot = *tree[len(tree)-1]
+		prevHash = *blk.Hash()
+		s.shard = append(s.shard, blk)
+	}
+}
+
+func TestClientVerifiesShardData(t *testing.T) {
+	verifier := newTestVerifier()
+	source := newTestSource(t, 10, nil, verifier)
+	source.addShardBlocks(6)
+	client, _ := NewClient(source.checkpoint(), source, verifier)
+	assert.NoError(t, client.SyncBeacon(context.Background()))
+
+	txHash := *source.shard[4].Body.Transactions[3].Hash()
+	tx, err := client.VerifyTx(context.Background(), txHash)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(4), tx.BlockHeight)
+		assert.Equal(t, txHash, *tx.Tx.Hash())
+	}
+
+	// a shard block not signed by its committee
+	verifier.shardSigners[0] = 101
+	_, err = client.VerifyTx(context.Background(), txHash)
+	assert.Error(t, err)
+	verifier.shardSigners[0] = 100
+
+	// a shard block referring to a beacon block the client does not know
+	source.shard[5].Header.BeaconHash = common.Hash{1}
+	_, err = client.VerifyShardHeader(context.Background(), 0, 5)
+	assert.Contains(t, err.Error(), ErrUnknownBeacon.Error())
+
+	// a previous block committing to another committee
+	source.shard[3].Header.CommitteeRoot = common.Hash{1}
+	source.shard[4].Header.PreviousBlockHash = *source.shard[3].Hash()
+	_, err = client.VerifyShardHeader(context.Background(), 0, 4)
+	assert.Contains(t, err.Error(), ErrShardCommittee.Error())
+}
+
+func init() {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+}
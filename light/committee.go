@@ -0,0 +1,97 @@
+package light
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+// CommitteeLists are the committees and pending validators of the beacon and
+// the shards after the beacon block of height BeaconHeight, with the keys in
+// base58. The header of the block commits to them by its
+// BeaconCommitteeAndValidatorRoot and ShardCommitteeAndValidatorRoot.
+type CommitteeLists struct {
+	BeaconHeight           uint64
+	BeaconCommittee        []string
+	BeaconPendingValidator []string
+	ShardCommittee         map[byte][]string
+	ShardPendingValidator  map[byte][]string
+}
+
+func NewCommitteeLists(
+	beaconHeight uint64,
+	beaconCommittee []incognitokey.CommitteePublicKey,
+	beaconPendingValidator []incognitokey.CommitteePublicKey,
+	shardCommittee map[byte][]incognitokey.CommitteePublicKey,
+	shardPendingValidator map[byte][]incognitokey.CommitteePublicKey,
+) (*CommitteeLists, error) {
+	var err error
+	lists := &CommitteeLists{
+		BeaconHeight:          beaconHeight,
+		ShardCommittee:        map[byte][]string{},
+		ShardPendingValidator: map[byte][]string{},
+	}
+	if lists.BeaconCommittee, err = incognitokey.CommitteeKeyListToString(beaconCommittee); err != nil {
+		return nil, err
+	}
+	if lists.BeaconPendingValidator, err = incognitokey.CommitteeKeyListToString(beaconPendingValidator); err != nil {
+		return nil, err
+	}
+	for shardID, keys := range shardCommittee {
+		if lists.ShardCommittee[shardID], err = incognitokey.CommitteeKeyListToString(keys); err != nil {
+			return nil, err
+		}
+	}
+	for shardID, keys := range shardPendingValidator {
+		if lists.ShardPendingValidator[shardID], err = incognitokey.CommitteeKeyListToString(keys); err != nil {
+			return nil, err
+		}
+	}
+	return lists, nil
+}
+
+// BeaconRoot returns the BeaconCommitteeAndValidatorRoot of the lists
+func (lists *CommitteeLists) BeaconRoot() (common.Hash, error) {
+	return blockchain.CommitteeRoot(lists.BeaconCommittee, lists.BeaconPendingValidator)
+}
+
+// ShardRoot returns the ShardCommitteeAndValidatorRoot of the lists
+func (lists *CommitteeLists) ShardRoot() (common.Hash, error) {
+	return blockchain.ShardCommitteeRoot(lists.ShardPendingValidator, lists.ShardCommittee)
+}
+
+// Verify returns an error if header does not commit to the lists
+func (lists *CommitteeLists) Verify(header *blockchain.BeaconBlock) error {
+	if lists.BeaconHeight != header.Header.Height {
+		return fmt.Errorf("committee lists of beacon height %v for block %v", lists.BeaconHeight, header.Header.Height)
+	}
+	root, err := lists.BeaconRoot()
+	if err != nil {
+		return err
+	}
+	if !root.IsEqual(&header.Header.BeaconCommitteeAndValidatorRoot) {
+		return fmt.Errorf("%v: beacon root %v, header %v", ErrCommitteeRoot, root, header.Header.BeaconCommitteeAndValidatorRoot)
+	}
+	return lists.verifyShards(header.Header.ShardCommitteeAndValidatorRoot)
+}
+
+func (lists *CommitteeLists) verifyShards(shardRoot common.Hash) error {
+	root, err := lists.ShardRoot()
+	if err != nil {
+		return err
+	}
+	if !root.IsEqual(&shardRoot) {
+		return fmt.Errorf("%v: shard root %v, header %v", ErrCommitteeRoot, root, shardRoot)
+	}
+	return nil
+}
+
+func (lists *CommitteeLists) beaconCommittee() ([]incognitokey.CommitteePublicKey, error) {
+	return incognitokey.CommitteeBase58KeyListToStruct(lists.BeaconCommittee)
+}
+
+func (lists *CommitteeLists) shardCommittee(shardID byte) ([]incognitokey.CommitteePublicKey, error) {
+	return incognitokey.CommitteeBase58KeyListToStruct(lists.ShardCommittee[shardID])
+}
@@ -0,0 +1,14 @@
+package light
+
+import "github.com/incognitochain/incognito-chain/common"
+
+type LightLogger struct {
+	common.Logger
+}
+
+func (self *LightLogger) Init(inst common.Logger) {
+	self.Logger = inst
+}
+
+// Global instant to use
+var Logger = LightLogger{}
@@ -0,0 +1,134 @@
+package light
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// TxProof proves that a transaction is in a shard block by the Merkle path
+// from its hash to the TxRoot of the block header. Tx is the transaction in
+// json, for the light client to read its outputs.
+type TxProof struct {
+	ShardID     byte
+	BlockHeight uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	Index       int
+	Path        []common.Hash
+	Tx          json.RawMessage `json:",omitempty"`
+}
+
+// BuildTxProof returns the proof of the transaction at index in block
+func BuildTxProof(block *blockchain.ShardBlock, index int) (*TxProof, error) {
+	txs := block.Body.Transactions
+	if index < 0 || index >= len(txs) {
+		return nil, fmt.Errorf("no transaction %v in block %v", index, block.Header.Height)
+	}
+	tx, err := json.Marshal(txs[index])
+	if err != nil {
+		return nil, err
+	}
+	tree := blockchain.Merkle{}.BuildMerkleTreeStore(txs)
+	return &TxProof{
+		ShardID:     block.Header.ShardID,
+		BlockHeight: block.Header.Height,
+		BlockHash:   *block.Hash(),
+		TxHash:      *txs[index].Hash(),
+		Index:       index,
+		Path:        merklePath(tree, index),
+		Tx:          tx,
+	}, nil
+}
+
+// merklePath returns the siblings of the nodes from the leaf at index to the
+// root of tree, stored as by Merkle.BuildMerkleTreeStore. A node without
+// sibling is hashed with itself, so it is its own sibling.
+func merklePath(tree []*common.Hash, index int) []common.Hash {
+	path := []common.Hash{}
+	start, width := 0, (len(tree)+1)/2
+	for ; width > 1; width /= 2 {
+		sibling := tree[start+(index^1)]
+		if sibling == nil {
+			sibling = tree[start+index]
+		}
+		path = append(path, *sibling)
+		start += width
+		index /= 2
+	}
+	return path
+}
+
+// Verify returns an error if the path of the proof does not lead from the
+// transaction hash to txRoot
+func (proof *TxProof) Verify(txRoot common.Hash) error {
+	hash := proof.TxHash
+	index := proof.Index
+	for _, sibling := range proof.Path {
+		if index%2 == 0 {
+			hash = hashMerkleBranches(hash, sibling)
+		} else {
+			hash = hashMerkleBranches(sibling, hash)
+		}
+		index /= 2
+	}
+	if index != 0 || !hash.IsEqual(&txRoot) {
+		return ErrTxProof
+	}
+	return nil
+}
+
+// Transaction decodes the transaction of the proof, and checks it has the
+// hash of the proof
+func (proof *TxProof) Transaction() (metadata.Transaction, error) {
+	if len(proof.Tx) == 0 {
+		return nil, errors.New("no transaction in the proof")
+	}
+	// the shard block body decodes the transactions of every type
+	body := blockchain.ShardBody{}
+	data, err := json.Marshal(map[string][]json.RawMessage{"Transactions": {proof.Tx}})
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	if len(body.Transactions) != 1 || !body.Transactions[0].Hash().IsEqual(&proof.TxHash) {
+		return nil, ErrTxProof
+	}
+	return body.Transactions[0], nil
+}
+
+func hashMerkleBranches(left common.Hash, right common.Hash) common.Hash {
+	var data [common.HashSize * 2]byte
+	copy(data[:common.HashSize], left[:])
+	copy(data[common.HashSize:], right[:])
+	return common.HashH(data[:])
+}
+
+// VerifyInstruction returns true if path leads from the instruction inst,
+// flattened as by blockchain.FlattenAndConvertStringInst, to the keccak256
+// InstructionMerkleRoot root. left tells which nodes of path are on the left,
+// as returned by blockchain.GetKeccak256MerkleProofFromTree.
+func VerifyInstruction(inst []byte, path [][]byte, left []bool, root common.Hash) bool {
+	if len(path) != len(left) {
+		return false
+	}
+	hash := common.Keccak256(inst)
+	for i, sibling := range path {
+		// a node without sibling is hashed with itself
+		if len(sibling) == 0 {
+			sibling = hash[:]
+		}
+		if left[i] {
+			hash = common.Keccak256(sibling, hash[:])
+		} else {
+			hash = common.Keccak256(hash[:], sibling)
+		}
+	}
+	return hash.IsEqual(&root)
+}
@@ -0,0 +1,83 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTxs returns n transactions told apart by their lock times from
+// lockTime on
+func newTestTxs(n int, lockTime int64) []metadata.Transaction {
+	txs := []metadata.Transaction{}
+	for i := 0; i < n; i++ {
+		txs = append(txs, &transaction.Tx{Version: 1, Type: common.TxNormalType, LockTime: lockTime + int64(i), Fee: uint64(i)})
+	}
+	return txs
+}
+
+func TestTxProof(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		block := &blockchain.ShardBlock{}
+		block.Body.Transactions = newTestTxs(n, 1)
+		block.Header.Height = 5
+		root := blockchain.Merkle{}.BuildMerkleTreeStore(block.Body.Transactions)
+		txRoot := *root[len(root)-1]
+		for i := 0; i < n; i++ {
+			proof, err := BuildTxProof(block, i)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, proof.Verify(txRoot), "tx %v of %v", i, n)
+			tx, err := proof.Transaction()
+			if assert.NoError(t, err) {
+				assert.Equal(t, block.Body.Transactions[i].Hash(), tx.Hash())
+			}
+
+			// the proof of a tx does not prove another one
+			other := *proof
+			other.TxHash = *block.Body.Transactions[(i+1)%n].Hash()
+			if n > 1 {
+				assert.Equal(t, ErrTxProof, other.Verify(txRoot))
+			}
+			other = *proof
+			other.Index = i + blockchain.NextPowerOfTwo(n)
+			assert.Equal(t, ErrTxProof, other.Verify(txRoot))
+		}
+	}
+	_, err := BuildTxProof(&blockchain.ShardBlock{}, 0)
+	assert.Error(t, err)
+}
+
+func TestTxProofTransaction(t *testing.T) {
+	block := &blockchain.ShardBlock{}
+	block.Body.Transactions = newTestTxs(2, 1)
+	proof, err := BuildTxProof(block, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the transaction must have the hash of the proof
+	proof.TxHash = *block.Body.Transactions[0].Hash()
+	_, err = proof.Transaction()
+	assert.Equal(t, ErrTxProof, err)
+}
+
+func TestVerifyInstruction(t *testing.T) {
+	for n := 1; n <= 7; n++ {
+		insts := [][]byte{}
+		for i := 0; i < n; i++ {
+			insts = append(insts, []byte{byte(i), 42})
+		}
+		tree := blockchain.BuildKeccak256MerkleTree(insts)
+		root := common.BytesToHash(tree[len(tree)-1])
+		for i := range insts {
+			path, left := blockchain.GetKeccak256MerkleProofFromTree(tree, i)
+			assert.True(t, VerifyInstruction(insts[i], path, left, root), "inst %v of %v", i, n)
+			assert.False(t, VerifyInstruction([]byte{byte(i), 43}, path, left, root))
+		}
+	}
+}
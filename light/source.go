@@ -0,0 +1,208 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// Source serves the light client the data it verifies. Headers are blocks
+// without bodies.
+type Source interface {
+	BeaconHeaders(ctx context.Context, from, to uint64) ([]*blockchain.BeaconBlock, error)
+	ShardHeaders(ctx context.Context, shardID byte, from, to uint64) ([]*blockchain.ShardBlock, error)
+	Committees(ctx context.Context, beaconHeight uint64) (*CommitteeLists, error)
+	TxProof(ctx context.Context, txHash common.Hash) (*TxProof, error)
+}
+
+// RPC methods of full nodes serving light clients
+const (
+	GetBeaconHeadersMethod = "getbeaconheaders"
+	GetShardHeadersMethod  = "getshardheaders"
+	GetCommitteeKeysMethod = "getcommitteekeys"
+	GetTxProofMethod       = "gettxproof"
+)
+
+// RPCSource requests the data to the RPC server of a full node
+type RPCSource struct {
+	address string
+	client  *http.Client
+}
+
+func NewRPCSource(address string) *RPCSource {
+	return &RPCSource{address: address, client: &http.Client{Timeout: RPCTimeout}}
+}
+
+type jsonRequest struct {
+	Jsonrpc string      `json:"Jsonrpc"`
+	Method  string      `json:"Method"`
+	Params  interface{} `json:"Params"`
+	Id      interface{} `json:"Id"`
+}
+
+type jsonResponse struct {
+	Result json.RawMessage `json:"Result"`
+	Error  *struct {
+		Code       int    `json:"Code"`
+		Message    string `json:"Message"`
+		StackTrace string `json:"StackTrace"`
+	} `json:"Error"`
+}
+
+func (s *RPCSource) call(ctx context.Context, res interface{}, method string, params ...interface{}) error {
+	data, err := json.Marshal(jsonRequest{Jsonrpc: "1.0", Method: method, Params: params, Id: 1})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.address, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	jsonResp := jsonResponse{}
+	if err := json.Unmarshal(data, &jsonResp); err != nil {
+		return fmt.Errorf("%v: %v", method, err)
+	}
+	if jsonResp.Error != nil {
+		// the trace has the error of the full node after the message
+		if jsonResp.Error.StackTrace != "" {
+			return fmt.Errorf("%v: %v", method, jsonResp.Error.StackTrace)
+		}
+		return fmt.Errorf("%v: %v", method, jsonResp.Error.Message)
+	}
+	return json.Unmarshal(jsonResp.Result, res)
+}
+
+func (s *RPCSource) BeaconHeaders(ctx context.Context, from, to uint64) ([]*blockchain.BeaconBlock, error) {
+	headers := []*blockchain.BeaconBlock{}
+	err := s.call(ctx, &headers, GetBeaconHeadersMethod, from, to)
+	return headers, err
+}
+
+func (s *RPCSource) ShardHeaders(ctx context.Context, shardID byte, from, to uint64) ([]*blockchain.ShardBlock, error) {
+	headers := []*blockchain.ShardBlock{}
+	err := s.call(ctx, &headers, GetShardHeadersMethod, shardID, from, to)
+	return headers, err
+}
+
+func (s *RPCSource) Committees(ctx context.Context, beaconHeight uint64) (*CommitteeLists, error) {
+	lists := &CommitteeLists{}
+	if err := s.call(ctx, lists, GetCommitteeKeysMethod, beaconHeight); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (s *RPCSource) TxProof(ctx context.Context, txHash common.Hash) (*TxProof, error) {
+	proof := &TxProof{}
+	if err := s.call(ctx, proof, GetTxProofMethod, txHash.String()); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// streamFunc streams the blocks of the heights from to to of a chain
+type streamFunc func(ctx context.Context, from, to uint64) (chan common.BlockInterface, error)
+
+// StreamSource streams the headers from the peers of a node, and requests the
+// committee lists and proofs, which are not served over p2p, to another
+// source
+type StreamSource struct {
+	Source
+	beaconStream streamFunc
+	shardStream  func(shardID byte) streamFunc
+}
+
+// NewStreamSource streams the headers with the header streams of a node,
+// which are given a peer id the highway picks the peer for when empty
+func NewStreamSource(
+	source Source,
+	beaconStream func(ctx context.Context, peerID string, from, to uint64) (chan common.BlockInterface, error),
+	shardStream func(ctx context.Context, peerID string, fromSID int, from, to uint64) (chan common.BlockInterface, error),
+) *StreamSource {
+	return &StreamSource{
+		Source: source,
+		beaconStream: func(ctx context.Context, from, to uint64) (chan common.BlockInterface, error) {
+			return beaconStream(ctx, "", from, to)
+		},
+		shardStream: func(shardID byte) streamFunc {
+			return func(ctx context.Context, from, to uint64) (chan common.BlockInterface, error) {
+				return shardStream(ctx, "", int(shardID), from, to)
+			}
+		},
+	}
+}
+
+func (s *StreamSource) BeaconHeaders(ctx context.Context, from, to uint64) ([]*blockchain.BeaconBlock, error) {
+	blocks, err := readStream(ctx, s.beaconStream, from, to)
+	headers := []*blockchain.BeaconBlock{}
+	for _, blk := range blocks {
+		header, ok := blk.(*blockchain.BeaconBlock)
+		if !ok {
+			return nil, errors.New("not a beacon block")
+		}
+		headers = append(headers, header)
+	}
+	return headers, err
+}
+
+func (s *StreamSource) ShardHeaders(ctx context.Context, shardID byte, from, to uint64) ([]*blockchain.ShardBlock, error) {
+	blocks, err := readStream(ctx, s.shardStream(shardID), from, to)
+	headers := []*blockchain.ShardBlock{}
+	for _, blk := range blocks {
+		header, ok := blk.(*blockchain.ShardBlock)
+		if !ok {
+			return nil, errors.New("not a shard block")
+		}
+		headers = append(headers, header)
+	}
+	return headers, err
+}
+
+// readStream reads the blocks of a stream until it ends, the last height is
+// read or StreamTimeout
+func readStream(ctx context.Context, stream streamFunc, from, to uint64) ([]common.BlockInterface, error) {
+	ctx, cancel := context.WithTimeout(ctx, StreamTimeout)
+	defer cancel()
+	ch, err := stream(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	blocks := []common.BlockInterface{}
+	for {
+		select {
+		case blk, ok := <-ch:
+			if !ok || blk == nil {
+				return blocks, nil
+			}
+			blocks = append(blocks, blk)
+			if blk.GetHeight() >= to {
+				return blocks, nil
+			}
+		case <-ctx.Done():
+			return blocks, nil
+		}
+	}
+}
+
+var (
+	RPCTimeout    = 60 * time.Second // Time to answer an RPC of RPCSource
+	StreamTimeout = 30 * time.Second // Time to stream headers to StreamSource
+)
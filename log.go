@@ -8,6 +8,7 @@ import (
 	"github.com/incognitochain/incognito-chain/dataaccessobject"
 	relaying "github.com/incognitochain/incognito-chain/relaying/bnb"
 	btcRelaying "github.com/incognitochain/incognito-chain/relaying/btc"
+	"github.com/incognitochain/incognito-chain/relaying/ethbridge"
 
 	"github.com/incognitochain/incognito-chain/syncker"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/incognitochain/incognito-chain/consensus"
 	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/light"
 	"github.com/incognitochain/incognito-chain/mempool"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/netsync"
@@ -66,6 +68,8 @@ var (
 	daov2Logger            = backendLog.Logger("DAO log", false)
 	btcRelayingLogger      = backendLog.Logger("BTC relaying log", false)
 	synckerLogger          = backendLog.Logger("Syncker log ", false)
+	lightLogger            = backendLog.Logger("Light client log", false)
+	ethBridgeLogger        = backendLog.Logger("ETH bridge relayer log", false)
 )
 
 // logWriter implements an io.Writer that outputs to both standard output and
@@ -109,6 +113,8 @@ func init() {
 	dataaccessobject.Logger.Init(daov2Logger)
 	btcRelaying.Logger.Init(btcRelayingLogger)
 	syncker.Logger.Init(synckerLogger)
+	light.Logger.Init(lightLogger)
+	ethbridge.Logger.Init(ethBridgeLogger)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -138,6 +144,8 @@ var subsystemLoggers = map[string]common.Logger{
 	"DAO":               daov2Logger,
 	"BTCRELAYING":       btcRelayingLogger,
 	"SYNCKER":           synckerLogger,
+	"LIGHT":             lightLogger,
+	"ETHBRIDGE":         ethBridgeLogger,
 }
 
 // initLogRotator initializes the logging rotater to write logs to logFile and
@@ -185,6 +193,25 @@ func setLogLevels(logLevel string) {
 	}
 }
 
+// logLevels reads and changes the levels of the subsystem loggers at runtime,
+// for the log level RPCs
+type logLevels struct{}
+
+// GetLogLevels returns the level of every subsystem logger
+func (logLevels) GetLogLevels() map[string]string {
+	levels := map[string]string{}
+	for subsystemID, logger := range subsystemLoggers {
+		levels[subsystemID] = logger.Level().String()
+	}
+	return levels
+}
+
+// SetLogLevels sets the levels of the subsystem loggers, it accepts the
+// syntax of --loglevel
+func (logLevels) SetLogLevels(levels string) error {
+	return parseAndSetDebugLevels(levels)
+}
+
 type MainLogger struct {
 	log common.Logger
 }
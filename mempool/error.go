@@ -45,6 +45,9 @@ const (
 	ValidateAggSignatureForCrossShardBlockError
 	DuplicateSerialNumbersHashError
 	CouldNotGetExchangeRateError
+	RejectTimeLockNotYetValid
+	RejectExpiredTimeLockTx
+	MaxHeldTxsError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -87,6 +90,9 @@ var ErrCodeMessage = map[int]struct {
 	CouldNotGetExchangeRateError:                {-1032, "Could not get the exchange rate error"},
 	RejectSanityTxLocktime:                      {-1033, "Wrong tx locktime"},
 	RejectMetadataWithBlockchainTx:              {-1034, "Reject invalid metadata with blockchain"},
+	RejectTimeLockNotYetValid:                   {-1035, "Reject time-locked tx before its window"},
+	RejectExpiredTimeLockTx:                     {-1036, "Reject expired time-locked tx"},
+	MaxHeldTxsError:                             {-1037, "Max Held Time-Locked Txs Error"},
 }
 
 type MempoolTxError struct {
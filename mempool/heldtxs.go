@@ -0,0 +1,218 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/txtracker"
+)
+
+// heldTxView is the shard view a time-locked tx is validated on before its
+// window opens: the beacon height is the one the tx becomes valid at
+type heldTxView struct {
+	*blockchain.ShardBestState
+	beaconHeight uint64
+}
+
+func (view heldTxView) GetBeaconHeight() uint64 {
+	return view.beaconHeight
+}
+
+// holdTx keeps a time-locked tx validated before its window opens, it is
+// moved to the pool by releaseHeldTxs. The caller holds tp.mtx.
+func (tp *TxPool) holdTx(shardView *blockchain.ShardBestState, tx metadata.Transaction) (*common.Hash, *TxDesc, error) {
+	txHash := *tx.Hash()
+	timeLock, ok := tx.GetMetadata().(*metadata.TimeLock)
+	if !ok {
+		return nil, nil, NewMempoolTxError(RejectInvalidTx, errors.New("only time-locked transactions are held"))
+	}
+	if uint64(len(tp.heldTxs)) >= tp.config.MaxHeldTx {
+		err := NewMempoolTxError(MaxHeldTxsError, fmt.Errorf("%+v time-locked transactions are already held", len(tp.heldTxs)))
+		txLogger(tx).Error(err)
+		txtracker.Rejected(tx, err)
+		return nil, nil, err
+	}
+	txD := createTxDescMempool(tx, shardView.BestBlock.Header.Height, tx.GetTxFee(), tx.GetTxFeeToken())
+	if tp.config.PersistMempool {
+		if err := tp.addTransactionToDatabaseMempool(&txHash, *txD); err != nil {
+			Logger.log.Errorf("Fail to add held tx %+v to mempool database %+v \n", txHash, err)
+		}
+	}
+	tp.addHeldTx(txD)
+	txLogger(tx).Infof("Hold time-locked tx %+v until beacon height %+v", txHash.String(), timeLock.NotBefore)
+	txtracker.Held(tx, timeLock.NotBefore)
+	return &txHash, txD, nil
+}
+
+// releaseHeldTxs moves the held txs whose window is open at the beacon height
+// of the best view of their shard to the pool, and drops the expired ones
+func (tp *TxPool) releaseHeldTxs() {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+	if len(tp.heldTxs) == 0 {
+		return
+	}
+	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	for txHash, txDesc := range tp.heldTxs {
+		tx := txDesc.Desc.Tx
+		timeLock := tx.GetMetadata().(*metadata.TimeLock)
+		senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+		shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
+		beaconHeight := shardView.GetBeaconHeight()
+		expired := timeLock.IsExpiredAt(beaconHeight)
+		if !expired && !timeLock.IsOpenAt(beaconHeight) {
+			continue
+		}
+		tp.removeHeldTx(txHash)
+		if expired {
+			txLogger(tx).Infof("Drop time-locked tx %+v expired at beacon height %+v", txHash.String(), timeLock.NotAfter)
+			txtracker.Evicted(tx, "time lock expired")
+			tp.removeHeldTxFromDatabaseMP(&txHash)
+			continue
+		}
+		_, _, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, tp.config.PersistMempool, true, int64(beaconView.BeaconHeight))
+		if err != nil {
+			txLogger(tx).Error(err)
+			txtracker.Rejected(tx, err)
+			tp.removeHeldTxFromDatabaseMP(&txHash)
+			continue
+		}
+		txLogger(tx).Infof("Release time-locked tx %+v at beacon height %+v", txHash.String(), beaconHeight)
+		txtracker.Validated(tx)
+		if tp.IsBlockGenStarted && tp.IsUnlockMempool {
+			go func(tx metadata.Transaction) {
+				tp.CPendingTxs <- tx
+			}(tx)
+		}
+	}
+}
+
+// reloadHeldTx holds again a time-locked tx of the mempool database, it is
+// dropped if it was held longer than HeldTxLifeTime at now, if too many txs
+// are held or if it is not validated by current blockchain db
+func (tp *TxPool) reloadHeldTx(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, txDesc *TxDesc, now time.Time) bool {
+	txHash := txDesc.Desc.Tx.Hash()
+	if tp.isStaleHeldTx(txDesc, now) {
+		Logger.log.Infof("Drop time-locked tx %+v held since %+v", txHash.String(), txDesc.StartTime)
+		tp.removeHeldTxFromDatabaseMP(txHash)
+		return false
+	}
+	if uint64(len(tp.heldTxs)) >= tp.config.MaxHeldTx {
+		Logger.log.Errorf("Drop time-locked tx %+v, %+v time-locked transactions are already held", txHash.String(), len(tp.heldTxs))
+		tp.removeHeldTxFromDatabaseMP(txHash)
+		return false
+	}
+	if err := tp.validateTransaction(shardView, beaconView, txDesc.Desc.Tx, -1, false, false); !isNotYetValid(err) {
+		Logger.log.Errorf("Drop time-locked tx %+v not held anymore %+v", txHash.String(), err)
+		tp.removeHeldTxFromDatabaseMP(txHash)
+		return false
+	}
+	tp.addHeldTx(txDesc)
+	return true
+}
+
+// addHeldTx adds txDesc to the held txs with its serial numbers. The caller
+// holds tp.mtx.
+func (tp *TxPool) addHeldTx(txDesc *TxDesc) {
+	tx := txDesc.Desc.Tx
+	txHash := *tx.Hash()
+	tp.heldTxs[txHash] = txDesc
+	for _, serialNumber := range tx.ListSerialNumbersHashH() {
+		tp.heldSerialNumbers[serialNumber] = txHash
+	}
+}
+
+// removeHeldTx removes the held tx of txHash with its serial numbers. The
+// caller holds tp.mtx.
+func (tp *TxPool) removeHeldTx(txHash common.Hash) {
+	txDesc, ok := tp.heldTxs[txHash]
+	if !ok {
+		return
+	}
+	delete(tp.heldTxs, txHash)
+	for _, serialNumber := range txDesc.Desc.Tx.ListSerialNumbersHashH() {
+		if tp.heldSerialNumbers[serialNumber] == txHash {
+			delete(tp.heldSerialNumbers, serialNumber)
+		}
+	}
+}
+
+// validateTxWithHeldTxs rejects tx if it spends a coin a held tx spends, held
+// txs are not replaced as their fee only counts once they are released
+func (tp *TxPool) validateTxWithHeldTxs(tx metadata.Transaction) error {
+	for _, serialNumber := range tx.ListSerialNumbersHashH() {
+		if heldTxHash, ok := tp.heldSerialNumbers[serialNumber]; ok {
+			return NewMempoolTxError(RejectDoubleSpendWithMempoolTx, fmt.Errorf("input coin of transaction %+v is spent by held time-locked transaction %+v", tx.Hash().String(), heldTxHash.String()))
+		}
+	}
+	return nil
+}
+
+// evictStaleHeldTxs drops the held txs older than HeldTxLifeTime, whether or
+// not their window ever closes
+func (tp *TxPool) evictStaleHeldTxs(now time.Time) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+	for txHash, txDesc := range tp.heldTxs {
+		if !tp.isStaleHeldTx(txDesc, now) {
+			continue
+		}
+		tp.removeHeldTx(txHash)
+		tp.removeHeldTxFromDatabaseMP(&txHash)
+		txLogger(txDesc.Desc.Tx).Infof("Drop time-locked tx %+v held since %+v", txHash.String(), txDesc.StartTime)
+		txtracker.Evicted(txDesc.Desc.Tx, "held too long")
+	}
+}
+
+// isStaleHeldTx returns whether txDesc has been held longer than
+// HeldTxLifeTime at now
+func (tp *TxPool) isStaleHeldTx(txDesc *TxDesc, now time.Time) bool {
+	return now.Sub(txDesc.StartTime) > time.Duration(tp.config.HeldTxLifeTime)*time.Second
+}
+
+func (tp *TxPool) removeHeldTxFromDatabaseMP(txHash *common.Hash) {
+	if !tp.config.PersistMempool {
+		return
+	}
+	if err := tp.removeTransactionFromDatabaseMP(txHash); err != nil {
+		Logger.log.Error(err)
+	}
+}
+
+// isNotYetValid returns whether err rejects a time-locked tx validated before
+// its window opens
+func isNotYetValid(err error) bool {
+	mempoolErr, ok := err.(*MempoolTxError)
+	return ok && mempoolErr.Code == ErrCodeMessage[RejectTimeLockNotYetValid].Code
+}
+
+// isHeldAt returns whether tx is a time-locked tx the mempool holds at the
+// beacon height of shardView
+func isHeldAt(shardView *blockchain.ShardBestState, tx metadata.Transaction) bool {
+	timeLock, ok := tx.GetMetadata().(*metadata.TimeLock)
+	if !ok {
+		return false
+	}
+	beaconHeight := shardView.GetBeaconHeight()
+	return !timeLock.IsExpiredAt(beaconHeight) && !timeLock.IsOpenAt(beaconHeight)
+}
+
+// HeldTxs returns the time-locked txs waiting for their window, by the
+// beacon height they become valid at
+func (tp *TxPool) HeldTxs() []TxDesc {
+	tp.mtx.RLock()
+	defer tp.mtx.RUnlock()
+	txDescs := make([]TxDesc, 0, len(tp.heldTxs))
+	for _, txDesc := range tp.heldTxs {
+		txDescs = append(txDescs, *txDesc)
+	}
+	sort.Slice(txDescs, func(i, j int) bool {
+		return txDescs[i].Desc.Tx.GetMetadata().(*metadata.TimeLock).NotBefore < txDescs[j].Desc.Tx.GetMetadata().(*metadata.TimeLock).NotBefore
+	})
+	return txDescs
+}
@@ -0,0 +1,220 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/databasemp"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// heldTestTx is a time-locked tx spending the coins of serialNumbers
+type heldTestTx struct {
+	metadata.Transaction
+	hash          common.Hash
+	timeLock      *metadata.TimeLock
+	serialNumbers []common.Hash
+}
+
+func newHeldTestTx(id byte, notBefore, notAfter uint64, serialNumbers ...common.Hash) *heldTestTx {
+	return &heldTestTx{
+		hash:          common.Hash{id},
+		timeLock:      &metadata.TimeLock{MetadataBase: *metadata.NewMetadataBase(metadata.TimeLockMeta), NotBefore: notBefore, NotAfter: notAfter},
+		serialNumbers: serialNumbers,
+	}
+}
+
+func (tx *heldTestTx) Hash() *common.Hash                    { return &tx.hash }
+func (tx *heldTestTx) GetMetadata() metadata.Metadata        { return tx.timeLock }
+func (tx *heldTestTx) ListSerialNumbersHashH() []common.Hash { return tx.serialNumbers }
+func (tx *heldTestTx) GetSenderAddrLastByte() byte           { return 0 }
+func (tx *heldTestTx) GetTxFee() uint64                      { return 0 }
+func (tx *heldTestTx) GetTxFeeToken() uint64                 { return 0 }
+
+// heldTestDatabase is a mempool database keeping only the hashes of its txs
+type heldTestDatabase struct {
+	databasemp.DatabaseInterface
+	txs map[common.Hash]bool
+}
+
+func (db *heldTestDatabase) HasTransaction(txHash *common.Hash) (bool, error) {
+	return db.txs[*txHash], nil
+}
+
+func (db *heldTestDatabase) RemoveTransaction(txHash *common.Hash) error {
+	delete(db.txs, *txHash)
+	return nil
+}
+
+// heldTestChain activates the time lock fork from beacon height 1
+type heldTestChain struct {
+	metadata.ChainRetriever
+}
+
+func (chain heldTestChain) IsForkActive(fork forks.Fork, beaconHeight uint64) bool {
+	return fork == forks.TimeLock && beaconHeight >= 1
+}
+
+func newHeldTestPool(maxHeldTx uint64) *TxPool {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	tp := &TxPool{}
+	tp.config = Config{MaxHeldTx: maxHeldTx, HeldTxLifeTime: 60}
+	tp.heldTxs = make(map[common.Hash]*TxDesc)
+	tp.heldSerialNumbers = make(map[common.Hash]common.Hash)
+	return tp
+}
+
+var heldTestShardView = &blockchain.ShardBestState{BestBlock: &blockchain.ShardBlock{}}
+
+func TestHoldTxMaxHeldTx(t *testing.T) {
+	tp := newHeldTestPool(2)
+	// the txs in the pool do not count in the held txs
+	tp.pool = map[common.Hash]*TxDesc{{9}: {}, {10}: {}, {11}: {}}
+	for id := byte(1); id <= 2; id++ {
+		if _, _, err := tp.holdTx(heldTestShardView, newHeldTestTx(id, 100, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, _, err := tp.holdTx(heldTestShardView, newHeldTestTx(3, 100, 0))
+	if mempoolErr, ok := err.(*MempoolTxError); !ok || mempoolErr.Code != ErrCodeMessage[MaxHeldTxsError].Code {
+		t.Fatalf("third tx held with error %v", err)
+	}
+	if len(tp.heldTxs) != 2 {
+		t.Fatalf("%d txs held", len(tp.heldTxs))
+	}
+	tp.removeHeldTx(common.Hash{1})
+	if _, _, err := tp.holdTx(heldTestShardView, newHeldTestTx(3, 100, 0)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeldTxsSerialNumbers(t *testing.T) {
+	tp := newHeldTestPool(10)
+	serialNumber := common.HashH([]byte{1})
+	held := newHeldTestTx(1, 100, 0, serialNumber, common.HashH([]byte{2}))
+	if _, _, err := tp.holdTx(heldTestShardView, held); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		tx    *heldTestTx
+		valid bool
+	}{
+		{"spends a coin of the held tx", newHeldTestTx(2, 0, 0, serialNumber), false},
+		{"spends the coins of the held tx", newHeldTestTx(3, 100, 0, common.HashH([]byte{2}), serialNumber), false},
+		{"spends other coins", newHeldTestTx(4, 100, 0, common.HashH([]byte{3})), true},
+		{"spends no coin", newHeldTestTx(5, 100, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tp.validateTxWithHeldTxs(tt.tx)
+			if (err == nil) != tt.valid {
+				t.Fatalf("valid %v, error %v", tt.valid, err)
+			}
+			if !tt.valid && err.(*MempoolTxError).Code != ErrCodeMessage[RejectDoubleSpendWithMempoolTx].Code {
+				t.Fatalf("wrong error %v", err)
+			}
+		})
+	}
+	if tp.ValidateSerialNumberHashH([]byte{1}) == nil {
+		t.Fatal("coin spent by the held tx selectable")
+	}
+	tp.removeHeldTx(*held.Hash())
+	if err := tp.validateTxWithHeldTxs(tests[0].tx); err != nil {
+		t.Fatalf("coin still spent once the tx is released %v", err)
+	}
+	if err := tp.ValidateSerialNumberHashH([]byte{1}); err != nil {
+		t.Fatalf("coin not selectable once the tx is released %v", err)
+	}
+}
+
+func TestEvictStaleHeldTxs(t *testing.T) {
+	tp := newHeldTestPool(10)
+	db := &heldTestDatabase{txs: map[common.Hash]bool{}}
+	tp.config.PersistMempool = true
+	tp.config.DataBaseMempool = db
+	now := time.Now()
+	serialNumber := common.HashH([]byte{1})
+	tests := []struct {
+		tx    *heldTestTx
+		since time.Duration
+		stale bool
+	}{
+		{newHeldTestTx(1, 100, 0, serialNumber), 61 * time.Second, true},
+		{newHeldTestTx(2, 100, 200), 61 * time.Second, true},
+		{newHeldTestTx(3, 100, 0), 59 * time.Second, false},
+		{newHeldTestTx(4, 100, 200), 0, false},
+	}
+	for _, tt := range tests {
+		txD := createTxDescMempool(tt.tx, 1, 0, 0)
+		txD.StartTime = now.Add(-tt.since)
+		tp.addHeldTx(txD)
+		db.txs[*tt.tx.Hash()] = true
+	}
+	tp.evictStaleHeldTxs(now)
+	for _, tt := range tests {
+		_, held := tp.heldTxs[*tt.tx.Hash()]
+		if held == tt.stale || db.txs[*tt.tx.Hash()] == tt.stale {
+			t.Fatalf("tx %v held %v, stored %v, held for %v", tt.tx.Hash().String(), held, db.txs[*tt.tx.Hash()], tt.since)
+		}
+	}
+	if _, ok := tp.heldSerialNumbers[serialNumber]; ok {
+		t.Fatal("coin of the evicted tx still spent")
+	}
+}
+
+func TestReloadStaleHeldTx(t *testing.T) {
+	tp := newHeldTestPool(10)
+	db := &heldTestDatabase{txs: map[common.Hash]bool{}}
+	tp.config.PersistMempool = true
+	tp.config.DataBaseMempool = db
+	now := time.Now()
+	for _, tx := range []*heldTestTx{newHeldTestTx(1, 100, 0), newHeldTestTx(2, 100, 200)} {
+		txD := createTxDescMempool(tx, 1, 0, 0)
+		txD.StartTime = now.Add(-61 * time.Second)
+		db.txs[*tx.Hash()] = true
+		if tp.reloadHeldTx(heldTestShardView, nil, txD, now) {
+			t.Fatalf("tx %v held again", tx.Hash().String())
+		}
+		if db.txs[*tx.Hash()] {
+			t.Fatalf("tx %v still stored", tx.Hash().String())
+		}
+	}
+	if len(tp.heldTxs) != 0 {
+		t.Fatalf("%d txs held", len(tp.heldTxs))
+	}
+}
+
+func TestReloadHeldTxMaxHeldTx(t *testing.T) {
+	tp := newHeldTestPool(1)
+	db := &heldTestDatabase{txs: map[common.Hash]bool{}}
+	tp.config.PersistMempool = true
+	tp.config.DataBaseMempool = db
+	tp.addHeldTx(createTxDescMempool(newHeldTestTx(1, 100, 0), 1, 0, 0))
+	tx := newHeldTestTx(2, 100, 0)
+	db.txs[*tx.Hash()] = true
+	if tp.reloadHeldTx(heldTestShardView, nil, createTxDescMempool(tx, 1, 0, 0), time.Now()) {
+		t.Fatal("tx held over the max held txs")
+	}
+	if db.txs[*tx.Hash()] {
+		t.Fatal("tx still stored")
+	}
+}
+
+func TestHeldTxViewValidatesAtNotBefore(t *testing.T) {
+	tx := newHeldTestTx(1, 100, 200)
+	shardView := &blockchain.ShardBestState{BeaconHeight: 50}
+	if _, err := tx.timeLock.ValidateTxWithBlockChain(tx, heldTestChain{}, shardView, nil, 0, nil); err == nil {
+		t.Fatal("tx valid before its window")
+	}
+	view := heldTxView{ShardBestState: shardView, beaconHeight: tx.timeLock.NotBefore}
+	if view.GetBeaconHeight() != 100 {
+		t.Fatalf("held tx validated at beacon height %d", view.GetBeaconHeight())
+	}
+	if _, err := tx.timeLock.ValidateTxWithBlockChain(tx, heldTestChain{}, view, nil, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+}
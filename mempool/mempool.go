@@ -14,10 +14,19 @@ import (
 	"github.com/incognitochain/incognito-chain/pubsub"
 
 	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/metrics"
 	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/txtracker"
+)
+
+var (
+	poolSizeGauge     = metrics.NewRegisteredGauge("mempool/txs", nil)
+	poolAddedCounter  = metrics.NewRegisteredCounter("mempool/added", nil)
+	poolRemoveCounter = metrics.NewRegisteredCounter("mempool/removed", nil)
 )
 
 // default value
@@ -28,6 +37,8 @@ const (
 	defaultRoleInCommittees  = -1
 	defaultIsTest            = false
 	defaultReplaceFeeRatio   = 1.1
+	defaultMaxHeldTx         = 10000
+	defaultHeldTxLifeTime    = 7 * 24 * 60 * 60 // 7 days
 )
 
 // config is a descriptor containing the memory pool configuration.
@@ -39,12 +50,15 @@ type Config struct {
 	FeeEstimator      map[byte]*FeeEstimator // FeeEstimatator provides a feeEstimator. If it is not nil, the mempool records all new transactions it observes into the feeEstimator.
 	TxLifeTime        uint                   // Transaction life time in pool
 	MaxTx             uint64                 //Max transaction pool may have
+	MaxHeldTx         uint64                 //Max time-locked transaction pool may hold, not counted in MaxTx
+	HeldTxLifeTime    uint                   // Time-locked transaction life time in held txs, whatever its window
 	IsLoadFromMempool bool                   //Reset mempool database when run node
 	PersistMempool    bool
 	RelayShards       []byte
 	// UserKeyset            *incognitokey.KeySet
 	PubSubManager         *pubsub.PubSubManager
 	RoleInCommitteesEvent pubsub.EventChannel
+	NewShardBlockEvent    pubsub.EventChannel
 }
 
 // TxDesc is transaction message in mempool
@@ -61,6 +75,8 @@ type TxPool struct {
 	pool                      map[common.Hash]*TxDesc
 	poolSerialNumbersHashList map[common.Hash][]common.Hash // [txHash] -> list hash serialNumbers of input coin
 	poolSerialNumberHash      map[common.Hash]common.Hash   // [hash from list of serialNumber] -> txHash
+	heldTxs                   map[common.Hash]*TxDesc       // time-locked txs waiting for their window, guarded by mtx
+	heldSerialNumbers         map[common.Hash]common.Hash   // [hash serialNumber of input coin] -> held txHash, guarded by mtx
 	mtx                       sync.RWMutex
 	poolCandidate             map[common.Hash]string //Candidate List in mempool
 	candidateMtx              sync.RWMutex
@@ -88,11 +104,21 @@ func (tp *TxPool) Init(cfg *Config) {
 	tp.pool = make(map[common.Hash]*TxDesc)
 	tp.poolSerialNumbersHashList = make(map[common.Hash][]common.Hash)
 	tp.poolSerialNumberHash = make(map[common.Hash]common.Hash)
+	tp.heldTxs = make(map[common.Hash]*TxDesc)
+	tp.heldSerialNumbers = make(map[common.Hash]common.Hash)
+	if tp.config.MaxHeldTx == 0 {
+		tp.config.MaxHeldTx = defaultMaxHeldTx
+	}
+	if tp.config.HeldTxLifeTime == 0 {
+		tp.config.HeldTxLifeTime = defaultHeldTxLifeTime
+	}
 	tp.poolCandidate = make(map[common.Hash]string)
 	tp.poolRequestStopStaking = make(map[common.Hash]string)
 	tp.duplicateTxs = make(map[common.Hash]uint64)
 	_, subChanRole, _ := tp.config.PubSubManager.RegisterNewSubscriber(pubsub.ShardRoleTopic)
 	tp.config.RoleInCommitteesEvent = subChanRole
+	_, subChanShardBlock, _ := tp.config.PubSubManager.RegisterNewSubscriber(pubsub.NewShardblockTopic)
+	tp.config.NewShardBlockEvent = subChanShardBlock
 	tp.ScanTime = defaultScanTime
 	tp.IsUnlockMempool = defaultIsUnlockMempool
 	tp.IsBlockGenStarted = defaultIsBlockGenStarted
@@ -156,17 +182,20 @@ func (tp *TxPool) Start(cQuit chan struct{}) {
 					tp.RoleInCommittees = shardID
 				}()
 			}
+		case <-tp.config.NewShardBlockEvent:
+			tp.releaseHeldTxs()
 		}
 	}
 }
 
 func (tp *TxPool) MonitorPool() {
-	if tp.config.TxLifeTime == 0 {
-		return
-	}
 	ticker := time.NewTicker(tp.ScanTime)
 	defer ticker.Stop()
 	for _ = range ticker.C {
+		tp.evictStaleHeldTxs(time.Now())
+		if tp.config.TxLifeTime == 0 {
+			continue
+		}
 		tp.mtx.Lock()
 		ttl := time.Duration(tp.config.TxLifeTime) * time.Second
 		txsToBeRemoved := []*TxDesc{}
@@ -181,6 +210,7 @@ func (tp *TxPool) MonitorPool() {
 		for _, txDesc := range txsToBeRemoved {
 			txHash := *txDesc.Desc.Tx.Hash()
 			tp.removeTx(txDesc.Desc.Tx)
+			txtracker.Evicted(txDesc.Desc.Tx, "expired")
 			tp.TriggerCRemoveTxs(txDesc.Desc.Tx)
 			tp.removeCandidateByTxHash(txHash)
 			//tp.removeRequestStopStakingByTxHash(txHash)
@@ -220,20 +250,29 @@ func (tp *TxPool) MaybeAcceptTransaction(tx metadata.Transaction, beaconHeight i
 	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
 	if !tp.checkRelayShard(tx) && !tp.checkPublicKeyRole(tx) {
 		err := NewMempoolTxError(UnexpectedTransactionError, errors.New("Unexpected Transaction From Shard "+fmt.Sprintf("%d", senderShardID)))
-		Logger.log.Error(err)
+		txLogger(tx).Error(err)
+		txtracker.Rejected(tx, err)
 		return &common.Hash{}, &TxDesc{}, err
 	}
 	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
 	shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
 	//==========
-	if uint64(len(tp.pool)) >= tp.config.MaxTx {
-		return nil, nil, NewMempoolTxError(MaxPoolSizeError, errors.New("Pool reach max number of transaction"))
+	if uint64(len(tp.pool)) >= tp.config.MaxTx && !isHeldAt(shardView, tx) {
+		err := NewMempoolTxError(MaxPoolSizeError, errors.New("Pool reach max number of transaction"))
+		txLogger(tx).Warn(err)
+		txtracker.Rejected(tx, err)
+		return nil, nil, err
 	}
 	hash, txDesc, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, tp.config.PersistMempool, true, beaconHeight)
+	if isNotYetValid(err) {
+		return tp.holdTx(shardView, tx)
+	}
 	//==========
 	if err != nil {
-		Logger.log.Error(err)
+		txLogger(tx).Error(err)
+		txtracker.Rejected(tx, err)
 	} else {
+		txtracker.Validated(tx)
 		if tp.IsBlockGenStarted {
 			if tp.IsUnlockMempool {
 				go func(tx metadata.Transaction) {
@@ -260,7 +299,7 @@ func (tp *TxPool) MaybeAcceptTransactionForBlockProducing(tx metadata.Transactio
 	}
 	_, txDesc, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, false, false, int64(bHeight))
 	if err != nil {
-		Logger.log.Error(err)
+		txLogger(tx).Error(err)
 		return nil, err
 	}
 	tempTxDesc := &txDesc.Desc
@@ -335,11 +374,20 @@ func (tp *TxPool) maybeAcceptTransaction(shardView *blockchain.ShardBestState, b
 		return nil, nil, err
 	}
 	if isNewTransaction {
-		Logger.log.Infof("Add New Txs Into Pool %+v FROM SHARD %+v\n", *tx.Hash(), shardID)
+		txLogger(tx).Infof("Add New Txs Into Pool %+v FROM SHARD %+v\n", *tx.Hash(), shardID)
 	}
 	return tx.Hash(), txD, nil
 }
 
+// txLogger returns the logger of the entries about a tx, with the shard of its
+// sender
+func txLogger(tx metadata.Transaction) common.Logger {
+	return common.WithFields(Logger.log, common.Fields{
+		common.FieldChainID: int(common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())),
+		common.FieldTxHash:  tx.Hash().String(),
+	})
+}
+
 // createTxDescMempool - return an object TxDesc for mempool from original Tx
 func createTxDescMempool(tx metadata.Transaction, height uint64, fee uint64, feeToken uint64) *TxDesc {
 	txDesc := &TxDesc{
@@ -370,7 +418,7 @@ func (tp *TxPool) checkFees(
 		meta := tx.GetMetadata()
 		// verify at metadata level
 		if meta != nil {
-			ok := meta.CheckTransactionFee(tx, limitFee, beaconHeight, beaconView.GetBeaconFeatureStateDB())
+			ok := meta.CheckTransactionFee(tx, limitFee, beaconHeight, beaconView.GetBeaconFeatureStateDB(), tp.config.BlockChain)
 			if !ok {
 				Logger.log.Errorf("Error: %+v", NewMempoolTxError(RejectInvalidFee,
 					fmt.Errorf("transaction %+v: Invalid fee metadata",
@@ -384,7 +432,7 @@ func (tp *TxPool) checkFees(
 		feePToken := tx.GetTxFeeToken()
 		//convert fee in Ptoken to fee in native token (if feePToken > 0)
 		if feePToken > 0 {
-			feePTokenToNativeTokenTmp, err := metadata.ConvertPrivacyTokenToNativeToken(feePToken, tokenID, beaconHeight, beaconView.GetBeaconFeatureStateDB())
+			feePTokenToNativeTokenTmp, err := metadata.ConvertPrivacyTokenToNativeToken(tp.config.BlockChain, feePToken, tokenID, beaconHeight, beaconView.GetBeaconFeatureStateDB())
 			if err != nil {
 				Logger.log.Errorf("ERROR: %+v", NewMempoolTxError(RejectInvalidFee,
 					fmt.Errorf("transaction %+v: %+v %v can not convert to native token %+v",
@@ -413,7 +461,7 @@ func (tp *TxPool) checkFees(
 		if limitFee > 0 {
 			meta := tx.GetMetadata()
 			if meta != nil {
-				ok := tx.GetMetadata().CheckTransactionFee(tx, limitFee, beaconHeight, beaconView.GetBeaconFeatureStateDB())
+				ok := tx.GetMetadata().CheckTransactionFee(tx, limitFee, beaconHeight, beaconView.GetBeaconFeatureStateDB(), tp.config.BlockChain)
 				if !ok {
 					Logger.log.Errorf("ERROR: %+v", NewMempoolTxError(RejectInvalidFee,
 						fmt.Errorf("transaction %+v has %d fees which is under the required amount of %d",
@@ -489,6 +537,9 @@ func (tp *TxPool) validateTransaction(shardView *blockchain.ShardBestState, beac
 	if isTxInPool {
 		return NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already had transaction %+v in mempool", txHash.String()))
 	}
+	if _, ok := tp.heldTxs[*txHash]; ok {
+		return NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already held time-locked transaction %+v in mempool", txHash.String()))
+	}
 	// Condition 3: A standalone transaction must not be a salary transaction.
 	isSalaryTx := tx.IsSalaryTx()
 	if isSalaryTx {
@@ -515,6 +566,10 @@ func (tp *TxPool) validateTransaction(shardView *blockchain.ShardBestState, beac
 			return NewMempoolTxError(RejectDoubleSpendWithMempoolTx, err)
 		}
 	}
+	// Condition 5.2: check tx with the time-locked txs held in mempool
+	if err := tp.validateTxWithHeldTxs(tx); err != nil {
+		return err
+	}
 	// Condition 6: ValidateTransaction tx by it self
 	if !isBatch {
 		validated, errValidateTxByItself := tx.ValidateTxByItself(tx.IsPrivacy(), shardView.GetCopiedTransactionStateDB(), beaconView.GetBeaconFeatureStateDB(), tp.config.BlockChain, shardID, isNewTransaction, nil, nil)
@@ -522,8 +577,22 @@ func (tp *TxPool) validateTransaction(shardView *blockchain.ShardBestState, beac
 			return NewMempoolTxError(RejectInvalidTx, errValidateTxByItself)
 		}
 	}
+	// Condition 6.1: a time-locked tx must be within its window, the txs before it go through the next conditions
+	// at the beacon height their window opens, then they are held by MaybeAcceptTransaction
+	var validationView metadata.ShardViewRetriever = shardView
+	var notYetValidErr error
+	if timeLock, ok := tx.GetMetadata().(*metadata.TimeLock); ok {
+		beaconHeight := shardView.GetBeaconHeight()
+		if timeLock.IsExpiredAt(beaconHeight) {
+			return NewMempoolTxError(RejectExpiredTimeLockTx, fmt.Errorf("transaction %+v expired at beacon height %+v", txHash.String(), timeLock.NotAfter))
+		}
+		if !timeLock.IsOpenAt(beaconHeight) && tp.config.BlockChain.IsForkActive(forks.TimeLock, beaconHeight) {
+			validationView = heldTxView{ShardBestState: shardView, beaconHeight: timeLock.NotBefore}
+			notYetValidErr = NewMempoolTxError(RejectTimeLockNotYetValid, fmt.Errorf("transaction %+v is not valid before beacon height %+v", txHash.String(), timeLock.NotBefore))
+		}
+	}
 	// Condition 7: validate tx with data of blockchain
-	err = tx.ValidateTxWithBlockChain(tp.config.BlockChain, shardView, beaconView, shardID, shardView.GetCopiedTransactionStateDB())
+	err = tx.ValidateTxWithBlockChain(tp.config.BlockChain, validationView, beaconView, shardID, shardView.GetCopiedTransactionStateDB())
 	if err != nil {
 		// parse error
 		e1, ok := err.(*transaction.TransactionError)
@@ -573,6 +642,9 @@ func (tp *TxPool) validateTransaction(shardView *blockchain.ShardBestState, beac
 	if foundRequestStopAutoStaking > 0 {
 		return NewMempoolTxError(RejectDuplicateRequestStopAutoStaking, fmt.Errorf("This public key already request to stop auto staking and still in pool %+v", requestedPublicKey))
 	}
+	if notYetValidErr != nil {
+		return notYetValidErr
+	}
 	return nil
 }
 
@@ -630,6 +702,7 @@ func (tp *TxPool) validateTransactionReplacement(tx metadata.Transaction) (error
 			if isReplaced {
 				txToBeReplaced := txDescToBeReplaced.Desc.Tx
 				tp.removeTx(txToBeReplaced)
+				txtracker.Evicted(txToBeReplaced, "replaced by "+tx.Hash().String())
 				tp.TriggerCRemoveTxs(txToBeReplaced)
 				//tp.removeRequestStopStakingByTxHash(*txToBeReplaced.Hash())
 				// send tx into channel of CRmoveTxs
@@ -675,6 +748,8 @@ func (tp *TxPool) addTx(txD *TxDesc, isStore bool) error {
 		}
 	}
 	tp.pool[*txHash] = txD
+	poolSizeGauge.Update(int64(len(tp.pool)))
+	poolAddedCounter.Inc(1)
 	var serialNumberList []common.Hash
 	serialNumberList = append(serialNumberList, txD.Desc.Tx.ListSerialNumbersHashH()...)
 	serialNumberListHash := common.HashArrayOfHashArray(serialNumberList)
@@ -785,6 +860,7 @@ func (tp *TxPool) removeTx(tx metadata.Transaction) {
 	//Logger.log.Infof((*tx).Hash().String())
 	if _, exists := tp.pool[*tx.Hash()]; exists {
 		delete(tp.pool, *tx.Hash())
+		poolRemoveCounter.Inc(1)
 		atomic.StoreInt64(&tp.lastUpdated, time.Now().Unix())
 	}
 	if _, exists := tp.poolSerialNumbersHashList[*tx.Hash()]; exists {
@@ -798,12 +874,15 @@ func (tp *TxPool) removeTx(tx metadata.Transaction) {
 		// this new transaction maybe not exist
 		if _, exists := tp.pool[hash]; exists {
 			delete(tp.pool, hash)
+			poolRemoveCounter.Inc(1)
 			atomic.StoreInt64(&tp.lastUpdated, time.Now().Unix())
 		}
 		if _, exists := tp.poolSerialNumbersHashList[hash]; exists {
 			delete(tp.poolSerialNumbersHashList, hash)
 		}
 	}
+	tp.removeHeldTx(*tx.Hash())
+	poolSizeGauge.Update(int64(len(tp.pool)))
 	tp.removeRequestStopStakingByTxHash(*tx.Hash())
 }
 
@@ -1037,6 +1116,9 @@ func (tp *TxPool) ValidateSerialNumberHashH(serialNumber []byte) error {
 			}
 		}
 	}
+	if txHash, ok := tp.heldSerialNumbers[hash]; ok {
+		return NewMempoolTxError(DuplicateSerialNumbersHashError, fmt.Errorf("Held time-locked transaction %+v use duplicate current serial number in pool", txHash.String()))
+	}
 	return nil
 }
 
@@ -1060,6 +1142,7 @@ func (tp *TxPool) EmptyPool() bool {
 	tp.poolSerialNumberHash = make(map[common.Hash]common.Hash)
 	tp.poolCandidate = make(map[common.Hash]string)
 	tp.poolRequestStopStaking = make(map[common.Hash]string)
+	poolSizeGauge.Update(0)
 	if len(tp.pool) == 0 && len(tp.poolSerialNumbersHashList) == 0 && len(tp.poolSerialNumberHash) == 0 && len(tp.poolCandidate) == 0 && len(tp.poolRequestStopStaking) == 0 {
 		return true
 	}
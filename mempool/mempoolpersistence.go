@@ -111,6 +111,16 @@ func (tp *TxPool) loadDatabaseMP() ([]TxDesc, error) {
 			}
 			continue
 		}
+		senderShardID := common.GetShardIDFromLastByte(txDesc.Desc.Tx.GetSenderAddrLastByte())
+		beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+		shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
+		//if time-locked transaction is not valid yet then hold it again
+		if isHeldAt(shardView, txDesc.Desc.Tx) {
+			if tp.reloadHeldTx(shardView, beaconView, txDesc, time.Now()) {
+				txDescs = append(txDescs, *txDesc)
+			}
+			continue
+		}
 		//if transaction is timeout then remove
 		if time.Since(txDesc.StartTime) > ttl {
 			err1 := tp.removeTransactionFromDatabaseMP(txDesc.Desc.Tx.Hash())
@@ -119,9 +129,6 @@ func (tp *TxPool) loadDatabaseMP() ([]TxDesc, error) {
 			}
 		}
 		//if not validated by current blockchain db then remove
-		senderShardID := common.GetShardIDFromLastByte(txDesc.Desc.Tx.GetSenderAddrLastByte())
-		beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
-		shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
 		err = tp.validateTransaction(shardView, beaconView, txDesc.Desc.Tx, -1, false, false)
 		if err != nil {
 			Logger.log.Error(err)
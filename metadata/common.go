@@ -135,6 +135,8 @@ func ParseMetadata(meta interface{}) (Metadata, error) {
 		md = &PortalTopUpWaitingPortingRequest{}
 	case PortalTopUpWaitingPortingResponseMeta:
 		md = &PortalTopUpWaitingPortingResponse{}
+	case TimeLockMeta:
+		md = &TimeLock{}
 	default:
 		Logger.log.Debug("[db] parse meta err: %+v\n", meta)
 		return nil, errors.Errorf("Could not parse metadata with type: %d", int(mtTemp["Type"].(float64)))
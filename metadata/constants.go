@@ -90,6 +90,9 @@ const (
 	// incognito mode for smart contract
 	BurningForDepositToSCRequestMeta = 96
 	BurningConfirmForDepositToSCMeta = 97
+
+	// time-locked txs
+	TimeLockMeta = 210
 )
 
 var minerCreatedMetaTypes = []int{
@@ -47,6 +47,11 @@ const (
 	PortalRequestPTokenParamError
 	PortalRedeemRequestParamError
 	PortalRedeemLiquidateExchangeRatesParamError
+
+	// time lock
+	TimeLockNotActiveError
+	TimeLockNotYetValidError
+	TimeLockExpiredError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -97,6 +102,11 @@ var ErrCodeMessage = map[int]struct {
 	PortalRequestPTokenParamError:                {-7001, "Portal request ptoken param error"},
 	PortalRedeemRequestParamError:                {-7002, "Portal redeem request param error"},
 	PortalRedeemLiquidateExchangeRatesParamError: {-7003, "Portal redeem liquidate exchange rates param error"},
+
+	// -8xxx time lock
+	TimeLockNotActiveError:   {-8001, "Time-locked txs are not active"},
+	TimeLockNotYetValidError: {-8002, "Time-locked tx is not valid yet"},
+	TimeLockExpiredError:     {-8003, "Time-locked tx expired"},
 }
 
 type MetadataTxError struct {
@@ -42,7 +42,7 @@ func NewIssuingETHResponse(
 	}
 }
 
-func (iRes IssuingETHResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB) bool {
+func (iRes IssuingETHResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	// no need to have fee for this tx
 	return true
 }
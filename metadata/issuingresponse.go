@@ -30,7 +30,7 @@ func NewIssuingResponse(requestedTxID common.Hash, metaType int) *IssuingRespons
 	}
 }
 
-func (iRes IssuingResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB) bool {
+func (iRes IssuingResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	// no need to have fee for this tx
 	return true
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/incognitochain/incognito-chain/privacy"
 	"github.com/incognitochain/incognito-chain/relaying/bnb"
 
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"github.com/incognitochain/incognito-chain/incognitokey"
@@ -19,7 +20,7 @@ import (
 type Metadata interface {
 	GetType() int
 	Hash() *common.Hash
-	CheckTransactionFee(Transaction, uint64, int64, *statedb.StateDB) bool
+	CheckTransactionFee(Transaction, uint64, int64, *statedb.StateDB, ChainRetriever) bool
 	ValidateTxWithBlockChain(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, transactionStateDB *statedb.StateDB) (bool, error)
 	ValidateSanityData(chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, beaconHeight uint64, tx Transaction) (bool, bool, error)
 	ValidateMetadataByItself() bool
@@ -57,7 +58,7 @@ type MempoolRetriever interface {
 type ChainRetriever interface {
 	GetStakingAmountShard() uint64
 	GetCentralizedWebsitePaymentAddress(uint64) string
-	GetBeaconHeightBreakPointBurnAddr() uint64
+	IsForkActive(fork forks.Fork, beaconHeight uint64) bool
 	GetBurningAddress(blockHeight uint64) string
 	GetTransactionByHash(common.Hash) (byte, common.Hash, uint64, int, Transaction, error)
 	ListPrivacyTokenAndBridgeTokenAndPRVByShardID(byte) ([]common.Hash, error)
@@ -159,25 +160,26 @@ func getPDEPoolPair(
 	return &pdePoolForPair, nil
 }
 
-func isPairValid(poolPair *rawdbv2.PDEPoolForPair, beaconHeight int64) bool {
+func isPairValid(chainRetriever ChainRetriever, poolPair *rawdbv2.PDEPoolForPair, beaconHeight int64) bool {
 	if poolPair == nil {
 		return false
 	}
 	prvIDStr := common.PRVCoinID.String()
 	if poolPair.Token1IDStr == prvIDStr &&
 		poolPair.Token1PoolValue < uint64(common.MinTxFeesOnTokenRequirement) &&
-		beaconHeight >= common.BeaconBlockHeighMilestoneForMinTxFeesOnTokenRequirement {
+		beaconHeight >= 0 && chainRetriever.IsForkActive(forks.MinTxFeesOnTokenRequirement, uint64(beaconHeight)) {
 		return false
 	}
 	if poolPair.Token2IDStr == prvIDStr &&
 		poolPair.Token2PoolValue < uint64(common.MinTxFeesOnTokenRequirement) &&
-		beaconHeight >= common.BeaconBlockHeighMilestoneForMinTxFeesOnTokenRequirement {
+		beaconHeight >= 0 && chainRetriever.IsForkActive(forks.MinTxFeesOnTokenRequirement, uint64(beaconHeight)) {
 		return false
 	}
 	return true
 }
 
 func convertValueBetweenCurrencies(
+	chainRetriever ChainRetriever,
 	amount uint64,
 	currentCurrencyIDStr string,
 	tokenID *common.Hash,
@@ -190,7 +192,7 @@ func convertValueBetweenCurrencies(
 	if err != nil {
 		return 0, NewMetadataTxError(CouldNotGetExchangeRateError, err)
 	}
-	if !isPairValid(pdePoolForPair, beaconHeight) {
+	if !isPairValid(chainRetriever, pdePoolForPair, beaconHeight) {
 		return 0, NewMetadataTxError(CouldNotGetExchangeRateError, errors.New("PRV pool size on pdex is smaller minimum initial adding liquidity amount"))
 	}
 	invariant := float64(0)
@@ -215,12 +217,14 @@ func convertValueBetweenCurrencies(
 // return error if there is no exchange rate between native token and privacy token
 // beaconHeight = -1: get the latest beacon height
 func ConvertNativeTokenToPrivacyToken(
+	chainRetriever ChainRetriever,
 	nativeTokenAmount uint64,
 	tokenID *common.Hash,
 	beaconHeight int64,
 	stateDB *statedb.StateDB,
 ) (float64, error) {
 	return convertValueBetweenCurrencies(
+		chainRetriever,
 		nativeTokenAmount,
 		common.PRVCoinID.String(),
 		tokenID,
@@ -232,12 +236,14 @@ func ConvertNativeTokenToPrivacyToken(
 // return error if there is no exchange rate between native token and privacy token
 // beaconHeight = -1: get the latest beacon height
 func ConvertPrivacyTokenToNativeToken(
+	chainRetriever ChainRetriever,
 	privacyTokenAmount uint64,
 	tokenID *common.Hash,
 	beaconHeight int64,
 	stateDB *statedb.StateDB,
 ) (float64, error) {
 	return convertValueBetweenCurrencies(
+		chainRetriever,
 		privacyTokenAmount,
 		tokenID.String(),
 		tokenID,
@@ -50,13 +50,13 @@ func (mb MetadataBase) Hash() *common.Hash {
 	return &hash
 }
 
-func (mb MetadataBase) CheckTransactionFee(tx Transaction, minFeePerKbTx uint64, beaconHeight int64, stateDB *statedb.StateDB) bool {
+func (mb MetadataBase) CheckTransactionFee(tx Transaction, minFeePerKbTx uint64, beaconHeight int64, stateDB *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	if tx.GetType() == common.TxCustomTokenPrivacyType {
 		feeNativeToken := tx.GetTxFee()
 		feePToken := tx.GetTxFeeToken()
 		if feePToken > 0 {
 			tokenID := tx.GetTokenID()
-			feePTokenToNativeTokenTmp, err := ConvertPrivacyTokenToNativeToken(feePToken, tx.GetTokenID(), beaconHeight, stateDB)
+			feePTokenToNativeTokenTmp, err := ConvertPrivacyTokenToNativeToken(chainRetriever, feePToken, tx.GetTokenID(), beaconHeight, stateDB)
 			if err != nil {
 				fmt.Printf("transaction %+v: %+v %v can not convert to native token",
 					tx.Hash().String(), feePToken, tokenID)
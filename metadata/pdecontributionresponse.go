@@ -35,7 +35,7 @@ func NewPDEContributionResponse(
 	}
 }
 
-func (iRes PDEContributionResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB) bool {
+func (iRes PDEContributionResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	// no need to have fee for this tx
 	return true
 }
@@ -32,7 +32,7 @@ func NewPDECrossPoolTradeResponse(
 	}
 }
 
-func (iRes PDECrossPoolTradeResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB) bool {
+func (iRes PDECrossPoolTradeResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	// no need to have fee for this tx
 	return true
 }
@@ -36,7 +36,7 @@ func NewPortalCustodianWithdrawResponse(
 	}
 }
 
-func (responseMeta PortalCustodianWithdrawResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB) bool {
+func (responseMeta PortalCustodianWithdrawResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	// no need to have fee for this tx
 	return true
 }
@@ -32,7 +32,7 @@ func NewPortalFeeRefundResponse(
 	}
 }
 
-func (iRes PortalFeeRefundResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB) bool {
+func (iRes PortalFeeRefundResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	// no need to have fee for this tx
 	return true
 }
@@ -33,7 +33,7 @@ func NewPortalTopUpWaitingPortingResponse(
 	}
 }
 
-func (iRes PortalTopUpWaitingPortingResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB) bool {
+func (iRes PortalTopUpWaitingPortingResponse) CheckTransactionFee(tr Transaction, minFee uint64, beaconHeight int64, db *statedb.StateDB, chainRetriever ChainRetriever) bool {
 	// no need to have fee for this tx
 	return true
 }
@@ -0,0 +1,111 @@
+package metadata
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/pkg/errors"
+)
+
+// TimeLock makes a tx valid only within a window of beacon heights, a tx can
+// be signed in advance and is held by the mempool until NotBefore.
+// The window is checked against the beacon height of the shard view the tx is
+// validated on: the one of the best block when the tx gets in a block.
+type TimeLock struct {
+	MetadataBase
+	// NotBefore is the first beacon height where the tx is valid
+	NotBefore uint64
+	// NotAfter is the last beacon height where the tx is valid, 0 if the tx
+	// does not expire
+	NotAfter uint64
+}
+
+func NewTimeLock(notBefore, notAfter uint64) (*TimeLock, error) {
+	timeLock := &TimeLock{
+		MetadataBase: *NewMetadataBase(TimeLockMeta),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	if err := timeLock.validateWindow(); err != nil {
+		return nil, err
+	}
+	return timeLock, nil
+}
+
+// NewTimeLockFromRPC reads the window from the NotBefore and NotAfter fields
+// of data, both optional
+func NewTimeLockFromRPC(data map[string]interface{}) (*TimeLock, error) {
+	var window [2]uint64
+	for i, field := range []string{"NotBefore", "NotAfter"} {
+		value, ok := data[field]
+		if !ok {
+			continue
+		}
+		height, ok := value.(float64)
+		if !ok || height < 0 {
+			return nil, errors.Errorf("invalid %v %v", field, value)
+		}
+		window[i] = uint64(height)
+	}
+	return NewTimeLock(window[0], window[1])
+}
+
+func (timeLock TimeLock) validateWindow() error {
+	if timeLock.NotBefore == 0 && timeLock.NotAfter == 0 {
+		return errors.New("time lock needs NotBefore or NotAfter")
+	}
+	if timeLock.NotAfter > 0 && timeLock.NotAfter < timeLock.NotBefore {
+		return errors.Errorf("time lock window %v-%v is empty", timeLock.NotBefore, timeLock.NotAfter)
+	}
+	return nil
+}
+
+// IsOpenAt returns whether beaconHeight is in the window
+func (timeLock TimeLock) IsOpenAt(beaconHeight uint64) bool {
+	return beaconHeight >= timeLock.NotBefore && !timeLock.IsExpiredAt(beaconHeight)
+}
+
+// IsExpiredAt returns whether the window is over at beaconHeight
+func (timeLock TimeLock) IsExpiredAt(beaconHeight uint64) bool {
+	return timeLock.NotAfter > 0 && beaconHeight > timeLock.NotAfter
+}
+
+func (timeLock TimeLock) Hash() *common.Hash {
+	record := strconv.Itoa(timeLock.Type)
+	record += strconv.FormatUint(timeLock.NotBefore, 10)
+	record += strconv.FormatUint(timeLock.NotAfter, 10)
+	hash := common.HashH([]byte(record))
+	return &hash
+}
+
+func (timeLock TimeLock) ValidateTxWithBlockChain(tx Transaction, chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, shardID byte, transactionStateDB *statedb.StateDB) (bool, error) {
+	beaconHeight := shardViewRetriever.GetBeaconHeight()
+	if !chainRetriever.IsForkActive(forks.TimeLock, beaconHeight) {
+		return false, NewMetadataTxError(TimeLockNotActiveError, fmt.Errorf("time-locked txs are not accepted at beacon height %v", beaconHeight))
+	}
+	if timeLock.IsExpiredAt(beaconHeight) {
+		return false, NewMetadataTxError(TimeLockExpiredError, fmt.Errorf("tx %v expired at beacon height %v", tx.Hash().String(), timeLock.NotAfter))
+	}
+	if !timeLock.IsOpenAt(beaconHeight) {
+		return false, NewMetadataTxError(TimeLockNotYetValidError, fmt.Errorf("tx %v is not valid before beacon height %v", tx.Hash().String(), timeLock.NotBefore))
+	}
+	return true, nil
+}
+
+func (timeLock TimeLock) ValidateSanityData(chainRetriever ChainRetriever, shardViewRetriever ShardViewRetriever, beaconViewRetriever BeaconViewRetriever, beaconHeight uint64, tx Transaction) (bool, bool, error) {
+	if err := timeLock.validateWindow(); err != nil {
+		return false, false, err
+	}
+	return true, true, nil
+}
+
+func (timeLock TimeLock) ValidateMetadataByItself() bool {
+	return timeLock.Type == TimeLockMeta
+}
+
+func (timeLock *TimeLock) CalculateSize() uint64 {
+	return calculateSize(timeLock)
+}
@@ -0,0 +1,120 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+)
+
+// RegistryQuantiles are pushed for the histograms and timers of a registry
+var RegistryQuantiles = []float64{0.5, 0.95, 0.99}
+
+// PushRegistry pushes the metrics of r to the InfluxDB at url every interval
+// until quit is closed, next to the measurements sent by the metric tool. The
+// labels of the metrics are pushed as tags, with the external address of the
+// node.
+func PushRegistry(r metrics.Registry, url, externalAddress string, interval time.Duration, quit <-chan struct{}) {
+	if url == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	client := &http.Client{Timeout: 30 * time.Second}
+	for {
+		select {
+		case <-quit:
+			return
+		case now := <-ticker.C:
+			data := RegistryLines(r, externalAddress, now)
+			if len(data) == 0 {
+				continue
+			}
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(data))
+			if err != nil {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(req.Context(), 30*time.Second)
+			resp, err := client.Do(req.WithContext(ctx))
+			if err == nil {
+				resp.Body.Close()
+			}
+			cancel()
+		}
+	}
+}
+
+// RegistryLines returns the metrics of r in the line protocol at time now,
+// one line per metric with its values as fields
+func RegistryLines(r metrics.Registry, externalAddress string, now time.Time) []byte {
+	lines := []string{}
+	r.Each(func(registryName string, i interface{}) {
+		name, labels := metrics.SplitLabels(registryName)
+		tags := []string{}
+		if externalAddress != "" {
+			tags = append(tags, ExternalAddressTag+"="+escapeTag(externalAddress))
+		}
+		for _, l := range labels {
+			tags = append(tags, escapeTag(l.Key)+"="+escapeTag(l.Value))
+		}
+		sort.Strings(tags)
+		fields := registryFields(i)
+		if len(fields) == 0 {
+			return
+		}
+		measurement := measurementEscaper.Replace(strings.Replace(name, "/", ".", -1))
+		if len(tags) > 0 {
+			measurement += "," + strings.Join(tags, ",")
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %d", measurement, strings.Join(fields, ","), now.UnixNano()))
+	})
+	sort.Strings(lines)
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func registryFields(i interface{}) []string {
+	switch m := i.(type) {
+	case metrics.Counter:
+		return []string{fmt.Sprintf("count=%di", m.Count())}
+	case metrics.Gauge:
+		return []string{fmt.Sprintf("value=%di", m.Value())}
+	case metrics.GaugeFloat64:
+		return []string{fmt.Sprintf("value=%f", m.Value())}
+	case metrics.Meter:
+		s := m.Snapshot()
+		return []string{fmt.Sprintf("count=%di", s.Count()), fmt.Sprintf("m1=%f", s.Rate1()), fmt.Sprintf("mean=%f", s.RateMean())}
+	case metrics.Histogram:
+		s := m.Snapshot()
+		return quantileFields(s.Count(), s.Max(), s.Mean(), s.Percentiles(RegistryQuantiles))
+	case metrics.Timer:
+		s := m.Snapshot()
+		return quantileFields(s.Count(), s.Max(), s.Mean(), s.Percentiles(RegistryQuantiles))
+	}
+	return nil
+}
+
+func quantileFields(count, max int64, mean float64, quantiles []float64) []string {
+	fields := []string{fmt.Sprintf("count=%di", count), fmt.Sprintf("max=%di", max), fmt.Sprintf("mean=%f", mean)}
+	for i, q := range RegistryQuantiles {
+		fields = append(fields, fmt.Sprintf("p%s=%f", strconv.FormatFloat(q*100, 'f', -1, 64), quantiles[i]))
+	}
+	return fields
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+func escapeTag(s string) string {
+	return tagEscaper.Replace(s)
+}
@@ -0,0 +1,57 @@
+package grafana
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryLines(t *testing.T) {
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredCounter("mempool/added", r).Inc(3)
+	metrics.NewRegisteredGauge(metrics.LabeledName("syncker/lag", "chain", "shard 0"), r).Update(7)
+	metrics.NewRegisteredTimer(metrics.LabeledName("rpc/latency", "method", "getbeaconheaders"), r).Update(time.Millisecond)
+
+	now := time.Unix(100, 0)
+	lines := strings.Split(strings.TrimSpace(string(RegistryLines(r, "1.2.3.4:9334", now))), "\n")
+	if !assert.Len(t, lines, 3) {
+		return
+	}
+	assert.Equal(t, "mempool.added,externaladdresstag=1.2.3.4:9334 count=3i 100000000000", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "rpc.latency,externaladdresstag=1.2.3.4:9334,method=getbeaconheaders count=1i,max=1000000i,"), lines[1])
+	assert.Equal(t, `syncker.lag,chain=shard\ 0,externaladdresstag=1.2.3.4:9334 value=7i 100000000000`, lines[2])
+
+	assert.Empty(t, RegistryLines(metrics.NewRegistry(), "", now))
+}
+
+func TestPushRegistry(t *testing.T) {
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("peerv2/peers", r).Update(5)
+	pushed := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, _ := ioutil.ReadAll(req.Body)
+		pushed <- string(data)
+	}))
+	defer server.Close()
+
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		PushRegistry(r, server.URL, "", 10*time.Millisecond, quit)
+		close(done)
+	}()
+	select {
+	case data := <-pushed:
+		assert.True(t, strings.HasPrefix(data, "peerv2.peers value=5i "), data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing pushed")
+	}
+	close(quit)
+	<-done
+}
@@ -0,0 +1,139 @@
+// Package health serves the healthchecks of a metrics registry to the probes
+// of an orchestrator: /healthz reports whether the node works, /readyz
+// whether it is in sync and can serve requests.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+)
+
+// The healthchecks of a registry are grouped by the prefix of their name
+const (
+	// LivePrefix is the prefix of the checks of /healthz, the node is
+	// restarted when they fail
+	LivePrefix = "health/"
+	// ReadyPrefix is the prefix of the checks of /readyz with the live
+	// checks, the node gets no traffic while they fail
+	ReadyPrefix = "ready/"
+)
+
+// Status values of a report and of its checks
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Report is the result of the healthchecks of a probe, the checks map their
+// name without prefix to ok or their error
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// OK returns whether all the checks of the report passed
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Register registers a healthcheck running f in place of the check of the
+// same name, name starts with LivePrefix or ReadyPrefix
+func Register(r metrics.Registry, name string, f func() error) {
+	if r == nil {
+		r = metrics.DefaultRegistry
+	}
+	r.Unregister(name)
+	r.Register(name, metrics.NewHealthcheck(func(h metrics.Healthcheck) {
+		if err := f(); err != nil {
+			h.Unhealthy(err)
+		} else {
+			h.Healthy()
+		}
+	}))
+}
+
+// runMtx serializes the runs of the healthchecks, which keep their result
+var runMtx sync.Mutex
+
+// Run runs the healthchecks of r whose name starts with one of prefixes
+func Run(r metrics.Registry, prefixes ...string) Report {
+	if r == nil {
+		r = metrics.DefaultRegistry
+	}
+	runMtx.Lock()
+	defer runMtx.Unlock()
+	report := Report{Status: StatusOK, Checks: map[string]string{}}
+	names := []string{}
+	checks := map[string]metrics.Healthcheck{}
+	r.Each(func(name string, i interface{}) {
+		if h, ok := i.(metrics.Healthcheck); ok && hasPrefix(name, prefixes) {
+			names = append(names, name)
+			checks[name] = h
+		}
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		h := checks[name]
+		h.Check()
+		result := StatusOK
+		if err := h.Error(); err != nil {
+			result = err.Error()
+			report.Status = StatusFail
+		}
+		report.Checks[trimPrefix(name, prefixes)] = result
+	}
+	return report
+}
+
+// Live runs the checks of /healthz
+func Live(r metrics.Registry) Report {
+	return Run(r, LivePrefix)
+}
+
+// Ready runs the checks of /readyz
+func Ready(r metrics.Registry) Report {
+	return Run(r, LivePrefix, ReadyPrefix)
+}
+
+// Handler serves the report of a probe, with the status 503 when a check
+// fails
+func Handler(r metrics.Registry, probe func(metrics.Registry) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := probe(r)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		if !report.OK() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// Handle adds /healthz and /readyz to mux
+func Handle(mux *http.ServeMux, r metrics.Registry) {
+	mux.Handle("/healthz", Handler(r, Live))
+	mux.Handle("/readyz", Handler(r, Ready))
+}
+
+func hasPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func trimPrefix(name string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+	return name
+}
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	r := metrics.NewRegistry()
+	var syncErr error
+	Register(r, LivePrefix+"db/beacon", func() error { return nil })
+	Register(r, ReadyPrefix+"sync/beacon", func() error { return syncErr })
+	metrics.NewRegisteredGauge("syncker/lag", r)
+
+	assert.Equal(t, Report{Status: StatusOK, Checks: map[string]string{"db/beacon": StatusOK}}, Live(r))
+	assert.Equal(t, Report{Status: StatusOK, Checks: map[string]string{"db/beacon": StatusOK, "sync/beacon": StatusOK}}, Ready(r))
+
+	syncErr = errors.New("20 blocks behind peers")
+	assert.True(t, Live(r).OK())
+	report := Ready(r)
+	assert.False(t, report.OK())
+	assert.Equal(t, "20 blocks behind peers", report.Checks["sync/beacon"])
+
+	// a check registered again replaces the previous one
+	Register(r, ReadyPrefix+"sync/beacon", func() error { return nil })
+	assert.True(t, Ready(r).OK())
+}
+
+func TestHandle(t *testing.T) {
+	r := metrics.NewRegistry()
+	Register(r, LivePrefix+"db/beacon", func() error { return nil })
+	Register(r, ReadyPrefix+"sync/shard-0", func() error { return errors.New("no peer") })
+	mux := http.NewServeMux()
+	Handle(mux, r)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	report := Report{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, Report{Status: StatusOK, Checks: map[string]string{"db/beacon": StatusOK}}, report)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	report = Report{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, "no peer", report.Checks["sync/shard-0"])
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"strings"
+)
+
+// Label is a key and value telling apart the metrics of one name, like the
+// chain of a block insertion timer
+type Label struct {
+	Key   string
+	Value string
+}
+
+// LabeledName returns the registry name of the metric name with labels, given
+// as keys followed by their values: LabeledName("chain/insert", "chain",
+// "beacon") is `chain/insert{chain="beacon"}`. Exporters supporting labels
+// split them from the name with SplitLabels, the others keep the whole name.
+func LabeledName(name string, keyValues ...string) string {
+	if len(keyValues) < 2 {
+		return name
+	}
+	b := strings.Builder{}
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(keyValues[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(keyValues[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	labelUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n")
+)
+
+// SplitLabels returns the name and the labels of a registry name built by
+// LabeledName, a name without labels is returned as is
+func SplitLabels(labeledName string) (string, []Label) {
+	start := strings.IndexByte(labeledName, '{')
+	if start < 0 || !strings.HasSuffix(labeledName, "}") {
+		return labeledName, nil
+	}
+	name, rest := labeledName[:start], labeledName[start+1:len(labeledName)-1]
+	labels := []Label{}
+	for len(rest) > 0 {
+		eq := strings.Index(rest, `="`)
+		if eq < 0 {
+			return labeledName, nil
+		}
+		key := rest[:eq]
+		rest = rest[eq+2:]
+		// the value ends at the first quote not escaped
+		end := -1
+		for i := 0; i < len(rest); i++ {
+			if rest[i] == '\\' {
+				i++
+			} else if rest[i] == '"' {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return labeledName, nil
+		}
+		labels = append(labels, Label{Key: key, Value: labelUnescaper.Replace(rest[:end])})
+		rest = strings.TrimPrefix(rest[end+1:], ",")
+	}
+	return name, labels
+}
@@ -0,0 +1,150 @@
+// Package prometheus exports the metrics of a registry in the Prometheus text
+// format, for Prometheus to scrape them from the /metrics endpoint of a node.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+)
+
+// Namespace prefixes the names of the exported metrics
+const Namespace = "incognito"
+
+// ContentType is the content type of the text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Quantiles are exported for the histograms and timers
+var Quantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+
+// Handler serves the metrics of r in the text format
+func Handler(r metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := Write(w, r); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+type sample struct {
+	suffix string
+	series string // labels of the metric, samples of a summary share them
+	labels []metrics.Label
+	value  float64
+}
+
+// family holds the samples of the metrics of one name, which only differ by
+// their labels
+type family struct {
+	name    string
+	kind    string
+	samples []sample
+}
+
+// Write writes the metrics of r in the text format to w. The registry names
+// are turned into metric names by replacing the characters not allowed in
+// them with underscores, after splitting their labels; counters and meters
+// are counters, gauges are gauges, histograms and timers are summaries, in
+// seconds for the timers.
+func Write(w io.Writer, r metrics.Registry) error {
+	families := map[string]*family{}
+	r.Each(func(registryName string, i interface{}) {
+		name, labels := metrics.SplitLabels(registryName)
+		name = Namespace + "_" + sanitize(name)
+		switch m := i.(type) {
+		case metrics.Counter:
+			addSample(families, name+"_total", "counter", labels, sample{value: float64(m.Count())})
+		case metrics.Meter:
+			addSample(families, name+"_total", "counter", labels, sample{value: float64(m.Count())})
+		case metrics.Gauge:
+			addSample(families, name, "gauge", labels, sample{value: float64(m.Value())})
+		case metrics.GaugeFloat64:
+			addSample(families, name, "gauge", labels, sample{value: m.Value()})
+		case metrics.Histogram:
+			h := m.Snapshot()
+			addSummary(families, name, labels, h.Percentiles(Quantiles), float64(h.Sum()), h.Count(), 1)
+		case metrics.Timer:
+			t := m.Snapshot()
+			addSummary(families, name+"_seconds", labels, t.Percentiles(Quantiles), float64(t.Sum()), t.Count(), float64(time.Second))
+		}
+	})
+
+	names := []string{}
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := families[name]
+		sort.SliceStable(f.samples, func(i, j int) bool {
+			return f.samples[i].series < f.samples[j].series
+		})
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+		for _, s := range f.samples {
+			fmt.Fprintf(bw, "%s%s%s %s\n", f.name, s.suffix, labelString(s.labels), formatValue(s.value))
+		}
+	}
+	return bw.Flush()
+}
+
+func addSample(families map[string]*family, name, kind string, labels []metrics.Label, s sample) {
+	f, ok := families[name]
+	if !ok {
+		f = &family{name: name, kind: kind}
+		families[name] = f
+	}
+	// a name registered with metrics of different kinds keeps the first one
+	if f.kind != kind {
+		return
+	}
+	s.series = labelString(labels)
+	s.labels = append(append([]metrics.Label{}, labels...), s.labels...)
+	f.samples = append(f.samples, s)
+}
+
+// addSummary adds the quantiles, sum and count of a summary, its values
+// divided by unit
+func addSummary(families map[string]*family, name string, labels []metrics.Label, quantiles []float64, sum float64, count int64, unit float64) {
+	for i, q := range Quantiles {
+		quantile := metrics.Label{Key: "quantile", Value: strconv.FormatFloat(q, 'g', -1, 64)}
+		addSample(families, name, "summary", labels, sample{labels: []metrics.Label{quantile}, value: quantiles[i] / unit})
+	}
+	addSample(families, name, "summary", labels, sample{suffix: "_sum", value: sum / unit})
+	addSample(families, name, "summary", labels, sample{suffix: "_count", value: float64(count)})
+}
+
+// sanitize replaces the characters not allowed in metric and label names
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelString(labels []metrics.Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := []string{}
+	for _, l := range labels {
+		parts = append(parts, sanitize(l.Key)+`="`+labelEscaper.Replace(l.Value)+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,67 @@
+package prometheus
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLabels(t *testing.T) {
+	name := metrics.LabeledName("rpc/latency", "method", `get"x\y`, "chain", "shard-1")
+	base, labels := metrics.SplitLabels(name)
+	assert.Equal(t, "rpc/latency", base)
+	assert.Equal(t, []metrics.Label{{Key: "method", Value: `get"x\y`}, {Key: "chain", Value: "shard-1"}}, labels)
+
+	base, labels = metrics.SplitLabels("syncker/download/blocks")
+	assert.Equal(t, "syncker/download/blocks", base)
+	assert.Empty(t, labels)
+
+	// a name which is not built by LabeledName is kept whole
+	base, labels = metrics.SplitLabels("a{b}")
+	assert.Equal(t, "a{b}", base)
+	assert.Empty(t, labels)
+}
+
+func TestWrite(t *testing.T) {
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredCounter("mempool/added", r).Inc(3)
+	metrics.NewRegisteredGauge(metrics.LabeledName("syncker/lag", "chain", "shard-0"), r).Update(7)
+	metrics.NewRegisteredGauge(metrics.LabeledName("syncker/lag", "chain", "beacon"), r).Update(2)
+	metrics.NewRegisteredGaugeFloat64("incdb/compaction/progress", r).Update(0.5)
+	timer := metrics.NewRegisteredTimer(metrics.LabeledName("chain/insert", "chain", "beacon"), r)
+	timer.Update(time.Second)
+	timer.Update(3 * time.Second)
+	metrics.NewRegisteredHistogram("peerv2/size", r, metrics.NewUniformSample(10)).Update(4)
+
+	w := &bytes.Buffer{}
+	assert.NoError(t, Write(w, r))
+	out := w.String()
+
+	assert.Contains(t, out, "# TYPE incognito_mempool_added_total counter\nincognito_mempool_added_total 3\n")
+	// the series of a name are under one type line, ordered by labels
+	assert.Contains(t, out, "# TYPE incognito_syncker_lag gauge\n"+
+		"incognito_syncker_lag{chain=\"beacon\"} 2\n"+
+		"incognito_syncker_lag{chain=\"shard-0\"} 7\n")
+	assert.Contains(t, out, "incognito_incdb_compaction_progress 0.5\n")
+	// timers are summaries in seconds
+	assert.Contains(t, out, "# TYPE incognito_chain_insert_seconds summary\n")
+	assert.Contains(t, out, "incognito_chain_insert_seconds{chain=\"beacon\",quantile=\"0.5\"} 2\n")
+	assert.Contains(t, out, "incognito_chain_insert_seconds_sum{chain=\"beacon\"} 4\n")
+	assert.Contains(t, out, "incognito_chain_insert_seconds_count{chain=\"beacon\"} 2\n")
+	assert.Contains(t, out, "incognito_peerv2_size{quantile=\"0.99\"} 4\n")
+	assert.Equal(t, 1, strings.Count(out, "# TYPE incognito_syncker_lag "))
+}
+
+func TestHandler(t *testing.T) {
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("peerv2/peers", r).Update(5)
+	rec := httptest.NewRecorder()
+	Handler(r).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "# TYPE incognito_peerv2_peers gauge\nincognito_peerv2_peers 5\n", rec.Body.String())
+}
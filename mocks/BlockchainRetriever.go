@@ -3,6 +3,7 @@
 package mocks
 
 import common "github.com/incognitochain/incognito-chain/common"
+import forks "github.com/incognitochain/incognito-chain/blockchain/forks"
 import incognitokey "github.com/incognitochain/incognito-chain/incognitokey"
 import metadata "github.com/incognitochain/incognito-chain/metadata"
 import mock "github.com/stretchr/testify/mock"
@@ -168,20 +169,6 @@ func (_m *BlockchainRetriever) GetBeaconHeight() uint64 {
 	return r0
 }
 
-// GetBeaconHeightBreakPointBurnAddr provides a mock function with given fields:
-func (_m *BlockchainRetriever) GetBeaconHeightBreakPointBurnAddr() uint64 {
-	ret := _m.Called()
-
-	var r0 uint64
-	if rf, ok := ret.Get(0).(func() uint64); ok {
-		r0 = rf()
-	} else {
-		r0 = ret.Get(0).(uint64)
-	}
-
-	return r0
-}
-
 // GetBeaconRewardStateDB provides a mock function with given fields:
 func (_m *BlockchainRetriever) GetBeaconRewardStateDB() *statedb.StateDB {
 	ret := _m.Called()
@@ -441,6 +428,20 @@ func (_m *BlockchainRetriever) GetTxValue(txid string) (uint64, error) {
 	return r0, r1
 }
 
+// IsForkActive provides a mock function with given fields: fork, beaconHeight
+func (_m *BlockchainRetriever) IsForkActive(fork forks.Fork, beaconHeight uint64) bool {
+	ret := _m.Called(fork, beaconHeight)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(forks.Fork, uint64) bool); ok {
+		r0 = rf(fork, beaconHeight)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // ListPrivacyTokenAndBridgeTokenAndPRVByShardID provides a mock function with given fields: _a0
 func (_m *BlockchainRetriever) ListPrivacyTokenAndBridgeTokenAndPRVByShardID(_a0 byte) ([]common.Hash, error) {
 	ret := _m.Called(_a0)
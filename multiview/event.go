@@ -0,0 +1,106 @@
+package multiview
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/pubsub"
+)
+
+// NewBestViewEvent is published whenever the best view of a chain changes
+type NewBestViewEvent struct {
+	ChainID      int
+	Hash         common.Hash
+	PreviousHash common.Hash
+	Height       uint64
+}
+
+// FinalViewEvent is published whenever the final view of a chain advances
+type FinalViewEvent struct {
+	ChainID        int
+	Hash           common.Hash
+	Height         uint64
+	PreviousFinal  common.Hash
+	PreviousHeight uint64
+}
+
+// ReorgEvent is published when the new best view is not a direct child of the previous best view.
+// Detached hashes are ordered from the old tip down to the fork point,
+// Attached hashes are ordered from the fork point up to the new tip.
+type ReorgEvent struct {
+	ChainID     int
+	ForkPoint   common.Hash
+	ForkHeight  uint64
+	OldBestHash common.Hash
+	NewBestHash common.Hash
+	Detached    []common.Hash
+	Attached    []common.Hash
+}
+
+// SetPubSubManager enables event publishing for this multiview, chainID is -1 for beacon
+func (multiView *MultiView) SetPubSubManager(chainID int, pubSubManager *pubsub.PubSubManager) {
+	multiView.chainID = chainID
+	multiView.pubSubManager = pubSubManager
+}
+
+// publish queues the event in the pubsub manager, synchronously so the
+// subscribers receive the events of the multiview in the order they happened
+func (multiView *MultiView) publish(topic string, value interface{}) {
+	if multiView.pubSubManager == nil {
+		return
+	}
+	multiView.pubSubManager.PublishMessage(pubsub.NewMessage(topic, value))
+}
+
+// notifyViewChange compares the previous best/final views with the current ones and publishes the corresponding events
+func (multiView *MultiView) notifyViewChange(prevBest, prevFinal View) {
+	if prevBest != nil && multiView.bestView != nil && !prevBest.GetHash().IsEqual(multiView.bestView.GetHash()) {
+		multiView.publish(pubsub.NewBestViewTopic, &NewBestViewEvent{
+			ChainID:      multiView.chainID,
+			Hash:         *multiView.bestView.GetHash(),
+			PreviousHash: *multiView.bestView.GetPreviousHash(),
+			Height:       multiView.bestView.GetHeight(),
+		})
+		if !multiView.bestView.GetPreviousHash().IsEqual(prevBest.GetHash()) {
+			if reorg := multiView.getReorgEvent(prevBest, multiView.bestView); reorg != nil {
+				multiView.publish(pubsub.ChainReorgTopic, reorg)
+			}
+		}
+	}
+	if prevFinal != nil && multiView.finalView != nil && !prevFinal.GetHash().IsEqual(multiView.finalView.GetHash()) {
+		multiView.publish(pubsub.FinalizedViewTopic, &FinalViewEvent{
+			ChainID:        multiView.chainID,
+			Hash:           *multiView.finalView.GetHash(),
+			Height:         multiView.finalView.GetHeight(),
+			PreviousFinal:  *prevFinal.GetHash(),
+			PreviousHeight: prevFinal.GetHeight(),
+		})
+	}
+}
+
+// getReorgEvent walks back from both tips until it finds the common ancestor.
+// It returns nil if one of the branches can not be traced back to the fork point.
+func (multiView *MultiView) getReorgEvent(oldBest, newBest View) *ReorgEvent {
+	detached := []common.Hash{}
+	attached := []common.Hash{}
+	oldView, newView := oldBest, newBest
+	for !oldView.GetHash().IsEqual(newView.GetHash()) {
+		if oldView.GetHeight() >= newView.GetHeight() {
+			detached = append(detached, *oldView.GetHash())
+			oldView = multiView.viewByHash[*oldView.GetPreviousHash()]
+		} else {
+			attached = append([]common.Hash{*newView.GetHash()}, attached...)
+			newView = multiView.viewByHash[*newView.GetPreviousHash()]
+		}
+		if oldView == nil || newView == nil {
+			return nil
+		}
+	}
+	return &ReorgEvent{
+		ChainID:     multiView.chainID,
+		ForkPoint:   *oldView.GetHash(),
+		ForkHeight:  oldView.GetHeight(),
+		OldBestHash: *oldBest.GetHash(),
+		NewBestHash: *newBest.GetHash(),
+		Detached:    detached,
+		Attached:    attached,
+	}
+}
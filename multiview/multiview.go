@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"sync"
 	"time"
 )
 
@@ -21,9 +23,14 @@ type MultiView struct {
 	viewByPrevHash map[common.Hash][]View
 	actionCh       chan func()
 
-	//state
+	//state, only changed by the actions, lock guards them for the readers
 	finalView View
 	bestView  View
+	lock      sync.RWMutex
+
+	//event
+	chainID       int
+	pubSubManager *pubsub.PubSubManager
 }
 
 func NewMultiView() *MultiView {
@@ -103,21 +110,29 @@ func (multiView *MultiView) AddView(view View) bool {
 }
 
 func (multiView *MultiView) GetBestView() View {
+	multiView.lock.RLock()
+	defer multiView.lock.RUnlock()
 	return multiView.bestView
 }
 
 func (multiView *MultiView) GetFinalView() View {
+	multiView.lock.RLock()
+	defer multiView.lock.RUnlock()
 	return multiView.finalView
 }
 
 //update view whenever there is new view insert into system
 func (multiView *MultiView) updateViewState(newView View) {
+	prevBest, prevFinal := multiView.bestView, multiView.finalView
 	defer func() {
+		multiView.notifyViewChange(prevBest, prevFinal)
 		if multiView.viewByHash[*multiView.finalView.GetPreviousHash()] != nil {
 			delete(multiView.viewByHash, *multiView.finalView.GetPreviousHash())
 			delete(multiView.viewByPrevHash, *multiView.finalView.GetPreviousHash())
 		}
 	}()
+	multiView.lock.Lock()
+	defer multiView.lock.Unlock()
 
 	if multiView.finalView == nil {
 		multiView.bestView = newView
@@ -167,7 +182,7 @@ func (multiView *MultiView) updateViewState(newView View) {
 }
 
 func (multiView *MultiView) GetAllViewsWithBFS() []View {
-	queue := []View{multiView.finalView}
+	queue := []View{multiView.GetFinalView()}
 	resCh := make(chan []View)
 
 	multiView.actionCh <- func() {
@@ -3,9 +3,34 @@ package multiview
 import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/pubsub"
 	"testing"
+	"time"
 )
 
+type FakeBlock struct {
+	hash     *common.Hash
+	prevHash *common.Hash
+	height   uint64
+	time     int64
+}
+
+func (s *FakeBlock) GetVersion() int             { return 2 }
+func (s *FakeBlock) GetHeight() uint64           { return s.height }
+func (s *FakeBlock) Hash() *common.Hash          { return s.hash }
+func (s *FakeBlock) GetProducer() string         { return "" }
+func (s *FakeBlock) GetValidationField() string  { return "" }
+func (s *FakeBlock) GetRound() int               { return 1 }
+func (s *FakeBlock) GetRoundKey() string         { return "" }
+func (s *FakeBlock) GetInstructions() [][]string { return nil }
+func (s *FakeBlock) GetConsensusType() string    { return common.BlsConsensus }
+func (s *FakeBlock) GetCurrentEpoch() uint64     { return 1 }
+func (s *FakeBlock) GetProduceTime() int64       { return s.time }
+func (s *FakeBlock) GetProposeTime() int64       { return s.time }
+func (s *FakeBlock) GetPrevHash() common.Hash    { return *s.prevHash }
+func (s *FakeBlock) GetProposer() string         { return "" }
+
 type FakeView struct {
 	hash     *common.Hash
 	prevHash *common.Hash
@@ -34,6 +59,18 @@ func (s *FakeView) GetBlockTime() int64 {
 	return s.time
 }
 
+func (s *FakeView) GetCommittee() []incognitokey.CommitteePublicKey {
+	return nil
+}
+
+func (s *FakeView) GetProposerByTimeSlot(ts int64, version int) incognitokey.CommitteePublicKey {
+	return incognitokey.CommitteePublicKey{}
+}
+
+func (s *FakeView) GetBlock() common.BlockInterface {
+	return &FakeBlock{s.hash, s.prevHash, s.height, s.time * common.TIMESLOT}
+}
+
 func TestNewMultiView(t *testing.T) {
 
 	multiView := NewMultiView()
@@ -94,3 +131,109 @@ func TestNewMultiView(t *testing.T) {
 		panic("Wrong")
 	}
 }
+
+func TestMultiViewReorgEvent(t *testing.T) {
+	pubSubManager := pubsub.NewPubSubManager()
+	go pubSubManager.Start()
+	_, reorgCh, _ := pubSubManager.RegisterNewSubscriber(pubsub.ChainReorgTopic)
+	_, finalCh, _ := pubSubManager.RegisterNewSubscriber(pubsub.FinalizedViewTopic)
+
+	multiView := NewMultiView()
+	multiView.SetPubSubManager(2, pubSubManager)
+	multiView.AddView(&FakeView{&common.Hash{1}, &common.Hash{0}, 1, 1, 1})
+	multiView.AddView(&FakeView{&common.Hash{2}, &common.Hash{1}, 2, 2, 2})
+	multiView.AddView(&FakeView{&common.Hash{3}, &common.Hash{2}, 3, 4, 4})
+	multiView.AddView(&FakeView{&common.Hash{4}, &common.Hash{3}, 4, 6, 6})
+	// same height, produced earlier -> become best view, switching branch
+	multiView.AddView(&FakeView{&common.Hash{5}, &common.Hash{3}, 4, 5, 5})
+
+	select {
+	case msg := <-reorgCh:
+		reorg := msg.Value.(*ReorgEvent)
+		if reorg.ChainID != 2 || !reorg.ForkPoint.IsEqual(&common.Hash{3}) {
+			t.Fatalf("wrong fork point %+v", reorg)
+		}
+		if len(reorg.Detached) != 1 || !reorg.Detached[0].IsEqual(&common.Hash{4}) {
+			t.Fatalf("wrong detached list %+v", reorg.Detached)
+		}
+		if len(reorg.Attached) != 1 || !reorg.Attached[0].IsEqual(&common.Hash{5}) {
+			t.Fatalf("wrong attached list %+v", reorg.Attached)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reorg event")
+	}
+
+	select {
+	case msg := <-finalCh:
+		if msg.Value.(*FinalViewEvent).ChainID != 2 {
+			t.Fatal("wrong chain id")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no finalized event")
+	}
+}
+
+func TestMultiViewEventOrder(t *testing.T) {
+	pubSubManager := pubsub.NewPubSubManager()
+	go pubSubManager.Start()
+	_, bestCh, _ := pubSubManager.RegisterNewSubscriber(pubsub.NewBestViewTopic)
+	_, finalCh, _ := pubSubManager.RegisterNewSubscriber(pubsub.FinalizedViewTopic)
+
+	multiView := NewMultiView()
+	multiView.SetPubSubManager(-1, pubSubManager)
+	n := 50
+	for i := 1; i <= n; i++ {
+		multiView.AddView(&FakeView{&common.Hash{byte(i)}, &common.Hash{byte(i - 1)}, uint64(i), uint64(i), int64(i)})
+	}
+
+	// the best view moves from 1 to n and the final view from 1 to n-1, one
+	// height at a time
+	for topic, ch := range map[string]pubsub.EventChannel{pubsub.NewBestViewTopic: bestCh, pubsub.FinalizedViewTopic: finalCh} {
+		last := n
+		if topic == pubsub.FinalizedViewTopic {
+			last = n - 1
+		}
+		for height := 2; height <= last; height++ {
+			select {
+			case msg := <-ch:
+				got := uint64(0)
+				switch event := msg.Value.(type) {
+				case *NewBestViewEvent:
+					got = event.Height
+				case *FinalViewEvent:
+					got = event.Height
+				}
+				if got != uint64(height) {
+					t.Fatalf("%s event of height %d received instead of %d", topic, got, height)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("no %s event of height %d", topic, height)
+			}
+		}
+	}
+}
+
+func TestGetReorgEvent(t *testing.T) {
+	multiView := NewMultiView()
+	views := []*FakeView{
+		{&common.Hash{1}, &common.Hash{0}, 1, 1, 1},
+		{&common.Hash{2}, &common.Hash{1}, 2, 2, 2},
+		{&common.Hash{3}, &common.Hash{2}, 3, 3, 3},
+		{&common.Hash{4}, &common.Hash{1}, 2, 4, 4},
+		{&common.Hash{5}, &common.Hash{4}, 3, 5, 5},
+		{&common.Hash{6}, &common.Hash{5}, 4, 6, 6},
+	}
+	for _, v := range views {
+		multiView.viewByHash[*v.GetHash()] = v
+	}
+	reorg := multiView.getReorgEvent(views[2], views[5])
+	if reorg == nil || !reorg.ForkPoint.IsEqual(&common.Hash{1}) || reorg.ForkHeight != 1 {
+		t.Fatalf("wrong fork point %+v", reorg)
+	}
+	if len(reorg.Detached) != 2 || !reorg.Detached[0].IsEqual(&common.Hash{3}) || !reorg.Detached[1].IsEqual(&common.Hash{2}) {
+		t.Fatalf("wrong detached list %+v", reorg.Detached)
+	}
+	if len(reorg.Attached) != 3 || !reorg.Attached[0].IsEqual(&common.Hash{4}) || !reorg.Attached[2].IsEqual(&common.Hash{6}) {
+		t.Fatalf("wrong attached list %+v", reorg.Attached)
+	}
+}
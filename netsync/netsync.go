@@ -14,6 +14,7 @@ import (
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/peer"
 	"github.com/incognitochain/incognito-chain/pubsub"
+	"github.com/incognitochain/incognito-chain/txtracker"
 	"github.com/incognitochain/incognito-chain/wire"
 	libp2p "github.com/libp2p/go-libp2p-peer"
 	"github.com/patrickmn/go-cache"
@@ -270,6 +271,7 @@ func (netSync *NetSync) handleMessageTx(msg *wire.MessageTx, beaconHeight int64)
 		return
 	}
 	if isAdded := netSync.handleCacheTx(*msg.Transaction.Hash()); !isAdded {
+		txtracker.Received(msg.Transaction, txtracker.SourcePeer)
 		hash, _, err := netSync.config.TxMemPool.MaybeAcceptTransaction(msg.Transaction, beaconHeight)
 		if err != nil {
 			Logger.log.Error(err)
@@ -300,6 +302,7 @@ func (netSync *NetSync) handleMessageTxPrivacyToken(msg *wire.MessageTxPrivacyTo
 		return
 	}
 	if isAdded := netSync.handleCacheTx(*msg.Transaction.Hash()); !isAdded {
+		txtracker.Received(msg.Transaction, txtracker.SourcePeer)
 		hash, _, err := netSync.config.TxMemPool.MaybeAcceptTransaction(msg.Transaction, beaconHeight)
 		if err != nil {
 			Logger.log.Error(err)
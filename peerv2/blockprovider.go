@@ -90,8 +90,14 @@ func (bp *BlockProvider) StreamBlockByHeight(
 	uuid := req.GetUUID()
 	// Logger.Infof("[stream] Block provider received request block type %v, blk heights specific %v [%v..%v], len %v", req.GetType(), req.GetSpecific(), req.Heights[0], req.Heights[len(req.Heights)-1], len(req.Heights))
 	Logger.Infof("[stream] Block provider received request stream block type %v, spec %v, height [%v..%v] len %v, from %v to %v, uuid = %s ", req.Type, req.Specific, req.Heights[0], req.Heights[len(req.Heights)-1], len(req.Heights), req.From, req.To, uuid)
-	blkRecv := bp.NetSync.StreamBlockByHeight(false, req)
+	blkReq := *req
+	blkType, headerOnly := BlockTypeOf(req.Type)
+	blkReq.Type = blkType
+	blkRecv := bp.NetSync.StreamBlockByHeight(false, &blkReq)
 	for blk := range blkRecv {
+		if headerOnly {
+			blk = BlockHeader(blk)
+		}
 		rdata, err := wrapper.EnCom(blk)
 		blkData := append([]byte{byte(req.Type)}, rdata...)
 		if err != nil {
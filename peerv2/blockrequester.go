@@ -66,7 +66,7 @@ func (c *BlockRequester) keepConnection() {
 		select {
 		case <-watchTimestep.C:
 			ready := c.IsReady()
-			if ready {
+			if ready || currentHWID == peer.ID("") { // Nothing to dial before the first target
 				continue
 			}
 
@@ -93,7 +93,7 @@ func (c *BlockRequester) keepConnection() {
 
 		case hwID := <-c.peerIDs:
 			Logger.Infof("Received new highway peerID, old = %s, new = %s", currentHWID.String(), hwID.String())
-			if hwID != currentHWID && c.conn != nil {
+			if hwID != currentHWID {
 				closeConnection()
 			}
 			currentHWID = hwID
@@ -122,6 +122,8 @@ func (c *BlockRequester) UpdateTarget(p peer.ID) {
 }
 
 func (c *BlockRequester) Target() string {
+	c.RLock()
+	defer c.RUnlock()
 	if c.conn == nil {
 		return ""
 	}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/incognitochain/incognito-chain/peerv2/mocks"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -22,11 +23,13 @@ func TestDialWithTimeout(t *testing.T) {
 		_, ok := ctx.Deadline()
 		hasTimeout = ok
 	})
-	c := BlockRequester{prtc: dialer, stop: make(chan int, 2)}
+	c := BlockRequester{prtc: dialer, stop: make(chan int, 2), peerIDs: make(chan peer.ID, 1)}
+	c.UpdateTarget(peer.ID("highway"))
 
-	go c.keepConnection()
+	done := start(c.keepConnection)
 	time.Sleep(200 * time.Millisecond)
 	c.stop <- 1
+	<-done
 
 	assert.True(t, hasTimeout)
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/hex"
 	"reflect"
+	"sync/atomic"
 	"time"
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metrics"
 	"github.com/incognitochain/incognito-chain/peerv2/proto"
 	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+	"github.com/incognitochain/incognito-chain/txtracker"
 	"github.com/incognitochain/incognito-chain/wire"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -67,6 +70,7 @@ func (cm *ConnManager) PublishMessage(msg wire.Message) error {
 						Logger.Errorf("Broadcast to topic %v error %v", topic, err)
 						return err
 					}
+					trackRelayedTx(msg, topic)
 				}
 
 			}
@@ -81,6 +85,17 @@ func (cm *ConnManager) PublishMessage(msg wire.Message) error {
 	return nil
 }
 
+// trackRelayedTx records the broadcast of the tx messages in the lifecycle
+// of their tx
+func trackRelayedTx(msg wire.Message, topic string) {
+	switch msg := msg.(type) {
+	case *wire.MessageTx:
+		txtracker.Relayed(msg.Transaction, topic)
+	case *wire.MessageTxPrivacyToken:
+		txtracker.Relayed(msg.Transaction, topic)
+	}
+}
+
 func (cm *ConnManager) PublishMessageToShard(msg wire.Message, shardID byte) error {
 	publishable := []string{wire.CmdBlockShard, wire.CmdCrossShard, wire.CmdBFT}
 	msgType := msg.MessageType()
@@ -105,19 +120,37 @@ func (cm *ConnManager) PublishMessageToShard(msg wire.Message, shardID byte) err
 func (cm *ConnManager) Start(ns NetSync) {
 	// Pubsub
 	var err error
-	cm.ps, err = pubsub.NewFloodSub(context.Background(), cm.LocalHost.Host)
+	opts := []pubsub.Option{}
+	if cm.disp != nil && cm.disp.Reputation != nil {
+		opts = append(opts, pubsub.WithBlacklist(reputationBlacklist{rep: cm.disp.Reputation}))
+	}
+	if cm.direct != nil {
+		// Gossipsub also talks to the floodsub highways, and forms a mesh
+		// with the direct peers in direct mode
+		cm.ps, err = pubsub.NewGossipSub(context.Background(), cm.LocalHost.Host, opts...)
+	} else {
+		cm.ps, err = pubsub.NewFloodSub(context.Background(), cm.LocalHost.Host, opts...)
+	}
 	if err != nil {
 		panic(err)
 	}
 	cm.messages = make(chan *pubsub.Message, 1000)
 	cm.data = make(chan []byte, 1000)
+	metrics.NewRegisteredFunctionalGauge("peerv2/peers", nil, func() int64 {
+		return int64(len(cm.LocalHost.Host.Network().Peers()))
+	})
 
 	// NOTE: must Connect after creating FloodSub
 	go cm.keepHighwayConnection()
 
 	cm.Requester = NewRequester(cm.LocalHost.GRPC)
 	cm.Requester.HandleResponseBlock = cm.PutData
-	cm.subscriber = NewSubManager(cm.info, cm.ps, cm.Requester, cm.messages)
+	var registerer Registerer = cm.Requester
+	if cm.direct != nil {
+		registerer = &modeRegisterer{BlockRequester: cm.Requester, cm: cm}
+		go cm.keepDirectPeers()
+	}
+	cm.subscriber = NewSubManager(cm.info, cm.ps, registerer, cm.messages)
 	cm.Provider = NewBlockProvider(cm.LocalHost.GRPC, ns)
 	go cm.manageRoleSubscription()
 	cm.process()
@@ -167,6 +200,7 @@ type ConnManager struct {
 	subscriber   ForcedSubscriber
 	disconnected int
 	registered   bool
+	connected    int32 // 1 while a highway is connected, read atomically
 
 	DiscoverPeersAddress string
 	IsMasterNode         bool
@@ -181,10 +215,20 @@ type ConnManager struct {
 	disp       *Dispatcher
 	Requester  *BlockRequester
 	Provider   *BlockProvider
+	direct     *direct // nil if direct fallback is disabled
 
 	stop chan int
 }
 
+// Reputation returns the scores of the peers, nil if the node does not score
+// them
+func (cm *ConnManager) Reputation() *Reputation {
+	if cm.disp == nil {
+		return nil
+	}
+	return cm.disp.Reputation
+}
+
 func (cm *ConnManager) PutMessage(msg *pubsub.Message) {
 	cm.messages <- msg
 }
@@ -198,7 +242,7 @@ func (cm *ConnManager) process() {
 	for {
 		select {
 		case msg := <-cm.messages:
-			err := cm.disp.processInMessageString(string(msg.Data))
+			err := cm.disp.processInMessage(msg.GetFrom(), string(msg.Data))
 			if err != nil {
 				Logger.Warn(err)
 			}
@@ -232,6 +276,7 @@ func (cm *ConnManager) keepHighwayConnection() {
 	defer refreshTimestep.Stop()
 	cm.disconnected = 1 // Init, to make first connection to highway
 	pid := cm.LocalHost.Host.ID()
+	lastConnected := time.Now() // Last time a highway was connected, for direct fallback
 
 	refreshHighway := func() (*rpcclient.HighwayAddr, error) {
 		newHighway, err := cm.keeper.ChooseHighway(cm.discoverer, pid)
@@ -245,6 +290,9 @@ func (cm *ConnManager) keepHighwayConnection() {
 	for {
 		select {
 		case <-watchTimestep.C:
+			if cm.direct != nil {
+				cm.checkFallback(currentHighway, &lastConnected)
+			}
 			if currentHighway == nil {
 				var err error
 				if currentHighway, err = refreshHighway(); err != nil || currentHighway == nil {
@@ -263,7 +311,7 @@ func (cm *ConnManager) keepHighwayConnection() {
 
 		case <-cm.stop:
 			Logger.Info("Stop keeping connection to highway")
-			break
+			return
 		}
 	}
 }
@@ -272,6 +320,7 @@ func (cm *ConnManager) checkConnection(addrInfo *peer.AddrInfo) bool {
 	net := cm.LocalHost.Host.Network()
 	// Reconnect if not connected
 	if net.Connectedness(addrInfo.ID) != network.Connected {
+		atomic.StoreInt32(&cm.connected, 0)
 		cm.disconnected++
 		cm.registered = false // Next time we connect to highway, we need to register again
 		Logger.Info("Not connected to highway, connecting")
@@ -285,11 +334,17 @@ func (cm *ConnManager) checkConnection(addrInfo *peer.AddrInfo) bool {
 			cm.disconnected = 0 // Retry N times for next chosen highway
 			return true
 		}
+	} else {
+		atomic.StoreInt32(&cm.connected, 1)
 	}
 
 	if !cm.registered && net.Connectedness(addrInfo.ID) == network.Connected {
 		// Register again since this might be a new highway
 		Logger.Info("Connected to highway, sending register request")
+		atomic.StoreInt32(&cm.connected, 1)
+		if cm.IsDirect() {
+			cm.leaveDirectMode()
+		}
 		cm.registerRequests <- addrInfo.ID
 		cm.disconnected = 0
 		cm.registered = true
@@ -297,6 +352,16 @@ func (cm *ConnManager) checkConnection(addrInfo *peer.AddrInfo) bool {
 	return false
 }
 
+// IsHighwayConnected returns whether the node is connected to a highway
+func (cm *ConnManager) IsHighwayConnected() bool {
+	return atomic.LoadInt32(&cm.connected) == 1
+}
+
+// PeerCount returns the number of peers the node is connected to
+func (cm *ConnManager) PeerCount() int {
+	return len(cm.LocalHost.Host.Network().Peers())
+}
+
 // manageRoleSubscription: polling current role periodically and subscribe to relevant topics
 func (cm *ConnManager) manageRoleSubscription() {
 	forced := false // only subscribe when role changed or last forced subscribe failed
@@ -336,7 +401,7 @@ func (cm *ConnManager) manageRoleSubscription() {
 
 		case <-cm.stop:
 			Logger.Info("Stop managing role subscription")
-			break
+			return
 		}
 	}
 }
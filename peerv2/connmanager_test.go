@@ -65,9 +65,10 @@ func TestConnectAtStart(t *testing.T) {
 		registerRequests: make(chan peer.ID, 1),
 		keeper:           NewAddrKeeper(),
 	}
-	go cm.keepHighwayConnection()
+	done := start(cm.keepHighwayConnection)
 	time.Sleep(200 * time.Millisecond)
 	close(cm.stop)
+	<-done
 
 	assert.Equal(t, 1, len(cm.registerRequests), "not connect at startup")
 }
@@ -96,9 +97,10 @@ func TestConnectWhenMaxedRetry(t *testing.T) {
 		registerRequests: make(chan peer.ID, 1),
 		keeper:           NewAddrKeeper(),
 	}
-	go cm.keepHighwayConnection()
+	done := start(cm.keepHighwayConnection)
 	time.Sleep(1 * time.Second)
 	close(cm.stop)
+	<-done
 
 	discoverer.AssertNumberOfCalls(t, "DiscoverHighway", 2)
 	assert.Equal(t, 1, len(cm.keeper.ignoreHWUntil))
@@ -178,9 +180,10 @@ func TestPeriodicManageSub(t *testing.T) {
 		subscriber:       sc,
 		keeper:           NewAddrKeeper(),
 	}
-	go cm.manageRoleSubscription()
+	done := start(cm.manageRoleSubscription)
 	time.Sleep(RegisterTimestep + 50*time.Millisecond)
 	close(cm.stop)
+	<-done
 
 	assert.Equal(t, 1, sc.normal, "not subbed")
 }
@@ -197,9 +200,10 @@ func TestForcedSub(t *testing.T) {
 		keeper:           NewAddrKeeper(),
 	}
 	cm.registerRequests <- peer.ID("") // Sent forced, must sub with forced = True next time
-	go cm.manageRoleSubscription()
+	done := start(cm.manageRoleSubscription)
 	time.Sleep(RegisterTimestep + 50*time.Millisecond)
 	close(cm.stop)
+	<-done
 
 	assert.Equal(t, 1, sc.forced, "not subbed")
 }
@@ -240,6 +244,16 @@ func setupConnectedness(net *mocks.Network, values []network.Connectedness) {
 	})
 }
 
+// start runs f in a goroutine, the returned channel is closed once f returns
+func start(f func()) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	return done
+}
+
 func configTime() func() {
 	reconnectHighwayTimestep := ReconnectHighwayTimestep
 	requesterDialTimestep := RequesterDialTimestep
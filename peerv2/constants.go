@@ -26,4 +26,15 @@ var (
 
 	IgnoreRPCDuration = 60 * time.Minute  // Ignore an address after a failed RPC
 	IgnoreHWDuration  = 360 * time.Minute // Ignore a highway when cannot connect
+
+	DirectFallbackTimeout = 1 * time.Minute  // Switch to direct mode when no highway is connected for this long
+	DirectPeerTimestep    = 30 * time.Second // Announce to bootnode and check direct peer connections
+	MaxDirectPeers        = 8                // Direct peers to keep connected in direct mode
+	PeerTableExpiry       = 72 * time.Hour   // Forget a direct peer not seen for this long
+	MaxPeerTableSize      = 1000
+
+	MaxReputationScore = 100              // Useful data cannot raise a score higher
+	BanScore           = -100             // Ban a peer whose score falls this low
+	BanDuration        = 30 * time.Minute // Ignore a banned peer for this long
+	MaxReputationPeers = 10000            // Peers to keep the score of
 )
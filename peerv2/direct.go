@@ -0,0 +1,288 @@
+package peerv2
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var (
+	directModeGauge   = metrics.NewRegisteredGauge("peerv2/direct/active", nil)
+	directPeersGauge  = metrics.NewRegisteredGauge("peerv2/direct/peers", nil)
+	peerTableGauge    = metrics.NewRegisteredGauge("peerv2/direct/table", nil)
+	modeSwitchCounter = metrics.NewRegisteredCounter("peerv2/direct/switch", nil)
+)
+
+const (
+	highwayMode int32 = iota
+	directMode
+)
+
+// DirectConfig sets up the direct mode of ConnManager, used when no highway
+// is reachable
+type DirectConfig struct {
+	BootnodeAddress string // rpc address of the bootnode keeping the direct peers
+	PeerTablePath   string // file saving the known direct peers
+	NumShards       int
+}
+
+type DirectDiscoverer interface {
+	AnnounceDirectPeer(bootnodeAddress string, self rpcclient.DirectPeer) ([]rpcclient.DirectPeer, error)
+}
+
+// direct keeps the state of the direct mode: instead of going through a
+// highway, the node joins a gossipsub mesh with the nodes of its peer table
+// and requests blocks to the BlockProvider of one of them
+type direct struct {
+	DirectConfig
+	table      *PeerTable
+	discoverer DirectDiscoverer
+	mode       int32
+	wake       chan struct{}
+
+	peers    map[peer.ID]rpcclient.DirectPeer // connected direct peers
+	provider peer.ID                          // direct peer the BlockRequester talks to
+	sync.Mutex
+}
+
+// EnableDirectFallback lets the node gossip and sync with the other nodes
+// directly when it cannot reach any highway for DirectFallbackTimeout; it goes
+// back to the highway as soon as one is reachable again. Must be called before
+// Start.
+func (cm *ConnManager) EnableDirectFallback(config DirectConfig) error {
+	table, err := NewPeerTable(config.PeerTablePath)
+	if err != nil {
+		return err
+	}
+	cm.direct = &direct{
+		DirectConfig: config,
+		table:        table,
+		discoverer:   new(rpcclient.RPCClient),
+		wake:         make(chan struct{}, 1),
+		peers:        map[peer.ID]rpcclient.DirectPeer{},
+	}
+	peerTableGauge.Update(int64(table.Len()))
+	return nil
+}
+
+// IsDirect returns true if the node gossips with its direct peers instead of
+// a highway
+func (cm *ConnManager) IsDirect() bool {
+	return cm.direct != nil && atomic.LoadInt32(&cm.direct.mode) == directMode
+}
+
+// DirectPeers returns the connected direct peers
+func (cm *ConnManager) DirectPeers() []rpcclient.DirectPeer {
+	if cm.direct == nil {
+		return nil
+	}
+	cm.direct.Lock()
+	defer cm.direct.Unlock()
+	peers := []rpcclient.DirectPeer{}
+	for _, p := range cm.direct.peers {
+		peers = append(peers, p)
+	}
+	return peers
+}
+
+// checkFallback switches to direct mode when no highway has been connected
+// for DirectFallbackTimeout
+func (cm *ConnManager) checkFallback(hw *rpcclient.HighwayAddr, lastConnected *time.Time) {
+	if hw != nil {
+		addrInfo, err := getAddressInfo(hw.Libp2pAddr)
+		if err == nil && cm.LocalHost.Host.Network().Connectedness(addrInfo.ID) == network.Connected {
+			*lastConnected = time.Now()
+			return
+		}
+	}
+	if !cm.IsDirect() && time.Since(*lastConnected) > DirectFallbackTimeout {
+		cm.enterDirectMode()
+	}
+}
+
+func (cm *ConnManager) enterDirectMode() {
+	Logger.Warnf("No highway connected for %v, switching to direct mode", DirectFallbackTimeout)
+	atomic.StoreInt32(&cm.direct.mode, directMode)
+	cm.registered = false // Register again when a highway is back
+	directModeGauge.Update(1)
+	modeSwitchCounter.Inc(1)
+	select {
+	case cm.direct.wake <- struct{}{}:
+	default:
+	}
+}
+
+// leaveDirectMode closes the connections to the direct peers, the node
+// registers to the highway right after
+func (cm *ConnManager) leaveDirectMode() {
+	Logger.Info("Highway connected, leaving direct mode")
+	atomic.StoreInt32(&cm.direct.mode, highwayMode)
+	directModeGauge.Update(0)
+	modeSwitchCounter.Inc(1)
+
+	cm.direct.Lock()
+	defer cm.direct.Unlock()
+	for pid := range cm.direct.peers {
+		if err := cm.LocalHost.Host.Network().ClosePeer(pid); err != nil {
+			Logger.Warnf("Failed closing connection to direct peer %v: %v", pid.Pretty(), err)
+		}
+	}
+	cm.direct.peers = map[peer.ID]rpcclient.DirectPeer{}
+	cm.direct.provider = peer.ID("")
+	directPeersGauge.Update(0)
+}
+
+// keepDirectPeers periodically announces the node to the bootnode, saves the
+// peer table and, in direct mode, keeps the node connected to direct peers
+func (cm *ConnManager) keepDirectPeers() {
+	timestep := time.NewTicker(DirectPeerTimestep)
+	defer timestep.Stop()
+	cm.refreshPeerTable()
+	for {
+		select {
+		case <-timestep.C:
+			cm.refreshPeerTable()
+		case <-cm.direct.wake:
+		case <-cm.stop:
+			Logger.Info("Stop keeping direct peers")
+			return
+		}
+		if cm.IsDirect() {
+			cm.connectDirectPeers()
+		}
+	}
+}
+
+func (cm *ConnManager) refreshPeerTable() {
+	d := cm.direct
+	peers, err := d.discoverer.AnnounceDirectPeer(d.BootnodeAddress, cm.selfDirectPeer())
+	if err != nil {
+		Logger.Warnf("Failed announcing direct peer to bootnode: %v", err)
+	}
+	d.table.Add(peers...)
+	if err := d.table.Save(); err != nil {
+		Logger.Errorf("Failed saving peer table: %+v", err)
+	}
+	peerTableGauge.Update(int64(d.table.Len()))
+}
+
+// connectDirectPeers connects up to MaxDirectPeers peers of the table, and
+// picks one of them to request blocks to if the previous one left
+func (cm *ConnManager) connectDirectPeers() {
+	d := cm.direct
+	d.Lock()
+	defer d.Unlock()
+	if !cm.IsDirect() { // Left direct mode while waiting for the lock
+		return
+	}
+
+	net := cm.LocalHost.Host.Network()
+	for pid, p := range d.peers {
+		if net.Connectedness(pid) != network.Connected {
+			delete(d.peers, pid)
+			continue
+		}
+		d.table.Seen(p.Libp2pAddr)
+	}
+
+	for _, p := range d.table.Peers() {
+		if len(d.peers) >= MaxDirectPeers {
+			break
+		}
+		addrInfo, err := getAddressInfo(p.Libp2pAddr)
+		if err != nil || addrInfo.ID == cm.peerID {
+			continue
+		}
+		if _, ok := d.peers[addrInfo.ID]; ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), DialTimeout)
+		err = cm.LocalHost.Host.Connect(ctx, *addrInfo)
+		cancel()
+		if err != nil {
+			Logger.Warnf("Could not connect to direct peer %v: %v", p.Libp2pAddr, err)
+			continue
+		}
+		d.peers[addrInfo.ID] = p
+		d.table.Seen(p.Libp2pAddr)
+	}
+	directPeersGauge.Update(int64(len(d.peers)))
+
+	if _, ok := d.peers[d.provider]; ok {
+		return
+	}
+	if provider := chooseProvider(d.peers, cm.wantedShardIDs()); provider != "" {
+		Logger.Infof("Requesting blocks to direct peer %v", provider.Pretty())
+		d.provider = provider
+		cm.registerRequests <- provider
+	}
+}
+
+// chooseProvider prefers a peer keeping the blocks of the committees the node
+// wants
+func chooseProvider(peers map[peer.ID]rpcclient.DirectPeer, wanted []byte) peer.ID {
+	provider := peer.ID("")
+	for pid, p := range peers {
+		for _, cID := range wanted {
+			if containsCommittee(p.CommitteeID, cID) {
+				return pid
+			}
+		}
+		if provider == "" {
+			provider = pid
+		}
+	}
+	return provider
+}
+
+func (cm *ConnManager) wantedShardIDs() []byte {
+	return getWantedShardIDs(newUserRole(cm.consensusData.GetUserRole()), cm.nodeMode, cm.relayShard)
+}
+
+// selfDirectPeer returns the address other nodes dial to reach this node,
+// the listening address of the public ip of the host if any
+func (cm *ConnManager) selfDirectPeer() rpcclient.DirectPeer {
+	addrs := cm.LocalHost.Host.Addrs()
+	self := rpcclient.DirectPeer{CommitteeID: cm.wantedShardIDs()}
+	if len(addrs) == 0 {
+		return self
+	}
+	addr := addrs[0].String()
+	for _, a := range addrs {
+		if strings.HasPrefix(a.String(), "/ip4/"+cm.LocalHost.SelfPeer.IP+"/") {
+			addr = a.String()
+			break
+		}
+	}
+	self.Libp2pAddr = addr + "/p2p/" + peer.IDB58Encode(cm.peerID)
+	return self
+}
+
+// modeRegisterer registers to the highway in highway mode; in direct mode,
+// there is no one to register to and the node takes the topics highways give
+type modeRegisterer struct {
+	*BlockRequester
+	cm *ConnManager
+}
+
+func (r *modeRegisterer) Register(
+	ctx context.Context,
+	pubkey string,
+	messages []string,
+	committeeIDs []byte,
+	selfID peer.ID,
+	role string,
+) ([]*proto.MessageTopicPair, *proto.UserRole, error) {
+	if !r.cm.IsDirect() {
+		return r.BlockRequester.Register(ctx, pubkey, messages, committeeIDs, selfID, role)
+	}
+	return TopicPairs(messages, committeeIDs, r.cm.direct.NumShards), &proto.UserRole{Role: role}, nil
+}
@@ -0,0 +1,20 @@
+package peerv2
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseProvider(t *testing.T) {
+	peers := map[peer.ID]rpcclient.DirectPeer{
+		peer.ID("beacon"): {CommitteeID: []byte{HighwayBeaconID}},
+		peer.ID("shard1"): {CommitteeID: []byte{1}},
+	}
+	assert.Equal(t, peer.ID("shard1"), chooseProvider(peers, []byte{1}))
+	assert.Equal(t, peer.ID("beacon"), chooseProvider(peers, []byte{HighwayBeaconID}))
+	assert.NotEqual(t, peer.ID(""), chooseProvider(peers, []byte{2}))
+	assert.Equal(t, peer.ID(""), chooseProvider(map[peer.ID]rpcclient.DirectPeer{}, []byte{2}))
+}
@@ -19,6 +19,30 @@ type Dispatcher struct {
 	PublishableMessage []string
 	BC                 *blockchain.BlockChain
 	CurrentHWPeerID    libp2p.ID
+	Reputation         *Reputation // nil to accept messages from every peer
+}
+
+// errOversizedPayload is returned for a message bigger than the maximum
+// payload of its type
+var errOversizedPayload = errors.New("oversized payload")
+
+// processInMessage drops the messages of banned peers and lowers the score of
+// a peer sending a message which cannot be processed
+func (d *Dispatcher) processInMessage(from libp2p.ID, msgStr string) error {
+	if d.Reputation.IsBanned(from.Pretty()) {
+		Logger.Debugf("Dropping message of banned peer %v", from.Pretty())
+		return nil
+	}
+	err := d.processInMessageString(from, msgStr)
+	if err == nil {
+		return nil
+	}
+	if errors.Cause(err) == errOversizedPayload {
+		d.Reputation.Report(from.Pretty(), OversizedPayload)
+	} else {
+		d.Reputation.Report(from.Pretty(), InvalidMessage)
+	}
+	return err
 }
 
 // Just for consensus v1
@@ -71,7 +95,7 @@ func (d *Dispatcher) processStreamBlk(blktype byte, data []byte) error {
 // processInMessageString - this is sub-function of InMessageHandler
 // after receiving a good message from stream,
 // we need analyze it and process with corresponding message type
-func (d *Dispatcher) processInMessageString(msgStr string) error {
+func (d *Dispatcher) processInMessageString(from libp2p.ID, msgStr string) error {
 	// NOTE: copy from peerConn.processInMessageString
 	// Parse Message header from last 24 bytes header message
 	jsonDecodeBytesRaw, err := hex.DecodeString(msgStr)
@@ -96,6 +120,10 @@ func (d *Dispatcher) processInMessageString(msgStr string) error {
 
 	// fmt.Printf("In message content : %s", string(jsonDecodeBytes))
 
+	if len(jsonDecodeBytes) < wire.MessageHeaderSize {
+		return errors.Errorf("Message too short %v, it must be at least %v", len(jsonDecodeBytes), wire.MessageHeaderSize)
+	}
+
 	// Parse Message body
 	messageBody := jsonDecodeBytes[:len(jsonDecodeBytes)-wire.MessageHeaderSize]
 
@@ -111,7 +139,7 @@ func (d *Dispatcher) processInMessageString(msgStr string) error {
 	}
 
 	if len(jsonDecodeBytes) > message.MaxPayloadLength(wire.Version) {
-		return errors.Errorf("Message size too lagre %v, it must be less than %v", len(jsonDecodeBytes), message.MaxPayloadLength(wire.Version))
+		return errors.Wrapf(errOversizedPayload, "Message size too lagre %v, it must be less than %v", len(jsonDecodeBytes), message.MaxPayloadLength(wire.Version))
 	}
 	// check forward TODO
 	/*if peerConn.config.MessageListeners.GetCurrentRoleShard != nil {
@@ -162,7 +190,7 @@ func (d *Dispatcher) processInMessageString(msgStr string) error {
 	// }
 
 	// process message for each of message type
-	errProcessMessage := d.processMessageForEachType(realType, message)
+	errProcessMessage := d.processMessageForEachType(from, realType, message)
 	if errProcessMessage != nil {
 		return errors.WithStack(errProcessMessage)
 	}
@@ -173,11 +201,15 @@ func (d *Dispatcher) processInMessageString(msgStr string) error {
 }
 
 // process message for each of message type
-func (d *Dispatcher) processMessageForEachType(messageType reflect.Type, message wire.Message) error {
+func (d *Dispatcher) processMessageForEachType(from libp2p.ID, messageType reflect.Type, message wire.Message) error {
 	// NOTE: copy from peerConn.processInMessageString
 	Logger.Debugf("Processing msgType %s", message.MessageType())
 	peerConn := &peer.PeerConn{}
-	peerConn.SetRemotePeerID(d.CurrentHWPeerID)
+	// The listeners see the author of the message, or the highway if unknown
+	if from == "" {
+		from = d.CurrentHWPeerID
+	}
+	peerConn.SetRemotePeerID(from)
 	//fmt.Printf("[stream2] %v\n", peerConn.GetRemotePeerID())
 	switch messageType {
 	case reflect.TypeOf(&wire.MessageTx{}):
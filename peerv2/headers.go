@@ -0,0 +1,38 @@
+package peerv2
+
+import (
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+)
+
+// Header block types request the headers and validation data of the blocks,
+// with empty bodies. They are not in highway.proto: highways forward block
+// requests without looking at their type.
+const (
+	BlkTypeShardHeader  proto.BlkType = 100 + proto.BlkType_BlkShard
+	BlkTypeBeaconHeader proto.BlkType = 100 + proto.BlkType_BlkBc
+)
+
+// BlockTypeOf returns the type of the blocks streamed for a request of type
+// t, and whether their bodies are left out
+func BlockTypeOf(t proto.BlkType) (proto.BlkType, bool) {
+	switch t {
+	case BlkTypeShardHeader:
+		return proto.BlkType_BlkShard, true
+	case BlkTypeBeaconHeader:
+		return proto.BlkType_BlkBc, true
+	}
+	return t, false
+}
+
+// BlockHeader returns a copy of blk without body, the hash of a block is the
+// hash of its header so the copy keeps the hash and validation data of blk
+func BlockHeader(blk interface{}) interface{} {
+	switch blk := blk.(type) {
+	case *blockchain.BeaconBlock:
+		return &blockchain.BeaconBlock{ValidationData: blk.ValidationData, Header: blk.Header}
+	case *blockchain.ShardBlock:
+		return &blockchain.ShardBlock{ValidationData: blk.ValidationData, Header: blk.Header}
+	}
+	return blk
+}
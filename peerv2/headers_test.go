@@ -0,0 +1,38 @@
+package peerv2
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockTypeOf(t *testing.T) {
+	blkType, headerOnly := BlockTypeOf(BlkTypeShardHeader)
+	assert.Equal(t, proto.BlkType_BlkShard, blkType)
+	assert.True(t, headerOnly)
+	blkType, headerOnly = BlockTypeOf(BlkTypeBeaconHeader)
+	assert.Equal(t, proto.BlkType_BlkBc, blkType)
+	assert.True(t, headerOnly)
+	blkType, headerOnly = BlockTypeOf(proto.BlkType_BlkS2B)
+	assert.Equal(t, proto.BlkType_BlkS2B, blkType)
+	assert.False(t, headerOnly)
+}
+
+func TestBlockHeader(t *testing.T) {
+	blk := &blockchain.ShardBlock{ValidationData: "sigs"}
+	blk.Header.Height = 10
+	blk.Body.Instructions = [][]string{{"1", "2"}}
+	header := BlockHeader(blk).(*blockchain.ShardBlock)
+	assert.Equal(t, blk.Hash(), header.Hash())
+	assert.Equal(t, "sigs", header.ValidationData)
+	assert.Empty(t, header.Body.Instructions)
+	assert.Len(t, blk.Body.Instructions, 1)
+
+	beaconBlk := &blockchain.BeaconBlock{ValidationData: "sigs"}
+	beaconBlk.Body.Instructions = [][]string{{"1"}}
+	beaconHeader := BlockHeader(beaconBlk).(*blockchain.BeaconBlock)
+	assert.Equal(t, beaconBlk.Hash(), beaconHeader.Hash())
+	assert.Empty(t, beaconHeader.Body.Instructions)
+}
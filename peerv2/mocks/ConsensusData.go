@@ -36,3 +36,24 @@ func (_m *ConsensusData) GetUserRole() (string, string, int) {
 
 	return r0, r1, r2
 }
+
+// GetCurrentMiningPublicKey provides a mock function with given fields:
+func (_m *ConsensusData) GetCurrentMiningPublicKey() (string, string) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func() string); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,143 @@
+package peerv2
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+	"github.com/pkg/errors"
+)
+
+// PeerTable keeps the nodes reachable without highway. It is saved in a file
+// so that a restarted node finds its direct peers even if the bootnode is down.
+type PeerTable struct {
+	path    string
+	entries map[string]*peerEntry // by libp2p address
+	sync.Mutex
+}
+
+type peerEntry struct {
+	rpcclient.DirectPeer
+	LastSeen time.Time
+}
+
+// NewPeerTable loads the table saved at path, a missing file gives an empty
+// table
+func NewPeerTable(path string) (*PeerTable, error) {
+	table := &PeerTable{
+		path:    path,
+		entries: map[string]*peerEntry{},
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return table, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	entries := []*peerEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, errors.Wrapf(err, "invalid peer table %v", path)
+	}
+	for _, entry := range entries {
+		table.entries[entry.Libp2pAddr] = entry
+	}
+	return table, nil
+}
+
+// Add saves new peers, the known ones keep their last seen time
+func (table *PeerTable) Add(peers ...rpcclient.DirectPeer) {
+	table.Lock()
+	defer table.Unlock()
+	for _, p := range peers {
+		if p.Libp2pAddr == "" {
+			continue
+		}
+		if entry, ok := table.entries[p.Libp2pAddr]; ok {
+			entry.CommitteeID = p.CommitteeID
+			continue
+		}
+		table.entries[p.Libp2pAddr] = &peerEntry{DirectPeer: p, LastSeen: time.Now()}
+	}
+}
+
+// Seen marks the peer of addr as connected now
+func (table *PeerTable) Seen(addr string) {
+	table.Lock()
+	defer table.Unlock()
+	if entry, ok := table.entries[addr]; ok {
+		entry.LastSeen = time.Now()
+	}
+}
+
+// Peers returns the peers, the last seen first
+func (table *PeerTable) Peers() []rpcclient.DirectPeer {
+	table.Lock()
+	defer table.Unlock()
+	entries := table.sortedEntries()
+	peers := make([]rpcclient.DirectPeer, 0, len(entries))
+	for _, entry := range entries {
+		peers = append(peers, entry.DirectPeer)
+	}
+	return peers
+}
+
+// Len returns the number of peers in the table
+func (table *PeerTable) Len() int {
+	table.Lock()
+	defer table.Unlock()
+	return len(table.entries)
+}
+
+// Save forgets the peers not seen for PeerTableExpiry, keeps at most
+// MaxPeerTableSize of the others and writes them to the file of the table
+func (table *PeerTable) Save() error {
+	table.Lock()
+	defer table.Unlock()
+	now := time.Now()
+	for addr, entry := range table.entries {
+		if now.Sub(entry.LastSeen) > PeerTableExpiry {
+			delete(table.entries, addr)
+		}
+	}
+	entries := table.sortedEntries()
+	if len(entries) > MaxPeerTableSize {
+		for _, entry := range entries[MaxPeerTableSize:] {
+			delete(table.entries, entry.Libp2pAddr)
+		}
+		entries = entries[:MaxPeerTableSize]
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(table.path), 0700); err != nil {
+		return errors.WithStack(err)
+	}
+	// Write then rename so that a crash never leaves half a table
+	tmp := table.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmp, table.path))
+}
+
+func (table *PeerTable) sortedEntries() []*peerEntry {
+	entries := make([]*peerEntry, 0, len(table.entries))
+	for _, entry := range table.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].LastSeen.Equal(entries[j].LastSeen) {
+			return entries[i].Libp2pAddr < entries[j].Libp2pAddr
+		}
+		return entries[i].LastSeen.After(entries[j].LastSeen)
+	})
+	return entries
+}
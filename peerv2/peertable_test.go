@@ -0,0 +1,65 @@
+package peerv2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerTableSaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peertable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "peers", "directpeers.json")
+
+	table, err := NewPeerTable(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, table.Len())
+
+	peerA := rpcclient.DirectPeer{Libp2pAddr: "/ip4/127.0.0.1/tcp/9433/p2p/QmSPa4gxx6PRmoNRu6P2iFwEwmayaoLdR5By3i3MgM9gMv", CommitteeID: []byte{0}}
+	peerB := rpcclient.DirectPeer{Libp2pAddr: "/ip4/127.0.0.1/tcp/9434/p2p/Qmba4kphPTHc3bxsgXJ6aT5SvNT2FoCXq8pe4vHs7kVSZm", CommitteeID: []byte{1}}
+	table.Add(peerA, peerB, rpcclient.DirectPeer{})
+	time.Sleep(time.Millisecond)
+	table.Seen(peerB.Libp2pAddr)
+	assert.Equal(t, []rpcclient.DirectPeer{peerB, peerA}, table.Peers())
+	assert.Nil(t, table.Save())
+
+	loaded, err := NewPeerTable(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []rpcclient.DirectPeer{peerB, peerA}, loaded.Peers())
+}
+
+func TestPeerTableExpiry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peertable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table, err := NewPeerTable(filepath.Join(dir, "directpeers.json"))
+	assert.Nil(t, err)
+	table.Add(rpcclient.DirectPeer{Libp2pAddr: "old"}, rpcclient.DirectPeer{Libp2pAddr: "new"})
+	table.entries["old"].LastSeen = time.Now().Add(-PeerTableExpiry - time.Minute)
+	assert.Nil(t, table.Save())
+	assert.Equal(t, []rpcclient.DirectPeer{{Libp2pAddr: "new"}}, table.Peers())
+}
+
+func TestPeerTableInvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peertable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "directpeers.json")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("{"), 0600))
+
+	_, err = NewPeerTable(path)
+	assert.NotNil(t, err)
+}
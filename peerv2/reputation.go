@@ -0,0 +1,207 @@
+package peerv2
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var (
+	bannedPeersGauge = metrics.NewRegisteredGauge("peerv2/reputation/banned", nil)
+	banCounter       = metrics.NewRegisteredCounter("peerv2/reputation/ban", nil)
+)
+
+// Behaviour is something a peer did, it moves the score of the peer
+type Behaviour int
+
+const (
+	UsefulData       Behaviour = iota // valid blocks synced from the peer
+	InvalidMessage                    // a message which cannot be decoded or dispatched
+	OversizedPayload                  // a message bigger than its maximum payload
+	InvalidBlock                      // a block failing validation
+	BadSignature                      // a block without a valid committee signature
+	FailedStream                      // a block stream which cannot be opened
+)
+
+var behaviourScores = map[Behaviour]int{
+	UsefulData:       1,
+	InvalidMessage:   -10,
+	OversizedPayload: -25,
+	InvalidBlock:     -20,
+	BadSignature:     -30,
+	FailedStream:     -5,
+}
+
+var behaviourNames = map[Behaviour]string{
+	UsefulData:       "useful data",
+	InvalidMessage:   "invalid message",
+	OversizedPayload: "oversized payload",
+	InvalidBlock:     "invalid block",
+	BadSignature:     "bad signature",
+	FailedStream:     "failed stream",
+}
+
+func (b Behaviour) String() string {
+	return behaviourNames[b]
+}
+
+// PeerReputation is the score of a peer, as listed by the getpeerreputations
+// RPC
+type PeerReputation struct {
+	PeerID        string
+	Score         int
+	Banned        bool
+	BannedUntil   int64 // unix time, 0 if not banned
+	LastBehaviour string
+	LastUpdate    int64 // unix time
+}
+
+type peerScore struct {
+	score         int
+	bannedUntil   time.Time
+	lastBehaviour Behaviour
+	lastUpdate    time.Time
+}
+
+// Reputation scores the peers by their behaviour. A peer whose score falls to
+// BanScore is banned for BanDuration: its messages are dropped and syncker
+// does not sync from it. The peer starts again from a zero score once the ban
+// is over. A nil Reputation bans no one.
+type Reputation struct {
+	scores map[string]*peerScore // by base58 peer id
+	sync.RWMutex
+}
+
+func NewReputation() *Reputation {
+	return &Reputation{scores: map[string]*peerScore{}}
+}
+
+// Report moves the score of peerID by the score of b, and bans the peer if
+// the score falls to BanScore
+func (r *Reputation) Report(peerID string, b Behaviour) {
+	if r == nil || peerID == "" {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	now := time.Now()
+	s, ok := r.scores[peerID]
+	if !ok {
+		r.evict()
+		s = &peerScore{}
+		r.scores[peerID] = s
+	}
+	if !s.bannedUntil.IsZero() {
+		if now.Before(s.bannedUntil) {
+			return // Already banned
+		}
+		s.score, s.bannedUntil = 0, time.Time{}
+	}
+	s.score += behaviourScores[b]
+	if s.score > MaxReputationScore {
+		s.score = MaxReputationScore
+	}
+	s.lastBehaviour, s.lastUpdate = b, now
+	if s.score <= BanScore {
+		s.bannedUntil = now.Add(BanDuration)
+		banCounter.Inc(1)
+		Logger.Warnf("Banning peer %v until %v, last behaviour: %v", peerID, s.bannedUntil.Format(time.RFC3339), b)
+	}
+	bannedPeersGauge.Update(int64(r.countBanned(now)))
+}
+
+// IsBanned returns true if peerID is banned now
+func (r *Reputation) IsBanned(peerID string) bool {
+	if r == nil {
+		return false
+	}
+	r.RLock()
+	defer r.RUnlock()
+	s, ok := r.scores[peerID]
+	return ok && time.Now().Before(s.bannedUntil)
+}
+
+// Scores returns the reputations of the known peers, the lowest scores first
+func (r *Reputation) Scores() []PeerReputation {
+	if r == nil {
+		return nil
+	}
+	r.RLock()
+	defer r.RUnlock()
+	now := time.Now()
+	res := make([]PeerReputation, 0, len(r.scores))
+	for peerID, s := range r.scores {
+		rep := PeerReputation{
+			PeerID:        peerID,
+			Score:         s.score,
+			LastBehaviour: s.lastBehaviour.String(),
+			LastUpdate:    s.lastUpdate.Unix(),
+		}
+		if now.Before(s.bannedUntil) {
+			rep.Banned = true
+			rep.BannedUntil = s.bannedUntil.Unix()
+		}
+		res = append(res, rep)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score == res[j].Score {
+			return res[i].PeerID < res[j].PeerID
+		}
+		return res[i].Score < res[j].Score
+	})
+	return res
+}
+
+// evict forgets the least recently updated peer not banned when the table is
+// full, so that peers with new ids cannot grow it without bound
+func (r *Reputation) evict() {
+	if len(r.scores) < MaxReputationPeers {
+		return
+	}
+	now := time.Now()
+	oldest := ""
+	for peerID, s := range r.scores {
+		if now.Before(s.bannedUntil) {
+			continue
+		}
+		if oldest == "" || s.lastUpdate.Before(r.scores[oldest].lastUpdate) {
+			oldest = peerID
+		}
+	}
+	delete(r.scores, oldest)
+}
+
+func (r *Reputation) countBanned(now time.Time) int {
+	banned := 0
+	for _, s := range r.scores {
+		if now.Before(s.bannedUntil) {
+			banned++
+		}
+	}
+	return banned
+}
+
+// reputationBlacklist makes pubsub drop the messages of banned peers, so
+// that they are not forwarded to the mesh either
+type reputationBlacklist struct {
+	rep *Reputation
+}
+
+func (b reputationBlacklist) Add(pid peer.ID) {
+	b.rep.Lock()
+	defer b.rep.Unlock()
+	s, ok := b.rep.scores[pid.Pretty()]
+	if !ok {
+		b.rep.evict()
+		s = &peerScore{}
+		b.rep.scores[pid.Pretty()] = s
+	}
+	s.bannedUntil = time.Now().Add(BanDuration)
+}
+
+func (b reputationBlacklist) Contains(pid peer.ID) bool {
+	return b.rep.IsBanned(pid.Pretty())
+}
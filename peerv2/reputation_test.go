@@ -0,0 +1,125 @@
+package peerv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/peer"
+	"github.com/incognitochain/incognito-chain/wire"
+	libp2p "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReputationBan(t *testing.T) {
+	rep := NewReputation()
+	for i := 0; i < 3; i++ {
+		rep.Report("good", UsefulData)
+	}
+	for i := 0; i < 3; i++ {
+		rep.Report("bad", BadSignature)
+	}
+	assert.False(t, rep.IsBanned("bad"))
+	rep.Report("bad", InvalidBlock)
+	assert.True(t, rep.IsBanned("bad"))
+	assert.False(t, rep.IsBanned("good"))
+	assert.False(t, rep.IsBanned("unknown"))
+
+	// a banned peer cannot make up for its behaviour
+	rep.Report("bad", UsefulData)
+	scores := rep.Scores()
+	if assert.Len(t, scores, 2) {
+		assert.Equal(t, "bad", scores[0].PeerID)
+		assert.Equal(t, -110, scores[0].Score)
+		assert.True(t, scores[0].Banned)
+		assert.Equal(t, InvalidBlock.String(), scores[0].LastBehaviour)
+		assert.Equal(t, PeerReputation{PeerID: "good", Score: 3, LastBehaviour: "useful data", LastUpdate: scores[1].LastUpdate}, scores[1])
+	}
+}
+
+func TestReputationMaxScore(t *testing.T) {
+	rep := NewReputation()
+	for i := 0; i < MaxReputationScore+10; i++ {
+		rep.Report("peer", UsefulData)
+	}
+	assert.Equal(t, MaxReputationScore, rep.Scores()[0].Score)
+}
+
+func TestReputationBanExpiry(t *testing.T) {
+	banDuration := BanDuration
+	BanDuration = 50 * time.Millisecond
+	defer func() { BanDuration = banDuration }()
+
+	rep := NewReputation()
+	for !rep.IsBanned("peer") {
+		rep.Report("peer", OversizedPayload)
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, rep.IsBanned("peer"))
+
+	// the peer starts again from a zero score
+	rep.Report("peer", FailedStream)
+	assert.Equal(t, -5, rep.Scores()[0].Score)
+}
+
+func TestReputationEvict(t *testing.T) {
+	maxPeers := MaxReputationPeers
+	MaxReputationPeers = 2
+	defer func() { MaxReputationPeers = maxPeers }()
+
+	rep := NewReputation()
+	for !rep.IsBanned("banned") {
+		rep.Report("banned", BadSignature)
+	}
+	rep.Report("old", UsefulData)
+	rep.Report("new", UsefulData)
+	assert.True(t, rep.IsBanned("banned"))
+	peers := []string{}
+	for _, s := range rep.Scores() {
+		peers = append(peers, s.PeerID)
+	}
+	assert.Equal(t, []string{"banned", "new"}, peers)
+}
+
+func TestNilReputation(t *testing.T) {
+	var rep *Reputation
+	rep.Report("peer", BadSignature)
+	assert.False(t, rep.IsBanned("peer"))
+	assert.Nil(t, rep.Scores())
+}
+
+func TestReputationBlacklist(t *testing.T) {
+	rep := NewReputation()
+	blacklist := reputationBlacklist{rep: rep}
+	pid := libp2p.ID("peer")
+	assert.False(t, blacklist.Contains(pid))
+	blacklist.Add(pid)
+	assert.True(t, blacklist.Contains(pid))
+	assert.True(t, rep.IsBanned(pid.Pretty()))
+}
+
+func TestDispatcherReputation(t *testing.T) {
+	received := []libp2p.ID{}
+	disp := &Dispatcher{
+		Reputation: NewReputation(),
+		MessageListeners: &MessageListeners{
+			OnBFTMsg: func(p *peer.PeerConn, msg wire.Message) {
+				received = append(received, p.GetRemotePeerID())
+			},
+		},
+	}
+	msgStr, err := encodeMessage(&wire.MessageBFT{ChainKey: "beacon"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	good, bad := libp2p.ID("good"), libp2p.ID("bad")
+
+	assert.Nil(t, disp.processInMessage(good, msgStr))
+	assert.Equal(t, []libp2p.ID{good}, received)
+
+	for !disp.Reputation.IsBanned(bad.Pretty()) {
+		assert.NotNil(t, disp.processInMessage(bad, "not hex"))
+	}
+	assert.Nil(t, disp.processInMessage(bad, msgStr))
+	assert.Equal(t, []libp2p.ID{good}, received)
+	assert.Equal(t, InvalidMessage.String(), disp.Reputation.Scores()[0].LastBehaviour)
+}
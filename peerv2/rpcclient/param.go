@@ -12,3 +12,9 @@ type Response struct {
 type Request struct {
 	Shard []string
 }
+
+// DirectPeer is a node reachable without highway, announced to the bootnode
+type DirectPeer struct {
+	Libp2pAddr  string // multiaddress ending with /p2p/<peer id>
+	CommitteeID []byte // committees the node keeps the blocks of
+}
@@ -40,3 +40,29 @@ func (rpcClient *RPCClient) DiscoverHighway(
 	// Logger.Infof("Bootnode return %v", res.PeerPerShard)
 	return res.PeerPerShard, nil
 }
+
+// AnnounceDirectPeer saves self in the bootnode and returns the other direct
+// peers it knows
+func (rpcClient *RPCClient) AnnounceDirectPeer(
+	bootnodeAddress string,
+	self DirectPeer,
+) (
+	[]DirectPeer,
+	error,
+) {
+	if bootnodeAddress == "" {
+		return nil, errors.Errorf("empty address")
+	}
+	client, err := rpc.Dial("tcp", bootnodeAddress)
+	if err != nil {
+		return nil, errors.Errorf("Connect to bootnode %v return error %v:", bootnodeAddress, err)
+	}
+	defer client.Close()
+
+	var res []DirectPeer
+	err = client.Call("Handler.AnnounceDirectPeer", self, &res)
+	if err != nil {
+		return nil, errors.Errorf("Call Handler.AnnounceDirectPeer return error %v", err)
+	}
+	return res, nil
+}
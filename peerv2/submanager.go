@@ -2,6 +2,7 @@ package peerv2
 
 import (
 	"context"
+	"sync"
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/peerv2/proto"
@@ -24,9 +25,10 @@ type SubManager struct {
 	registerer Registerer
 	subscriber Subscriber
 
-	role   userRole
-	topics msgToTopics
-	subs   msgToTopics // mapping from message to topic's subscription
+	role     userRole
+	topics   msgToTopics
+	subs     msgToTopics // mapping from message to topic's subscription
+	subsLock sync.RWMutex
 }
 
 type info struct {
@@ -65,8 +67,16 @@ func NewSubManager(
 	}
 }
 
+// GetMsgToTopics returns a copy of the subscriptions, they change when the
+// role changes
 func (sub *SubManager) GetMsgToTopics() msgToTopics {
-	return sub.subs // no need to make a copy since topics rarely changed (when role changed)
+	sub.subsLock.RLock()
+	defer sub.subsLock.RUnlock()
+	subs := msgToTopics{}
+	for m, topics := range sub.subs {
+		subs[m] = append([]Topic{}, topics...)
+	}
+	return subs
 }
 
 // Subscribe registers to proxy and save the list of new topics if needed
@@ -159,6 +169,9 @@ func (sub *SubManager) subscribeNewTopics(newTopics, subscribed msgToTopics, for
 		return false
 	}
 
+	sub.subsLock.Lock()
+	defer sub.subsLock.Unlock()
+
 	// Unsubscribe to old ones
 	for m, topicList := range subscribed {
 		for _, t := range topicList {
@@ -81,6 +81,7 @@ func TestSubscribeRoleChanged(t *testing.T) {
 	registerer.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pairs, &proto.UserRole{}, err)
 	consensusData := &mocks.ConsensusData{}
 	consensusData.On("GetUserRole").Once().Return(common.ShardRole, common.PendingRole, 1)
+	consensusData.On("GetCurrentMiningPublicKey").Return("pubkey", common.BlsConsensus)
 	sub := &SubManager{
 		info: info{
 			consensusData: consensusData,
@@ -107,6 +108,7 @@ func TestSubscribeForced(t *testing.T) {
 	registerer.On("Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pairs, &proto.UserRole{}, err)
 	consensusData := &mocks.ConsensusData{}
 	consensusData.On("GetUserRole").Return(role.layer, role.role, role.shardID)
+	consensusData.On("GetCurrentMiningPublicKey").Return("pubkey", common.BlsConsensus)
 	sub := &SubManager{
 		info: info{
 			consensusData: consensusData,
@@ -0,0 +1,67 @@
+package peerv2
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/wire"
+)
+
+// TopicName names the topic of msg for the committee cID, the committee is
+// read back by GetCommitteeIDOfTopic
+func TopicName(msg string, cID byte) string {
+	return fmt.Sprintf("%s-%d", msg, cID)
+}
+
+func containsCommittee(committeeIDs []byte, cID byte) bool {
+	for _, id := range committeeIDs {
+		if id == cID {
+			return true
+		}
+	}
+	return false
+}
+
+// TopicPairs returns the topics of the wanted messages of a node of the
+// committees committeeIDs, HighwayBeaconID being the beacon committee. Highways
+// answer Register with them and nodes in direct mode subscribe to them:
+//   - beacon blocks and peer states go through one topic every node reads and writes
+//   - shard to beacon blocks are written by the shards and read by the beacon
+//   - bft messages and shard blocks stay in the committees of the node
+//   - txs and cross shard blocks are read by their shard and written by anyone
+func TopicPairs(wanted []string, committeeIDs []byte, numShards int) []*proto.MessageTopicPair {
+	pairs := []*proto.MessageTopicPair{}
+	for _, msg := range wanted {
+		pair := &proto.MessageTopicPair{Message: msg}
+		add := func(cID byte, act proto.MessageTopicPair_Action) {
+			pair.Topic = append(pair.Topic, TopicName(msg, cID))
+			pair.Act = append(pair.Act, act)
+		}
+		switch msg {
+		case wire.CmdBlockBeacon, wire.CmdPeerState:
+			add(HighwayBeaconID, proto.MessageTopicPair_PUBSUB)
+		case wire.CmdBlkShardToBeacon:
+			if containsCommittee(committeeIDs, HighwayBeaconID) {
+				add(HighwayBeaconID, proto.MessageTopicPair_SUB)
+			} else {
+				add(HighwayBeaconID, proto.MessageTopicPair_PUB)
+			}
+		case wire.CmdBFT, wire.CmdBlockShard:
+			for _, cID := range committeeIDs {
+				add(cID, proto.MessageTopicPair_PUBSUB)
+			}
+		case wire.CmdTx, wire.CmdPrivacyCustomToken, wire.CmdCrossShard:
+			for shardID := 0; shardID < numShards; shardID++ {
+				if containsCommittee(committeeIDs, byte(shardID)) {
+					add(byte(shardID), proto.MessageTopicPair_PUBSUB)
+				} else {
+					add(byte(shardID), proto.MessageTopicPair_PUB)
+				}
+			}
+		default:
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
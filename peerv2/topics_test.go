@@ -0,0 +1,29 @@
+package peerv2
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicPairs(t *testing.T) {
+	beacon := HighwayBeaconID
+	pairs := TopicPairs([]string{wire.CmdBFT, wire.CmdBlkShardToBeacon, wire.CmdTx, wire.CmdBlockBeacon}, []byte{1}, 2)
+	assert.Equal(t, []*proto.MessageTopicPair{
+		{Message: wire.CmdBFT, Topic: []string{"bft-1"}, Act: []proto.MessageTopicPair_Action{proto.MessageTopicPair_PUBSUB}},
+		{Message: wire.CmdBlkShardToBeacon, Topic: []string{"blkshdtobcn-255"}, Act: []proto.MessageTopicPair_Action{proto.MessageTopicPair_PUB}},
+		{Message: wire.CmdTx, Topic: []string{"tx-0", "tx-1"}, Act: []proto.MessageTopicPair_Action{proto.MessageTopicPair_PUB, proto.MessageTopicPair_PUBSUB}},
+		{Message: wire.CmdBlockBeacon, Topic: []string{"blockbeacon-255"}, Act: []proto.MessageTopicPair_Action{proto.MessageTopicPair_PUBSUB}},
+	}, pairs)
+	for _, pair := range pairs {
+		for _, topic := range pair.Topic {
+			assert.NotEqual(t, -1, GetCommitteeIDOfTopic(topic))
+		}
+	}
+
+	pairs = TopicPairs([]string{wire.CmdBlkShardToBeacon, wire.CmdBFT}, []byte{beacon}, 2)
+	assert.Equal(t, []proto.MessageTopicPair_Action{proto.MessageTopicPair_SUB}, pairs[0].Act)
+	assert.Equal(t, []string{"bft-255"}, pairs[1].Topic)
+}
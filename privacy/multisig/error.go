@@ -0,0 +1,56 @@
+package multisig
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	UnExpectedError = iota
+	InvalidThresholdErr
+	InvalidParticipantErr
+	InvalidSessionErr
+	InvalidRoundErr
+	DuplicatedMessageErr
+	InvalidMessageErr
+	InvalidShareErr
+	InvalidPartialSignatureErr
+	DecryptShareErr
+	PaillierErr
+)
+
+var ErrCodeMessage = map[int]struct {
+	Code    int
+	Message string
+}{
+	UnExpectedError:            {-1300, "Unexpected error"},
+	InvalidThresholdErr:        {-1301, "Threshold is invalid"},
+	InvalidParticipantErr:      {-1302, "Participant is not in the session"},
+	InvalidSessionErr:          {-1303, "Session is invalid"},
+	InvalidRoundErr:            {-1304, "Message is not for the current round"},
+	DuplicatedMessageErr:       {-1305, "Participant already sent its message for the round"},
+	InvalidMessageErr:          {-1306, "Message is invalid"},
+	InvalidShareErr:            {-1307, "Key share does not match the commitments of its dealer"},
+	InvalidPartialSignatureErr: {-1308, "Partial signature is invalid"},
+	DecryptShareErr:            {-1309, "Can not decrypt key share"},
+	PaillierErr:                {-1310, "Paillier error"},
+}
+
+type MultiSigError struct {
+	Code    int
+	Message string
+	err     error
+}
+
+func (e MultiSigError) Error() string {
+	return fmt.Sprintf("%d: %s \n %+v", e.Code, e.Message, e.err)
+}
+
+func NewMultiSigError(key int, err error) error {
+	return &MultiSigError{
+		Code:    ErrCodeMessage[key].Code,
+		Message: ErrCodeMessage[key].Message,
+		err:     errors.Wrap(err, ErrCodeMessage[key].Message),
+	}
+}
@@ -0,0 +1,406 @@
+package multisig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/privacy"
+)
+
+// GroupKey is the public part of a k-of-n group key. PublicKey and
+// TransmissionKey make an ordinary payment address, PublicShares[i-1] is the
+// public key of the share of participant i
+type GroupKey struct {
+	Threshold       int
+	PublicKey       []byte
+	TransmissionKey []byte
+	PublicShares    [][]byte
+}
+
+// KeyShare is the secret part of a participant of a group key. The receiving
+// key of the group is known to every participant, it only reveals the coins
+// of the group and can not spend them
+type KeyShare struct {
+	GroupKey
+	Index        uint32
+	SecretShare  []byte
+	ReceivingKey []byte
+}
+
+// PaymentAddress returns the payment address of the group
+func (group GroupKey) PaymentAddress() privacy.PaymentAddress {
+	return privacy.PaymentAddress{
+		Pk: group.PublicKey,
+		Tk: group.TransmissionKey,
+	}
+}
+
+// ShardID returns the shard of the payment address of the group
+func (group GroupKey) ShardID() byte {
+	return common.GetShardIDFromLastByte(group.PublicKey[len(group.PublicKey)-1])
+}
+
+func (group GroupKey) publicShare(index uint32) (*privacy.Point, error) {
+	if index == 0 || int(index) > len(group.PublicShares) {
+		return nil, NewMultiSigError(InvalidParticipantErr, fmt.Errorf("participant %d", index))
+	}
+	return new(privacy.Point).FromBytesS(group.PublicShares[index-1])
+}
+
+func (group GroupKey) validate() error {
+	if group.Threshold < 1 || group.Threshold > len(group.PublicShares) {
+		return NewMultiSigError(InvalidThresholdErr, fmt.Errorf("threshold %d of %d participants", group.Threshold, len(group.PublicShares)))
+	}
+	if _, err := new(privacy.Point).FromBytesS(group.PublicKey); err != nil {
+		return NewMultiSigError(InvalidSessionErr, err)
+	}
+	if _, err := new(privacy.Point).FromBytesS(group.TransmissionKey); err != nil {
+		return NewMultiSigError(InvalidSessionErr, err)
+	}
+	for _, publicShare := range group.PublicShares {
+		if _, err := new(privacy.Point).FromBytesS(publicShare); err != nil {
+			return NewMultiSigError(InvalidSessionErr, err)
+		}
+	}
+	return nil
+}
+
+// ReadonlyKey returns the viewing key of the group
+func (share KeyShare) ReadonlyKey() privacy.ViewingKey {
+	return privacy.ViewingKey{
+		Pk: share.PublicKey,
+		Rk: share.ReceivingKey,
+	}
+}
+
+// KeyGenSession is the public transcript of the distributed generation of a
+// group key. The coordinator creates it from the transmission keys of the
+// participants, participant i is the owner of TransmissionKeys[i-1] and gets
+// its shares encrypted with it
+type KeyGenSession struct {
+	ID               string
+	Threshold        int
+	TransmissionKeys [][]byte
+	Round1           []*KeyGenRound1
+}
+
+// KeyGenRound1 is the message of a participant: Feldman commitments to the
+// coefficients of its polynomial with a proof of possession of the constant
+// term, the commitment to its part of the receiving key of the group, and the
+// shares of the other participants encrypted with their transmission keys
+type KeyGenRound1 struct {
+	SessionID         string
+	Index             uint32
+	Commitments       [][]byte
+	ViewCommitment    []byte
+	ProofOfPossession []byte
+	Shares            [][]byte
+}
+
+// KeyGenSecret is the state a participant keeps between its round 1 message
+// and the end of the key generation
+type KeyGenSecret struct {
+	SessionID    string
+	Index        uint32
+	Coefficients [][]byte
+	View         []byte
+}
+
+// NewKeyGenSession returns the session of a threshold-of-n group key where n
+// is the number of transmission keys
+func NewKeyGenSession(threshold int, transmissionKeys [][]byte) (*KeyGenSession, error) {
+	if len(transmissionKeys) < 2 || threshold < 1 || threshold > len(transmissionKeys) {
+		return nil, NewMultiSigError(InvalidThresholdErr, fmt.Errorf("threshold %d of %d participants", threshold, len(transmissionKeys)))
+	}
+	for i, transmissionKey := range transmissionKeys {
+		if _, err := new(privacy.Point).FromBytesS(transmissionKey); err != nil {
+			return nil, NewMultiSigError(InvalidParticipantErr, err)
+		}
+		for _, other := range transmissionKeys[:i] {
+			if bytes.Equal(other, transmissionKey) {
+				return nil, NewMultiSigError(InvalidParticipantErr, errors.New("duplicated transmission key"))
+			}
+		}
+	}
+	id, err := newSessionID()
+	if err != nil {
+		return nil, NewMultiSigError(UnExpectedError, err)
+	}
+	return &KeyGenSession{
+		ID:               id,
+		Threshold:        threshold,
+		TransmissionKeys: transmissionKeys,
+		Round1:           make([]*KeyGenRound1, len(transmissionKeys)),
+	}, nil
+}
+
+func newSessionID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// IndexOf returns the index of the participant owning transmissionKey, 0 if
+// it is not in the session
+func (session KeyGenSession) IndexOf(transmissionKey []byte) uint32 {
+	for i, key := range session.TransmissionKeys {
+		if bytes.Equal(key, transmissionKey) {
+			return uint32(i + 1)
+		}
+	}
+	return 0
+}
+
+// IsComplete returns whether every participant sent its round 1 message
+func (session KeyGenSession) IsComplete() bool {
+	for _, msg := range session.Round1 {
+		if msg == nil {
+			return false
+		}
+	}
+	return len(session.Round1) > 0
+}
+
+func (session KeyGenSession) possessionHash(msg *KeyGenRound1) []byte {
+	data := append([]byte(session.ID), common.Uint32ToBytes(msg.Index)...)
+	for _, commitment := range msg.Commitments {
+		data = append(data, commitment...)
+	}
+	data = append(data, msg.ViewCommitment...)
+	hash := common.HashH(data)
+	return hash[:]
+}
+
+// NewRound1 returns the round 1 message of participant index and the secret it
+// has to keep until FinishKeyGen
+func (session KeyGenSession) NewRound1(index uint32) (*KeyGenSecret, *KeyGenRound1, error) {
+	if index == 0 || int(index) > len(session.TransmissionKeys) {
+		return nil, nil, NewMultiSigError(InvalidParticipantErr, fmt.Errorf("participant %d", index))
+	}
+	coefficients := make([]*privacy.Scalar, session.Threshold)
+	secret := &KeyGenSecret{
+		SessionID:    session.ID,
+		Index:        index,
+		Coefficients: make([][]byte, session.Threshold),
+	}
+	msg := &KeyGenRound1{
+		SessionID:   session.ID,
+		Index:       index,
+		Commitments: make([][]byte, session.Threshold),
+		Shares:      make([][]byte, len(session.TransmissionKeys)),
+	}
+	for i := range coefficients {
+		coefficients[i] = privacy.RandomScalar()
+		secret.Coefficients[i] = coefficients[i].ToBytesS()
+		msg.Commitments[i] = new(privacy.Point).ScalarMultBase(coefficients[i]).ToBytesS()
+	}
+	view := privacy.RandomScalar()
+	secret.View = view.ToBytesS()
+	msg.ViewCommitment = new(privacy.Point).ScalarMultBase(view).ToBytesS()
+
+	possessionKey := new(privacy.SchnorrPrivateKey)
+	possessionKey.Set(coefficients[0], new(privacy.Scalar).FromUint64(0))
+	proof, err := possessionKey.Sign(session.possessionHash(msg))
+	if err != nil {
+		return nil, nil, NewMultiSigError(UnExpectedError, err)
+	}
+	msg.ProofOfPossession = proof.Bytes()
+
+	for i, transmissionKey := range session.TransmissionKeys {
+		receiver := uint32(i + 1)
+		if receiver == index {
+			continue
+		}
+		point, err := new(privacy.Point).FromBytesS(transmissionKey)
+		if err != nil {
+			return nil, nil, NewMultiSigError(InvalidParticipantErr, err)
+		}
+		share := evaluatePolynomial(coefficients, receiver)
+		cipherText, err := privacy.HybridEncrypt(append(share.ToBytesS(), secret.View...), point)
+		if err != nil {
+			return nil, nil, NewMultiSigError(UnExpectedError, err)
+		}
+		msg.Shares[i] = cipherText.Bytes()
+	}
+	return secret, msg, nil
+}
+
+// AddRound1 adds the round 1 message of a participant to the session after
+// checking its proof of possession
+func (session *KeyGenSession) AddRound1(msg *KeyGenRound1) error {
+	if msg.SessionID != session.ID {
+		return NewMultiSigError(InvalidSessionErr, fmt.Errorf("message of session %s", msg.SessionID))
+	}
+	if msg.Index == 0 || int(msg.Index) > len(session.Round1) {
+		return NewMultiSigError(InvalidParticipantErr, fmt.Errorf("participant %d", msg.Index))
+	}
+	if session.Round1[msg.Index-1] != nil {
+		return NewMultiSigError(DuplicatedMessageErr, fmt.Errorf("participant %d", msg.Index))
+	}
+	if len(msg.Commitments) != session.Threshold || len(msg.Shares) != len(session.TransmissionKeys) {
+		return NewMultiSigError(InvalidMessageErr, fmt.Errorf("participant %d sent %d commitments and %d shares", msg.Index, len(msg.Commitments), len(msg.Shares)))
+	}
+	for _, commitment := range msg.Commitments {
+		if _, err := new(privacy.Point).FromBytesS(commitment); err != nil {
+			return NewMultiSigError(InvalidMessageErr, err)
+		}
+	}
+	if _, err := new(privacy.Point).FromBytesS(msg.ViewCommitment); err != nil {
+		return NewMultiSigError(InvalidMessageErr, err)
+	}
+	constantTerm, _ := new(privacy.Point).FromBytesS(msg.Commitments[0])
+	verifyKey := new(privacy.SchnorrPublicKey)
+	verifyKey.Set(constantTerm)
+	proof := new(privacy.SchnSignature)
+	if err := proof.SetBytes(msg.ProofOfPossession); err != nil || len(msg.ProofOfPossession) != 2*privacy.Ed25519KeySize {
+		return NewMultiSigError(InvalidMessageErr, errors.New("invalid proof of possession"))
+	}
+	if !verifyKey.Verify(proof, session.possessionHash(msg)) {
+		return NewMultiSigError(InvalidMessageErr, fmt.Errorf("participant %d does not own its constant term", msg.Index))
+	}
+	session.Round1[msg.Index-1] = msg
+	return nil
+}
+
+// GroupKey returns the group key once every participant sent its round 1
+// message
+func (session KeyGenSession) GroupKey() (*GroupKey, error) {
+	if !session.IsComplete() {
+		return nil, NewMultiSigError(InvalidRoundErr, errors.New("key generation is not complete"))
+	}
+	publicKey := new(privacy.Point).Identity()
+	transmissionKey := new(privacy.Point).Identity()
+	for _, msg := range session.Round1 {
+		constantTerm, err := new(privacy.Point).FromBytesS(msg.Commitments[0])
+		if err != nil {
+			return nil, NewMultiSigError(InvalidMessageErr, err)
+		}
+		publicKey.Add(publicKey, constantTerm)
+		viewCommitment, err := new(privacy.Point).FromBytesS(msg.ViewCommitment)
+		if err != nil {
+			return nil, NewMultiSigError(InvalidMessageErr, err)
+		}
+		transmissionKey.Add(transmissionKey, viewCommitment)
+	}
+	group := &GroupKey{
+		Threshold:       session.Threshold,
+		PublicKey:       publicKey.ToBytesS(),
+		TransmissionKey: transmissionKey.ToBytesS(),
+		PublicShares:    make([][]byte, len(session.Round1)),
+	}
+	for i := range session.Round1 {
+		publicShare := new(privacy.Point).Identity()
+		for _, msg := range session.Round1 {
+			commitment, err := evaluateCommitments(msg.Commitments, uint32(i+1))
+			if err != nil {
+				return nil, NewMultiSigError(InvalidMessageErr, err)
+			}
+			publicShare.Add(publicShare, commitment)
+		}
+		group.PublicShares[i] = publicShare.ToBytesS()
+	}
+	return group, nil
+}
+
+// FinishKeyGen decrypts the shares sent to the participant of secret with its
+// receiving key, checks them against the commitments of their dealers and
+// returns the key share of the participant
+func (session KeyGenSession) FinishKeyGen(secret *KeyGenSecret, receivingKey []byte) (*KeyShare, error) {
+	if secret.SessionID != session.ID {
+		return nil, NewMultiSigError(InvalidSessionErr, fmt.Errorf("secret of session %s", secret.SessionID))
+	}
+	group, err := session.GroupKey()
+	if err != nil {
+		return nil, err
+	}
+	coefficients := make([]*privacy.Scalar, len(secret.Coefficients))
+	for i, coefficient := range secret.Coefficients {
+		coefficients[i] = new(privacy.Scalar).FromBytesS(coefficient)
+	}
+	secretShare := evaluatePolynomial(coefficients, secret.Index)
+	view := new(privacy.Scalar).FromBytesS(secret.View)
+	rk := new(privacy.Scalar).FromBytesS(receivingKey)
+	for _, msg := range session.Round1 {
+		if msg.Index == secret.Index {
+			continue
+		}
+		cipherText := new(privacy.HybridCipherText)
+		if err := cipherText.SetBytes(msg.Shares[secret.Index-1]); err != nil {
+			return nil, NewMultiSigError(DecryptShareErr, err)
+		}
+		plainText, err := privacy.HybridDecrypt(cipherText, rk)
+		if err != nil || len(plainText) != 2*privacy.Ed25519KeySize {
+			return nil, NewMultiSigError(DecryptShareErr, fmt.Errorf("share of participant %d", msg.Index))
+		}
+		share := new(privacy.Scalar).FromBytesS(plainText[:privacy.Ed25519KeySize])
+		expected, err := evaluateCommitments(msg.Commitments, secret.Index)
+		if err != nil || !privacy.IsPointEqual(new(privacy.Point).ScalarMultBase(share), expected) {
+			return nil, NewMultiSigError(InvalidShareErr, fmt.Errorf("share of participant %d", msg.Index))
+		}
+		dealerView := new(privacy.Scalar).FromBytesS(plainText[privacy.Ed25519KeySize:])
+		if !bytes.Equal(new(privacy.Point).ScalarMultBase(dealerView).ToBytesS(), msg.ViewCommitment) {
+			return nil, NewMultiSigError(InvalidShareErr, fmt.Errorf("view key part of participant %d", msg.Index))
+		}
+		secretShare.Add(secretShare, share)
+		view.Add(view, dealerView)
+	}
+	if !bytes.Equal(new(privacy.Point).ScalarMultBase(secretShare).ToBytesS(), group.PublicShares[secret.Index-1]) {
+		return nil, NewMultiSigError(InvalidShareErr, errors.New("share does not match the group key"))
+	}
+	return &KeyShare{
+		GroupKey:     *group,
+		Index:        secret.Index,
+		SecretShare:  secretShare.ToBytesS(),
+		ReceivingKey: view.ToBytesS(),
+	}, nil
+}
+
+// evaluatePolynomial returns the sum of coefficients[i] * x^i
+func evaluatePolynomial(coefficients []*privacy.Scalar, x uint32) *privacy.Scalar {
+	xScalar := new(privacy.Scalar).FromUint64(uint64(x))
+	result := new(privacy.Scalar).FromUint64(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result.Mul(result, xScalar)
+		result.Add(result, coefficients[i])
+	}
+	return result
+}
+
+// evaluateCommitments returns the sum of commitments[i] * x^i, the public key
+// of the value at x of the committed polynomial
+func evaluateCommitments(commitments [][]byte, x uint32) (*privacy.Point, error) {
+	xScalar := new(privacy.Scalar).FromUint64(uint64(x))
+	power := new(privacy.Scalar).FromUint64(1)
+	result := new(privacy.Point).Identity()
+	for _, commitment := range commitments {
+		point, err := new(privacy.Point).FromBytesS(commitment)
+		if err != nil {
+			return nil, err
+		}
+		result.Add(result, new(privacy.Point).ScalarMult(point, power))
+		power = new(privacy.Scalar).Mul(power, xScalar)
+	}
+	return result, nil
+}
+
+// lagrangeCoefficient returns the coefficient of the share of index in the
+// interpolation at 0 of the shares of signers
+func lagrangeCoefficient(index uint32, signers []uint32) *privacy.Scalar {
+	numerator := new(privacy.Scalar).FromUint64(1)
+	denominator := new(privacy.Scalar).FromUint64(1)
+	for _, signer := range signers {
+		if signer == index {
+			continue
+		}
+		j := new(privacy.Scalar).FromUint64(uint64(signer))
+		numerator.Mul(numerator, j)
+		denominator.Mul(denominator, new(privacy.Scalar).Sub(j, new(privacy.Scalar).FromUint64(uint64(index))))
+	}
+	return new(privacy.Scalar).Mul(numerator, new(privacy.Scalar).Invert(denominator))
+}
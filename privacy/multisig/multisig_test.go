@@ -0,0 +1,183 @@
+package multisig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/stretchr/testify/assert"
+)
+
+// jsonCopy sends v through an exchange file
+func jsonCopy(t *testing.T, v interface{}, out interface{}) {
+	data, err := json.Marshal(v)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, json.Unmarshal(data, out))
+}
+
+func newKeyShares(t *testing.T, threshold int, n int) []*KeyShare {
+	receivingKeys := make([][]byte, n)
+	transmissionKeys := make([][]byte, n)
+	for i := range receivingKeys {
+		receivingKeys[i] = privacy.GenerateReceivingKey(privacy.GeneratePrivateKey(privacy.RandomScalar().ToBytesS()))
+		transmissionKeys[i] = privacy.GenerateTransmissionKey(receivingKeys[i])
+	}
+	session, err := NewKeyGenSession(threshold, transmissionKeys)
+	assert.Equal(t, nil, err)
+
+	secrets := make([]*KeyGenSecret, n)
+	for i := range secrets {
+		var msg *KeyGenRound1
+		secrets[i], msg, err = session.NewRound1(uint32(i + 1))
+		assert.Equal(t, nil, err)
+		received := new(KeyGenRound1)
+		jsonCopy(t, msg, received)
+		assert.Equal(t, nil, session.AddRound1(received))
+		assert.NotEqual(t, nil, session.AddRound1(received))
+	}
+	assert.Equal(t, true, session.IsComplete())
+
+	shares := make([]*KeyShare, n)
+	for i := range shares {
+		shares[i], err = session.FinishKeyGen(secrets[i], receivingKeys[i])
+		assert.Equal(t, nil, err)
+	}
+	return shares
+}
+
+func TestKeyGen(t *testing.T) {
+	shares := newKeyShares(t, 2, 3)
+	group := shares[0].GroupKey
+	for _, share := range shares[1:] {
+		assert.Equal(t, group, share.GroupKey)
+		assert.Equal(t, shares[0].ReceivingKey, share.ReceivingKey)
+	}
+	assert.Equal(t, group.TransmissionKey, []byte(privacy.GenerateTransmissionKey(shares[0].ReceivingKey)))
+
+	// any 2 shares interpolate to the private key of the group
+	for _, signers := range [][]uint32{{1, 2}, {1, 3}, {2, 3}} {
+		privateKey := new(privacy.Scalar).FromUint64(0)
+		for _, signer := range signers {
+			term := new(privacy.Scalar).Mul(lagrangeCoefficient(signer, signers), new(privacy.Scalar).FromBytesS(shares[signer-1].SecretShare))
+			privateKey.Add(privateKey, term)
+		}
+		assert.Equal(t, group.PublicKey, new(privacy.Point).ScalarMultBase(privateKey).ToBytesS())
+	}
+}
+
+func TestKeyGenRejectsBadShare(t *testing.T) {
+	receivingKeys := make([][]byte, 2)
+	transmissionKeys := make([][]byte, 2)
+	for i := range receivingKeys {
+		receivingKeys[i] = privacy.GenerateReceivingKey(privacy.GeneratePrivateKey(privacy.RandomScalar().ToBytesS()))
+		transmissionKeys[i] = privacy.GenerateTransmissionKey(receivingKeys[i])
+	}
+	session, err := NewKeyGenSession(2, transmissionKeys)
+	assert.Equal(t, nil, err)
+	secret1, msg1, err := session.NewRound1(1)
+	assert.Equal(t, nil, err)
+	_, msg2, err := session.NewRound1(2)
+	assert.Equal(t, nil, err)
+
+	// participant 2 sends participant 1 a share of another polynomial
+	_, other, err := session.NewRound1(2)
+	assert.Equal(t, nil, err)
+	msg2.Shares[0] = other.Shares[0]
+	assert.Equal(t, nil, session.AddRound1(msg1))
+	assert.Equal(t, nil, session.AddRound1(msg2))
+	_, err = session.FinishKeyGen(secret1, receivingKeys[0])
+	assert.NotEqual(t, nil, err)
+}
+
+// runSigning runs the rounds of session with the shares of its signers and
+// exchanges every message through JSON
+func runSigning(t *testing.T, session *SigningSession, shares []*KeyShare, messages [][]byte) {
+	states := make([]*SignerState, len(shares))
+	for i := range states {
+		states[i] = new(SignerState)
+	}
+	for round := SignRound1; round <= SignRound6; round++ {
+		if round == SignRound6 {
+			assert.Equal(t, nil, session.SetMessages(messages))
+		}
+		// every signer works on its own copy of the session
+		for i, share := range shares {
+			signerSession := new(SigningSession)
+			jsonCopy(t, session, signerSession)
+			msg, err := signerSession.Sign(share, states[i])
+			assert.Equal(t, nil, err)
+			again, err := signerSession.Sign(share, states[i])
+			assert.Equal(t, nil, err)
+			assert.Equal(t, msg, again)
+
+			received := new(SigningMessage)
+			jsonCopy(t, msg, received)
+			assert.Equal(t, nil, session.AddMessage(received))
+		}
+		assert.Equal(t, round+1, session.Round())
+	}
+}
+
+func TestSigning(t *testing.T) {
+	shares := newKeyShares(t, 2, 3)
+	signers := []*KeyShare{shares[2], shares[0]}
+	privateKey := new(privacy.Scalar).FromUint64(0)
+	for _, share := range signers {
+		term := new(privacy.Scalar).Mul(lagrangeCoefficient(share.Index, []uint32{1, 3}), new(privacy.Scalar).FromBytesS(share.SecretShare))
+		privateKey.Add(privateKey, term)
+	}
+
+	snds := []*privacy.Scalar{privacy.RandomScalar(), privacy.RandomScalar()}
+	session, err := NewSigningSession(&shares[0].GroupKey, []uint32{3, 1}, snds, 2)
+	assert.Equal(t, nil, err)
+	messages := [][]byte{common.HashB([]byte("tx")), common.HashB([]byte("token tx"))}
+	runSigning(t, session, signers, messages)
+
+	serialNumbers, err := session.SerialNumbers()
+	assert.Equal(t, nil, err)
+	for j, snd := range snds {
+		expected := new(privacy.Point).Derive(privacy.PedCom.G[privacy.PedersenPrivateKeyIndex], privateKey, snd)
+		assert.Equal(t, true, privacy.IsPointEqual(expected, serialNumbers[j]))
+	}
+
+	proofs, err := session.SerialNumberProofs()
+	assert.Equal(t, nil, err)
+	for _, proof := range proofs {
+		valid, err := proof.Verify(nil)
+		assert.Equal(t, true, valid, err)
+	}
+
+	signatures, err := session.Signatures()
+	assert.Equal(t, nil, err)
+	publicKey, err := new(privacy.Point).FromBytesS(shares[0].PublicKey)
+	assert.Equal(t, nil, err)
+	verifyKey := new(privacy.SchnorrPublicKey)
+	verifyKey.Set(publicKey)
+	for t2, signature := range signatures {
+		assert.Equal(t, true, verifyKey.Verify(signature, messages[t2]))
+	}
+}
+
+func TestSigningRejectsBadAnswer(t *testing.T) {
+	shares := newKeyShares(t, 2, 2)
+	session, err := NewSigningSession(&shares[0].GroupKey, []uint32{1, 2}, []*privacy.Scalar{privacy.RandomScalar()}, 1)
+	assert.Equal(t, nil, err)
+	states := []*SignerState{new(SignerState), new(SignerState)}
+	for round := SignRound1; round <= SignRound4; round++ {
+		for i, share := range shares {
+			msg, err := session.Sign(share, states[i])
+			assert.Equal(t, nil, err)
+			assert.Equal(t, nil, session.AddMessage(msg))
+		}
+	}
+	msg, err := session.Sign(shares[0], states[0])
+	assert.Equal(t, nil, err)
+	forged := *msg
+	forged.SeedResponses = [][]byte{privacy.RandomScalar().ToBytesS()}
+	assert.NotEqual(t, nil, session.AddMessage(&forged))
+	assert.Equal(t, nil, session.AddMessage(msg))
+
+	_, err = session.Sign(shares[0], new(SignerState))
+	assert.NotEqual(t, nil, err)
+}
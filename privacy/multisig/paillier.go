@@ -0,0 +1,165 @@
+package multisig
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// paillierKeyBits is the size of the modulus of the Paillier key of a signer,
+// the MtA conversions need it far larger than the square of the curve order
+// so that the masked products never wrap around
+const paillierKeyBits = 2048
+
+var (
+	one   = big.NewInt(1)
+	three = big.NewInt(3)
+	four  = big.NewInt(4)
+)
+
+// PaillierPublicKey is a Paillier public key with generator N + 1
+type PaillierPublicKey struct {
+	N *big.Int
+}
+
+// PaillierPrivateKey is a Paillier private key, P and Q are the factors of N
+// the signer proves the modulus with
+type PaillierPrivateKey struct {
+	PaillierPublicKey
+	Lambda *big.Int
+	Mu     *big.Int
+	P      *big.Int
+	Q      *big.Int
+}
+
+// newPaillierKey returns a key whose modulus is the product of two primes
+// congruent to 3 mod 4, as the modulus proof needs
+func newPaillierKey(bits int) (*PaillierPrivateKey, error) {
+	for {
+		p, err := newBlumPrime(bits / 2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := newBlumPrime(bits / 2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+		p1 := new(big.Int).Sub(p, one)
+		q1 := new(big.Int).Sub(q, one)
+		gcd := new(big.Int).GCD(nil, nil, p1, q1)
+		lambda := new(big.Int).Mul(p1, q1)
+		lambda.Div(lambda, gcd)
+		// with g = N + 1, L(g^lambda mod N^2) = lambda mod N
+		mu := new(big.Int).ModInverse(lambda, n)
+		if mu == nil {
+			continue
+		}
+		return &PaillierPrivateKey{
+			PaillierPublicKey: PaillierPublicKey{N: n},
+			Lambda:            lambda,
+			Mu:                mu,
+			P:                 p,
+			Q:                 q,
+		}, nil
+	}
+}
+
+func newBlumPrime(bits int) (*big.Int, error) {
+	for {
+		p, err := rand.Prime(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).Mod(p, four).Cmp(three) == 0 {
+			return p, nil
+		}
+	}
+}
+
+// phi returns (P-1)*(Q-1)
+func (key PaillierPrivateKey) phi() *big.Int {
+	return new(big.Int).Mul(new(big.Int).Sub(key.P, one), new(big.Int).Sub(key.Q, one))
+}
+
+func (key PaillierPublicKey) nSquare() *big.Int {
+	return new(big.Int).Mul(key.N, key.N)
+}
+
+// encrypt returns (1 + m*N) * r^N mod N^2 for a random r and r
+func (key PaillierPublicKey) encrypt(m *big.Int) (*big.Int, *big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(key.N) >= 0 {
+		return nil, nil, errors.New("plaintext is out of range")
+	}
+	r, err := randomUnit(key.N)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key.encryptWithNonce(m, r), r, nil
+}
+
+// encryptWithNonce returns (1 + m*N) * r^N mod N^2
+func (key PaillierPublicKey) encryptWithNonce(m, r *big.Int) *big.Int {
+	nSquare := key.nSquare()
+	c := new(big.Int).Mul(m, key.N)
+	c.Add(c, one)
+	c.Mod(c, nSquare)
+	c.Mul(c, new(big.Int).Exp(r, key.N, nSquare))
+	return c.Mod(c, nSquare)
+}
+
+// add returns an encryption of the sum of the plaintexts of c1 and c2
+func (key PaillierPublicKey) add(c1, c2 *big.Int) *big.Int {
+	nSquare := key.nSquare()
+	c := new(big.Int).Mul(c1, c2)
+	return c.Mod(c, nSquare)
+}
+
+// mul returns an encryption of the plaintext of c times k
+func (key PaillierPublicKey) mul(c, k *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k, key.nSquare())
+}
+
+// isValidCipherText returns whether c is a unit modulo N^2
+func (key PaillierPublicKey) isValidCipherText(c *big.Int) bool {
+	return isUnit(c, key.nSquare())
+}
+
+func (key PaillierPrivateKey) decrypt(c *big.Int) (*big.Int, error) {
+	if !key.isValidCipherText(c) {
+		return nil, errors.New("ciphertext is out of range")
+	}
+	nSquare := key.nSquare()
+	m := new(big.Int).Exp(c, key.Lambda, nSquare)
+	m.Sub(m, one)
+	m.Div(m, key.N)
+	m.Mul(m, key.Mu)
+	return m.Mod(m, key.N), nil
+}
+
+// isUnit returns whether x is in [1, n) and coprime with n
+func isUnit(x, n *big.Int) bool {
+	if x == nil || x.Sign() <= 0 || x.Cmp(n) >= 0 {
+		return false
+	}
+	return new(big.Int).GCD(nil, nil, x, n).Cmp(one) == 0
+}
+
+// randomUnit returns a random unit modulo n
+func randomUnit(n *big.Int) (*big.Int, error) {
+	for {
+		r, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, err
+		}
+		if isUnit(r, n) {
+			return r, nil
+		}
+	}
+}
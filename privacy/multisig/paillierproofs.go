@@ -0,0 +1,570 @@
+package multisig
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/privacy"
+)
+
+// Zero-knowledge proofs of the Paillier keys and of the MtA conversions, the
+// non-interactive versions of the proofs of Canetti, Gennaro, Goldfeder,
+// Makriyannis and Peled (CGGMP). Every value of a proof is non-negative, the
+// random values are taken in [0, 2*bound) instead of [-bound, bound]
+const (
+	// proofIterations is the number of binary challenges of the modulus and
+	// ring-Pedersen proofs, a cheating prover passes with probability
+	// 2^-proofIterations
+	proofIterations = 80
+	// rangeBits is the size of the values the range proofs bound: the shares
+	// and the multipliers of the MtA conversions are under the curve order
+	rangeBits = 256
+	// slackBits is the slack of the range proofs, a proof only bounds a value
+	// by 2^(rangeBits+slackBits)
+	slackBits = 2 * rangeBits
+	// mtaMaskBits is the size of the mask added to a product in a MtA
+	// conversion, it hides the product statistically and the sum of both
+	// bounded by the range proofs stays far under the Paillier modulus
+	mtaMaskBits = 5 * rangeBits
+)
+
+// ringPedersen are the ring-Pedersen parameters of a signer: T is a square
+// modulo N and S = T^lambda, the range proofs the signer verifies commit to
+// their values with them. N is the modulus of the Paillier key of the signer
+type ringPedersen struct {
+	N *big.Int
+	S *big.Int
+	T *big.Int
+}
+
+func newRingPedersen(key *PaillierPrivateKey) (*ringPedersen, *big.Int, error) {
+	r, err := randomUnit(key.N)
+	if err != nil {
+		return nil, nil, err
+	}
+	lambda, err := rand.Int(rand.Reader, key.phi())
+	if err != nil {
+		return nil, nil, err
+	}
+	t := new(big.Int).Exp(r, big.NewInt(2), key.N)
+	return &ringPedersen{
+		N: key.N,
+		S: new(big.Int).Exp(t, lambda, key.N),
+		T: t,
+	}, lambda, nil
+}
+
+// commit returns S^x * T^r mod N
+func (params ringPedersen) commit(x, r *big.Int) *big.Int {
+	c := new(big.Int).Exp(params.S, x, params.N)
+	c.Mul(c, new(big.Int).Exp(params.T, r, params.N))
+	return c.Mod(c, params.N)
+}
+
+func (params ringPedersen) bytes() [][]byte {
+	return [][]byte{params.N.Bytes(), params.S.Bytes(), params.T.Bytes()}
+}
+
+// hashToInt returns a hash of values of bits bits
+func hashToInt(bits int, values ...[]byte) *big.Int {
+	data := []byte{}
+	for _, value := range values {
+		data = append(data, common.Uint32ToBytes(uint32(len(value)))...)
+		data = append(data, value...)
+	}
+	hash := []byte{}
+	for counter := uint32(0); len(hash)*8 < bits; counter++ {
+		hash = append(hash, common.HashB(append(common.Uint32ToBytes(counter), data...))...)
+	}
+	x := new(big.Int).SetBytes(hash)
+	return x.Rsh(x, uint(len(hash)*8-bits))
+}
+
+// proofChallenge returns the challenge in [0, q) of a proof of label on
+// values
+func proofChallenge(label string, values ...[]byte) *big.Int {
+	x := hashToInt(curveOrder.BitLen()+128, append([][]byte{[]byte(label)}, values...)...)
+	return x.Mod(x, curveOrder)
+}
+
+// randomBelow returns a random integer in [0, 2^bits * factor)
+func randomBelow(bits uint, factor *big.Int) (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(factor, bits))
+}
+
+// affine returns a + e*b
+func affine(a, e, b *big.Int) *big.Int {
+	return new(big.Int).Add(a, new(big.Int).Mul(e, b))
+}
+
+// mulMod returns a * b^e mod n
+func mulMod(a, b, e, n *big.Int) *big.Int {
+	c := new(big.Int).Exp(b, e, n)
+	c.Mul(c, a)
+	return c.Mod(c, n)
+}
+
+func bytesToInts(values ...[]byte) []*big.Int {
+	ints := make([]*big.Int, len(values))
+	for i, value := range values {
+		ints[i] = new(big.Int).SetBytes(value)
+	}
+	return ints
+}
+
+// ModulusProof proves a Paillier modulus N is square-free and the product of
+// two primes congruent to 3 mod 4 (the Paillier-Blum modulus proof of CGGMP):
+// Z[i]^N = y_i and X[i]^4 = (-1)^a_i * W^b_i * y_i mod N for the challenges
+// y_i, where Flags[i] = a_i + 2*b_i
+type ModulusProof struct {
+	W     []byte
+	X     [][]byte
+	Z     [][]byte
+	Flags []byte
+}
+
+// modulusChallenges returns the challenges y_i of a modulus proof
+func modulusChallenges(n, w *big.Int, context []byte) []*big.Int {
+	ys := make([]*big.Int, proofIterations)
+	for i := range ys {
+		y := hashToInt(n.BitLen()+128, []byte("modulus"), context, n.Bytes(), w.Bytes(), common.Uint32ToBytes(uint32(i)))
+		ys[i] = y.Mod(y, n)
+	}
+	return ys
+}
+
+// twist returns (-1)^a * w^b * y mod n for flags = a + 2*b
+func twist(y *big.Int, flags byte, w, n *big.Int) *big.Int {
+	v := new(big.Int).Set(y)
+	if flags&1 != 0 {
+		v.Sub(n, v)
+	}
+	if flags&2 != 0 {
+		v.Mul(v, w)
+	}
+	return v.Mod(v, n)
+}
+
+// fourthRoot returns a fourth root of y modulo N, y is a square modulo P and
+// Q. Modulo a prime p = 3 mod 4, y^((p+1)/4) is the square root of y that is
+// a square itself
+func (key PaillierPrivateKey) fourthRoot(y *big.Int) *big.Int {
+	roots := make([]*big.Int, 2)
+	for i, p := range []*big.Int{key.P, key.Q} {
+		exponent := new(big.Int).Add(p, one)
+		exponent.Rsh(exponent, 2)
+		exponent.Mul(exponent, exponent)
+		roots[i] = new(big.Int).Exp(y, exponent, p)
+	}
+	// x = roots[0] + P * ((roots[1] - roots[0]) / P mod Q)
+	x := new(big.Int).Sub(roots[1], roots[0])
+	x.Mul(x, new(big.Int).ModInverse(key.P, key.Q))
+	x.Mod(x, key.Q)
+	x.Mul(x, key.P)
+	return x.Add(x, roots[0])
+}
+
+func isSquareModPrime(y, p *big.Int) bool {
+	return big.Jacobi(new(big.Int).Mod(y, p), p) == 1
+}
+
+func proveModulus(key *PaillierPrivateKey, context []byte) (*ModulusProof, error) {
+	n := key.N
+	nInverse := new(big.Int).ModInverse(n, key.phi())
+	if nInverse == nil {
+		return nil, errors.New("modulus is not square-free")
+	}
+	var w *big.Int
+	for w == nil || big.Jacobi(w, n) != -1 {
+		var err error
+		if w, err = randomUnit(n); err != nil {
+			return nil, err
+		}
+	}
+	proof := &ModulusProof{
+		W:     w.Bytes(),
+		X:     make([][]byte, proofIterations),
+		Z:     make([][]byte, proofIterations),
+		Flags: make([]byte, proofIterations),
+	}
+	for i, y := range modulusChallenges(n, w, context) {
+		proof.Z[i] = new(big.Int).Exp(y, nInverse, n).Bytes()
+		for flags := byte(0); flags < 4; flags++ {
+			twisted := twist(y, flags, w, n)
+			if isSquareModPrime(twisted, key.P) && isSquareModPrime(twisted, key.Q) {
+				proof.X[i] = key.fourthRoot(twisted).Bytes()
+				proof.Flags[i] = flags
+				break
+			}
+		}
+		if proof.X[i] == nil {
+			return nil, errors.New("modulus is not the product of primes congruent to 3 mod 4")
+		}
+	}
+	return proof, nil
+}
+
+func (proof ModulusProof) verify(n *big.Int, context []byte) error {
+	if n.Bit(0) == 0 || n.ProbablyPrime(20) {
+		return errors.New("modulus is even or prime")
+	}
+	if len(proof.X) != proofIterations || len(proof.Z) != proofIterations || len(proof.Flags) != proofIterations {
+		return errors.New("wrong number of modulus proof iterations")
+	}
+	w := new(big.Int).SetBytes(proof.W)
+	if !isUnit(w, n) || big.Jacobi(w, n) != -1 {
+		return errors.New("invalid modulus proof non-residue")
+	}
+	for i, y := range modulusChallenges(n, w, context) {
+		z := new(big.Int).SetBytes(proof.Z[i])
+		if z.Cmp(n) >= 0 || new(big.Int).Exp(z, n, n).Cmp(y) != 0 {
+			return errors.New("modulus is not square-free")
+		}
+		x := new(big.Int).SetBytes(proof.X[i])
+		if proof.Flags[i] > 3 || x.Cmp(n) >= 0 || new(big.Int).Exp(x, four, n).Cmp(twist(y, proof.Flags[i], w, n)) != 0 {
+			return errors.New("modulus is not the product of two primes congruent to 3 mod 4")
+		}
+	}
+	return nil
+}
+
+// RingPedersenProof proves S is a power of T modulo N (the ring-Pedersen
+// parameters proof of CGGMP): T^Z[i] = A[i] * S^e_i mod N for the challenge
+// bits e_i
+type RingPedersenProof struct {
+	A [][]byte
+	Z [][]byte
+}
+
+func ringPedersenChallenges(params ringPedersen, a [][]byte, context []byte) *big.Int {
+	return hashToInt(proofIterations, append([][]byte{[]byte("ring-pedersen"), context}, append(params.bytes(), a...)...)...)
+}
+
+func proveRingPedersen(params ringPedersen, lambda, phi *big.Int, context []byte) (*RingPedersenProof, error) {
+	alphas := make([]*big.Int, proofIterations)
+	proof := &RingPedersenProof{
+		A: make([][]byte, proofIterations),
+		Z: make([][]byte, proofIterations),
+	}
+	for i := range alphas {
+		var err error
+		if alphas[i], err = rand.Int(rand.Reader, phi); err != nil {
+			return nil, err
+		}
+		proof.A[i] = new(big.Int).Exp(params.T, alphas[i], params.N).Bytes()
+	}
+	challenges := ringPedersenChallenges(params, proof.A, context)
+	for i, alpha := range alphas {
+		z := new(big.Int).Set(alpha)
+		if challenges.Bit(i) == 1 {
+			z.Add(z, lambda)
+		}
+		proof.Z[i] = z.Mod(z, phi).Bytes()
+	}
+	return proof, nil
+}
+
+func (proof RingPedersenProof) verify(params ringPedersen, context []byte) error {
+	if !isUnit(params.S, params.N) || !isUnit(params.T, params.N) || params.T.Cmp(one) == 0 {
+		return errors.New("invalid ring-Pedersen parameters")
+	}
+	if len(proof.A) != proofIterations || len(proof.Z) != proofIterations {
+		return errors.New("wrong number of ring-Pedersen proof iterations")
+	}
+	challenges := ringPedersenChallenges(params, proof.A, context)
+	for i := range proof.A {
+		a := new(big.Int).SetBytes(proof.A[i])
+		z := new(big.Int).SetBytes(proof.Z[i])
+		if !isUnit(a, params.N) || z.Cmp(params.N) >= 0 {
+			return errors.New("invalid ring-Pedersen proof")
+		}
+		expected := new(big.Int).Set(a)
+		if challenges.Bit(i) == 1 {
+			expected.Mul(expected, params.S)
+			expected.Mod(expected, params.N)
+		}
+		if new(big.Int).Exp(params.T, z, params.N).Cmp(expected) != 0 {
+			return errors.New("S is not a power of T")
+		}
+	}
+	return nil
+}
+
+// FactorProof proves the factors p and q of a Paillier modulus N0 are under
+// 2^(rangeBits+slackBits+1) * sqrt(N0), so that none of them is small (the
+// no small factor proof of CGGMP), with the ring-Pedersen parameters of the
+// verifier: P and Q commit to p and q, and R = S^N0 * T^Sigma is Q^p times a
+// power of T
+type FactorProof struct {
+	P     []byte
+	Q     []byte
+	A     []byte
+	B     []byte
+	T     []byte
+	Sigma []byte
+	Z1    []byte
+	Z2    []byte
+	W1    []byte
+	W2    []byte
+	V     []byte
+}
+
+func factorBound(n *big.Int) *big.Int {
+	return new(big.Int).Lsh(new(big.Int).Sqrt(n), rangeBits+slackBits+1)
+}
+
+func (proof FactorProof) challenge(n *big.Int, verifier ringPedersen, context []byte) *big.Int {
+	return proofChallenge("factor", append([][]byte{context, n.Bytes()}, append(verifier.bytes(), proof.P, proof.Q, proof.A, proof.B, proof.T, proof.Sigma)...)...)
+}
+
+func proveFactors(key *PaillierPrivateKey, verifier ringPedersen, context []byte) (*FactorProof, error) {
+	n := key.N
+	sqrtN := new(big.Int).Sqrt(n)
+	nHat := verifier.N
+	nnHat := new(big.Int).Mul(n, nHat)
+	for {
+		values := make([]*big.Int, 8)
+		for i, bound := range []struct {
+			bits   uint
+			factor *big.Int
+		}{
+			{rangeBits + slackBits, sqrtN}, // alpha
+			{rangeBits + slackBits, sqrtN}, // beta
+			{rangeBits, nHat},              // mu
+			{rangeBits, nHat},              // nu
+			{rangeBits, nnHat},             // sigma
+			{rangeBits + slackBits, nnHat}, // r
+			{rangeBits + slackBits, nHat},  // x
+			{rangeBits + slackBits, nHat},  // y
+		} {
+			var err error
+			if values[i], err = randomBelow(bound.bits, bound.factor); err != nil {
+				return nil, err
+			}
+		}
+		alpha, beta, mu, nu, sigma, r, x, y := values[0], values[1], values[2], values[3], values[4], values[5], values[6], values[7]
+		q := verifier.commit(key.Q, nu)
+		proof := &FactorProof{
+			P:     verifier.commit(key.P, mu).Bytes(),
+			Q:     q.Bytes(),
+			A:     verifier.commit(alpha, x).Bytes(),
+			B:     verifier.commit(beta, y).Bytes(),
+			T:     mulMod(new(big.Int).Exp(q, alpha, nHat), verifier.T, r, nHat).Bytes(),
+			Sigma: sigma.Bytes(),
+		}
+		e := proof.challenge(n, verifier, context)
+		// v = r + e * (sigma - nu*p), taken again in the unlikely case it is
+		// negative
+		sigmaHat := new(big.Int).Sub(sigma, new(big.Int).Mul(nu, key.P))
+		v := affine(r, e, sigmaHat)
+		if v.Sign() < 0 {
+			continue
+		}
+		proof.Z1 = affine(alpha, e, key.P).Bytes()
+		proof.Z2 = affine(beta, e, key.Q).Bytes()
+		proof.W1 = affine(x, e, mu).Bytes()
+		proof.W2 = affine(y, e, nu).Bytes()
+		proof.V = v.Bytes()
+		return proof, nil
+	}
+}
+
+func (proof FactorProof) verify(n *big.Int, verifier ringPedersen, context []byte) error {
+	nHat := verifier.N
+	values := bytesToInts(proof.P, proof.Q, proof.A, proof.B, proof.T)
+	for _, value := range values {
+		if !isUnit(value, nHat) {
+			return errors.New("invalid factor proof commitment")
+		}
+	}
+	p, q, a, b, t := values[0], values[1], values[2], values[3], values[4]
+	responses := bytesToInts(proof.Sigma, proof.Z1, proof.Z2, proof.W1, proof.W2, proof.V)
+	sigma, z1, z2, w1, w2, v := responses[0], responses[1], responses[2], responses[3], responses[4], responses[5]
+	bound := factorBound(n)
+	if z1.Cmp(bound) > 0 || z2.Cmp(bound) > 0 {
+		return errors.New("modulus has a small factor")
+	}
+	e := proof.challenge(n, verifier, context)
+	if verifier.commit(z1, w1).Cmp(mulMod(a, p, e, nHat)) != 0 || verifier.commit(z2, w2).Cmp(mulMod(b, q, e, nHat)) != 0 {
+		return errors.New("invalid factor commitments")
+	}
+	r := verifier.commit(n, sigma)
+	if mulMod(new(big.Int).Exp(q, z1, nHat), verifier.T, v, nHat).Cmp(mulMod(t, r, e, nHat)) != 0 {
+		return errors.New("modulus is not the product of the committed factors")
+	}
+	return nil
+}
+
+// EncryptionProof proves the plaintext of a Paillier ciphertext K is under
+// 2^(rangeBits+slackBits+1) (the encryption range proof of CGGMP), with the
+// ring-Pedersen parameters of the verifier: S commits to the plaintext, A
+// encrypts and C commits to the same random value
+type EncryptionProof struct {
+	S  []byte
+	A  []byte
+	C  []byte
+	Z1 []byte
+	Z2 []byte
+	Z3 []byte
+}
+
+func (proof EncryptionProof) challenge(key PaillierPublicKey, k *big.Int, verifier ringPedersen, context []byte) *big.Int {
+	return proofChallenge("encryption", append([][]byte{context, key.N.Bytes(), k.Bytes()}, append(verifier.bytes(), proof.S, proof.A, proof.C)...)...)
+}
+
+// proveEncryption proves k = Enc(plaintext; nonce) is the encryption of a
+// plaintext under 2^rangeBits
+func proveEncryption(key PaillierPublicKey, k, plaintext, nonce *big.Int, verifier ringPedersen, context []byte) (*EncryptionProof, error) {
+	alpha, err := randomBelow(rangeBits+slackBits, one)
+	if err != nil {
+		return nil, err
+	}
+	mu, err := randomBelow(rangeBits, verifier.N)
+	if err != nil {
+		return nil, err
+	}
+	gamma, err := randomBelow(rangeBits+slackBits, verifier.N)
+	if err != nil {
+		return nil, err
+	}
+	r, err := randomUnit(key.N)
+	if err != nil {
+		return nil, err
+	}
+	proof := &EncryptionProof{
+		S: verifier.commit(plaintext, mu).Bytes(),
+		A: key.encryptWithNonce(alpha, r).Bytes(),
+		C: verifier.commit(alpha, gamma).Bytes(),
+	}
+	e := proof.challenge(key, k, verifier, context)
+	proof.Z1 = affine(alpha, e, plaintext).Bytes()
+	proof.Z2 = mulMod(r, nonce, e, key.N).Bytes()
+	proof.Z3 = affine(gamma, e, mu).Bytes()
+	return proof, nil
+}
+
+func (proof EncryptionProof) verify(key PaillierPublicKey, k *big.Int, verifier ringPedersen, context []byte) error {
+	s, c := new(big.Int).SetBytes(proof.S), new(big.Int).SetBytes(proof.C)
+	if !isUnit(s, verifier.N) || !isUnit(c, verifier.N) {
+		return errors.New("invalid encryption proof commitment")
+	}
+	a := new(big.Int).SetBytes(proof.A)
+	z1, z2, z3 := new(big.Int).SetBytes(proof.Z1), new(big.Int).SetBytes(proof.Z2), new(big.Int).SetBytes(proof.Z3)
+	if !key.isValidCipherText(a) || !isUnit(z2, key.N) {
+		return errors.New("invalid encryption proof ciphertext")
+	}
+	if z1.BitLen() > rangeBits+slackBits+1 {
+		return errors.New("plaintext is out of range")
+	}
+	e := proof.challenge(key, k, verifier, context)
+	if key.encryptWithNonce(z1, z2).Cmp(mulMod(a, k, e, key.nSquare())) != 0 {
+		return errors.New("invalid encryption proof")
+	}
+	if verifier.commit(z1, z3).Cmp(mulMod(c, s, e, verifier.N)) != 0 {
+		return errors.New("invalid encryption proof commitments")
+	}
+	return nil
+}
+
+// AffineProof proves a MtA conversion D = C^x * Enc(y; r) under the Paillier
+// key of the verifier is made with the discrete logarithm x of X, x under
+// 2^(rangeBits+slackBits+1) and y under 2^(mtaMaskBits+slackBits+1) (the
+// affine operation proof of CGGMP, without the encryption of y under the key
+// of the prover), with the ring-Pedersen parameters of the verifier
+type AffineProof struct {
+	A  []byte
+	Bx []byte
+	E  []byte
+	S  []byte
+	F  []byte
+	T  []byte
+	Z1 []byte
+	Z2 []byte
+	Z3 []byte
+	Z4 []byte
+	W  []byte
+}
+
+func (proof AffineProof) challenge(key PaillierPublicKey, c, d *big.Int, x *privacy.Point, verifier ringPedersen, context []byte) *big.Int {
+	return proofChallenge("affine", append([][]byte{context, key.N.Bytes(), c.Bytes(), d.Bytes(), x.ToBytesS()}, append(verifier.bytes(), proof.A, proof.Bx, proof.E, proof.S, proof.F, proof.T)...)...)
+}
+
+// proveAffine proves d = c^x * Enc(y; nonce), x under the curve order and y
+// under 2^mtaMaskBits
+func proveAffine(key PaillierPublicKey, c, d, x, y, nonce *big.Int, verifier ringPedersen, context []byte) (*AffineProof, error) {
+	values := make([]*big.Int, 6)
+	for i, bound := range []struct {
+		bits   uint
+		factor *big.Int
+	}{
+		{rangeBits + slackBits, one},        // alpha
+		{mtaMaskBits + slackBits, one},      // beta
+		{rangeBits + slackBits, verifier.N}, // gamma
+		{rangeBits, verifier.N},             // m
+		{rangeBits + slackBits, verifier.N}, // delta
+		{rangeBits, verifier.N},             // mu
+	} {
+		var err error
+		if values[i], err = randomBelow(bound.bits, bound.factor); err != nil {
+			return nil, err
+		}
+	}
+	alpha, beta, gamma, m, delta, mu := values[0], values[1], values[2], values[3], values[4], values[5]
+	r, err := randomUnit(key.N)
+	if err != nil {
+		return nil, err
+	}
+	proof := &AffineProof{
+		A:  key.add(key.mul(c, alpha), key.encryptWithNonce(beta, r)).Bytes(),
+		Bx: new(privacy.Point).ScalarMultBase(bigToScalar(alpha)).ToBytesS(),
+		E:  verifier.commit(alpha, gamma).Bytes(),
+		S:  verifier.commit(x, m).Bytes(),
+		F:  verifier.commit(beta, delta).Bytes(),
+		T:  verifier.commit(y, mu).Bytes(),
+	}
+	e := proof.challenge(key, c, d, new(privacy.Point).ScalarMultBase(bigToScalar(x)), verifier, context)
+	proof.Z1 = affine(alpha, e, x).Bytes()
+	proof.Z2 = affine(beta, e, y).Bytes()
+	proof.Z3 = affine(gamma, e, m).Bytes()
+	proof.Z4 = affine(delta, e, mu).Bytes()
+	proof.W = mulMod(r, nonce, e, key.N).Bytes()
+	return proof, nil
+}
+
+func (proof AffineProof) verify(key PaillierPublicKey, c, d *big.Int, x *privacy.Point, verifier ringPedersen, context []byte) error {
+	commitments := bytesToInts(proof.E, proof.S, proof.F, proof.T)
+	for _, commitment := range commitments {
+		if !isUnit(commitment, verifier.N) {
+			return errors.New("invalid affine proof commitment")
+		}
+	}
+	eCommitment, s, f, t := commitments[0], commitments[1], commitments[2], commitments[3]
+	a := new(big.Int).SetBytes(proof.A)
+	bx, err := new(privacy.Point).FromBytesS(proof.Bx)
+	if err != nil {
+		return err
+	}
+	responses := bytesToInts(proof.Z1, proof.Z2, proof.Z3, proof.Z4, proof.W)
+	z1, z2, z3, z4, w := responses[0], responses[1], responses[2], responses[3], responses[4]
+	if !key.isValidCipherText(a) || !isUnit(w, key.N) {
+		return errors.New("invalid affine proof ciphertext")
+	}
+	if z1.BitLen() > rangeBits+slackBits+1 || z2.BitLen() > mtaMaskBits+slackBits+1 {
+		return errors.New("multiplier or mask is out of range")
+	}
+	e := proof.challenge(key, c, d, x, verifier, context)
+	if key.add(key.mul(c, z1), key.encryptWithNonce(z2, w)).Cmp(mulMod(a, d, e, key.nSquare())) != 0 {
+		return errors.New("invalid affine proof")
+	}
+	left := new(privacy.Point).ScalarMultBase(bigToScalar(z1))
+	right := new(privacy.Point).Add(bx, new(privacy.Point).ScalarMult(x, bigToScalar(e)))
+	if !privacy.IsPointEqual(left, right) {
+		return errors.New("multiplier is not the discrete logarithm of its point")
+	}
+	if verifier.commit(z1, z3).Cmp(mulMod(eCommitment, s, e, verifier.N)) != 0 || verifier.commit(z2, z4).Cmp(mulMod(f, t, e, verifier.N)) != 0 {
+		return errors.New("invalid affine proof commitments")
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package multisig
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestKey returns a key of the primes p and q, which may not make a valid
+// Paillier key
+func newTestKey(p, q *big.Int) *PaillierPrivateKey {
+	return &PaillierPrivateKey{
+		PaillierPublicKey: PaillierPublicKey{N: new(big.Int).Mul(p, q)},
+		P:                 p,
+		Q:                 q,
+	}
+}
+
+// newTestPrime returns a prime of bits bits equal to residue mod 4
+func newTestPrime(t *testing.T, bits int, residue int64) *big.Int {
+	for {
+		p, err := rand.Prime(rand.Reader, bits)
+		assert.Nil(t, err)
+		if new(big.Int).Mod(p, four).Int64() == residue {
+			return p
+		}
+	}
+}
+
+func newTestRingPedersen(t *testing.T) (*PaillierPrivateKey, ringPedersen) {
+	key, err := newPaillierKey(paillierKeyBits)
+	assert.Nil(t, err)
+	params, _, err := newRingPedersen(key)
+	assert.Nil(t, err)
+	return key, *params
+}
+
+func TestModulusProof(t *testing.T) {
+	key, err := newPaillierKey(paillierKeyBits)
+	assert.Nil(t, err)
+	context := []byte("context")
+	proof, err := proveModulus(key, context)
+	assert.Nil(t, err)
+	assert.Nil(t, proof.verify(key.N, context))
+
+	// the proof is bound to its context and its modulus
+	assert.NotNil(t, proof.verify(key.N, []byte("other context")))
+	assert.NotNil(t, proof.verify(new(big.Int).Add(key.N, big.NewInt(2)), context))
+	tampered := *proof
+	tampered.Z = append([][]byte{}, proof.Z...)
+	tampered.Z[0] = new(big.Int).Add(new(big.Int).SetBytes(proof.Z[0]), one).Bytes()
+	assert.NotNil(t, tampered.verify(key.N, context))
+
+	// a prime modulus has no proof
+	assert.NotNil(t, proof.verify(key.P, context))
+
+	tests := []struct {
+		name string
+		key  *PaillierPrivateKey
+	}{
+		{"primes congruent to 1 mod 4", newTestKey(newTestPrime(t, paillierKeyBits/2, 1), newTestPrime(t, paillierKeyBits/2, 1))},
+		{"square factor", &PaillierPrivateKey{PaillierPublicKey: PaillierPublicKey{N: new(big.Int).Mul(key.N, key.P)}, P: key.P, Q: key.Q}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, err := proveModulus(tt.key, context)
+			if err == nil {
+				assert.NotNil(t, proof.verify(tt.key.N, context))
+			}
+		})
+	}
+}
+
+func TestRingPedersenProof(t *testing.T) {
+	key, err := newPaillierKey(paillierKeyBits)
+	assert.Nil(t, err)
+	params, lambda, err := newRingPedersen(key)
+	assert.Nil(t, err)
+	context := []byte("context")
+	proof, err := proveRingPedersen(*params, lambda, key.phi(), context)
+	assert.Nil(t, err)
+	assert.Nil(t, proof.verify(*params, context))
+	assert.NotNil(t, proof.verify(*params, []byte("other context")))
+
+	// S out of the group of T
+	other := *params
+	other.S = new(big.Int).Add(params.S, one)
+	assert.NotNil(t, proof.verify(other, context))
+	forged, err := proveRingPedersen(other, lambda, key.phi(), context)
+	assert.Nil(t, err)
+	assert.NotNil(t, forged.verify(other, context))
+
+	// T = 1 commits to nothing
+	trivial := ringPedersen{N: params.N, S: one, T: one}
+	trivialProof, err := proveRingPedersen(trivial, lambda, key.phi(), context)
+	assert.Nil(t, err)
+	assert.NotNil(t, trivialProof.verify(trivial, context))
+}
+
+func TestFactorProof(t *testing.T) {
+	key, err := newPaillierKey(paillierKeyBits)
+	assert.Nil(t, err)
+	_, verifier := newTestRingPedersen(t)
+	context := []byte("context")
+	proof, err := proveFactors(key, verifier, context)
+	assert.Nil(t, err)
+	assert.Nil(t, proof.verify(key.N, verifier, context))
+	assert.NotNil(t, proof.verify(key.N, verifier, []byte("other context")))
+
+	// a modulus with a small factor has a valid modulus proof but no factor
+	// proof
+	small := newTestKey(newTestPrime(t, 128, 3), newTestPrime(t, paillierKeyBits-128, 3))
+	modulusProof, err := proveModulus(small, context)
+	assert.Nil(t, err)
+	assert.Nil(t, modulusProof.verify(small.N, context))
+	proof, err = proveFactors(small, verifier, context)
+	assert.Nil(t, err)
+	assert.NotNil(t, proof.verify(small.N, verifier, context))
+}
+
+func TestPaillierCipherText(t *testing.T) {
+	key, err := newPaillierKey(paillierKeyBits)
+	assert.Nil(t, err)
+	c, _, err := key.encrypt(big.NewInt(42))
+	assert.Nil(t, err)
+	m, err := key.decrypt(c)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), m.Int64())
+
+	_, _, err = key.encrypt(key.N)
+	assert.NotNil(t, err)
+	for _, c := range []*big.Int{nil, big.NewInt(0), key.N, key.P, key.nSquare(), new(big.Int).Add(key.nSquare(), one)} {
+		assert.False(t, key.isValidCipherText(c))
+		_, err := key.decrypt(c)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestEncryptionProof(t *testing.T) {
+	key, err := newPaillierKey(paillierKeyBits)
+	assert.Nil(t, err)
+	_, verifier := newTestRingPedersen(t)
+	context := []byte("context")
+	plaintext := privacy.ScalarToBigInt(privacy.RandomScalar())
+	c, nonce, err := key.encrypt(plaintext)
+	assert.Nil(t, err)
+	proof, err := proveEncryption(key.PaillierPublicKey, c, plaintext, nonce, verifier, context)
+	assert.Nil(t, err)
+	assert.Nil(t, proof.verify(key.PaillierPublicKey, c, verifier, context))
+	assert.NotNil(t, proof.verify(key.PaillierPublicKey, c, verifier, []byte("other context")))
+
+	// the proof is of its ciphertext only
+	other, _, err := key.encrypt(plaintext)
+	assert.Nil(t, err)
+	assert.NotNil(t, proof.verify(key.PaillierPublicKey, other, verifier, context))
+
+	// a plaintext out of range leaks the multiplier of the conversion
+	large := new(big.Int).Lsh(one, 1000)
+	c, nonce, err = key.encrypt(large)
+	assert.Nil(t, err)
+	proof, err = proveEncryption(key.PaillierPublicKey, c, large, nonce, verifier, context)
+	assert.Nil(t, err)
+	assert.NotNil(t, proof.verify(key.PaillierPublicKey, c, verifier, context))
+}
+
+func TestAffineProof(t *testing.T) {
+	verifierKey, verifier := newTestRingPedersen(t)
+	context := []byte("context")
+	encShare, _, err := verifierKey.encrypt(privacy.ScalarToBigInt(privacy.RandomScalar()))
+	assert.Nil(t, err)
+	rho := privacy.RandomScalar()
+	gamma := new(privacy.Point).ScalarMultBase(rho)
+	convert := func(multiplier, mask *big.Int) (*big.Int, *AffineProof) {
+		encMask, nonce, err := verifierKey.encrypt(mask)
+		assert.Nil(t, err)
+		conversion := verifierKey.add(verifierKey.mul(encShare, multiplier), encMask)
+		proof, err := proveAffine(verifierKey.PaillierPublicKey, encShare, conversion, multiplier, mask, nonce, verifier, context)
+		assert.Nil(t, err)
+		return conversion, proof
+	}
+	mask, err := randomBelow(mtaMaskBits, one)
+	assert.Nil(t, err)
+	conversion, proof := convert(privacy.ScalarToBigInt(rho), mask)
+	assert.Nil(t, proof.verify(verifierKey.PaillierPublicKey, encShare, conversion, gamma, verifier, context))
+	assert.NotNil(t, proof.verify(verifierKey.PaillierPublicKey, encShare, conversion, gamma, verifier, []byte("other context")))
+	assert.NotNil(t, proof.verify(verifierKey.PaillierPublicKey, encShare, conversion, new(privacy.Point).ScalarMultBase(privacy.RandomScalar()), verifier, context))
+
+	tests := []struct {
+		name       string
+		multiplier *big.Int
+		mask       *big.Int
+	}{
+		{"multiplier is not the discrete logarithm", privacy.ScalarToBigInt(privacy.RandomScalar()), mask},
+		{"multiplier out of range", new(big.Int).Add(privacy.ScalarToBigInt(rho), new(big.Int).Lsh(curveOrder, 800)), mask},
+		{"mask out of range", privacy.ScalarToBigInt(rho), new(big.Int).Lsh(one, 1900)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversion, proof := convert(tt.multiplier, tt.mask)
+			assert.NotNil(t, proof.verify(verifierKey.PaillierPublicKey, encShare, conversion, gamma, verifier, context))
+		})
+	}
+}
+
+// signRound runs round of session for shares without adding the messages
+func signRound(t *testing.T, session *SigningSession, shares []*KeyShare, states []*SignerState) []*SigningMessage {
+	msgs := make([]*SigningMessage, len(shares))
+	for i, share := range shares {
+		msg, err := session.Sign(share, states[i])
+		assert.Nil(t, err)
+		msgs[i] = msg
+	}
+	return msgs
+}
+
+func TestSigningRejectsMalformedPaillier(t *testing.T) {
+	shares := newKeyShares(t, 2, 2)
+	newSession := func() (*SigningSession, []*SignerState) {
+		session, err := NewSigningSession(&shares[0].GroupKey, []uint32{1, 2}, []*privacy.Scalar{privacy.RandomScalar()}, 1)
+		assert.Nil(t, err)
+		return session, []*SignerState{new(SignerState), new(SignerState)}
+	}
+	session, states := newSession()
+	round1 := signRound(t, session, shares, states)
+
+	// the key of another signer or session does not match its proofs
+	other, otherStates := newSession()
+	otherRound1 := signRound(t, other, shares, otherStates)
+	forged := *round1[0]
+	forged.PaillierN = otherRound1[0].PaillierN
+	assert.NotNil(t, session.AddMessage(&forged))
+	forged = *otherRound1[0]
+	forged.SessionID = session.ID
+	assert.NotNil(t, session.AddMessage(&forged))
+	forged = *round1[0]
+	forged.ModulusProof = nil
+	assert.NotNil(t, session.AddMessage(&forged))
+	for _, msg := range round1 {
+		assert.Nil(t, session.AddMessage(msg))
+	}
+
+	round2 := signRound(t, session, shares, states)
+	tests := []struct {
+		name   string
+		forge  func(msg *SigningMessage)
+		reject bool
+	}{
+		{"encrypted share out of range", func(msg *SigningMessage) {
+			msg.EncShares = [][]byte{session.paillierKey(0).nSquare().Bytes()}
+		}, true},
+		{"encrypted share of another signer", func(msg *SigningMessage) {
+			msg.EncShares = round2[1].EncShares
+		}, true},
+		{"missing factor proof", func(msg *SigningMessage) {
+			msg.FactorProofs = make([]*FactorProof, 2)
+		}, true},
+		{"factor proof for another signer", func(msg *SigningMessage) {
+			msg.FactorProofs = []*FactorProof{nil, round2[1].FactorProofs[0]}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forged := *round2[0]
+			tt.forge(&forged)
+			assert.NotNil(t, session.AddMessage(&forged))
+		})
+	}
+
+	// a signer checks the proofs it relies on even if the coordinator did not
+	session.Rounds[SignRound2-1][1] = round2[1]
+	forged = *round2[0]
+	large := new(big.Int).Lsh(one, 1000)
+	encShare, _, err := session.paillierKey(0).encrypt(large)
+	assert.Nil(t, err)
+	forged.EncShares = [][]byte{encShare.Bytes()}
+	session.Rounds[SignRound2-1][0] = &forged
+	_, err = session.Sign(shares[1], states[1])
+	assert.NotNil(t, err)
+	session.Rounds[SignRound2-1][0] = round2[0]
+	_, err = session.Sign(shares[1], states[1])
+	assert.Nil(t, err)
+}
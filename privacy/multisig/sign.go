@@ -0,0 +1,995 @@
+package multisig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/privacy"
+	C25519 "github.com/incognitochain/incognito-chain/privacy/curve25519"
+	"github.com/incognitochain/incognito-chain/privacy/zeroknowledge/serialnumbernoprivacy"
+	"github.com/incognitochain/incognito-chain/privacy/zeroknowledge/utils"
+)
+
+// Rounds of a signing session. Spending a coin without privacy needs its
+// serial number G^(1/(sk+snd)) and a proof of it, on top of the signature of
+// the tx:
+//   - round 1: every signer publishes a new Paillier key with the proof that
+//     its modulus is square-free and the product of two primes, and its
+//     ring-Pedersen parameters with their proof
+//   - round 2: every signer encrypts its additive share x_i of sk+snd with its
+//     Paillier key and publishes G^rho_i for a random rho_i. It proves to
+//     every other signer that the factors of its modulus are not small and
+//     that x_i is in range
+//   - round 3: every pair of signers turns x_i*rho_j into additive shares with
+//     a MtA conversion, proving rho_j is the discrete logarithm of G^rho_j and
+//     the mask is in range, every signer commits to its nonces
+//   - round 4: every signer publishes its share of delta = (sk+snd)*rho and
+//     its nonces, the serial number is (sum G^rho_i) / delta
+//   - round 5: every signer answers the challenges of the serial number proofs
+//   - round 6: every signer answers the challenges of the signatures of the
+//     messages the coordinator built from the proofs
+//
+// The coordinator checks every proof when it adds a message and every signer
+// checks again the proofs it relies on before answering the next round. A
+// signer deviating from the protocol can not forge a serial number or a
+// signature either, the coordinator checks every answer against the public
+// share of its signer
+const (
+	SignRound1 = iota + 1
+	SignRound2
+	SignRound3
+	SignRound4
+	SignRound5
+	SignRound6
+	signRounds = SignRound6
+)
+
+var curveOrder = func() *big.Int {
+	order := privacy.Reverse(C25519.CurveOrder())
+	return new(big.Int).SetBytes(order[:])
+}()
+
+// SigningSession is the public transcript of the signing by Signers of the
+// spending of coins of SNDerivators and of NumMessages messages. The
+// coordinator adds the messages of the signers round after round and sets
+// Messages once the serial number proofs are done
+type SigningSession struct {
+	ID           string
+	Group        GroupKey
+	Signers      []uint32
+	SNDerivators [][]byte
+	NumMessages  int
+	Messages     [][]byte
+	Rounds       [][]*SigningMessage
+}
+
+// SigningMessage is the message of a signer for a round, only the fields of
+// the round are set
+type SigningMessage struct {
+	SessionID string
+	Round     int
+	Index     uint32
+
+	PaillierN     []byte             `json:",omitempty"`
+	PedersenS     []byte             `json:",omitempty"`
+	PedersenT     []byte             `json:",omitempty"`
+	ModulusProof  *ModulusProof      `json:",omitempty"`
+	PedersenProof *RingPedersenProof `json:",omitempty"`
+
+	EncShares    [][]byte             `json:",omitempty"`
+	Gammas       [][]byte             `json:",omitempty"`
+	FactorProofs []*FactorProof       `json:",omitempty"`
+	EncProofs    [][]*EncryptionProof `json:",omitempty"`
+
+	MtA             [][][]byte       `json:",omitempty"`
+	MtAProofs       [][]*AffineProof `json:",omitempty"`
+	NonceCommitment []byte           `json:",omitempty"`
+
+	Deltas       [][]byte `json:",omitempty"`
+	SeedNonces   [][]byte `json:",omitempty"`
+	OutputNonces [][]byte `json:",omitempty"`
+	SigNonces    [][]byte `json:",omitempty"`
+
+	SeedResponses [][]byte `json:",omitempty"`
+
+	SigResponses [][]byte `json:",omitempty"`
+}
+
+// SignerState is the secret state a signer keeps between two rounds of a
+// session, it must not be reused for another session
+type SignerState struct {
+	SessionID   string
+	Index       uint32
+	Round       int
+	LastMessage *SigningMessage
+	Paillier    *PaillierPrivateKey
+	Rhos        [][]byte
+	Betas       [][]byte
+	SeedNonces  [][]byte
+	SigNonces   [][]byte
+}
+
+// NewSigningSession returns the session of signers of group spending the coins
+// of sndDerivators and signing numMessages messages
+func NewSigningSession(group *GroupKey, signers []uint32, sndDerivators []*privacy.Scalar, numMessages int) (*SigningSession, error) {
+	if err := group.validate(); err != nil {
+		return nil, err
+	}
+	if len(signers) != group.Threshold {
+		return nil, NewMultiSigError(InvalidThresholdErr, fmt.Errorf("%d signers for a threshold of %d", len(signers), group.Threshold))
+	}
+	sorted := append([]uint32{}, signers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i, signer := range sorted {
+		if signer == 0 || int(signer) > len(group.PublicShares) || (i > 0 && sorted[i-1] == signer) {
+			return nil, NewMultiSigError(InvalidParticipantErr, fmt.Errorf("signer %d", signer))
+		}
+	}
+	if numMessages < 1 {
+		return nil, NewMultiSigError(InvalidSessionErr, errors.New("nothing to sign"))
+	}
+	id, err := newSessionID()
+	if err != nil {
+		return nil, NewMultiSigError(UnExpectedError, err)
+	}
+	session := &SigningSession{
+		ID:           id,
+		Group:        *group,
+		Signers:      sorted,
+		SNDerivators: make([][]byte, len(sndDerivators)),
+		NumMessages:  numMessages,
+		Rounds:       make([][]*SigningMessage, signRounds),
+	}
+	for i, snd := range sndDerivators {
+		session.SNDerivators[i] = snd.ToBytesS()
+	}
+	for i := range session.Rounds {
+		session.Rounds[i] = make([]*SigningMessage, len(sorted))
+	}
+	return session, nil
+}
+
+// Round returns the round the session waits the messages of, it is
+// signRounds+1 once the session is done
+func (session SigningSession) Round() int {
+	for round := SignRound1; round <= signRounds; round++ {
+		if !session.isRoundComplete(round) {
+			return round
+		}
+	}
+	return signRounds + 1
+}
+
+// IsComplete returns whether every signer sent its last message
+func (session SigningSession) IsComplete() bool {
+	return session.Round() > signRounds
+}
+
+func (session SigningSession) isRoundComplete(round int) bool {
+	for _, msg := range session.Rounds[round-1] {
+		if msg == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (session SigningSession) position(index uint32) int {
+	for i, signer := range session.Signers {
+		if signer == index {
+			return i
+		}
+	}
+	return -1
+}
+
+func (session SigningSession) validate() error {
+	if err := session.Group.validate(); err != nil {
+		return err
+	}
+	if len(session.Signers) != session.Group.Threshold || len(session.Rounds) != signRounds {
+		return NewMultiSigError(InvalidSessionErr, errors.New("wrong number of signers or rounds"))
+	}
+	for _, msgs := range session.Rounds {
+		if len(msgs) != len(session.Signers) {
+			return NewMultiSigError(InvalidSessionErr, errors.New("wrong number of messages"))
+		}
+	}
+	return nil
+}
+
+// SetMessages sets the messages to sign, once the serial number proofs are
+// done
+func (session *SigningSession) SetMessages(messages [][]byte) error {
+	if session.Round() != SignRound6 {
+		return NewMultiSigError(InvalidRoundErr, errors.New("messages are set after round 5"))
+	}
+	if len(messages) != session.NumMessages {
+		return NewMultiSigError(InvalidSessionErr, fmt.Errorf("%d messages instead of %d", len(messages), session.NumMessages))
+	}
+	for _, message := range messages {
+		if len(message) != common.HashSize {
+			return NewMultiSigError(InvalidSessionErr, errors.New("message is not a hash"))
+		}
+	}
+	session.Messages = messages
+	return nil
+}
+
+// shares returns the additive shares of the signer of index of sk and of
+// sk+snd for every coin of the session, the first signer adds the snd
+func (session SigningSession) shares(share *KeyShare) (*privacy.Scalar, []*privacy.Scalar) {
+	secret := new(privacy.Scalar).Mul(lagrangeCoefficient(share.Index, session.Signers), new(privacy.Scalar).FromBytesS(share.SecretShare))
+	coinShares := make([]*privacy.Scalar, len(session.SNDerivators))
+	for j, snd := range session.SNDerivators {
+		coinShares[j] = new(privacy.Scalar).Set(secret)
+		if session.Signers[0] == share.Index {
+			coinShares[j].Add(coinShares[j], new(privacy.Scalar).FromBytesS(snd))
+		}
+	}
+	return secret, coinShares
+}
+
+// Sign returns the message of the signer of share for the current round of
+// the session and updates state, it returns the same message when it is
+// called twice for a round
+func (session SigningSession) Sign(share *KeyShare, state *SignerState) (*SigningMessage, error) {
+	if err := session.validate(); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(share.PublicKey, session.Group.PublicKey) {
+		return nil, NewMultiSigError(InvalidParticipantErr, errors.New("key share of another group"))
+	}
+	position := session.position(share.Index)
+	if position < 0 {
+		return nil, NewMultiSigError(InvalidParticipantErr, fmt.Errorf("participant %d is not a signer", share.Index))
+	}
+	if state.SessionID != "" && (state.SessionID != session.ID || state.Index != share.Index) {
+		return nil, NewMultiSigError(InvalidSessionErr, fmt.Errorf("state of session %s", state.SessionID))
+	}
+	round := session.Round()
+	if round > signRounds {
+		return nil, NewMultiSigError(InvalidRoundErr, errors.New("session is complete"))
+	}
+	if state.Round == round && state.LastMessage != nil {
+		return state.LastMessage, nil
+	}
+	if state.Round != round-1 {
+		return nil, NewMultiSigError(InvalidRoundErr, fmt.Errorf("state is at round %d, session at round %d", state.Round, round))
+	}
+	if err := session.checkPreviousRound(round, position); err != nil {
+		return nil, err
+	}
+	var msg *SigningMessage
+	var err error
+	switch round {
+	case SignRound1:
+		msg, err = session.signRound1(share, state)
+	case SignRound2:
+		msg, err = session.signRound2(share, state, position)
+	case SignRound3:
+		msg, err = session.signRound3(share, state, position)
+	case SignRound4:
+		msg, err = session.signRound4(share, state, position)
+	case SignRound5:
+		msg, err = session.signRound5(share, state)
+	case SignRound6:
+		msg, err = session.signRound6(share, state)
+	}
+	if err != nil {
+		return nil, err
+	}
+	msg.SessionID = session.ID
+	msg.Round = round
+	msg.Index = share.Index
+	state.SessionID = session.ID
+	state.Index = share.Index
+	state.Round = round
+	state.LastMessage = msg
+	return msg, nil
+}
+
+func (session SigningSession) signRound1(share *KeyShare, state *SignerState) (*SigningMessage, error) {
+	paillierKey, err := newPaillierKey(paillierKeyBits)
+	if err != nil {
+		return nil, NewMultiSigError(PaillierErr, err)
+	}
+	params, lambda, err := newRingPedersen(paillierKey)
+	if err != nil {
+		return nil, NewMultiSigError(PaillierErr, err)
+	}
+	context := session.keyProofContext(share.Index)
+	modulusProof, err := proveModulus(paillierKey, context)
+	if err != nil {
+		return nil, NewMultiSigError(PaillierErr, err)
+	}
+	pedersenProof, err := proveRingPedersen(*params, lambda, paillierKey.phi(), context)
+	if err != nil {
+		return nil, NewMultiSigError(PaillierErr, err)
+	}
+	state.Paillier = paillierKey
+	return &SigningMessage{
+		PaillierN:     paillierKey.N.Bytes(),
+		PedersenS:     params.S.Bytes(),
+		PedersenT:     params.T.Bytes(),
+		ModulusProof:  modulusProof,
+		PedersenProof: pedersenProof,
+	}, nil
+}
+
+func (session SigningSession) signRound2(share *KeyShare, state *SignerState, position int) (*SigningMessage, error) {
+	_, coinShares := session.shares(share)
+	msg := &SigningMessage{
+		EncShares:    make([][]byte, len(coinShares)),
+		Gammas:       make([][]byte, len(coinShares)),
+		FactorProofs: make([]*FactorProof, len(session.Signers)),
+		EncProofs:    make([][]*EncryptionProof, len(coinShares)),
+	}
+	for l := range session.Signers {
+		if l == position {
+			continue
+		}
+		proof, err := proveFactors(state.Paillier, session.ringPedersen(l), session.proofContext(position, l, 0))
+		if err != nil {
+			return nil, NewMultiSigError(PaillierErr, err)
+		}
+		msg.FactorProofs[l] = proof
+	}
+	state.Rhos = make([][]byte, len(coinShares))
+	for j, coinShare := range coinShares {
+		plaintext := privacy.ScalarToBigInt(coinShare)
+		encShare, nonce, err := state.Paillier.encrypt(plaintext)
+		if err != nil {
+			return nil, NewMultiSigError(PaillierErr, err)
+		}
+		msg.EncShares[j] = encShare.Bytes()
+		msg.EncProofs[j] = make([]*EncryptionProof, len(session.Signers))
+		for l := range session.Signers {
+			if l == position {
+				continue
+			}
+			msg.EncProofs[j][l], err = proveEncryption(state.Paillier.PaillierPublicKey, encShare, plaintext, nonce, session.ringPedersen(l), session.proofContext(position, l, j))
+			if err != nil {
+				return nil, NewMultiSigError(PaillierErr, err)
+			}
+		}
+		rho := privacy.RandomScalar()
+		state.Rhos[j] = rho.ToBytesS()
+		msg.Gammas[j] = new(privacy.Point).ScalarMultBase(rho).ToBytesS()
+	}
+	return msg, nil
+}
+
+func (session SigningSession) signRound3(share *KeyShare, state *SignerState, position int) (*SigningMessage, error) {
+	numCoins := len(session.SNDerivators)
+	msg := &SigningMessage{
+		MtA:       make([][][]byte, numCoins),
+		MtAProofs: make([][]*AffineProof, numCoins),
+	}
+	state.Betas = make([][]byte, numCoins)
+	for j := 0; j < numCoins; j++ {
+		rho := privacy.ScalarToBigInt(new(privacy.Scalar).FromBytesS(state.Rhos[j]))
+		beta := new(big.Int)
+		msg.MtA[j] = make([][]byte, len(session.Signers))
+		msg.MtAProofs[j] = make([]*AffineProof, len(session.Signers))
+		for l, round2 := range session.Rounds[SignRound2-1] {
+			if l == position {
+				continue
+			}
+			// Enc_l(x_l*rho + mask), the signer l gets x_l*rho + mask and this
+			// signer keeps -mask
+			publicKey := session.paillierKey(l)
+			mask, err := randomBelow(mtaMaskBits, one)
+			if err != nil {
+				return nil, NewMultiSigError(UnExpectedError, err)
+			}
+			encMask, nonce, err := publicKey.encrypt(mask)
+			if err != nil {
+				return nil, NewMultiSigError(PaillierErr, err)
+			}
+			encShare := new(big.Int).SetBytes(round2.EncShares[j])
+			conversion := publicKey.add(publicKey.mul(encShare, rho), encMask)
+			msg.MtA[j][l] = conversion.Bytes()
+			msg.MtAProofs[j][l], err = proveAffine(publicKey, encShare, conversion, rho, mask, nonce, session.ringPedersen(l), session.proofContext(position, l, j))
+			if err != nil {
+				return nil, NewMultiSigError(PaillierErr, err)
+			}
+			beta.Sub(beta, mask)
+		}
+		state.Betas[j] = bigToScalar(beta).ToBytesS()
+	}
+
+	state.SeedNonces = make([][]byte, numCoins)
+	for j := range state.SeedNonces {
+		state.SeedNonces[j] = privacy.RandomScalar().ToBytesS()
+	}
+	state.SigNonces = make([][]byte, session.NumMessages)
+	for t := range state.SigNonces {
+		state.SigNonces[t] = privacy.RandomScalar().ToBytesS()
+	}
+	nonces, err := session.nonces(state)
+	if err != nil {
+		return nil, err
+	}
+	msg.NonceCommitment = nonceCommitment(nonces)
+	return msg, nil
+}
+
+func (session SigningSession) signRound4(share *KeyShare, state *SignerState, position int) (*SigningMessage, error) {
+	_, coinShares := session.shares(share)
+	nonces, err := session.nonces(state)
+	if err != nil {
+		return nil, err
+	}
+	msg := &SigningMessage{
+		Deltas:       make([][]byte, len(coinShares)),
+		SeedNonces:   nonces.SeedNonces,
+		OutputNonces: nonces.OutputNonces,
+		SigNonces:    nonces.SigNonces,
+	}
+	for j, coinShare := range coinShares {
+		rho := new(privacy.Scalar).FromBytesS(state.Rhos[j])
+		delta := new(privacy.Scalar).Mul(coinShare, rho)
+		delta.Add(delta, new(privacy.Scalar).FromBytesS(state.Betas[j]))
+		for l, round3 := range session.Rounds[SignRound3-1] {
+			if l == position {
+				continue
+			}
+			alpha, err := state.Paillier.decrypt(new(big.Int).SetBytes(round3.MtA[j][position]))
+			if err != nil {
+				return nil, NewMultiSigError(PaillierErr, err)
+			}
+			delta.Add(delta, bigToScalar(alpha))
+		}
+		msg.Deltas[j] = delta.ToBytesS()
+	}
+	return msg, nil
+}
+
+func (session SigningSession) signRound5(share *KeyShare, state *SignerState) (*SigningMessage, error) {
+	secret, _ := session.shares(share)
+	challenges, err := session.proofChallenges()
+	if err != nil {
+		return nil, err
+	}
+	msg := &SigningMessage{
+		SeedResponses: make([][]byte, len(challenges)),
+	}
+	for j, challenge := range challenges {
+		response := new(privacy.Scalar).Mul(challenge, secret)
+		response.Add(response, new(privacy.Scalar).FromBytesS(state.SeedNonces[j]))
+		msg.SeedResponses[j] = response.ToBytesS()
+	}
+	return msg, nil
+}
+
+func (session SigningSession) signRound6(share *KeyShare, state *SignerState) (*SigningMessage, error) {
+	if len(session.Messages) != session.NumMessages {
+		return nil, NewMultiSigError(InvalidRoundErr, errors.New("messages to sign are not set"))
+	}
+	secret, _ := session.shares(share)
+	challenges, err := session.sigChallenges()
+	if err != nil {
+		return nil, err
+	}
+	msg := &SigningMessage{
+		SigResponses: make([][]byte, len(challenges)),
+	}
+	for t, challenge := range challenges {
+		// z1 = k - e*sk as in privacy.SchnorrPrivateKey.Sign
+		response := new(privacy.Scalar).Mul(challenge, secret)
+		response.Sub(new(privacy.Scalar).FromBytesS(state.SigNonces[t]), response)
+		msg.SigResponses[t] = response.ToBytesS()
+	}
+	return msg, nil
+}
+
+// signerNonces are the nonces of a signer revealed at round 4
+type signerNonces struct {
+	SeedNonces   [][]byte
+	OutputNonces [][]byte
+	SigNonces    [][]byte
+}
+
+func (session SigningSession) nonces(state *SignerState) (*signerNonces, error) {
+	nonces := &signerNonces{
+		SeedNonces:   make([][]byte, len(state.SeedNonces)),
+		OutputNonces: make([][]byte, len(state.SeedNonces)),
+		SigNonces:    make([][]byte, len(state.SigNonces)),
+	}
+	for j, seedNonce := range state.SeedNonces {
+		gamma, err := session.gamma(j)
+		if err != nil {
+			return nil, err
+		}
+		nonce := new(privacy.Scalar).FromBytesS(seedNonce)
+		nonces.SeedNonces[j] = new(privacy.Point).ScalarMultBase(nonce).ToBytesS()
+		nonces.OutputNonces[j] = new(privacy.Point).ScalarMult(gamma, nonce).ToBytesS()
+	}
+	for t, sigNonce := range state.SigNonces {
+		nonces.SigNonces[t] = new(privacy.Point).ScalarMultBase(new(privacy.Scalar).FromBytesS(sigNonce)).ToBytesS()
+	}
+	return nonces, nil
+}
+
+func nonceCommitment(nonces *signerNonces) []byte {
+	data := []byte{}
+	for _, points := range [][][]byte{nonces.SeedNonces, nonces.OutputNonces, nonces.SigNonces} {
+		for _, point := range points {
+			data = append(data, point...)
+		}
+	}
+	return common.HashB(data)
+}
+
+// AddMessage checks the message of a signer for the current round and adds
+// it to the session
+func (session *SigningSession) AddMessage(msg *SigningMessage) error {
+	if msg.SessionID != session.ID {
+		return NewMultiSigError(InvalidSessionErr, fmt.Errorf("message of session %s", msg.SessionID))
+	}
+	round := session.Round()
+	if msg.Round != round {
+		return NewMultiSigError(InvalidRoundErr, fmt.Errorf("message of round %d, session at round %d", msg.Round, round))
+	}
+	position := session.position(msg.Index)
+	if position < 0 {
+		return NewMultiSigError(InvalidParticipantErr, fmt.Errorf("participant %d is not a signer", msg.Index))
+	}
+	if session.Rounds[round-1][position] != nil {
+		return NewMultiSigError(DuplicatedMessageErr, fmt.Errorf("participant %d", msg.Index))
+	}
+	if round == SignRound6 && len(session.Messages) != session.NumMessages {
+		return NewMultiSigError(InvalidRoundErr, errors.New("messages to sign are not set"))
+	}
+	var err error
+	switch round {
+	case SignRound1:
+		err = session.checkRound1(msg)
+	case SignRound2:
+		err = session.checkRound2(msg, position, session.others(position))
+	case SignRound3:
+		err = session.checkRound3(msg, position, session.others(position))
+	case SignRound4:
+		err = session.checkRound4(msg, position)
+	case SignRound5:
+		err = session.checkRound5(msg)
+	case SignRound6:
+		err = session.checkRound6(msg, position)
+	}
+	if err != nil {
+		return err
+	}
+	session.Rounds[round-1][position] = msg
+	return nil
+}
+
+func checkPoints(points [][]byte, size int) error {
+	if len(points) != size {
+		return NewMultiSigError(InvalidMessageErr, fmt.Errorf("%d points instead of %d", len(points), size))
+	}
+	for _, point := range points {
+		if _, err := new(privacy.Point).FromBytesS(point); err != nil {
+			return NewMultiSigError(InvalidMessageErr, err)
+		}
+	}
+	return nil
+}
+
+func checkScalars(scalars [][]byte, size int) error {
+	if len(scalars) != size {
+		return NewMultiSigError(InvalidMessageErr, fmt.Errorf("%d scalars instead of %d", len(scalars), size))
+	}
+	for _, scalar := range scalars {
+		if len(scalar) != privacy.Ed25519KeySize || !new(privacy.Scalar).FromBytesS(scalar).ScalarValid() {
+			return NewMultiSigError(InvalidMessageErr, errors.New("invalid scalar"))
+		}
+	}
+	return nil
+}
+
+func (session SigningSession) checkRound1(msg *SigningMessage) error {
+	publicKey := PaillierPublicKey{N: new(big.Int).SetBytes(msg.PaillierN)}
+	if publicKey.N.BitLen() != paillierKeyBits {
+		return NewMultiSigError(InvalidMessageErr, fmt.Errorf("Paillier key of %d bits", publicKey.N.BitLen()))
+	}
+	if msg.ModulusProof == nil || msg.PedersenProof == nil {
+		return NewMultiSigError(InvalidMessageErr, errors.New("missing Paillier key proofs"))
+	}
+	context := session.keyProofContext(msg.Index)
+	if err := msg.ModulusProof.verify(publicKey.N, context); err != nil {
+		return NewMultiSigError(PaillierErr, err)
+	}
+	params := ringPedersen{N: publicKey.N, S: new(big.Int).SetBytes(msg.PedersenS), T: new(big.Int).SetBytes(msg.PedersenT)}
+	if err := msg.PedersenProof.verify(params, context); err != nil {
+		return NewMultiSigError(PaillierErr, err)
+	}
+	return nil
+}
+
+// checkRound2 checks the encrypted shares of the signer at position and the
+// proofs it sent to the signers at verifiers
+func (session SigningSession) checkRound2(msg *SigningMessage, position int, verifiers []int) error {
+	numCoins := len(session.SNDerivators)
+	if len(msg.EncShares) != numCoins || len(msg.EncProofs) != numCoins || len(msg.FactorProofs) != len(session.Signers) {
+		return NewMultiSigError(InvalidMessageErr, errors.New("wrong number of encrypted shares or proofs"))
+	}
+	publicKey := session.paillierKey(position)
+	for _, encShare := range msg.EncShares {
+		if !publicKey.isValidCipherText(new(big.Int).SetBytes(encShare)) {
+			return NewMultiSigError(InvalidMessageErr, errors.New("invalid encrypted share"))
+		}
+	}
+	if err := checkPoints(msg.Gammas, numCoins); err != nil {
+		return err
+	}
+	for _, l := range verifiers {
+		params := session.ringPedersen(l)
+		if msg.FactorProofs[l] == nil {
+			return NewMultiSigError(InvalidMessageErr, fmt.Errorf("missing factor proof for participant %d", session.Signers[l]))
+		}
+		if err := msg.FactorProofs[l].verify(publicKey.N, params, session.proofContext(position, l, 0)); err != nil {
+			return NewMultiSigError(PaillierErr, err)
+		}
+		for j, encShare := range msg.EncShares {
+			if len(msg.EncProofs[j]) != len(session.Signers) || msg.EncProofs[j][l] == nil {
+				return NewMultiSigError(InvalidMessageErr, fmt.Errorf("missing encryption proof for participant %d", session.Signers[l]))
+			}
+			if err := msg.EncProofs[j][l].verify(publicKey, new(big.Int).SetBytes(encShare), params, session.proofContext(position, l, j)); err != nil {
+				return NewMultiSigError(PaillierErr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRound3 checks the conversions the signer at position sent to the
+// signers at verifiers and their proofs
+func (session SigningSession) checkRound3(msg *SigningMessage, position int, verifiers []int) error {
+	numCoins := len(session.SNDerivators)
+	if len(msg.MtA) != numCoins || len(msg.MtAProofs) != numCoins || len(msg.NonceCommitment) != common.HashSize {
+		return NewMultiSigError(InvalidMessageErr, errors.New("wrong number of conversions or nonce commitment"))
+	}
+	for j := range msg.MtA {
+		if len(msg.MtA[j]) != len(session.Signers) || len(msg.MtAProofs[j]) != len(session.Signers) {
+			return NewMultiSigError(InvalidMessageErr, errors.New("wrong number of conversions"))
+		}
+		gamma, err := new(privacy.Point).FromBytesS(session.Rounds[SignRound2-1][position].Gammas[j])
+		if err != nil {
+			return NewMultiSigError(InvalidMessageErr, err)
+		}
+		for _, l := range verifiers {
+			publicKey := session.paillierKey(l)
+			conversion := new(big.Int).SetBytes(msg.MtA[j][l])
+			if !publicKey.isValidCipherText(conversion) || msg.MtAProofs[j][l] == nil {
+				return NewMultiSigError(InvalidMessageErr, errors.New("invalid conversion"))
+			}
+			encShare := new(big.Int).SetBytes(session.Rounds[SignRound2-1][l].EncShares[j])
+			if err := msg.MtAProofs[j][l].verify(publicKey, encShare, conversion, gamma, session.ringPedersen(l), session.proofContext(position, l, j)); err != nil {
+				return NewMultiSigError(PaillierErr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPreviousRound checks again the proofs of the round before round the
+// signer at position relies on, before answering round: the Paillier keys of
+// the other signers, then the proofs they sent to this signer
+func (session SigningSession) checkPreviousRound(round int, position int) error {
+	if round <= SignRound1 || round > SignRound4 {
+		return nil
+	}
+	for _, l := range session.others(position) {
+		var err error
+		switch round {
+		case SignRound2:
+			err = session.checkRound1(session.Rounds[SignRound1-1][l])
+		case SignRound3:
+			err = session.checkRound2(session.Rounds[SignRound2-1][l], l, []int{position})
+		case SignRound4:
+			err = session.checkRound3(session.Rounds[SignRound3-1][l], l, []int{position})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// others returns the positions of the signers other than the one at
+// position
+func (session SigningSession) others(position int) []int {
+	others := make([]int, 0, len(session.Signers)-1)
+	for l := range session.Signers {
+		if l != position {
+			others = append(others, l)
+		}
+	}
+	return others
+}
+
+// paillierKey returns the Paillier key of the signer at position, once round
+// 1 is done
+func (session SigningSession) paillierKey(position int) PaillierPublicKey {
+	return PaillierPublicKey{N: new(big.Int).SetBytes(session.Rounds[SignRound1-1][position].PaillierN)}
+}
+
+// ringPedersen returns the ring-Pedersen parameters of the signer at
+// position, once round 1 is done
+func (session SigningSession) ringPedersen(position int) ringPedersen {
+	msg := session.Rounds[SignRound1-1][position]
+	return ringPedersen{
+		N: new(big.Int).SetBytes(msg.PaillierN),
+		S: new(big.Int).SetBytes(msg.PedersenS),
+		T: new(big.Int).SetBytes(msg.PedersenT),
+	}
+}
+
+// keyProofContext binds the proofs of the Paillier key of signer index to the
+// session
+func (session SigningSession) keyProofContext(index uint32) []byte {
+	return append([]byte(session.ID), common.Uint32ToBytes(index)...)
+}
+
+// proofContext binds a proof of the signer at prover for the signer at
+// verifier about coin j to the session
+func (session SigningSession) proofContext(prover, verifier, j int) []byte {
+	context := session.keyProofContext(session.Signers[prover])
+	context = append(context, common.Uint32ToBytes(session.Signers[verifier])...)
+	return append(context, common.Uint32ToBytes(uint32(j))...)
+}
+
+func (session SigningSession) checkRound4(msg *SigningMessage, position int) error {
+	numCoins := len(session.SNDerivators)
+	if err := checkScalars(msg.Deltas, numCoins); err != nil {
+		return err
+	}
+	if err := checkPoints(msg.SeedNonces, numCoins); err != nil {
+		return err
+	}
+	if err := checkPoints(msg.OutputNonces, numCoins); err != nil {
+		return err
+	}
+	if err := checkPoints(msg.SigNonces, session.NumMessages); err != nil {
+		return err
+	}
+	commitment := nonceCommitment(&signerNonces{
+		SeedNonces:   msg.SeedNonces,
+		OutputNonces: msg.OutputNonces,
+		SigNonces:    msg.SigNonces,
+	})
+	if !bytes.Equal(commitment, session.Rounds[SignRound3-1][position].NonceCommitment) {
+		return NewMultiSigError(InvalidMessageErr, errors.New("nonces do not match their commitment"))
+	}
+	return nil
+}
+
+func (session SigningSession) checkRound5(msg *SigningMessage) error {
+	if err := checkScalars(msg.SeedResponses, len(session.SNDerivators)); err != nil {
+		return err
+	}
+	challenges, err := session.proofChallenges()
+	if err != nil {
+		return err
+	}
+	for j, challenge := range challenges {
+		if err := session.checkResponse(msg.Index, msg.SeedResponses[j], challenge, session.Rounds[SignRound4-1][session.position(msg.Index)].SeedNonces[j], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (session SigningSession) checkRound6(msg *SigningMessage, position int) error {
+	if err := checkScalars(msg.SigResponses, session.NumMessages); err != nil {
+		return err
+	}
+	challenges, err := session.sigChallenges()
+	if err != nil {
+		return err
+	}
+	for t, challenge := range challenges {
+		if err := session.checkResponse(msg.Index, msg.SigResponses[t], challenge, session.Rounds[SignRound4-1][position].SigNonces[t], true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkResponse checks the answer of a signer to a challenge against its
+// public share Y and its nonce T: G^z = T + e*lambda*Y for a serial number
+// proof, G^z + e*lambda*Y = T for a signature
+func (session SigningSession) checkResponse(index uint32, response []byte, challenge *privacy.Scalar, nonce []byte, isSignature bool) error {
+	publicShare, err := session.Group.publicShare(index)
+	if err != nil {
+		return err
+	}
+	noncePoint, _ := new(privacy.Point).FromBytesS(nonce)
+	exponent := new(privacy.Scalar).Mul(challenge, lagrangeCoefficient(index, session.Signers))
+	left := new(privacy.Point).ScalarMultBase(new(privacy.Scalar).FromBytesS(response))
+	right := new(privacy.Point).ScalarMult(publicShare, exponent)
+	if isSignature {
+		left.Add(left, right)
+		right = noncePoint
+	} else {
+		right.Add(right, noncePoint)
+	}
+	if !privacy.IsPointEqual(left, right) {
+		return NewMultiSigError(InvalidPartialSignatureErr, fmt.Errorf("answer of participant %d", index))
+	}
+	return nil
+}
+
+func sumPoints(msgs []*SigningMessage, points func(*SigningMessage) []byte) (*privacy.Point, error) {
+	sum := new(privacy.Point).Identity()
+	for _, msg := range msgs {
+		point, err := new(privacy.Point).FromBytesS(points(msg))
+		if err != nil {
+			return nil, NewMultiSigError(InvalidMessageErr, err)
+		}
+		sum.Add(sum, point)
+	}
+	return sum, nil
+}
+
+// gamma returns G^rho of coin j
+func (session SigningSession) gamma(j int) (*privacy.Point, error) {
+	return sumPoints(session.Rounds[SignRound2-1], func(msg *SigningMessage) []byte { return msg.Gammas[j] })
+}
+
+// invertedDelta returns 1/((sk+snd)*rho) of coin j
+func (session SigningSession) invertedDelta(j int) (*privacy.Scalar, error) {
+	delta := new(privacy.Scalar).FromUint64(0)
+	for _, msg := range session.Rounds[SignRound4-1] {
+		delta.Add(delta, new(privacy.Scalar).FromBytesS(msg.Deltas[j]))
+	}
+	if delta.IsZero() {
+		return nil, NewMultiSigError(InvalidMessageErr, errors.New("delta is zero"))
+	}
+	return new(privacy.Scalar).Invert(delta), nil
+}
+
+// SerialNumbers returns the serial numbers of the coins of the session, once
+// round 4 is done
+func (session SigningSession) SerialNumbers() ([]*privacy.Point, error) {
+	if !session.isRoundComplete(SignRound4) {
+		return nil, NewMultiSigError(InvalidRoundErr, errors.New("serial numbers are known after round 4"))
+	}
+	serialNumbers := make([]*privacy.Point, len(session.SNDerivators))
+	for j := range session.SNDerivators {
+		gamma, err := session.gamma(j)
+		if err != nil {
+			return nil, err
+		}
+		invertedDelta, err := session.invertedDelta(j)
+		if err != nil {
+			return nil, err
+		}
+		serialNumbers[j] = new(privacy.Point).ScalarMult(gamma, invertedDelta)
+	}
+	return serialNumbers, nil
+}
+
+// proofNonces returns the nonces tSeed and tOutput of the serial number proof
+// of coin j
+func (session SigningSession) proofNonces(j int) (*privacy.Point, *privacy.Point, error) {
+	msgs := session.Rounds[SignRound4-1]
+	seedNonce, err := sumPoints(msgs, func(msg *SigningMessage) []byte { return msg.SeedNonces[j] })
+	if err != nil {
+		return nil, nil, err
+	}
+	outputNonce, err := sumPoints(msgs, func(msg *SigningMessage) []byte { return msg.OutputNonces[j] })
+	if err != nil {
+		return nil, nil, err
+	}
+	invertedDelta, err := session.invertedDelta(j)
+	if err != nil {
+		return nil, nil, err
+	}
+	return seedNonce, outputNonce.ScalarMult(outputNonce, invertedDelta), nil
+}
+
+func (session SigningSession) proofChallenges() ([]*privacy.Scalar, error) {
+	challenges := make([]*privacy.Scalar, len(session.SNDerivators))
+	for j := range session.SNDerivators {
+		seedNonce, outputNonce, err := session.proofNonces(j)
+		if err != nil {
+			return nil, err
+		}
+		challenges[j] = utils.GenerateChallenge([][]byte{seedNonce.ToBytesS(), outputNonce.ToBytesS()})
+	}
+	return challenges, nil
+}
+
+// SerialNumberProofs returns the serial number proofs of the coins of the
+// session, once round 5 is done
+func (session SigningSession) SerialNumberProofs() ([]*serialnumbernoprivacy.SNNoPrivacyProof, error) {
+	if !session.isRoundComplete(SignRound5) {
+		return nil, NewMultiSigError(InvalidRoundErr, errors.New("serial number proofs are known after round 5"))
+	}
+	serialNumbers, err := session.SerialNumbers()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := new(privacy.Point).FromBytesS(session.Group.PublicKey)
+	if err != nil {
+		return nil, NewMultiSigError(InvalidSessionErr, err)
+	}
+	proofs := make([]*serialnumbernoprivacy.SNNoPrivacyProof, len(session.SNDerivators))
+	for j, snd := range session.SNDerivators {
+		seedNonce, outputNonce, err := session.proofNonces(j)
+		if err != nil {
+			return nil, err
+		}
+		response := new(privacy.Scalar).FromUint64(0)
+		for _, msg := range session.Rounds[SignRound5-1] {
+			response.Add(response, new(privacy.Scalar).FromBytesS(msg.SeedResponses[j]))
+		}
+		proofs[j] = new(serialnumbernoprivacy.SNNoPrivacyProof).Init()
+		proofs[j].Set(serialNumbers[j], publicKey, new(privacy.Scalar).FromBytesS(snd), seedNonce, outputNonce, response)
+		if valid, err := proofs[j].Verify(nil); !valid {
+			return nil, NewMultiSigError(InvalidPartialSignatureErr, err)
+		}
+	}
+	return proofs, nil
+}
+
+func (session SigningSession) sigNonce(t int) (*privacy.Point, error) {
+	return sumPoints(session.Rounds[SignRound4-1], func(msg *SigningMessage) []byte { return msg.SigNonces[t] })
+}
+
+// sigChallenges returns the challenges of the signatures of the messages,
+// computed as in privacy.SchnorrPrivateKey.Sign
+func (session SigningSession) sigChallenges() ([]*privacy.Scalar, error) {
+	challenges := make([]*privacy.Scalar, len(session.Messages))
+	for t, message := range session.Messages {
+		nonce, err := session.sigNonce(t)
+		if err != nil {
+			return nil, err
+		}
+		challenges[t] = privacy.HashToScalar(append(nonce.ToBytesS(), message...))
+	}
+	return challenges, nil
+}
+
+// Signatures returns the signatures of the messages by the group key, once
+// round 6 is done
+func (session SigningSession) Signatures() ([]*privacy.SchnSignature, error) {
+	if !session.IsComplete() {
+		return nil, NewMultiSigError(InvalidRoundErr, errors.New("signatures are known after round 6"))
+	}
+	challenges, err := session.sigChallenges()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := new(privacy.Point).FromBytesS(session.Group.PublicKey)
+	if err != nil {
+		return nil, NewMultiSigError(InvalidSessionErr, err)
+	}
+	verifyKey := new(privacy.SchnorrPublicKey)
+	verifyKey.Set(publicKey)
+	signatures := make([]*privacy.SchnSignature, len(challenges))
+	for t, challenge := range challenges {
+		response := new(privacy.Scalar).FromUint64(0)
+		for _, msg := range session.Rounds[SignRound6-1] {
+			response.Add(response, new(privacy.Scalar).FromBytesS(msg.SigResponses[t]))
+		}
+		signatures[t] = new(privacy.SchnSignature)
+		if err := signatures[t].SetBytes(append(challenge.ToBytesS(), response.ToBytesS()...)); err != nil {
+			return nil, NewMultiSigError(UnExpectedError, err)
+		}
+		if !verifyKey.Verify(signatures[t], session.Messages[t]) {
+			return nil, NewMultiSigError(InvalidPartialSignatureErr, fmt.Errorf("signature of message %d", t))
+		}
+	}
+	return signatures, nil
+}
+
+func bigToScalar(value *big.Int) *privacy.Scalar {
+	return privacy.BigIntToScalar(new(big.Int).Mod(value, curveOrder))
+}
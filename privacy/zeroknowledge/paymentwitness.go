@@ -111,7 +111,7 @@ func (wit *PaymentWitness) Init(PaymentWitnessParam PaymentWitnessParam) *privac
 	cmInputSK := privacy.PedCom.CommitAtIndex(wit.privateKey, randInputSK, privacy.PedersenPrivateKeyIndex)
 	wit.comInputSecretKey = new(privacy.Point).Set(cmInputSK)
 
-	// from BCHeightBreakPointFixRandShardCM, we fixed the randomness for shardID commitment
+	// from fork FixRandShardCommitment, we fixed the randomness for shardID commitment
 	// instead of generating it randomly.
 	//randInputShardID := privacy.RandomScalar()
 	randInputShardID := privacy.FixedRandomnessShardID
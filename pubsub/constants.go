@@ -16,6 +16,9 @@ const (
 	RequestShardBlockByHeightTopic  = "requestshardblockbyheighttopic"
 	RequestBeaconBlockByHeightTopic = "requestbeaconblockbyheighttopic"
 	RequestBeaconBlockByHashTopic   = "requestbeaconblockbyhashtopic"
+	NewBestViewTopic                = "newbestviewtopic"
+	FinalizedViewTopic              = "finalizedviewtopic"
+	ChainReorgTopic                 = "chainreorgtopic"
 	TestTopic                       = "testtopic"
 )
 
@@ -33,4 +36,7 @@ var Topics = []string{
 	RequestShardBlockByHeightTopic,
 	RequestShardBlockByHashTopic,
 	ShardBeststateTopic,
+	NewBestViewTopic,
+	FinalizedViewTopic,
+	ChainReorgTopic,
 }
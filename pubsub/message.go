@@ -5,6 +5,8 @@ type Message struct {
 
 	topic           string
 	unSendSubscribe []chan interface{}
+	// sequence is the order of the message among all the published messages
+	sequence uint64
 }
 
 func NewMessage(topic string, value interface{}) *Message {
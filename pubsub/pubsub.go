@@ -3,6 +3,7 @@ package pubsub
 import (
 	"errors"
 	"github.com/incognitochain/incognito-chain/common"
+	"sort"
 	"sync"
 	"time"
 )
@@ -19,14 +20,51 @@ type PubSubManager struct {
 	subscriberList map[string]map[uint]EventChannel // List of Subscriber
 	messageBroker  map[string][]*Message            // Message pool
 	idGenerator    uint                             // id generator for event
+	sequence       uint64                           // number of published messages
+	deliveries     map[uint]*delivery               // pending messages of every subscriber
 	cond           *sync.Cond
 }
 
+// delivery sends the messages of a subscriber in the order they were
+// published, without blocking the event channel when the subscriber is slow
+type delivery struct {
+	event   EventChannel
+	lock    sync.Mutex
+	queue   []*Message
+	running bool
+}
+
+func (d *delivery) push(message *Message) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.queue = append(d.queue, message)
+	if !d.running {
+		d.running = true
+		go d.run()
+	}
+}
+
+func (d *delivery) run() {
+	for {
+		d.lock.Lock()
+		if len(d.queue) == 0 {
+			d.running = false
+			d.lock.Unlock()
+			return
+		}
+		message := d.queue[0]
+		d.queue = d.queue[1:]
+		d.lock.Unlock()
+		d.event.NotifyMessage(message)
+	}
+}
+
 func NewPubSubManager() *PubSubManager {
 	pubSubManager := &PubSubManager{
 		topicList:      Topics,
 		subscriberList: make(map[string]map[uint]EventChannel),
 		messageBroker:  make(map[string][]*Message),
+		deliveries:     make(map[uint]*delivery),
 		idGenerator:    0,
 		cond:           sync.NewCond(&sync.Mutex{}),
 	}
@@ -40,16 +78,22 @@ func NewPubSubManager() *PubSubManager {
 func (pubSubManager *PubSubManager) Start() {
 	for {
 		pubSubManager.cond.L.Lock()
+		pending := []*Message{}
 		for topic, messages := range pubSubManager.messageBroker {
-			for _, message := range messages {
-				if subMap, ok := pubSubManager.subscriberList[topic]; ok {
-					for _, event := range subMap {
-						go event.NotifyMessage(message)
+			pending = append(pending, messages...)
+			// delete message (if no thing subscribe for it then delete msg too)
+			pubSubManager.messageBroker[topic] = []*Message{}
+		}
+		// every subscriber receives the messages in the order they were published
+		sort.Slice(pending, func(i, j int) bool { return pending[i].sequence < pending[j].sequence })
+		for _, message := range pending {
+			if subMap, ok := pubSubManager.subscriberList[message.topic]; ok {
+				for id := range subMap {
+					if d, ok := pubSubManager.deliveries[id]; ok {
+						d.push(message)
 					}
 				}
 			}
-			// delete message (if no thing subscribe for it then delete msg too)
-			pubSubManager.messageBroker[topic] = []*Message{}
 		}
 		pubSubManager.cond.Wait()
 		pubSubManager.cond.L.Unlock()
@@ -72,6 +116,7 @@ func (pubSubManager *PubSubManager) RegisterNewSubscriber(topic string) (uint, E
 	}
 	id := pubSubManager.idGenerator
 	pubSubManager.subscriberList[topic][id] = cSubscribe
+	pubSubManager.deliveries[id] = &delivery{event: cSubscribe}
 	pubSubManager.idGenerator = id + 1
 	return id, cSubscribe, nil
 }
@@ -80,6 +125,8 @@ func (pubSubManager *PubSubManager) RegisterNewSubscriber(topic string) (uint, E
 func (pubSubManager *PubSubManager) PublishMessage(message *Message) {
 	pubSubManager.cond.L.Lock()
 	defer pubSubManager.cond.L.Unlock()
+	message.sequence = pubSubManager.sequence
+	pubSubManager.sequence++
 	pubSubManager.messageBroker[message.topic] = append(pubSubManager.messageBroker[message.topic], message)
 	pubSubManager.cond.Signal()
 }
@@ -90,6 +137,7 @@ func (pubSubManager *PubSubManager) Unsubscribe(topic string, subId uint) {
 	if subMap, ok := pubSubManager.subscriberList[topic]; ok {
 		if _, ok := subMap[subId]; ok {
 			delete(subMap, subId)
+			delete(pubSubManager.deliveries, subId)
 		}
 	}
 }
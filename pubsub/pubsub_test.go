@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewMessage(t *testing.T) {
@@ -117,6 +118,31 @@ func TestMessageBroken(t *testing.T) {
 	pubsubManager.Unsubscribe(TestTopic, id)
 	return
 }
+func TestMessageOrder(t *testing.T) {
+	var pubsubManager = NewPubSubManager()
+	go pubsubManager.Start()
+	_, event, err := pubsubManager.RegisterNewSubscriber(TestTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// more messages than the buffer of the event channel
+	n := 3 * ChanWorkLoad
+	go func() {
+		for i := 0; i < n; i++ {
+			pubsubManager.PublishMessage(NewMessage(TestTopic, i))
+		}
+	}()
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-event:
+			if msg.Value.(int) != i {
+				t.Fatalf("message %d received at %d", msg.Value.(int), i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %d not received", i)
+		}
+	}
+}
 func TestHasTopic(t *testing.T) {
 	var pubsubManager = NewPubSubManager()
 	if !pubsubManager.HasTopic(NewBeaconBlockTopic) {
@@ -0,0 +1,14 @@
+package ethbridge
+
+import "github.com/incognitochain/incognito-chain/common"
+
+type RelayerLogger struct {
+	log common.Logger
+}
+
+func (logger *RelayerLogger) Init(inst common.Logger) {
+	logger.log = inst
+}
+
+// Global instant to use
+var Logger = RelayerLogger{}
@@ -0,0 +1,219 @@
+package ethbridge
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/pkg/errors"
+)
+
+// Kind is the type of a bridge instruction the relayer submits
+type Kind int
+
+const (
+	KindBurn               Kind = iota // burn to withdraw from the vault
+	KindBurnForDepositToSC             // burn to deposit to a smart contract through the vault
+	KindBeaconSwap                     // new beacon committee
+	KindBridgeSwap                     // new bridge committee
+)
+
+var kindNames = map[Kind]string{
+	KindBurn:               "burn",
+	KindBurnForDepositToSC: "burn for deposit to SC",
+	KindBeaconSwap:         "beacon swap",
+	KindBridgeSwap:         "bridge swap",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Job is a bridge instruction of a beacon block to submit to Ethereum
+type Job struct {
+	Kind         Kind
+	BeaconHeight uint64
+	TxID         common.Hash // burn tx of the burn kinds
+
+	attempts int
+}
+
+func (job *Job) String() string {
+	if job.Kind == KindBurn || job.Kind == KindBurnForDepositToSC {
+		return fmt.Sprintf("%v of tx %v at beacon height %v", job.Kind, job.TxID.String(), job.BeaconHeight)
+	}
+	return fmt.Sprintf("%v at beacon height %v", job.Kind, job.BeaconHeight)
+}
+
+// Proof is the proof of a bridge instruction as returned by the getburnproof
+// and get*swapproof RPCs
+type Proof struct {
+	Instruction  string // hex encoded instruction
+	BeaconHeight string // hex encoded 32 bytes height for the burns
+	BridgeHeight string
+
+	BeaconInstPath       []string
+	BeaconInstPathIsLeft []bool
+	BeaconInstRoot       string
+	BeaconBlkData        string
+	BeaconSigs           []string // hex encoded signatures (r, s, v)
+	BeaconSigIdxs        []int
+
+	BridgeInstPath       []string
+	BridgeInstPathIsLeft []bool
+	BridgeInstRoot       string
+	BridgeBlkData        string
+	BridgeSigs           []string
+	BridgeSigIdxs        []int
+}
+
+// The calls of the vault and of the incognito proxy contracts, the arrays of
+// 2 elements hold the proof on beacon then the proof on the bridge shard
+const (
+	vaultABIJSON = `[
+{"name":"withdraw","type":"function","stateMutability":"nonpayable","outputs":[],"inputs":[` + burnProofInputs + `]},
+{"name":"submitBurnProof","type":"function","stateMutability":"nonpayable","outputs":[],"inputs":[` + burnProofInputs + `]}
+]`
+	burnProofInputs = `{"name":"inst","type":"bytes"},{"name":"heights","type":"uint256[2]"},` + swapProofInputs
+	swapProofInputs = `{"name":"instPaths","type":"bytes32[][2]"},{"name":"instPathIsLefts","type":"bool[][2]"},{"name":"instRoots","type":"bytes32[2]"},{"name":"blkData","type":"bytes32[2]"},{"name":"sigIdxs","type":"uint256[][2]"},{"name":"sigVs","type":"uint8[][2]"},{"name":"sigRs","type":"bytes32[][2]"},{"name":"sigSs","type":"bytes32[][2]"}`
+
+	proxyABIJSON = `[
+{"name":"swapBeaconCommittee","type":"function","stateMutability":"nonpayable","outputs":[],"inputs":[{"name":"inst","type":"bytes"},{"name":"instPath","type":"bytes32[]"},{"name":"instPathIsLeft","type":"bool[]"},{"name":"instRoot","type":"bytes32"},{"name":"blkData","type":"bytes32"},{"name":"sigIdx","type":"uint256[]"},{"name":"sigV","type":"uint8[]"},{"name":"sigR","type":"bytes32[]"},{"name":"sigS","type":"bytes32[]"}]},
+{"name":"swapBridgeCommittee","type":"function","stateMutability":"nonpayable","outputs":[],"inputs":[{"name":"inst","type":"bytes"},` + swapProofInputs + `]}
+]`
+)
+
+var vaultABI, proxyABI abi.ABI
+
+func init() {
+	var err error
+	if vaultABI, err = abi.JSON(strings.NewReader(vaultABIJSON)); err != nil {
+		panic(err)
+	}
+	if proxyABI, err = abi.JSON(strings.NewReader(proxyABIJSON)); err != nil {
+		panic(err)
+	}
+}
+
+// instProof is the proof of an instruction in a block, decoded for a call
+type instProof struct {
+	path    [][32]byte
+	isLeft  []bool
+	root    [32]byte
+	blkData [32]byte
+	sigIdxs []*big.Int
+	sigVs   []uint8
+	sigRs   [][32]byte
+	sigSs   [][32]byte
+}
+
+func decodeInstProof(path []string, isLeft []bool, root, blkData string, sigs []string, sigIdxs []int) (*instProof, error) {
+	p := &instProof{isLeft: isLeft}
+	if p.isLeft == nil {
+		p.isLeft = []bool{}
+	}
+	var err error
+	if p.path, err = decodeHashes(path); err != nil {
+		return nil, errors.Wrap(err, "invalid inst path")
+	}
+	if p.root, err = decodeHash(root); err != nil {
+		return nil, errors.Wrap(err, "invalid inst root")
+	}
+	if p.blkData, err = decodeHash(blkData); err != nil {
+		return nil, errors.Wrap(err, "invalid block data")
+	}
+	p.sigIdxs = make([]*big.Int, len(sigIdxs))
+	for i, idx := range sigIdxs {
+		p.sigIdxs[i] = big.NewInt(int64(idx))
+	}
+	p.sigVs = make([]uint8, len(sigs))
+	p.sigRs = make([][32]byte, len(sigs))
+	p.sigSs = make([][32]byte, len(sigs))
+	for i, s := range sigs {
+		sig, err := hex.DecodeString(s)
+		if err != nil || len(sig) != 65 {
+			return nil, errors.Errorf("invalid signature %v", s)
+		}
+		copy(p.sigRs[i][:], sig[:32])
+		copy(p.sigSs[i][:], sig[32:64])
+		p.sigVs[i] = sig[64] + 27
+	}
+	return p, nil
+}
+
+// encodeCall returns the data of the call submitting the proof of a job, to
+// the vault for the burns and to the incognito proxy for the swaps
+func encodeCall(kind Kind, proof *Proof) ([]byte, error) {
+	inst, err := hex.DecodeString(proof.Instruction)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid instruction")
+	}
+	beacon, err := decodeInstProof(proof.BeaconInstPath, proof.BeaconInstPathIsLeft, proof.BeaconInstRoot, proof.BeaconBlkData, proof.BeaconSigs, proof.BeaconSigIdxs)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proof on beacon")
+	}
+	if kind == KindBeaconSwap {
+		return proxyABI.Pack("swapBeaconCommittee", inst, beacon.path, beacon.isLeft, beacon.root, beacon.blkData, beacon.sigIdxs, beacon.sigVs, beacon.sigRs, beacon.sigSs)
+	}
+
+	bridge, err := decodeInstProof(proof.BridgeInstPath, proof.BridgeInstPathIsLeft, proof.BridgeInstRoot, proof.BridgeBlkData, proof.BridgeSigs, proof.BridgeSigIdxs)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proof on bridge")
+	}
+	args := []interface{}{
+		[2][][32]byte{beacon.path, bridge.path},
+		[2][]bool{beacon.isLeft, bridge.isLeft},
+		[2][32]byte{beacon.root, bridge.root},
+		[2][32]byte{beacon.blkData, bridge.blkData},
+		[2][]*big.Int{beacon.sigIdxs, bridge.sigIdxs},
+		[2][]uint8{beacon.sigVs, bridge.sigVs},
+		[2][][32]byte{beacon.sigRs, bridge.sigRs},
+		[2][][32]byte{beacon.sigSs, bridge.sigSs},
+	}
+	if kind == KindBridgeSwap {
+		return proxyABI.Pack("swapBridgeCommittee", append([]interface{}{inst}, args...)...)
+	}
+
+	// the heights of the burn proofs are hex encoded 32 bytes integers
+	heights := [2]*big.Int{}
+	for i, h := range []string{proof.BeaconHeight, proof.BridgeHeight} {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid height")
+		}
+		heights[i] = new(big.Int).SetBytes(b)
+	}
+	method := "withdraw"
+	if kind == KindBurnForDepositToSC {
+		method = "submitBurnProof"
+	}
+	return vaultABI.Pack(method, append([]interface{}{inst, heights}, args...)...)
+}
+
+func decodeHash(s string) ([32]byte, error) {
+	h := [32]byte{}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return h, err
+	}
+	if len(b) != len(h) {
+		return h, errors.Errorf("%v bytes instead of 32", len(b))
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+func decodeHashes(s []string) ([][32]byte, error) {
+	res := make([][32]byte, len(s))
+	for i := range s {
+		h, err := decodeHash(s[i])
+		if err != nil {
+			return nil, err
+		}
+		res[i] = h
+	}
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package ethbridge
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+}
+
+func testHash(b byte) string {
+	return hex.EncodeToString([]byte(strings.Repeat(string([]byte{b}), 32)))
+}
+
+// testProof returns a proof as built by the bridge proof RPCs
+func testProof(inst string) *Proof {
+	sig := make([]byte, 65)
+	sig[0], sig[32], sig[64] = 1, 2, 1
+	return &Proof{
+		Instruction:          inst,
+		BeaconHeight:         hex.EncodeToString(common.AddPaddingBigInt(big.NewInt(10), 32)),
+		BridgeHeight:         hex.EncodeToString(common.AddPaddingBigInt(big.NewInt(20), 32)),
+		BeaconInstPath:       []string{testHash(1), testHash(2)},
+		BeaconInstPathIsLeft: []bool{true, false},
+		BeaconInstRoot:       testHash(3),
+		BeaconBlkData:        testHash(4),
+		BeaconSigs:           []string{hex.EncodeToString(sig)},
+		BeaconSigIdxs:        []int{2},
+		BridgeInstPath:       []string{testHash(5)},
+		BridgeInstPathIsLeft: []bool{true},
+		BridgeInstRoot:       testHash(6),
+		BridgeBlkData:        testHash(7),
+		BridgeSigs:           []string{hex.EncodeToString(sig), hex.EncodeToString(sig)},
+		BridgeSigIdxs:        []int{0, 3},
+	}
+}
+
+func TestEncodeBurnCall(t *testing.T) {
+	for kind, method := range map[Kind]string{KindBurn: "withdraw", KindBurnForDepositToSC: "submitBurnProof"} {
+		data, err := encodeCall(kind, testProof("aabb"))
+		assert.NoError(t, err)
+		assert.Equal(t, vaultABI.Methods[method].Id(), data[:4])
+
+		args := struct {
+			Inst            []byte
+			Heights         [2]*big.Int
+			InstPaths       [2][][32]byte
+			InstPathIsLefts [2][]bool
+			InstRoots       [2][32]byte
+			BlkData         [2][32]byte
+			SigIdxs         [2][]*big.Int
+			SigVs           [2][]uint8
+			SigRs           [2][][32]byte
+			SigSs           [2][][32]byte
+		}{}
+		assert.NoError(t, vaultABI.Methods[method].Inputs.Unpack(&args, data[4:]))
+		assert.Equal(t, []byte{0xaa, 0xbb}, args.Inst)
+		assert.Equal(t, [2]*big.Int{big.NewInt(10), big.NewInt(20)}, args.Heights)
+		assert.Len(t, args.InstPaths[0], 2)
+		assert.Len(t, args.InstPaths[1], 1)
+		assert.Equal(t, byte(5), args.InstPaths[1][0][0])
+		assert.Equal(t, [2][]bool{{true, false}, {true}}, args.InstPathIsLefts)
+		assert.Equal(t, byte(6), args.InstRoots[1][31])
+		assert.Equal(t, byte(4), args.BlkData[0][0])
+		assert.Equal(t, "[[2] [0 3]]", fmt.Sprint(args.SigIdxs))
+		// v is moved to 27/28 as ecrecover expects
+		assert.Equal(t, [2][]uint8{{28}, {28, 28}}, args.SigVs)
+		assert.Equal(t, byte(1), args.SigRs[0][0][0])
+		assert.Equal(t, byte(2), args.SigSs[1][1][0])
+	}
+}
+
+func TestEncodeSwapCall(t *testing.T) {
+	data, err := encodeCall(KindBeaconSwap, testProof("cc"))
+	assert.NoError(t, err)
+	assert.Equal(t, proxyABI.Methods["swapBeaconCommittee"].Id(), data[:4])
+	args := struct {
+		Inst           []byte
+		InstPath       [][32]byte
+		InstPathIsLeft []bool
+		InstRoot       [32]byte
+		BlkData        [32]byte
+		SigIdx         []*big.Int
+		SigV           []uint8
+		SigR           [][32]byte
+		SigS           [][32]byte
+	}{}
+	assert.NoError(t, proxyABI.Methods["swapBeaconCommittee"].Inputs.Unpack(&args, data[4:]))
+	assert.Equal(t, []byte{0xcc}, args.Inst)
+	assert.Len(t, args.InstPath, 2)
+	assert.Equal(t, byte(3), args.InstRoot[0])
+
+	data, err = encodeCall(KindBridgeSwap, testProof("cc"))
+	assert.NoError(t, err)
+	assert.Equal(t, proxyABI.Methods["swapBridgeCommittee"].Id(), data[:4])
+
+	invalid := testProof("cc")
+	invalid.BridgeSigs = []string{"00"}
+	_, err = encodeCall(KindBridgeSwap, invalid)
+	assert.Error(t, err)
+	// a beacon swap has no proof on bridge
+	_, err = encodeCall(KindBeaconSwap, invalid)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,184 @@
+// Package ethbridge relays the bridge instructions of the beacon chain to
+// Ethereum: the proofs of the burns are submitted to the vault contract and
+// the proofs of the committee swaps to the incognito proxy contract.
+package ethbridge
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// ProofSource reads the bridge instructions of the beacon blocks and builds
+// their proofs, it is rpcserver.BridgeProofSource on a node
+type ProofSource interface {
+	FinalBeaconHeight() uint64
+	// Jobs returns the jobs of the bridge instructions of a beacon block
+	Jobs(height uint64) ([]*Job, error)
+	// Proof fails while the proof of a job cannot be built, e.g. a burn not
+	// in a bridge block yet
+	Proof(job *Job) (*Proof, error)
+}
+
+const (
+	DefaultInterval    = 10 * time.Second
+	DefaultMaxAttempts = 60
+)
+
+type Config struct {
+	Source     ProofSource
+	Transactor Transactor
+	Vault      ethcommon.Address
+	Proxy      ethcommon.Address
+
+	// StartHeight is the first beacon height scanned when there is no state
+	// file, 0 starts after the final beacon block
+	StartHeight uint64
+	// StateFile keeps the beacon height to scan from across restarts
+	StateFile   string
+	Interval    time.Duration
+	MaxAttempts int // attempts of a job before it is dropped
+}
+
+// Relayer scans the final beacon blocks for bridge instructions and submits
+// their proofs in the order of the blocks, a job is only tried once the jobs
+// before it are done: the proofs signed by a new committee need its swap.
+type Relayer struct {
+	config Config
+	next   uint64 // next beacon height to scan
+	jobs   []*Job // jobs not done, in the order of the blocks
+}
+
+func NewRelayer(config Config) (*Relayer, error) {
+	if config.Source == nil || config.Transactor == nil {
+		return nil, errors.New("relayer needs a proof source and a transactor")
+	}
+	if config.Vault == (ethcommon.Address{}) || config.Proxy == (ethcommon.Address{}) {
+		return nil, errors.New("relayer needs the addresses of the vault and of the incognito proxy")
+	}
+	if config.Interval == 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	r := &Relayer{config: config, next: config.StartHeight}
+	if config.StateFile != "" {
+		data, err := ioutil.ReadFile(config.StateFile)
+		switch {
+		case err == nil:
+			if r.next, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err != nil {
+				return nil, errors.Wrapf(err, "invalid relayer state file %v", config.StateFile)
+			}
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+	if r.next == 0 {
+		r.next = config.Source.FinalBeaconHeight() + 1
+	}
+	return r, nil
+}
+
+// Start polls the beacon chain until quit is closed
+func (r *Relayer) Start(quit <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-quit
+		cancel()
+	}()
+	Logger.log.Infof("Bridge relayer started at beacon height %v", r.next)
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Poll(ctx); err != nil {
+			Logger.log.Error(err)
+		}
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll scans the final beacon blocks not scanned yet and submits the proofs
+// of the jobs not done
+func (r *Relayer) Poll(ctx context.Context) error {
+	final := r.config.Source.FinalBeaconHeight()
+	for ; r.next <= final; r.next++ {
+		jobs, err := r.config.Source.Jobs(r.next)
+		if err != nil {
+			return errors.Wrapf(err, "cannot get beacon block %v", r.next)
+		}
+		r.jobs = append(r.jobs, jobs...)
+	}
+
+	for len(r.jobs) > 0 && ctx.Err() == nil {
+		job := r.jobs[0]
+		err := r.submit(ctx, job)
+		if err != nil && errors.Cause(err) != ErrReverted {
+			job.attempts++
+			if job.attempts < r.config.MaxAttempts {
+				Logger.log.Warnf("Cannot submit %v, attempt %v: %v", job, job.attempts, err)
+				break
+			}
+			Logger.log.Errorf("Dropped %v after %v attempts: %v", job, job.attempts, err)
+		} else if err != nil {
+			// e.g. submitted by another relayer
+			Logger.log.Infof("Skipped %v: %v", job, err)
+		}
+		r.jobs = r.jobs[1:]
+	}
+	return r.saveState()
+}
+
+// Pending returns the jobs not done
+func (r *Relayer) Pending() []Job {
+	res := []Job{}
+	for _, job := range r.jobs {
+		res = append(res, *job)
+	}
+	return res
+}
+
+func (r *Relayer) submit(ctx context.Context, job *Job) error {
+	proof, err := r.config.Source.Proof(job)
+	if err != nil {
+		return errors.Wrap(err, "cannot build proof")
+	}
+	data, err := encodeCall(job.Kind, proof)
+	if err != nil {
+		return err
+	}
+	to := r.config.Vault
+	if job.Kind == KindBeaconSwap || job.Kind == KindBridgeSwap {
+		to = r.config.Proxy
+	}
+	receipt, err := r.config.Transactor.Transact(ctx, to, data)
+	if err != nil {
+		return err
+	}
+	Logger.log.Infof("Submitted %v in tx %v", job, receipt.TxHash.Hex())
+	return nil
+}
+
+// saveState writes the height of the first job not done, the jobs of its
+// block are scanned again after a restart and those done are reverted by the
+// contracts
+func (r *Relayer) saveState() error {
+	if r.config.StateFile == "" {
+		return nil
+	}
+	height := r.next
+	if len(r.jobs) > 0 {
+		height = r.jobs[0].BeaconHeight
+	}
+	return ioutil.WriteFile(r.config.StateFile, []byte(strconv.FormatUint(height, 10)), 0600)
+}
@@ -0,0 +1,164 @@
+package ethbridge
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSource struct {
+	final    uint64
+	jobs     map[uint64][]*Job
+	notReady map[common.Hash]int // polls before the proof of a burn is ready
+}
+
+func (s *testSource) FinalBeaconHeight() uint64 {
+	return s.final
+}
+
+func (s *testSource) Jobs(height uint64) ([]*Job, error) {
+	return s.jobs[height], nil
+}
+
+func (s *testSource) Proof(job *Job) (*Proof, error) {
+	if s.notReady[job.TxID] > 0 {
+		s.notReady[job.TxID]--
+		return nil, errors.New("burning confirm not found")
+	}
+	// the instruction tells the jobs apart
+	return testProof(hex.EncodeToString([]byte{byte(job.Kind), byte(job.BeaconHeight), job.TxID[0]})), nil
+}
+
+type testCall struct {
+	to   ethcommon.Address
+	inst string
+}
+
+type testTransactor struct {
+	calls []testCall
+	errs  []error // errors of the next calls
+}
+
+func (t *testTransactor) Transact(ctx context.Context, to ethcommon.Address, data []byte) (*types.Receipt, error) {
+	if len(t.errs) > 0 {
+		err := t.errs[0]
+		t.errs = t.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.calls = append(t.calls, testCall{to: to, inst: hex.EncodeToString(callInst(data))})
+	return &types.Receipt{Status: types.ReceiptStatusSuccessful}, nil
+}
+
+// callInst returns the instruction of a call, its first argument
+func callInst(data []byte) []byte {
+	args := data[4:]
+	offset := new(big.Int).SetBytes(args[:32]).Uint64()
+	length := new(big.Int).SetBytes(args[offset : offset+32]).Uint64()
+	return args[offset+32 : offset+32+length]
+}
+
+var (
+	testVault = ethcommon.HexToAddress("0x1111")
+	testProxy = ethcommon.HexToAddress("0x2222")
+)
+
+func TestRelayer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tx1, tx2 := common.HashH([]byte{1}), common.HashH([]byte{2})
+	source := &testSource{
+		final: 6,
+		jobs: map[uint64][]*Job{
+			5: {{Kind: KindBeaconSwap, BeaconHeight: 5}},
+			6: {{Kind: KindBurn, BeaconHeight: 6, TxID: tx1}, {Kind: KindBridgeSwap, BeaconHeight: 6}},
+			7: {{Kind: KindBurnForDepositToSC, BeaconHeight: 7, TxID: tx2}},
+		},
+		notReady: map[common.Hash]int{tx1: 1},
+	}
+	transactor := &testTransactor{}
+	config := Config{Source: source, Transactor: transactor, Vault: testVault, Proxy: testProxy, StartHeight: 5, StateFile: filepath.Join(dir, "state")}
+	r, err := NewRelayer(config)
+	assert.NoError(t, err)
+
+	// the burn is not in a bridge block yet, the jobs after it wait
+	assert.NoError(t, r.Poll(context.Background()))
+	assert.Equal(t, []testCall{{testProxy, hex.EncodeToString([]byte{byte(KindBeaconSwap), 5, 0})}}, transactor.calls)
+	assert.Len(t, r.Pending(), 2)
+	state, _ := ioutil.ReadFile(config.StateFile)
+	assert.Equal(t, "6", string(state))
+
+	source.final = 7
+	assert.NoError(t, r.Poll(context.Background()))
+	assert.Equal(t, []testCall{
+		{testProxy, hex.EncodeToString([]byte{byte(KindBeaconSwap), 5, 0})},
+		{testVault, hex.EncodeToString([]byte{byte(KindBurn), 6, tx1[0]})},
+		{testProxy, hex.EncodeToString([]byte{byte(KindBridgeSwap), 6, 0})},
+		{testVault, hex.EncodeToString([]byte{byte(KindBurnForDepositToSC), 7, tx2[0]})},
+	}, transactor.calls)
+	assert.Empty(t, r.Pending())
+	state, _ = ioutil.ReadFile(config.StateFile)
+	assert.Equal(t, "8", string(state))
+
+	// a restart goes on from the state file
+	r, err = NewRelayer(config)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), r.next)
+
+	_, err = NewRelayer(Config{Source: source, Transactor: transactor, Vault: testVault})
+	assert.Error(t, err)
+	r, err = NewRelayer(Config{Source: source, Transactor: transactor, Vault: testVault, Proxy: testProxy})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), r.next)
+}
+
+func TestRelayerDropsJobs(t *testing.T) {
+	source := &testSource{
+		final: 1,
+		jobs:  map[uint64][]*Job{1: {{Kind: KindBeaconSwap, BeaconHeight: 1}, {Kind: KindBridgeSwap, BeaconHeight: 1}, {Kind: KindBurn, BeaconHeight: 1}}},
+	}
+	// the first call reverts, the second fails until it is dropped
+	transactor := &testTransactor{errs: []error{ErrReverted, errors.New("timeout"), errors.New("timeout")}}
+	r, err := NewRelayer(Config{Source: source, Transactor: transactor, Vault: testVault, Proxy: testProxy, StartHeight: 1, MaxAttempts: 2})
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Poll(context.Background()))
+	assert.Equal(t, []Job{{Kind: KindBridgeSwap, BeaconHeight: 1, attempts: 1}, {Kind: KindBurn, BeaconHeight: 1}}, r.Pending())
+	assert.NoError(t, r.Poll(context.Background()))
+	assert.Empty(t, r.Pending())
+	assert.Equal(t, []testCall{{testVault, hex.EncodeToString([]byte{byte(KindBurn), 1, 0})}}, transactor.calls)
+}
+
+func TestRelayerSimulated(t *testing.T) {
+	backend, key := newTestBackend(t)
+	proxy := backend.deployReverting(t, key)
+	source := &testSource{
+		final: 1,
+		jobs:  map[uint64][]*Job{1: {{Kind: KindBridgeSwap, BeaconHeight: 1}, {Kind: KindBurn, BeaconHeight: 1}}},
+	}
+	transactor := NewEthTransactor(backend, key, testChainID, testTransactorConfig())
+	r, err := NewRelayer(Config{Source: source, Transactor: transactor, Vault: testVault, Proxy: proxy, StartHeight: 1})
+	assert.NoError(t, err)
+
+	// the swap reverts on the proxy and is skipped, the burn is submitted
+	assert.NoError(t, r.Poll(context.Background()))
+	assert.Empty(t, r.Pending())
+	assert.Len(t, backend.sent, 1)
+	assert.Equal(t, testVault, *backend.sent[0].To())
+	assert.Equal(t, vaultABI.Methods["withdraw"].Id(), backend.sent[0].Data()[:4])
+	receipt, err := backend.TransactionReceipt(context.Background(), backend.sent[0].Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
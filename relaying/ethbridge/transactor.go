@@ -0,0 +1,230 @@
+package ethbridge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// Transactor sends the calls of the relayer to Ethereum, it returns once the
+// call is mined. Calls that would revert, e.g. proofs already submitted,
+// fail with ErrReverted and are not retried by the relayer.
+type Transactor interface {
+	Transact(ctx context.Context, to ethcommon.Address, data []byte) (*types.Receipt, error)
+}
+
+// ErrReverted is the error of the calls reverted by the contract
+var ErrReverted = errors.New("call reverted")
+
+// Backend is the Ethereum node EthTransactor sends the calls to, an
+// ethclient.Client or a simulated backend
+type Backend interface {
+	PendingNonceAt(ctx context.Context, account ethcommon.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash ethcommon.Hash) (*types.Receipt, error)
+}
+
+// TransactorConfig sets the gas of the calls and how they are retried
+type TransactorConfig struct {
+	GasLimitMargin  uint64        // percents added to the estimated gas
+	MaxGasPrice     *big.Int      // cap of the gas price, nil for no cap
+	GasPriceBump    uint64        // percents added to the gas price of a call resent
+	ReceiptTimeout  time.Duration // time before a call not mined is resent
+	ReceiptInterval time.Duration // interval of the polls of the receipts
+	MaxResends      int           // resends of a call before giving up
+}
+
+var DefaultTransactorConfig = TransactorConfig{
+	GasLimitMargin:  20,
+	GasPriceBump:    15,
+	ReceiptTimeout:  3 * time.Minute,
+	ReceiptInterval: 5 * time.Second,
+	MaxResends:      5,
+}
+
+// EthTransactor signs the calls with a key and manages its nonce: the calls
+// are sent one at a time, a call not mined is resent with the same nonce and
+// a higher gas price
+type EthTransactor struct {
+	backend Backend
+	key     *ecdsa.PrivateKey
+	from    ethcommon.Address
+	signer  types.Signer
+	config  TransactorConfig
+
+	mtx      sync.Mutex
+	nonce    uint64
+	hasNonce bool
+}
+
+// NewEthTransactor returns a transactor signing for the chain chainID
+func NewEthTransactor(backend Backend, key *ecdsa.PrivateKey, chainID *big.Int, config TransactorConfig) *EthTransactor {
+	return &EthTransactor{
+		backend: backend,
+		key:     key,
+		from:    crypto.PubkeyToAddress(key.PublicKey),
+		signer:  types.NewEIP155Signer(chainID),
+		config:  config,
+	}
+}
+
+// From returns the address the calls are sent from
+func (t *EthTransactor) From() ethcommon.Address {
+	return t.from
+}
+
+func (t *EthTransactor) Transact(ctx context.Context, to ethcommon.Address, data []byte) (*types.Receipt, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	gas, err := t.backend.EstimateGas(ctx, ethereum.CallMsg{From: t.from, To: &to, Data: data})
+	if err != nil {
+		if isRevertError(err) {
+			return nil, errors.Wrap(ErrReverted, err.Error())
+		}
+		return nil, errors.Wrap(err, "cannot estimate gas")
+	}
+	gas += gas * t.config.GasLimitMargin / 100
+	gasPrice, err := t.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get gas price")
+	}
+	gasPrice = t.capGasPrice(gasPrice)
+	if !t.hasNonce {
+		if err := t.syncNonce(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	sent := []ethcommon.Hash{}
+	send := true
+	for resends := 0; ; resends++ {
+		if send {
+			tx, err := types.SignTx(types.NewTransaction(t.nonce, to, big.NewInt(0), gas, gasPrice, data), t.signer, t.key)
+			if err != nil {
+				return nil, err
+			}
+			if err := t.backend.SendTransaction(ctx, tx); err != nil {
+				switch {
+				case isNonceError(err):
+					// the account sent a tx out of the relayer
+					Logger.log.Warnf("Nonce %v of %v is used, resyncing it: %v", t.nonce, t.from.Hex(), err)
+					if err := t.syncNonce(ctx); err != nil {
+						return nil, err
+					}
+				case isUnderpriced(err):
+					gasPrice = t.bumpGasPrice(gasPrice)
+				default:
+					t.hasNonce = false
+					return nil, errors.Wrap(err, "cannot send tx")
+				}
+				if resends >= t.config.MaxResends {
+					return nil, errors.Wrap(err, "cannot send tx")
+				}
+				continue
+			}
+			sent = append(sent, tx.Hash())
+			Logger.log.Infof("Sent tx %v with nonce %v and gas price %v", tx.Hash().Hex(), t.nonce, gasPrice)
+		}
+
+		receipt, err := t.waitReceipt(ctx, sent)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			t.nonce++
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return receipt, errors.Wrapf(ErrReverted, "tx %v", receipt.TxHash.Hex())
+			}
+			return receipt, nil
+		}
+		if resends >= t.config.MaxResends {
+			// the nonce may still be used by one of the txs sent
+			t.hasNonce = false
+			return nil, errors.Errorf("tx with nonce %v not mined after %v resends", t.nonce, resends)
+		}
+		// the txs sent stay valid, there is nothing to resend once the gas
+		// price is capped
+		bumped := t.bumpGasPrice(gasPrice)
+		send = bumped.Cmp(gasPrice) != 0
+		gasPrice = bumped
+	}
+}
+
+// waitReceipt waits for the receipt of one of the txs sent with the current
+// nonce, it returns no receipt when none is mined before ReceiptTimeout
+func (t *EthTransactor) waitReceipt(ctx context.Context, sent []ethcommon.Hash) (*types.Receipt, error) {
+	timeout := time.NewTimer(t.config.ReceiptTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(t.config.ReceiptInterval)
+	defer ticker.Stop()
+	for {
+		for _, hash := range sent {
+			receipt, err := t.backend.TransactionReceipt(ctx, hash)
+			if err == nil && receipt != nil {
+				return receipt, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			t.hasNonce = false
+			return nil, ctx.Err()
+		case <-timeout.C:
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *EthTransactor) syncNonce(ctx context.Context) error {
+	nonce, err := t.backend.PendingNonceAt(ctx, t.from)
+	if err != nil {
+		return errors.Wrap(err, "cannot get nonce")
+	}
+	t.nonce = nonce
+	t.hasNonce = true
+	return nil
+}
+
+func (t *EthTransactor) bumpGasPrice(gasPrice *big.Int) *big.Int {
+	bump := new(big.Int).Mul(gasPrice, big.NewInt(int64(t.config.GasPriceBump)))
+	bump.Div(bump, big.NewInt(100))
+	if bump.Sign() == 0 {
+		bump.SetInt64(1)
+	}
+	return t.capGasPrice(new(big.Int).Add(gasPrice, bump))
+}
+
+func (t *EthTransactor) capGasPrice(gasPrice *big.Int) *big.Int {
+	if t.config.MaxGasPrice != nil && gasPrice.Cmp(t.config.MaxGasPrice) > 0 {
+		return new(big.Int).Set(t.config.MaxGasPrice)
+	}
+	return gasPrice
+}
+
+// isRevertError returns whether the node failed a gas estimation because the
+// call always reverts
+func isRevertError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "always failing transaction") || strings.Contains(msg, "revert")
+}
+
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "invalid nonce") || strings.Contains(msg, "known transaction")
+}
+
+func isUnderpriced(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "underpriced")
+}
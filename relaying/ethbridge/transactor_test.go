@@ -0,0 +1,155 @@
+package ethbridge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var testChainID = params.AllEthashProtocolChanges.ChainID
+
+// testBackend is a simulated backend mining the txs it gets, it fails the
+// txs with a used nonce as a node does and drops the first drop txs
+type testBackend struct {
+	*backends.SimulatedBackend
+	drop int
+	sent []*types.Transaction
+}
+
+func newTestBackend(t *testing.T) (*testBackend, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	alloc := core.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(1e18)}}
+	return &testBackend{SimulatedBackend: backends.NewSimulatedBackend(alloc, 8000000)}, key
+}
+
+func (b *testBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	from, err := types.Sender(types.NewEIP155Signer(testChainID), tx)
+	if err != nil {
+		return err
+	}
+	if nonce, _ := b.PendingNonceAt(ctx, from); tx.Nonce() < nonce {
+		return errors.New("nonce too low")
+	}
+	b.sent = append(b.sent, tx)
+	if b.drop > 0 {
+		b.drop--
+		return nil
+	}
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+// deployReverting deploys a contract reverting all its calls
+func (b *testBackend) deployReverting(t *testing.T, key *ecdsa.PrivateKey) ethcommon.Address {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := b.PendingNonceAt(context.Background(), from)
+	assert.NoError(t, err)
+	// the code returns the runtime code PUSH1 0 PUSH1 0 REVERT
+	code := ethcommon.FromHex("0x6005600c60003960056000f360006000fd")
+	tx, err := types.SignTx(types.NewContractCreation(nonce, big.NewInt(0), 100000, big.NewInt(1), code), types.NewEIP155Signer(testChainID), key)
+	assert.NoError(t, err)
+	assert.NoError(t, b.SimulatedBackend.SendTransaction(context.Background(), tx))
+	b.Commit()
+	return crypto.CreateAddress(from, nonce)
+}
+
+func testTransactorConfig() TransactorConfig {
+	config := DefaultTransactorConfig
+	config.ReceiptTimeout = 50 * time.Millisecond
+	config.ReceiptInterval = 5 * time.Millisecond
+	return config
+}
+
+func TestEthTransactor(t *testing.T) {
+	backend, key := newTestBackend(t)
+	transactor := NewEthTransactor(backend, key, testChainID, testTransactorConfig())
+	to := ethcommon.HexToAddress("0x1234")
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		receipt, err := transactor.Transact(ctx, to, []byte{1, 2, 3})
+		assert.NoError(t, err)
+		assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	}
+	assert.Len(t, backend.sent, 2)
+	for i, tx := range backend.sent {
+		assert.Equal(t, uint64(i), tx.Nonce())
+		assert.Equal(t, to, *tx.To())
+		assert.Equal(t, []byte{1, 2, 3}, tx.Data())
+	}
+	// the estimation of the gas has a margin
+	estimated, err := backend.EstimateGas(ctx, ethereum.CallMsg{From: transactor.From(), To: &to, Data: []byte{1, 2, 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, estimated*120/100, backend.sent[0].Gas())
+
+	// the calls reverting are not sent
+	reverting := backend.deployReverting(t, key)
+	_, err = transactor.Transact(ctx, reverting, []byte{1})
+	assert.Equal(t, ErrReverted, pkgerrors.Cause(err))
+	assert.Len(t, backend.sent, 2)
+}
+
+func TestEthTransactorNonce(t *testing.T) {
+	backend, key := newTestBackend(t)
+	transactor := NewEthTransactor(backend, key, testChainID, testTransactorConfig())
+	to := ethcommon.HexToAddress("0x1234")
+	ctx := context.Background()
+	_, err := transactor.Transact(ctx, to, nil)
+	assert.NoError(t, err)
+
+	// the key is used out of the transactor
+	tx, err := types.SignTx(types.NewTransaction(1, to, big.NewInt(1), 21000, big.NewInt(1), nil), types.NewEIP155Signer(testChainID), key)
+	assert.NoError(t, err)
+	assert.NoError(t, backend.SendTransaction(ctx, tx))
+
+	receipt, err := transactor.Transact(ctx, to, nil)
+	assert.NoError(t, err)
+	mined, _, err := backend.TransactionByHash(ctx, receipt.TxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), mined.Nonce())
+}
+
+func TestEthTransactorResend(t *testing.T) {
+	backend, key := newTestBackend(t)
+	transactor := NewEthTransactor(backend, key, testChainID, testTransactorConfig())
+	to := ethcommon.HexToAddress("0x1234")
+	ctx := context.Background()
+
+	// the first tx is lost, it is sent again with a higher gas price
+	backend.drop = 1
+	receipt, err := transactor.Transact(ctx, to, nil)
+	assert.NoError(t, err)
+	assert.Len(t, backend.sent, 2)
+	assert.Equal(t, backend.sent[0].Nonce(), backend.sent[1].Nonce())
+	assert.Equal(t, big.NewInt(1), backend.sent[0].GasPrice())
+	assert.Equal(t, big.NewInt(2), backend.sent[1].GasPrice())
+	assert.Equal(t, backend.sent[1].Hash(), receipt.TxHash)
+
+	// the gas price is capped, the tx is not sent again
+	config := testTransactorConfig()
+	config.MaxGasPrice = big.NewInt(1)
+	config.MaxResends = 2
+	transactor = NewEthTransactor(backend, key, testChainID, config)
+	backend.drop = 1
+	backend.sent = nil
+	_, err = transactor.Transact(ctx, to, nil)
+	assert.Error(t, err)
+	assert.Len(t, backend.sent, 1)
+}
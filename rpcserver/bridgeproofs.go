@@ -0,0 +1,99 @@
+package rpcserver
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/relaying/ethbridge"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// BridgeProofSource finds the bridge instructions of the beacon blocks and
+// builds their proofs the same way as the getburnproof and get*swapproof
+// RPCs, for the bridge relayer
+type BridgeProofSource struct {
+	blockChain      *blockchain.BlockChain
+	consensusEngine ConsensusEngine
+}
+
+func NewBridgeProofSource(bc *blockchain.BlockChain, ce ConsensusEngine) *BridgeProofSource {
+	return &BridgeProofSource{blockChain: bc, consensusEngine: ce}
+}
+
+// FinalBeaconHeight returns the height of the final beacon block, the
+// instructions of the blocks above it can still be reverted
+func (source *BridgeProofSource) FinalBeaconHeight() uint64 {
+	return source.blockChain.BeaconChain.GetFinalViewState().BeaconHeight
+}
+
+// Jobs returns the jobs of the beacon block at height: the burning confirms
+// beacon adds for the bridge shard (see pickBurningConfirmInstruction) and the
+// confirms of the committee swaps (see pickBridgeSwapConfirmInst)
+func (source *BridgeProofSource) Jobs(height uint64) ([]*ethbridge.Job, error) {
+	beaconBlocks, err := blockchain.FetchBeaconBlockFromHeight(source.blockChain, height, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(beaconBlocks) == 0 {
+		return nil, fmt.Errorf("cannot find beacon block with height %d", height)
+	}
+	jobs := []*ethbridge.Job{}
+	for _, inst := range beaconBlocks[0].Body.Instructions {
+		if len(inst) == 0 {
+			continue
+		}
+		switch inst[0] {
+		case strconv.Itoa(metadata.BurningConfirmMeta), strconv.Itoa(metadata.BurningConfirmForDepositToSCMeta):
+			if len(inst) < 6 {
+				continue
+			}
+			txID, err := common.Hash{}.NewHashFromStr(inst[5])
+			if err != nil {
+				BLogger.log.Warnf("Burning confirm instruction at beacon height %v has an invalid tx id: %v", height, err)
+				continue
+			}
+			kind := ethbridge.KindBurn
+			if inst[0] == strconv.Itoa(metadata.BurningConfirmForDepositToSCMeta) {
+				kind = ethbridge.KindBurnForDepositToSC
+			}
+			jobs = append(jobs, &ethbridge.Job{Kind: kind, BeaconHeight: height, TxID: *txID})
+		case strconv.Itoa(metadata.BeaconSwapConfirmMeta):
+			jobs = append(jobs, &ethbridge.Job{Kind: ethbridge.KindBeaconSwap, BeaconHeight: height})
+		case strconv.Itoa(metadata.BridgeSwapConfirmMeta):
+			jobs = append(jobs, &ethbridge.Job{Kind: ethbridge.KindBridgeSwap, BeaconHeight: height})
+		}
+	}
+	return jobs, nil
+}
+
+// Proof returns the proof of a job, the proof of a burn fails until its
+// instruction is in a bridge block
+func (source *BridgeProofSource) Proof(job *ethbridge.Job) (*ethbridge.Proof, error) {
+	var proof *jsonresult.GetInstructionProof
+	var errProof *rpcservice.RPCError
+	switch job.Kind {
+	case ethbridge.KindBurn, ethbridge.KindBurnForDepositToSC:
+		height, err := rpcservice.BlockService{BlockChain: source.blockChain}.GetBurningConfirm(job.TxID)
+		if err != nil {
+			return nil, err
+		}
+		metaType := metadata.BurningConfirmMeta
+		if job.Kind == ethbridge.KindBurnForDepositToSC {
+			metaType = metadata.BurningConfirmForDepositToSCMeta
+		}
+		proof, errProof = getBurnProofByHeight(metaType, source.blockChain, source.consensusEngine, height, &job.TxID)
+	case ethbridge.KindBeaconSwap:
+		proof, errProof = buildBeaconSwapProof(job.BeaconHeight, source.blockChain, source.consensusEngine)
+	default:
+		proof, errProof = buildBridgeSwapProof(job.BeaconHeight, source.blockChain, source.consensusEngine)
+	}
+	if errProof != nil {
+		return nil, errProof
+	}
+	res := ethbridge.Proof(*proof)
+	return &res, nil
+}
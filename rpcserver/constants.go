@@ -15,6 +15,7 @@ const (
 	getNodeRole          = "getnoderole"
 	getInOutMessages     = "getinoutmessages"
 	getInOutMessageCount = "getinoutmessagecount"
+	getPeerReputations   = "getpeerreputations"
 
 	estimateFee              = "estimatefee"
 	estimateFeeWithEstimator = "estimatefeewithestimator"
@@ -255,9 +256,59 @@ const (
 	getCrossShardPoolInfo    = "getcrossshardpoolinfo"
 	getAllView               = "getallview"
 	getAllViewDetail         = "getallviewdetail"
+	getChainForkTree         = "getchainforktree"
 
 	// feature rewards
 	getRewardFeature = "getrewardfeature"
+
+	// storage
+	getStorageStats        = "getstoragestats"
+	startStorageCompaction = "startstoragecompaction"
+	stopStorageCompaction  = "stopstoragecompaction"
+
+	// version bits
+	getDeploymentInfo = "getdeploymentinfo"
+	getForkSchedule   = "getforkschedule"
+
+	// light client
+	getBeaconHeaders       = "getbeaconheaders"
+	getShardHeaders        = "getshardheaders"
+	getCommitteeKeys       = "getcommitteekeys"
+	getTxProof             = "gettxproof"
+	getLightStatus         = "getlightstatus"
+	verifyLightTx          = "verifylighttx"
+	verifyLightShardHeader = "verifylightshardheader"
+
+	// logging
+	getLogLevels = "getloglevels"
+	setLogLevels = "setloglevels"
+
+	// tx lifecycle
+	getTxLifecycle = "gettxlifecycle"
+
+	// health
+	getSyncStatus = "getsyncstatus"
+
+	// supply audit
+	auditSupply = "auditsupply"
+
+	// time-locked txs
+	createRawTimeLockedTransaction                   = "createrawtimelockedtransaction"
+	createAndSendTimeLockedTransaction               = "createandsendtimelockedtransaction"
+	createRawTimeLockedPrivacyCustomTokenTransaction = "createrawtimelockedprivacycustomtokentransaction"
+	getHeldTransactions                              = "getheldtransactions"
+	addScheduledPayment                              = "addscheduledpayment"
+	listScheduledPayments                            = "listscheduledpayments"
+	removeScheduledPayment                           = "removescheduledpayment"
+	sendScheduledPayments                            = "sendscheduledpayments"
+
+	// multisig
+	createMultiSigTransaction = "createmultisigtransaction"
+
+	// wallet accounts
+	createPathAccount      = "createpathaccount"
+	discoverAccounts       = "discoveraccounts"
+	importWatchOnlyAccount = "importwatchonlyaccount"
 )
 
 const (
@@ -279,4 +330,6 @@ const (
 	subcribeBeaconBestState                     = "subcribebeaconbeststate"
 	subcribeBeaconPoolBeststate                 = "subcribebeaconpoolbeststate"
 	subcribeShardPoolBeststate                  = "subcribeshardpoolbeststate"
+	subcribeChainReorg                          = "subcribechainreorg"
+	subcribeFinalizedBlock                      = "subcribefinalizedblock"
 )
@@ -45,6 +45,8 @@ type HttpServer struct {
 	walletService     *rpcservice.WalletService
 	portal            *rpcservice.PortalService
 	synkerService     *rpcservice.SynkerService
+	storageService    *rpcservice.StorageService
+	lightService      *rpcservice.LightService
 }
 
 func (httpServer *HttpServer) Init(config *RpcServerConfig) {
@@ -74,7 +76,8 @@ func (httpServer *HttpServer) Init(config *RpcServerConfig) {
 		TxMemPool: httpServer.config.TxMemPool,
 	}
 	httpServer.networkService = &rpcservice.NetworkService{
-		ConnMgr: httpServer.config.ConnMgr,
+		ConnMgr:    httpServer.config.ConnMgr,
+		Reputation: httpServer.config.Reputation,
 	}
 	httpServer.txService = &rpcservice.TxService{
 		BlockChain:   httpServer.config.BlockChain,
@@ -93,6 +96,16 @@ func (httpServer *HttpServer) Init(config *RpcServerConfig) {
 	httpServer.portal = &rpcservice.PortalService{
 		BlockChain: httpServer.config.BlockChain,
 	}
+	httpServer.storageService = &rpcservice.StorageService{
+		DB:         httpServer.config.Database,
+		MempoolDB:  httpServer.config.DatabaseMempool,
+		BTCDataDir: httpServer.config.BTCDataDir,
+		Compactor:  httpServer.config.StorageCompactor,
+	}
+	httpServer.lightService = &rpcservice.LightService{
+		BlockChain: httpServer.config.BlockChain,
+		Client:     httpServer.config.LightClient,
+	}
 }
 
 // Start is used by rpcserver.go to start the rpc listener.
@@ -282,7 +295,7 @@ func (httpServer *HttpServer) ProcessRpcRequest(w http.ResponseWriter, r *http.R
 			}
 		}
 		if jsonErr == nil {
-			if request.Method == "downloadbackup" {
+			if request.Method == "downloadbackup" && httpServer.config.NodeMode != common.NodeModeLight {
 				httpServer.handleDownloadBackup(conn, request.Params)
 				return
 			}
@@ -290,8 +303,12 @@ func (httpServer *HttpServer) ProcessRpcRequest(w http.ResponseWriter, r *http.R
 			// Attempt to parse the JSON-RPC request into a known concrete
 			// command.
 			command := HttpHandler[request.Method]
+			if httpServer.config.NodeMode == common.NodeModeLight {
+				// a light node has no chain data for the other commands
+				command = LightHttpHandler[request.Method]
+			}
 			if command == nil {
-				if isLimitedUser {
+				if isLimitedUser && httpServer.config.NodeMode != common.NodeModeLight {
 					command = LimitedHttpHandler[request.Method]
 				} else {
 					result = nil
@@ -299,7 +316,12 @@ func (httpServer *HttpServer) ProcessRpcRequest(w http.ResponseWriter, r *http.R
 				}
 			}
 			if command != nil {
+				start := time.Now()
 				result, jsonErr = command(httpServer, request.Params, closeChan)
+				rpcTimer(request.Method).UpdateSince(start)
+				if jsonErr.(*rpcservice.RPCError) != nil {
+					rpcErrorCounter(request.Method).Inc(1)
+				}
 			} else {
 				jsonErr = rpcservice.NewRPCError(rpcservice.RPCMethodNotFoundError, errors.New("Method not found: "+request.Method))
 			}
@@ -2,6 +2,7 @@ package rpcserver
 
 import (
 	"fmt"
+	"github.com/incognitochain/incognito-chain/incdb"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 	"github.com/pkg/errors"
 	"io"
@@ -31,7 +32,11 @@ func (httpServer *HttpServer) handleGetLatestBackup(params interface{}, closeCha
 		if !ok {
 			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("chainName is invalid"))
 		}
-		epoch, _ := httpServer.config.BlockChain.GetBeaconChainDatabase().LatestBackup(fmt.Sprintf("../../backup/%v", chainName))
+		backuper, ok := httpServer.config.BlockChain.GetBeaconChainDatabase().(incdb.Backuper)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, errors.New("database engine does not support backup"))
+		}
+		epoch, _ := backuper.LatestBackup(fmt.Sprintf("../../backup/%v", chainName))
 		return struct {
 			LatestEpoch int
 		}{
@@ -49,10 +54,14 @@ func (httpServer *HttpServer) handleDownloadBackup(conn net.Conn, params interfa
 		if !ok {
 			return
 		}
+		backuper, ok := httpServer.config.BlockChain.GetBeaconChainDatabase().(incdb.Backuper)
+		if !ok {
+			return
+		}
 		var fd *os.File
 		var err error
 		if len(paramArray) == 1 {
-			_, filepath := httpServer.config.BlockChain.GetBeaconChainDatabase().LatestBackup(fmt.Sprintf("../../backup/%v", chainName))
+			_, filepath := backuper.LatestBackup(fmt.Sprintf("../../backup/%v", chainName))
 			fd, err = os.Open(filepath)
 			if err != nil {
 				fmt.Println(err)
@@ -64,7 +73,7 @@ func (httpServer *HttpServer) handleDownloadBackup(conn net.Conn, params interfa
 			if !ok {
 				return
 			}
-			_, filepath := httpServer.config.BlockChain.GetBeaconChainDatabase().LatestBackup(fmt.Sprintf("../../backup/%v", otherChain))
+			_, filepath := backuper.LatestBackup(fmt.Sprintf("../../backup/%v", otherChain))
 			fd, err = os.Open(filepath)
 			if err != nil {
 				fmt.Println(err)
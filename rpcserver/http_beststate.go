@@ -143,6 +143,19 @@ func (httpServer *HttpServer) handleGetCandidateList(params interface{}, closeCh
 
 // handleGetCommitteeList - return current committee in network
 func (httpServer *HttpServer) handleGetCommitteeList(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1 (optional): beacon height, default is current committee
+	beaconHeight, rpcErr := rpcservice.ParseOptionalHeight(common.InterfaceSlice(params), 0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if beaconHeight != 0 {
+		result, err := httpServer.blockService.GetCommitteeListAtHeight(beaconHeight)
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.GetClonedBeaconBestStateError, err)
+		}
+		return result, nil
+	}
+
 	clonedBeaconBestState, err := httpServer.blockService.GetBeaconBestState()
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.GetClonedBeaconBestStateError, err)
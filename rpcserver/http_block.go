@@ -102,7 +102,12 @@ func (httpServer *HttpServer) handleRetrieveBlockByHeight(params interface{}, cl
 			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("verbosity is invalid"))
 		}
 
-		result, err := httpServer.blockService.RetrieveShardBlockByHeight(uint64(blockHeight), int(shardID), verbosity)
+		// param #4 (optional): view selector - best (default), final, block hash or block height
+		selector, err := rpcservice.ParseOptionalViewSelector(paramArray, 3)
+		if err != nil {
+			return nil, err
+		}
+		result, err := httpServer.blockService.RetrieveShardBlockByHeight(uint64(blockHeight), int(shardID), verbosity, selector)
 		if err != nil {
 			return nil, err
 		}
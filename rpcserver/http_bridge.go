@@ -3,6 +3,7 @@ package rpcserver
 import (
 	"encoding/json"
 
+	"github.com/incognitochain/incognito-chain/blockchain/supplyaudit"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
 	"github.com/incognitochain/incognito-chain/metadata"
@@ -220,6 +221,81 @@ func (httpServer *HttpServer) handleGetAllBridgeTokens(params interface{}, close
 	return allBridgeTokens, nil
 }
 
+// The auditsupply RPC replays at most maxAuditSupplyBlocks beacon blocks and opens the state every minAuditSupplyRangeSize
+// blocks at most, the auditsupply command of cmd audits the whole history
+const (
+	maxAuditSupplyBlocks    = 100000
+	minAuditSupplyRangeSize = 100
+)
+
+// handleAuditSupply - replay the issuing and burning instructions of a beacon height range and report the pTokens whose supply
+// differs from the bridge token amounts, the custodian holdings or the given external balances
+func (httpServer *HttpServer) handleAuditSupply(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
+	}
+
+	// param #1: from beacon height
+	fromHeight, ok := arrayParams[0].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("From height is invalid"))
+	}
+	// param #2: to beacon height, 0 is the final beacon block
+	toHeight := float64(0)
+	if len(arrayParams) > 1 {
+		if toHeight, ok = arrayParams[1].(float64); !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("To height is invalid"))
+		}
+	}
+	// param #3: number of beacon blocks between two comparisons
+	rangeSize := float64(supplyaudit.DefaultRangeSize)
+	if len(arrayParams) > 2 {
+		if rangeSize, ok = arrayParams[2].(float64); !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Range size is invalid"))
+		}
+	}
+	if rangeSize < minAuditSupplyRangeSize {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.Errorf("Range size is below %v", minAuditSupplyRangeSize))
+	}
+	if toHeight == 0 {
+		toHeight = float64(httpServer.config.BlockChain.BeaconChain.GetFinalView().GetHeight())
+	}
+	if fromHeight < 1 {
+		fromHeight = 1
+	}
+	if toHeight >= fromHeight && toHeight-fromHeight >= maxAuditSupplyBlocks {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.Errorf("Height range is over %v blocks", maxAuditSupplyBlocks))
+	}
+	config := supplyaudit.Config{
+		Source:    supplyaudit.NewChainSource(httpServer.config.BlockChain),
+		RangeSize: uint64(rangeSize),
+		Stop:      closeChan,
+	}
+	// param #4: balances of the external vaults by pToken id, in the unit of the pToken
+	if len(arrayParams) > 3 && arrayParams[3] != nil {
+		balancesData, ok := arrayParams[3].(map[string]interface{})
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("External balances are invalid"))
+		}
+		balances := supplyaudit.Balances{}
+		for tokenID, balance := range balancesData {
+			amount, ok := balance.(float64)
+			if !ok {
+				return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.Errorf("External balance of %v is invalid", tokenID))
+			}
+			balances[tokenID] = uint64(amount)
+		}
+		config.External = balances
+	}
+
+	report, err := supplyaudit.Audit(config, uint64(fromHeight), uint64(toHeight))
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.AuditSupplyError, err)
+	}
+	return report, nil
+}
+
 func (httpServer *HttpServer) handleGetETHHeaderByHash(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
 	if arrayParams == nil || len(arrayParams) < 1 {
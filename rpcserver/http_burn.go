@@ -8,6 +8,7 @@ import (
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 	"github.com/pkg/errors"
 )
@@ -53,29 +54,34 @@ func retrieveBurnProof(
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, fmt.Errorf("proof of tx not found"))
 	}
-	return getBurnProofByHeight(burningMetaType, httpServer, height, txID)
+	proof, errProof := getBurnProofByHeight(burningMetaType, httpServer.GetBlockchain(), httpServer.config.ConsensusEngine, height, txID)
+	if errProof != nil {
+		return nil, errProof
+	}
+	return proof, nil
 }
 
 func getBurnProofByHeight(
 	burningMetaType int,
-	httpServer *HttpServer,
+	bc *blockchain.BlockChain,
+	ce ConsensusEngine,
 	height uint64,
 	txID *common.Hash,
-) (interface{}, *rpcservice.RPCError) {
+) (*jsonresult.GetInstructionProof, *rpcservice.RPCError) {
 
 	// Get bridge block and corresponding beacon blocks
-	bridgeBlock, beaconBlocks, err := getShardAndBeaconBlocks(height, httpServer.GetBlockchain())
+	bridgeBlock, beaconBlocks, err := getShardAndBeaconBlocks(height, bc)
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
 	}
 	// Get proof of instruction on bridge
-	bridgeInstProof, err := getBurnProofOnBridge(burningMetaType, txID, bridgeBlock, httpServer.config.ConsensusEngine)
+	bridgeInstProof, err := getBurnProofOnBridge(burningMetaType, txID, bridgeBlock, ce)
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
 	}
 	fmt.Println("bridgeInstProof", bridgeInstProof)
 	// Get proof of instruction on beacon
-	beaconInstProof, err := getBurnProofOnBeacon(bridgeInstProof.inst, beaconBlocks, httpServer.config.ConsensusEngine)
+	beaconInstProof, err := getBurnProofOnBeacon(bridgeInstProof.inst, beaconBlocks, ce)
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
 	}
@@ -87,7 +93,8 @@ func getBurnProofByHeight(
 	fmt.Println("beaconHeight", beaconHeight)
 	//decodedInst := hex.EncodeToString(blockchain.DecodeInstruction(bridgeInstProof.inst))
 
-	return buildProofResult(decodedInst, beaconInstProof, bridgeInstProof, beaconHeight, bridgeHeight), nil
+	proof := buildProofResult(decodedInst, beaconInstProof, bridgeInstProof, beaconHeight, bridgeHeight)
+	return &proof, nil
 }
 
 // getBurnProofOnBridge finds a beacon committee swap instruction in a given bridge block and returns its proof
@@ -0,0 +1,19 @@
+package rpcserver
+
+import (
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+/*
+handleGetDeploymentInfo - RPC returns the version bits state of every consensus deployment known by this node
+*/
+func (httpServer *HttpServer) handleGetDeploymentInfo(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return httpServer.blockService.GetDeploymentInfo()
+}
+
+/*
+handleGetForkSchedule - RPC returns the activation height or epoch of every consensus rule change of the network
+*/
+func (httpServer *HttpServer) handleGetForkSchedule(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return httpServer.blockService.GetForkSchedule()
+}
@@ -2,7 +2,10 @@ package rpcserver
 
 import (
 	"errors"
+	"time"
+
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metrics/health"
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 )
@@ -100,6 +103,52 @@ func (httpServer *HttpServer) handleGetConnectionCount(params interface{}, close
 	return result, nil
 }
 
+/*
+handleGetPeerReputations - RPC returns the reputation scores of the peers and
+whether they are banned.
+*/
+func (httpServer *HttpServer) handleGetPeerReputations(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	result := httpServer.networkService.GetPeerReputations()
+	return result, nil
+}
+
+/*
+handleGetSyncStatus - RPC returns the heights of the chains against the best heights of the peers, the time since their
+last block, the consensus participation of the node and the results of the health and readiness checks
+*/
+func (httpServer *HttpServer) handleGetSyncStatus(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	result := jsonresult.GetSyncStatusResult{Chains: []jsonresult.ChainSyncStatus{}}
+	live := health.Live(nil)
+	ready := health.Ready(nil)
+	result.Healthy, result.Ready, result.Checks = live.OK(), ready.OK(), ready.Checks
+	if httpServer.config.Syncker == nil {
+		return result, nil
+	}
+	now := time.Now().Unix()
+	for _, status := range httpServer.config.Syncker.GetChainSyncStatus() {
+		chain := jsonresult.ChainSyncStatus{
+			ChainID:       status.ChainID,
+			Chain:         status.Chain,
+			IsSync:        status.IsSync,
+			IsCatchUp:     status.IsCatchUp,
+			BestHeight:    status.BestHeight,
+			FinalHeight:   status.FinalHeight,
+			PeerHeight:    status.PeerHeight,
+			Peers:         status.Peers,
+			Lag:           status.Lag,
+			LastBlockTime: status.LastBlockTime,
+		}
+		if status.LastBlockTime > 0 {
+			chain.SecondsSinceLastBlock = now - status.LastBlockTime
+		}
+		if httpServer.config.Server != nil {
+			chain.MiningStatus = httpServer.config.Server.GetChainMiningStatus(status.ChainID)
+		}
+		result.Chains = append(result.Chains, chain)
+	}
+	return result, nil
+}
+
 // handleGetActiveShards - return active shard num
 func (httpServer *HttpServer) handleGetActiveShards(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	activeShards := httpServer.blockService.GetActiveShards()
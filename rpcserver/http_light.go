@@ -0,0 +1,131 @@
+package rpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// lightParams reads the numbers of the params array
+func lightParams(params interface{}, n int) ([]uint64, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != n {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("wrong number of params"))
+	}
+	res := []uint64{}
+	for i := range arrayParams {
+		value, ok := arrayParams[i].(float64)
+		if !ok || value < 0 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param is not a number"))
+		}
+		res = append(res, uint64(value))
+	}
+	return res, nil
+}
+
+// lightContext returns a context canceled when the client closes the
+// connection
+func lightContext(closeChan <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-closeChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+/*
+handleGetBeaconHeaders - RPC returns the final beacon blocks between two heights without their bodies, for light clients
+*/
+func (httpServer *HttpServer) handleGetBeaconHeaders(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1: from height, param #2: to height
+	heights, rpcErr := lightParams(params, 2)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return httpServer.lightService.GetBeaconHeaders(heights[0], heights[1])
+}
+
+/*
+handleGetShardHeaders - RPC returns the final blocks of a shard between two heights without their bodies, for light clients
+*/
+func (httpServer *HttpServer) handleGetShardHeaders(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1: shard id, param #2: from height, param #3: to height
+	values, rpcErr := lightParams(params, 3)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return httpServer.lightService.GetShardHeaders(byte(values[0]), values[1], values[2])
+}
+
+/*
+handleGetCommitteeKeys - RPC returns the committee lists committed to by the header of a beacon block, for light clients
+*/
+func (httpServer *HttpServer) handleGetCommitteeKeys(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1: beacon height
+	heights, rpcErr := lightParams(params, 1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return httpServer.lightService.GetCommitteeKeys(heights[0])
+}
+
+/*
+handleGetTxProof - RPC returns the Merkle proof of a transaction to the TxRoot of its shard block, for light clients
+*/
+func (httpServer *HttpServer) handleGetTxProof(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1: tx hash
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("tx hash is missing"))
+	}
+	txHash, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("tx hash is invalid"))
+	}
+	return httpServer.lightService.GetTxProof(txHash)
+}
+
+/*
+handleGetLightStatus - RPC returns the beacon chain followed by the light client of a node in light mode
+*/
+func (httpServer *HttpServer) handleGetLightStatus(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return httpServer.lightService.GetLightStatus()
+}
+
+/*
+handleVerifyLightTx - RPC verifies a transaction by its proof with the light client of a node in light mode
+*/
+func (httpServer *HttpServer) handleVerifyLightTx(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1: tx hash
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("tx hash is missing"))
+	}
+	txHash, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("tx hash is invalid"))
+	}
+	ctx, cancel := lightContext(closeChan)
+	defer cancel()
+	return httpServer.lightService.VerifyTx(ctx, txHash)
+}
+
+/*
+handleVerifyLightShardHeader - RPC verifies the header of a shard block with the light client of a node in light mode
+*/
+func (httpServer *HttpServer) handleVerifyLightShardHeader(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1: shard id, param #2: height
+	values, rpcErr := lightParams(params, 2)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	ctx, cancel := lightContext(closeChan)
+	defer cancel()
+	return httpServer.lightService.VerifyShardHeader(ctx, byte(values[0]), values[1])
+}
@@ -0,0 +1,42 @@
+package rpcserver
+
+import (
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+/*
+handleGetLogLevels - RPC returns the log level of every subsystem
+*/
+func (httpServer *HttpServer) handleGetLogLevels(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	if httpServer.config.LogLevels == nil {
+		return map[string]string{}, nil
+	}
+	return httpServer.config.LogLevels.GetLogLevels(), nil
+}
+
+/*
+handleSetLogLevels - RPC changes the log levels at runtime, with the syntax of --loglevel:
+a level for all subsystems or <subsystem>=<level>,<subsystem2>=<level>,...
+It returns the log level of every subsystem after the change
+*/
+func (httpServer *HttpServer) handleSetLogLevels(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// param #1: levels
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("expected one param, the log levels"))
+	}
+	levels, ok := arrayParams[0].(string)
+	if !ok || levels == "" {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("log levels is invalid"))
+	}
+	if httpServer.config.LogLevels == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.SetLogLevelsError, errors.New("log levels cannot be changed"))
+	}
+	if err := httpServer.config.LogLevels.SetLogLevels(levels); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.SetLogLevelsError, err)
+	}
+	return httpServer.config.LogLevels.GetLogLevels(), nil
+}
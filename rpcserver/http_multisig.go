@@ -0,0 +1,114 @@
+package rpcserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+/*
+handleCreateMultiSigTransaction - RPC creates the unsigned tx of a transfer from the payment address of a multisig group, the result is the file passed to the signers by walletctl multisigsign
+- Param #1: the group key, as listed by walletctl multisiglistaccounts
+- Param #2: the readonly key of the group
+- Param #3: list of PRV receivers {"<payment address>": amount}
+- Param #4: estimated fee nano PRV per kb, -1 to estimate it
+- Param #5: the indexes of the signers, e.g. [1, 3]
+- Param #6: token param {"TokenID": "...", "TokenReceivers": {"<payment address>": amount}}, null for a PRV transfer
+- Param #7: SNDerivators of the coins spent by txs of the group not in the chain state of the node yet, optional
+*/
+func (httpServer *HttpServer) handleCreateMultiSigTransaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 5 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 5 elements"))
+	}
+	groupData, err := json.Marshal(arrayParams[0])
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	group := new(multisig.GroupKey)
+	if err := json.Unmarshal(groupData, group); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("group key is invalid: %v", err))
+	}
+	readonlyKeyStr, ok := arrayParams[1].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("readonly key is invalid"))
+	}
+	readonlyKey, err := wallet.Base58CheckDeserialize(readonlyKeyStr)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	paymentInfos, _, err := transaction.CreateCustomTokenPrivacyReceiverArray(arrayParams[2])
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	unitFee, ok := arrayParams[3].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("estimate fee coin per kb is invalid"))
+	}
+	signersParam, ok := arrayParams[4].([]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("signers are invalid"))
+	}
+	signers := make([]uint32, len(signersParam))
+	for i, signer := range signersParam {
+		index, ok := signer.(float64)
+		if !ok || index < 1 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("signer %+v is invalid", signer))
+		}
+		signers[i] = uint32(index)
+	}
+
+	var tokenParams *transaction.CustomTokenPrivacyParamTx
+	if len(arrayParams) > 5 && arrayParams[5] != nil {
+		tokenParamsRaw, ok := arrayParams[5].(map[string]interface{})
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("token param is invalid"))
+		}
+		tokenID, ok := tokenParamsRaw["TokenID"].(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("token id is invalid"))
+		}
+		receivers, amount, err := transaction.CreateCustomTokenPrivacyReceiverArray(tokenParamsRaw["TokenReceivers"])
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+		}
+		tokenParams = &transaction.CustomTokenPrivacyParamTx{
+			PropertyID:  tokenID,
+			TokenTxType: transaction.CustomTokenTransfer,
+			Amount:      uint64(amount),
+			Receiver:    receivers,
+		}
+	}
+
+	spentSNDs := map[string]bool{}
+	if len(arrayParams) > 6 && arrayParams[6] != nil {
+		spentParam, ok := arrayParams[6].([]interface{})
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("spent SNDerivators are invalid"))
+		}
+		for _, item := range spentParam {
+			sndStr, ok := item.(string)
+			if !ok {
+				return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("SNDerivator %+v is invalid", item))
+			}
+			snd, _, err := base58.Base58Check{}.Decode(sndStr)
+			if err != nil {
+				return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+			}
+			spentSNDs[string(snd)] = true
+		}
+	}
+
+	multiSigTx, errBuild := httpServer.txService.BuildMultiSigTransaction(group, readonlyKey.KeySet.ReadonlyKey, signers, paymentInfos, int64(unitFee), tokenParams, spentSNDs, nil)
+	if errBuild != nil {
+		return nil, errBuild
+	}
+	return multiSigTx, nil
+}
@@ -479,9 +479,9 @@ func (httpServer *HttpServer) handleGetPDEState(params interface{}, closeChan <-
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
 	}
-	beaconHeight, ok := data["BeaconHeight"].(float64)
-	if !ok {
-		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Beacon height is invalid"))
+	beaconHeight, isFinal, rpcErr := httpServer.blockService.ResolveBeaconHeightFromPayload(data)
+	if rpcErr != nil {
+		return nil, rpcErr
 	}
 	beaconFeatureStateRootHash, err := httpServer.config.BlockChain.GetBeaconFeatureRootHash(httpServer.config.BlockChain.GetBeaconBestState(), uint64(beaconHeight))
 	if err != nil {
@@ -506,9 +506,13 @@ func (httpServer *HttpServer) handleGetPDEState(params interface{}, closeChan <-
 		PDEShares               map[string]uint64                   `json:"PDEShares"`
 		PDETradingFees          map[string]uint64                   `json:"PDETradingFees"`
 		BeaconTimeStamp         int64                               `json:"BeaconTimeStamp"`
+		BeaconHeight            uint64                              `json:"BeaconHeight"`
+		IsFinal                 bool                                `json:"IsFinal"`
 	}
 	result := CurrentPDEState{
 		BeaconTimeStamp:         beaconBlock.Header.Timestamp,
+		BeaconHeight:            beaconHeight,
+		IsFinal:                 isFinal,
 		PDEPoolPairs:            pdeState.PDEPoolPairs,
 		PDEShares:               pdeState.PDEShares,
 		WaitingPDEContributions: pdeState.WaitingPDEContributions,
@@ -541,6 +545,7 @@ func (httpServer *HttpServer) handleConvertNativeTokenToPrivacyToken(params inte
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
 	}
 	res, err := metadata.ConvertNativeTokenToPrivacyToken(
+		httpServer.config.BlockChain,
 		uint64(nativeTokenAmount),
 		tokenID,
 		int64(beaconHeight),
@@ -576,6 +581,7 @@ func (httpServer *HttpServer) handleConvertPrivacyTokenToNativeToken(params inte
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
 	}
 	res, err := metadata.ConvertPrivacyTokenToNativeToken(
+		httpServer.config.BlockChain,
 		uint64(privacyTokenAmount),
 		tokenID,
 		int64(beaconHeight),
@@ -155,3 +155,24 @@ func (httpServer *HttpServer) hanldeGetAllViewDetail(params interface{}, closeCh
 	}
 	return res, nil
 }
+
+func (httpServer *HttpServer) handleGetChainForkTree(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) != 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Invalid param, param 0 must be chainid (-1 for beacon)"))
+	}
+	chainID, ok := arrayParams[0].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID component invalid"))
+	}
+
+	if chainID == -1 {
+		beaconChain := httpServer.config.BlockChain.BeaconChain
+		return jsonresult.NewGetForkTreeResult(-1, beaconChain.GetBestView(), beaconChain.GetFinalView(), beaconChain.GetAllView()), nil
+	}
+	if chainID < 0 || int(chainID) >= len(httpServer.config.BlockChain.ShardChain) {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID component invalid"))
+	}
+	shardChain := httpServer.config.BlockChain.ShardChain[int(chainID)]
+	return jsonresult.NewGetForkTreeResult(int(chainID), shardChain.GetBestView(), shardChain.GetFinalView(), shardChain.GetAllView()), nil
+}
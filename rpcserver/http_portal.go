@@ -207,9 +207,9 @@ func (httpServer *HttpServer) handleGetPortalState(params interface{}, closeChan
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
 	}
-	beaconHeight, err := common.AssertAndConvertStrToNumber(data["BeaconHeight"])
-	if err != nil {
-		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	beaconHeight, isFinal, rpcErr := httpServer.blockService.ResolveBeaconHeightFromPayload(data)
+	if rpcErr != nil {
+		return nil, rpcErr
 	}
 
 	//beaconFeatureStateDB := httpServer.config.BlockChain.GetBeaconBestState().GetCopiedFeatureStateDB()
@@ -239,10 +239,14 @@ func (httpServer *HttpServer) handleGetPortalState(params interface{}, closeChan
 		LiquidationPool            map[string]*statedb.LiquidationPool       `json:"LiquidationPool"`
 		LockedCollateralForRewards *statedb.LockedCollateralState            `json:"LockedCollateralForRewards"`
 		BeaconTimeStamp            int64                                     `json:"BeaconTimeStamp"`
+		BeaconHeight               uint64                                    `json:"BeaconHeight"`
+		IsFinal                    bool                                      `json:"IsFinal"`
 	}
 
 	result := CurrentPortalState{
 		BeaconTimeStamp:            beaconBlock.Header.Timestamp,
+		BeaconHeight:               beaconHeight,
+		IsFinal:                    isFinal,
 		WaitingPortingRequests:     portalState.WaitingPortingRequests,
 		WaitingRedeemRequests:      portalState.WaitingRedeemRequests,
 		MatchedRedeemRequests:      portalState.MatchedRedeemRequests,
@@ -0,0 +1,27 @@
+package rpcserver
+
+import (
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+/*
+handleGetStorageStats - RPC returns disk usage of every database of the node (beacon, shards, mempool, btc relaying)
+and of each key prefix of beacon and shard databases
+*/
+func (httpServer *HttpServer) handleGetStorageStats(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return httpServer.storageService.GetStorageStats()
+}
+
+/*
+handleStartStorageCompaction - RPC starts a throttled background compaction of beacon, shard and mempool databases
+*/
+func (httpServer *HttpServer) handleStartStorageCompaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return httpServer.storageService.StartCompaction()
+}
+
+/*
+handleStopStorageCompaction - RPC cancels the running background compaction
+*/
+func (httpServer *HttpServer) handleStopStorageCompaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return httpServer.storageService.StopCompaction()
+}
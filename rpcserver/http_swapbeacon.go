@@ -55,9 +55,21 @@ func (httpServer *HttpServer) handleGetBeaconSwapProof(params interface{}, close
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("height param is invalid"))
 	}
-	beaconHeigh := uint64(heightParam)
+	proof, errProof := buildBeaconSwapProof(uint64(heightParam), httpServer.config.BlockChain, httpServer.config.ConsensusEngine)
+	if errProof != nil {
+		return nil, errProof
+	}
+	return proof, nil
+}
+
+// buildBeaconSwapProof returns the proof of the beacon committee swap instruction in a beacon block
+func buildBeaconSwapProof(
+	beaconHeigh uint64,
+	bc *blockchain.BlockChain,
+	ce ConsensusEngine,
+) (*jsonresult.GetInstructionProof, *rpcservice.RPCError) {
 	// Get proof of instruction on beacon
-	beaconInstProof, _, errProof := getSwapProofOnBeacon(beaconHeigh, httpServer.config.BlockChain, httpServer.config.ConsensusEngine, metadata.BeaconSwapConfirmMeta)
+	beaconInstProof, _, errProof := getSwapProofOnBeacon(beaconHeigh, bc, ce, metadata.BeaconSwapConfirmMeta)
 	if errProof != nil {
 		return nil, errProof
 	}
@@ -68,7 +80,8 @@ func (httpServer *HttpServer) handleGetBeaconSwapProof(params interface{}, close
 	}
 	inst := hex.EncodeToString(decodedInst)
 	bridgeInstProof := &swapProof{}
-	return buildProofResult(inst, beaconInstProof, bridgeInstProof, strconv.FormatUint(beaconHeigh, 10), ""), nil
+	proof := buildProofResult(inst, beaconInstProof, bridgeInstProof, strconv.FormatUint(beaconHeigh, 10), "")
+	return &proof, nil
 }
 
 // getSwapProofOnBeacon finds in a given beacon block a committee swap instruction and returns its proof;
@@ -8,6 +8,7 @@ import (
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 	"github.com/pkg/errors"
 )
@@ -38,15 +39,28 @@ func (httpServer *HttpServer) handleGetBridgeSwapProof(params interface{}, close
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("height param is invalid"))
 	}
-	beaconHeigh := uint64(heightParam)
+	proof, errProof := buildBridgeSwapProof(uint64(heightParam), httpServer.config.BlockChain, httpServer.config.ConsensusEngine)
+	if errProof != nil {
+		return nil, errProof
+	}
+	return proof, nil
+}
+
+// buildBridgeSwapProof returns the proof of the bridge committee swap instruction in a beacon block and in
+// the bridge block it comes from
+func buildBridgeSwapProof(
+	beaconHeigh uint64,
+	bc *blockchain.BlockChain,
+	ce ConsensusEngine,
+) (*jsonresult.GetInstructionProof, *rpcservice.RPCError) {
 	// Get proof of instruction on beacon
-	beaconInstProof, beaconBlock, errProof := getSwapProofOnBeacon(beaconHeigh, httpServer.config.BlockChain, httpServer.config.ConsensusEngine, metadata.BridgeSwapConfirmMeta)
+	beaconInstProof, beaconBlock, errProof := getSwapProofOnBeacon(beaconHeigh, bc, ce, metadata.BridgeSwapConfirmMeta)
 	if errProof != nil {
 		return nil, errProof
 	}
 
 	// Get proof of instruction on bridge
-	bridgeInstProof, bridgeShardBlockHeight, err := getBridgeSwapProofOnBridge(beaconBlock, httpServer.GetBlockchain(), httpServer.config.ConsensusEngine)
+	bridgeInstProof, bridgeShardBlockHeight, err := getBridgeSwapProofOnBridge(beaconBlock, bc, ce)
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
 	}
@@ -58,7 +72,8 @@ func (httpServer *HttpServer) handleGetBridgeSwapProof(params interface{}, close
 	}
 	inst := hex.EncodeToString(decodedInst)
 
-	return buildProofResult(inst, beaconInstProof, bridgeInstProof, strconv.FormatUint(beaconBlock.Header.Height, 10), strconv.FormatUint(bridgeShardBlockHeight, 10)), nil
+	proof := buildProofResult(inst, beaconInstProof, bridgeInstProof, strconv.FormatUint(beaconBlock.Header.Height, 10), strconv.FormatUint(bridgeShardBlockHeight, 10))
+	return &proof, nil
 }
 
 // getBridgeSwapProofOnBridge finds a bridge committee swap instruction in a bridge block and returns its proof; the bridge block must be included in a given beaconBlock
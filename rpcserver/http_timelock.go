@@ -0,0 +1,253 @@
+package rpcserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/rpcserver/bean"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+/*
+handleCreateRawTimeLockedTransaction - RPC creates a PRV transfer valid within a window of beacon heights
+- Param #1 to #4: as createtransaction
+- Param #5: the window {"NotBefore": 1000, "NotAfter": 2000}, NotAfter is optional
+*/
+func (httpServer *HttpServer) handleCreateRawTimeLockedTransaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	paramsArray := common.InterfaceSlice(params)
+	if paramsArray == nil || len(paramsArray) < 5 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 5 element"))
+	}
+	data, ok := paramsArray[4].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("invalid time lock window %+v", paramsArray[4]))
+	}
+	timeLock, err := metadata.NewTimeLockFromRPC(data)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	createRawTxParam, errNewParam := bean.NewCreateRawTxParam(params)
+	if errNewParam != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errNewParam)
+	}
+
+	txID, txBytes, txShardID, errCreate := httpServer.txService.CreateRawTransaction(createRawTxParam, timeLock)
+	if errCreate != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.CreateTxDataError, errCreate)
+	}
+	return jsonresult.NewCreateTransactionResult(txID, common.EmptyString, txBytes, txShardID), nil
+}
+
+// handleCreateAndSendTimeLockedTransaction - RPC creates a time-locked PRV transfer and sends it, the mempool holds it until its window opens
+func (httpServer *HttpServer) handleCreateAndSendTimeLockedTransaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	data, err := httpServer.handleCreateRawTimeLockedTransaction(params, closeChan)
+	if err != nil {
+		return nil, err
+	}
+	tx := data.(jsonresult.CreateTransactionResult)
+	sendResult, err := httpServer.handleSendRawTransaction([]interface{}{tx.Base58CheckData}, closeChan)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.SendTxDataError, err)
+	}
+	result := jsonresult.NewCreateTransactionResult(nil, sendResult.(jsonresult.CreateTransactionResult).TxID, nil, tx.ShardID)
+	return result, nil
+}
+
+/*
+handleCreateRawTimeLockedPrivacyCustomTokenTransaction - RPC creates a token transfer valid within a window of beacon heights
+- Params: as createrawprivacycustomtokentransaction, the window is read from the NotBefore and NotAfter fields of the token param (#5)
+*/
+func (httpServer *HttpServer) handleCreateRawTimeLockedPrivacyCustomTokenTransaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	paramsArray := common.InterfaceSlice(params)
+	if paramsArray == nil || len(paramsArray) < 5 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 5 element"))
+	}
+	tokenParams, ok := paramsArray[4].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("token param is invalid"))
+	}
+	timeLock, err := metadata.NewTimeLockFromRPC(tokenParams)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+
+	tx, errBuild := httpServer.txService.BuildRawPrivacyCustomTokenTransaction(params, timeLock)
+	if errBuild != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.CreateTxDataError, errBuild)
+	}
+	byteArrays, err := json.Marshal(tx)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.CreateTxDataError, err)
+	}
+	result := jsonresult.CreateTransactionTokenResult{
+		ShardID:         common.GetShardIDFromLastByte(tx.Tx.PubKeyLastByteSender),
+		TxID:            tx.Hash().String(),
+		TokenID:         tx.TxPrivacyTokenData.PropertyID.String(),
+		TokenName:       tx.TxPrivacyTokenData.PropertyName,
+		TokenAmount:     tx.TxPrivacyTokenData.Amount,
+		Base58CheckData: base58.Base58Check{}.Encode(byteArrays, 0x00),
+	}
+	return result, nil
+}
+
+// handleGetHeldTransactions - RPC returns the time-locked txs the mempool holds until their window opens
+func (httpServer *HttpServer) handleGetHeldTransactions(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	return jsonresult.NewHeldTransactions(httpServer.config.TxMemPool.HeldTxs()), nil
+}
+
+/*
+handleAddScheduledPayment - RPC stores a pre-signed time-locked tx in the wallet of the node, sendscheduledpayments broadcasts it once its window opens
+- Param #1: base58 check data of the tx
+- Param #2: passPhrase of wallet
+*/
+func (httpServer *HttpServer) handleAddScheduledPayment(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 2 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 2 elements"))
+	}
+	base58CheckData, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("base58 check data is invalid"))
+	}
+	passPhrase, ok := arrayParams[1].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("passPhrase is invalid"))
+	}
+	if httpServer.config.Wallet == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, errors.New("node has no wallet"))
+	}
+	payment, err := newScheduledPayment(base58CheckData)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	if err := httpServer.config.Wallet.AddScheduledPayment(*payment, passPhrase); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
+	}
+	return payment, nil
+}
+
+// handleListScheduledPayments - RPC returns the pre-signed time-locked txs stored in the wallet of the node
+func (httpServer *HttpServer) handleListScheduledPayments(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	if httpServer.config.Wallet == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, errors.New("node has no wallet"))
+	}
+	return httpServer.config.Wallet.ListScheduledPayments(), nil
+}
+
+/*
+handleRemoveScheduledPayment - RPC removes a pre-signed time-locked tx from the wallet of the node
+- Param #1: tx id
+- Param #2: passPhrase of wallet
+*/
+func (httpServer *HttpServer) handleRemoveScheduledPayment(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 2 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 2 elements"))
+	}
+	txID, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("tx id is invalid"))
+	}
+	passPhrase, ok := arrayParams[1].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("passPhrase is invalid"))
+	}
+	if httpServer.config.Wallet == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, errors.New("node has no wallet"))
+	}
+	if err := httpServer.config.Wallet.RemoveScheduledPayments([]string{txID}, passPhrase); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
+	}
+	return true, nil
+}
+
+/*
+handleSendScheduledPayments - RPC broadcasts the payments of the wallet whose window is open at the beacon height of the best block of their shard,
+the payments sent and the expired ones are removed from the wallet
+- Param #1: passPhrase of wallet
+*/
+func (httpServer *HttpServer) handleSendScheduledPayments(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
+	}
+	passPhrase, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("passPhrase is invalid"))
+	}
+	w := httpServer.config.Wallet
+	if w == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, errors.New("node has no wallet"))
+	}
+	if passPhrase != w.PassPhrase {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, wallet.NewWalletError(wallet.WrongPassphraseErr, nil))
+	}
+
+	result := jsonresult.SendScheduledPaymentsResult{Sent: []string{}, Expired: []string{}, Failed: map[string]string{}}
+	for _, payment := range w.ListScheduledPayments() {
+		beaconHeight := httpServer.config.BlockChain.GetBestStateShard(payment.ShardID).BeaconHeight
+		if payment.IsExpiredAt(beaconHeight) {
+			result.Expired = append(result.Expired, payment.TxID)
+			continue
+		}
+		if !payment.IsDueAt(beaconHeight) {
+			continue
+		}
+		var err *rpcservice.RPCError
+		if payment.IsPrivacyToken {
+			_, err = httpServer.handleSendRawPrivacyCustomTokenTransaction([]interface{}{payment.Base58CheckData}, closeChan)
+		} else {
+			_, err = httpServer.handleSendRawTransaction([]interface{}{payment.Base58CheckData}, closeChan)
+		}
+		if err != nil {
+			result.Failed[payment.TxID] = err.Error()
+			continue
+		}
+		result.Sent = append(result.Sent, payment.TxID)
+	}
+	if err := w.RemoveScheduledPayments(append(result.Sent, result.Expired...), passPhrase); err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
+	}
+	return result, nil
+}
+
+// newScheduledPayment decodes a time-locked tx, token txs are told apart by
+// their type
+func newScheduledPayment(base58CheckData string) (*wallet.ScheduledPayment, error) {
+	rawTxBytes, _, err := base58.Base58Check{}.Decode(base58CheckData)
+	if err != nil {
+		return nil, err
+	}
+	var tx metadata.Transaction
+	normalTx := &transaction.Tx{}
+	if err := json.Unmarshal(rawTxBytes, normalTx); err != nil {
+		return nil, err
+	}
+	tx = normalTx
+	if normalTx.Type == common.TxCustomTokenPrivacyType {
+		tokenTx := &transaction.TxCustomTokenPrivacy{}
+		if err := json.Unmarshal(rawTxBytes, tokenTx); err != nil {
+			return nil, err
+		}
+		tx = tokenTx
+	}
+	timeLock, ok := tx.GetMetadata().(*metadata.TimeLock)
+	if !ok {
+		return nil, errors.New("transaction is not time-locked")
+	}
+	return &wallet.ScheduledPayment{
+		TxID:            tx.Hash().String(),
+		ShardID:         common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()),
+		Base58CheckData: base58CheckData,
+		IsPrivacyToken:  tx.GetType() == common.TxCustomTokenPrivacyType,
+		NotBefore:       timeLock.NotBefore,
+		NotAfter:        timeLock.NotAfter,
+	}, nil
+}
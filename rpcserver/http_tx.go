@@ -12,6 +12,7 @@ import (
 	"github.com/incognitochain/incognito-chain/rpcserver/bean"
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+	"github.com/incognitochain/incognito-chain/txtracker"
 	"github.com/incognitochain/incognito-chain/wallet"
 )
 
@@ -154,6 +155,29 @@ func (httpServer *HttpServer) handleGetTransactionByHash(params interface{}, clo
 	return httpServer.txService.GetTransactionByHash(txHashStr)
 }
 
+// handleGetTxLifecycle - return the events of a tx seen by the node, from its submission to its inclusion in a block
+func (httpServer *HttpServer) handleGetTxLifecycle(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
+	}
+
+	// param #1: transaction Hash
+	txHashStr, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Tx hash is invalid"))
+	}
+	txHash, err := common.Hash{}.NewHashFromStr(txHashStr)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, err)
+	}
+	lifecycle := txtracker.Get(*txHash)
+	if lifecycle == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.TxLifecycleNotFoundError, fmt.Errorf("tx %v is not tracked", txHashStr))
+	}
+	return lifecycle, nil
+}
+
 // handleGetListPrivacyCustomTokenBalance - return list privacy token + balance for one account payment address
 func (httpServer *HttpServer) handleGetListPrivacyCustomTokenBalance(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 
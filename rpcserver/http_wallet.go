@@ -9,6 +9,7 @@ import (
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+	"github.com/incognitochain/incognito-chain/wallet"
 )
 
 /*
@@ -156,6 +157,107 @@ func (httpServer *HttpServer) handleRemoveAccount(params interface{}, closeChan
 	return httpServer.walletService.RemoveAccount(privateKey, passPhrase)
 }
 
+/*
+handleCreatePathAccount - create a new account at the next index of m/44'/587'/account'/index
+- Param #1: account name, empty for "AccountWallet <n>"
+- Param #2: account number of the path
+- Param #3: shard of the payment address, -1 for any shard
+- Param #4: passPhrase of wallet
+*/
+func (httpServer *HttpServer) handleCreatePathAccount(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 4 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 4 elements"))
+	}
+
+	accountName, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("accountName is invalid"))
+	}
+
+	account, ok := arrayParams[1].(float64)
+	if !ok || account < 0 || account >= float64(wallet.HardenedKeyStart) {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("account is invalid"))
+	}
+
+	shardIDParam, ok := arrayParams[2].(float64)
+	if !ok || shardIDParam > 255 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("shardID is invalid"))
+	}
+	var shardID *byte
+	if shardIDParam >= 0 {
+		temp := byte(shardIDParam)
+		shardID = &temp
+	}
+
+	passPhrase, ok := arrayParams[3].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("passPhrase is invalid"))
+	}
+
+	return httpServer.walletService.CreatePathAccount(accountName, uint32(account), shardID, passPhrase)
+}
+
+/*
+handleDiscoverAccounts - add the accounts of the seed of the wallet which received coins, after restoring it from its mnemonic
+- Param #1: gap limit, the number of unused addresses in a row which ends an account, 0 for 20
+- Param #2: passPhrase of wallet
+*/
+func (httpServer *HttpServer) handleDiscoverAccounts(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 2 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 2 elements"))
+	}
+
+	gapLimit, ok := arrayParams[0].(float64)
+	if !ok || gapLimit < 0 || gapLimit > 1000 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("gapLimit is invalid"))
+	}
+
+	passPhrase, ok := arrayParams[1].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("passPhrase is invalid"))
+	}
+
+	return httpServer.walletService.DiscoverAccounts(uint32(gapLimit), passPhrase)
+}
+
+/*
+handleImportWatchOnlyAccount - import an account without its private key, it lists the coins of the payment address but can not spend them
+- Param #1: payment address
+- Param #2: readonly key of the payment address
+- Param #3: account name
+- Param #4: passPhrase of wallet
+*/
+func (httpServer *HttpServer) handleImportWatchOnlyAccount(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 4 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 4 elements"))
+	}
+
+	paymentAddress, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("paymentAddress is invalid"))
+	}
+
+	readonlyKey, ok := arrayParams[1].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("readonlyKey is invalid"))
+	}
+
+	accountName, ok := arrayParams[2].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("accountName is invalid"))
+	}
+
+	passPhrase, ok := arrayParams[3].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("passPhrase is invalid"))
+	}
+
+	return httpServer.walletService.ImportWatchOnlyAccount(paymentAddress, readonlyKey, accountName, passPhrase)
+}
+
 // handleGetBalanceByPrivatekey -  return balance of private key
 func (httpServer *HttpServer) handleGetBalanceByPrivatekey(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	// all component
@@ -229,7 +331,13 @@ func (httpServer *HttpServer) handleGetBalance(params interface{}, closeChan <-c
 		return uint64(0), rpcservice.NewRPCError(rpcservice.UnexpectedError, errors.New("password phrase is wrong for local wallet"))
 	}
 
-	return httpServer.walletService.GetBalance(accountName)
+	// Param #4 (optional): view selector - best (default), final, block hash or block height
+	selector, rpcErr := rpcservice.ParseOptionalViewSelector(arrayParams, 3)
+	if rpcErr != nil {
+		return uint64(0), rpcErr
+	}
+
+	return httpServer.walletService.GetBalance(accountName, selector)
 }
 
 /*
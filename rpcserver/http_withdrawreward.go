@@ -62,7 +62,7 @@ func (httpServer *HttpServer) handleCreateAndSendWithDrawTransaction(params inte
 // handleGetRewardAmount - Get the reward amount of a payment address with all existed token
 func (httpServer *HttpServer) handleGetRewardAmount(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
-	if arrayParams == nil || len(arrayParams) != 1 {
+	if arrayParams == nil || len(arrayParams) < 1 || len(arrayParams) > 2 {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
 	}
 
@@ -70,7 +70,12 @@ func (httpServer *HttpServer) handleGetRewardAmount(params interface{}, closeCha
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("payment address is invalid"))
 	}
-	rewardAmount, err := httpServer.blockService.GetRewardAmount(paymentAddress)
+	// param #2 (optional): shard height of the reward state, default is best view
+	shardHeight, rpcErr := rpcservice.ParseOptionalHeight(arrayParams, 1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	rewardAmount, err := httpServer.blockService.GetRewardAmount(paymentAddress, shardHeight)
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.GetRewardAmountError, err)
 	}
@@ -80,7 +85,7 @@ func (httpServer *HttpServer) handleGetRewardAmount(params interface{}, closeCha
 // handleGetRewardAmount - Get the reward amount of a payment address with all existed token
 func (httpServer *HttpServer) handleGetRewardAmountByPublicKey(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
-	if arrayParams == nil || len(arrayParams) != 1 {
+	if arrayParams == nil || len(arrayParams) < 1 || len(arrayParams) > 2 {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
 	}
 
@@ -88,7 +93,12 @@ func (httpServer *HttpServer) handleGetRewardAmountByPublicKey(params interface{
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("payment address is invalid"))
 	}
-	rewardAmount, err := httpServer.blockService.GetRewardAmountByPublicKey(paymentAddress)
+	// param #2 (optional): shard height of the reward state, default is best view
+	shardHeight, rpcErr := rpcservice.ParseOptionalHeight(arrayParams, 1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	rewardAmount, err := httpServer.blockService.GetRewardAmountByPublicKey(paymentAddress, shardHeight)
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.GetRewardAmountError, err)
 	}
@@ -97,7 +107,12 @@ func (httpServer *HttpServer) handleGetRewardAmountByPublicKey(params interface{
 
 // handleListRewardAmount - Get the reward amount of all committee with all existed token
 func (httpServer *HttpServer) handleListRewardAmount(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
-	result, err := httpServer.blockService.ListRewardAmount()
+	// param #1 (optional): beacon height of the reward snapshot, default is best view
+	beaconHeight, rpcErr := rpcservice.ParseOptionalHeight(common.InterfaceSlice(params), 0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	result, err := httpServer.blockService.ListRewardAmount(beaconHeight)
 	if err != nil {
 		return nil, rpcservice.NewRPCError(rpcservice.ListCommitteeRewardError, err)
 	}
@@ -0,0 +1,103 @@
+package jsonresult
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+type ForkTreeNode struct {
+	Hash              string   `json:"Hash"`
+	PreviousBlockHash string   `json:"PreviousBlockHash"`
+	Height            uint64   `json:"Height"`
+	Round             uint64   `json:"Round"`
+	Children          []string `json:"Children"`
+	IsBest            bool     `json:"IsBest"`
+	IsFinal           bool     `json:"IsFinal"`
+}
+
+type GetForkTreeResult struct {
+	ChainID         int            `json:"ChainID"`
+	BestViewHash    string         `json:"BestViewHash"`
+	BestViewHeight  uint64         `json:"BestViewHeight"`
+	FinalViewHash   string         `json:"FinalViewHash"`
+	FinalViewHeight uint64         `json:"FinalViewHeight"`
+	Views           []ForkTreeNode `json:"Views"`
+}
+
+func NewGetForkTreeResult(chainID int, bestView, finalView multiview.View, views []multiview.View) *GetForkTreeResult {
+	result := &GetForkTreeResult{
+		ChainID:         chainID,
+		BestViewHash:    bestView.GetHash().String(),
+		BestViewHeight:  bestView.GetHeight(),
+		FinalViewHash:   finalView.GetHash().String(),
+		FinalViewHeight: finalView.GetHeight(),
+		Views:           []ForkTreeNode{},
+	}
+	children := make(map[common.Hash][]string)
+	for _, view := range views {
+		children[*view.GetPreviousHash()] = append(children[*view.GetPreviousHash()], view.GetHash().String())
+	}
+	for _, view := range views {
+		node := ForkTreeNode{
+			Hash:              view.GetHash().String(),
+			PreviousBlockHash: view.GetPreviousHash().String(),
+			Height:            view.GetHeight(),
+			Round:             uint64(view.GetBlock().GetRound()),
+			Children:          children[*view.GetHash()],
+			IsBest:            view.GetHash().IsEqual(bestView.GetHash()),
+			IsFinal:           view.GetHash().IsEqual(finalView.GetHash()),
+		}
+		if node.Children == nil {
+			node.Children = []string{}
+		}
+		result.Views = append(result.Views, node)
+	}
+	return result
+}
+
+type ChainReorgResult struct {
+	ChainID     int      `json:"ChainID"`
+	ForkPoint   string   `json:"ForkPoint"`
+	ForkHeight  uint64   `json:"ForkHeight"`
+	OldBestHash string   `json:"OldBestHash"`
+	NewBestHash string   `json:"NewBestHash"`
+	Detached    []string `json:"Detached"`
+	Attached    []string `json:"Attached"`
+}
+
+func NewChainReorgResult(event *multiview.ReorgEvent) *ChainReorgResult {
+	result := &ChainReorgResult{
+		ChainID:     event.ChainID,
+		ForkPoint:   event.ForkPoint.String(),
+		ForkHeight:  event.ForkHeight,
+		OldBestHash: event.OldBestHash.String(),
+		NewBestHash: event.NewBestHash.String(),
+		Detached:    []string{},
+		Attached:    []string{},
+	}
+	for _, hash := range event.Detached {
+		result.Detached = append(result.Detached, hash.String())
+	}
+	for _, hash := range event.Attached {
+		result.Attached = append(result.Attached, hash.String())
+	}
+	return result
+}
+
+type FinalizedBlockResult struct {
+	ChainID             int    `json:"ChainID"`
+	Hash                string `json:"Hash"`
+	Height              uint64 `json:"Height"`
+	PreviousFinalHash   string `json:"PreviousFinalHash"`
+	PreviousFinalHeight uint64 `json:"PreviousFinalHeight"`
+}
+
+func NewFinalizedBlockResult(event *multiview.FinalViewEvent) *FinalizedBlockResult {
+	return &FinalizedBlockResult{
+		ChainID:             event.ChainID,
+		Hash:                event.Hash.String(),
+		Height:              event.Height,
+		PreviousFinalHash:   event.PreviousFinal.String(),
+		PreviousFinalHeight: event.PreviousHeight,
+	}
+}
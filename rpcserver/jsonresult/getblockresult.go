@@ -27,6 +27,7 @@ type GetShardBlockResult struct {
 	ShardID           byte               `json:"ShardID"`
 	Height            uint64             `json:"Height"`
 	Confirmations     int64              `json:"Confirmations"`
+	IsFinal           bool               `json:"IsFinal"`
 	Version           int                `json:"Version"`
 	TxRoot            string             `json:"TxRoot"`
 	Time              int64              `json:"Time"`
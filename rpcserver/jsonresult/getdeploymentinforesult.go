@@ -0,0 +1,37 @@
+package jsonresult
+
+// DeploymentInfo is the state of a consensus deployment voted with beacon header version bits
+type DeploymentInfo struct {
+	Name         string `json:"Name"`
+	Bit          uint8  `json:"Bit"`
+	StartHeight  uint64 `json:"StartHeight"`
+	ExpireHeight uint64 `json:"ExpireHeight"`
+	Status       string `json:"Status"`
+}
+
+// GetDeploymentInfoResult models the data returned from the getdeploymentinfo
+// command, the status of each deployment is the one of the next beacon block.
+type GetDeploymentInfoResult struct {
+	BeaconHeight uint64           `json:"BeaconHeight"`
+	Window       uint64           `json:"Window"`
+	Threshold    uint64           `json:"Threshold"`
+	Deployments  []DeploymentInfo `json:"Deployments"`
+}
+
+// ForkInfo is the activation of a consensus rule change of the fork schedule
+type ForkInfo struct {
+	Name             string   `json:"Name"`
+	Height           uint64   `json:"Height,omitempty"`
+	Epoch            uint64   `json:"Epoch,omitempty"`
+	Epochs           []uint64 `json:"Epochs,omitempty"`
+	ActivationHeight uint64   `json:"ActivationHeight,omitempty"`
+	Active           bool     `json:"Active"`
+}
+
+// GetForkScheduleResult models the data returned from the getforkschedule command,
+// Active tells whether the fork applies to the next beacon block
+type GetForkScheduleResult struct {
+	BeaconHeight uint64     `json:"BeaconHeight"`
+	EpochLength  uint64     `json:"EpochLength"`
+	Forks        []ForkInfo `json:"Forks"`
+}
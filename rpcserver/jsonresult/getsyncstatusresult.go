@@ -0,0 +1,27 @@
+package jsonresult
+
+// ChainSyncStatus is the sync state of a chain and the consensus
+// participation of the mining key of the node in it
+type ChainSyncStatus struct {
+	ChainID               int    `json:"ChainID"`
+	Chain                 string `json:"Chain"`
+	IsSync                bool   `json:"IsSync"`
+	IsCatchUp             bool   `json:"IsCatchUp"`
+	BestHeight            uint64 `json:"BestHeight"`
+	FinalHeight           uint64 `json:"FinalHeight"`
+	PeerHeight            uint64 `json:"PeerHeight"`
+	Peers                 int    `json:"Peers"`
+	Lag                   uint64 `json:"Lag"`
+	LastBlockTime         int64  `json:"LastBlockTime"`
+	SecondsSinceLastBlock int64  `json:"SecondsSinceLastBlock"`
+	MiningStatus          string `json:"MiningStatus"`
+}
+
+// GetSyncStatusResult models the data returned from the getsyncstatus
+// command, Checks are the results of the /healthz and /readyz checks
+type GetSyncStatusResult struct {
+	Healthy bool              `json:"Healthy"`
+	Ready   bool              `json:"Ready"`
+	Checks  map[string]string `json:"Checks"`
+	Chains  []ChainSyncStatus `json:"Chains"`
+}
@@ -12,6 +12,14 @@ import (
 	"time"
 )
 
+// Confirmation status of a transaction
+const (
+	TxStatusPending     = "pending"
+	TxStatusInBestChain = "inbestchain"
+	TxStatusFinalized   = "finalized"
+	TxStatusOrphaned    = "orphaned"
+)
+
 type TransactionDetail struct {
 	BlockHash   string `json:"BlockHash"`
 	BlockHeight uint64 `json:"BlockHeight"`
@@ -42,8 +50,10 @@ type TransactionDetail struct {
 	PrivacyCustomTokenIsPrivacy   bool        `json:"PrivacyCustomTokenIsPrivacy"`
 	PrivacyCustomTokenFee         uint64      `json:"PrivacyCustomTokenFee"`
 
-	IsInMempool bool `json:"IsInMempool"`
-	IsInBlock   bool `json:"IsInBlock"`
+	IsInMempool   bool   `json:"IsInMempool"`
+	IsInBlock     bool   `json:"IsInBlock"`
+	Status        string `json:"Status"`
+	Confirmations uint64 `json:"Confirmations"`
 
 	Info string `json:"Info"`
 }
@@ -0,0 +1,44 @@
+package jsonresult
+
+import "github.com/incognitochain/incognito-chain/incdb"
+
+type PrefixStorageStats struct {
+	Name   string `json:"Name"`
+	Prefix string `json:"Prefix"`
+	Size   uint64 `json:"Size"`
+}
+
+type DatabaseStorageStats struct {
+	Name     string               `json:"Name"`
+	Path     string               `json:"Path"`
+	DiskSize uint64               `json:"DiskSize"`
+	Stats    string               `json:"Stats,omitempty"`
+	Prefixes []PrefixStorageStats `json:"Prefixes,omitempty"`
+	Error    string               `json:"Error,omitempty"`
+}
+
+type GetStorageStatsResult struct {
+	TotalDiskSize uint64                 `json:"TotalDiskSize"`
+	Databases     []DatabaseStorageStats `json:"Databases"`
+	Compaction    CompactionStatusResult `json:"Compaction"`
+}
+
+type CompactionStatusResult struct {
+	Running   bool    `json:"Running"`
+	Current   string  `json:"Current"`
+	Progress  float64 `json:"Progress"`
+	StartTime int64   `json:"StartTime"`
+	EndTime   int64   `json:"EndTime"`
+	LastError string  `json:"LastError"`
+}
+
+func NewCompactionStatusResult(status incdb.CompactionStatus) CompactionStatusResult {
+	return CompactionStatusResult{
+		Running:   status.Running,
+		Current:   status.Current,
+		Progress:  status.Progress,
+		StartTime: status.StartTime,
+		EndTime:   status.EndTime,
+		LastError: status.LastError,
+	}
+}
@@ -0,0 +1,39 @@
+package jsonresult
+
+import (
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+type HeldTransaction struct {
+	TxID      string    `json:"TxID"`
+	ShardID   byte      `json:"ShardID"`
+	NotBefore uint64    `json:"NotBefore"`
+	NotAfter  uint64    `json:"NotAfter"`
+	HeldSince time.Time `json:"HeldSince"`
+}
+
+func NewHeldTransactions(txDescs []mempool.TxDesc) []HeldTransaction {
+	result := make([]HeldTransaction, 0, len(txDescs))
+	for _, txDesc := range txDescs {
+		tx := txDesc.Desc.Tx
+		timeLock := tx.GetMetadata().(*metadata.TimeLock)
+		result = append(result, HeldTransaction{
+			TxID:      tx.Hash().String(),
+			ShardID:   common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()),
+			NotBefore: timeLock.NotBefore,
+			NotAfter:  timeLock.NotAfter,
+			HeldSince: txDesc.StartTime,
+		})
+	}
+	return result
+}
+
+type SendScheduledPaymentsResult struct {
+	Sent    []string          `json:"Sent"`
+	Expired []string          `json:"Expired"`
+	Failed  map[string]string `json:"Failed"`
+}
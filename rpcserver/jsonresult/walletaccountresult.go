@@ -0,0 +1,29 @@
+package jsonresult
+
+import (
+	"encoding/hex"
+
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+type WalletAccountResult struct {
+	Name           string `json:"Name"`
+	PaymentAddress string `json:"PaymentAddress"`
+	Pubkey         string `json:"Pubkey"`
+	ReadonlyKey    string `json:"ReadonlyKey"`
+	ShardID        byte   `json:"ShardID"`
+	Path           string `json:"Path,omitempty"`
+	IsWatchOnly    bool   `json:"IsWatchOnly"`
+}
+
+func NewWalletAccountResult(account wallet.AccountWallet) WalletAccountResult {
+	return WalletAccountResult{
+		Name:           account.Name,
+		PaymentAddress: account.Key.Base58CheckSerialize(wallet.PaymentAddressType),
+		Pubkey:         hex.EncodeToString(account.Key.KeySet.PaymentAddress.Pk),
+		ReadonlyKey:    account.Key.Base58CheckSerialize(wallet.ReadonlyKeyType),
+		ShardID:        account.Key.ShardID(),
+		Path:           account.Path,
+		IsWatchOnly:    account.IsWatchOnly,
+	}
+}
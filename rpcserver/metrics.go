@@ -0,0 +1,16 @@
+package rpcserver
+
+import (
+	"github.com/incognitochain/incognito-chain/metrics"
+)
+
+// rpcTimer times the calls of an RPC method, only known methods are timed to
+// keep the number of metrics bounded
+func rpcTimer(method string) metrics.Timer {
+	return metrics.GetOrRegisterTimer(metrics.LabeledName("rpc/latency", "method", method), nil)
+}
+
+// rpcErrorCounter counts the calls of an RPC method returning an error
+func rpcErrorCounter(method string) metrics.Counter {
+	return metrics.GetOrRegisterCounter(metrics.LabeledName("rpc/errors", "method", method), nil)
+}
@@ -23,6 +23,7 @@ var HttpHandler = map[string]httpHandler{
 	getInOutMessages:         (*HttpServer).handleGetInOutMessages,
 	getInOutMessageCount:     (*HttpServer).handleGetInOutMessageCount,
 	getAllPeers:              (*HttpServer).handleGetAllPeers,
+	getPeerReputations:       (*HttpServer).handleGetPeerReputations,
 	estimateFee:              (*HttpServer).handleEstimateFee,
 	estimateFeeWithEstimator: (*HttpServer).handleEstimateFeeWithEstimator,
 	getActiveShards:          (*HttpServer).handleGetActiveShards,
@@ -238,10 +239,43 @@ var HttpHandler = map[string]httpHandler{
 	getCrossShardPoolInfo:    (*HttpServer).hanldeGetCrossShardPoolInfo,
 	getAllView:               (*HttpServer).hanldeGetAllView,
 	getAllViewDetail:         (*HttpServer).hanldeGetAllViewDetail,
+	getChainForkTree:         (*HttpServer).handleGetChainForkTree,
 
 	// feature reward
 	getRewardFeature: (*HttpServer).handleGetRewardFeature,
 
+	// storage
+	getStorageStats: (*HttpServer).handleGetStorageStats,
+
+	// version bits
+	getDeploymentInfo: (*HttpServer).handleGetDeploymentInfo,
+	getForkSchedule:   (*HttpServer).handleGetForkSchedule,
+
+	// light client
+	getBeaconHeaders: (*HttpServer).handleGetBeaconHeaders,
+	getShardHeaders:  (*HttpServer).handleGetShardHeaders,
+	getCommitteeKeys: (*HttpServer).handleGetCommitteeKeys,
+	getTxProof:       (*HttpServer).handleGetTxProof,
+
+	// logging
+	getLogLevels: (*HttpServer).handleGetLogLevels,
+	setLogLevels: (*HttpServer).handleSetLogLevels,
+
+	// tx lifecycle
+	getTxLifecycle: (*HttpServer).handleGetTxLifecycle,
+
+	// health
+	getSyncStatus: (*HttpServer).handleGetSyncStatus,
+
+	// time-locked txs
+	createRawTimeLockedTransaction:                   (*HttpServer).handleCreateRawTimeLockedTransaction,
+	createAndSendTimeLockedTransaction:               (*HttpServer).handleCreateAndSendTimeLockedTransaction,
+	createRawTimeLockedPrivacyCustomTokenTransaction: (*HttpServer).handleCreateRawTimeLockedPrivacyCustomTokenTransaction,
+	getHeldTransactions:                              (*HttpServer).handleGetHeldTransactions,
+
+	// multisig
+	createMultiSigTransaction: (*HttpServer).handleCreateMultiSigTransaction,
+
 	// get committeeByHeight
 }
 
@@ -263,6 +297,37 @@ var LimitedHttpHandler = map[string]httpHandler{
 	setTxFee:                         (*HttpServer).handleSetTxFee,
 	convertNativeTokenToPrivacyToken: (*HttpServer).handleConvertNativeTokenToPrivacyToken,
 	convertPrivacyTokenToNativeToken: (*HttpServer).handleConvertPrivacyTokenToNativeToken,
+	addScheduledPayment:              (*HttpServer).handleAddScheduledPayment,
+	listScheduledPayments:            (*HttpServer).handleListScheduledPayments,
+	removeScheduledPayment:           (*HttpServer).handleRemoveScheduledPayment,
+	sendScheduledPayments:            (*HttpServer).handleSendScheduledPayments,
+	createPathAccount:                (*HttpServer).handleCreatePathAccount,
+	discoverAccounts:                 (*HttpServer).handleDiscoverAccounts,
+	importWatchOnlyAccount:           (*HttpServer).handleImportWatchOnlyAccount,
+
+	// storage compaction
+	startStorageCompaction: (*HttpServer).handleStartStorageCompaction,
+	stopStorageCompaction:  (*HttpServer).handleStopStorageCompaction,
+
+	// supply audit
+	auditSupply: (*HttpServer).handleAuditSupply,
+}
+
+// Commands served by a node in light mode, which has no chain data
+var LightHttpHandler = map[string]httpHandler{
+	getNodeRole:            (*HttpServer).handleGetNodeRole,
+	getNetworkInfo:         (*HttpServer).handleGetNetWorkInfo,
+	getConnectionCount:     (*HttpServer).handleGetConnectionCount,
+	getAllConnectedPeers:   (*HttpServer).handleGetAllConnectedPeers,
+	getPeerReputations:     (*HttpServer).handleGetPeerReputations,
+	getActiveShards:        (*HttpServer).handleGetActiveShards,
+	getMaxShardsNumber:     (*HttpServer).handleGetMaxShardsNumber,
+	getLightStatus:         (*HttpServer).handleGetLightStatus,
+	verifyLightTx:          (*HttpServer).handleVerifyLightTx,
+	verifyLightShardHeader: (*HttpServer).handleVerifyLightShardHeader,
+	getLogLevels:           (*HttpServer).handleGetLogLevels,
+	setLogLevels:           (*HttpServer).handleSetLogLevels,
+	getSyncStatus:          (*HttpServer).handleGetSyncStatus,
 }
 
 var WsHandler = map[string]wsHandler{
@@ -283,4 +348,6 @@ var WsHandler = map[string]wsHandler{
 	subcribeBeaconBestState:                     (*WsServer).handleSubscribeBeaconBestState,
 	subcribeBeaconPoolBeststate:                 (*WsServer).handleSubscribeBeaconPoolBestState,
 	subcribeShardPoolBeststate:                  (*WsServer).handleSubscribeShardPoolBeststate,
+	subcribeChainReorg:                          (*WsServer).handleSubscribeChainReorg,
+	subcribeFinalizedBlock:                      (*WsServer).handleSubscribeFinalizedBlock,
 }
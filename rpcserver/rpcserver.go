@@ -10,10 +10,13 @@ import (
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/connmanager"
+	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/light"
 	"github.com/incognitochain/incognito-chain/memcache"
 	"github.com/incognitochain/incognito-chain/mempool"
 	"github.com/incognitochain/incognito-chain/netsync"
+	"github.com/incognitochain/incognito-chain/peerv2"
 	"github.com/incognitochain/incognito-chain/pubsub"
 	"github.com/incognitochain/incognito-chain/syncker"
 	"github.com/incognitochain/incognito-chain/wallet"
@@ -66,12 +69,15 @@ type RpcServerConfig struct {
 	Blockgen        *blockchain.BlockGenerator
 	MemCache        *memcache.MemoryCache
 	Database        map[int]incdb.Database
+	DatabaseMempool databasemp.DatabaseInterface
+	BTCDataDir      string // directory of btc relaying database
 	Wallet          *wallet.Wallet
 	ConnMgr         *connmanager.ConnManager
 	AddrMgr         *addrmanager.AddrManager
 	NodeMode        string
 	NetSync         *netsync.NetSync
 	Syncker         *syncker.SynckerManager
+	Reputation      *peerv2.Reputation
 	Server          interface {
 		// Push TxNormal Message
 		PushMessageToAll(message wire.Message) error
@@ -110,6 +116,15 @@ type RpcServerConfig struct {
 	// IsMiningNode    bool   // flag mining node. True: mining, False: not mining
 	MiningKeys    string // encode of mining key
 	PubSubManager *pubsub.PubSubManager
+	// compacts Database and DatabaseMempool in background
+	StorageCompactor *incdb.Compactor
+	// verifies beacon headers and shard data in light mode
+	LightClient *light.Client
+	// levels of the loggers of the subsystems, keyed by subsystem id
+	LogLevels interface {
+		GetLogLevels() map[string]string
+		SetLogLevels(levels string) error
+	}
 }
 
 func (rpcServer *RpcServer) Init(config *RpcServerConfig) {
@@ -9,11 +9,13 @@ import (
 
 	rCommon "github.com/ethereum/go-ethereum/common"
 	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/memcache"
 	"github.com/incognitochain/incognito-chain/mempool"
 	"github.com/incognitochain/incognito-chain/metadata"
@@ -194,16 +196,50 @@ func (blockService BlockService) RetrieveShardBlock(hashString string, verbosity
 	return &result, nil
 }
 
-func (blockService BlockService) RetrieveShardBlockByHeight(blockHeight uint64, shardId int, verbosity string) ([]*jsonresult.GetShardBlockResult, *RPCError) {
-	shardBlocks, errD := blockService.BlockChain.GetShardBlockByHeight(blockHeight, byte(shardId))
+// getShardBlockByHeightWithSelector returns the block at blockHeight in the branch of the view chosen by selector
+func (blockService BlockService) getShardBlockByHeightWithSelector(blockHeight uint64, shardID byte, selector *ViewSelector) (map[common.Hash]*blockchain.ShardBlock, *RPCError) {
+	if selector == nil || selector.Type == BestViewSelector {
+		shardBlocks, err := blockService.BlockChain.GetShardBlockByHeight(blockHeight, shardID)
+		if err != nil {
+			return nil, NewRPCError(GetShardBlockByHashError, err)
+		}
+		return shardBlocks, nil
+	}
+	tip, rpcErr := blockService.ResolveShardView(shardID, selector)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if blockHeight > tip.Height {
+		return nil, NewRPCError(GetShardBlockByHeightError, fmt.Errorf("block height %+v is above the selected view height %+v", blockHeight, tip.Height))
+	}
+	shardChain := blockService.BlockChain.ShardChain[shardID]
+	tipView := tip.View
+	if tipView == nil {
+		tipView = shardChain.GetBestView()
+	}
+	blockHash, err := blockService.BlockChain.GetShardBlockHashByHeight(shardChain.GetFinalView(), tipView, blockHeight)
+	if err != nil {
+		return nil, NewRPCError(GetShardBlockByHeightError, err)
+	}
+	shardBlock, _, err := blockService.BlockChain.GetShardBlockByHashWithShardID(*blockHash, shardID)
+	if err != nil {
+		return nil, NewRPCError(GetShardBlockByHashError, err)
+	}
+	return map[common.Hash]*blockchain.ShardBlock{*blockHash: shardBlock}, nil
+}
+
+func (blockService BlockService) RetrieveShardBlockByHeight(blockHeight uint64, shardId int, verbosity string, selector *ViewSelector) ([]*jsonresult.GetShardBlockResult, *RPCError) {
+	shardBlocks, errD := blockService.getShardBlockByHeightWithSelector(blockHeight, byte(shardId), selector)
 	if errD != nil {
 		Logger.log.Debugf("handleRetrieveBlock result: %+v, err: %+v", nil, errD)
-		return nil, NewRPCError(GetShardBlockByHashError, errD)
+		return nil, errD
 	}
+	finalHeight := blockService.BlockChain.ShardChain[byte(shardId)].GetFinalView().GetHeight()
 	result := []*jsonresult.GetShardBlockResult{}
 	for _, shardBlock := range shardBlocks {
 		res := jsonresult.GetShardBlockResult{}
 		shardID := shardBlock.Header.ShardID
+		res.IsFinal = shardBlock.Header.Height <= finalHeight
 		if verbosity == "0" {
 			data, err := json.Marshal(shardBlock)
 			if err != nil {
@@ -646,12 +682,32 @@ func (blockService BlockService) GetMinerRewardFromMiningKey(incPublicKey []byte
 	return rewardAmountResult, nil
 }
 
-func (blockService BlockService) ListRewardAmount() (map[string]map[common.Hash]uint64, error) {
+// getShardRewardStateDB returns reward state of the shard best view, or of the given shard height (0 means best view)
+func (blockService BlockService) getShardRewardStateDB(shardID byte, shardHeight uint64) (*statedb.StateDB, error) {
+	if shardHeight == 0 {
+		return blockService.BlockChain.GetBestStateShard(shardID).GetShardRewardStateDB(), nil
+	}
+	return blockService.BlockChain.GetShardStateDBAtHeight(shardID, blockchain.RewardStateDBType, shardHeight)
+}
+
+// ListRewardAmount lists committee rewards of all shards at the best views, or at the shard heights confirmed by beaconHeight (0 means best views)
+func (blockService BlockService) ListRewardAmount(beaconHeight uint64) (map[string]map[common.Hash]uint64, error) {
 	m := make(map[string]map[common.Hash]uint64)
 	beaconBestState := blockService.BlockChain.GetBeaconBestState()
+	shardHeights := make(map[byte]uint64)
+	if beaconHeight != 0 {
+		var err error
+		shardHeights, err = blockService.BlockChain.GetShardHeightsAtBeaconHeight(beaconHeight)
+		if err != nil {
+			return nil, err
+		}
+	}
 	for i := 0; i < beaconBestState.ActiveShards; i++ {
 		shardID := byte(i)
-		committeeRewardStateDB := blockService.BlockChain.GetBestStateShard(shardID).GetShardRewardStateDB()
+		committeeRewardStateDB, err := blockService.getShardRewardStateDB(shardID, shardHeights[shardID])
+		if err != nil {
+			return nil, err
+		}
 		committeeReward := statedb.ListCommitteeReward(committeeRewardStateDB)
 		for k, v := range committeeReward {
 			m[k] = v
@@ -660,7 +716,8 @@ func (blockService BlockService) ListRewardAmount() (map[string]map[common.Hash]
 	return m, nil
 }
 
-func (blockService BlockService) GetRewardAmount(paymentAddress string) (map[string]uint64, error) {
+// GetRewardAmount returns rewards of a payment address at the best view, or at the given height of its shard (0 means best view)
+func (blockService BlockService) GetRewardAmount(paymentAddress string, shardHeight uint64) (map[string]uint64, error) {
 	rewardAmountResult := make(map[string]uint64)
 	keySet, _, err := GetKeySetFromPaymentAddressParam(paymentAddress)
 	if err != nil {
@@ -675,8 +732,11 @@ func (blockService BlockService) GetRewardAmount(paymentAddress string) (map[str
 	if err != nil {
 		return nil, err
 	}
+	committeeRewardStateDB, err := blockService.getShardRewardStateDB(shardID, shardHeight)
+	if err != nil {
+		return nil, err
+	}
 	for _, coinID := range allCoinIDs {
-		committeeRewardStateDB := blockService.BlockChain.GetBestStateShard(shardID).GetShardRewardStateDB()
 		tempPK := base58.Base58Check{}.Encode(publicKey, common.Base58Version)
 		amount, err := statedb.GetCommitteeReward(committeeRewardStateDB, tempPK, coinID)
 		if err != nil {
@@ -693,7 +753,8 @@ func (blockService BlockService) GetRewardAmount(paymentAddress string) (map[str
 	return rewardAmountResult, nil
 }
 
-func (blockService BlockService) GetRewardAmountByPublicKey(publicKey string) (map[string]uint64, error) {
+// GetRewardAmountByPublicKey returns rewards of a public key at the best view, or at the given height of its shard (0 means best view)
+func (blockService BlockService) GetRewardAmountByPublicKey(publicKey string, shardHeight uint64) (map[string]uint64, error) {
 	rewardAmountResult := make(map[string]uint64)
 	tempPK, _, err := base58.Base58Check{}.Decode(publicKey)
 	if err != nil {
@@ -704,8 +765,11 @@ func (blockService BlockService) GetRewardAmountByPublicKey(publicKey string) (m
 	if err != nil {
 		return nil, err
 	}
+	committeeRewardStateDB, err := blockService.getShardRewardStateDB(shardID, shardHeight)
+	if err != nil {
+		return nil, err
+	}
 	for _, coinID := range allCoinIDs {
-		committeeRewardStateDB := blockService.BlockChain.GetBestStateShard(shardID).GetShardRewardStateDB()
 		amount, err := statedb.GetCommitteeReward(committeeRewardStateDB, publicKey, coinID)
 		if err != nil {
 			return nil, err
@@ -1144,3 +1208,87 @@ func (blockService BlockService) GetRewardFeatureByFeatureName(featureName strin
 
 	return data.GetTotalRewards(), nil
 }
+
+// GetCommitteeListAtHeight reads beacon and shard committees from the beacon consensus state at beaconHeight
+func (blockService BlockService) GetCommitteeListAtHeight(beaconHeight uint64) (*jsonresult.CommitteeListsResult, error) {
+	consensusStateDB, err := blockService.BlockChain.GetBeaconStateDBAtHeight(blockchain.ConsensusStateDBType, beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	beaconBlock, err := blockService.BlockChain.GetBeaconBlockByHeightV1(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	shardIDs := []int{}
+	for shardID := 0; shardID < blockService.BlockChain.GetBeaconBestState().ActiveShards; shardID++ {
+		shardIDs = append(shardIDs, shardID)
+	}
+	shardCommittee := make(map[byte][]incognitokey.CommitteePublicKey)
+	for shardID, committee := range statedb.GetAllShardCommittee(consensusStateDB, shardIDs) {
+		shardCommittee[byte(shardID)] = committee
+	}
+	shardPendingValidator := make(map[byte][]incognitokey.CommitteePublicKey)
+	for shardID, substitute := range statedb.GetAllShardSubstituteValidator(consensusStateDB, shardIDs) {
+		shardPendingValidator[byte(shardID)] = substitute
+	}
+	beaconCommittee := statedb.GetBeaconCommittee(consensusStateDB)
+	beaconPendingValidator := statedb.GetBeaconSubstituteValidator(consensusStateDB)
+	return jsonresult.NewCommitteeListsResult(beaconBlock.Header.Epoch, shardCommittee, shardPendingValidator, beaconCommittee, beaconPendingValidator), nil
+}
+
+func (blockService BlockService) GetDeploymentInfo() (*jsonresult.GetDeploymentInfoResult, *RPCError) {
+	if blockService.IsBeaconBestStateNil() {
+		return nil, NewRPCError(GetDeploymentInfoError, errors.New("Best State beacon not existed"))
+	}
+	beaconBestState := blockService.BlockChain.GetBeaconBestState()
+	chainParams := blockService.BlockChain.GetConfig().ChainParams
+	result := &jsonresult.GetDeploymentInfoResult{
+		BeaconHeight: beaconBestState.BeaconHeight,
+		Window:       chainParams.VersionBitsWindow,
+		Threshold:    chainParams.RuleChangeActivationThreshold,
+		Deployments:  []jsonresult.DeploymentInfo{},
+	}
+	for _, deployment := range chainParams.Deployments {
+		state, err := blockService.BlockChain.DeploymentState(beaconBestState, deployment.Name)
+		if err != nil {
+			return nil, NewRPCError(GetDeploymentInfoError, err)
+		}
+		result.Deployments = append(result.Deployments, jsonresult.DeploymentInfo{
+			Name:         deployment.Name,
+			Bit:          deployment.BitNumber,
+			StartHeight:  deployment.StartHeight,
+			ExpireHeight: deployment.ExpireHeight,
+			Status:       state.String(),
+		})
+	}
+	return result, nil
+}
+
+func (blockService BlockService) GetForkSchedule() (*jsonresult.GetForkScheduleResult, *RPCError) {
+	if blockService.IsBeaconBestStateNil() {
+		return nil, NewRPCError(UnexpectedError, errors.New("Best State beacon not existed"))
+	}
+	beaconHeight := blockService.BlockChain.GetBeaconBestState().BeaconHeight
+	chainParams := blockService.BlockChain.GetConfig().ChainParams
+	result := &jsonresult.GetForkScheduleResult{
+		BeaconHeight: beaconHeight,
+		EpochLength:  chainParams.Epoch,
+		Forks:        []jsonresult.ForkInfo{},
+	}
+	for _, fork := range forks.All {
+		activation, ok := chainParams.Forks[fork]
+		if !ok {
+			continue
+		}
+		activationHeight, _ := chainParams.Forks.ActivationHeight(fork, chainParams.Epoch)
+		result.Forks = append(result.Forks, jsonresult.ForkInfo{
+			Name:             string(fork),
+			Height:           activation.Height,
+			Epoch:            activation.Epoch,
+			Epochs:           activation.Epochs,
+			ActivationHeight: activationHeight,
+			Active:           chainParams.IsForkActive(fork, beaconHeight+1),
+		})
+	}
+	return result, nil
+}
@@ -124,6 +124,36 @@ const (
 	RestoreCandidateBeaconWaitingForNextRandom
 	RestoreCandidateShardWaitingForCurrentRandom
 	RestoreCandidateShardWaitingForNextRandom
+
+	// view selector
+	ResolveViewSelectorError
+
+	// storage
+	StorageCompactionError
+
+	// version bits
+	GetDeploymentInfoError
+
+	// light client
+	GetLightHeadersError
+	GetLightProofError
+	LightClientError
+	LightVerifyError
+
+	// logging
+	SetLogLevelsError
+
+	// tx lifecycle
+	TxLifecycleNotFoundError
+
+	// supply audit
+	AuditSupplyError
+
+	// multisig
+	BuildMultiSigTxError
+
+	// wallet accounts
+	WalletAccountError
 )
 
 // Standard JSON-RPC 2.0 errors.
@@ -249,6 +279,36 @@ var ErrCodeMessage = map[int]struct {
 	RestoreCandidateShardWaitingForCurrentRandom:  {-12007, "Restore candidate shard waiting for current random"},
 	RestoreCandidateShardWaitingForNextRandom:     {-12008, "Restore candidate shard waiting for next random"},
 	GetAllBeaconViews:                             {-12009, "Get all beacon views"},
+
+	// view selector
+	ResolveViewSelectorError: {-13001, "Resolve view selector error"},
+
+	// storage
+	StorageCompactionError: {-14001, "Storage compaction error"},
+
+	// version bits
+	GetDeploymentInfoError: {-15001, "Get deployment info error"},
+
+	// light client
+	GetLightHeadersError: {-16001, "Get light client headers error"},
+	GetLightProofError:   {-16002, "Get light client proof error"},
+	LightClientError:     {-16003, "Node is not a light client"},
+	LightVerifyError:     {-16004, "Light client verification error"},
+
+	// logging
+	SetLogLevelsError: {-17001, "Set log levels error"},
+
+	// tx lifecycle
+	TxLifecycleNotFoundError: {-18001, "Tx lifecycle not found"},
+
+	// supply audit
+	AuditSupplyError: {-19001, "Audit supply error"},
+
+	// multisig
+	BuildMultiSigTxError: {-20001, "Build multisig tx error"},
+
+	// wallet accounts
+	WalletAccountError: {-21001, "Wallet account error"},
 }
 
 // RPCError represents an error that is used as a part of a JSON-RPC JsonResponse
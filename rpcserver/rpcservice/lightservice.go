@@ -0,0 +1,179 @@
+package rpcservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/light"
+	"github.com/incognitochain/incognito-chain/peerv2"
+)
+
+// MaxLightHeaders is the number of headers served by one request of a light
+// client
+const MaxLightHeaders = 500
+
+// LightService serves light clients from a full node, and the light client of
+// a node in light mode
+type LightService struct {
+	BlockChain *blockchain.BlockChain
+	Client     *light.Client
+}
+
+// headerRange checks a range of heights and ends it at the final height of
+// a chain, light clients only follow final blocks
+func headerRange(from, to, finalHeight uint64) (uint64, *RPCError) {
+	if from == 0 || to < from {
+		return 0, NewRPCError(RPCInvalidParamsError, fmt.Errorf("invalid heights %v to %v", from, to))
+	}
+	if to-from >= MaxLightHeaders {
+		to = from + MaxLightHeaders - 1
+	}
+	if to > finalHeight {
+		to = finalHeight
+	}
+	return to, nil
+}
+
+// GetBeaconHeaders returns the final beacon blocks from from to to without
+// their bodies
+func (lightService LightService) GetBeaconHeaders(from, to uint64) ([]*blockchain.BeaconBlock, *RPCError) {
+	to, rpcErr := headerRange(from, to, lightService.BlockChain.BeaconChain.GetFinalView().GetHeight())
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	headers := []*blockchain.BeaconBlock{}
+	for height := from; height <= to; height++ {
+		blk, err := lightService.BlockChain.GetBeaconBlockByHeightV1(height)
+		if err != nil {
+			return nil, NewRPCError(GetLightHeadersError, err)
+		}
+		headers = append(headers, peerv2.BlockHeader(blk).(*blockchain.BeaconBlock))
+	}
+	return headers, nil
+}
+
+// GetShardHeaders returns the final blocks of a shard from from to to without
+// their bodies
+func (lightService LightService) GetShardHeaders(shardID byte, from, to uint64) ([]*blockchain.ShardBlock, *RPCError) {
+	if int(shardID) >= len(lightService.BlockChain.ShardChain) {
+		return nil, NewRPCError(RPCInvalidParamsError, fmt.Errorf("no shard %v", shardID))
+	}
+	to, rpcErr := headerRange(from, to, lightService.BlockChain.ShardChain[shardID].GetFinalView().GetHeight())
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	headers := []*blockchain.ShardBlock{}
+	for height := from; height <= to; height++ {
+		blk, err := lightService.BlockChain.GetShardBlockByHeightV1(height, shardID)
+		if err != nil {
+			return nil, NewRPCError(GetLightHeadersError, err)
+		}
+		headers = append(headers, peerv2.BlockHeader(blk).(*blockchain.ShardBlock))
+	}
+	return headers, nil
+}
+
+// GetCommitteeKeys returns the committee lists after the beacon block at
+// height, which its header commits to
+func (lightService LightService) GetCommitteeKeys(beaconHeight uint64) (*light.CommitteeLists, *RPCError) {
+	consensusStateDB, err := lightService.BlockChain.GetBeaconStateDBAtHeight(blockchain.ConsensusStateDBType, beaconHeight)
+	if err != nil {
+		return nil, NewRPCError(GetLightProofError, err)
+	}
+	shardIDs := []int{}
+	for shardID := 0; shardID < lightService.BlockChain.GetBeaconBestState().ActiveShards; shardID++ {
+		shardIDs = append(shardIDs, shardID)
+	}
+	shardCommittee := make(map[byte][]incognitokey.CommitteePublicKey)
+	for shardID, committee := range statedb.GetAllShardCommittee(consensusStateDB, shardIDs) {
+		shardCommittee[byte(shardID)] = committee
+	}
+	shardPendingValidator := make(map[byte][]incognitokey.CommitteePublicKey)
+	for shardID, substitute := range statedb.GetAllShardSubstituteValidator(consensusStateDB, shardIDs) {
+		shardPendingValidator[byte(shardID)] = substitute
+	}
+	lists, err := light.NewCommitteeLists(
+		beaconHeight,
+		statedb.GetBeaconCommittee(consensusStateDB),
+		statedb.GetBeaconSubstituteValidator(consensusStateDB),
+		shardCommittee,
+		shardPendingValidator,
+	)
+	if err != nil {
+		return nil, NewRPCError(GetLightProofError, err)
+	}
+	return lists, nil
+}
+
+// GetTxProof returns the Merkle proof of a transaction in its shard block
+func (lightService LightService) GetTxProof(txHashStr string) (*light.TxProof, *RPCError) {
+	txHash, err := common.Hash{}.NewHashFromStr(txHashStr)
+	if err != nil {
+		return nil, NewRPCError(RPCInvalidParamsError, errors.New("tx hash is invalid"))
+	}
+	_, blockHash, _, index, _, err := lightService.BlockChain.GetTransactionByHash(*txHash)
+	if err != nil {
+		return nil, NewRPCError(GetLightProofError, err)
+	}
+	block, _, err := lightService.BlockChain.GetShardBlockByHash(blockHash)
+	if err != nil {
+		return nil, NewRPCError(GetLightProofError, err)
+	}
+	proof, err := light.BuildTxProof(block, index)
+	if err != nil {
+		return nil, NewRPCError(GetLightProofError, err)
+	}
+	return proof, nil
+}
+
+func (lightService LightService) client() (*light.Client, *RPCError) {
+	if lightService.Client == nil {
+		return nil, NewRPCError(LightClientError, nil)
+	}
+	return lightService.Client, nil
+}
+
+// GetLightStatus returns the beacon chain followed by the light client
+func (lightService LightService) GetLightStatus() (*light.Status, *RPCError) {
+	client, rpcErr := lightService.client()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return client.Status(), nil
+}
+
+// VerifyTx verifies a transaction by its proof with the light client
+func (lightService LightService) VerifyTx(ctx context.Context, txHashStr string) (*light.VerifiedTx, *RPCError) {
+	client, rpcErr := lightService.client()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	txHash, err := common.Hash{}.NewHashFromStr(txHashStr)
+	if err != nil {
+		return nil, NewRPCError(RPCInvalidParamsError, errors.New("tx hash is invalid"))
+	}
+	tx, err := client.VerifyTx(ctx, *txHash)
+	if err != nil {
+		return nil, NewRPCError(LightVerifyError, err)
+	}
+	return tx, nil
+}
+
+// VerifyShardHeader verifies the header of a shard block with the light
+// client
+func (lightService LightService) VerifyShardHeader(ctx context.Context, shardID byte, height uint64) (*blockchain.ShardBlock, *RPCError) {
+	client, rpcErr := lightService.client()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	header, err := client.VerifyShardHeader(ctx, shardID, height)
+	if err != nil {
+		return nil, NewRPCError(LightVerifyError, err)
+	}
+	return header, nil
+}
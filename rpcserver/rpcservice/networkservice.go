@@ -1,9 +1,13 @@
 package rpcservice
 
-import "github.com/incognitochain/incognito-chain/connmanager"
+import (
+	"github.com/incognitochain/incognito-chain/connmanager"
+	"github.com/incognitochain/incognito-chain/peerv2"
+)
 
 type NetworkService struct{
-	ConnMgr *connmanager.ConnManager
+	ConnMgr    *connmanager.ConnManager
+	Reputation *peerv2.Reputation
 }
 
 func (networkService  NetworkService) GetConnectionCount() int {
@@ -14,3 +18,12 @@ func (networkService  NetworkService) GetConnectionCount() int {
 	listeningPeer := networkService.ConnMgr.GetListeningPeer()
 	return len(listeningPeer.GetPeerConns())
 }
+
+// GetPeerReputations returns the scores of the peers, the lowest first
+func (networkService NetworkService) GetPeerReputations() []peerv2.PeerReputation {
+	reputations := networkService.Reputation.Scores()
+	if reputations == nil {
+		return []peerv2.PeerReputation{}
+	}
+	return reputations
+}
@@ -0,0 +1,130 @@
+package rpcservice
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/databasemp"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+)
+
+// storageStatProperty is asked to every engine, leveldb reports its levels, other engines ignore the name
+const storageStatProperty = "leveldb.stats"
+
+type StorageService struct {
+	DB         map[int]incdb.Database
+	MempoolDB  databasemp.DatabaseInterface
+	BTCDataDir string
+	Compactor  *incdb.Compactor
+}
+
+func (storageService StorageService) GetStorageStats() (*jsonresult.GetStorageStatsResult, *RPCError) {
+	result := &jsonresult.GetStorageStatsResult{
+		Databases: []jsonresult.DatabaseStorageStats{},
+	}
+	chainIDs := []int{}
+	for chainID := range storageService.DB {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Ints(chainIDs)
+	for _, chainID := range chainIDs {
+		name := common.BeaconChainDatabaseDirectory
+		if chainID != common.BeaconChainDataBaseID {
+			name = fmt.Sprintf("%v%v", common.ShardChainDatabaseDirectory, chainID)
+		}
+		result.Databases = append(result.Databases, getChainDatabaseStats(name, storageService.DB[chainID]))
+	}
+	if storageService.MempoolDB != nil {
+		result.Databases = append(result.Databases, getDatabaseStats("mempool", storageService.MempoolDB))
+	}
+	if storageService.BTCDataDir != "" {
+		stats := jsonresult.DatabaseStorageStats{Name: "btcrelaying", Path: storageService.BTCDataDir}
+		size, err := incdb.DirSize(storageService.BTCDataDir)
+		if err != nil {
+			stats.Error = err.Error()
+		}
+		stats.DiskSize = size
+		result.Databases = append(result.Databases, stats)
+	}
+	for _, stats := range result.Databases {
+		result.TotalDiskSize += stats.DiskSize
+	}
+	if storageService.Compactor != nil {
+		result.Compaction = jsonresult.NewCompactionStatusResult(storageService.Compactor.Status())
+	}
+	return result, nil
+}
+
+// getDatabaseStats reports the disk usage and engine stats of any database which exposes its path
+func getDatabaseStats(name string, db interface{}) jsonresult.DatabaseStorageStats {
+	stats := jsonresult.DatabaseStorageStats{Name: name}
+	if stater, ok := db.(incdb.Stater); ok {
+		if s, err := stater.Stat(storageStatProperty); err == nil {
+			stats.Stats = s
+		}
+	}
+	pather, ok := db.(interface{ Path() string })
+	if !ok {
+		return stats
+	}
+	stats.Path = pather.Path()
+	size, err := incdb.DirSize(stats.Path)
+	if err != nil {
+		stats.Error = err.Error()
+	}
+	stats.DiskSize = size
+	return stats
+}
+
+// getChainDatabaseStats adds the usage of each rawdbv2 key prefix, the rest of the space is used by state trie nodes
+func getChainDatabaseStats(name string, db incdb.Database) jsonresult.DatabaseStorageStats {
+	stats := getDatabaseStats(name, db)
+	stats.Prefixes = []jsonresult.PrefixStorageStats{}
+	used := uint64(0)
+	for _, schemaPrefix := range rawdbv2.GetSchemaPrefixes() {
+		size, err := incdb.PrefixSize(db, schemaPrefix.Prefix)
+		if err != nil {
+			stats.Error = err.Error()
+			continue
+		}
+		used += size
+		stats.Prefixes = append(stats.Prefixes, jsonresult.PrefixStorageStats{
+			Name:   schemaPrefix.Name,
+			Prefix: string(schemaPrefix.Prefix),
+			Size:   size,
+		})
+	}
+	if stats.DiskSize > used {
+		stats.Prefixes = append(stats.Prefixes, jsonresult.PrefixStorageStats{
+			Name: "StateTrieAndOthers",
+			Size: stats.DiskSize - used,
+		})
+	}
+	return stats
+}
+
+func (storageService StorageService) StartCompaction() (*jsonresult.CompactionStatusResult, *RPCError) {
+	if storageService.Compactor == nil {
+		return nil, NewRPCError(StorageCompactionError, errors.New("compaction is not available"))
+	}
+	if err := storageService.Compactor.Start(); err != nil {
+		return nil, NewRPCError(StorageCompactionError, err)
+	}
+	result := jsonresult.NewCompactionStatusResult(storageService.Compactor.Status())
+	return &result, nil
+}
+
+func (storageService StorageService) StopCompaction() (*jsonresult.CompactionStatusResult, *RPCError) {
+	if storageService.Compactor == nil {
+		return nil, NewRPCError(StorageCompactionError, errors.New("compaction is not available"))
+	}
+	if err := storageService.Compactor.Stop(); err != nil {
+		return nil, NewRPCError(StorageCompactionError, err)
+	}
+	result := jsonresult.NewCompactionStatusResult(storageService.Compactor.Status())
+	return &result, nil
+}
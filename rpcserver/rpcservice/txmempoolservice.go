@@ -5,6 +5,7 @@ import (
 	"github.com/incognitochain/incognito-chain/mempool"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/incognitochain/incognito-chain/txtracker"
 )
 
 type TxMemPoolService struct {
@@ -59,6 +60,7 @@ func (txMemPoolService *TxMemPoolService) RemoveTxInMempool(txIDString string) (
 		return false, NewRPCError(GeTxFromPoolError, err)
 	}
 	txMemPoolService.TxMemPool.RemoveTx([]metadata.Transaction{tempTx}, false)
+	txtracker.Evicted(tempTx, "removed by rpc")
 	txMemPoolService.TxMemPool.TriggerCRemoveTxs(tempTx)
 
 	return true, nil
@@ -19,9 +19,11 @@ import (
 	"github.com/incognitochain/incognito-chain/mempool"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
 	"github.com/incognitochain/incognito-chain/rpcserver/bean"
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/txtracker"
 	"github.com/incognitochain/incognito-chain/wallet"
 	"github.com/incognitochain/incognito-chain/wire"
 )
@@ -341,7 +343,7 @@ func (txService TxService) EstimateFeeWithEstimator(defaultFee int64, shardID by
 		return unitFee, nil
 	} else {
 		// convert limit fee native token to limit fee ptoken
-		limitFeePTokenTmp, err := metadata.ConvertNativeTokenToPrivacyToken(limitFee, tokenId, beaconHeight, txService.BlockChain.GetBeaconBestState().GetBeaconFeatureStateDB())
+		limitFeePTokenTmp, err := metadata.ConvertNativeTokenToPrivacyToken(txService.BlockChain, limitFee, tokenId, beaconHeight, txService.BlockChain.GetBeaconBestState().GetBeaconFeatureStateDB())
 		limitFeePToken := uint64(math.Ceil(limitFeePTokenTmp))
 		if err != nil {
 			return uint64(0), err
@@ -416,6 +418,7 @@ func (txService TxService) SendRawTransaction(txB58Check string) (wire.Message,
 		Logger.log.Errorf("Send Raw Transaction Error: %+v", err)
 		return nil, nil, byte(0), NewRPCError(JsonDataOfTxInvalid, err)
 	}
+	txtracker.Received(&tx, txtracker.SourceRPC)
 
 	beaconHeigh := int64(-1)
 	beaconBestState, err := txService.BlockChain.GetClonedBeaconBestState()
@@ -884,6 +887,7 @@ func (txService TxService) GetTransactionByHash(txHashStr string) (*jsonresult.T
 			return nil, NewRPCError(UnexpectedError, errM)
 		}
 		result.IsInMempool = true
+		result.Status = jsonresult.TxStatusPending
 		return result, nil
 	}
 
@@ -892,10 +896,30 @@ func (txService TxService) GetTransactionByHash(txHashStr string) (*jsonresult.T
 		return nil, NewRPCError(UnexpectedError, err)
 	}
 	result.IsInBlock = true
+	result.Status, result.Confirmations = txService.getConfirmationStatus(shardID, blockHash, blockHeight)
 	Logger.log.Debugf("handleGetTransactionByHash result: %+v", result)
 	return result, nil
 }
 
+// getConfirmationStatus checks whether the block containing a tx is still in the best chain of its shard
+// and how many blocks have been built on top of it
+func (txService TxService) getConfirmationStatus(shardID byte, blockHash common.Hash, blockHeight uint64) (string, uint64) {
+	if int(shardID) >= len(txService.BlockChain.ShardChain) {
+		return jsonresult.TxStatusOrphaned, 0
+	}
+	shardChain := txService.BlockChain.ShardChain[shardID]
+	finalView, bestView := shardChain.GetFinalView(), shardChain.GetBestView()
+	hash, err := txService.BlockChain.GetShardBlockHashByHeight(finalView, bestView, blockHeight)
+	if err != nil || !hash.IsEqual(&blockHash) {
+		return jsonresult.TxStatusOrphaned, 0
+	}
+	confirmations := bestView.GetHeight() - blockHeight + 1
+	if blockHeight <= finalView.GetHeight() {
+		return jsonresult.TxStatusFinalized, confirmations
+	}
+	return jsonresult.TxStatusInBestChain, confirmations
+}
+
 func (txService TxService) ListPrivacyCustomToken() (map[common.Hash]*statedb.TokenState, error) {
 	tokenStates, err := txService.BlockChain.ListAllPrivacyCustomTokenAndPRV()
 	if err != nil {
@@ -1187,6 +1211,7 @@ func (txService TxService) SendRawPrivacyCustomTokenTransaction(base58CheckData
 		Logger.log.Debugf("handleSendRawPrivacyCustomTokenTransaction result: %+v, err: %+v", nil, err)
 		return nil, nil, NewRPCError(RPCInvalidParamsError, err)
 	}
+	txtracker.Received(&tx, txtracker.SourceRPC)
 
 	beaconHeigh := int64(-1)
 	beaconBestState, err := txService.BlockChain.GetClonedBeaconBestState()
@@ -1865,3 +1890,106 @@ func (txService TxService) BuildDefragmentPrivacyCustomTokenParam(tokenParamsRaw
 	}
 	return tokenParams, nil, nil, nil
 }
+
+// multiSigOutCoins returns the coins tokenID of the group of keySet whose
+// SNDerivators are not in spentSNDs
+func (txService TxService) multiSigOutCoins(keySet *incognitokey.KeySet, shardID byte, tokenID *common.Hash, spentSNDs map[string]bool) ([]*privacy.OutputCoin, error) {
+	outCoins, err := txService.BlockChain.GetListOutputCoinsByKeyset(keySet, shardID, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*privacy.OutputCoin, 0, len(outCoins))
+	for _, outCoin := range outCoins {
+		if !spentSNDs[string(outCoin.CoinDetails.GetSNDerivator().ToBytesS())] {
+			result = append(result, outCoin)
+		}
+	}
+	return result, nil
+}
+
+// BuildMultiSigTransaction returns the unsigned tx sending paymentInfos, and
+// the token receivers of tokenParams if not nil, from the payment address of
+// group, to be signed by signers. The node decrypts the coins of the group
+// with its readonly key but cannot tell which ones are spent, the coins spent
+// by the previous txs of the group are excluded by their SNDerivators
+func (txService TxService) BuildMultiSigTransaction(
+	group *multisig.GroupKey,
+	readonlyKey privacy.ViewingKey,
+	signers []uint32,
+	paymentInfos []*privacy.PaymentInfo,
+	unitFee int64,
+	tokenParams *transaction.CustomTokenPrivacyParamTx,
+	spentSNDs map[string]bool,
+	info []byte,
+) (*transaction.MultiSigTx, *RPCError) {
+	keySet := &incognitokey.KeySet{
+		PaymentAddress: group.PaymentAddress(),
+		ReadonlyKey:    readonlyKey,
+	}
+	if !bytes.Equal(readonlyKey.Pk, group.PublicKey) {
+		return nil, NewRPCError(RPCInvalidParamsError, errors.New("readonly key is not the key of the group"))
+	}
+	shardID := group.ShardID()
+	beaconState, err := txService.BlockChain.GetClonedBeaconBestState()
+	if err != nil {
+		return nil, NewRPCError(GetClonedBeaconBestStateError, err)
+	}
+
+	if tokenParams != nil {
+		tokenID, err := common.Hash{}.NewHashFromStr(tokenParams.PropertyID)
+		if err != nil {
+			return nil, NewRPCError(RPCInvalidParamsError, errors.New("Invalid Token ID"))
+		}
+		tokenAmount := uint64(0)
+		for _, receiver := range tokenParams.Receiver {
+			tokenAmount += receiver.Amount
+		}
+		outputTokens, err := txService.multiSigOutCoins(keySet, shardID, tokenID, spentSNDs)
+		if err != nil {
+			return nil, NewRPCError(GetOutputCoinError, err)
+		}
+		candidateOutputTokens, _, _, err := txService.chooseBestOutCoinsToSpent(outputTokens, tokenAmount)
+		if err != nil {
+			return nil, NewRPCError(GetOutputCoinError, err)
+		}
+		tokenParams.TokenInput = transaction.ConvertOutputCoinToInputCoin(candidateOutputTokens)
+	}
+
+	totalAmount := uint64(0)
+	for _, paymentInfo := range paymentInfos {
+		totalAmount += paymentInfo.Amount
+	}
+	outCoins, err := txService.multiSigOutCoins(keySet, shardID, &common.PRVCoinID, spentSNDs)
+	if err != nil {
+		return nil, NewRPCError(GetOutputCoinError, err)
+	}
+	candidateOutputCoins, outCoins, candidateAmount, _ := txService.chooseBestOutCoinsToSpent(outCoins, totalAmount)
+	// the change goes back to the group
+	estimatePaymentInfos := append(append([]*privacy.PaymentInfo{}, paymentInfos...), &privacy.PaymentInfo{PaymentAddress: keySet.PaymentAddress})
+	fee, _, _, err := txService.EstimateFee(unitFee, false, candidateOutputCoins, estimatePaymentInfos, shardID, 0, false, nil, tokenParams, int64(beaconState.BeaconHeight))
+	if err != nil {
+		return nil, NewRPCError(RejectInvalidTxFeeError, err)
+	}
+	if candidateAmount < totalAmount+fee {
+		candidateOutputCoinsForFee, _, _, err := txService.chooseBestOutCoinsToSpent(outCoins, totalAmount+fee-candidateAmount)
+		if err != nil {
+			return nil, NewRPCError(GetOutputCoinError, err)
+		}
+		candidateOutputCoins = append(candidateOutputCoins, candidateOutputCoinsForFee...)
+	}
+
+	multiSigTx, err := transaction.NewMultiSigTx(&transaction.MultiSigTxParams{
+		Group:       group,
+		Signers:     signers,
+		PaymentInfo: paymentInfos,
+		InputCoins:  transaction.ConvertOutputCoinToInputCoin(candidateOutputCoins),
+		Fee:         fee,
+		TokenParams: tokenParams,
+		StateDB:     txService.BlockChain.GetBestStateShard(shardID).GetCopiedTransactionStateDB(),
+		Info:        info,
+	})
+	if err != nil {
+		return nil, NewRPCError(BuildMultiSigTxError, err)
+	}
+	return multiSigTx, nil
+}
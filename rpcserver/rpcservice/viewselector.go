@@ -0,0 +1,239 @@
+package rpcservice
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+const (
+	BestViewSelector   = "best"
+	FinalViewSelector  = "final"
+	HashViewSelector   = "hash"
+	HeightViewSelector = "height"
+)
+
+// ViewSelector tells read RPCs which view of a chain the answer must come from:
+// the best view, the final view, the view of a specific block hash or the block at a height in the best chain
+type ViewSelector struct {
+	Type   string
+	Hash   common.Hash
+	Height uint64
+}
+
+// ResolvedView is the block a ViewSelector points to
+type ResolvedView struct {
+	Hash    common.Hash
+	Height  uint64
+	IsFinal bool
+	View    multiview.View // nil if the block is older than final view
+}
+
+// ParseViewSelector accepts nil/"" (best), "best", "final", a block hash string or a block height (number or numeric string)
+func ParseViewSelector(param interface{}) (*ViewSelector, error) {
+	switch value := param.(type) {
+	case nil:
+		return &ViewSelector{Type: BestViewSelector}, nil
+	case float64:
+		if value < 0 {
+			return nil, fmt.Errorf("block height %v is invalid", value)
+		}
+		return &ViewSelector{Type: HeightViewSelector, Height: uint64(value)}, nil
+	case string:
+		switch value {
+		case "", BestViewSelector:
+			return &ViewSelector{Type: BestViewSelector}, nil
+		case FinalViewSelector:
+			return &ViewSelector{Type: FinalViewSelector}, nil
+		}
+		if height, err := strconv.ParseUint(value, 10, 64); err == nil && len(value) < common.HashSize {
+			return &ViewSelector{Type: HeightViewSelector, Height: height}, nil
+		}
+		hash, err := common.Hash{}.NewHashFromStr(value)
+		if err != nil {
+			return nil, fmt.Errorf("view selector %+v is neither best, final, block hash nor block height", value)
+		}
+		return &ViewSelector{Type: HashViewSelector, Hash: *hash}, nil
+	}
+	return nil, fmt.Errorf("view selector %+v has invalid type", param)
+}
+
+// ParseOptionalViewSelector reads the selector at the given index of the params array, missing selector means best view
+func ParseOptionalViewSelector(arrayParams []interface{}, index int) (*ViewSelector, *RPCError) {
+	if len(arrayParams) <= index {
+		return &ViewSelector{Type: BestViewSelector}, nil
+	}
+	selector, err := ParseViewSelector(arrayParams[index])
+	if err != nil {
+		return nil, NewRPCError(RPCInvalidParamsError, err)
+	}
+	return selector, nil
+}
+
+func (blockService BlockService) ResolveBeaconView(selector *ViewSelector) (*ResolvedView, *RPCError) {
+	beaconChain := blockService.BlockChain.BeaconChain
+	finalView, bestView := beaconChain.GetFinalView(), beaconChain.GetBestView()
+	resolved, err := resolveView(selector, finalView, bestView, beaconChain.GetViewByHash,
+		func(height uint64) (*common.Hash, error) {
+			return blockService.BlockChain.GetBeaconBlockHashByHeight(finalView, bestView, height)
+		},
+		func(hash common.Hash) (uint64, error) {
+			block, _, err := blockService.BlockChain.GetBeaconBlockByHash(hash)
+			if err != nil {
+				return 0, err
+			}
+			return block.GetHeight(), nil
+		})
+	if err != nil {
+		return nil, NewRPCError(ResolveViewSelectorError, err)
+	}
+	return resolved, nil
+}
+
+func (blockService BlockService) ResolveShardView(shardID byte, selector *ViewSelector) (*ResolvedView, *RPCError) {
+	if int(shardID) >= len(blockService.BlockChain.ShardChain) {
+		return nil, NewRPCError(ResolveViewSelectorError, fmt.Errorf("shard %+v not found", shardID))
+	}
+	shardChain := blockService.BlockChain.ShardChain[shardID]
+	finalView, bestView := shardChain.GetFinalView(), shardChain.GetBestView()
+	resolved, err := resolveView(selector, finalView, bestView, shardChain.GetViewByHash,
+		func(height uint64) (*common.Hash, error) {
+			return blockService.BlockChain.GetShardBlockHashByHeight(finalView, bestView, height)
+		},
+		func(hash common.Hash) (uint64, error) {
+			block, _, err := blockService.BlockChain.GetShardBlockByHashWithShardID(hash, shardID)
+			if err != nil {
+				return 0, err
+			}
+			return block.GetHeight(), nil
+		})
+	if err != nil {
+		return nil, NewRPCError(ResolveViewSelectorError, err)
+	}
+	return resolved, nil
+}
+
+// ResolveShardBestState returns the shard view (with its state) the selector points to.
+// Only views which are still kept in multiview (from final view up) have state available.
+func (blockService BlockService) ResolveShardBestState(shardID byte, selector *ViewSelector) (*blockchain.ShardBestState, bool, *RPCError) {
+	resolved, err := blockService.ResolveShardView(shardID, selector)
+	if err != nil {
+		return nil, false, err
+	}
+	if resolved.View == nil {
+		return nil, false, NewRPCError(ResolveViewSelectorError, fmt.Errorf("state of shard %+v block %+v is older than final view and not available", shardID, resolved.Hash.String()))
+	}
+	return resolved.View.(*blockchain.ShardBestState), resolved.IsFinal, nil
+}
+
+func resolveView(
+	selector *ViewSelector,
+	finalView, bestView multiview.View,
+	getViewByHash func(common.Hash) multiview.View,
+	getHashByHeight func(uint64) (*common.Hash, error),
+	getHeightByHash func(common.Hash) (uint64, error),
+) (*ResolvedView, error) {
+	if selector == nil {
+		selector = &ViewSelector{Type: BestViewSelector}
+	}
+	switch selector.Type {
+	case BestViewSelector:
+		return &ResolvedView{
+			Hash:    *bestView.GetHash(),
+			Height:  bestView.GetHeight(),
+			IsFinal: bestView.GetHash().IsEqual(finalView.GetHash()),
+			View:    bestView,
+		}, nil
+	case FinalViewSelector:
+		return &ResolvedView{
+			Hash:    *finalView.GetHash(),
+			Height:  finalView.GetHeight(),
+			IsFinal: true,
+			View:    finalView,
+		}, nil
+	case HeightViewSelector:
+		hash, err := getHashByHeight(selector.Height)
+		if err != nil {
+			return nil, err
+		}
+		return &ResolvedView{
+			Hash:    *hash,
+			Height:  selector.Height,
+			IsFinal: selector.Height <= finalView.GetHeight(),
+			View:    getViewByHash(*hash),
+		}, nil
+	case HashViewSelector:
+		if view := getViewByHash(selector.Hash); view != nil {
+			return &ResolvedView{
+				Hash:    selector.Hash,
+				Height:  view.GetHeight(),
+				IsFinal: view.GetHash().IsEqual(finalView.GetHash()),
+				View:    view,
+			}, nil
+		}
+		height, err := getHeightByHash(selector.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if height > finalView.GetHeight() {
+			return nil, fmt.Errorf("block %+v is not in any view of this chain", selector.Hash.String())
+		}
+		// older than final view, it must be the finalized block at this height
+		hash, err := getHashByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if !hash.IsEqual(&selector.Hash) {
+			return nil, fmt.Errorf("block %+v is not in the finalized chain", selector.Hash.String())
+		}
+		return &ResolvedView{Hash: selector.Hash, Height: height, IsFinal: true}, nil
+	}
+	return nil, errors.New("unknown view selector type " + selector.Type)
+}
+
+// ResolveBeaconHeightFromPayload reads the beacon height of a state query from its payload.
+// "View" selector has priority over "BeaconHeight" which is kept for backward compatibility.
+func (blockService BlockService) ResolveBeaconHeightFromPayload(data map[string]interface{}) (uint64, bool, *RPCError) {
+	if viewParam, ok := data["View"]; ok {
+		selector, err := ParseViewSelector(viewParam)
+		if err != nil {
+			return 0, false, NewRPCError(RPCInvalidParamsError, err)
+		}
+		resolved, rpcErr := blockService.ResolveBeaconView(selector)
+		if rpcErr != nil {
+			return 0, false, rpcErr
+		}
+		return resolved.Height, resolved.IsFinal, nil
+	}
+	var beaconHeight uint64
+	if heightParam, ok := data["BeaconHeight"].(float64); ok {
+		beaconHeight = uint64(heightParam)
+	} else {
+		var err error
+		beaconHeight, err = common.AssertAndConvertStrToNumber(data["BeaconHeight"])
+		if err != nil {
+			return 0, false, NewRPCError(RPCInvalidParamsError, errors.New("Beacon height is invalid"))
+		}
+	}
+	return beaconHeight, beaconHeight <= blockService.BlockChain.BeaconChain.GetFinalView().GetHeight(), nil
+}
+
+// ParseOptionalHeight reads an optional block height (number or numeric string) at the given index of the params array,
+// 0 is returned when it is missing, meaning the best view
+func ParseOptionalHeight(arrayParams []interface{}, index int) (uint64, *RPCError) {
+	if len(arrayParams) <= index || arrayParams[index] == nil {
+		return 0, nil
+	}
+	if height, ok := arrayParams[index].(float64); ok && height >= 0 {
+		return uint64(height), nil
+	}
+	height, err := common.AssertAndConvertStrToNumber(arrayParams[index])
+	if err != nil {
+		return 0, NewRPCError(RPCInvalidParamsError, errors.New("height is invalid"))
+	}
+	return height, nil
+}
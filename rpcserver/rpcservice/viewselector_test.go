@@ -0,0 +1,106 @@
+package rpcservice
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+type fakeView struct {
+	hash     common.Hash
+	prevHash common.Hash
+	height   uint64
+}
+
+func (v *fakeView) GetHash() *common.Hash                           { return &v.hash }
+func (v *fakeView) GetPreviousHash() *common.Hash                   { return &v.prevHash }
+func (v *fakeView) GetHeight() uint64                               { return v.height }
+func (v *fakeView) GetCommittee() []incognitokey.CommitteePublicKey { return nil }
+func (v *fakeView) GetBlock() common.BlockInterface                 { return nil }
+func (v *fakeView) GetProposerByTimeSlot(ts int64, version int) incognitokey.CommitteePublicKey {
+	return incognitokey.CommitteePublicKey{}
+}
+
+func TestParseViewSelector(t *testing.T) {
+	hash := common.Hash{1, 2, 3}
+	tests := []struct {
+		param   interface{}
+		want    ViewSelector
+		wantErr bool
+	}{
+		{nil, ViewSelector{Type: BestViewSelector}, false},
+		{"", ViewSelector{Type: BestViewSelector}, false},
+		{"best", ViewSelector{Type: BestViewSelector}, false},
+		{"final", ViewSelector{Type: FinalViewSelector}, false},
+		{float64(100), ViewSelector{Type: HeightViewSelector, Height: 100}, false},
+		{"100", ViewSelector{Type: HeightViewSelector, Height: 100}, false},
+		{hash.String(), ViewSelector{Type: HashViewSelector, Hash: hash}, false},
+		{float64(-1), ViewSelector{}, true},
+		{"latest", ViewSelector{}, true},
+		{true, ViewSelector{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseViewSelector(tt.param)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseViewSelector(%v) error = %v, wantErr %v", tt.param, err, tt.wantErr)
+			continue
+		}
+		if err == nil && *got != tt.want {
+			t.Errorf("ParseViewSelector(%v) = %+v, want %+v", tt.param, *got, tt.want)
+		}
+	}
+}
+
+func TestResolveView(t *testing.T) {
+	views := map[common.Hash]multiview.View{}
+	chain := []*fakeView{}
+	for i := 1; i <= 5; i++ {
+		v := &fakeView{hash: common.Hash{byte(i)}, prevHash: common.Hash{byte(i - 1)}, height: uint64(i)}
+		chain = append(chain, v)
+		if i >= 3 {
+			views[v.hash] = v
+		}
+	}
+	finalView, bestView := chain[2], chain[4]
+	getViewByHash := func(hash common.Hash) multiview.View { return views[hash] }
+	getHashByHeight := func(height uint64) (*common.Hash, error) { return &chain[height-1].hash, nil }
+	getHeightByHash := func(hash common.Hash) (uint64, error) { return uint64(hash[0]), nil }
+
+	resolve := func(selector *ViewSelector) *ResolvedView {
+		resolved, err := resolveView(selector, finalView, bestView, getViewByHash, getHashByHeight, getHeightByHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resolved
+	}
+	if r := resolve(&ViewSelector{Type: BestViewSelector}); r.Height != 5 || r.IsFinal {
+		t.Errorf("wrong best view %+v", r)
+	}
+	if r := resolve(&ViewSelector{Type: FinalViewSelector}); r.Height != 3 || !r.IsFinal {
+		t.Errorf("wrong final view %+v", r)
+	}
+	if r := resolve(&ViewSelector{Type: HeightViewSelector, Height: 4}); r.Height != 4 || r.IsFinal || r.View == nil {
+		t.Errorf("wrong view at height 4 %+v", r)
+	}
+	if r := resolve(&ViewSelector{Type: HashViewSelector, Hash: common.Hash{2}}); r.Height != 2 || !r.IsFinal || r.View != nil {
+		t.Errorf("wrong view of old block %+v", r)
+	}
+}
+
+func TestParseOptionalHeight(t *testing.T) {
+	params := []interface{}{"address", float64(10), "20", "abc"}
+	if h, err := ParseOptionalHeight(params, 1); err != nil || h != 10 {
+		t.Errorf("wrong height %v %v", h, err)
+	}
+	if h, err := ParseOptionalHeight(params, 2); err != nil || h != 20 {
+		t.Errorf("wrong height %v %v", h, err)
+	}
+	if _, err := ParseOptionalHeight(params, 3); err == nil {
+		t.Error("expect error for invalid height")
+	}
+	if h, err := ParseOptionalHeight(params, 4); err != nil || h != 0 {
+		t.Errorf("missing height must be 0, got %v %v", h, err)
+	}
+}
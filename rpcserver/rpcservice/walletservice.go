@@ -3,11 +3,15 @@ package rpcservice
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"log"
 	"math/rand"
 
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/privacy"
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/wallet"
 )
@@ -162,7 +166,7 @@ func (walletService WalletService) GetBalanceByPaymentAddress(paymentAddress str
 	return balance, nil
 }
 
-func (walletService WalletService) GetBalance(accountName string) (uint64, *RPCError) {
+func (walletService WalletService) GetBalance(accountName string, selector *ViewSelector) (uint64, *RPCError) {
 	prvCoinID := &common.Hash{}
 	err1 := prvCoinID.SetBytes(common.PRVCoinID[:])
 	if err1 != nil {
@@ -175,9 +179,9 @@ func (walletService WalletService) GetBalance(accountName string) (uint64, *RPCE
 		for _, account := range walletService.Wallet.MasterAccount.Child {
 			lastByte := account.Key.KeySet.PaymentAddress.Pk[len(account.Key.KeySet.PaymentAddress.Pk)-1]
 			shardIDSender := common.GetShardIDFromLastByte(lastByte)
-			outCoins, err := walletService.BlockChain.GetListOutputCoinsByKeyset(&account.Key.KeySet, shardIDSender, prvCoinID)
+			outCoins, err := walletService.getListOutputCoinsByKeyset(&account.Key.KeySet, shardIDSender, prvCoinID, selector)
 			if err != nil {
-				return uint64(0), NewRPCError(UnexpectedError, err)
+				return uint64(0), err
 			}
 			for _, out := range outCoins {
 				balance += out.CoinDetails.GetValue()
@@ -189,9 +193,9 @@ func (walletService WalletService) GetBalance(accountName string) (uint64, *RPCE
 				// get balance for accountName in wallet
 				lastByte := account.Key.KeySet.PaymentAddress.Pk[len(account.Key.KeySet.PaymentAddress.Pk)-1]
 				shardIDSender := common.GetShardIDFromLastByte(lastByte)
-				outCoins, err := walletService.BlockChain.GetListOutputCoinsByKeyset(&account.Key.KeySet, shardIDSender, prvCoinID)
+				outCoins, err := walletService.getListOutputCoinsByKeyset(&account.Key.KeySet, shardIDSender, prvCoinID, selector)
 				if err != nil {
-					return uint64(0), NewRPCError(UnexpectedError, err)
+					return uint64(0), err
 				}
 				for _, out := range outCoins {
 					balance += out.CoinDetails.GetValue()
@@ -204,6 +208,26 @@ func (walletService WalletService) GetBalance(accountName string) (uint64, *RPCE
 	return balance, nil
 }
 
+// getListOutputCoinsByKeyset reads output coins from the best view (cached) or from the shard view chosen by selector
+func (walletService WalletService) getListOutputCoinsByKeyset(keySet *incognitokey.KeySet, shardID byte, tokenID *common.Hash, selector *ViewSelector) ([]*privacy.OutputCoin, *RPCError) {
+	if selector == nil || selector.Type == BestViewSelector {
+		outCoins, err := walletService.BlockChain.GetListOutputCoinsByKeyset(keySet, shardID, tokenID)
+		if err != nil {
+			return nil, NewRPCError(UnexpectedError, err)
+		}
+		return outCoins, nil
+	}
+	shardView, _, rpcErr := BlockService{BlockChain: walletService.BlockChain}.ResolveShardBestState(shardID, selector)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	outCoins, err := walletService.BlockChain.GetListOutputCoinsByKeysetFromView(shardView, keySet, tokenID)
+	if err != nil {
+		return nil, NewRPCError(UnexpectedError, err)
+	}
+	return outCoins, nil
+}
+
 func (walletService WalletService) GetReceivedByAccount(accountName string) (uint64, *RPCError) {
 	balance := uint64(0)
 	for _, account := range walletService.Wallet.MasterAccount.Child {
@@ -228,3 +252,65 @@ func (walletService WalletService) GetReceivedByAccount(accountName string) (uin
 	}
 	return balance, nil
 }
+
+func (walletService *WalletService) CreatePathAccount(accountName string, account uint32, shardID *byte, passPhrase string) (jsonresult.WalletAccountResult, *RPCError) {
+	if shardID != nil && int(*shardID) >= walletService.BlockChain.GetBeaconBestState().ActiveShards {
+		return jsonresult.WalletAccountResult{}, NewRPCError(RPCInvalidParamsError, fmt.Errorf("shard %d is not active", *shardID))
+	}
+	accountWallet, err := walletService.Wallet.CreatePathAccount(accountName, account, shardID, passPhrase)
+	if err != nil {
+		return jsonresult.WalletAccountResult{}, NewRPCError(WalletAccountError, err)
+	}
+	return jsonresult.NewWalletAccountResult(*accountWallet), nil
+}
+
+// DiscoverAccounts adds the accounts of the seed of the wallet which received
+// PRV or a privacy token in the best state of their shard
+func (walletService *WalletService) DiscoverAccounts(gapLimit uint32, passPhrase string) ([]jsonresult.WalletAccountResult, *RPCError) {
+	activeShards := walletService.BlockChain.GetBeaconBestState().ActiveShards
+	stateDBs := map[byte]*statedb.StateDB{}
+	tokenIDs := map[byte][]common.Hash{}
+	isUsed := func(key *wallet.KeyWallet) (bool, error) {
+		shardID := key.ShardID()
+		if int(shardID) >= activeShards {
+			// no coin is sent to the addresses of an inactive shard
+			return false, nil
+		}
+		stateDB, ok := stateDBs[shardID]
+		if !ok {
+			stateDB = walletService.BlockChain.GetBestStateShard(shardID).GetCopiedTransactionStateDB()
+			stateDBs[shardID] = stateDB
+			tokenIDs[shardID] = []common.Hash{common.PRVCoinID}
+			for tokenID := range statedb.ListPrivacyToken(stateDB) {
+				tokenIDs[shardID] = append(tokenIDs[shardID], tokenID)
+			}
+		}
+		for _, tokenID := range tokenIDs[shardID] {
+			outCoins, err := statedb.GetOutcoinsByPubkey(stateDB, tokenID, key.KeySet.PaymentAddress.Pk, shardID)
+			if err != nil {
+				return false, err
+			}
+			if len(outCoins) > 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	accounts, err := walletService.Wallet.DiscoverAccounts(isUsed, gapLimit, passPhrase)
+	if err != nil {
+		return nil, NewRPCError(WalletAccountError, err)
+	}
+	result := make([]jsonresult.WalletAccountResult, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, jsonresult.NewWalletAccountResult(account))
+	}
+	return result, nil
+}
+
+func (walletService *WalletService) ImportWatchOnlyAccount(paymentAddress string, readonlyKey string, accountName string, passPhrase string) (jsonresult.WalletAccountResult, *RPCError) {
+	account, err := walletService.Wallet.ImportWatchOnlyAccount(paymentAddress, readonlyKey, accountName, passPhrase)
+	if err != nil {
+		return jsonresult.WalletAccountResult{}, NewRPCError(WalletAccountError, err)
+	}
+	return jsonresult.NewWalletAccountResult(*account), nil
+}
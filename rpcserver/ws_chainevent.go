@@ -0,0 +1,110 @@
+package rpcserver
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/multiview"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleSubscribeChainReorg notifies client whenever best view of a chain (-1 for beacon) switches to another branch
+func (wsServer *WsServer) handleSubscribeChainReorg(params interface{}, subcription string, cResult chan RpcSubResult, closeChan <-chan struct{}) {
+	Logger.log.Info("Handle Subscribe Chain Reorg", params, subcription)
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Methods should only contain 1 params"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	chainID, ok := arrayParams[0].(float64)
+	if !ok {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID component invalid"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.ChainReorgTopic)
+	if err != nil {
+		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	defer func() {
+		Logger.log.Info("Finish Subscribe Chain Reorg ChainID ", chainID)
+		wsServer.config.PubSubManager.Unsubscribe(pubsub.ChainReorgTopic, subId)
+		close(cResult)
+	}()
+	for {
+		select {
+		case msg := <-subChan:
+			{
+				reorg, ok := msg.Value.(*multiview.ReorgEvent)
+				if !ok {
+					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *multiview.ReorgEvent, have %+v", reflect.TypeOf(msg.Value))
+					continue
+				}
+				if reorg.ChainID != int(chainID) {
+					continue
+				}
+				cResult <- RpcSubResult{Result: jsonresult.NewChainReorgResult(reorg), Error: nil}
+			}
+		case <-closeChan:
+			{
+				cResult <- RpcSubResult{Result: jsonresult.UnsubcribeResult{Message: "Unsubscribe Chain Reorg"}}
+				return
+			}
+		}
+	}
+}
+
+// handleSubscribeFinalizedBlock notifies client whenever final view of a chain (-1 for beacon) advances
+func (wsServer *WsServer) handleSubscribeFinalizedBlock(params interface{}, subcription string, cResult chan RpcSubResult, closeChan <-chan struct{}) {
+	Logger.log.Info("Handle Subscribe Finalized Block", params, subcription)
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Methods should only contain 1 params"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	chainID, ok := arrayParams[0].(float64)
+	if !ok {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID component invalid"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.FinalizedViewTopic)
+	if err != nil {
+		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	defer func() {
+		Logger.log.Info("Finish Subscribe Finalized Block ChainID ", chainID)
+		wsServer.config.PubSubManager.Unsubscribe(pubsub.FinalizedViewTopic, subId)
+		close(cResult)
+	}()
+	for {
+		select {
+		case msg := <-subChan:
+			{
+				finalView, ok := msg.Value.(*multiview.FinalViewEvent)
+				if !ok {
+					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *multiview.FinalViewEvent, have %+v", reflect.TypeOf(msg.Value))
+					continue
+				}
+				if finalView.ChainID != int(chainID) {
+					continue
+				}
+				cResult <- RpcSubResult{Result: jsonresult.NewFinalizedBlockResult(finalView), Error: nil}
+			}
+		case <-closeChan:
+			{
+				cResult <- RpcSubResult{Result: jsonresult.UnsubcribeResult{Message: "Unsubscribe Finalized Block"}}
+				return
+			}
+		}
+	}
+}
@@ -7,9 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -19,7 +19,11 @@ import (
 	"time"
 
 	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/metrics/grafana"
+	"github.com/incognitochain/incognito-chain/metrics/health"
 	"github.com/incognitochain/incognito-chain/metrics/monitor"
+	"github.com/incognitochain/incognito-chain/metrics/prometheus"
 	"github.com/incognitochain/incognito-chain/peerv2/proto"
 	"github.com/incognitochain/incognito-chain/peerv2/wrapper"
 	bnbrelaying "github.com/incognitochain/incognito-chain/relaying/bnb"
@@ -27,8 +31,6 @@ import (
 
 	"github.com/incognitochain/incognito-chain/peerv2"
 
-	"cloud.google.com/go/storage"
-	"google.golang.org/api/option"
 
 	"github.com/incognitochain/incognito-chain/addrmanager"
 	"github.com/incognitochain/incognito-chain/blockchain"
@@ -39,6 +41,7 @@ import (
 	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/incognitochain/incognito-chain/incdb"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/light"
 	"github.com/incognitochain/incognito-chain/memcache"
 	"github.com/incognitochain/incognito-chain/mempool"
 	"github.com/incognitochain/incognito-chain/metadata"
@@ -46,8 +49,10 @@ import (
 	"github.com/incognitochain/incognito-chain/peer"
 	"github.com/incognitochain/incognito-chain/pubsub"
 	btcrelaying "github.com/incognitochain/incognito-chain/relaying/btc"
+	"github.com/incognitochain/incognito-chain/relaying/ethbridge"
 	"github.com/incognitochain/incognito-chain/rpcserver"
 	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/txtracker"
 	"github.com/incognitochain/incognito-chain/wallet"
 	"github.com/incognitochain/incognito-chain/wire"
 	libp2p "github.com/libp2p/go-libp2p-peer"
@@ -86,6 +91,16 @@ type Server struct {
 	// the mempool before they are mined into blocks.
 	feeEstimator map[byte]*mempool.FeeEstimator
 	highway      *peerv2.ConnManager
+	// compacts chain and mempool databases in background
+	storageCompactor *incdb.Compactor
+	// verifies beacon headers and shard data in light mode
+	lightClient *light.Client
+	// serves the metrics registry to Prometheus
+	metricsServer *http.Server
+	// pushes the tx lifecycles to an OpenTelemetry collector
+	txSpanExporter *txtracker.OTLPExporter
+	// submits the bridge proofs to Ethereum
+	bridgeRelayer *ethbridge.Relayer
 
 	cQuit     chan struct{}
 	cNewPeers chan *peer.Peer
@@ -215,6 +230,11 @@ func (serverObj *Server) NewServer(
 	serverObj.memCache = memcache.New()
 	serverObj.consensusEngine = consensus.NewConsensusEngine()
 	serverObj.syncker = syncker.NewSynckerManager()
+	txtracker.Default = txtracker.NewTracker(cfg.TxTrackerSize)
+	if cfg.OTLPURL != "" {
+		serverObj.txSpanExporter = txtracker.NewOTLPExporter(cfg.OTLPURL, "incognito", cfg.ExternalAddress)
+		txtracker.Default.SetExporter(serverObj.txSpanExporter)
+	}
 	//Init channel
 	cPendingTxs := make(chan metadata.Transaction, 500)
 	cRemovedTxs := make(chan metadata.Transaction, 500)
@@ -280,6 +300,7 @@ func (serverObj *Server) NewServer(
 
 	pubkey := serverObj.consensusEngine.GetMiningPublicKeys()
 	dispatcher := &peerv2.Dispatcher{
+		Reputation: peerv2.NewReputation(),
 		MessageListeners: &peerv2.MessageListeners{
 			OnBlockShard:       serverObj.OnBlockShard,
 			OnBlockBeacon:      serverObj.OnBlockBeacon,
@@ -315,6 +336,16 @@ func (serverObj *Server) NewServer(
 		cfg.NodeMode,
 		relayShards,
 	)
+	if cfg.DirectFallback {
+		err = serverObj.highway.EnableDirectFallback(peerv2.DirectConfig{
+			BootnodeAddress: cfg.DirectBootnode,
+			PeerTablePath:   filepath.Join(cfg.DataDir, DefaultDirectPeersFilename),
+			NumShards:       serverObj.chainParams.ActiveShards,
+		})
+		if err != nil {
+			return err
+		}
+	}
 
 	err = serverObj.blockChain.Init(&blockchain.Config{
 		BTCChain:      btcChain,
@@ -336,6 +367,7 @@ func (serverObj *Server) NewServer(
 		ConsensusEngine: serverObj.consensusEngine,
 		Highway:         serverObj.highway,
 		GenesisParams:   blockchain.GenesisParam,
+		ArchiveMode:     cfg.Archive,
 	})
 	if err != nil {
 		return err
@@ -404,6 +436,8 @@ func (serverObj *Server) NewServer(
 		FeeEstimator:      serverObj.feeEstimator,
 		TxLifeTime:        cfg.TxPoolTTL,
 		MaxTx:             cfg.TxPoolMaxTx,
+		MaxHeldTx:         cfg.TxPoolMaxHeldTx,
+		HeldTxLifeTime:    cfg.TxPoolHeldTTL,
 		DataBaseMempool:   dbmp,
 		IsLoadFromMempool: cfg.LoadMempool,
 		PersistMempool:    cfg.PersistMempool,
@@ -496,6 +530,30 @@ func (serverObj *Server) NewServer(
 		go serverObj.connManager.Connect(addr, "", "", nil)
 	}
 
+	serverObj.storageCompactor = incdb.NewCompactor(getCompactionTargets(db, dbmp), cfg.CompactThrottle)
+
+	if cfg.NodeMode == common.NodeModeLight {
+		// the light client follows the beacon chain from the best state
+		// stored, streams headers from peers and asks committee lists
+		// and proofs to the full node of lightrpc
+		checkpoint, err := light.CheckpointFromBestState(serverObj.blockChain.GetBeaconBestState())
+		if err != nil {
+			return err
+		}
+		source := light.NewStreamSource(light.NewRPCSource(cfg.LightRPC), serverObj.RequestBeaconHeadersViaStream, serverObj.RequestShardHeadersViaStream)
+		serverObj.lightClient, err = light.NewClient(checkpoint, source, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.RelayerEthURL != "" {
+		serverObj.bridgeRelayer, err = newBridgeRelayer(serverObj.blockChain, serverObj.consensusEngine)
+		if err != nil {
+			return err
+		}
+	}
+
 	if !cfg.DisableRPC {
 		// Setup listeners for the configured RPC listen addresses and
 		// TLS settings.
@@ -540,6 +598,12 @@ func (serverObj *Server) NewServer(
 			ConsensusEngine:             serverObj.consensusEngine,
 			MemCache:                    serverObj.memCache,
 			Syncker:                     serverObj.syncker,
+			Reputation:                  serverObj.highway.Reputation(),
+			DatabaseMempool:             dbmp,
+			BTCDataDir:                  filepath.Join(cfg.DataDir, chainParams.BTCDataFolderName),
+			StorageCompactor:            serverObj.storageCompactor,
+			LightClient:                 serverObj.lightClient,
+			LogLevels:                   logLevels{},
 		}
 		serverObj.rpcServer = &rpcserver.RpcServer{}
 		serverObj.rpcServer.Init(&rpcConfig)
@@ -555,11 +619,16 @@ func (serverObj *Server) NewServer(
 		}()
 	}
 
-	//Init Metric Tool
-	//if cfg.MetricUrl != "" {
-	//	grafana := metrics.NewGrafana(cfg.MetricUrl, cfg.ExternalAddress)
-	//	metrics.InitMetricTool(&grafana)
-	//}
+	// Serve the metrics registry on /metrics for Prometheus, it is also
+	// pushed to the InfluxDB of metricurl once the server starts. Its
+	// healthchecks are the liveness and readiness probes.
+	serverObj.registerHealthchecks()
+	if cfg.MetricsListen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", prometheus.Handler(metrics.DefaultRegistry))
+		health.Handle(mux, metrics.DefaultRegistry)
+		serverObj.metricsServer = &http.Server{Addr: cfg.MetricsListen, Handler: mux}
+	}
 	return nil
 }
 
@@ -607,6 +676,9 @@ func (serverObj *Server) Stop() error {
 	if !cfg.DisableRPC && serverObj.rpcServer != nil {
 		serverObj.rpcServer.Stop()
 	}
+	if serverObj.metricsServer != nil {
+		serverObj.metricsServer.Close()
+	}
 
 	// Save fee estimator in the db
 	for shardID, feeEstimator := range serverObj.feeEstimator {
@@ -687,9 +759,13 @@ func (serverObj Server) Start() {
 		return
 	}
 	Logger.log.Debug("Starting server")
-	// --- Checkforce update code ---
-	if serverObj.chainParams.CheckForce {
-		serverObj.CheckForceUpdateSourceCode()
+	if cfg.UpgradeNoticeURL != "" {
+		pubKey, err := parseUpgradeNoticePubKey(cfg.UpgradeNoticePubKey)
+		if err != nil {
+			Logger.log.Error(err)
+		} else {
+			go watchUpgradeNotice(cfg.UpgradeNoticeURL, pubKey, version(), serverObj.cQuit)
+		}
 	}
 	if cfg.IsTestnet() {
 		Logger.log.Critical("************************" +
@@ -717,7 +793,29 @@ func (serverObj Server) Start() {
 		serverObj.rpcServer.Start()
 	}
 
-	if cfg.NodeMode != common.NodeModeRelay {
+	if serverObj.metricsServer != nil {
+		go func() {
+			Logger.log.Infof("Metrics server listening on %s", cfg.MetricsListen)
+			if err := serverObj.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				Logger.log.Errorf("Metrics server: %v", err)
+			}
+		}()
+	}
+	if cfg.MetricUrl != "" {
+		go grafana.PushRegistry(metrics.DefaultRegistry, cfg.MetricUrl, cfg.ExternalAddress, cfg.MetricInterval, serverObj.cQuit)
+	}
+	if serverObj.txSpanExporter != nil {
+		go serverObj.txSpanExporter.Run(cfg.MetricInterval, serverObj.cQuit)
+	}
+	if serverObj.bridgeRelayer != nil {
+		go serverObj.bridgeRelayer.Start(serverObj.cQuit)
+	}
+
+	if cfg.NodeMode == common.NodeModeLight {
+		// a light node keeps no chain: it neither syncs blocks nor
+		// handles transactions
+		go serverObj.lightClient.Run(serverObj.cQuit)
+	} else if cfg.NodeMode != common.NodeModeRelay {
 		serverObj.memPool.IsBlockGenStarted = true
 		serverObj.blockChain.SetIsBlockGenStarted(true)
 		// for _, shardPool := range serverObj.shardPool {
@@ -727,10 +825,12 @@ func (serverObj Server) Start() {
 	}
 
 	//go serverObj.blockChain.Synker.Start()
-	go serverObj.syncker.Start()
-	go serverObj.blockgen.Start(serverObj.cQuit)
+	if cfg.NodeMode != common.NodeModeLight {
+		go serverObj.syncker.Start()
+		go serverObj.blockgen.Start(serverObj.cQuit)
+	}
 
-	if serverObj.memPool != nil {
+	if serverObj.memPool != nil && cfg.NodeMode != common.NodeModeLight {
 		err := serverObj.memPool.LoadOrResetDatabaseMempool()
 		if err != nil {
 			Logger.log.Error(err)
@@ -741,6 +841,10 @@ func (serverObj Server) Start() {
 	}
 	go serverObj.pusubManager.Start()
 
+	if cfg.CompactInterval > 0 {
+		go serverObj.storageCompactor.Schedule(cfg.CompactInterval, serverObj.cQuit)
+	}
+
 	err := serverObj.consensusEngine.Start()
 	if err != nil {
 		Logger.log.Error(err)
@@ -805,78 +909,6 @@ func (serverObj *Server) TransactionPoolBroadcastLoop() {
 	}
 }
 
-// CheckForceUpdateSourceCode - loop to check current version with update version is equal
-// Force source code to be updated and remove data
-func (serverObject Server) CheckForceUpdateSourceCode() {
-	go func() {
-		ctx := context.Background()
-		myClient, err := storage.NewClient(ctx, option.WithoutAuthentication())
-		if err != nil {
-			Logger.log.Error(err)
-		}
-		for {
-			reader, err := myClient.Bucket("incognito").Object(serverObject.chainParams.ChainVersion).NewReader(ctx)
-			if err != nil {
-				Logger.log.Error(err)
-				time.Sleep(10 * time.Second)
-				continue
-			}
-			defer reader.Close()
-
-			type VersionChain struct {
-				Version    string `json:"Version"`
-				Note       string `json:"Note"`
-				RemoveData bool   `json:"RemoveData"`
-			}
-			versionChain := VersionChain{}
-			currentVersion := version()
-			body, err := ioutil.ReadAll(reader)
-			if err != nil {
-				Logger.log.Error(err)
-				time.Sleep(10 * time.Second)
-				continue
-			}
-			err = json.Unmarshal(body, &versionChain)
-			if err != nil {
-				Logger.log.Error(err)
-				time.Sleep(10 * time.Second)
-				continue
-			}
-			force := currentVersion != versionChain.Version
-			if force {
-				Logger.log.Error("\n*********************************************************************************\n" +
-					versionChain.Note +
-					"\n*********************************************************************************\n")
-				Logger.log.Error("\n*********************************************************************************\n You're running version: " +
-					currentVersion +
-					"\n*********************************************************************************\n")
-				Logger.log.Error("\n*********************************************************************************\n" +
-					versionChain.Note +
-					"\n*********************************************************************************\n")
-
-				Logger.log.Error("\n*********************************************************************************\n New version: " +
-					versionChain.Version +
-					"\n*********************************************************************************\n")
-
-				Logger.log.Error("\n*********************************************************************************\n" +
-					"We're exited because having a force update on this souce code." +
-					"\nPlease Update source code at https://github.com/incognitochain/incognito-chain" +
-					"\n*********************************************************************************\n")
-				if versionChain.RemoveData {
-					serverObject.Stop()
-					errRemove := os.RemoveAll(cfg.DataDir)
-					if errRemove != nil {
-						Logger.log.Error("We NEEDD to REMOVE database directory but can not process by error", errRemove)
-					}
-					time.Sleep(60 * time.Second)
-				}
-				os.Exit(common.ExitCodeForceUpdate)
-			}
-			time.Sleep(10 * time.Second)
-		}
-	}()
-}
-
 /*
 // initListeners initializes the configured net listeners and adds any bound
 // addresses to the address manager. Returns the listeners and a NAT interface,
@@ -2091,6 +2123,17 @@ func (serverObj *Server) GetMinerIncognitoPublickey(publicKey string, keyType st
 	return nil
 }
 
+// ReportPeer moves the reputation score of peerID by its behaviour
+func (serverObj *Server) ReportPeer(peerID string, b peerv2.Behaviour) {
+	serverObj.highway.Reputation().Report(peerID, b)
+}
+
+// IsPeerBanned returns true if the node does not sync from peerID nor
+// process its messages
+func (serverObj *Server) IsPeerBanned(peerID string) bool {
+	return serverObj.highway.Reputation().IsBanned(peerID)
+}
+
 func (serverObj *Server) RequestBeaconBlocksViaStream(ctx context.Context, peerID string, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
 	Logger.log.Infof("[SyncBeacon] from %v to %v ", from, to)
 	req := &proto.BlockByHeightRequest{
@@ -2117,6 +2160,36 @@ func (serverObj *Server) RequestShardBlocksViaStream(ctx context.Context, peerID
 	return serverObj.requestBlocksViaStream(ctx, peerID, req)
 }
 
+// RequestBeaconHeadersViaStream streams the beacon blocks of the heights from
+// to to, without their bodies
+func (serverObj *Server) RequestBeaconHeadersViaStream(ctx context.Context, peerID string, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
+	Logger.log.Infof("[SyncBeacon] headers from %v to %v ", from, to)
+	req := &proto.BlockByHeightRequest{
+		Type:         peerv2.BlkTypeBeaconHeader,
+		Specific:     false,
+		Heights:      []uint64{from, to},
+		From:         int32(peerv2.HighwayBeaconID),
+		To:           int32(peerv2.HighwayBeaconID),
+		SyncFromPeer: peerID,
+	}
+	return serverObj.requestBlocksViaStream(ctx, peerID, req)
+}
+
+// RequestShardHeadersViaStream streams the shard blocks of the heights from
+// to to, without their bodies
+func (serverObj *Server) RequestShardHeadersViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
+	Logger.log.Infof("[SyncShard] headers from %v to %v fromShard %v", from, to, fromSID)
+	req := &proto.BlockByHeightRequest{
+		Type:         peerv2.BlkTypeShardHeader,
+		Specific:     false,
+		Heights:      []uint64{from, to},
+		From:         int32(fromSID),
+		To:           int32(fromSID),
+		SyncFromPeer: peerID,
+	}
+	return serverObj.requestBlocksViaStream(ctx, peerID, req)
+}
+
 func (serverObj *Server) RequestShardToBeaconBlocksViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
 	Logger.log.Infof("[SyncS2B] from %v to %v fromShard %v", from, to, fromSID)
 	req := &proto.BlockByHeightRequest{
@@ -2222,12 +2295,13 @@ func (serverObj *Server) requestBlocksViaStream(ctx context.Context, peerID stri
 				return
 			}
 
+			blkType, _ := peerv2.BlockTypeOf(req.Type)
 			var newBlk common.BlockInterface = new(blockchain.BeaconBlock)
-			if req.Type == proto.BlkType_BlkShard {
+			if blkType == proto.BlkType_BlkShard {
 				newBlk = new(blockchain.ShardBlock)
-			} else if req.Type == proto.BlkType_BlkS2B {
+			} else if blkType == proto.BlkType_BlkS2B {
 				newBlk = new(blockchain.ShardToBeaconBlock)
-			} else if req.Type == proto.BlkType_BlkXShard {
+			} else if blkType == proto.BlkType_BlkXShard {
 				newBlk = new(blockchain.CrossShardBlock)
 			}
 
@@ -2414,3 +2488,23 @@ func (serverObj *Server) RequestMissingViewViaStream(peerID string, hashes [][]b
 func (serverObj *Server) GetSelfPeerID() libp2p.ID {
 	return serverObj.highway.LocalHost.Host.ID()
 }
+
+// getCompactionTargets lists beacon, shard and mempool databases for background compaction
+func getCompactionTargets(db map[int]incdb.Database, dbmp databasemp.DatabaseInterface) []incdb.CompactionTarget {
+	targets := []incdb.CompactionTarget{}
+	for chainID := common.BeaconChainDataBaseID; chainID < common.MaxShardNumber; chainID++ {
+		chainDB, ok := db[chainID]
+		if !ok {
+			continue
+		}
+		name := common.BeaconChainDatabaseDirectory
+		if chainID != common.BeaconChainDataBaseID {
+			name = common.ShardChainDatabaseDirectory + strconv.Itoa(chainID)
+		}
+		targets = append(targets, incdb.CompactionTarget{Name: name, DB: chainDB})
+	}
+	if compacter, ok := dbmp.(incdb.Compacter); ok {
+		targets = append(targets, incdb.CompactionTarget{Name: "mempool", DB: compacter})
+	}
+	return targets
+}
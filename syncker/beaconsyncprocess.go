@@ -19,21 +19,21 @@ type BeaconPeerState struct {
 	Timestamp      int64
 	BestViewHash   string
 	BestViewHeight uint64
-	processed      bool
 }
 
 type BeaconSyncProcess struct {
-	status              string //stop, running
-	isCommittee         bool
-	isCatchUp           bool
+	syncState
 	beaconPeerStates    map[string]BeaconPeerState //sender -> state
 	beaconPeerStateCh   chan *wire.MessagePeerState
 	server              Server
 	chain               Chain
 	beaconPool          *BlkPool
+	downloader          *Downloader
+	headerSync          *HeaderSync
 	s2bSyncProcess      *S2BSyncProcess
 	actionCh            chan func()
 	lastCrossShardState map[byte]map[byte]uint64
+	peers               peerBest
 }
 
 func NewBeaconSyncProcess(server Server, chain BeaconChainInterface) *BeaconSyncProcess {
@@ -46,10 +46,12 @@ func NewBeaconSyncProcess(server Server, chain BeaconChainInterface) *BeaconSync
 	}
 
 	s := &BeaconSyncProcess{
-		status:              STOP_SYNC,
+		syncState:           syncState{status: STOP_SYNC},
 		server:              server,
 		chain:               chain,
 		beaconPool:          NewBlkPool("BeaconPool", isOutdatedBlock),
+		downloader:          NewDownloader("beacon", DefaultDownloaderConfig, server.RequestBeaconBlocksViaStream, server.ReportPeer),
+		headerSync:          NewHeaderSync("beacon", chain, DefaultDownloaderConfig, server.RequestBeaconHeadersViaStream, server.ReportPeer),
 		beaconPeerStates:    make(map[string]BeaconPeerState),
 		beaconPeerStateCh:   make(chan *wire.MessagePeerState),
 		actionCh:            make(chan func()),
@@ -63,7 +65,7 @@ func NewBeaconSyncProcess(server Server, chain BeaconChainInterface) *BeaconSync
 	go func() {
 		ticker := time.NewTicker(time.Millisecond * 500)
 		for {
-			if s.isCommittee {
+			if s.getCommittee() {
 				s.s2bSyncProcess.start()
 			} else {
 				s.s2bSyncProcess.stop()
@@ -81,13 +83,18 @@ func NewBeaconSyncProcess(server Server, chain BeaconChainInterface) *BeaconSync
 				}
 				s.chain.SetReady(true)
 			case <-ticker.C:
+				peerHeight := uint64(0)
 				for sender, ps := range s.beaconPeerStates {
 					if ps.Timestamp < time.Now().Unix()-10 {
 						delete(s.beaconPeerStates, sender)
+					} else if ps.BestViewHeight > peerHeight {
+						peerHeight = ps.BestViewHeight
 					}
 				}
+				updateSyncLag(common.BeaconChainKey, s.chain, peerHeight)
+				s.peers.update(peerHeight, len(s.beaconPeerStates))
 			}
-			if s.status != RUNNING_SYNC {
+			if s.getStatus() != RUNNING_SYNC {
 				time.Sleep(time.Second)
 				continue
 			}
@@ -98,15 +105,11 @@ func NewBeaconSyncProcess(server Server, chain BeaconChainInterface) *BeaconSync
 }
 
 func (s *BeaconSyncProcess) start() {
-	if s.status == RUNNING_SYNC {
-		return
-	}
-	s.status = RUNNING_SYNC
-
+	s.setStatus(RUNNING_SYNC)
 }
 
 func (s *BeaconSyncProcess) stop() {
-	s.status = STOP_SYNC
+	s.setStatus(STOP_SYNC)
 	s.s2bSyncProcess.stop()
 }
 
@@ -252,7 +255,7 @@ func (s *BeaconSyncProcess) insertBeaconBlockFromPool() {
 			insertCnt++
 			//must validate this block when insert
 			if err := s.chain.InsertBlk(blk.(common.BlockInterface), true); err != nil {
-				Logger.Error("Insert beacon block from pool fail", blk.GetHeight(), blk.Hash(), err)
+				blockLogger(blk.(common.BlockInterface)).Error("Insert beacon block from pool fail", blk.GetHeight(), blk.Hash(), err)
 				continue
 			}
 			s.beaconPool.RemoveBlock(blk.Hash())
@@ -265,96 +268,89 @@ func (s *BeaconSyncProcess) insertBeaconBlockFromPool() {
 func (s *BeaconSyncProcess) syncBeacon() {
 	for {
 		requestCnt := 0
-		if s.status != RUNNING_SYNC {
-			s.isCatchUp = false
+		if s.getStatus() != RUNNING_SYNC {
+			s.setCatchUp(false)
 			time.Sleep(time.Second)
 			continue
 		}
 
-		for peerID, pState := range s.getBeaconPeerStates() {
-			requestCnt += s.streamFromPeer(peerID, pState)
-		}
+		peerStates := s.getBeaconPeerStates()
+		requestCnt += s.downloadFromPeers(peerStates)
 
 		//last check, if we still need to sync more
 		if requestCnt > 0 {
-			s.isCatchUp = false
+			s.setCatchUp(false)
 		} else {
-			if len(s.beaconPeerStates) > 0 {
-				s.isCatchUp = true
+			if len(peerStates) > 0 {
+				s.setCatchUp(true)
 			}
 			time.Sleep(time.Second * 5)
 		}
 	}
 }
 
-func (s *BeaconSyncProcess) streamFromPeer(peerID string, pState BeaconPeerState) (requestCnt int) {
-	if pState.processed {
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	blockBuffer := []common.BlockInterface{}
-	defer func() {
-		if requestCnt == 0 {
-			pState.processed = true
+// downloadFromPeers downloads the blocks up to the best height of the peers,
+// from all of them at once
+func (s *BeaconSyncProcess) downloadFromPeers(peerStates map[string]BeaconPeerState) (requestCnt int) {
+	peers := map[string]uint64{}
+	toHeight := uint64(0)
+	for peerID, pState := range peerStates {
+		if s.server.IsPeerBanned(peerID) {
+			continue
+		}
+		height := pState.BestViewHeight
+		//fullnode delay 1 block (make sure insert final block)
+		if os.Getenv("FULLNODE") != "" && height > 0 {
+			height--
+		}
+		peers[peerID] = height
+		if height > toHeight {
+			toHeight = height
 		}
-		cancel()
-	}()
-
-	toHeight := pState.BestViewHeight
-	//process param
-
-	//fullnode delay 1 block (make sure insert final block)
-	if os.Getenv("FULLNODE") != "" {
-		toHeight = toHeight - 1
 	}
 
 	if toHeight <= s.chain.GetBestViewHeight() {
 		return
 	}
 
-	//stream
-	ch, err := s.server.RequestBeaconBlocksViaStream(ctx, "", s.chain.GetFinalViewHeight()+1, toHeight)
-	if err != nil {
-		fmt.Println("Syncker: create channel fail")
-		return
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	requestCnt++
+	from := s.chain.GetFinalViewHeight() + 1
+	var headers *VerifiedHeaders
+	if HeaderFirst {
+		if headers = s.headerSync.Sync(ctx, from, toHeight, peers); headers == nil {
+			return
+		}
+		toHeight = headers.To()
 	}
+	for chunk := range s.downloader.Download(ctx, from, toHeight, peers) {
+		matched := matchHeaders(s.server, headers, chunk)
+		if len(chunk.Blocks) == 0 || !s.insertChunk(chunk) || !matched {
+			return
+		}
+	}
+	return
+}
 
-	//receive
-	requestCnt++
-	insertTime := time.Now()
+// insertChunk inserts the blocks of a chunk, it returns false if they cannot
+// be inserted
+func (s *BeaconSyncProcess) insertChunk(chunk *Chunk) bool {
+	blockBuffer := chunk.Blocks
 	for {
-		select {
-		case blk := <-ch:
-			if !isNil(blk) {
-				//Logger.Infof("Syncker beacon receive block %v", blk.GetHeight())
-				blockBuffer = append(blockBuffer, blk)
-			}
-
-			if uint64(len(blockBuffer)) >= blockchain.DefaultMaxBlkReqPerPeer || (len(blockBuffer) > 0 && (isNil(blk) || time.Since(insertTime) > time.Millisecond*2000)) {
-				insertBlkCnt := 0
-				for {
-					time1 := time.Now()
-					if successBlk, err := InsertBatchBlock(s.chain, blockBuffer); err != nil {
-						if successBlk == 0 {
-							fmt.Println(err)
-						}
-						return
-					} else {
-						insertBlkCnt += successBlk
-						Logger.Infof("Syncker Insert %d beacon block (from %d to %d) elaspse %f \n", successBlk, blockBuffer[0].GetHeight(), blockBuffer[len(blockBuffer)-1].GetHeight(), time.Since(time1).Seconds())
-						if successBlk >= len(blockBuffer) || successBlk == 0 {
-							break
-						}
-						blockBuffer = blockBuffer[successBlk:]
-					}
-				}
-				insertTime = time.Now()
-				blockBuffer = []common.BlockInterface{}
-			}
-			if isNil(blk) && len(blockBuffer) == 0 {
-				return
+		time1 := time.Now()
+		successBlk, err := InsertBatchBlock(s.chain, blockBuffer)
+		reportBatch(s.server, chunk.PeerID, successBlk, err)
+		if err != nil {
+			if successBlk == 0 {
+				chunkLogger(common.BeaconChainID, chunk).Error(err)
 			}
+			return false
+		}
+		chunkLogger(common.BeaconChainID, chunk).Infof("Syncker Insert %d beacon block (from %d to %d) elaspse %f \n", successBlk, blockBuffer[0].GetHeight(), blockBuffer[len(blockBuffer)-1].GetHeight(), time.Since(time1).Seconds())
+		if successBlk >= len(blockBuffer) || successBlk == 0 {
+			return true
 		}
+		blockBuffer = blockBuffer[successBlk:]
 	}
 }
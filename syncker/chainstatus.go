@@ -0,0 +1,85 @@
+package syncker
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// peerBest is the best height of the peers of a chain and their number,
+// written by the loop of a sync process and read by the status
+type peerBest struct {
+	height uint64
+	count  uint64
+}
+
+func (p *peerBest) update(height uint64, count int) {
+	atomic.StoreUint64(&p.height, height)
+	atomic.StoreUint64(&p.count, uint64(count))
+}
+
+func (p *peerBest) get() (height uint64, count int) {
+	return atomic.LoadUint64(&p.height), int(atomic.LoadUint64(&p.count))
+}
+
+// ChainSyncStatus is the sync state of a chain: its local heights against
+// the best height its peers announced
+type ChainSyncStatus struct {
+	ChainID       int
+	Chain         string
+	IsSync        bool
+	IsCatchUp     bool
+	BestHeight    uint64
+	FinalHeight   uint64
+	PeerHeight    uint64 // 0 when no peer announced the chain
+	Peers         int
+	Lag           uint64 // blocks behind PeerHeight
+	LastBlockTime int64  // produce time of the best block
+}
+
+// GetChainSyncStatus returns the sync state of the beacon chain and the
+// shards, in this order
+func (synckerManager *SynckerManager) GetChainSyncStatus() []ChainSyncStatus {
+	if synckerManager.config == nil || synckerManager.BeaconSyncProcess == nil {
+		return nil
+	}
+	bc := synckerManager.config.Blockchain
+	beacon := synckerManager.BeaconSyncProcess
+	res := []ChainSyncStatus{newChainSyncStatus(common.BeaconChainID, common.BeaconChainKey, beacon.getStatus(), beacon.getCatchUp(), beacon.chain, bc.BeaconChain.GetBestView(), &beacon.peers)}
+
+	shardIDs := []int{}
+	for shardID := range synckerManager.ShardSyncProcess {
+		shardIDs = append(shardIDs, shardID)
+	}
+	sort.Ints(shardIDs)
+	for _, shardID := range shardIDs {
+		s := synckerManager.ShardSyncProcess[shardID]
+		var bestView multiview.View
+		if shardID < len(bc.ShardChain) {
+			bestView = bc.ShardChain[shardID].GetBestView()
+		}
+		res = append(res, newChainSyncStatus(shardID, common.GetShardChainKey(byte(shardID)), s.getStatus(), s.getCatchUp(), s.Chain, bestView, &s.peers))
+	}
+	return res
+}
+
+func newChainSyncStatus(chainID int, chainKey, status string, isCatchUp bool, chain Chain, bestView multiview.View, peers *peerBest) ChainSyncStatus {
+	res := ChainSyncStatus{
+		ChainID:     chainID,
+		Chain:       chainKey,
+		IsSync:      status == RUNNING_SYNC,
+		IsCatchUp:   isCatchUp,
+		BestHeight:  chain.GetBestViewHeight(),
+		FinalHeight: chain.GetFinalViewHeight(),
+	}
+	res.PeerHeight, res.Peers = peers.get()
+	if res.PeerHeight > res.BestHeight {
+		res.Lag = res.PeerHeight - res.BestHeight
+	}
+	if !isNil(bestView) && !isNil(bestView.GetBlock()) {
+		res.LastBlockTime = bestView.GetBlock().GetProduceTime()
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+package syncker
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/multiview"
+	"github.com/stretchr/testify/assert"
+)
+
+type statusChain struct {
+	Chain
+	best, final uint64
+}
+
+func (c *statusChain) GetBestViewHeight() uint64  { return c.best }
+func (c *statusChain) GetFinalViewHeight() uint64 { return c.final }
+
+type statusView struct {
+	multiview.View
+	block common.BlockInterface
+}
+
+func (v *statusView) GetBlock() common.BlockInterface { return v.block }
+
+func TestChainSyncStatus(t *testing.T) {
+	blk := &blockchain.BeaconBlock{}
+	blk.Header.Timestamp = 1000
+	peers := &peerBest{}
+	peers.update(120, 3)
+
+	status := newChainSyncStatus(common.BeaconChainID, common.BeaconChainKey, RUNNING_SYNC, false, &statusChain{best: 100, final: 98}, &statusView{block: blk}, peers)
+	assert.Equal(t, ChainSyncStatus{
+		ChainID:       common.BeaconChainID,
+		Chain:         common.BeaconChainKey,
+		IsSync:        true,
+		BestHeight:    100,
+		FinalHeight:   98,
+		PeerHeight:    120,
+		Peers:         3,
+		Lag:           20,
+		LastBlockTime: 1000,
+	}, status)
+
+	// a chain ahead of its peers has no lag, a missing view no block time
+	peers.update(90, 1)
+	status = newChainSyncStatus(1, common.GetShardChainKey(1), STOP_SYNC, true, &statusChain{best: 100, final: 98}, nil, peers)
+	assert.Equal(t, uint64(0), status.Lag)
+	assert.Equal(t, int64(0), status.LastBlockTime)
+	assert.True(t, status.IsCatchUp)
+	assert.False(t, status.IsSync)
+}
@@ -11,7 +11,7 @@ import (
 )
 
 type CrossShardSyncProcess struct {
-	status           string //stop, running
+	syncState
 	server           Server
 	shardID          int
 	shardSyncProcess *ShardSyncProcess
@@ -35,7 +35,7 @@ func NewCrossShardSyncProcess(server Server, shardSyncProcess *ShardSyncProcess,
 	}
 
 	s := &CrossShardSyncProcess{
-		status:           STOP_SYNC,
+		syncState:        syncState{status: STOP_SYNC},
 		server:           server,
 		beaconChain:      beaconChain,
 		shardSyncProcess: shardSyncProcess,
@@ -57,16 +57,11 @@ func NewCrossShardSyncProcess(server Server, shardSyncProcess *ShardSyncProcess,
 }
 
 func (s *CrossShardSyncProcess) start() bool {
-	if s.status == RUNNING_SYNC {
-		return false
-	}
-	s.status = RUNNING_SYNC
-
-	return true
+	return s.setStatus(RUNNING_SYNC)
 }
 
 func (s *CrossShardSyncProcess) stop() {
-	s.status = STOP_SYNC
+	s.setStatus(STOP_SYNC)
 }
 
 //check beacon state and retrieve needed crossshard block, then add to request pool
@@ -74,7 +69,7 @@ func (s *CrossShardSyncProcess) syncCrossShard() {
 	for {
 		reqCnt := 0
 		//only run when shard is validator and sync shard is finish
-		if s.status != RUNNING_SYNC || !s.shardSyncProcess.isCatchUp {
+		if s.getStatus() != RUNNING_SYNC || !s.shardSyncProcess.getCatchUp() {
 			time.Sleep(time.Second * 5)
 			continue
 		}
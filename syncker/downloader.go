@@ -0,0 +1,266 @@
+package syncker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/peerv2"
+)
+
+var (
+	downloadBlockMeter    = metrics.NewRegisteredMeter("syncker/download/blocks", nil)
+	downloadChunkTimer    = metrics.NewRegisteredTimer("syncker/download/chunk", nil)
+	downloadRetryCounter  = metrics.NewRegisteredCounter("syncker/download/retry", nil)
+	downloadPeersGauge    = metrics.NewRegisteredGauge("syncker/download/peers", nil)
+	downloadInflightGauge = metrics.NewRegisteredGauge("syncker/download/inflight", nil)
+)
+
+// DownloaderConfig sets how a Downloader splits the blocks and requests them
+type DownloaderConfig struct {
+	ChunkSize        uint64        // Blocks requested to a peer at once
+	MaxPeers         int           // Peers downloading chunks at the same time
+	MaxChunksAhead   uint64        // Chunks downloaded ahead of the first missing one
+	MaxChunkAttempts int           // Give up the download after a chunk failed this many times
+	MaxPeerFailed    int           // Stop requesting chunks to a peer after this many failures
+	ChunkTimeout     time.Duration // Time to download a chunk
+}
+
+var DefaultDownloaderConfig = DownloaderConfig{
+	ChunkSize:        100,
+	MaxPeers:         8,
+	MaxChunksAhead:   32,
+	MaxChunkAttempts: 5,
+	MaxPeerFailed:    3,
+	ChunkTimeout:     30 * time.Second,
+}
+
+// fetchFunc streams the blocks of the heights from to to from peerID
+type fetchFunc func(ctx context.Context, peerID string, from, to uint64) (chan common.BlockInterface, error)
+
+// Chunk is a range of consecutive blocks downloaded from one peer
+type Chunk struct {
+	PeerID string
+	Blocks []common.BlockInterface
+}
+
+// Downloader splits a range of heights into chunks and downloads them from
+// several peers at once. The chunks are delivered in order of height; a chunk
+// which a peer fails to serve is requested to another one.
+type Downloader struct {
+	name   string // chain of the blocks, for the logs
+	config DownloaderConfig
+	fetch  fetchFunc
+	report func(peerID string, b peerv2.Behaviour)
+}
+
+func NewDownloader(name string, config DownloaderConfig, fetch fetchFunc, report func(peerID string, b peerv2.Behaviour)) *Downloader {
+	return &Downloader{name: name, config: config, fetch: fetch, report: report}
+}
+
+type pendingChunk struct {
+	from, to uint64
+	tried    map[string]bool // peers which failed to serve the chunk
+	attempts int
+}
+
+type chunkResult struct {
+	chunk   *pendingChunk
+	peerID  string
+	blocks  []common.BlockInterface
+	err     error
+	elapsed time.Duration
+}
+
+// Download downloads the blocks of the heights from to to from peers, which
+// maps the peer ids to their best height: a peer is only requested chunks
+// under its best height. The returned channel is closed once every block is
+// delivered, ctx is done, or a chunk cannot be downloaded, and once every
+// request to the peers is over.
+func (d *Downloader) Download(ctx context.Context, from, to uint64, peers map[string]uint64) <-chan *Chunk {
+	out := make(chan *Chunk)
+	go d.run(ctx, from, to, peers, out)
+	return out
+}
+
+func (d *Downloader) run(ctx context.Context, from, to uint64, peers map[string]uint64, out chan<- *Chunk) {
+	defer close(out)
+	var requests sync.WaitGroup
+	defer requests.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunkSize := d.config.ChunkSize
+	pending := []*pendingChunk{}
+	for height := from; height <= to; height += chunkSize {
+		chunkTo := height + chunkSize - 1
+		if chunkTo > to || chunkTo < height {
+			chunkTo = to
+		}
+		pending = append(pending, &pendingChunk{from: height, to: chunkTo, tried: map[string]bool{}})
+	}
+
+	idle := map[string]bool{}
+	for peerID := range peers {
+		idle[peerID] = true
+	}
+	failed := map[string]int{}
+	results := make(chan *chunkResult, len(peers))
+	downloaded := map[uint64]*chunkResult{} // by first height, waiting for the chunks before
+	next := from
+	inflight := 0
+	defer func() {
+		downloadPeersGauge.Update(0)
+		downloadInflightGauge.Update(0)
+	}()
+
+	for next <= to {
+		// Give a chunk to every idle peer having its blocks
+		for peerID := range idle {
+			if inflight >= d.config.MaxPeers {
+				break
+			}
+			i := d.nextChunk(pending, peerID, peers[peerID], next)
+			if i < 0 {
+				continue
+			}
+			chunk := pending[i]
+			pending = append(pending[:i], pending[i+1:]...)
+			delete(idle, peerID)
+			inflight++
+			requests.Add(1)
+			go func(peerID string) {
+				defer requests.Done()
+				d.download(ctx, peerID, chunk, results)
+			}(peerID)
+		}
+		downloadPeersGauge.Update(int64(len(peers) - len(idle)))
+		downloadInflightGauge.Update(int64(inflight))
+		if inflight == 0 {
+			Logger.Infof("Syncker cannot download %v blocks from %v: no peer has them", d.name, next)
+			return
+		}
+
+		var res *chunkResult
+		select {
+		case res = <-results:
+		case <-ctx.Done():
+			return
+		}
+		inflight--
+		downloadChunkTimer.Update(res.elapsed)
+		peerMeter(res.peerID).Mark(int64(len(res.blocks)))
+		downloadBlockMeter.Mark(int64(len(res.blocks)))
+
+		chunk := res.chunk
+		if len(res.blocks) > 0 {
+			downloaded[chunk.from] = res
+		}
+		if res.err != nil || len(res.blocks) < int(chunk.to-chunk.from+1) {
+			// Request the missing blocks to another peer
+			missing := &pendingChunk{from: chunk.from + uint64(len(res.blocks)), to: chunk.to, tried: chunk.tried, attempts: chunk.attempts + 1}
+			missing.tried[res.peerID] = true
+			if missing.attempts >= d.config.MaxChunkAttempts {
+				Logger.Errorf("Syncker cannot download %v blocks %v to %v after %v attempts", d.name, missing.from, missing.to, missing.attempts)
+				return
+			}
+			pending = insertChunk(pending, missing)
+			downloadRetryCounter.Inc(1)
+			peerFailCounter(res.peerID).Inc(1)
+			failed[res.peerID]++
+		}
+		if failed[res.peerID] < d.config.MaxPeerFailed {
+			idle[res.peerID] = true
+		} else {
+			Logger.Infof("Syncker stops downloading %v blocks from peer %v", d.name, res.peerID)
+		}
+
+		// Deliver the chunks following the last delivered block
+		for res, ok := downloaded[next]; ok; res, ok = downloaded[next] {
+			delete(downloaded, next)
+			next += uint64(len(res.blocks))
+			select {
+			case out <- &Chunk{PeerID: res.peerID, Blocks: res.blocks}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// nextChunk returns the index of the first pending chunk the peer can serve,
+// not too far ahead of the first missing block, -1 if none
+func (d *Downloader) nextChunk(pending []*pendingChunk, peerID string, peerHeight uint64, next uint64) int {
+	for i, chunk := range pending {
+		if chunk.from >= next+d.config.MaxChunksAhead*d.config.ChunkSize {
+			return -1
+		}
+		if chunk.to <= peerHeight && !chunk.tried[peerID] {
+			return i
+		}
+	}
+	return -1
+}
+
+// download streams the blocks of chunk from the peer, it keeps the blocks
+// following each other from the first height of the chunk
+func (d *Downloader) download(ctx context.Context, peerID string, chunk *pendingChunk, results chan<- *chunkResult) {
+	start := time.Now()
+	res := &chunkResult{chunk: chunk, peerID: peerID}
+	defer func() {
+		res.elapsed = time.Since(start)
+		select {
+		case results <- res:
+		case <-ctx.Done():
+		}
+	}()
+
+	chunkCtx, cancel := context.WithTimeout(ctx, d.config.ChunkTimeout)
+	defer cancel()
+	ch, err := d.fetch(chunkCtx, peerID, chunk.from, chunk.to)
+	if err != nil {
+		res.err = err
+		d.report(peerID, peerv2.FailedStream)
+		return
+	}
+	for {
+		select {
+		case blk, ok := <-ch:
+			if !ok || isNil(blk) {
+				return
+			}
+			if blk.GetHeight() != chunk.from+uint64(len(res.blocks)) {
+				Logger.Infof("Syncker got %v block %v from peer %v, expected %v", d.name, blk.GetHeight(), peerID, chunk.from+uint64(len(res.blocks)))
+				d.report(peerID, peerv2.InvalidBlock)
+				return
+			}
+			res.blocks = append(res.blocks, blk)
+			if blk.GetHeight() == chunk.to {
+				return
+			}
+		case <-chunkCtx.Done():
+			res.err = chunkCtx.Err()
+			return
+		}
+	}
+}
+
+// insertChunk adds chunk to pending, which stays sorted by height
+func insertChunk(pending []*pendingChunk, chunk *pendingChunk) []*pendingChunk {
+	i := sort.Search(len(pending), func(i int) bool { return pending[i].from > chunk.from })
+	pending = append(pending, nil)
+	copy(pending[i+1:], pending[i:])
+	pending[i] = chunk
+	return pending
+}
+
+func peerMeter(peerID string) metrics.Meter {
+	return metrics.GetOrRegisterMeter("syncker/download/peer/"+peerID+"/blocks", nil)
+}
+
+func peerFailCounter(peerID string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("syncker/download/peer/"+peerID+"/failed", nil)
+}
@@ -0,0 +1,177 @@
+package syncker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPeers serves the blocks up to their height, a broken peer fails every
+// request and a lossy one stops in the middle of the chunks
+type testPeers struct {
+	height   map[string]uint64
+	broken   map[string]bool
+	lossy    map[string]bool
+	requests map[string]int
+	reports  map[string][]peerv2.Behaviour
+	sync.Mutex
+}
+
+func (p *testPeers) fetch(ctx context.Context, peerID string, from, to uint64) (chan common.BlockInterface, error) {
+	p.Lock()
+	defer p.Unlock()
+	p.requests[peerID]++
+	if p.broken[peerID] {
+		return nil, errors.New("cannot open stream")
+	}
+	if p.lossy[peerID] && to > from {
+		to = (from + to) / 2
+	}
+	ch := make(chan common.BlockInterface, to-from+1)
+	for height := from; height <= to && height <= p.height[peerID]; height++ {
+		blk := &blockchain.BeaconBlock{}
+		blk.Header.Height = height
+		ch <- blk
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (p *testPeers) report(peerID string, b peerv2.Behaviour) {
+	p.Lock()
+	defer p.Unlock()
+	p.reports[peerID] = append(p.reports[peerID], b)
+}
+
+func newTestPeers(height map[string]uint64) *testPeers {
+	return &testPeers{
+		height:   height,
+		broken:   map[string]bool{},
+		lossy:    map[string]bool{},
+		requests: map[string]int{},
+		reports:  map[string][]peerv2.Behaviour{},
+	}
+}
+
+// downloadHeights returns the heights of the downloaded blocks, in the order
+// they are delivered, and the peers they come from
+func downloadHeights(d *Downloader, from, to uint64, peers map[string]uint64) ([]uint64, map[string]bool) {
+	heights := []uint64{}
+	senders := map[string]bool{}
+	for chunk := range d.Download(context.Background(), from, to, peers) {
+		senders[chunk.PeerID] = true
+		for _, blk := range chunk.Blocks {
+			heights = append(heights, blk.GetHeight())
+		}
+	}
+	return heights, senders
+}
+
+func expectedHeights(from, to uint64) []uint64 {
+	heights := []uint64{}
+	for height := from; height <= to; height++ {
+		heights = append(heights, height)
+	}
+	return heights
+}
+
+func TestDownloaderInOrderFromSeveralPeers(t *testing.T) {
+	peers := newTestPeers(map[string]uint64{"a": 200, "b": 200, "c": 200})
+	d := NewDownloader("beacon", testDownloaderConfig(), peers.fetch, peers.report)
+
+	heights, senders := downloadHeights(d, 5, 200, peers.height)
+	assert.Equal(t, expectedHeights(5, 200), heights)
+	assert.Len(t, senders, 3)
+	assert.Equal(t, 20, peers.requests["a"]+peers.requests["b"]+peers.requests["c"])
+}
+
+func TestDownloaderOnlyAsksPeersHavingTheBlocks(t *testing.T) {
+	peers := newTestPeers(map[string]uint64{"high": 100, "low": 30})
+	d := NewDownloader("beacon", testDownloaderConfig(), peers.fetch, peers.report)
+
+	heights, _ := downloadHeights(d, 1, 100, peers.height)
+	assert.Equal(t, expectedHeights(1, 100), heights)
+	assert.True(t, peers.requests["low"] <= 3)
+}
+
+func TestDownloaderRetriesFailedChunks(t *testing.T) {
+	peers := newTestPeers(map[string]uint64{"good": 100, "broken": 100, "lossy": 100})
+	peers.broken["broken"] = true
+	peers.lossy["lossy"] = true
+	config := testDownloaderConfig()
+	d := NewDownloader("beacon", config, peers.fetch, peers.report)
+
+	heights, senders := downloadHeights(d, 1, 100, peers.height)
+	assert.Equal(t, expectedHeights(1, 100), heights)
+	assert.False(t, senders["broken"])
+	assert.True(t, peers.requests["broken"] <= config.MaxPeerFailed)
+	assert.True(t, peers.requests["lossy"] <= config.MaxPeerFailed)
+	assert.Contains(t, peers.reports["broken"], peerv2.FailedStream)
+}
+
+func TestDownloaderDeliversChunkOfFailedPeer(t *testing.T) {
+	peers := newTestPeers(map[string]uint64{"lossy": 100})
+	peers.lossy["lossy"] = true
+	config := testDownloaderConfig()
+	config.MaxPeerFailed = 1
+	d := NewDownloader("beacon", config, peers.fetch, peers.report)
+
+	// the peer is not requested more chunks, the blocks it sent are delivered
+	heights, _ := downloadHeights(d, 1, 100, peers.height)
+	assert.Equal(t, expectedHeights(1, 5), heights)
+	assert.Equal(t, 1, peers.requests["lossy"])
+}
+
+func TestDownloaderGivesUp(t *testing.T) {
+	peers := newTestPeers(map[string]uint64{"a": 100, "b": 15})
+	peers.broken["a"] = true
+	d := NewDownloader("beacon", testDownloaderConfig(), peers.fetch, peers.report)
+
+	// b only has the first blocks
+	heights, _ := downloadHeights(d, 1, 100, peers.height)
+	assert.Equal(t, expectedHeights(1, 10), heights)
+
+	heights, _ = downloadHeights(d, 1, 100, map[string]uint64{})
+	assert.Empty(t, heights)
+}
+
+func TestDownloaderStopsWithContext(t *testing.T) {
+	peers := newTestPeers(map[string]uint64{"a": 1000})
+	d := NewDownloader("beacon", testDownloaderConfig(), peers.fetch, peers.report)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := d.Download(ctx, 1, 1000, peers.height)
+	<-ch
+	cancel()
+	select {
+	case <-func() chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			for range ch {
+			}
+			close(done)
+		}()
+		return done
+	}():
+	case <-time.After(5 * time.Second):
+		t.Fatal("download not stopped")
+	}
+}
+
+// testDownloaderConfig downloads chunks of 10 blocks
+func testDownloaderConfig() DownloaderConfig {
+	config := DefaultDownloaderConfig
+	config.ChunkSize = 10
+	return config
+}
+
+func init() {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+}
@@ -0,0 +1,197 @@
+package syncker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/peerv2"
+)
+
+var (
+	headerVerifiedMeter = metrics.NewRegisteredMeter("syncker/headers/verified", nil)
+	headerBatchTimer    = metrics.NewRegisteredTimer("syncker/headers/batch", nil)
+)
+
+// HeaderFirst makes syncker download and verify the headers of the blocks
+// before downloading their bodies
+var HeaderFirst = true
+
+var (
+	errHeaderLink  = errors.New("header does not follow the previous block")
+	errHeaderEpoch = errors.New("header goes back to a previous epoch")
+)
+
+// HeaderSync downloads the headers of a range of blocks, with their
+// validation data but without bodies, and verifies that they follow the final
+// view of the chain and are signed by their committee. The committee
+// signatures of a chunk of headers are verified in batches, split at the
+// blocks changing the committee.
+type HeaderSync struct {
+	chain      Chain
+	downloader *Downloader
+	report     func(peerID string, b peerv2.Behaviour)
+}
+
+func NewHeaderSync(name string, chain Chain, config DownloaderConfig, fetch fetchFunc, report func(peerID string, b peerv2.Behaviour)) *HeaderSync {
+	return &HeaderSync{
+		chain:      chain,
+		downloader: NewDownloader(name+" headers", config, fetch, report),
+		report:     report,
+	}
+}
+
+// VerifiedHeaders are the hashes of the headers verified from a height on
+type VerifiedHeaders struct {
+	From   uint64
+	Hashes []common.Hash
+}
+
+// To returns the height of the last verified header
+func (v *VerifiedHeaders) To() uint64 {
+	return v.From + uint64(len(v.Hashes)) - 1
+}
+
+// Match returns the number of blocks, from the first one, having the hash of
+// their verified header
+func (v *VerifiedHeaders) Match(blocks []common.BlockInterface) int {
+	for i, blk := range blocks {
+		height := blk.GetHeight()
+		if height < v.From || height > v.To() || !blk.Hash().IsEqual(&v.Hashes[height-v.From]) {
+			return i
+		}
+	}
+	return len(blocks)
+}
+
+// Sync downloads the headers of the heights from to to from peers and returns
+// the headers verified, from the first one. The headers are verified against
+// the committee of the final view, and of the view of each block changing the
+// committee once the block is inserted. It stops at a block changing the
+// committee which is not inserted, whose next committee is only known once
+// the bodies before are inserted, and at the first header failing
+// verification. It returns nil if no header is verified. It returns once
+// every request to the peers is over.
+func (h *HeaderSync) Sync(ctx context.Context, from, to uint64, peers map[string]uint64) *VerifiedHeaders {
+	ctx, cancel := context.WithCancel(ctx)
+	chunks := h.downloader.Download(ctx, from, to, peers)
+	defer func() {
+		cancel()
+		for range chunks {
+		}
+	}()
+
+	view := h.chain.GetFinalView()
+	prevHash := view.GetHash().String()
+	epoch := view.GetBlock().GetCurrentEpoch()
+	committee := view.GetCommittee()
+	// changed is true if the committee changed at the last header verified
+	changed := false
+	res := &VerifiedHeaders{From: from}
+	for chunk := range chunks {
+		for headers := chunk.Blocks; len(headers) > 0; {
+			start := time.Now()
+			verified, done, err := verifyHeaders(h.chain, prevHash, epoch, committee, headers)
+			headerBatchTimer.UpdateSince(start)
+			headerVerifiedMeter.Mark(int64(len(verified)))
+			for _, blk := range verified {
+				res.Hashes = append(res.Hashes, *blk.Hash())
+			}
+			if len(verified) > 0 {
+				last := verified[len(verified)-1]
+				prevHash, epoch, changed = last.Hash().String(), last.GetCurrentEpoch(), false
+			}
+			if err != nil {
+				Logger.Infof("Syncker rejects header %v from peer %v: %v", from+uint64(len(res.Hashes)), chunk.PeerID, err)
+				// a header at a committee change may be signed by a committee
+				// the node does not know yet, its peer is not reported
+				switch {
+				case err == errHeaderLink || err == errHeaderEpoch:
+					h.report(chunk.PeerID, peerv2.InvalidBlock)
+				case !changed && !h.chain.ChangesCommittee(headers[len(verified)], committee):
+					h.report(chunk.PeerID, peerv2.BadSignature)
+				}
+				return res.verified()
+			}
+			if !done {
+				break
+			}
+			// the committee after the last header is of its view, if the
+			// block is inserted
+			next := h.chain.GetViewByHash(*verified[len(verified)-1].Hash())
+			if next == nil {
+				return res.verified()
+			}
+			committee, changed = next.GetCommittee(), true
+			headers = headers[len(verified):]
+		}
+	}
+	return res.verified()
+}
+
+// verified returns v, or nil if it has no header
+func (v *VerifiedHeaders) verified() *VerifiedHeaders {
+	if len(v.Hashes) == 0 {
+		return nil
+	}
+	return v
+}
+
+// verifyHeaders verifies that headers follow the block of hash prevHash and
+// each other, do not go back to an epoch before epoch, and are signed by
+// committee. It returns the headers verified from the first one, and whether
+// they end with a block changing the committee.
+func verifyHeaders(chain Chain, prevHash string, epoch uint64, committee []incognitokey.CommitteePublicKey, headers []common.BlockInterface) ([]common.BlockInterface, bool, error) {
+	var err error
+	done := false
+	linked := headers
+	for i, header := range headers {
+		if header.GetPrevHash().String() != prevHash {
+			linked, err = headers[:i], errHeaderLink
+			break
+		}
+		if header.GetCurrentEpoch() < epoch {
+			linked, err = headers[:i], errHeaderEpoch
+			break
+		}
+		prevHash, epoch = header.Hash().String(), header.GetCurrentEpoch()
+		if chain.ChangesCommittee(header, committee) {
+			linked, done = headers[:i+1], true
+			break
+		}
+	}
+	if len(linked) == 0 {
+		return nil, false, err
+	}
+
+	if sigErr := chain.BatchValidateBlockSignatures(linked, committee); sigErr != nil {
+		// Find the first header which is not signed
+		for i, header := range linked {
+			if sigErr := chain.ValidateBlockSignatures(header, committee); sigErr != nil {
+				return linked[:i], false, sigErr
+			}
+		}
+	}
+	return linked, done, err
+}
+
+// matchHeaders returns true if the blocks of chunk have the hashes of their
+// verified headers, or if headers is nil. A peer sending other blocks is
+// reported, and the blocks before the first one not matching are kept in
+// chunk.
+func matchHeaders(server Server, headers *VerifiedHeaders, chunk *Chunk) bool {
+	if headers == nil {
+		return true
+	}
+	n := headers.Match(chunk.Blocks)
+	if n == len(chunk.Blocks) {
+		return true
+	}
+	Logger.Infof("Syncker got block %v from peer %v not matching its header", chunk.Blocks[n].GetHeight(), chunk.PeerID)
+	server.ReportPeer(chunk.PeerID, peerv2.InvalidBlock)
+	chunk.Blocks = chunk.Blocks[:n]
+	return false
+}
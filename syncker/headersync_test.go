@@ -0,0 +1,268 @@
+package syncker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/stretchr/testify/assert"
+)
+
+// testChain is a chain of beacon blocks whose blocks are signed unless they
+// are in unsigned. Its committee changes at the last block of each epoch, and
+// the blocks up to best are inserted.
+type testChain struct {
+	Chain
+	blocks   []*blockchain.BeaconBlock // by height, from 0
+	final    uint64
+	best     uint64
+	unsigned map[uint64]bool
+	batches  int
+
+	epochLength uint64
+}
+
+func newTestChain(height uint64, epochLength uint64) *testChain {
+	c := &testChain{unsigned: map[uint64]bool{}, epochLength: epochLength}
+	prevHash := common.Hash{}
+	for h := uint64(0); h <= height; h++ {
+		blk := &blockchain.BeaconBlock{}
+		blk.Header.Height = h
+		blk.Header.Epoch = 1
+		if h > 0 {
+			blk.Header.Epoch = (h-1)/epochLength + 1
+		}
+		blk.Header.PreviousBlockHash = prevHash
+		prevHash = *blk.Hash()
+		c.blocks = append(c.blocks, blk)
+	}
+	return c
+}
+
+// committee returns the committee after the block of height h
+func (c *testChain) committee(h uint64) []incognitokey.CommitteePublicKey {
+	return []incognitokey.CommitteePublicKey{{IncPubKey: []byte{byte(h / c.epochLength)}}}
+}
+
+// testView is the view of the chain at a height
+type testView struct {
+	multiview.View
+	chain  *testChain
+	height uint64
+}
+
+func (v *testView) GetHash() *common.Hash           { return v.chain.blocks[v.height].Hash() }
+func (v *testView) GetBlock() common.BlockInterface { return v.chain.blocks[v.height] }
+func (v *testView) GetCommittee() []incognitokey.CommitteePublicKey {
+	return v.chain.committee(v.height)
+}
+
+func (c *testChain) GetFinalView() multiview.View { return &testView{chain: c, height: c.final} }
+
+func (c *testChain) GetViewByHash(hash common.Hash) multiview.View {
+	for h := c.final; h <= c.best && h < uint64(len(c.blocks)); h++ {
+		if c.blocks[h].Hash().IsEqual(&hash) {
+			return &testView{chain: c, height: h}
+		}
+	}
+	return nil
+}
+
+func (c *testChain) ChangesCommittee(blk common.BlockInterface, committee []incognitokey.CommitteePublicKey) bool {
+	return blk.GetHeight()%c.epochLength == 0
+}
+
+func (c *testChain) ValidateBlockSignatures(blk common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	if c.unsigned[blk.GetHeight()] {
+		return errors.New("not signed")
+	}
+	if !reflect.DeepEqual(committee, c.committee(blk.GetHeight()-1)) {
+		return errors.New("signed by another committee")
+	}
+	return nil
+}
+
+func (c *testChain) BatchValidateBlockSignatures(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	c.batches++
+	for _, blk := range blocks {
+		if err := c.ValidateBlockSignatures(blk, committee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetch streams the headers of the chain, from a peer which can change them
+func (c *testChain) fetch(edit func(blk *blockchain.BeaconBlock)) fetchFunc {
+	return func(ctx context.Context, peerID string, from, to uint64) (chan common.BlockInterface, error) {
+		ch := make(chan common.BlockInterface, to-from+1)
+		for height := from; height <= to && height < uint64(len(c.blocks)); height++ {
+			blk := peerv2.BlockHeader(c.blocks[height]).(*blockchain.BeaconBlock)
+			if edit != nil {
+				edit(blk)
+			}
+			ch <- blk
+		}
+		close(ch)
+		return ch, nil
+	}
+}
+
+func TestHeaderSyncVerifiesInBatches(t *testing.T) {
+	chain := newTestChain(100, 1000)
+	chain.final = 20
+	peers := newTestPeers(map[string]uint64{"a": 100})
+	hs := NewHeaderSync("beacon", chain, testDownloaderConfig(), chain.fetch(nil), peers.report)
+
+	headers := hs.Sync(context.Background(), 21, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		assert.Equal(t, uint64(21), headers.From)
+		assert.Equal(t, uint64(100), headers.To())
+		assert.Equal(t, *chain.blocks[57].Hash(), headers.Hashes[57-21])
+	}
+	assert.Equal(t, 8, chain.batches)
+	assert.Empty(t, peers.reports["a"])
+}
+
+func TestHeaderSyncStopsAtCommitteeChange(t *testing.T) {
+	chain := newTestChain(100, 50)
+	chain.final = 10
+	peers := newTestPeers(map[string]uint64{"a": 100})
+	hs := NewHeaderSync("beacon", chain, testDownloaderConfig(), chain.fetch(nil), peers.report)
+
+	headers := hs.Sync(context.Background(), 11, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		// the committee changes at block 50, which is not inserted
+		assert.Equal(t, uint64(50), headers.To())
+	}
+	assert.Empty(t, peers.reports["a"])
+}
+
+func TestHeaderSyncFollowsCommitteeChanges(t *testing.T) {
+	chain := newTestChain(100, 20)
+	chain.final = 10
+	chain.best = 45
+	peers := newTestPeers(map[string]uint64{"a": 100})
+	hs := NewHeaderSync("beacon", chain, testDownloaderConfig(), chain.fetch(nil), peers.report)
+
+	// the headers after blocks 20 and 40 are verified against the committees
+	// of their views, and the sync stops at block 60, which is not inserted
+	headers := hs.Sync(context.Background(), 11, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		assert.Equal(t, uint64(60), headers.To())
+		assert.Equal(t, *chain.blocks[41].Hash(), headers.Hashes[41-11])
+	}
+	assert.Empty(t, peers.reports["a"])
+
+	// the first block of a committee may be signed by a committee the node
+	// does not know yet
+	chain.unsigned[41] = true
+	headers = hs.Sync(context.Background(), 11, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		assert.Equal(t, uint64(40), headers.To())
+	}
+	assert.Empty(t, peers.reports["a"])
+
+	chain.unsigned = map[uint64]bool{42: true}
+	headers = hs.Sync(context.Background(), 11, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		assert.Equal(t, uint64(41), headers.To())
+	}
+	assert.Equal(t, []peerv2.Behaviour{peerv2.BadSignature}, peers.reports["a"])
+}
+
+func TestHeaderSyncWaitsForRequests(t *testing.T) {
+	chain := newTestChain(100, 50)
+	chain.final = 10
+	peers := newTestPeers(map[string]uint64{"a": 100})
+	fetch := chain.fetch(nil)
+	// the peer does not answer the requests after the epoch
+	slow := func(ctx context.Context, peerID string, from, to uint64) (chan common.BlockInterface, error) {
+		if from > 50 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return fetch(ctx, peerID, from, to)
+	}
+	hs := NewHeaderSync("beacon", chain, testDownloaderConfig(), slow, peers.report)
+
+	headers := hs.Sync(context.Background(), 11, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		assert.Equal(t, uint64(50), headers.To())
+	}
+	// the request of the blocks after 50 is over
+	assert.Equal(t, []peerv2.Behaviour{peerv2.FailedStream}, peers.reports["a"])
+}
+
+func TestHeaderSyncRejectsBadHeaders(t *testing.T) {
+	chain := newTestChain(100, 1000)
+	peers := newTestPeers(map[string]uint64{"a": 100})
+
+	chain.unsigned[35] = true
+	headers := NewHeaderSync("beacon", chain, testDownloaderConfig(), chain.fetch(nil), peers.report).Sync(context.Background(), 1, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		assert.Equal(t, uint64(34), headers.To())
+	}
+	assert.Equal(t, []peerv2.Behaviour{peerv2.BadSignature}, peers.reports["a"])
+
+	// a peer changing a header breaks the link to the next one
+	chain.unsigned = map[uint64]bool{}
+	peers = newTestPeers(map[string]uint64{"a": 100})
+	edit := func(blk *blockchain.BeaconBlock) {
+		if blk.Header.Height == 5 {
+			blk.Header.Timestamp++
+		}
+	}
+	headers = NewHeaderSync("beacon", chain, testDownloaderConfig(), chain.fetch(edit), peers.report).Sync(context.Background(), 1, 100, peers.height)
+	if assert.NotNil(t, headers) {
+		assert.Equal(t, uint64(5), headers.To())
+	}
+	assert.Equal(t, []peerv2.Behaviour{peerv2.InvalidBlock}, peers.reports["a"])
+
+	// the first header must follow the final view
+	chain.final = 3
+	peers = newTestPeers(map[string]uint64{"a": 100})
+	headers = NewHeaderSync("beacon", chain, testDownloaderConfig(), chain.fetch(nil), peers.report).Sync(context.Background(), 1, 100, peers.height)
+	assert.Nil(t, headers)
+	assert.Equal(t, []peerv2.Behaviour{peerv2.InvalidBlock}, peers.reports["a"])
+}
+
+func TestMatchHeaders(t *testing.T) {
+	chain := newTestChain(20, 1000)
+	headers := &VerifiedHeaders{From: 1}
+	for _, blk := range chain.blocks[1:11] {
+		headers.Hashes = append(headers.Hashes, *blk.Hash())
+	}
+	node := &testServer{reports: map[string][]peerv2.Behaviour{}}
+
+	chunk := &Chunk{PeerID: "a", Blocks: []common.BlockInterface{chain.blocks[1], chain.blocks[2]}}
+	assert.True(t, matchHeaders(node, headers, chunk))
+	assert.True(t, matchHeaders(node, nil, chunk))
+
+	other := &blockchain.BeaconBlock{}
+	other.Header.Height = 3
+	chunk = &Chunk{PeerID: "a", Blocks: []common.BlockInterface{chain.blocks[1], chain.blocks[2], other, chain.blocks[4]}}
+	assert.False(t, matchHeaders(node, headers, chunk))
+	assert.Len(t, chunk.Blocks, 2)
+	assert.Equal(t, []peerv2.Behaviour{peerv2.InvalidBlock}, node.reports["a"])
+
+	// blocks after the verified headers
+	chunk = &Chunk{PeerID: "b", Blocks: []common.BlockInterface{chain.blocks[10], chain.blocks[11]}}
+	assert.False(t, matchHeaders(node, headers, chunk))
+	assert.Len(t, chunk.Blocks, 1)
+}
+
+type testServer struct {
+	Server
+	reports map[string][]peerv2.Behaviour
+}
+
+func (s *testServer) ReportPeer(peerID string, b peerv2.Behaviour) {
+	s.reports[peerID] = append(s.reports[peerID], b)
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
+	"github.com/incognitochain/incognito-chain/peerv2"
 )
 
 type Server interface {
@@ -19,6 +21,8 @@ type Server interface {
 	PublishNodeState(userLayer string, shardID int) error
 	RequestBeaconBlocksViaStream(ctx context.Context, peerID string, from uint64, to uint64) (blockCh chan common.BlockInterface, err error)
 	RequestShardBlocksViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error)
+	RequestBeaconHeadersViaStream(ctx context.Context, peerID string, from uint64, to uint64) (blockCh chan common.BlockInterface, err error)
+	RequestShardHeadersViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error)
 	RequestShardToBeaconBlocksViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error)
 	RequestCrossShardBlocksViaStream(ctx context.Context, peerID string, fromSID int, toSID int, heights []uint64) (blockCh chan common.BlockInterface, err error)
 	//PushMessageGetBlockShardToBeaconByHash(shardID byte, blkHashes []common.Hash, getFromPool bool, peerID libp2p.ID) error
@@ -28,6 +32,8 @@ type Server interface {
 
 	RequestBeaconBlocksByHashViaStream(ctx context.Context, peerID string, hashes [][]byte) (blockCh chan common.BlockInterface, err error)
 	RequestShardBlocksByHashViaStream(ctx context.Context, peerID string, fromSID int, hashes [][]byte) (blockCh chan common.BlockInterface, err error)
+	ReportPeer(peerID string, b peerv2.Behaviour)
+	IsPeerBanned(peerID string) bool
 	//database
 	FetchConfirmBeaconBlockByHeight(height uint64) (*blockchain.BeaconBlock, error)
 	GetBeaconChainDatabase() incdb.Database
@@ -55,10 +61,14 @@ type Chain interface {
 	IsReady() bool
 	GetBestViewHash() string
 	GetFinalViewHash() string
+	GetFinalView() multiview.View
+	GetViewByHash(hash common.Hash) multiview.View
 	GetEpoch() uint64
 	ValidateBlockSignatures(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) error
+	BatchValidateBlockSignatures(blocks []common.BlockInterface, committee []incognitokey.CommitteePublicKey) error
 	//ValidateProducerPosition(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) error
 	GetCommittee() []incognitokey.CommitteePublicKey
+	ChangesCommittee(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) bool
 	CurrentHeight() uint64
 	InsertBlk(block common.BlockInterface, shouldValidate bool) error
 	CheckExistedBlk(block common.BlockInterface) bool
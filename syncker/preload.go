@@ -3,6 +3,7 @@ package syncker
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -95,6 +96,10 @@ func makeRPCRequest(address string, method string, params ...interface{}) (*Json
 
 //preloadDatabase call to backuped database node ...
 func preloadDatabase(chainID int, currentEpoch int, url string, db incdb.Database, btcChain *btcrelaying.BlockChain) error {
+	backuper, ok := db.(incdb.Backuper)
+	if !ok {
+		return errors.New("database engine does not support preload backup")
+	}
 	chainName := "beacon"
 	if chainID > -1 {
 		chainName = fmt.Sprintf("shard%v", chainID)
@@ -142,10 +147,10 @@ func preloadDatabase(chainID int, currentEpoch int, url string, db incdb.Databas
 		fmt.Println("Download finish", chainName)
 
 		db.Close()
-		defer db.ReOpen()
+		defer backuper.ReOpen()
 
 		//restore beacon|shard
-		err = db.PreloadBackup(backupFile)
+		err = backuper.PreloadBackup(backupFile)
 		if err != nil {
 			return err
 		}
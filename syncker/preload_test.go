@@ -5,5 +5,5 @@ import (
 )
 
 func Test_preloadDatabase(t *testing.T) {
-	preloadDatabase(0, 0, "http://127.0.0.1:20004", nil)
+	preloadDatabase(0, 0, "http://127.0.0.1:20004", nil, nil)
 }
@@ -18,7 +18,7 @@ type S2BPeerState struct {
 }
 
 type S2BSyncProcess struct {
-	status            string                           //stop, running
+	syncState
 	s2bPeerState      map[string]map[byte]S2BPeerState //sender -> state
 	s2bPeerStateCh    chan *wire.MessagePeerState
 	Server            Server
@@ -38,7 +38,7 @@ func NewS2BSyncProcess(server Server, beaconSyncProc *BeaconSyncProcess, beaconC
 	}
 
 	s := &S2BSyncProcess{
-		status:            STOP_SYNC,
+		syncState:         syncState{status: STOP_SYNC},
 		Server:            server,
 		beaconChain:       beaconChain,
 		s2bPool:           NewBlkPool("ShardToBeaconPool", isOutdatedBlock),
@@ -85,15 +85,11 @@ func NewS2BSyncProcess(server Server, beaconSyncProc *BeaconSyncProcess, beaconC
 }
 
 func (s *S2BSyncProcess) start() {
-	if s.status == RUNNING_SYNC {
-		return
-	}
-	s.status = RUNNING_SYNC
-
+	s.setStatus(RUNNING_SYNC)
 }
 
 func (s *S2BSyncProcess) stop() {
-	s.status = STOP_SYNC
+	s.setStatus(STOP_SYNC)
 }
 
 //helper function to access map in atomic way
@@ -116,7 +112,7 @@ func (s *S2BSyncProcess) getS2BPeerState() map[string]map[byte]S2BPeerState {
 func (s *S2BSyncProcess) syncS2BPoolProcess() {
 	for {
 		requestCnt := 0
-		if !s.beaconSyncProcess.isCatchUp || s.status != RUNNING_SYNC {
+		if !s.beaconSyncProcess.getCatchUp() || s.getStatus() != RUNNING_SYNC {
 			time.Sleep(time.Second)
 			continue
 		}
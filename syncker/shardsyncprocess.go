@@ -18,14 +18,11 @@ type ShardPeerState struct {
 	Timestamp      int64
 	BestViewHash   string
 	BestViewHeight uint64
-	processed      bool
 }
 
 type ShardSyncProcess struct {
-	isCommittee           bool
-	isCatchUp             bool
+	syncState
 	shardID               int
-	status                string                    //stop, running
 	shardPeerState        map[string]ShardPeerState //peerid -> state
 	shardPeerStateCh      chan *wire.MessagePeerState
 	crossShardSyncProcess *CrossShardSyncProcess
@@ -33,8 +30,11 @@ type ShardSyncProcess struct {
 	Chain                 ShardChainInterface
 	beaconChain           Chain
 	shardPool             *BlkPool
+	downloader            *Downloader
+	headerSync            *HeaderSync
 	actionCh              chan func()
 	lock                  *sync.RWMutex
+	peers                 peerBest
 }
 
 func NewShardSyncProcess(shardID int, server Server, beaconChain BeaconChainInterface, chain ShardChainInterface) *ShardSyncProcess {
@@ -47,13 +47,19 @@ func NewShardSyncProcess(shardID int, server Server, beaconChain BeaconChainInte
 
 	s := &ShardSyncProcess{
 		shardID:          shardID,
-		status:           STOP_SYNC,
+		syncState:        syncState{status: STOP_SYNC},
 		Server:           server,
 		Chain:            chain,
 		beaconChain:      beaconChain,
 		shardPool:        NewBlkPool("ShardPool-"+string(shardID), isOutdatedBlock),
 		shardPeerState:   make(map[string]ShardPeerState),
 		shardPeerStateCh: make(chan *wire.MessagePeerState),
+		downloader: NewDownloader(fmt.Sprintf("shard %v", shardID), DefaultDownloaderConfig, func(ctx context.Context, peerID string, from, to uint64) (chan common.BlockInterface, error) {
+			return server.RequestShardBlocksViaStream(ctx, peerID, shardID, from, to)
+		}, server.ReportPeer),
+		headerSync: NewHeaderSync(fmt.Sprintf("shard %v", shardID), chain, DefaultDownloaderConfig, func(ctx context.Context, peerID string, from, to uint64) (chan common.BlockInterface, error) {
+			return server.RequestShardHeadersViaStream(ctx, peerID, shardID, from, to)
+		}, server.ReportPeer),
 
 		actionCh: make(chan func()),
 	}
@@ -65,7 +71,7 @@ func NewShardSyncProcess(shardID int, server Server, beaconChain BeaconChainInte
 	go func() {
 		ticker := time.NewTicker(time.Millisecond * 500)
 		for {
-			if s.isCommittee {
+			if s.getCommittee() {
 				s.crossShardSyncProcess.start()
 			} else {
 				s.crossShardSyncProcess.stop()
@@ -86,11 +92,16 @@ func NewShardSyncProcess(shardID int, server Server, beaconChain BeaconChainInte
 					}
 				}
 			case <-ticker.C:
+				peerHeight := uint64(0)
 				for sender, ps := range s.shardPeerState {
 					if ps.Timestamp < time.Now().Unix()-10 {
 						delete(s.shardPeerState, sender)
+					} else if ps.BestViewHeight > peerHeight {
+						peerHeight = ps.BestViewHeight
 					}
 				}
+				updateSyncLag(common.GetShardChainKey(byte(s.shardID)), s.Chain, peerHeight)
+				s.peers.update(peerHeight, len(s.shardPeerState))
 			}
 		}
 	}()
@@ -99,14 +110,11 @@ func NewShardSyncProcess(shardID int, server Server, beaconChain BeaconChainInte
 }
 
 func (s *ShardSyncProcess) start() {
-	if s.status == RUNNING_SYNC {
-		return
-	}
-	s.status = RUNNING_SYNC
+	s.setStatus(RUNNING_SYNC)
 }
 
 func (s *ShardSyncProcess) stop() {
-	s.status = STOP_SYNC
+	s.setStatus(STOP_SYNC)
 	s.crossShardSyncProcess.stop()
 }
 
@@ -162,7 +170,7 @@ func (s *ShardSyncProcess) insertShardBlockFromPool() {
 			insertCnt++
 			//must validate this block when insert
 			if err := s.Chain.InsertBlk(blk.(common.BlockInterface), true); err != nil {
-				Logger.Error("Insert shard block from pool fail", blk.GetHeight(), blk.Hash(), err)
+				blockLogger(blk.(common.BlockInterface)).Error("Insert shard block from pool fail", blk.GetHeight(), blk.Hash(), err)
 				continue
 			}
 			s.shardPool.RemoveBlock(blk.Hash())
@@ -173,22 +181,21 @@ func (s *ShardSyncProcess) insertShardBlockFromPool() {
 func (s *ShardSyncProcess) syncShardProcess() {
 	for {
 		requestCnt := 0
-		if s.status != RUNNING_SYNC {
-			s.isCatchUp = false
+		if s.getStatus() != RUNNING_SYNC {
+			s.setCatchUp(false)
 			time.Sleep(time.Second * 5)
 			continue
 		}
 
-		for peerID, pState := range s.getShardPeerStates() {
-			requestCnt += s.streamFromPeer(peerID, pState)
-		}
+		peerStates := s.getShardPeerStates()
+		requestCnt += s.downloadFromPeers(peerStates)
 
 		if requestCnt > 0 {
-			s.isCatchUp = false
+			s.setCatchUp(false)
 			// s.syncShardProcess()
 		} else {
-			if len(s.shardPeerState) > 0 {
-				s.isCatchUp = true
+			if len(peerStates) > 0 {
+				s.setCatchUp(true)
 			}
 			time.Sleep(time.Second * 5)
 		}
@@ -196,83 +203,74 @@ func (s *ShardSyncProcess) syncShardProcess() {
 
 }
 
-func (s *ShardSyncProcess) streamFromPeer(peerID string, pState ShardPeerState) (requestCnt int) {
-	if pState.processed {
-		return
-	}
-
-	blockBuffer := []common.BlockInterface{}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer func() {
-		if requestCnt == 0 {
-			pState.processed = true
+// downloadFromPeers downloads the blocks up to the best height of the peers,
+// from all of them at once
+func (s *ShardSyncProcess) downloadFromPeers(peerStates map[string]ShardPeerState) (requestCnt int) {
+	peers := map[string]uint64{}
+	toHeight := uint64(0)
+	for peerID, pState := range peerStates {
+		if s.Server.IsPeerBanned(peerID) {
+			continue
 		}
-		cancel()
-	}()
+		height := pState.BestViewHeight
+		//fullnode delay 1 block (make sure insert final block)
+		if os.Getenv("FULLNODE") != "" && height > 0 {
+			height--
+		}
+		peers[peerID] = height
+		if height > toHeight {
+			toHeight = height
+		}
+	}
 
-	if pState.processed {
+	if toHeight <= s.Chain.GetBestViewHeight() {
 		return
 	}
-	toHeight := pState.BestViewHeight
 
-	//fullnode delay 1 block (make sure insert final block)
-	if os.Getenv("FULLNODE") != "" {
-		toHeight = pState.BestViewHeight - 1
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	requestCnt++
+	from := s.Chain.GetFinalViewHeight() + 1
+	var headers *VerifiedHeaders
+	if HeaderFirst {
+		if headers = s.headerSync.Sync(ctx, from, toHeight, peers); headers == nil {
+			return
+		}
+		toHeight = headers.To()
 	}
-
-	if toHeight <= s.Chain.GetBestViewHeight() {
-		return
+	for chunk := range s.downloader.Download(ctx, from, toHeight, peers) {
+		matched := matchHeaders(s.Server, headers, chunk)
+		if len(chunk.Blocks) == 0 || !s.insertChunk(chunk) || !matched {
+			return
+		}
 	}
+	return
+}
 
-	//fmt.Println("SYNCKER Request Shard Block", peerID, s.ShardID, s.Chain.GetBestViewHeight()+1, pState.BestViewHeight)
-	ch, err := s.Server.RequestShardBlocksViaStream(ctx, peerID, s.shardID, s.Chain.GetFinalViewHeight()+1, toHeight)
-	// ch, err := s.Server.RequestShardBlocksViaStream(ctx, "", s.shardID, s.Chain.GetBestViewHeight()+1, pState.BestViewHeight)
-	if err != nil {
-		fmt.Println("Syncker: create channel fail")
-		return
+// insertChunk inserts the blocks of a chunk once the beacon blocks they
+// refer to are inserted, it returns false if they cannot be inserted
+func (s *ShardSyncProcess) insertChunk(chunk *Chunk) bool {
+	lastBlk := chunk.Blocks[len(chunk.Blocks)-1].(*blockchain.ShardBlock)
+	if lastBlk.Header.BeaconHeight > s.beaconChain.GetBestViewHeight() {
+		time.Sleep(30 * time.Second)
+	}
+	if lastBlk.Header.BeaconHeight > s.beaconChain.GetBestViewHeight() {
+		Logger.Infof("Cannot find beacon for inserting shard block")
+		return false
 	}
 
-	requestCnt++
-	insertTime := time.Now()
+	blockBuffer := chunk.Blocks
 	for {
-		select {
-		case blk := <-ch:
-			if !isNil(blk) {
-				blockBuffer = append(blockBuffer, blk)
-
-				if blk.(*blockchain.ShardBlock).Header.BeaconHeight > s.beaconChain.GetBestViewHeight() {
-					time.Sleep(30 * time.Second)
-				}
-				if blk.(*blockchain.ShardBlock).Header.BeaconHeight > s.beaconChain.GetBestViewHeight() {
-					Logger.Infof("Cannot find beacon for inserting shard block")
-					return
-				}
-			}
-
-			if uint64(len(blockBuffer)) >= 500 || (len(blockBuffer) > 0 && (isNil(blk) || time.Since(insertTime) > time.Millisecond*2000)) {
-				insertBlkCnt := 0
-				for {
-					time1 := time.Now()
-					if successBlk, err := InsertBatchBlock(s.Chain, blockBuffer); err != nil {
-						return
-					} else {
-						insertBlkCnt += successBlk
-						fmt.Printf("Syncker Insert %d shard %d block(from %d to %d) elaspse %f \n", successBlk, s.shardID, blockBuffer[0].GetHeight(), blockBuffer[len(blockBuffer)-1].GetHeight(), time.Since(time1).Seconds())
-						if successBlk >= len(blockBuffer) || successBlk == 0 {
-							break
-						}
-						blockBuffer = blockBuffer[successBlk:]
-					}
-				}
-
-				insertTime = time.Now()
-				blockBuffer = []common.BlockInterface{}
-			}
-
-			if isNil(blk) && len(blockBuffer) == 0 {
-				return
-			}
+		time1 := time.Now()
+		successBlk, err := InsertBatchBlock(s.Chain, blockBuffer)
+		reportBatch(s.Server, chunk.PeerID, successBlk, err)
+		if err != nil {
+			return false
+		}
+		chunkLogger(s.shardID, chunk).Infof("Syncker Insert %d shard %d block(from %d to %d) elaspse %f \n", successBlk, s.shardID, blockBuffer[0].GetHeight(), blockBuffer[len(blockBuffer)-1].GetHeight(), time.Since(time1).Seconds())
+		if successBlk >= len(blockBuffer) || successBlk == 0 {
+			return true
 		}
+		blockBuffer = blockBuffer[successBlk:]
 	}
-
 }
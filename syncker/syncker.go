@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
@@ -25,7 +26,7 @@ type SynckerManagerConfig struct {
 }
 
 type SynckerManager struct {
-	isEnabled             bool //0 > stop, 1: running
+	isEnabled             int32 //0 > stop, 1: running
 	config                *SynckerManagerConfig
 	BeaconSyncProcess     *BeaconSyncProcess
 	S2BSyncProcess        *S2BSyncProcess
@@ -96,11 +97,11 @@ func (synckerManager *SynckerManager) Init(config *SynckerManagerConfig) {
 }
 
 func (synckerManager *SynckerManager) Start() {
-	synckerManager.isEnabled = true
+	atomic.StoreInt32(&synckerManager.isEnabled, 1)
 }
 
 func (synckerManager *SynckerManager) Stop() {
-	synckerManager.isEnabled = false
+	atomic.StoreInt32(&synckerManager.isEnabled, 0)
 	synckerManager.BeaconSyncProcess.stop()
 	for _, chain := range synckerManager.ShardSyncProcess {
 		chain.stop()
@@ -112,11 +113,11 @@ func (synckerManager *SynckerManager) manageSyncProcess() {
 	defer time.AfterFunc(time.Second*5, synckerManager.manageSyncProcess)
 
 	//check if enable
-	if !synckerManager.isEnabled || synckerManager.config == nil {
+	if atomic.LoadInt32(&synckerManager.isEnabled) == 0 || synckerManager.config == nil {
 		return
 	}
 	role, chainID := synckerManager.config.Node.GetUserMiningState()
-	synckerManager.BeaconSyncProcess.isCommittee = (role == common.CommitteeRole) && (chainID == -1)
+	synckerManager.BeaconSyncProcess.setCommittee((role == common.CommitteeRole) && (chainID == -1))
 
 	preloadAddr := synckerManager.config.Blockchain.GetConfig().ChainParams.PreloadAddress
 	synckerManager.BeaconSyncProcess.start()
@@ -130,7 +131,7 @@ func (synckerManager *SynckerManager) manageSyncProcess() {
 			if _, ok := wantedShard[byte(sid)]; ok || (int(sid) == chainID) {
 				//check preload shard
 				if preloadAddr != "" {
-					if syncProc.status != RUNNING_SYNC { //run only when start
+					if syncProc.getStatus() != RUNNING_SYNC { //run only when start
 						if err := preloadDatabase(sid, int(syncProc.Chain.GetEpoch()), preloadAddr, synckerManager.config.Blockchain.GetShardChainDatabase(byte(sid)), nil); err != nil {
 							fmt.Println(err)
 							Logger.Infof("Preload shard %v fail!", sid)
@@ -143,7 +144,7 @@ func (synckerManager *SynckerManager) manageSyncProcess() {
 			} else {
 				syncProc.stop()
 			}
-			syncProc.isCommittee = role == common.CommitteeRole || role == common.PendingRole
+			syncProc.setCommittee(role == common.CommitteeRole || role == common.PendingRole)
 		}(sid, syncProc)
 	}
 	wg.Wait()
@@ -575,26 +576,26 @@ type SynckerStatusInfo struct {
 func (synckerManager *SynckerManager) GetSyncStatus(includePool bool) SynckerStatusInfo {
 	info := SynckerStatusInfo{}
 	info.Beacon = syncInfo{
-		IsSync:   synckerManager.BeaconSyncProcess.status == RUNNING_SYNC,
-		IsLatest: synckerManager.BeaconSyncProcess.isCatchUp,
+		IsSync:   synckerManager.BeaconSyncProcess.getStatus() == RUNNING_SYNC,
+		IsLatest: synckerManager.BeaconSyncProcess.getCatchUp(),
 	}
 	info.S2B = syncInfo{
-		IsSync:   synckerManager.S2BSyncProcess.status == RUNNING_SYNC,
+		IsSync:   synckerManager.S2BSyncProcess.getStatus() == RUNNING_SYNC,
 		IsLatest: false,
 	}
 
 	info.Shard = make(map[int]*syncInfo)
 	for k, v := range synckerManager.ShardSyncProcess {
 		info.Shard[k] = &syncInfo{
-			IsSync:   v.status == RUNNING_SYNC,
-			IsLatest: v.isCatchUp,
+			IsSync:   v.getStatus() == RUNNING_SYNC,
+			IsLatest: v.getCatchUp(),
 		}
 	}
 
 	info.Crossshard = make(map[int]*syncInfo)
 	for k, v := range synckerManager.CrossShardSyncProcess {
 		info.Crossshard[k] = &syncInfo{
-			IsSync:   v.status == RUNNING_SYNC,
+			IsSync:   v.getStatus() == RUNNING_SYNC,
 			IsLatest: false,
 		}
 	}
@@ -614,9 +615,9 @@ func (synckerManager *SynckerManager) GetSyncStatus(includePool bool) SynckerSta
 
 func (synckerManager *SynckerManager) IsChainReady(chainID int) bool {
 	if chainID == -1 {
-		return synckerManager.BeaconSyncProcess.isCatchUp
+		return synckerManager.BeaconSyncProcess.getCatchUp()
 	} else if chainID >= 0 {
-		return synckerManager.ShardSyncProcess[chainID].isCatchUp
+		return synckerManager.ShardSyncProcess[chainID].getCatchUp()
 	}
 	return false
 }
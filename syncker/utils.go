@@ -1,15 +1,97 @@
 package syncker
 
 import (
+	"errors"
 	"reflect"
+	"sync"
 
+	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/peerv2"
 )
 
 const RUNNING_SYNC = "running_sync"
 const STOP_SYNC = "stop_sync"
 
+// syncState is the state of a sync process, set by the syncker manager and
+// read by the goroutines of the process
+type syncState struct {
+	stateLock   sync.RWMutex
+	status      string //stop, running
+	isCommittee bool
+	isCatchUp   bool
+}
+
+func (s *syncState) getStatus() string {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+	return s.status
+}
+
+// setStatus sets the status and returns whether it changed
+func (s *syncState) setStatus(status string) bool {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+	changed := s.status != status
+	s.status = status
+	return changed
+}
+
+func (s *syncState) getCommittee() bool {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+	return s.isCommittee
+}
+
+func (s *syncState) setCommittee(isCommittee bool) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+	s.isCommittee = isCommittee
+}
+
+func (s *syncState) getCatchUp() bool {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+	return s.isCatchUp
+}
+
+func (s *syncState) setCatchUp(isCatchUp bool) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+	s.isCatchUp = isCatchUp
+}
+
+// ErrBlockSignature is returned by InsertBatchBlock when no block of the batch
+// is signed by the committee
+var ErrBlockSignature = errors.New("no block signed by the committee")
+
+// updateSyncLag records how many blocks a chain is behind the best of the
+// heights of its peers, by chain key
+func updateSyncLag(chainKey string, chain Chain, peerHeight uint64) {
+	lag := int64(0)
+	if height := chain.GetBestViewHeight(); peerHeight > height {
+		lag = int64(peerHeight - height)
+	}
+	metrics.GetOrRegisterGauge(metrics.LabeledName("syncker/lag", "chain", chainKey), nil).Update(lag)
+}
+
+// blockLogger returns the logger of the entries about a block
+func blockLogger(blk common.BlockInterface) common.Logger {
+	chainID := common.BeaconChainID
+	if shardBlk, ok := blk.(*blockchain.ShardBlock); ok {
+		chainID = int(shardBlk.Header.ShardID)
+	}
+	return common.WithFields(Logger.Logger, common.BlockFields(chainID, blk.GetHeight(), blk.Hash().String()))
+}
+
+// chunkLogger returns the logger of the entries about a chunk of blocks of a
+// chain, by the peer which sent them
+func chunkLogger(chainID int, chunk *Chunk) common.Logger {
+	return common.WithFields(Logger.Logger, common.Fields{common.FieldChainID: chainID, common.FieldPeerID: chunk.PeerID})
+}
+
 func isNil(v interface{}) bool {
 	return v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil())
 }
@@ -41,6 +123,9 @@ func InsertBatchBlock(chain Chain, blocks []common.BlockInterface) (int, error)
 			break
 		}
 	}
+	if len(blocks) > 0 && len(sameCommitteeBlock) == 0 {
+		return 0, ErrBlockSignature
+	}
 
 	for i, v := range sameCommitteeBlock {
 		if !chain.CheckExistedBlk(v) {
@@ -52,7 +137,7 @@ func InsertBatchBlock(chain Chain, blocks []common.BlockInterface) (int, error)
 			}
 			if err != nil {
 				committeeStr, _ := incognitokey.CommitteeKeyListToString(epochCommittee)
-				Logger.Errorf("Insert block %v hash %v got error %v, Committee of epoch %v", v.GetHeight(), v.Hash(), err, committeeStr)
+				blockLogger(v).Errorf("Insert block %v hash %v got error %v, Committee of epoch %v", v.GetHeight(), v.Hash(), err, committeeStr)
 				return 0, err
 			}
 		}
@@ -62,6 +147,79 @@ func InsertBatchBlock(chain Chain, blocks []common.BlockInterface) (int, error)
 	return len(sameCommitteeBlock), nil
 }
 
+// invalidBlockErrors are the errors of the checks of a block against the view
+// it links to in verifyPreProcessing, verifyBestState and verifyPostProcessing
+// which only depend on the block. The errors of the database, of a missing
+// view or beacon block, e.g. when this node is behind, are not the fault of
+// the peer sending the block. BeaconCandidateRootError has no code, it can not
+// be told from the errors of the database.
+var invalidBlockErrors = []int{
+	// beacon and shard blocks
+	blockchain.WrongBlockHeightError,
+	blockchain.WrongTimestampError,
+	blockchain.FlattenAndConvertStringInstError,
+	// beacon blocks
+	blockchain.WrongEpochError,
+	blockchain.InstructionHashError,
+	blockchain.ShardStateError,
+	blockchain.ShardStateHashError,
+	blockchain.BeaconBestStateBestBlockNotCompatibleError,
+	blockchain.BeaconBestStateBestShardHeightNotCompatibleError,
+	blockchain.CandidateError,
+	blockchain.BeaconCommitteeAndPendingValidatorRootError,
+	blockchain.ShardCommitteeAndPendingValidatorRootError,
+	blockchain.ShardCandidateRootError,
+	// shard blocks
+	blockchain.WrongShardIDError,
+	blockchain.TransactionRootHashError,
+	blockchain.ShardTransactionRootHashError,
+	blockchain.CrossShardTransactionRootHashError,
+	blockchain.WrongBlockTotalFeeError,
+	blockchain.CrossShardBitMapError,
+	blockchain.InstructionMerkleRootError,
+	blockchain.InstructionsHashError,
+	blockchain.BeaconBlockNotCompatibleError,
+	blockchain.SwapInstructionError,
+	blockchain.ShardBestStateNotCompatibleError,
+	blockchain.ShardBestStateBeaconHeightNotCompatibleError,
+	blockchain.SwapValidatorError,
+	blockchain.ProcessSwapInstructionError,
+	blockchain.ShardCommitteeRootHashError,
+	blockchain.ShardPendingValidatorRootHashError,
+	blockchain.ShardStakingTxRootHashError,
+}
+
+// isInvalidBlockError returns whether err is a validation error of the block
+// itself, see invalidBlockErrors
+func isInvalidBlockError(err error) bool {
+	bcErr, ok := err.(*blockchain.BlockChainError)
+	if !ok {
+		return false
+	}
+	for _, key := range invalidBlockErrors {
+		if bcErr.Code == blockchain.ErrCodeMessage[key].Code {
+			return true
+		}
+	}
+	return false
+}
+
+// reportBatch scores the peer which sent a batch of blocks by the result of
+// InsertBatchBlock, the errors which are not caused by the blocks are not
+// scored
+func reportBatch(server Server, peerID string, successBlk int, err error) {
+	switch {
+	case err == ErrBlockSignature:
+		server.ReportPeer(peerID, peerv2.BadSignature)
+	case err != nil:
+		if isInvalidBlockError(err) {
+			server.ReportPeer(peerID, peerv2.InvalidBlock)
+		}
+	case successBlk > 0:
+		server.ReportPeer(peerID, peerv2.UsefulData)
+	}
+}
+
 //final block
 func GetFinalBlockFromBlockHash_v1(currentFinalHash string, byHash map[string]common.BlockPoolInterface, byPrevHash map[string][]string) (res []common.BlockPoolInterface) {
 	var finalBlock common.BlockPoolInterface = nil
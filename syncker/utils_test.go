@@ -0,0 +1,37 @@
+package syncker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportBatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		successBlk int
+		err        error
+		reports    []peerv2.Behaviour
+	}{
+		{"inserted", 3, nil, []peerv2.Behaviour{peerv2.UsefulData}},
+		{"nothing new", 0, nil, nil},
+		{"bad signature", 0, ErrBlockSignature, []peerv2.Behaviour{peerv2.BadSignature}},
+		{"wrong transaction root", 0, blockchain.NewBlockChainError(blockchain.TransactionRootHashError, errors.New("root")), []peerv2.Behaviour{peerv2.InvalidBlock}},
+		{"wrong committee root", 1, blockchain.NewBlockChainError(blockchain.ShardCommitteeAndPendingValidatorRootError, errors.New("root")), []peerv2.Behaviour{peerv2.InvalidBlock}},
+		// the node is behind or fails, not the peer
+		{"unknown previous view", 0, blockchain.NewBlockChainError(blockchain.InsertShardBlockError, errors.New("wrong view")), nil},
+		{"missing beacon blocks", 0, blockchain.NewBlockChainError(blockchain.FetchBeaconBlocksError, errors.New("not found")), nil},
+		{"database", 0, blockchain.NewBlockChainError(blockchain.StoreBeaconBlockError, errors.New("disk full")), nil},
+		{"other error", 0, errors.New("statedb"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &testServer{reports: map[string][]peerv2.Behaviour{}}
+			reportBatch(node, "a", tt.successBlk, tt.err)
+			assert.Equal(t, tt.reports, node.reports["a"])
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/peerv2/wrapper"
+	"github.com/incognitochain/incognito-chain/wire"
+)
+
+// bus delivers the messages and block streams of the nodes of a harness in
+// process, it takes the place of the highway connections of peerv2.ConnManager
+type bus struct {
+	mtx     sync.RWMutex
+	nodes   []*Node
+	stopped bool
+}
+
+func (b *bus) add(node *Node) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.nodes = append(b.nodes, node)
+}
+
+func (b *bus) stop() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.stopped = true
+}
+
+// peers returns the nodes other than self which pass the filter, nothing once
+// the bus is stopped
+func (b *bus) peers(self *Node, filter func(*Node) bool) []*Node {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+	if b.stopped {
+		return nil
+	}
+	res := []*Node{}
+	for _, node := range b.nodes {
+		if node == self || (filter != nil && !filter(node)) {
+			continue
+		}
+		res = append(res, node)
+	}
+	return res
+}
+
+// publish sends a copy of msg to every receiver, decoded as peerv2.Dispatcher
+// decodes the messages of the highway
+func (b *bus) publish(sender *Node, msg wire.Message, receivers []*Node) error {
+	data, err := msg.JsonSerialize()
+	if err != nil {
+		return err
+	}
+	for _, node := range receivers {
+		msgCopy, err := wire.MakeEmptyMessage(msg.MessageType())
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &msgCopy); err != nil {
+			return err
+		}
+		if err := msgCopy.SetSenderID(sender.peerID); err != nil {
+			return err
+		}
+		node.receive(msgCopy, sender.peerID.Pretty())
+	}
+	return nil
+}
+
+// chainFilter selects the nodes working on a chain, -1 being the beacon chain
+func chainFilter(chainID int) func(*Node) bool {
+	return func(node *Node) bool {
+		_, nodeChainID := node.GetUserMiningState()
+		return nodeChainID == chainID
+	}
+}
+
+// provider returns the node which serves the blocks of chainID to requester,
+// peerID when it is a known peer, otherwise a node working on the chain
+func (b *bus) provider(requester *Node, peerID string, chainID int) *Node {
+	peers := b.peers(requester, nil)
+	for _, node := range peers {
+		if node.peerID.Pretty() == peerID {
+			return node
+		}
+	}
+	for _, node := range peers {
+		if chainFilter(chainID)(node) {
+			return node
+		}
+	}
+	return nil
+}
+
+// streamByHeight serves req from the netsync of the provider of chainID
+func (b *bus) streamByHeight(ctx context.Context, requester *Node, peerID string, chainID int, req *proto.BlockByHeightRequest) (chan common.BlockInterface, error) {
+	provider := b.provider(requester, peerID, chainID)
+	if provider == nil {
+		return nil, errors.New("no peer to stream blocks from")
+	}
+	blkReq := *req
+	blkType, headerOnly := peerv2.BlockTypeOf(req.Type)
+	blkReq.Type = blkType
+	blkRecv := provider.netSync.StreamBlockByHeight(false, &blkReq)
+	if blkRecv == nil {
+		return nil, errors.New("invalid block by height request")
+	}
+	return copyBlocks(ctx, blkType, headerOnly, blkRecv), nil
+}
+
+// streamByHash serves req from the netsync of the provider of chainID
+func (b *bus) streamByHash(ctx context.Context, requester *Node, peerID string, chainID int, req *proto.BlockByHashRequest) (chan common.BlockInterface, error) {
+	if len(req.Hashes) == 0 {
+		return nil, errors.New("invalid block by hash request")
+	}
+	provider := b.provider(requester, peerID, chainID)
+	if provider == nil {
+		return nil, errors.New("no peer to stream blocks from")
+	}
+	blkRecv := provider.netSync.StreamBlockByHash(false, req)
+	return copyBlocks(ctx, req.Type, false, blkRecv), nil
+}
+
+// copyBlocks encodes the blocks of blkRecv as the block provider does and
+// decodes them for the requester, so the nodes never share a block. The
+// bodies are left out when headerOnly.
+func copyBlocks(ctx context.Context, blkType proto.BlkType, headerOnly bool, blkRecv chan interface{}) chan common.BlockInterface {
+	blockCh := make(chan common.BlockInterface, blockchain.DefaultMaxBlkReqPerPeer)
+	go func() {
+		defer close(blockCh)
+		// drain the provider so its stream goroutine ends
+		defer func() {
+			for range blkRecv {
+			}
+		}()
+		for blk := range blkRecv {
+			if headerOnly {
+				blk = peerv2.BlockHeader(blk)
+			}
+			data, err := wrapper.EnCom(blk)
+			if err != nil {
+				Logger.log.Errorf("[stream] %v", err)
+				return
+			}
+			var newBlk common.BlockInterface = new(blockchain.BeaconBlock)
+			if blkType == proto.BlkType_BlkShard {
+				newBlk = new(blockchain.ShardBlock)
+			} else if blkType == proto.BlkType_BlkS2B {
+				newBlk = new(blockchain.ShardToBeaconBlock)
+			} else if blkType == proto.BlkType_BlkXShard {
+				newBlk = new(blockchain.CrossShardBlock)
+			}
+			if err := wrapper.DeCom(data, newBlk); err != nil {
+				Logger.log.Errorf("[stream] %v", err)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case blockCh <- newBlk:
+			}
+		}
+	}()
+	return blockCh
+}
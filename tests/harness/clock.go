@@ -0,0 +1,47 @@
+package harness
+
+import (
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// Clock is the time of every node of a harness. It follows the wall clock
+// shifted by an offset which only moves forward, so a test can jump to the
+// next time slot instead of waiting for it.
+type Clock struct {
+	mtx    sync.RWMutex
+	offset time.Duration
+}
+
+// Now returns the current time of the harness
+func (clock *Clock) Now() time.Time {
+	clock.mtx.RLock()
+	defer clock.mtx.RUnlock()
+	return time.Now().Add(clock.offset)
+}
+
+// Advance moves the clock forward by d
+func (clock *Clock) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	clock.mtx.Lock()
+	defer clock.mtx.Unlock()
+	clock.offset += d
+}
+
+// NextTimeSlot moves the clock to the beginning of the next consensus time slot
+// and returns it
+func (clock *Clock) NextTimeSlot() int64 {
+	now := clock.Now().Unix()
+	next := common.CalculateTimeSlot(now) + 1
+	clock.Advance(time.Duration(next*common.TIMESLOT-now) * time.Second)
+	return next
+}
+
+// TimeSlot returns the current consensus time slot
+func (clock *Clock) TimeSlot() int64 {
+	return common.CalculateTimeSlot(clock.Now().Unix())
+}
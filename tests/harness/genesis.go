@@ -0,0 +1,190 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/forks"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+const (
+	harnessNetName = "harness"
+	harnessNet     = 0x65
+)
+
+// Account is a key generated by the harness, validators and funded accounts
+// receive Config.InitAmount PRV in the genesis block
+type Account struct {
+	PrivateKey         string
+	PaymentAddress     string
+	CommitteePublicKey string
+	KeyWallet          *wallet.KeyWallet
+}
+
+// ShardID returns the shard of the account payment address
+func (account *Account) ShardID() byte {
+	pk := account.KeyWallet.KeySet.PaymentAddress.Pk
+	return common.GetShardIDFromLastByte(pk[len(pk)-1])
+}
+
+func newAccount(key *wallet.KeyWallet) (*Account, error) {
+	committeeKey, err := incognitokey.NewCommitteeKeyFromSeed(common.HashB(common.HashB(key.KeySet.PrivateKey)), key.KeySet.PaymentAddress.Pk)
+	if err != nil {
+		return nil, err
+	}
+	committeeKeyStr, err := incognitokey.CommitteeKeyListToString([]incognitokey.CommitteePublicKey{committeeKey})
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		PrivateKey:         key.Base58CheckSerialize(wallet.PriKeyType),
+		PaymentAddress:     key.Base58CheckSerialize(wallet.PaymentAddressType),
+		CommitteePublicKey: committeeKeyStr[0],
+		KeyWallet:          key,
+	}, nil
+}
+
+// genesis holds the keys of a harness network and its chain params
+type genesis struct {
+	beacon   []*Account
+	shard    map[int][]*Account
+	accounts []*Account
+	params   *blockchain.Params
+}
+
+// newGenesis derives the keys of the network from the seed of config, the same
+// config always gives the same keys and genesis txs
+func newGenesis(config *Config, genesisTime time.Time) (*genesis, error) {
+	masterKey, err := wallet.NewMasterKey([]byte(config.Seed))
+	if err != nil {
+		return nil, err
+	}
+	childIdx := uint32(0)
+	// next derives the next key whose payment address is in an active shard
+	next := func() (*Account, error) {
+		for {
+			child, err := masterKey.NewChildKey(childIdx)
+			if err != nil {
+				return nil, err
+			}
+			childIdx++
+			account, err := newAccount(child)
+			if err != nil {
+				return nil, err
+			}
+			if int(account.ShardID()) < config.NumShards {
+				return account, nil
+			}
+		}
+	}
+
+	g := &genesis{shard: map[int][]*Account{}}
+	funded := []*Account{}
+	for i := 0; i < config.NumBeacon; i++ {
+		account, err := next()
+		if err != nil {
+			return nil, err
+		}
+		g.beacon = append(g.beacon, account)
+		funded = append(funded, account)
+	}
+	for shardID := 0; shardID < config.NumShards; shardID++ {
+		for i := 0; i < config.ShardCommitteeSize; i++ {
+			account, err := next()
+			if err != nil {
+				return nil, err
+			}
+			g.shard[shardID] = append(g.shard[shardID], account)
+			funded = append(funded, account)
+		}
+	}
+	for i := 0; i < config.NumAccounts; i++ {
+		account, err := next()
+		if err != nil {
+			return nil, err
+		}
+		g.accounts = append(g.accounts, account)
+		funded = append(funded, account)
+	}
+
+	initTxs, err := buildInitTxs(funded, config.InitAmount)
+	if err != nil {
+		return nil, err
+	}
+	chainParamsFile := blockchain.ChainParamsFile{
+		Name:                   harnessNetName,
+		Net:                    harnessNet,
+		GenesisBlockTime:       genesisTime.UTC().Format("2006-01-02T15:04:05.000Z"),
+		ActiveShards:           config.NumShards,
+		Epoch:                  config.Epoch,
+		RandomTime:             config.Epoch / 2,
+		MinBeaconCommitteeSize: config.NumBeacon,
+		MaxBeaconCommitteeSize: config.NumBeacon,
+		MinShardCommitteeSize:  config.ShardCommitteeSize,
+		MaxShardCommitteeSize:  config.ShardCommitteeSize,
+		BeaconBlockInterval:    fmt.Sprintf("%ds", common.TIMESLOT),
+		ShardBlockInterval:     fmt.Sprintf("%ds", common.TIMESLOT),
+		Shard:                  map[int][]blockchain.GenesisValidator{},
+		InitialIncognito:       initTxs,
+		Forks: forks.Schedule{
+			forks.BurnAddressV2:               {Height: 1},
+			forks.MinTxFeesOnTokenRequirement: {Height: 1},
+			forks.ReplaceStakingTx:            {Height: 1},
+			forks.StakingTxRoot:               {Height: 2},
+			forks.FixRandShardCommitment:      {Height: 1},
+			forks.ConsensusV2:                 {Epoch: 1},
+			forks.TimeLock:                    {Height: 1},
+		},
+	}
+	for _, account := range g.beacon {
+		chainParamsFile.Beacon = append(chainParamsFile.Beacon, blockchain.GenesisValidator{PaymentAddress: account.PaymentAddress, CommitteePublicKey: account.CommitteePublicKey})
+	}
+	for shardID, accounts := range g.shard {
+		for _, account := range accounts {
+			chainParamsFile.Shard[shardID] = append(chainParamsFile.Shard[shardID], blockchain.GenesisValidator{PaymentAddress: account.PaymentAddress, CommitteePublicKey: account.CommitteePublicKey})
+		}
+	}
+	if g.params, err = chainParamsFile.ToParams(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// buildInitTxs creates the serialized txs of the genesis block giving amount PRV
+// to every account, a tx is signed by its receiver
+func buildInitTxs(accounts []*Account, amount uint64) ([]string, error) {
+	if amount == 0 {
+		return []string{}, nil
+	}
+	db, err := incdb.Open("memdb")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	stateDB, err := statedb.NewWithPrefixTrie(common.EmptyRoot, statedb.NewDatabaseAccessWarper(db))
+	if err != nil {
+		return nil, err
+	}
+	initTxs := []string{}
+	for _, account := range accounts {
+		keySet := &account.KeyWallet.KeySet
+		tx := transaction.Tx{}
+		if err := tx.InitTxSalary(amount, &keySet.PaymentAddress, &keySet.PrivateKey, stateDB, nil); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(tx)
+		if err != nil {
+			return nil, err
+		}
+		initTxs = append(initTxs, string(data))
+	}
+	return initTxs, nil
+}
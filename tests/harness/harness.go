@@ -0,0 +1,247 @@
+// Package harness runs a whole network, a beacon committee and several shard
+// committees, in one process. Every node runs the blockchain, mempool, syncker,
+// consensus engine and optionally rpcserver of a real node on top of in-memory
+// databases; the nodes talk over an in-process message bus instead of the
+// highway, and the consensus reads its time slots from a clock the test controls.
+package harness
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/wire"
+)
+
+const (
+	defaultTxPoolTTL     = uint(86400)
+	defaultTxPoolMaxTx   = uint64(20000)
+	defaultRPCMaxClients = 20
+)
+
+// Config describes the network of a harness, zero fields take the value of DefaultConfig
+type Config struct {
+	NumBeacon          int    // size of the beacon committee
+	NumShards          int    // number of active shards
+	ShardCommitteeSize int    // size of every shard committee, at least blockchain.NumberOfFixedBlockValidators
+	NumAccounts        int    // funded accounts which are not validators
+	InitAmount         uint64 // PRV given to every validator and account in the genesis block
+	Epoch              uint64
+	Seed               string // seed of the keys, the same seed always gives the same keys
+	RPC                bool   // start a rpc server on every node
+
+	// SlotWait is how long WaitForHeight lets the nodes work on a time slot
+	// before it moves the clock to the next one
+	SlotWait time.Duration
+}
+
+// DefaultConfig is a small network: one beacon node and two shards of four nodes
+var DefaultConfig = Config{
+	NumBeacon:          1,
+	NumShards:          2,
+	ShardCommitteeSize: blockchain.NumberOfFixedBlockValidators,
+	NumAccounts:        2,
+	InitAmount:         1000000000000000,
+	Epoch:              100,
+	Seed:               "incognito harness",
+	SlotWait:           5 * time.Second,
+}
+
+func (config *Config) setDefaults() {
+	if config.NumBeacon == 0 {
+		config.NumBeacon = DefaultConfig.NumBeacon
+	}
+	if config.NumShards == 0 {
+		config.NumShards = DefaultConfig.NumShards
+	}
+	if config.ShardCommitteeSize == 0 {
+		config.ShardCommitteeSize = DefaultConfig.ShardCommitteeSize
+	}
+	if config.NumAccounts == 0 {
+		config.NumAccounts = DefaultConfig.NumAccounts
+	}
+	if config.InitAmount == 0 {
+		config.InitAmount = DefaultConfig.InitAmount
+	}
+	if config.Epoch == 0 {
+		config.Epoch = DefaultConfig.Epoch
+	}
+	if config.Seed == "" {
+		config.Seed = DefaultConfig.Seed
+	}
+	if config.SlotWait == 0 {
+		config.SlotWait = DefaultConfig.SlotWait
+	}
+}
+
+// Harness is a network of nodes running in the current process
+type Harness struct {
+	config  Config
+	clock   *Clock
+	genesis *genesis
+	bus     *bus
+
+	beacon []*Node
+	shard  map[int][]*Node
+	nodes  []*Node
+}
+
+// New creates the nodes of the network described by config, they are started by Start
+func New(config Config) (*Harness, error) {
+	config.setDefaults()
+	if config.ShardCommitteeSize < blockchain.NumberOfFixedBlockValidators {
+		return nil, fmt.Errorf("shard committee size must be at least %d, got %d", blockchain.NumberOfFixedBlockValidators, config.ShardCommitteeSize)
+	}
+	h := &Harness{
+		config: config,
+		clock:  &Clock{},
+		bus:    &bus{},
+		shard:  map[int][]*Node{},
+	}
+	var err error
+	// the genesis block is one time slot old so the first block can be proposed at once
+	h.genesis, err = newGenesis(&h.config, h.clock.Now().Add(-common.TIMESLOT*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	for i, account := range h.genesis.beacon {
+		node, err := newNode(fmt.Sprintf("beacon-%d", i), account, h.genesis, h.bus, h.clock, config.RPC)
+		if err != nil {
+			return nil, err
+		}
+		h.beacon = append(h.beacon, node)
+	}
+	for shardID := 0; shardID < config.NumShards; shardID++ {
+		for i, account := range h.genesis.shard[shardID] {
+			node, err := newNode(fmt.Sprintf("shard%d-%d", shardID, i), account, h.genesis, h.bus, h.clock, config.RPC)
+			if err != nil {
+				return nil, err
+			}
+			h.shard[shardID] = append(h.shard[shardID], node)
+		}
+	}
+	h.nodes = append(h.nodes, h.beacon...)
+	for shardID := 0; shardID < config.NumShards; shardID++ {
+		h.nodes = append(h.nodes, h.shard[shardID]...)
+	}
+	for _, node := range h.nodes {
+		h.bus.add(node)
+	}
+	return h, nil
+}
+
+// Start starts every node
+func (h *Harness) Start() error {
+	for _, node := range h.nodes {
+		if err := node.start(); err != nil {
+			h.Stop()
+			return fmt.Errorf("start %v: %v", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// Stop disconnects and stops every node
+func (h *Harness) Stop() {
+	h.bus.stop()
+	for _, node := range h.nodes {
+		node.stop()
+	}
+}
+
+// Clock returns the clock of the consensus of the nodes
+func (h *Harness) Clock() *Clock {
+	return h.clock
+}
+
+// Nodes returns every node, beacon nodes first
+func (h *Harness) Nodes() []*Node {
+	return h.nodes
+}
+
+// Beacon returns the i-th node of the beacon committee
+func (h *Harness) Beacon(i int) *Node {
+	return h.beacon[i]
+}
+
+// Shard returns the i-th node of the committee of shardID
+func (h *Harness) Shard(shardID int, i int) *Node {
+	return h.shard[shardID][i]
+}
+
+// Accounts returns the funded accounts which are not validators
+func (h *Harness) Accounts() []*Account {
+	return h.genesis.accounts
+}
+
+// chainNodes returns the nodes which produce the blocks of chainID, -1 being the beacon chain
+func (h *Harness) chainNodes(chainID int) []*Node {
+	if chainID == -1 {
+		return h.beacon
+	}
+	return h.shard[chainID]
+}
+
+// minHeight returns the lowest best height of chainID among the nodes of its committee
+func (h *Harness) minHeight(chainID int) uint64 {
+	var height uint64
+	for i, node := range h.chainNodes(chainID) {
+		if nodeHeight := node.Height(chainID); i == 0 || nodeHeight < height {
+			height = nodeHeight
+		}
+	}
+	return height
+}
+
+// WaitForHeight drives the clock slot after slot until every node of the
+// committee of chainID reaches height, -1 being the beacon chain. The clock moves
+// to the next slot as soon as a block is added or after Config.SlotWait.
+func (h *Harness) WaitForHeight(chainID int, height uint64, timeout time.Duration) error {
+	if chainID < -1 || chainID >= h.config.NumShards {
+		return fmt.Errorf("chain %d is not in the harness", chainID)
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		current := h.minHeight(chainID)
+		if current >= height {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("chain %d is at height %d after %v, want %d", chainID, current, timeout, height)
+		}
+		h.clock.NextTimeSlot()
+		slotEnd := time.Now().Add(h.config.SlotWait)
+		for time.Now().Before(slotEnd) && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+			if h.minHeight(chainID) > current {
+				break
+			}
+		}
+	}
+}
+
+// SubmitTx adds tx to the mempool of a node of the shard of its sender and
+// broadcasts it, as netsync does for a tx received from a peer
+func (h *Harness) SubmitTx(tx metadata.Transaction) error {
+	shardID := int(common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()))
+	nodes := h.shard[shardID]
+	if len(nodes) == 0 {
+		return fmt.Errorf("shard %d is not in the harness", shardID)
+	}
+	node := nodes[0]
+	beaconHeight := int64(node.blockChain.GetBeaconBestState().BeaconHeight)
+	if _, _, err := node.memPool.MaybeAcceptTransaction(tx, beaconHeight); err != nil {
+		return err
+	}
+	var msg wire.Message = &wire.MessageTx{Transaction: tx}
+	if tx.GetType() == common.TxCustomTokenPrivacyType {
+		msg = &wire.MessageTxPrivacyToken{Transaction: tx}
+	}
+	if err := node.PushMessageToAll(msg); err != nil {
+		return err
+	}
+	node.memPool.MarkForwardedTransaction(*tx.Hash())
+	return nil
+}
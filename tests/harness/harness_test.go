@@ -0,0 +1,448 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/light"
+	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarnessProducesBlocks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping harness test in short mode")
+	}
+	h, err := New(Config{RPC: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+
+	assert.Nil(t, h.WaitForHeight(-1, 3, 2*time.Minute))
+	assert.Nil(t, h.WaitForHeight(0, 3, 2*time.Minute))
+	assert.Nil(t, h.WaitForHeight(1, 3, 2*time.Minute))
+
+	res, err := h.Beacon(0).RPC("getbeaconbeststate")
+	if !assert.Nil(t, err) {
+		return
+	}
+	bestState := struct{ BeaconHeight uint64 }{}
+	assert.Nil(t, json.Unmarshal(res, &bestState))
+	assert.True(t, bestState.BeaconHeight >= 3)
+}
+
+func TestHarnessTransfersPRV(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping harness test in short mode")
+	}
+	h, err := New(Config{RPC: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+	sender, receiver := h.Accounts()[0], h.Accounts()[1]
+	// the producer drops the txs it gets on top of the genesis block, which has no beacon block
+	if err := h.WaitForHeight(int(sender.ShardID()), 2, 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	senderNode := h.Shard(int(sender.ShardID()), 0)
+	receiverNode := h.Shard(int(receiver.ShardID()), 0)
+	_, err = senderNode.RPC("createandsendtransaction", sender.PrivateKey, map[string]uint64{receiver.PaymentAddress: 1000}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a cross shard transfer reaches the receiver once the beacon confirms the
+	// cross shard block, syncker watches the confirmations on the wall clock
+	receiverShard := int(receiver.ShardID())
+	deadline := time.Now().Add(time.Minute)
+	for height := receiverNode.Height(receiverShard) + 1; time.Now().Before(deadline); height++ {
+		if err := h.WaitForHeight(receiverShard, height, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		res, err := receiverNode.RPC("getbalancebyprivatekey", receiver.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		balance := uint64(0)
+		assert.Nil(t, json.Unmarshal(res, &balance))
+		if balance == DefaultConfig.InitAmount+1000 {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatal("receiver did not get the transfer")
+}
+
+func TestHarnessLightClient(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping harness test in short mode")
+	}
+	h, err := New(Config{RPC: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+	sender, receiver := h.Accounts()[0], h.Accounts()[1]
+	if err := h.WaitForHeight(int(sender.ShardID()), 2, 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	senderNode := h.Shard(int(sender.ShardID()), 0)
+	res, err := senderNode.RPC("createandsendtransaction", sender.PrivateKey, map[string]uint64{receiver.PaymentAddress: 1000}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := struct{ TxID string }{}
+	if err := json.Unmarshal(res, &sent); err != nil {
+		t.Fatal(err)
+	}
+	txHash, err := common.Hash{}.NewHashFromStr(sent.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the light client trusts the genesis block of the node it asks, which
+	// keeps the beacon chain and the chain of the shard of the transaction
+	ctx := context.Background()
+	source := light.NewRPCSource("http://" + senderNode.RPCAddress())
+	genesis, err := source.BeaconHeaders(ctx, 1, 1)
+	if err != nil || len(genesis) != 1 {
+		t.Fatal("no genesis header", err)
+	}
+	lists, err := source.Committees(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := light.NewClient(&light.Checkpoint{Header: genesis[0], Committees: lists}, source, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the transaction is verified once the beacon block its shard block
+	// refers to is final
+	deadline := time.Now().Add(2 * time.Minute)
+	for time.Now().Before(deadline) {
+		if err := client.SyncBeacon(ctx); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := client.VerifyTx(ctx, *txHash)
+		if err == nil {
+			assert.Equal(t, sender.ShardID(), tx.ShardID)
+			assert.Equal(t, *txHash, *tx.Tx.Hash())
+			assert.True(t, client.Status().BeaconHeight > 1)
+			return
+		}
+		t.Log(err)
+		time.Sleep(time.Second)
+	}
+	t.Fatal("light client did not verify the transaction")
+}
+
+func TestHarnessTimeLockedTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping harness test in short mode")
+	}
+	h, err := New(Config{RPC: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+	sender, receiver := h.Accounts()[0], h.Accounts()[1]
+	senderShard := int(sender.ShardID())
+	senderNode := h.Shard(senderShard, 0)
+	receiverNode := h.Shard(int(receiver.ShardID()), 0)
+	// the window is checked against the beacon height of the best shard block
+	beaconHeight := func() uint64 {
+		return senderNode.BlockChain().GetBestStateShard(sender.ShardID()).BeaconHeight
+	}
+	for height := uint64(2); beaconHeight() < 2; height++ {
+		if err := h.WaitForHeight(senderShard, height, 2*time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	receivers := map[string]uint64{receiver.PaymentAddress: 1000}
+	_, err = senderNode.RPC("createandsendtimelockedtransaction", sender.PrivateKey, receivers, 10, 0, map[string]uint64{"NotAfter": 1})
+	assert.NotNil(t, err, "an expired tx is rejected")
+
+	notBefore := beaconHeight() + 3
+	res, err := senderNode.RPC("createandsendtimelockedtransaction", sender.PrivateKey, receivers, 10, 0, map[string]uint64{"NotBefore": notBefore})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := struct{ TxID string }{}
+	assert.Nil(t, json.Unmarshal(res, &sent))
+	res, err = senderNode.RPC("getheldtransactions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := []struct {
+		TxID      string
+		NotBefore uint64
+	}{}
+	assert.Nil(t, json.Unmarshal(res, &held))
+	if assert.Len(t, held, 1) {
+		assert.Equal(t, sent.TxID, held[0].TxID)
+		assert.Equal(t, notBefore, held[0].NotBefore)
+	}
+
+	deadline := time.Now().Add(2 * time.Minute)
+	for time.Now().Before(deadline) {
+		res, err := receiverNode.RPC("getbalancebyprivatekey", receiver.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		balance := uint64(0)
+		assert.Nil(t, json.Unmarshal(res, &balance))
+		if balance == DefaultConfig.InitAmount+1000 {
+			assert.True(t, beaconHeight() >= notBefore)
+			assert.Empty(t, senderNode.TxPool().HeldTxs())
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatal("receiver did not get the time-locked transfer")
+}
+
+// newMultiSigShares runs a threshold-of-n key generation and returns the
+// shares of its participants, the payment address of the group is in shardID
+func newMultiSigShares(t *testing.T, threshold int, n int, shardID byte) []*multisig.KeyShare {
+	for {
+		receivingKeys := make([][]byte, n)
+		transmissionKeys := make([][]byte, n)
+		for i := range receivingKeys {
+			receivingKeys[i] = privacy.GenerateReceivingKey(privacy.GeneratePrivateKey(privacy.RandomScalar().ToBytesS()))
+			transmissionKeys[i] = privacy.GenerateTransmissionKey(receivingKeys[i])
+		}
+		session, err := multisig.NewKeyGenSession(threshold, transmissionKeys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets := make([]*multisig.KeyGenSecret, n)
+		for i := range secrets {
+			var round1 *multisig.KeyGenRound1
+			secrets[i], round1, err = session.NewRound1(uint32(i + 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := session.AddRound1(round1); err != nil {
+				t.Fatal(err)
+			}
+		}
+		group, err := session.GroupKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group.ShardID() != shardID {
+			continue
+		}
+		shares := make([]*multisig.KeyShare, n)
+		for i := range shares {
+			if shares[i], err = session.FinishKeyGen(secrets[i], receivingKeys[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return shares
+	}
+}
+
+func TestHarnessMultiSigTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping harness test in short mode")
+	}
+	h, err := New(Config{RPC: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+	sender, receiver := h.Accounts()[0], h.Accounts()[1]
+	senderShard := int(sender.ShardID())
+	if err := h.WaitForHeight(senderShard, 2, 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	senderNode := h.Shard(senderShard, 0)
+	receiverNode := h.Shard(int(receiver.ShardID()), 0)
+
+	shares := newMultiSigShares(t, 2, 3, sender.ShardID())
+	group := wallet.KeyWallet{}
+	group.KeySet.PaymentAddress = shares[0].PaymentAddress()
+	group.KeySet.ReadonlyKey = shares[0].ReadonlyKey()
+	_, err = senderNode.RPC("createandsendtransaction", sender.PrivateKey, map[string]uint64{group.Base58CheckSerialize(wallet.PaymentAddressType): 5000}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the group has no coin to spend until the funding tx is in a block
+	var res json.RawMessage
+	deadline := time.Now().Add(2 * time.Minute)
+	for time.Now().Before(deadline) {
+		res, err = senderNode.RPC("createmultisigtransaction", shares[0].GroupKey, group.Base58CheckSerialize(wallet.ReadonlyKeyType), map[string]uint64{receiver.PaymentAddress: 1000}, 10, []uint32{3, 1})
+		if err == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	multiSigTx := new(transaction.MultiSigTx)
+	if err := json.Unmarshal(res, multiSigTx); err != nil {
+		t.Fatal(err)
+	}
+
+	// every signer works on its own copy of the tx, as with the exchange files
+	signers := []*multisig.KeyShare{shares[2], shares[0]}
+	states := []*multisig.SignerState{new(multisig.SignerState), new(multisig.SignerState)}
+	for !multiSigTx.Session.IsComplete() {
+		data, err := json.Marshal(multiSigTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, share := range signers {
+			signerTx := new(transaction.MultiSigTx)
+			if err := json.Unmarshal(data, signerTx); err != nil {
+				t.Fatal(err)
+			}
+			msg, err := signerTx.Sign(share, states[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := multiSigTx.AddMessage(msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tx, err := multiSigTx.SignedTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the signed tx goes through the checks of any other tx
+	if _, err := senderNode.RPC("sendtransaction", base58.Base58Check{}.Encode(txBytes, common.ZeroByte)); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline = time.Now().Add(2 * time.Minute)
+	for time.Now().Before(deadline) {
+		res, err := receiverNode.RPC("getbalancebyprivatekey", receiver.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		balance := uint64(0)
+		assert.Nil(t, json.Unmarshal(res, &balance))
+		if balance == DefaultConfig.InitAmount+1000 {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatal("receiver did not get the multisig transfer")
+}
+
+func TestHarnessDiscoverAccounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping harness test in short mode")
+	}
+	h, err := New(Config{RPC: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+	sender := h.Accounts()[0]
+	senderShard := int(sender.ShardID())
+	if err := h.WaitForHeight(senderShard, 2, 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	senderNode := h.Shard(senderShard, 0)
+
+	dir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config := &wallet.WalletConfig{DataDir: dir, DataFile: "wallet", DataPath: filepath.Join(dir, "wallet")}
+	w := new(wallet.Wallet)
+	w.SetConfig(config)
+	if err := w.Init("12345678", 1, "harness"); err != nil {
+		t.Fatal(err)
+	}
+
+	// an address of the accounts 0 and 1 in the shard of the sender, the
+	// indexes before are unused
+	receivers := map[string]uint64{}
+	paths := []string{}
+	for account := uint32(0); account < 2; account++ {
+		for index := uint32(0); ; index++ {
+			path := wallet.NewAccountPath(account, index)
+			key, err := w.MasterAccount.Key.DerivePath(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int(key.ShardID()) == senderShard {
+				receivers[key.Base58CheckSerialize(wallet.PaymentAddressType)] = 1000
+				paths = append(paths, path.String())
+				break
+			}
+		}
+	}
+	if _, err := senderNode.RPC("createandsendtransaction", sender.PrivateKey, receivers, 10, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// a wallet restored from the mnemonic finds the accounts once the tx is in a block
+	var found []string
+	deadline := time.Now().Add(2 * time.Minute)
+	for time.Now().Before(deadline) {
+		restored := new(wallet.Wallet)
+		restored.SetConfig(config)
+		if err := restored.InitFromMnemonic(w.Mnemonic, "12345678", "restored"); err != nil {
+			t.Fatal(err)
+		}
+		walletService := rpcservice.WalletService{Wallet: restored, BlockChain: senderNode.BlockChain()}
+		accounts, rpcErr := walletService.DiscoverAccounts(0, "12345678")
+		if rpcErr != nil {
+			t.Fatal(rpcErr)
+		}
+		found = []string{}
+		for _, account := range accounts {
+			found = append(found, account.Path)
+		}
+		if len(found) == len(paths) {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	assert.Equal(t, paths, found)
+}
@@ -0,0 +1,88 @@
+package harness
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/blockchain/btc"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus"
+	"github.com/incognitochain/incognito-chain/dataaccessobject"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/light"
+	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/netsync"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/incognitochain/incognito-chain/peerv2/wrapper"
+	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/incognitochain/incognito-chain/rpcserver"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+	"github.com/incognitochain/incognito-chain/syncker"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/incognitochain/incognito-chain/trie"
+	"github.com/incognitochain/incognito-chain/wallet"
+)
+
+// logWriter forwards the logs of every node to the output set by SetLogOutput,
+// the subsystem loggers are package globals so all nodes share them
+type logWriter struct {
+	mtx sync.RWMutex
+	w   io.Writer
+}
+
+func (writer *logWriter) Write(p []byte) (int, error) {
+	writer.mtx.RLock()
+	defer writer.mtx.RUnlock()
+	return writer.w.Write(p)
+}
+
+type HarnessLogger struct {
+	log common.Logger
+}
+
+func (harnessLogger *HarnessLogger) Init(inst common.Logger) {
+	harnessLogger.log = inst
+}
+
+// Global instant to use
+var Logger = HarnessLogger{}
+
+var (
+	logOutput  = &logWriter{w: ioutil.Discard}
+	backendLog = common.NewBackend(logOutput)
+)
+
+// SetLogOutput sends the logs of the nodes to w, they are discarded by default
+func SetLogOutput(w io.Writer) {
+	logOutput.mtx.Lock()
+	defer logOutput.mtx.Unlock()
+	logOutput.w = w
+}
+
+func init() {
+	Logger.Init(backendLog.Logger("Harness log", false))
+	rpcserver.Logger.Init(backendLog.Logger("RPC log", false))
+	rpcserver.BLogger.Init(backendLog.Logger("DeBridge log", false))
+	rpcservice.Logger.Init(backendLog.Logger("RPC service log", false))
+	rpcservice.BLogger.Init(backendLog.Logger("RPC service DeBridge log", false))
+	netsync.Logger.Init(backendLog.Logger("Netsync log", false))
+	incdb.Logger.Init(backendLog.Logger("Database log", false))
+	wallet.Logger.Init(backendLog.Logger("Wallet log", false))
+	blockchain.Logger.Init(backendLog.Logger("BlockChain log", false))
+	blockchain.BLogger.Init(backendLog.Logger("DeBridge log", false))
+	consensus.Logger.Init(backendLog.Logger("Consensus log", false))
+	mempool.Logger.Init(backendLog.Logger("Mempool log", false))
+	btc.Logger.Init(backendLog.Logger("RandomAPI log", false))
+	transaction.Logger.Init(backendLog.Logger("Transaction log", false))
+	privacy.Logger.Init(backendLog.Logger("Privacy log", false))
+	metadata.Logger.Init(backendLog.Logger("Metadata log", false))
+	trie.Logger.Init(backendLog.Logger("Trie log", false))
+	wrapper.Logger.Init(backendLog.Logger("Wrapper log", false))
+	dataaccessobject.Logger.Init(backendLog.Logger("DAO log", false))
+	syncker.Logger.Init(backendLog.Logger("Syncker log", false))
+	peerv2.Logger.Init(backendLog.Logger("Peerv2 log", false))
+	light.Logger.Init(backendLog.Logger("Light client log", false))
+}
@@ -0,0 +1,778 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/memdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/memcache"
+	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/netsync"
+	"github.com/incognitochain/incognito-chain/peerv2"
+	"github.com/incognitochain/incognito-chain/peerv2/proto"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"github.com/incognitochain/incognito-chain/rpcserver"
+	"github.com/incognitochain/incognito-chain/syncker"
+	"github.com/incognitochain/incognito-chain/wire"
+	libp2p "github.com/libp2p/go-libp2p-peer"
+)
+
+// Node is a full node of a harness: blockchain, mempool, syncker, consensus
+// engine and optionally rpcserver, wired as in server.go on top of in-memory
+// databases and the message bus of the harness
+type Node struct {
+	Name    string
+	Account *Account
+
+	peerID      libp2p.ID
+	bus         *bus
+	chainParams *blockchain.Params
+
+	dataBase        map[int]incdb.Database
+	blockChain      *blockchain.BlockChain
+	memPool         *mempool.TxPool
+	tempMemPool     *mempool.TxPool
+	blockgen        *blockchain.BlockGenerator
+	syncker         *syncker.SynckerManager
+	netSync         *netsync.NetSync
+	consensusEngine *consensus.Engine
+	pubSubManager   *pubsub.PubSubManager
+	rpcServer       *rpcserver.RpcServer
+	rpcListener     net.Listener
+	reputation      *peerv2.Reputation
+
+	isEnableMining bool
+	stopOnce       sync.Once
+	cQuit          chan struct{}
+}
+
+func newNode(name string, account *Account, g *genesis, b *bus, clock common.Clock, enableRPC bool) (*Node, error) {
+	node := &Node{
+		Name:           name,
+		Account:        account,
+		peerID:         libp2p.ID(name),
+		bus:            b,
+		chainParams:    g.params,
+		isEnableMining: true,
+		reputation:     peerv2.NewReputation(),
+		cQuit:          make(chan struct{}),
+	}
+	var err error
+	node.dataBase, err = incdb.OpenMultipleDB("memdb", "")
+	if err != nil {
+		return nil, err
+	}
+
+	cPendingTxs := make(chan metadata.Transaction, 500)
+	cRemovedTxs := make(chan metadata.Transaction, 500)
+	node.blockChain = new(blockchain.BlockChain)
+	node.memPool = &mempool.TxPool{}
+	node.consensusEngine = consensus.NewConsensusEngine()
+	node.syncker = syncker.NewSynckerManager()
+	node.pubSubManager = pubsub.NewPubSubManager()
+	node.blockgen, err = blockchain.NewBlockGenerator(node.memPool, node.blockChain, node.syncker, cPendingTxs, cRemovedTxs)
+	if err != nil {
+		return nil, err
+	}
+	err = node.blockChain.Init(&blockchain.Config{
+		ChainParams:     g.params,
+		DataBase:        node.dataBase,
+		MemCache:        memcache.New(),
+		BlockGen:        node.blockgen,
+		Server:          node,
+		Syncker:         node.syncker,
+		NodeMode:        common.NodeModeAuto,
+		FeeEstimator:    make(map[byte]blockchain.FeeEstimator),
+		PubSubManager:   node.pubSubManager,
+		ConsensusEngine: node.consensusEngine,
+		Highway:         node,
+		GenesisParams:   g.params.GenesisParams,
+	})
+	if err != nil {
+		return nil, err
+	}
+	node.blockChain.InitChannelBlockchain(cRemovedTxs)
+
+	feeEstimator := make(map[byte]*mempool.FeeEstimator)
+	for shardID := range node.blockChain.ShardChain {
+		feeEstimator[byte(shardID)] = mempool.NewFeeEstimator(
+			mempool.DefaultEstimateFeeMaxRollback,
+			mempool.DefaultEstimateFeeMinRegisteredBlocks,
+			0)
+		node.blockChain.SetFeeEstimator(feeEstimator[byte(shardID)], byte(shardID))
+	}
+	node.memPool.Init(&mempool.Config{
+		BlockChain:    node.blockChain,
+		DataBase:      node.dataBase,
+		ChainParams:   g.params,
+		FeeEstimator:  feeEstimator,
+		TxLifeTime:    defaultTxPoolTTL,
+		MaxTx:         defaultTxPoolMaxTx,
+		PubSubManager: node.pubSubManager,
+	})
+	node.blockChain.AddTxPool(node.memPool)
+	node.memPool.InitChannelMempool(cPendingTxs, cRemovedTxs)
+	node.tempMemPool = &mempool.TxPool{}
+	node.tempMemPool.Init(&mempool.Config{
+		BlockChain:    node.blockChain,
+		DataBase:      node.dataBase,
+		ChainParams:   g.params,
+		FeeEstimator:  feeEstimator,
+		MaxTx:         defaultTxPoolMaxTx,
+		PubSubManager: node.pubSubManager,
+	})
+	node.blockChain.AddTempTxPool(node.tempMemPool)
+
+	node.netSync = &netsync.NetSync{}
+	node.netSync.Init(&netsync.NetSyncConfig{
+		Syncker:          node.syncker,
+		BlockChain:       node.blockChain,
+		ChainParam:       g.params,
+		TxMemPool:        node.memPool,
+		Server:           node,
+		Consensus:        node.consensusEngine,
+		PubSubManager:    node.pubSubManager,
+		RoleInCommittees: -1,
+	})
+	node.consensusEngine.Init(&consensus.EngineConfig{Node: node, Blockchain: node.blockChain, PubSubManager: node.pubSubManager, Clock: clock})
+	node.syncker.Init(&syncker.SynckerManagerConfig{Node: node, Blockchain: node.blockChain})
+
+	if enableRPC {
+		node.rpcListener, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		node.rpcServer = &rpcserver.RpcServer{}
+		node.rpcServer.Init(&rpcserver.RpcServerConfig{
+			HttpListenters:  []net.Listener{node.rpcListener},
+			RPCMaxClients:   defaultRPCMaxClients,
+			ChainParams:     g.params,
+			BlockChain:      node.blockChain,
+			Blockgen:        node.blockgen,
+			TxMemPool:       node.memPool,
+			Server:          node,
+			DisableAuth:     true,
+			NodeMode:        common.NodeModeAuto,
+			FeeEstimator:    feeEstimator,
+			Database:        node.dataBase,
+			NetSync:         node.netSync,
+			PubSubManager:   node.pubSubManager,
+			ConsensusEngine: node.consensusEngine,
+			MemCache:        memcache.New(),
+			Syncker:         node.syncker,
+		})
+	}
+	return node, nil
+}
+
+// start runs the node as Server.Start does
+func (node *Node) start() error {
+	if err := node.netSync.Start(); err != nil {
+		return err
+	}
+	if node.rpcServer != nil {
+		node.rpcServer.Start()
+	}
+	node.memPool.IsBlockGenStarted = true
+	node.blockChain.SetIsBlockGenStarted(true)
+	go node.tempMemPool.Start(node.cQuit)
+	go node.syncker.Start()
+	go node.blockgen.Start(node.cQuit)
+	go node.memPool.Start(node.cQuit)
+	go node.memPool.MonitorPool()
+	go node.pubSubManager.Start()
+	return node.consensusEngine.Start()
+}
+
+func (node *Node) stop() {
+	node.stopOnce.Do(func() {
+		if node.rpcServer != nil {
+			node.rpcServer.Stop()
+		}
+		if err := node.consensusEngine.Stop(); err != nil {
+			Logger.log.Error(err)
+		}
+		node.syncker.Stop()
+		node.netSync.Stop()
+		close(node.cQuit)
+	})
+}
+
+// BlockChain returns the blockchain of the node
+func (node *Node) BlockChain() *blockchain.BlockChain {
+	return node.blockChain
+}
+
+// TxPool returns the mempool of the node
+func (node *Node) TxPool() *mempool.TxPool {
+	return node.memPool
+}
+
+// Height returns the best height of a chain of the node, -1 being the beacon chain
+func (node *Node) Height(chainID int) uint64 {
+	if chainID == -1 {
+		return node.blockChain.BeaconChain.GetBestViewHeight()
+	}
+	return node.blockChain.ShardChain[chainID].GetBestViewHeight()
+}
+
+// RPCAddress returns the address of the rpc server of the node, empty when the
+// harness runs without rpc
+func (node *Node) RPCAddress() string {
+	if node.rpcListener == nil {
+		return ""
+	}
+	return node.rpcListener.Addr().String()
+}
+
+// RPC calls method on the rpc server of the node and returns the raw result
+func (node *Node) RPC(method string, params ...interface{}) (json.RawMessage, error) {
+	if node.rpcListener == nil {
+		return nil, errors.New("rpc is not enabled")
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcserver.JsonRequest{Jsonrpc: "1.0", Method: method, Params: params, Id: 1})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Post("http://"+node.RPCAddress(), "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	res := rpcserver.JsonResponse{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, data)
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("%v: %v", method, res.Error.Message)
+	}
+	return res.Result, nil
+}
+
+// receive dispatches a message of the bus as the message listeners of server.go do
+func (node *Node) receive(msg wire.Message, senderID string) {
+	switch msg := msg.(type) {
+	case *wire.MessageBFT:
+		node.netSync.QueueMessage(nil, msg, nil)
+	case *wire.MessageBlockBeacon:
+		go node.syncker.ReceiveBlock(msg.Block, senderID)
+	case *wire.MessageBlockShard:
+		go node.syncker.ReceiveBlock(msg.Block, senderID)
+	case *wire.MessageShardToBeacon:
+		go node.syncker.ReceiveBlock(msg.Block, senderID)
+	case *wire.MessageCrossShard:
+		go node.syncker.ReceiveBlock(msg.Block, senderID)
+	case *wire.MessageTx:
+		node.netSync.QueueTx(nil, msg, nil)
+	case *wire.MessageTxPrivacyToken:
+		node.netSync.QueueTxPrivacyToken(nil, msg, nil)
+	case *wire.MessagePeerState:
+		go node.syncker.ReceivePeerState(msg)
+	}
+}
+
+func (node *Node) PushMessageToAll(msg wire.Message) error {
+	return node.bus.publish(node, msg, node.bus.peers(node, nil))
+}
+
+func (node *Node) PushMessageToPeer(msg wire.Message, peerID libp2p.ID) error {
+	return node.bus.publish(node, msg, node.bus.peers(node, func(other *Node) bool {
+		return other.peerID == peerID
+	}))
+}
+
+// PushMessageToChain also delivers msg to the node itself when it works on the
+// chain, as the pubsub of the highway does for the topics a node subscribed
+// to: the consensus counts the vote of the node from its own message
+func (node *Node) PushMessageToChain(msg wire.Message, chain common.ChainInterface) error {
+	receivers := node.bus.peers(node, chainFilter(chain.GetShardID()))
+	if chainFilter(chain.GetShardID())(node) {
+		receivers = append(receivers, node)
+	}
+	return node.bus.publish(node, msg, receivers)
+}
+
+func (node *Node) PushBlockToAll(block common.BlockInterface, isBeacon bool) error {
+	if isBeacon {
+		beaconBlock, ok := block.(*blockchain.BeaconBlock)
+		if !ok || beaconBlock == nil {
+			return errors.New("Can not parse beacon block or beacon block is nil")
+		}
+		return node.PushMessageToAll(&wire.MessageBlockBeacon{Block: beaconBlock})
+	}
+	shardBlock, ok := block.(*blockchain.ShardBlock)
+	if !ok || shardBlock == nil {
+		return errors.New("Can not parse shard block or shard block is nil")
+	}
+	if err := node.bus.publish(node, &wire.MessageBlockShard{Block: shardBlock}, node.bus.peers(node, chainFilter(int(shardBlock.Header.ShardID)))); err != nil {
+		return err
+	}
+	shardToBeaconBlk := shardBlock.CreateShardToBeaconBlock(node.blockChain)
+	if shardToBeaconBlk == nil {
+		return errors.New("CreateShardToBeaconBlock return block nil")
+	}
+	if err := node.bus.publish(node, &wire.MessageShardToBeacon{Block: shardToBeaconBlk}, node.bus.peers(node, chainFilter(-1))); err != nil {
+		return err
+	}
+	crossShardBlks := shardBlock.CreateAllCrossShardBlock(node.blockChain.GetBeaconBestState().ActiveShards)
+	for shardID, crossShardBlk := range crossShardBlks {
+		if err := node.bus.publish(node, &wire.MessageCrossShard{Block: crossShardBlk}, node.bus.peers(node, chainFilter(int(shardID)))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (node *Node) PublishNodeState(userLayer string, shardID int) error {
+	userKey, _ := node.consensusEngine.GetCurrentMiningPublicKey()
+	if userKey == "" {
+		return nil
+	}
+	msg, err := wire.MakeEmptyMessage(wire.CmdPeerState)
+	if err != nil {
+		return err
+	}
+	peerState := msg.(*wire.MessagePeerState)
+	bBestState := node.blockChain.GetBeaconBestState()
+	peerState.Beacon = wire.ChainState{
+		Timestamp:     bBestState.BestBlock.Header.Timestamp,
+		Height:        bBestState.BeaconHeight,
+		BlockHash:     bBestState.BestBlockHash,
+		BestStateHash: bBestState.Hash(),
+	}
+	if userLayer != common.BeaconRole {
+		sBestState := node.blockChain.GetBestStateShard(byte(shardID))
+		peerState.Shards[byte(shardID)] = wire.ChainState{
+			Timestamp:     sBestState.BestBlock.Header.Timestamp,
+			Height:        sBestState.ShardHeight,
+			BlockHash:     sBestState.BestBlockHash,
+			BestStateHash: sBestState.Hash(),
+		}
+	} else {
+		s2bMap := make(map[byte][]uint64)
+		for sID := 0; sID < node.chainParams.ActiveShards; sID++ {
+			s2bMap[byte(sID)] = []uint64{node.syncker.GetPoolLatestHeight(
+				syncker.S2BPoolType,
+				bBestState.BestShardHash[byte(sID)].String(),
+				sID,
+			)}
+		}
+		peerState.ShardToBeaconPool = s2bMap
+	}
+	peerState.SenderMiningPublicKey, err = node.consensusEngine.GetMiningPublicKeys().ToBase58()
+	if err != nil {
+		return err
+	}
+	return node.PushMessageToAll(peerState)
+}
+
+// The blocks are requested with the stream methods, the legacy requests of
+// blockchain.Server are not served by the harness
+
+func (node *Node) PushMessageGetBlockBeaconByHeight(from uint64, to uint64) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockBeaconByHash(blksHash []common.Hash, getFromPool bool, peerID libp2p.ID) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockBeaconBySpecificHeight(heights []uint64, getFromPool bool) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockShardByHeight(shardID byte, from uint64, to uint64) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockShardByHash(shardID byte, blksHash []common.Hash, getFromPool bool, peerID libp2p.ID) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockShardBySpecificHeight(shardID byte, heights []uint64, getFromPool bool) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockShardToBeaconByHeight(shardID byte, from uint64, to uint64) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockShardToBeaconByHash(shardID byte, blksHash []common.Hash, getFromPool bool, peerID libp2p.ID) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockShardToBeaconBySpecificHeight(shardID byte, blksHeight []uint64, getFromPool bool, peerID libp2p.ID) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockCrossShardByHash(fromShard byte, toShard byte, blksHash []common.Hash, getFromPool bool, peerID libp2p.ID) error {
+	return nil
+}
+
+func (node *Node) PushMessageGetBlockCrossShardBySpecificHeight(fromShard byte, toShard byte, blksHeight []uint64, getFromPool bool, peerID libp2p.ID) error {
+	return nil
+}
+
+func (node *Node) UpdateConsensusState(role string, userPbk string, currentShard *byte, beaconCommittee []string, shardCommittee map[byte][]string) {
+}
+
+func (node *Node) BroadcastCommittee(epoch uint64, newBeaconCommittee []incognitokey.CommitteePublicKey, newAllShardCommittee map[byte][]incognitokey.CommitteePublicKey, newAllShardPending map[byte][]incognitokey.CommitteePublicKey) {
+}
+
+func (node *Node) ReportPeer(peerID string, b peerv2.Behaviour) {
+	node.reputation.Report(peerID, b)
+}
+
+func (node *Node) IsPeerBanned(peerID string) bool {
+	return node.reputation.IsBanned(peerID)
+}
+
+func (node *Node) RequestBeaconBlocksViaStream(ctx context.Context, peerID string, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHeightRequest{
+		Type:         proto.BlkType_BlkBc,
+		Heights:      []uint64{from, to},
+		From:         int32(peerv2.HighwayBeaconID),
+		To:           int32(peerv2.HighwayBeaconID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHeight(ctx, node, peerID, -1, req)
+}
+
+func (node *Node) RequestShardBlocksViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHeightRequest{
+		Type:         proto.BlkType_BlkShard,
+		Heights:      []uint64{from, to},
+		From:         int32(fromSID),
+		To:           int32(fromSID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHeight(ctx, node, peerID, fromSID, req)
+}
+
+func (node *Node) RequestBeaconHeadersViaStream(ctx context.Context, peerID string, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHeightRequest{
+		Type:         peerv2.BlkTypeBeaconHeader,
+		Heights:      []uint64{from, to},
+		From:         int32(peerv2.HighwayBeaconID),
+		To:           int32(peerv2.HighwayBeaconID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHeight(ctx, node, peerID, -1, req)
+}
+
+func (node *Node) RequestShardHeadersViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHeightRequest{
+		Type:         peerv2.BlkTypeShardHeader,
+		Heights:      []uint64{from, to},
+		From:         int32(fromSID),
+		To:           int32(fromSID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHeight(ctx, node, peerID, fromSID, req)
+}
+
+func (node *Node) RequestShardToBeaconBlocksViaStream(ctx context.Context, peerID string, fromSID int, from uint64, to uint64) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHeightRequest{
+		Type:         proto.BlkType_BlkS2B,
+		Heights:      []uint64{from, to},
+		From:         int32(fromSID),
+		To:           int32(peerv2.HighwayBeaconID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHeight(ctx, node, peerID, fromSID, req)
+}
+
+func (node *Node) RequestCrossShardBlocksViaStream(ctx context.Context, peerID string, fromSID int, toSID int, heights []uint64) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHeightRequest{
+		Type:         proto.BlkType_BlkXShard,
+		Specific:     true,
+		Heights:      heights,
+		From:         int32(fromSID),
+		To:           int32(toSID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHeight(ctx, node, peerID, fromSID, req)
+}
+
+func (node *Node) RequestCrossShardBlocksByHashViaStream(ctx context.Context, peerID string, fromSID int, toSID int, hashes [][]byte) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHashRequest{
+		Type:         proto.BlkType_BlkXShard,
+		Hashes:       hashes,
+		From:         int32(fromSID),
+		To:           int32(toSID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHash(ctx, node, peerID, fromSID, req)
+}
+
+func (node *Node) RequestBeaconBlocksByHashViaStream(ctx context.Context, peerID string, hashes [][]byte) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHashRequest{
+		Type:         proto.BlkType_BlkBc,
+		Hashes:       hashes,
+		From:         int32(peerv2.HighwayBeaconID),
+		To:           int32(peerv2.HighwayBeaconID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHash(ctx, node, peerID, -1, req)
+}
+
+func (node *Node) RequestShardBlocksByHashViaStream(ctx context.Context, peerID string, fromSID int, hashes [][]byte) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHashRequest{
+		Type:         proto.BlkType_BlkShard,
+		Hashes:       hashes,
+		From:         int32(fromSID),
+		To:           int32(fromSID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHash(ctx, node, peerID, fromSID, req)
+}
+
+func (node *Node) RequestShardToBeaconBlocksByHashViaStream(ctx context.Context, peerID string, fromSID int, hashes [][]byte) (blockCh chan common.BlockInterface, err error) {
+	req := &proto.BlockByHashRequest{
+		Type:         proto.BlkType_BlkS2B,
+		Hashes:       hashes,
+		From:         int32(fromSID),
+		To:           int32(peerv2.HighwayBeaconID),
+		SyncFromPeer: peerID,
+	}
+	return node.bus.streamByHash(ctx, node, peerID, fromSID, req)
+}
+
+func (node *Node) RequestMissingViewViaStream(peerID string, hashes [][]byte, fromCID int, chainName string) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, hashBytes := range hashes {
+		if chainName == common.BeaconChainKey {
+			node.syncker.SyncMissingBeaconBlock(ctx, peerID, common.BytesToHash(hashBytes))
+		} else {
+			node.syncker.SyncMissingShardBlock(ctx, peerID, byte(fromCID), common.BytesToHash(hashBytes))
+		}
+	}
+	return nil
+}
+
+func (node *Node) FetchConfirmBeaconBlockByHeight(height uint64) (*blockchain.BeaconBlock, error) {
+	blkhash, err := rawdbv2.GetFinalizedBeaconBlockHashByIndex(node.blockChain.GetBeaconChainDatabase(), height)
+	if err != nil {
+		return nil, err
+	}
+	beaconBlock, _, err := node.blockChain.GetBeaconBlockByHash(*blkhash)
+	if err != nil {
+		return nil, err
+	}
+	return beaconBlock, nil
+}
+
+func (node *Node) FetchNextCrossShard(fromSID, toSID int, currentHeight uint64) *syncker.NextCrossShardInfo {
+	b, err := rawdbv2.GetCrossShardNextHeight(node.dataBase[common.BeaconChainDataBaseID], byte(fromSID), byte(toSID), currentHeight)
+	if err != nil {
+		return nil
+	}
+	res := new(syncker.NextCrossShardInfo)
+	if err := json.Unmarshal(b, res); err != nil {
+		return nil
+	}
+	return res
+}
+
+func (node *Node) GetBeaconChainDatabase() incdb.Database {
+	return node.dataBase[common.BeaconChainDataBaseID]
+}
+
+func (node *Node) GetChainParam() *blockchain.Params {
+	return node.chainParams
+}
+
+func (node *Node) GetSelfPeerID() libp2p.ID {
+	return node.peerID
+}
+
+func (node *Node) GetMiningKeys() string {
+	return ""
+}
+
+func (node *Node) GetPrivateKey() string {
+	return node.Account.PrivateKey
+}
+
+func (node *Node) EnableMining(enable bool) error {
+	node.isEnableMining = enable
+	return nil
+}
+
+func (node *Node) IsEnableMining() bool {
+	return node.isEnableMining
+}
+
+// GetUserMiningState follows Server.GetUserMiningState
+func (node *Node) GetUserMiningState() (role string, chainID int) {
+	userPk := node.consensusEngine.GetMiningPublicKeys()
+	if node.blockChain == nil || userPk == nil {
+		return "", -2
+	}
+	for _, v := range node.blockChain.BeaconChain.GetCommittee() {
+		if v.IsEqualMiningPubKey(common.BlsConsensus, userPk) {
+			return common.CommitteeRole, -1
+		}
+	}
+	for _, v := range node.blockChain.BeaconChain.GetPendingCommittee() {
+		if v.IsEqualMiningPubKey(common.BlsConsensus, userPk) {
+			return common.PendingRole, -1
+		}
+	}
+	for _, chain := range node.blockChain.ShardChain {
+		for _, v := range chain.GetCommittee() {
+			if v.IsEqualMiningPubKey(common.BlsConsensus, userPk) {
+				return common.CommitteeRole, chain.GetShardID()
+			}
+		}
+		for _, v := range chain.GetPendingCommittee() {
+			if v.IsEqualMiningPubKey(common.BlsConsensus, userPk) {
+				return common.PendingRole, chain.GetShardID()
+			}
+		}
+	}
+	shardPendingCommiteeFromBeaconView := node.blockChain.GetBeaconBestState().GetShardPendingValidator()
+	shardCommiteeFromBeaconView := node.blockChain.GetBeaconBestState().GetShardCommittee()
+	for _, chain := range node.blockChain.ShardChain {
+		for _, v := range shardPendingCommiteeFromBeaconView[byte(chain.GetShardID())] {
+			if v.IsEqualMiningPubKey(common.BlsConsensus, userPk) {
+				return common.PendingRole, chain.GetShardID()
+			}
+		}
+		for _, v := range shardCommiteeFromBeaconView[byte(chain.GetShardID())] {
+			if v.IsEqualMiningPubKey(common.BlsConsensus, userPk) {
+				return common.SyncingRole, chain.GetShardID()
+			}
+		}
+	}
+	return "", -2
+}
+
+func (node *Node) GetNodeRole() string {
+	role, chainID := node.GetUserMiningState()
+	switch chainID {
+	case -2:
+		return ""
+	case -1:
+		return "BEACON_" + role
+	default:
+		return "SHARD_" + role
+	}
+}
+
+func (node *Node) GetChainMiningStatus(chain int) string {
+	role, chainID := node.GetUserMiningState()
+	if chainID == -2 || chain != chainID {
+		return "notmining"
+	}
+	switch role {
+	case common.CommitteeRole:
+		if node.syncker.IsChainReady(chain) {
+			return "mining"
+		}
+		return "syncing"
+	case common.PendingRole:
+		return "pending"
+	case common.SyncingRole:
+		return "syncing"
+	}
+	return "notmining"
+}
+
+// GetPublicKeyRole only looks up the committees, the harness has no candidates
+func (node *Node) GetPublicKeyRole(publicKey string, keyType string) (int, int) {
+	beaconBestState := node.blockChain.GetBeaconBestState()
+	for shardID, pubkeyArr := range beaconBestState.GetShardPendingValidator() {
+		keyList, _ := incognitokey.ExtractPublickeysFromCommitteeKeyList(pubkeyArr, keyType)
+		if common.IndexOfStr(publicKey, keyList) > -1 {
+			return 0, int(shardID)
+		}
+	}
+	for shardID, pubkeyArr := range beaconBestState.GetShardCommittee() {
+		keyList, _ := incognitokey.ExtractPublickeysFromCommitteeKeyList(pubkeyArr, keyType)
+		if common.IndexOfStr(publicKey, keyList) > -1 {
+			return 1, int(shardID)
+		}
+	}
+	keyList, _ := incognitokey.ExtractPublickeysFromCommitteeKeyList(beaconBestState.GetBeaconCommittee(), keyType)
+	if common.IndexOfStr(publicKey, keyList) > -1 {
+		return 1, -1
+	}
+	keyList, _ = incognitokey.ExtractPublickeysFromCommitteeKeyList(beaconBestState.GetBeaconPendingValidator(), keyType)
+	if common.IndexOfStr(publicKey, keyList) > -1 {
+		return 0, -1
+	}
+	return -1, -1
+}
+
+// GetIncognitoPublicKeyRole only looks up the committees, the harness has no candidates
+func (node *Node) GetIncognitoPublicKeyRole(publicKey string) (int, bool, int) {
+	beaconBestState := node.blockChain.GetBeaconBestState()
+	for shardID, pubkeyArr := range beaconBestState.GetShardPendingValidator() {
+		for _, key := range pubkeyArr {
+			if key.GetIncKeyBase58() == publicKey {
+				return 1, false, int(shardID)
+			}
+		}
+	}
+	for shardID, pubkeyArr := range beaconBestState.GetShardCommittee() {
+		for _, key := range pubkeyArr {
+			if key.GetIncKeyBase58() == publicKey {
+				return 2, false, int(shardID)
+			}
+		}
+	}
+	for _, key := range beaconBestState.GetBeaconCommittee() {
+		if key.GetIncKeyBase58() == publicKey {
+			return 2, true, -1
+		}
+	}
+	for _, key := range beaconBestState.GetBeaconPendingValidator() {
+		if key.GetIncKeyBase58() == publicKey {
+			return 1, true, -1
+		}
+	}
+	return -1, false, -1
+}
+
+// GetMinerIncognitoPublickey only looks up the committees, the harness has no candidates
+func (node *Node) GetMinerIncognitoPublickey(publicKey string, keyType string) []byte {
+	beaconBestState := node.blockChain.GetBeaconBestState()
+	committees := [][]incognitokey.CommitteePublicKey{beaconBestState.GetBeaconCommittee(), beaconBestState.GetBeaconPendingValidator()}
+	for _, pubkeyArr := range beaconBestState.GetShardCommittee() {
+		committees = append(committees, pubkeyArr)
+	}
+	for _, pubkeyArr := range beaconBestState.GetShardPendingValidator() {
+		committees = append(committees, pubkeyArr)
+	}
+	for _, pubkeyArr := range committees {
+		keyList, _ := incognitokey.ExtractPublickeysFromCommitteeKeyList(pubkeyArr, keyType)
+		if found := common.IndexOfStr(publicKey, keyList); found > -1 {
+			return pubkeyArr[found].GetNormalKey()
+		}
+	}
+	return nil
+}
@@ -65,6 +65,9 @@ const (
 	RejectTxType
 	RejectTxInfoSize
 	RejectTxMedataWithBlockChain
+
+	MultiSigTxError
+	MultiSigTxMismatchError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -131,6 +134,10 @@ var ErrCodeMessage = map[int]struct {
 	// for normal token
 	NormalTokenPRVJsonError: {-4000, "Json data error"},
 	NormalTokenJsonError:    {-4001, "Json data error"},
+
+	// for multisig tx
+	MultiSigTxError:         {-5000, "Multisig tx error"},
+	MultiSigTxMismatchError: {-5001, "Multisig tx does not match its signing session"},
 }
 
 type TransactionError struct {
@@ -0,0 +1,376 @@
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
+	zkp "github.com/incognitochain/incognito-chain/privacy/zeroknowledge"
+)
+
+// MultiSigTx is a transfer without privacy from the payment address of a
+// group key, signed by a threshold of the participants of the group. The
+// coordinator builds it unsigned with NewMultiSigTx, passes it to the signers
+// round after round and adds their messages with AddMessage. InputCoins[i]
+// are the coins spent by the i-th tx to sign, they are put into its proof once
+// their serial numbers are proved, after round 5
+type MultiSigTx struct {
+	Tx         *Tx                   `json:",omitempty"`
+	TokenTx    *TxCustomTokenPrivacy `json:",omitempty"`
+	InputCoins [][][]byte
+	Session    *multisig.SigningSession
+}
+
+// MultiSigTxParams are the params of a multisig tx, TokenParams is nil for a
+// PRV transfer, otherwise its TokenTxType must be CustomTokenTransfer
+type MultiSigTxParams struct {
+	Group       *multisig.GroupKey
+	Signers     []uint32
+	PaymentInfo []*privacy.PaymentInfo
+	InputCoins  []*privacy.InputCoin
+	Fee         uint64
+	TokenParams *CustomTokenPrivacyParamTx
+	StateDB     *statedb.StateDB
+	Info        []byte
+}
+
+// NewMultiSigTx returns the unsigned tx of params and the session of its
+// signing by params.Signers
+func NewMultiSigTx(params *MultiSigTxParams) (*MultiSigTx, error) {
+	group := params.Group
+	normalTx, err := newMultiSigNormalTx(group, params.PaymentInfo, params.InputCoins, params.Fee, nil, params.StateDB, params.Info)
+	if err != nil {
+		return nil, err
+	}
+	multiSigTx := &MultiSigTx{}
+	inputCoins := [][]*privacy.InputCoin{params.InputCoins}
+	if params.TokenParams == nil {
+		multiSigTx.Tx = normalTx
+	} else {
+		tokenParams := params.TokenParams
+		if tokenParams.TokenTxType != CustomTokenTransfer {
+			return nil, NewTransactionErr(PrivacyTokenTxTypeNotHandleError, errors.New("a multisig tx can only transfer a token"))
+		}
+		propertyID, err := common.Hash{}.NewHashFromStr(tokenParams.PropertyID)
+		if err != nil {
+			return nil, NewTransactionErr(TokenIDInvalidError, err, tokenParams.PropertyID)
+		}
+		tokenNormalTx, err := newMultiSigNormalTx(group, tokenParams.Receiver, tokenParams.TokenInput, tokenParams.Fee, propertyID, params.StateDB, nil)
+		if err != nil {
+			return nil, NewTransactionErr(PrivacyTokenInitTokenDataError, err)
+		}
+		normalTx.Type = common.TxCustomTokenPrivacyType
+		multiSigTx.TokenTx = &TxCustomTokenPrivacy{
+			Tx: *normalTx,
+			TxPrivacyTokenData: TxPrivacyTokenData{
+				Type:           tokenParams.TokenTxType,
+				PropertyName:   tokenParams.PropertyName,
+				PropertySymbol: tokenParams.PropertySymbol,
+				PropertyID:     *propertyID,
+				Mintable:       tokenParams.Mintable,
+				TxNormal:       *tokenNormalTx,
+			},
+		}
+		inputCoins = append(inputCoins, tokenParams.TokenInput)
+	}
+
+	sndDerivators := []*privacy.Scalar{}
+	multiSigTx.InputCoins = make([][][]byte, len(inputCoins))
+	for i, coins := range inputCoins {
+		multiSigTx.InputCoins[i] = make([][]byte, len(coins))
+		for j, coin := range coins {
+			if !bytes.Equal(coin.CoinDetails.GetPublicKey().ToBytesS(), group.PublicKey) {
+				return nil, NewTransactionErr(WrongInputError, errors.New("input coin is not owned by the group"))
+			}
+			sndDerivators = append(sndDerivators, coin.CoinDetails.GetSNDerivator())
+			multiSigTx.InputCoins[i][j] = coin.Bytes()
+		}
+	}
+	multiSigTx.Session, err = multisig.NewSigningSession(group, params.Signers, sndDerivators, len(multiSigTx.txs()))
+	if err != nil {
+		return nil, NewTransactionErr(MultiSigTxError, err)
+	}
+	return multiSigTx, nil
+}
+
+// newMultiSigNormalTx builds the unsigned tx without privacy spending
+// inputCoins of group as Tx.Init does, the change goes back to the group
+func newMultiSigNormalTx(group *multisig.GroupKey, paymentInfo []*privacy.PaymentInfo, inputCoins []*privacy.InputCoin, fee uint64, tokenID *common.Hash, stateDB *statedb.StateDB, info []byte) (*Tx, error) {
+	if len(inputCoins) > 255 {
+		return nil, NewTransactionErr(InputCoinIsVeryLargeError, nil, strconv.Itoa(len(inputCoins)))
+	}
+	if len(paymentInfo) > 254 {
+		return nil, NewTransactionErr(PaymentInfoIsVeryLargeError, nil, strconv.Itoa(len(paymentInfo)))
+	}
+	if txSize := EstimateTxSize(NewEstimateTxSizeParam(len(inputCoins), len(paymentInfo), false, nil, nil, 0)); txSize > common.MaxTxSize {
+		return nil, NewTransactionErr(ExceedSizeTx, nil, strconv.Itoa(int(txSize)))
+	}
+	if len(info) > MaxSizeInfo {
+		return nil, NewTransactionErr(ExceedSizeInfoTxError, nil)
+	}
+	if tokenID == nil {
+		tokenID = &common.Hash{}
+		if err := tokenID.SetBytes(common.PRVCoinID[:]); err != nil {
+			return nil, NewTransactionErr(TokenIDInvalidError, err, tokenID.String())
+		}
+	}
+	tx := &Tx{
+		Version:              txVersion,
+		Type:                 common.TxNormalType,
+		LockTime:             time.Now().Unix(),
+		Fee:                  fee,
+		Info:                 []byte{},
+		SigPubKey:            group.PublicKey,
+		PubKeyLastByteSender: group.PublicKey[len(group.PublicKey)-1],
+	}
+	if len(info) > 0 {
+		tx.Info = info
+	}
+	if len(inputCoins) == 0 && fee == 0 {
+		return tx, nil
+	}
+
+	sumOutputValue := uint64(0)
+	for _, p := range paymentInfo {
+		sumOutputValue += p.Amount
+	}
+	sumInputValue := uint64(0)
+	for _, coin := range inputCoins {
+		sumInputValue += coin.CoinDetails.GetValue()
+	}
+	overBalance := int64(sumInputValue - sumOutputValue - fee)
+	if overBalance < 0 {
+		return nil, NewTransactionErr(WrongInputError, fmt.Errorf("input value less than output value. sumInputValue=%d sumOutputValue=%d fee=%d", sumInputValue, sumOutputValue, fee))
+	}
+	if overBalance > 0 {
+		paymentInfo = append(paymentInfo, &privacy.PaymentInfo{
+			PaymentAddress: group.PaymentAddress(),
+			Amount:         uint64(overBalance),
+		})
+	}
+
+	// create SNDs for output coins, they must be new and distinct
+	sndOuts := make([]*privacy.Scalar, 0)
+	for len(sndOuts) < len(paymentInfo) {
+		sndOut := privacy.RandomScalar()
+		existed, err := CheckSNDerivatorExistence(tokenID, sndOut, stateDB)
+		if err != nil {
+			Logger.log.Error(err)
+		}
+		if existed || privacy.CheckDuplicateScalarArray(append(sndOuts, sndOut)) {
+			continue
+		}
+		sndOuts = append(sndOuts, sndOut)
+	}
+
+	outputCoins := make([]*privacy.OutputCoin, len(paymentInfo))
+	for i, pInfo := range paymentInfo {
+		if len(pInfo.Message) > privacy.MaxSizeInfoCoin {
+			return nil, NewTransactionErr(ExceedSizeInfoOutCoinError, nil)
+		}
+		publicKey, err := new(privacy.Point).FromBytesS(pInfo.PaymentAddress.Pk)
+		if err != nil {
+			return nil, NewTransactionErr(DecompressPaymentAddressError, err, pInfo.PaymentAddress)
+		}
+		outputCoins[i] = new(privacy.OutputCoin)
+		outputCoins[i].CoinDetails = new(privacy.Coin)
+		outputCoins[i].CoinDetails.SetValue(pInfo.Amount)
+		outputCoins[i].CoinDetails.SetInfo(pInfo.Message)
+		outputCoins[i].CoinDetails.SetPublicKey(publicKey)
+		outputCoins[i].CoinDetails.SetSNDerivator(sndOuts[i])
+	}
+
+	// the witness commits the output coins, the input coins and their serial
+	// number proofs are added once the signers proved them
+	witness := new(zkp.PaymentWitness)
+	if err := witness.Init(zkp.PaymentWitnessParam{
+		HasPrivacy:              false,
+		OutputCoins:             outputCoins,
+		PublicKeyLastByteSender: tx.PubKeyLastByteSender,
+		Fee:                     fee,
+	}); err != nil {
+		return nil, NewTransactionErr(InitWithnessError, err, "")
+	}
+	proof, err := witness.Prove(false)
+	if err != nil {
+		return nil, NewTransactionErr(WithnessProveError, err, false, "")
+	}
+	tx.Proof = proof
+	return tx, nil
+}
+
+// txs returns the txs to sign in the order of the messages of the session,
+// the fee tx then the token tx for a token transfer
+func (multiSigTx *MultiSigTx) txs() []*Tx {
+	if multiSigTx.TokenTx != nil {
+		return []*Tx{&multiSigTx.TokenTx.Tx, &multiSigTx.TokenTx.TxPrivacyTokenData.TxNormal}
+	}
+	if multiSigTx.Tx != nil {
+		return []*Tx{multiSigTx.Tx}
+	}
+	return nil
+}
+
+// inputCoins returns the input coins of every tx to sign
+func (multiSigTx *MultiSigTx) inputCoins() ([][]*privacy.InputCoin, error) {
+	result := make([][]*privacy.InputCoin, len(multiSigTx.InputCoins))
+	for i, coins := range multiSigTx.InputCoins {
+		result[i] = make([]*privacy.InputCoin, len(coins))
+		for j, coinBytes := range coins {
+			result[i][j] = new(privacy.InputCoin)
+			result[i][j].Init()
+			if err := result[i][j].SetBytes(coinBytes); err != nil {
+				return nil, NewTransactionErr(MultiSigTxMismatchError, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// check checks the txs against the session: they are signed by the group key
+// and spend the coins of the session, once the messages are set they are the
+// hashes of the txs
+func (multiSigTx *MultiSigTx) check() error {
+	session := multiSigTx.Session
+	txs := multiSigTx.txs()
+	if session == nil || len(txs) == 0 || len(txs) != session.NumMessages || len(txs) != len(multiSigTx.InputCoins) {
+		return NewTransactionErr(MultiSigTxMismatchError, errors.New("wrong number of txs"))
+	}
+	inputCoins, err := multiSigTx.inputCoins()
+	if err != nil {
+		return err
+	}
+	numCoins := 0
+	for i, tx := range txs {
+		if !bytes.Equal(tx.SigPubKey, session.Group.PublicKey) {
+			return NewTransactionErr(MultiSigTxMismatchError, errors.New("tx is not signed by the group key"))
+		}
+		for _, coin := range inputCoins[i] {
+			if numCoins >= len(session.SNDerivators) ||
+				!bytes.Equal(coin.CoinDetails.GetPublicKey().ToBytesS(), session.Group.PublicKey) ||
+				!bytes.Equal(coin.CoinDetails.GetSNDerivator().ToBytesS(), session.SNDerivators[numCoins]) {
+				return NewTransactionErr(MultiSigTxMismatchError, errors.New("input coins are not the coins of the session"))
+			}
+			numCoins++
+		}
+		if len(session.Messages) == 0 {
+			continue
+		}
+		proofCoins := []*privacy.InputCoin{}
+		if tx.Proof != nil {
+			proofCoins = tx.Proof.GetInputCoins()
+		}
+		if len(proofCoins) != len(inputCoins[i]) {
+			return NewTransactionErr(MultiSigTxMismatchError, errors.New("input coins are not set in the proof"))
+		}
+		for j, coin := range inputCoins[i] {
+			if !bytes.Equal(proofCoins[j].CoinDetails.GetSNDerivator().ToBytesS(), coin.CoinDetails.GetSNDerivator().ToBytesS()) {
+				return NewTransactionErr(MultiSigTxMismatchError, errors.New("input coins are not the coins of the proof"))
+			}
+		}
+		tx.cachedHash = nil
+		if !bytes.Equal(tx.Hash()[:], session.Messages[i]) {
+			return NewTransactionErr(MultiSigTxMismatchError, fmt.Errorf("message %d is not the hash of its tx", i))
+		}
+	}
+	if numCoins != len(session.SNDerivators) {
+		return NewTransactionErr(MultiSigTxMismatchError, errors.New("input coins are not the coins of the session"))
+	}
+	return nil
+}
+
+// Sign returns the message of the participant of share for the current round
+// of the signing, after checking the txs against the session
+func (multiSigTx *MultiSigTx) Sign(share *multisig.KeyShare, state *multisig.SignerState) (*multisig.SigningMessage, error) {
+	if err := multiSigTx.check(); err != nil {
+		return nil, err
+	}
+	msg, err := multiSigTx.Session.Sign(share, state)
+	if err != nil {
+		return nil, NewTransactionErr(MultiSigTxError, err)
+	}
+	return msg, nil
+}
+
+// AddMessage adds the message of a signer to the session. Once round 5 is
+// done it sets the input coins and their serial number proofs into the proofs
+// and the hashes of the txs as the messages to sign, once round 6 is done it
+// sets the signatures
+func (multiSigTx *MultiSigTx) AddMessage(msg *multisig.SigningMessage) error {
+	if err := multiSigTx.check(); err != nil {
+		return err
+	}
+	session := multiSigTx.Session
+	if err := session.AddMessage(msg); err != nil {
+		return NewTransactionErr(MultiSigTxError, err)
+	}
+	switch session.Round() {
+	case multisig.SignRound6:
+		if len(session.Messages) == 0 {
+			return multiSigTx.setSerialNumberProofs()
+		}
+	case multisig.SignRound6 + 1:
+		signatures, err := session.Signatures()
+		if err != nil {
+			return NewTransactionErr(MultiSigTxError, err)
+		}
+		for i, tx := range multiSigTx.txs() {
+			tx.Sig = signatures[i].Bytes()
+		}
+	}
+	return nil
+}
+
+func (multiSigTx *MultiSigTx) setSerialNumberProofs() error {
+	session := multiSigTx.Session
+	serialNumbers, err := session.SerialNumbers()
+	if err != nil {
+		return NewTransactionErr(MultiSigTxError, err)
+	}
+	proofs, err := session.SerialNumberProofs()
+	if err != nil {
+		return NewTransactionErr(MultiSigTxError, err)
+	}
+	inputCoins, err := multiSigTx.inputCoins()
+	if err != nil {
+		return err
+	}
+	txs := multiSigTx.txs()
+	messages := make([][]byte, len(txs))
+	offset := 0
+	for i, tx := range txs {
+		if len(inputCoins[i]) > 0 {
+			for j, coin := range inputCoins[i] {
+				coin.CoinDetails.SetSerialNumber(serialNumbers[offset+j])
+			}
+			tx.Proof.SetInputCoins(inputCoins[i])
+			tx.Proof.SetSerialNumberNoPrivacyProof(proofs[offset : offset+len(inputCoins[i])])
+			offset += len(inputCoins[i])
+		}
+		tx.cachedHash = nil
+		messages[i] = tx.Hash()[:]
+	}
+	if err := session.SetMessages(messages); err != nil {
+		return NewTransactionErr(MultiSigTxError, err)
+	}
+	return nil
+}
+
+// SignedTx returns the tx once every signer answered the last round, it is
+// sent as any other tx
+func (multiSigTx *MultiSigTx) SignedTx() (metadata.Transaction, error) {
+	if multiSigTx.Session == nil || !multiSigTx.Session.IsComplete() {
+		return nil, NewTransactionErr(MultiSigTxError, errors.New("tx is not signed yet"))
+	}
+	if multiSigTx.TokenTx != nil {
+		return multiSigTx.TokenTx, nil
+	}
+	return multiSigTx.Tx, nil
+}
@@ -424,6 +424,13 @@ func (txCustomTokenPrivacy TxCustomTokenPrivacy) validateDoubleSpendTxWithCurren
 }
 
 func (txCustomTokenPrivacy TxCustomTokenPrivacy) ValidateTxWithBlockChain(chainRetriever metadata.ChainRetriever, shardViewRetriever metadata.ShardViewRetriever, beaconViewRetriever metadata.BeaconViewRetriever, shardID byte, stateDB *statedb.StateDB) error {
+	// the metadata of token txs is not validated with the blockchain, but the
+	// window of a time lock applies to token transfers too
+	if meta := txCustomTokenPrivacy.Tx.Metadata; meta != nil && meta.GetType() == metadata.TimeLockMeta {
+		if _, err := meta.ValidateTxWithBlockChain(&txCustomTokenPrivacy, chainRetriever, shardViewRetriever, beaconViewRetriever, shardID, stateDB); err != nil {
+			return NewTransactionErr(RejectTxMedataWithBlockChain, fmt.Errorf("validate metadata of tx %s with blockchain error %+v", txCustomTokenPrivacy.Hash().String(), err))
+		}
+	}
 	err := txCustomTokenPrivacy.ValidateDoubleSpendWithBlockchain(shardID, stateDB, nil)
 	if err != nil {
 		return NewTransactionErr(InvalidDoubleSpendPRVError, err)
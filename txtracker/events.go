@@ -0,0 +1,62 @@
+package txtracker
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// Sources of the received txs
+const (
+	SourceRPC  = "rpc"
+	SourcePeer = "peer"
+)
+
+// Tx is the part of a transaction read by the tracker
+type Tx interface {
+	Hash() *common.Hash
+	GetSenderAddrLastByte() byte
+}
+
+// recordTx records an event of a tx in the default tracker, on the shard of
+// its sender if the event has no chain
+func recordTx(tx Tx, event Event, senderShard bool) {
+	if senderShard {
+		event.ChainID = int(common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()))
+	}
+	Default.Record(*tx.Hash(), event)
+}
+
+// Received records a tx submitted by rpc or received from a peer
+func Received(tx Tx, source string) {
+	recordTx(tx, Event{Type: EventReceived, Detail: source}, true)
+}
+
+// Validated records a tx accepted in the mempool
+func Validated(tx Tx) {
+	recordTx(tx, Event{Type: EventValidated}, true)
+}
+
+// Held records a time-locked tx kept by the mempool until the beacon height
+// notBefore
+func Held(tx Tx, notBefore uint64) {
+	recordTx(tx, Event{Type: EventHeld, Height: notBefore}, true)
+}
+
+// Rejected records a tx not accepted in the mempool
+func Rejected(tx Tx, err error) {
+	recordTx(tx, Event{Type: EventRejected, Detail: err.Error()}, true)
+}
+
+// Relayed records the broadcast of a tx on a topic
+func Relayed(tx Tx, topic string) {
+	recordTx(tx, Event{Type: EventRelayed, Detail: topic}, true)
+}
+
+// Evicted records a tx removed from the mempool without being included
+func Evicted(tx Tx, reason string) {
+	recordTx(tx, Event{Type: EventEvicted, Detail: reason}, true)
+}
+
+// Picked records a tx picked for the new block at height of a shard
+func Picked(tx Tx, shardID byte, height uint64) {
+	recordTx(tx, Event{Type: EventPicked, ChainID: int(shardID), Height: height}, false)
+}
@@ -0,0 +1,238 @@
+package txtracker
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// Span is the lifecycle of a tx on a node as an OpenTelemetry span. The
+// trace of a tx is its hash, the spans of the nodes it went through are in
+// the same trace.
+type Span struct {
+	TraceID [16]byte
+	Name    string
+	Start   time.Time
+	End     time.Time
+	Failed  bool
+	Events  []Event
+	TxHash  string
+}
+
+// NewSpan returns the span of a lifecycle, from its first to its last event
+func NewSpan(l *Lifecycle) Span {
+	span := Span{Name: "tx", TxHash: l.TxHash, Events: append([]Event{}, l.Events...)}
+	hash, err := common.Hash{}.NewHashFromStr(l.TxHash)
+	if err == nil {
+		copy(span.TraceID[:], hash[:16])
+	}
+	if len(l.Events) > 0 {
+		span.Start = l.Events[0].Time
+		last := l.Events[len(l.Events)-1]
+		span.End = last.Time
+		span.Failed = last.Type == EventRejected || last.Type == EventEvicted
+	}
+	return span
+}
+
+// SpanExporter sends the spans of the txs at the end of their lifecycle to a
+// tracing backend, ExportSpan must not block
+type SpanExporter interface {
+	ExportSpan(span Span)
+}
+
+// OTLPExporter pushes spans to an OpenTelemetry collector with the OTLP/HTTP
+// json encoding, in batches
+type OTLPExporter struct {
+	url      string
+	service  string
+	instance string
+	spans    chan Span
+	client   *http.Client
+}
+
+const (
+	// OTLPBatchSize is the max number of spans of a push
+	OTLPBatchSize = 512
+	// OTLPQueueSize is the number of spans waiting for a push, the spans
+	// exported to a full queue are dropped
+	OTLPQueueSize = 4096
+)
+
+// NewOTLPExporter returns an exporter to the collector at endpoint, which
+// pushes to endpoint/v1/traces. instance tells the spans of the nodes
+// apart.
+func NewOTLPExporter(endpoint, service, instance string) *OTLPExporter {
+	return &OTLPExporter{
+		url:      strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		service:  service,
+		instance: instance,
+		spans:    make(chan Span, OTLPQueueSize),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ExportSpan queues a span for the next push
+func (e *OTLPExporter) ExportSpan(span Span) {
+	select {
+	case e.spans <- span:
+	default:
+	}
+}
+
+// Run pushes the queued spans every interval until quit is closed
+func (e *OTLPExporter) Run(interval time.Duration, quit <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	batch := []Span{}
+	for {
+		select {
+		case <-quit:
+			return
+		case span := <-e.spans:
+			batch = append(batch, span)
+			if len(batch) < OTLPBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		e.push(batch)
+		batch = []Span{}
+	}
+}
+
+func (e *OTLPExporter) push(spans []Span) {
+	data, err := json.Marshal(e.Payload(spans))
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewBuffer(data))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	ctx, cancel := context.WithTimeout(req.Context(), 30*time.Second)
+	defer cancel()
+	resp, err := e.client.Do(req.WithContext(ctx))
+	if err == nil {
+		resp.Body.Close()
+	}
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue,omitempty"`
+	IntValue    string `json:"intValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Events            []otlpEvent     `json:"events"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+// OTLPPayload is the body of an OTLP/HTTP json export request
+type OTLPPayload struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// OTLP status codes and span kind
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+	otlpKindServer  = 2
+)
+
+// Payload returns the export request of spans
+func (e *OTLPExporter) Payload(spans []Span) OTLPPayload {
+	scope := otlpScopeSpans{Spans: []otlpSpan{}}
+	scope.Scope.Name = "txtracker"
+	for _, span := range spans {
+		// the span id is unique to the node in the trace of the tx
+		spanID := sha256.Sum256([]byte(span.TxHash + e.instance))
+		s := otlpSpan{
+			TraceID:           hex.EncodeToString(span.TraceID[:]),
+			SpanID:            hex.EncodeToString(spanID[:8]),
+			Name:              span.Name,
+			Kind:              otlpKindServer,
+			StartTimeUnixNano: unixNano(span.Start),
+			EndTimeUnixNano:   unixNano(span.End),
+			Attributes:        []otlpAttribute{stringAttribute("tx.hash", span.TxHash)},
+			Events:            []otlpEvent{},
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if span.Failed {
+			s.Status.Code = otlpStatusError
+		}
+		for _, event := range span.Events {
+			attributes := []otlpAttribute{{Key: "chain.id", Value: otlpValue{IntValue: strconv.Itoa(event.ChainID)}}}
+			if event.Height != 0 {
+				attributes = append(attributes, otlpAttribute{Key: "block.height", Value: otlpValue{IntValue: strconv.FormatUint(event.Height, 10)}})
+			}
+			if event.Detail != "" {
+				attributes = append(attributes, stringAttribute("detail", event.Detail))
+			}
+			s.Events = append(s.Events, otlpEvent{TimeUnixNano: unixNano(event.Time), Name: string(event.Type), Attributes: attributes})
+		}
+		scope.Spans = append(scope.Spans, s)
+	}
+	resource := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scope}}
+	resource.Resource.Attributes = []otlpAttribute{stringAttribute("service.name", e.service)}
+	if e.instance != "" {
+		resource.Resource.Attributes = append(resource.Resource.Attributes, stringAttribute("service.instance.id", e.instance))
+	}
+	return OTLPPayload{ResourceSpans: []otlpResourceSpans{resource}}
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,79 @@
+package txtracker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOTLPPayload(t *testing.T) {
+	txHash := common.HashH([]byte{1})
+	start := time.Unix(100, 0)
+	span := NewSpan(&Lifecycle{TxHash: txHash.String(), Events: []Event{
+		{Type: EventReceived, Time: start, ChainID: 1, Detail: SourceRPC},
+		{Type: EventRejected, Time: start.Add(time.Second), ChainID: 1, Detail: "double spend"},
+	}})
+	assert.True(t, span.Failed)
+
+	payload := NewOTLPExporter("http://collector:4318/", "incognito", "node1").Payload([]Span{span})
+	data, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	decoded := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	resource := decoded["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	attributes := resource["resource"].(map[string]interface{})["attributes"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "incognito"}}, attributes[0])
+	s := resource["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})[0].(map[string]interface{})
+	assert.Len(t, s["traceId"], 32)
+	assert.Len(t, s["spanId"], 16)
+	assert.Equal(t, "100000000000", s["startTimeUnixNano"])
+	assert.Equal(t, "101000000000", s["endTimeUnixNano"])
+	assert.Equal(t, float64(otlpStatusError), s["status"].(map[string]interface{})["code"])
+	events := s["events"].([]interface{})
+	assert.Len(t, events, 2)
+	assert.Equal(t, "rejected", events[1].(map[string]interface{})["name"])
+
+	// the nodes have their own span in the trace of a tx
+	other := NewOTLPExporter("http://collector:4318", "incognito", "node2").Payload([]Span{span})
+	assert.NotEqual(t, s["spanId"], other.ResourceSpans[0].ScopeSpans[0].Spans[0].SpanID)
+	assert.Equal(t, s["traceId"], other.ResourceSpans[0].ScopeSpans[0].Spans[0].TraceID)
+}
+
+func TestOTLPExporterRun(t *testing.T) {
+	pushed := make(chan OTLPPayload, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/traces", req.URL.Path)
+		data, _ := ioutil.ReadAll(req.Body)
+		payload := OTLPPayload{}
+		assert.NoError(t, json.Unmarshal(data, &payload))
+		pushed <- payload
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL, "incognito", "")
+	tracker := NewTracker(10)
+	tracker.SetExporter(exporter)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		exporter.Run(10*time.Millisecond, quit)
+		close(done)
+	}()
+	tracker.Record(common.HashH([]byte{1}), Event{Type: EventReceived})
+	tracker.Record(common.HashH([]byte{1}), Event{Type: EventEvicted, Detail: "expired"})
+	select {
+	case payload := <-pushed:
+		assert.Len(t, payload.ResourceSpans[0].ScopeSpans[0].Spans, 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing pushed")
+	}
+	close(quit)
+	<-done
+}
@@ -0,0 +1,247 @@
+// Package txtracker records the lifecycle of transactions, from their
+// submission to the node to their inclusion in a block and the delivery of
+// their cross shard outputs, to find where a tx which never confirmed was
+// dropped.
+package txtracker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// EventType is a step of the lifecycle of a tx
+type EventType string
+
+const (
+	// the tx is submitted by rpc or received from a peer, Detail is the
+	// source
+	EventReceived EventType = "received"
+	// the tx is accepted in the mempool
+	EventValidated EventType = "validated"
+	// the time-locked tx is held by the mempool until the beacon height
+	// Height, where its window opens
+	EventHeld EventType = "held"
+	// the tx is not accepted in the mempool, Detail is the reason
+	EventRejected EventType = "rejected"
+	// the tx is broadcast to the network, Detail is the topic
+	EventRelayed EventType = "relayed"
+	// the tx is picked from the mempool for a new block at Height
+	EventPicked EventType = "picked"
+	// the tx is in the block at Height of chain ChainID
+	EventIncluded EventType = "included"
+	// the outputs of the tx for shard ChainID are in its block at Height
+	EventCrossShardDelivered EventType = "crossshard_delivered"
+	// the tx is removed from the mempool without being included, Detail is
+	// the reason
+	EventEvicted EventType = "evicted"
+)
+
+const (
+	// DefaultCapacity is the number of txs tracked by the default tracker
+	DefaultCapacity = 10000
+	// MaxEvents is the number of events kept for a tx, the later ones are
+	// dropped
+	MaxEvents = 64
+)
+
+// Event is a timestamped step of the lifecycle of a tx
+type Event struct {
+	Type    EventType
+	Time    time.Time
+	ChainID int
+	Height  uint64 `json:",omitempty"`
+	Detail  string `json:",omitempty"`
+}
+
+// Lifecycle is the events of a tx in the order they are recorded
+type Lifecycle struct {
+	TxHash string
+	Events []Event
+}
+
+// Done returns whether the tx is at the end of its lifecycle: rejected,
+// evicted or included with all its cross shard outputs delivered
+func (l *Lifecycle) Done() bool {
+	if len(l.Events) == 0 {
+		return false
+	}
+	switch l.Events[len(l.Events)-1].Type {
+	case EventRejected, EventEvicted, EventIncluded, EventCrossShardDelivered:
+		return true
+	}
+	return false
+}
+
+// crossShardKey identifies the cross shard outputs of the block at height of
+// a shard for another shard
+type crossShardKey struct {
+	from   byte
+	to     byte
+	height uint64
+}
+
+// entry is a tracked tx, with the cross shard outputs it waits for
+type entry struct {
+	hash      common.Hash
+	lifecycle Lifecycle
+	pending   []crossShardKey
+}
+
+// Tracker keeps the lifecycles of the last txs seen by the node in a ring
+// buffer, the oldest tx is dropped when a new one comes to a full buffer
+type Tracker struct {
+	mtx        sync.Mutex
+	ring       []*entry
+	next       int
+	index      map[common.Hash]*entry
+	crossShard map[crossShardKey][]common.Hash
+	exporter   SpanExporter
+}
+
+// NewTracker returns a tracker of the lifecycles of capacity txs
+func NewTracker(capacity int) *Tracker {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Tracker{
+		ring:       make([]*entry, capacity),
+		index:      map[common.Hash]*entry{},
+		crossShard: map[crossShardKey][]common.Hash{},
+	}
+}
+
+// SetExporter sets the exporter of the spans of the txs which reach the end
+// of their lifecycle, nil stops the export
+func (t *Tracker) SetExporter(exporter SpanExporter) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.exporter = exporter
+}
+
+// Record appends an event to the lifecycle of a tx, the time of the event is
+// now if it is not set
+func (t *Tracker) Record(txHash common.Hash, event Event) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.record(txHash, event)
+}
+
+// Included records the inclusion of txs in the block at height of a shard,
+// crossShards are the other shards receiving outputs of each tx
+func (t *Tracker) Included(shardID byte, height uint64, txHashes []common.Hash, crossShards [][]byte) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for i, txHash := range txHashes {
+		e := t.get(txHash)
+		toShards := []byte{}
+		if i < len(crossShards) {
+			toShards = crossShards[i]
+		}
+		for _, to := range toShards {
+			key := crossShardKey{from: shardID, to: to, height: height}
+			e.pending = append(e.pending, key)
+			t.crossShard[key] = append(t.crossShard[key], txHash)
+		}
+		t.record(txHash, Event{Type: EventIncluded, ChainID: int(shardID), Height: height})
+	}
+}
+
+// CrossShardDelivered records the delivery of the cross shard outputs of the
+// block at height of shard from in the block at toHeight of shard to
+func (t *Tracker) CrossShardDelivered(from byte, height uint64, to byte, toHeight uint64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key := crossShardKey{from: from, to: to, height: height}
+	txHashes := t.crossShard[key]
+	delete(t.crossShard, key)
+	for _, txHash := range txHashes {
+		e, ok := t.index[txHash]
+		if !ok {
+			continue
+		}
+		e.pending = removeKey(e.pending, key)
+		t.record(txHash, Event{Type: EventCrossShardDelivered, ChainID: int(to), Height: toHeight})
+	}
+}
+
+// Get returns a copy of the lifecycle of a tx, nil if it is not tracked
+func (t *Tracker) Get(txHash common.Hash) *Lifecycle {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	e, ok := t.index[txHash]
+	if !ok {
+		return nil
+	}
+	return &Lifecycle{TxHash: e.lifecycle.TxHash, Events: append([]Event{}, e.lifecycle.Events...)}
+}
+
+// get returns the entry of a tx, it drops the oldest tx to track a new one
+// in a full buffer
+func (t *Tracker) get(txHash common.Hash) *entry {
+	if e, ok := t.index[txHash]; ok {
+		return e
+	}
+	if old := t.ring[t.next]; old != nil {
+		delete(t.index, old.hash)
+		for _, key := range old.pending {
+			t.crossShard[key] = removeHash(t.crossShard[key], old.hash)
+			if len(t.crossShard[key]) == 0 {
+				delete(t.crossShard, key)
+			}
+		}
+	}
+	e := &entry{hash: txHash, lifecycle: Lifecycle{TxHash: txHash.String()}}
+	t.ring[t.next] = e
+	t.next = (t.next + 1) % len(t.ring)
+	t.index[txHash] = e
+	return e
+}
+
+func (t *Tracker) record(txHash common.Hash, event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	e := t.get(txHash)
+	if len(e.lifecycle.Events) >= MaxEvents {
+		return
+	}
+	e.lifecycle.Events = append(e.lifecycle.Events, event)
+	if t.exporter != nil && len(e.pending) == 0 && e.lifecycle.Done() {
+		t.exporter.ExportSpan(NewSpan(&e.lifecycle))
+	}
+}
+
+func removeKey(keys []crossShardKey, key crossShardKey) []crossShardKey {
+	res := keys[:0]
+	for _, k := range keys {
+		if k != key {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+func removeHash(hashes []common.Hash, hash common.Hash) []common.Hash {
+	res := hashes[:0]
+	for _, h := range hashes {
+		if h != hash {
+			res = append(res, h)
+		}
+	}
+	return res
+}
+
+// Default is the tracker of the node
+var Default = NewTracker(DefaultCapacity)
+
+// Record appends an event to the lifecycle of a tx in the default tracker
+func Record(txHash common.Hash, event Event) {
+	Default.Record(txHash, event)
+}
+
+// Get returns the lifecycle of a tx in the default tracker
+func Get(txHash common.Hash) *Lifecycle {
+	return Default.Get(txHash)
+}
@@ -0,0 +1,81 @@
+package txtracker
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type spans []Span
+
+func (s *spans) ExportSpan(span Span) {
+	*s = append(*s, span)
+}
+
+func eventTypes(l *Lifecycle) []EventType {
+	types := []EventType{}
+	for _, e := range l.Events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestTrackerLifecycle(t *testing.T) {
+	tracker := NewTracker(10)
+	exported := &spans{}
+	tracker.SetExporter(exported)
+	tx1, tx2 := common.HashH([]byte{1}), common.HashH([]byte{2})
+
+	tracker.Record(tx1, Event{Type: EventReceived, Detail: SourceRPC})
+	tracker.Record(tx1, Event{Type: EventValidated})
+	tracker.Record(tx2, Event{Type: EventReceived, Detail: SourcePeer})
+	tracker.Record(tx1, Event{Type: EventPicked, ChainID: 0, Height: 5})
+	// tx1 has outputs for shards 1 and 2, tx2 stays in shard 0
+	tracker.Included(0, 5, []common.Hash{tx1, tx2}, [][]byte{{1, 2}, {}})
+	assert.Len(t, *exported, 1)
+	assert.Equal(t, tx2.String(), (*exported)[0].TxHash)
+
+	tracker.CrossShardDelivered(0, 5, 1, 8)
+	// another height of shard 0 is not about tx1
+	tracker.CrossShardDelivered(0, 6, 2, 9)
+	assert.Len(t, *exported, 1)
+	tracker.CrossShardDelivered(0, 5, 2, 9)
+	assert.Len(t, *exported, 2)
+	span := (*exported)[1]
+	assert.Equal(t, tx1.String(), span.TxHash)
+	assert.False(t, span.Failed)
+	assert.Equal(t, tx1[:16], span.TraceID[:])
+
+	l := tracker.Get(tx1)
+	assert.Equal(t, []EventType{EventReceived, EventValidated, EventPicked, EventIncluded, EventCrossShardDelivered, EventCrossShardDelivered}, eventTypes(l))
+	assert.Equal(t, Event{Type: EventCrossShardDelivered, Time: l.Events[5].Time, ChainID: 2, Height: 9}, l.Events[5])
+	assert.False(t, l.Events[0].Time.IsZero())
+	assert.True(t, l.Done())
+
+	// the copy returned is not changed by new events
+	tracker.Record(tx1, Event{Type: EventEvicted})
+	assert.Len(t, l.Events, 6)
+	assert.Nil(t, tracker.Get(common.HashH([]byte{3})))
+}
+
+func TestTrackerRing(t *testing.T) {
+	tracker := NewTracker(2)
+	tx1, tx2, tx3 := common.HashH([]byte{1}), common.HashH([]byte{2}), common.HashH([]byte{3})
+	tracker.Included(0, 1, []common.Hash{tx1}, [][]byte{{1}})
+	tracker.Record(tx2, Event{Type: EventReceived})
+	tracker.Record(tx1, Event{Type: EventReceived})
+	// tx1 is the oldest tx, it is dropped with its cross shard outputs
+	tracker.Record(tx3, Event{Type: EventReceived})
+	assert.Nil(t, tracker.Get(tx1))
+	assert.NotNil(t, tracker.Get(tx2))
+	assert.NotNil(t, tracker.Get(tx3))
+	assert.Empty(t, tracker.crossShard)
+	tracker.CrossShardDelivered(0, 1, 1, 2)
+	assert.Nil(t, tracker.Get(tx1))
+
+	for i := 0; i < MaxEvents+10; i++ {
+		tracker.Record(tx2, Event{Type: EventRelayed})
+	}
+	assert.Len(t, tracker.Get(tx2).Events, MaxEvents)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	upgradeNoticeInterval = 1 * time.Hour
+	upgradeNoticeTimeout  = 30 * time.Second
+	upgradeNoticeMaxSize  = 64 * 1024
+)
+
+// upgradeNotice is an out-of-band announcement of a new release. It is only
+// informative: consensus upgrades are activated on chain by beacon version bits.
+type upgradeNotice struct {
+	Version string `json:"Version"`
+	Note    string `json:"Note"`
+}
+
+// signedUpgradeNotice holds the notice bytes exactly as signed by the release key
+type signedUpgradeNotice struct {
+	Notice    json.RawMessage `json:"Notice"`
+	Signature string          `json:"Signature"` // hex encoded ed25519 signature of Notice
+}
+
+// parseUpgradeNoticePubKey decodes the hex encoded ed25519 public key used to check notices
+func parseUpgradeNoticePubKey(pubKeyStr string) (ed25519.PublicKey, error) {
+	pubKey, err := hex.DecodeString(pubKeyStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key length is %d, expected %d", len(pubKey), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(pubKey), nil
+}
+
+// verifyUpgradeNotice checks the notice is signed by pubKey and decodes it
+func verifyUpgradeNotice(data []byte, pubKey ed25519.PublicKey) (*upgradeNotice, error) {
+	signed := signedUpgradeNotice{}
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return nil, err
+	}
+	signature, err := hex.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(pubKey, signed.Notice, signature) {
+		return nil, errors.New("invalid signature")
+	}
+	notice := &upgradeNotice{}
+	if err := json.Unmarshal(signed.Notice, notice); err != nil {
+		return nil, err
+	}
+	return notice, nil
+}
+
+// fetchUpgradeNotice downloads and verifies the notice published at url
+func fetchUpgradeNotice(url string, pubKey ed25519.PublicKey) (*upgradeNotice, error) {
+	client := http.Client{Timeout: upgradeNoticeTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, upgradeNoticeMaxSize))
+	if err != nil {
+		return nil, err
+	}
+	return verifyUpgradeNotice(data, pubKey)
+}
+
+// watchUpgradeNotice periodically checks the notice published at url and logs a
+// warning when it announces another version. It never stops the node, notices
+// which are not signed by pubKey are ignored.
+func watchUpgradeNotice(url string, pubKey ed25519.PublicKey, currentVersion string, quit <-chan struct{}) {
+	for {
+		notice, err := fetchUpgradeNotice(url, pubKey)
+		if err != nil {
+			Logger.log.Warnf("Check upgrade notice at %v failed, error %+v", url, err)
+		} else if notice.Version != currentVersion {
+			Logger.log.Warnf("A new version %v is available, you're running version %v: %v", notice.Version, currentVersion, notice.Note)
+		}
+		select {
+		case <-quit:
+			return
+		case <-time.After(upgradeNoticeInterval):
+		}
+	}
+}
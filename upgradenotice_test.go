@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestVerifyUpgradeNotice(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notice := []byte(`{"Version":"1.2.3","Note":"upgrade"}`)
+	signature := ed25519.Sign(privKey, notice)
+	encode := func(notice []byte, signature string) []byte {
+		data, err := json.Marshal(signedUpgradeNotice{Notice: notice, Signature: signature})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	truncated := hex.EncodeToString(signature[:ed25519.SignatureSize-1])
+	flipped := append([]byte{}, signature...)
+	flipped[0] ^= 1
+
+	tests := []struct {
+		name  string
+		data  []byte
+		valid bool
+	}{
+		{"signed by the release key", encode(notice, hex.EncodeToString(signature)), true},
+		{"signed by another key", encode(notice, hex.EncodeToString(ed25519.Sign(otherKey, notice))), false},
+		{"notice changed after signing", encode([]byte(`{"Version":"9.9.9","Note":"upgrade"}`), hex.EncodeToString(signature)), false},
+		{"notice reformatted after signing", encode([]byte(`{"Note":"upgrade","Version":"1.2.3"}`), hex.EncodeToString(signature)), false},
+		{"signature changed", encode(notice, hex.EncodeToString(flipped)), false},
+		{"signature truncated", encode(notice, truncated), false},
+		{"signature not hex", encode(notice, "zz"+hex.EncodeToString(signature)[2:]), false},
+		{"no signature", encode(notice, ""), false},
+		{"signed invalid notice", encode([]byte(`"1.2.3"`), hex.EncodeToString(ed25519.Sign(privKey, []byte(`"1.2.3"`)))), false},
+		{"not json", []byte("1.2.3"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := verifyUpgradeNotice(tt.data, pubKey)
+			if !tt.valid {
+				if err == nil {
+					t.Fatalf("notice %+v accepted", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Version != "1.2.3" || result.Note != "upgrade" {
+				t.Fatalf("wrong notice %+v", result)
+			}
+		})
+	}
+}
+
+func TestParseUpgradeNoticePubKey(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		pubKey string
+		valid  bool
+	}{
+		{"valid", hex.EncodeToString(pubKey), true},
+		{"too short", hex.EncodeToString(pubKey[1:]), false},
+		{"too long", hex.EncodeToString(append(pubKey, 0)), false},
+		{"not hex", "zz" + hex.EncodeToString(pubKey)[2:], false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseUpgradeNoticePubKey(tt.pubKey)
+			if (err == nil) != tt.valid {
+				t.Fatalf("valid %v, error %v", tt.valid, err)
+			}
+			if tt.valid && !result.Equal(pubKey) {
+				t.Fatal("wrong public key")
+			}
+		})
+	}
+}
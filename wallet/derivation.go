@@ -0,0 +1,262 @@
+package wallet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+const (
+	HardenedKeyStart = uint32(0x80000000)
+
+	// DerivationPurpose and CoinType are the first levels of the account
+	// paths m/purpose'/coin'/account'/index
+	DerivationPurpose = uint32(44)
+	CoinType          = uint32(587)
+
+	// DefaultGapLimit is the number of unused addresses in a row after which
+	// the discovery of an account stops
+	DefaultGapLimit = uint32(20)
+
+	// maxShardSearch bounds the indexes tried to find an address in a shard
+	maxShardSearch = 4096
+)
+
+// DerivationPath is a list of child indexes from the master key, the indexes
+// from HardenedKeyStart are written with a '
+type DerivationPath []uint32
+
+// NewAccountPath returns the path m/44'/587'/account'/index
+func NewAccountPath(account uint32, index uint32) DerivationPath {
+	return DerivationPath{
+		DerivationPurpose + HardenedKeyStart,
+		CoinType + HardenedKeyStart,
+		account + HardenedKeyStart,
+		index,
+	}
+}
+
+// ParseDerivationPath parses a path such as m/44'/587'/0'/3
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	elements := strings.Split(strings.TrimSpace(path), "/")
+	if len(elements) == 0 || elements[0] != "m" {
+		return nil, NewWalletError(InvalidDerivationPathErr, fmt.Errorf("path %s does not start with m", path))
+	}
+	result := DerivationPath{}
+	for _, element := range elements[1:] {
+		hardened := strings.HasSuffix(element, "'")
+		index, err := strconv.ParseUint(strings.TrimSuffix(element, "'"), 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, NewWalletError(InvalidDerivationPathErr, fmt.Errorf("index %s of path %s is invalid", element, path))
+		}
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+		result = append(result, uint32(index))
+	}
+	return result, nil
+}
+
+func (path DerivationPath) String() string {
+	elements := []string{"m"}
+	for _, index := range path {
+		if index >= HardenedKeyStart {
+			elements = append(elements, strconv.FormatUint(uint64(index-HardenedKeyStart), 10)+"'")
+		} else {
+			elements = append(elements, strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return strings.Join(elements, "/")
+}
+
+// accountIndex returns the account and the index of a path built by
+// NewAccountPath
+func (path DerivationPath) accountIndex() (uint32, uint32, bool) {
+	if len(path) != 4 || path[0] != DerivationPurpose+HardenedKeyStart || path[1] != CoinType+HardenedKeyStart || path[2] < HardenedKeyStart {
+		return 0, 0, false
+	}
+	return path[2] - HardenedKeyStart, path[3], true
+}
+
+// DerivePath derives the key of path from key, one NewChildKey per level
+func (key *KeyWallet) DerivePath(path DerivationPath) (*KeyWallet, error) {
+	result := key
+	for _, index := range path {
+		child, err := result.NewChildKey(index)
+		if err != nil {
+			return nil, err
+		}
+		result = child
+	}
+	return result, nil
+}
+
+// ShardID returns the shard of the payment address of the key
+func (key *KeyWallet) ShardID() byte {
+	pk := key.KeySet.PaymentAddress.Pk
+	return common.GetShardIDFromLastByte(pk[len(pk)-1])
+}
+
+// InitFromMnemonic initializes the wallet of mnemonic and passPhrase, the
+// passphrase the wallet was created with, without any account. The accounts
+// are found back with DiscoverAccounts
+func (wallet *Wallet) InitFromMnemonic(mnemonic string, passPhrase string, name string) error {
+	if name == "" {
+		return NewWalletError(EmptyWalletNameErr, nil)
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	mnemonicGen := MnemonicGenerator{}
+	entropy, err := mnemonicGen.mnemonicToByteArray(mnemonic, true)
+	if err != nil {
+		return NewWalletError(MnemonicInvalidError, err)
+	}
+	wallet.Name = name
+	wallet.Entropy = entropy
+	wallet.Mnemonic = mnemonic
+	wallet.Seed = mnemonicGen.NewSeed(mnemonic, passPhrase)
+	wallet.PassPhrase = passPhrase
+
+	masterKey, err := NewMasterKey(wallet.Seed)
+	if err != nil {
+		return err
+	}
+	wallet.MasterAccount = AccountWallet{
+		Key:   *masterKey,
+		Child: make([]AccountWallet, 0),
+		Name:  "master",
+	}
+	return nil
+}
+
+// addPathAccount adds the account of key derived at path
+func (wallet *Wallet) addPathAccount(accountName string, key *KeyWallet, path DerivationPath) AccountWallet {
+	if accountName == "" {
+		accountName = fmt.Sprintf("AccountWallet %d", len(wallet.MasterAccount.Child))
+	}
+	account := AccountWallet{
+		Key:   *key,
+		Child: make([]AccountWallet, 0),
+		Name:  accountName,
+		Path:  path.String(),
+	}
+	wallet.MasterAccount.Child = append(wallet.MasterAccount.Child, account)
+	return account
+}
+
+// DerivePathAccount adds the account of the key at path
+func (wallet *Wallet) DerivePathAccount(accountName string, path DerivationPath, passPhrase string) (*AccountWallet, error) {
+	if passPhrase != wallet.PassPhrase {
+		return nil, NewWalletError(WrongPassphraseErr, nil)
+	}
+	if _, err := wallet.getAccountByName(accountName); accountName != "" && err == nil {
+		return nil, NewWalletError(ExistedAccountNameErr, nil)
+	}
+	key, err := wallet.MasterAccount.Key.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.ContainPublicKey(key.KeySet.PaymentAddress.Pk) {
+		return nil, NewWalletError(ExistedAccountErr, nil)
+	}
+	account := wallet.addPathAccount(accountName, key, path)
+	if err := wallet.Save(passPhrase); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// CreatePathAccount adds the account of the first index after the indexes of
+// the account number in the wallet, m/44'/587'/account'/index, whose payment
+// address is in shardID when it is not nil
+func (wallet *Wallet) CreatePathAccount(accountName string, account uint32, shardID *byte, passPhrase string) (*AccountWallet, error) {
+	if passPhrase != wallet.PassPhrase {
+		return nil, NewWalletError(WrongPassphraseErr, nil)
+	}
+	if _, err := wallet.getAccountByName(accountName); accountName != "" && err == nil {
+		return nil, NewWalletError(ExistedAccountNameErr, nil)
+	}
+	index := uint32(0)
+	for _, child := range wallet.MasterAccount.Child {
+		path, err := ParseDerivationPath(child.Path)
+		if err != nil {
+			continue
+		}
+		if childAccount, childIndex, ok := path.accountIndex(); ok && childAccount == account && childIndex >= index {
+			index = childIndex + 1
+		}
+	}
+	for i := 0; i < maxShardSearch; i++ {
+		path := NewAccountPath(account, index)
+		key, err := wallet.MasterAccount.Key.DerivePath(path)
+		if err != nil {
+			return nil, err
+		}
+		if shardID == nil || key.ShardID() == *shardID {
+			result := wallet.addPathAccount(accountName, key, path)
+			if err := wallet.Save(passPhrase); err != nil {
+				return nil, err
+			}
+			return &result, nil
+		}
+		index++
+	}
+	return nil, NewWalletError(NewChildKeyError, fmt.Errorf("no address of shard %d in %d indexes", *shardID, maxShardSearch))
+}
+
+// DiscoverAccounts adds the accounts of the seed of the wallet whose payment
+// address isUsed tells received coins. The indexes of an account are scanned
+// until gapLimit unused addresses in a row, the accounts until one without a
+// used address. The children of the master key created by Init, m/index, are
+// scanned the same way. It returns the accounts added
+func (wallet *Wallet) DiscoverAccounts(isUsed func(key *KeyWallet) (bool, error), gapLimit uint32, passPhrase string) ([]AccountWallet, error) {
+	if passPhrase != wallet.PassPhrase {
+		return nil, NewWalletError(WrongPassphraseErr, nil)
+	}
+	if gapLimit == 0 {
+		gapLimit = DefaultGapLimit
+	}
+	added := make([]AccountWallet, 0)
+	// scan scans the paths of pathOf and returns whether one is used
+	scan := func(pathOf func(index uint32) DerivationPath) (bool, error) {
+		found := false
+		for index, gap := uint32(0), uint32(0); gap < gapLimit; index++ {
+			path := pathOf(index)
+			key, err := wallet.MasterAccount.Key.DerivePath(path)
+			if err != nil {
+				return false, err
+			}
+			used, err := isUsed(key)
+			if err != nil {
+				return false, err
+			}
+			if !used {
+				gap++
+				continue
+			}
+			gap = 0
+			found = true
+			if !wallet.ContainPublicKey(key.KeySet.PaymentAddress.Pk) {
+				added = append(added, wallet.addPathAccount("", key, path))
+			}
+		}
+		return found, nil
+	}
+	for account := uint32(0); account < HardenedKeyStart; account++ {
+		found, err := scan(func(index uint32) DerivationPath { return NewAccountPath(account, index) })
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+	}
+	if _, err := scan(func(index uint32) DerivationPath { return DerivationPath{index} }); err != nil {
+		return nil, err
+	}
+	if err := wallet.Save(passPhrase); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
@@ -0,0 +1,169 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/587'/2'/7")
+	assert.Nil(t, err)
+	assert.Equal(t, NewAccountPath(2, 7), path)
+	assert.Equal(t, "m/44'/587'/2'/7", path.String())
+
+	path, err = ParseDerivationPath("m")
+	assert.Nil(t, err)
+	assert.Len(t, path, 0)
+
+	for _, invalid := range []string{"", "44'/587'", "m/a", "m/-1", "m/2147483648", "m/1''", "m//1"} {
+		_, err := ParseDerivationPath(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestDerivePathMatchesLegacyChildren(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 2)
+	defer cleanup()
+	for i, account := range w.MasterAccount.Child {
+		key, err := w.MasterAccount.Key.DerivePath(DerivationPath{uint32(i)})
+		assert.Nil(t, err)
+		assert.Equal(t, account.Key.KeySet.PaymentAddress.Pk, key.KeySet.PaymentAddress.Pk)
+	}
+}
+
+func TestCreatePathAccount(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 0)
+	defer cleanup()
+
+	_, err := w.CreatePathAccount("a", 0, nil, "wrong")
+	assert.NotNil(t, err)
+
+	// Init adds the account m/0
+	first, err := w.CreatePathAccount("", 0, nil, "12345678")
+	assert.Nil(t, err)
+	assert.Equal(t, "m/44'/587'/0'/0", first.Path)
+	assert.Equal(t, "AccountWallet 1", first.Name)
+
+	shardID := byte(3)
+	inShard, err := w.CreatePathAccount("shard 3", 0, &shardID, "12345678")
+	assert.Nil(t, err)
+	assert.Equal(t, shardID, inShard.Key.ShardID())
+	path, err := ParseDerivationPath(inShard.Path)
+	assert.Nil(t, err)
+	key, err := w.MasterAccount.Key.DerivePath(path)
+	assert.Nil(t, err)
+	assert.Equal(t, inShard.Key, *key)
+
+	// the next index follows the last one of the account
+	_, index, _ := path.accountIndex()
+	next, err := w.CreatePathAccount("next", 0, nil, "12345678")
+	assert.Nil(t, err)
+	assert.Equal(t, NewAccountPath(0, index+1).String(), next.Path)
+
+	other, err := w.CreatePathAccount("other", 1, nil, "12345678")
+	assert.Nil(t, err)
+	assert.Equal(t, "m/44'/587'/1'/0", other.Path)
+
+	_, err = w.CreatePathAccount("other", 1, nil, "12345678")
+	assert.Equal(t, ErrCodeMessage[ExistedAccountNameErr].code, err.(*WalletError).GetCode())
+
+	loaded := walletWithConfig(w.GetConfig())
+	assert.Nil(t, loaded.LoadWallet("12345678"))
+	assert.Equal(t, w.MasterAccount, loaded.MasterAccount)
+}
+
+func TestDiscoverAccounts(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 0)
+	defer cleanup()
+
+	used := map[string]bool{}
+	for _, path := range []DerivationPath{
+		NewAccountPath(0, 0),
+		NewAccountPath(0, 4),  // within the gap limit of 0/0
+		NewAccountPath(0, 10), // after a gap of 5 unused addresses
+		NewAccountPath(1, 2),
+		NewAccountPath(3, 0), // after the unused account 2
+		{1},
+	} {
+		used[path.String()] = true
+	}
+	usedKeys := map[string]string{}
+	for path := range used {
+		parsed, err := ParseDerivationPath(path)
+		assert.Nil(t, err)
+		key, err := w.MasterAccount.Key.DerivePath(parsed)
+		assert.Nil(t, err)
+		usedKeys[string(key.KeySet.PaymentAddress.Pk)] = path
+	}
+	isUsed := func(key *KeyWallet) (bool, error) {
+		_, ok := usedKeys[string(key.KeySet.PaymentAddress.Pk)]
+		return ok, nil
+	}
+
+	mnemonic := w.Mnemonic
+	restored := walletWithConfig(w.GetConfig())
+	assert.Nil(t, restored.InitFromMnemonic(" "+mnemonic+"  ", "12345678", "Wallet"))
+	assert.Equal(t, w.MasterAccount.Key, restored.MasterAccount.Key)
+	assert.Equal(t, w.Entropy, restored.Entropy)
+	assert.NotNil(t, walletWithConfig(w.GetConfig()).InitFromMnemonic(mnemonic+" abandon", "12345678", "Wallet"))
+
+	added, err := restored.DiscoverAccounts(isUsed, 5, "12345678")
+	assert.Nil(t, err)
+	paths := []string{}
+	for _, account := range added {
+		paths = append(paths, account.Path)
+	}
+	assert.Equal(t, []string{"m/44'/587'/0'/0", "m/44'/587'/0'/4", "m/44'/587'/1'/2", "m/1"}, paths)
+
+	// the accounts of the wallet are not added twice
+	added, err = restored.DiscoverAccounts(isUsed, 5, "12345678")
+	assert.Nil(t, err)
+	assert.Len(t, added, 0)
+
+	added, err = restored.DiscoverAccounts(isUsed, 6, "12345678")
+	assert.Nil(t, err)
+	assert.Len(t, added, 1)
+	assert.Equal(t, "m/44'/587'/0'/10", added[0].Path)
+	assert.Len(t, restored.MasterAccount.Child, 5)
+}
+
+func TestImportWatchOnlyAccount(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 2)
+	defer cleanup()
+	other, otherCleanup := newTestWallet(t, "12345678", 2)
+	defer otherCleanup()
+
+	key := other.MasterAccount.Child[0].Key
+	paymentAddress := key.Base58CheckSerialize(PaymentAddressType)
+	readonlyKey := key.Base58CheckSerialize(ReadonlyKeyType)
+
+	_, err := w.ImportWatchOnlyAccount(paymentAddress, other.MasterAccount.Child[1].Key.Base58CheckSerialize(ReadonlyKeyType), "watch", "12345678")
+	assert.Equal(t, ErrCodeMessage[MismatchedReadonlyKeyErr].code, err.(*WalletError).GetCode())
+	_, err = w.ImportWatchOnlyAccount(readonlyKey, readonlyKey, "watch", "12345678")
+	assert.NotNil(t, err)
+
+	account, err := w.ImportWatchOnlyAccount(paymentAddress, readonlyKey, "watch", "12345678")
+	assert.Nil(t, err)
+	assert.True(t, account.IsWatchOnly)
+	assert.Len(t, account.Key.KeySet.PrivateKey, 0)
+	assert.Equal(t, key.KeySet.ReadonlyKey, account.Key.KeySet.ReadonlyKey)
+
+	_, err = w.ImportWatchOnlyAccount(paymentAddress, readonlyKey, "watch 2", "12345678")
+	assert.Equal(t, ErrCodeMessage[ExistedAccountErr].code, err.(*WalletError).GetCode())
+
+	assert.Equal(t, KeySerializedData{}, w.DumpPrivateKey(paymentAddress))
+	assert.Empty(t, w.ExportAccount(uint32(len(w.MasterAccount.Child)-1)))
+	data := w.GetAddressByAccName("watch", nil)
+	assert.Equal(t, paymentAddress, data.PaymentAddress)
+	assert.Equal(t, readonlyKey, data.ReadonlyKey)
+	assert.Empty(t, data.PrivateKey)
+	assert.Empty(t, data.ValidatorKey)
+
+	loaded := walletWithConfig(w.GetConfig())
+	assert.Nil(t, loaded.LoadWallet("12345678"))
+	assert.Equal(t, w.MasterAccount, loaded.MasterAccount)
+
+	assert.Nil(t, loaded.RemoveAccount(paymentAddress, "12345678"))
+	assert.Len(t, loaded.MasterAccount.Child, 2)
+}
@@ -26,6 +26,17 @@ const (
 	NewMnemonicError
 	MnemonicInvalidError
 	InvalidSeserializedKey
+	ExistedScheduledPaymentErr
+	NotFoundScheduledPaymentErr
+	ExistedMultiSigAccountErr
+	NotFoundMultiSigAccountErr
+	NotFoundMultiSigKeyGenErr
+	MultiSigErr
+	UnsupportedKeystoreErr
+	InvalidKDFParamsErr
+	InvalidDerivationPathErr
+	WatchOnlyAccountErr
+	MismatchedReadonlyKeyErr
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -53,6 +64,21 @@ var ErrCodeMessage = map[int]struct {
 	NewMnemonicError:       {-1015, "Can not create mnemonic"},
 	MnemonicInvalidError:   {-1016, "Mnemonic is invalid"},
 	InvalidSeserializedKey: {-1016, "Serialized key is invalid"},
+
+	ExistedScheduledPaymentErr:  {-1017, "Existed scheduled payment"},
+	NotFoundScheduledPaymentErr: {-1018, "Scheduled payment is not found"},
+
+	ExistedMultiSigAccountErr:  {-1019, "Existed multisig account"},
+	NotFoundMultiSigAccountErr: {-1020, "Multisig account is not found"},
+	NotFoundMultiSigKeyGenErr:  {-1021, "Multisig key generation is not found"},
+	MultiSigErr:                {-1022, "Multisig error"},
+
+	UnsupportedKeystoreErr: {-1023, "Wallet file version is not supported"},
+	InvalidKDFParamsErr:    {-1024, "Key derivation params are invalid"},
+
+	InvalidDerivationPathErr: {-1025, "Derivation path is invalid"},
+	WatchOnlyAccountErr:      {-1026, "Account is watch-only"},
+	MismatchedReadonlyKeyErr: {-1027, "Readonly key does not match payment address"},
 }
 
 type WalletError struct {
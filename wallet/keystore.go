@@ -0,0 +1,320 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeystoreVersion is the version of the wallet file written by Save, the
+// wallets of the previous version are a single AES blob keyed with PBKDF2
+const KeystoreVersion = 2
+
+const (
+	KDFArgon2id = "argon2id"
+	KDFScrypt   = "scrypt"
+
+	keystoreSaltLen = 16
+
+	// bounds of the params read from a wallet file, a corrupted header must
+	// not make the node allocate gigabytes
+	maxArgon2Memory  = 4 * 1024 * 1024 // KiB
+	maxArgon2Time    = 64
+	maxScryptN       = 1 << 22
+	maxScryptRTimesP = 1 << 10
+)
+
+// KDFParams are the params of the derivation of the key of a wallet file from
+// its passphrase, they are stored in clear in the header of the file
+type KDFParams struct {
+	Name string
+	Salt []byte
+	// argon2id, Memory is in KiB
+	Time    uint32 `json:",omitempty"`
+	Memory  uint32 `json:",omitempty"`
+	Threads uint8  `json:",omitempty"`
+	// scrypt
+	N int `json:",omitempty"`
+	R int `json:",omitempty"`
+	P int `json:",omitempty"`
+}
+
+// DefaultKDFParams returns the argon2id params of the new wallet files
+func DefaultKDFParams() KDFParams {
+	return KDFParams{Name: KDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+// DefaultScryptKDFParams returns the scrypt params used when scrypt is chosen
+// without params
+func DefaultScryptKDFParams() KDFParams {
+	return KDFParams{Name: KDFScrypt, N: 1 << 17, R: 8, P: 1}
+}
+
+// Validate checks the params are known and within bounds
+func (params KDFParams) Validate() error {
+	switch params.Name {
+	case KDFArgon2id:
+		if params.Time == 0 || params.Time > maxArgon2Time {
+			return NewWalletError(InvalidKDFParamsErr, fmt.Errorf("argon2id time %d is not in [1, %d]", params.Time, maxArgon2Time))
+		}
+		if params.Threads == 0 || params.Memory < 8*uint32(params.Threads) || params.Memory > maxArgon2Memory {
+			return NewWalletError(InvalidKDFParamsErr, fmt.Errorf("argon2id memory %d KiB with %d threads is invalid", params.Memory, params.Threads))
+		}
+	case KDFScrypt:
+		if params.N <= 1 || params.N&(params.N-1) != 0 || params.N > maxScryptN {
+			return NewWalletError(InvalidKDFParamsErr, fmt.Errorf("scrypt N %d is not a power of 2 up to %d", params.N, maxScryptN))
+		}
+		if params.R <= 0 || params.P <= 0 || params.R*params.P > maxScryptRTimesP {
+			return NewWalletError(InvalidKDFParamsErr, fmt.Errorf("scrypt r %d and p %d are invalid", params.R, params.P))
+		}
+	default:
+		return NewWalletError(InvalidKDFParamsErr, fmt.Errorf("unknown kdf %s", params.Name))
+	}
+	if len(params.Salt) != 0 && len(params.Salt) < keystoreSaltLen {
+		return NewWalletError(InvalidKDFParamsErr, fmt.Errorf("salt of %d bytes is too short", len(params.Salt)))
+	}
+	return nil
+}
+
+// deriveKey returns the AES key derived from passPhrase with params
+func (params KDFParams) deriveKey(passPhrase string) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	switch params.Name {
+	case KDFScrypt:
+		key, err := scrypt.Key([]byte(passPhrase), params.Salt, params.N, params.R, params.P, common.AESKeySize)
+		if err != nil {
+			return nil, NewWalletError(InvalidKDFParamsErr, err)
+		}
+		return key, nil
+	default:
+		return argon2.IDKey([]byte(passPhrase), params.Salt, params.Time, params.Memory, params.Threads, common.AESKeySize), nil
+	}
+}
+
+// keystoreKey is the key of the wallet file derived from passPhrase, it is
+// kept to save the wallet without running the kdf each time
+type keystoreKey struct {
+	passPhrase string
+	kdf        KDFParams
+	key        []byte
+}
+
+// newKeystoreKey derives the key of passPhrase with params and a new salt
+func newKeystoreKey(passPhrase string, params KDFParams) (*keystoreKey, error) {
+	params.Salt = make([]byte, keystoreSaltLen)
+	if _, err := rand.Read(params.Salt); err != nil {
+		return nil, NewWalletError(UnexpectedErr, err)
+	}
+	key, err := params.deriveKey(passPhrase)
+	if err != nil {
+		return nil, err
+	}
+	return &keystoreKey{passPhrase: passPhrase, kdf: params, key: key}, nil
+}
+
+// keystoreEntry is a part of the wallet encrypted with AES-GCM
+type keystoreEntry struct {
+	Nonce      []byte
+	CipherText []byte
+}
+
+// keystoreFile is a wallet file: the header, the wallet without its accounts
+// and an entry per account
+type keystoreFile struct {
+	Version  int
+	KDF      KDFParams
+	Wallet   keystoreEntry
+	Accounts []keystoreEntry
+}
+
+// additionalData binds an entry to the header of the file, to the number of
+// accounts and to its place in it, so entries can not be swapped between
+// files or accounts nor removed
+func (file keystoreFile) additionalData(label string) ([]byte, error) {
+	header, err := json.Marshal(struct {
+		Version  int
+		KDF      KDFParams
+		Accounts int
+	}{file.Version, file.KDF, len(file.Accounts)})
+	if err != nil {
+		return nil, NewWalletError(JsonMarshalErr, err)
+	}
+	return append(header, label...), nil
+}
+
+func (file keystoreFile) seal(key []byte, label string, v interface{}) (keystoreEntry, error) {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return keystoreEntry{}, NewWalletError(JsonMarshalErr, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return keystoreEntry{}, err
+	}
+	additionalData, err := file.additionalData(label)
+	if err != nil {
+		return keystoreEntry{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return keystoreEntry{}, NewWalletError(AESEncryptErr, err)
+	}
+	return keystoreEntry{Nonce: nonce, CipherText: aead.Seal(nil, nonce, plaintext, additionalData)}, nil
+}
+
+func (file keystoreFile) open(key []byte, label string, entry keystoreEntry, v interface{}) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	additionalData, err := file.additionalData(label)
+	if err != nil {
+		return err
+	}
+	if len(entry.Nonce) != aead.NonceSize() {
+		return NewWalletError(AESDecryptErr, fmt.Errorf("nonce of %s has %d bytes", label, len(entry.Nonce)))
+	}
+	plaintext, err := aead.Open(nil, entry.Nonce, entry.CipherText, additionalData)
+	if err != nil {
+		// the tag does not match, the passphrase is wrong or the file is corrupted
+		return NewWalletError(WrongPassphraseErr, err)
+	}
+	if err := json.Unmarshal(plaintext, v); err != nil {
+		return NewWalletError(JsonUnmarshalErr, err)
+	}
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, NewWalletError(AESEncryptErr, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, NewWalletError(AESEncryptErr, err)
+	}
+	return aead, nil
+}
+
+func accountEntryLabel(index int) string {
+	return fmt.Sprintf("account/%d", index)
+}
+
+// encryptWallet returns the wallet file of wallet encrypted with storeKey
+func encryptWallet(wallet *Wallet, storeKey *keystoreKey) ([]byte, error) {
+	file := keystoreFile{
+		Version:  KeystoreVersion,
+		KDF:      storeKey.kdf,
+		Accounts: make([]keystoreEntry, len(wallet.MasterAccount.Child)),
+	}
+	base := *wallet
+	base.MasterAccount.Child = nil
+	var err error
+	file.Wallet, err = file.seal(storeKey.key, "wallet", base)
+	if err != nil {
+		return nil, err
+	}
+	for i, account := range wallet.MasterAccount.Child {
+		file.Accounts[i], err = file.seal(storeKey.key, accountEntryLabel(i), account)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.MarshalIndent(file, "", "\t")
+	if err != nil {
+		return nil, NewWalletError(JsonMarshalErr, err)
+	}
+	return data, nil
+}
+
+// decryptWallet reads the wallet file data into wallet and returns its key
+func decryptWallet(data []byte, passPhrase string, wallet *Wallet) (*keystoreKey, error) {
+	file := keystoreFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, NewWalletError(JsonUnmarshalErr, err)
+	}
+	if file.Version != KeystoreVersion {
+		return nil, NewWalletError(UnsupportedKeystoreErr, fmt.Errorf("version %d", file.Version))
+	}
+	if len(file.KDF.Salt) == 0 {
+		return nil, NewWalletError(InvalidKDFParamsErr, fmt.Errorf("salt is empty"))
+	}
+	key, err := file.KDF.deriveKey(passPhrase)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.open(key, "wallet", file.Wallet, wallet); err != nil {
+		return nil, err
+	}
+	wallet.MasterAccount.Child = make([]AccountWallet, len(file.Accounts))
+	for i, entry := range file.Accounts {
+		if err := file.open(key, accountEntryLabel(i), entry, &wallet.MasterAccount.Child[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &keystoreKey{passPhrase: passPhrase, kdf: file.KDF, key: key}, nil
+}
+
+// isKeystoreFile tells a versioned wallet file from a wallet of the first
+// version, which is hex encoded
+func isKeystoreFile(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// writeFileAtomic writes data to a temporary file renamed to fileName, a
+// crash while saving does not leave a truncated wallet
+func writeFileAtomic(fileName string, data []byte) error {
+	tmpFileName := fileName + ".tmp"
+	if err := ioutil.WriteFile(tmpFileName, data, 0600); err != nil {
+		return NewWalletError(WriteFileErr, err)
+	}
+	if err := os.Rename(tmpFileName, fileName); err != nil {
+		os.Remove(tmpFileName)
+		return NewWalletError(WriteFileErr, err)
+	}
+	return nil
+}
+
+// ChangePassPhrase encrypts the wallet with newPassPhrase and a key derived
+// with params and a new salt, then saves it. The seed of the wallet does not
+// change: restoring it from the mnemonic still takes the first passphrase
+func (wallet *Wallet) ChangePassPhrase(oldPassPhrase string, newPassPhrase string, params KDFParams) error {
+	if oldPassPhrase != wallet.PassPhrase {
+		return NewWalletError(WrongPassphraseErr, nil)
+	}
+	storeKey, err := newKeystoreKey(newPassPhrase, params)
+	if err != nil {
+		return err
+	}
+	oldStoreKey := wallet.storeKey
+	wallet.PassPhrase = newPassPhrase
+	wallet.storeKey = storeKey
+	if err := wallet.Save(newPassPhrase); err != nil {
+		wallet.PassPhrase = oldPassPhrase
+		wallet.storeKey = oldStoreKey
+		return err
+	}
+	return nil
+}
+
+// KDFParams returns the params of the key of the wallet file, with their
+// salt, once the wallet is saved or loaded
+func (wallet *Wallet) KDFParams() *KDFParams {
+	if wallet.storeKey == nil {
+		return nil
+	}
+	params := wallet.storeKey.kdf
+	return &params
+}
@@ -0,0 +1,147 @@
+package wallet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWallet(t *testing.T, passPhrase string, numOfAccount uint32) (*Wallet, func()) {
+	dir, err := ioutil.TempDir("", "wallet")
+	assert.Nil(t, err)
+	w := new(Wallet)
+	w.SetConfig(&WalletConfig{DataDir: dir, DataFile: "wallet", DataPath: filepath.Join(dir, "wallet")})
+	assert.Nil(t, w.Init(passPhrase, numOfAccount, "Wallet"))
+	return w, func() { os.RemoveAll(dir) }
+}
+
+func walletWithConfig(config *WalletConfig) *Wallet {
+	w := new(Wallet)
+	w.SetConfig(config)
+	return w
+}
+
+func readKeystoreFile(t *testing.T, w *Wallet) keystoreFile {
+	data, err := ioutil.ReadFile(w.GetConfig().DataPath)
+	assert.Nil(t, err)
+	file := keystoreFile{}
+	assert.Nil(t, json.Unmarshal(data, &file))
+	return file
+}
+
+func TestKeystoreSaveAndLoad(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 3)
+	defer cleanup()
+	assert.Nil(t, w.Save("12345678"))
+
+	file := readKeystoreFile(t, w)
+	assert.Equal(t, KeystoreVersion, file.Version)
+	assert.Equal(t, KDFArgon2id, file.KDF.Name)
+	assert.Len(t, file.KDF.Salt, keystoreSaltLen)
+	assert.Len(t, file.Accounts, 3)
+
+	loaded := new(Wallet)
+	loaded.SetConfig(w.GetConfig())
+	assert.Nil(t, loaded.LoadWallet("12345678"))
+	assert.Equal(t, w.MasterAccount, loaded.MasterAccount)
+	assert.Equal(t, w.Mnemonic, loaded.Mnemonic)
+
+	err := walletWithConfig(w.GetConfig()).LoadWallet("1234")
+	assert.Equal(t, ErrCodeMessage[WrongPassphraseErr].code, err.(*WalletError).GetCode())
+}
+
+func TestKeystoreRejectsTamperedFile(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 2)
+	defer cleanup()
+	assert.Nil(t, w.Save("12345678"))
+	file := readKeystoreFile(t, w)
+
+	load := func(file keystoreFile) error {
+		data, err := json.Marshal(file)
+		assert.Nil(t, err)
+		assert.Nil(t, ioutil.WriteFile(w.GetConfig().DataPath, data, 0600))
+		return walletWithConfig(w.GetConfig()).LoadWallet("12345678")
+	}
+
+	// accounts swapped
+	swapped := file
+	swapped.Accounts = []keystoreEntry{file.Accounts[1], file.Accounts[0]}
+	assert.NotNil(t, load(swapped))
+
+	// last account removed
+	truncated := file
+	truncated.Accounts = file.Accounts[:1]
+	assert.NotNil(t, load(truncated))
+
+	// an account entry repeated
+	extended := file
+	extended.Accounts = []keystoreEntry{file.Accounts[0], file.Accounts[1], file.Accounts[1]}
+	assert.NotNil(t, load(extended))
+
+	// params of the header changed
+	weaker := file
+	weaker.KDF.Time = 1
+	assert.NotNil(t, load(weaker))
+
+	// params out of bounds are not run
+	huge := file
+	huge.KDF.Memory = maxArgon2Memory + 1
+	err := load(huge)
+	assert.Equal(t, ErrCodeMessage[InvalidKDFParamsErr].code, err.(*WalletError).GetCode())
+
+	newer := file
+	newer.Version = KeystoreVersion + 1
+	err = load(newer)
+	assert.Equal(t, ErrCodeMessage[UnsupportedKeystoreErr].code, err.(*WalletError).GetCode())
+
+	assert.Nil(t, load(file))
+}
+
+func TestKeystoreMigratesLegacyWallet(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 2)
+	defer cleanup()
+	data, err := json.Marshal(w)
+	assert.Nil(t, err)
+	cipherText, err := encryptByPassPhrase("12345678", data)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(w.GetConfig().DataPath, []byte(cipherText), 0644))
+
+	loaded := new(Wallet)
+	loaded.SetConfig(w.GetConfig())
+	assert.Nil(t, loaded.LoadWallet("12345678"))
+	assert.Equal(t, w.MasterAccount, loaded.MasterAccount)
+
+	// the file is saved in the current version on load
+	assert.Equal(t, KeystoreVersion, readKeystoreFile(t, w).Version)
+	reloaded := new(Wallet)
+	reloaded.SetConfig(w.GetConfig())
+	assert.Nil(t, reloaded.LoadWallet("12345678"))
+	assert.Equal(t, w.MasterAccount, reloaded.MasterAccount)
+}
+
+func TestKeystoreChangePassPhrase(t *testing.T) {
+	w, cleanup := newTestWallet(t, "12345678", 1)
+	defer cleanup()
+	assert.Nil(t, w.Save("12345678"))
+
+	params := KDFParams{Name: KDFScrypt, N: 1 << 10, R: 8, P: 1}
+	assert.NotNil(t, w.ChangePassPhrase("wrong", "87654321", params))
+	assert.NotNil(t, w.ChangePassPhrase("12345678", "87654321", KDFParams{Name: "pbkdf2"}))
+	assert.Equal(t, "12345678", w.PassPhrase)
+	assert.Nil(t, w.ChangePassPhrase("12345678", "87654321", params))
+
+	file := readKeystoreFile(t, w)
+	assert.Equal(t, KDFScrypt, file.KDF.Name)
+	assert.Equal(t, 1<<10, file.KDF.N)
+
+	assert.NotNil(t, walletWithConfig(w.GetConfig()).LoadWallet("12345678"))
+	loaded := walletWithConfig(w.GetConfig())
+	assert.Nil(t, loaded.LoadWallet("87654321"))
+	assert.Equal(t, "87654321", loaded.PassPhrase)
+	assert.Equal(t, w.MasterAccount, loaded.MasterAccount)
+	assert.Equal(t, KDFScrypt, loaded.KDFParams().Name)
+}
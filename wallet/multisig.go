@@ -0,0 +1,195 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
+)
+
+// MultiSigAccount is the share of the wallet in a k-of-n group key, the
+// payment address of the group receives coins as any other address and a
+// threshold of the participants spends them together
+type MultiSigAccount struct {
+	Name  string
+	Share multisig.KeyShare
+}
+
+// MultiSigKeyGen is a key generation the wallet joined with the transmission
+// key of the account AccountName, the account decrypts the shares sent by the
+// other participants
+type MultiSigKeyGen struct {
+	AccountName string
+	Secret      multisig.KeyGenSecret
+	Round1      multisig.KeyGenRound1
+}
+
+// PaymentAddress returns the base58 check serialized payment address of the
+// group of the account
+func (account MultiSigAccount) PaymentAddress() string {
+	key := KeyWallet{}
+	key.KeySet.PaymentAddress = account.Share.PaymentAddress()
+	return key.Base58CheckSerialize(PaymentAddressType)
+}
+
+// ReadonlyKey returns the base58 check serialized readonly key of the group
+// of the account, it lists the coins of the group
+func (account MultiSigAccount) ReadonlyKey() string {
+	key := KeyWallet{}
+	key.KeySet.ReadonlyKey = account.Share.ReadonlyKey()
+	return key.Base58CheckSerialize(ReadonlyKeyType)
+}
+
+func (wallet *Wallet) getAccountByName(accountName string) (*AccountWallet, error) {
+	for i, account := range wallet.MasterAccount.Child {
+		if account.Name == accountName {
+			return &wallet.MasterAccount.Child[i], nil
+		}
+	}
+	return nil, NewWalletError(NotFoundAccountErr, nil)
+}
+
+// StartMultiSigKeyGen joins session with the account accountName, whose
+// transmission key must be in the session, and returns the round 1 message
+// to send to the coordinator. It returns the same message when it is called
+// twice for a session
+func (wallet *Wallet) StartMultiSigKeyGen(session *multisig.KeyGenSession, accountName string, passPhrase string) (*multisig.KeyGenRound1, error) {
+	if passPhrase != wallet.PassPhrase {
+		return nil, NewWalletError(WrongPassphraseErr, nil)
+	}
+	for _, keyGen := range wallet.MultiSigKeyGens {
+		if keyGen.Secret.SessionID == session.ID {
+			round1 := keyGen.Round1
+			return &round1, nil
+		}
+	}
+	account, err := wallet.getAccountByName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	if account.IsWatchOnly {
+		return nil, NewWalletError(WatchOnlyAccountErr, nil)
+	}
+	index := session.IndexOf(account.Key.KeySet.PaymentAddress.Tk)
+	if index == 0 {
+		return nil, NewWalletError(MultiSigErr, fmt.Errorf("account %s is not a participant of session %s", accountName, session.ID))
+	}
+	secret, round1, err := session.NewRound1(index)
+	if err != nil {
+		return nil, NewWalletError(MultiSigErr, err)
+	}
+	wallet.MultiSigKeyGens = append(wallet.MultiSigKeyGens, MultiSigKeyGen{
+		AccountName: accountName,
+		Secret:      *secret,
+		Round1:      *round1,
+	})
+	if err := wallet.Save(passPhrase); err != nil {
+		return nil, err
+	}
+	return round1, nil
+}
+
+// FinishMultiSigKeyGen derives the share of the wallet from the complete
+// session and stores it as the multisig account name
+func (wallet *Wallet) FinishMultiSigKeyGen(session *multisig.KeyGenSession, name string, passPhrase string) (*MultiSigAccount, error) {
+	if passPhrase != wallet.PassPhrase {
+		return nil, NewWalletError(WrongPassphraseErr, nil)
+	}
+	if _, err := wallet.GetMultiSigAccount(name); err == nil {
+		return nil, NewWalletError(ExistedMultiSigAccountErr, nil)
+	}
+	for i, keyGen := range wallet.MultiSigKeyGens {
+		if keyGen.Secret.SessionID != session.ID {
+			continue
+		}
+		account, err := wallet.getAccountByName(keyGen.AccountName)
+		if err != nil {
+			return nil, err
+		}
+		share, err := session.FinishKeyGen(&keyGen.Secret, account.Key.KeySet.ReadonlyKey.Rk)
+		if err != nil {
+			return nil, NewWalletError(MultiSigErr, err)
+		}
+		multiSigAccount := MultiSigAccount{Name: name, Share: *share}
+		wallet.MultiSigAccounts = append(wallet.MultiSigAccounts, multiSigAccount)
+		wallet.MultiSigKeyGens = append(wallet.MultiSigKeyGens[:i], wallet.MultiSigKeyGens[i+1:]...)
+		if err := wallet.Save(passPhrase); err != nil {
+			return nil, err
+		}
+		return &multiSigAccount, nil
+	}
+	return nil, NewWalletError(NotFoundMultiSigKeyGenErr, nil)
+}
+
+// GetMultiSigAccount returns the multisig account name
+func (wallet *Wallet) GetMultiSigAccount(name string) (*MultiSigAccount, error) {
+	for _, account := range wallet.MultiSigAccounts {
+		if account.Name == name {
+			result := account
+			return &result, nil
+		}
+	}
+	return nil, NewWalletError(NotFoundMultiSigAccountErr, nil)
+}
+
+// ListMultiSigAccounts returns a copy of the multisig accounts of the wallet
+func (wallet *Wallet) ListMultiSigAccounts() []MultiSigAccount {
+	return append([]MultiSigAccount{}, wallet.MultiSigAccounts...)
+}
+
+// MultiSigSignerState returns the state of the wallet in the signing session
+// sessionID, an empty state if the wallet did not sign for it yet
+func (wallet *Wallet) MultiSigSignerState(sessionID string) *multisig.SignerState {
+	for _, state := range wallet.MultiSigSigners {
+		if state.SessionID == sessionID {
+			result := state
+			return &result
+		}
+	}
+	return new(multisig.SignerState)
+}
+
+// SaveMultiSigSignerState stores state and saves the wallet, the state is
+// dropped once the wallet answered the last round
+func (wallet *Wallet) SaveMultiSigSignerState(state *multisig.SignerState, passPhrase string) error {
+	if passPhrase != wallet.PassPhrase {
+		return NewWalletError(WrongPassphraseErr, nil)
+	}
+	states := make([]multisig.SignerState, 0, len(wallet.MultiSigSigners)+1)
+	for _, saved := range wallet.MultiSigSigners {
+		if saved.SessionID != state.SessionID {
+			states = append(states, saved)
+		}
+	}
+	if state.Round < multisig.SignRound6 {
+		states = append(states, *state)
+	}
+	wallet.MultiSigSigners = states
+	return wallet.Save(passPhrase)
+}
+
+// WriteExchangeFile writes v as the JSON file exchanged between the
+// coordinator and the participants of a key generation or a signing
+func WriteExchangeFile(fileName string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return NewWalletError(JsonMarshalErr, err)
+	}
+	if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+		return NewWalletError(WriteFileErr, err)
+	}
+	return nil
+}
+
+// ReadExchangeFile reads the JSON file fileName into v
+func ReadExchangeFile(fileName string, v interface{}) error {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return NewWalletError(ReadFileErr, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return NewWalletError(JsonUnmarshalErr, err)
+	}
+	return nil
+}
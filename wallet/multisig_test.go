@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiSigKeyGen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	wallets := make([]*Wallet, 3)
+	transmissionKeys := make([][]byte, len(wallets))
+	for i := range wallets {
+		wallets[i] = new(Wallet)
+		dataPath := filepath.Join(dir, "wallet"+string(rune('0'+i)))
+		wallets[i].SetConfig(&WalletConfig{DataDir: dir, DataFile: filepath.Base(dataPath), DataPath: dataPath})
+		assert.Nil(t, wallets[i].Init("12345678", 1, "Wallet"))
+		account, err := wallets[i].getAccountByName("AccountWallet 0")
+		assert.Nil(t, err)
+		transmissionKeys[i] = account.Key.KeySet.PaymentAddress.Tk
+	}
+	session, err := multisig.NewKeyGenSession(2, transmissionKeys)
+	assert.Nil(t, err)
+	sessionFile := filepath.Join(dir, "keygen.json")
+	assert.Nil(t, WriteExchangeFile(sessionFile, session))
+
+	for i, w := range wallets {
+		participantSession := new(multisig.KeyGenSession)
+		assert.Nil(t, ReadExchangeFile(sessionFile, participantSession))
+		_, err := w.StartMultiSigKeyGen(participantSession, "AccountWallet 0", "wrong")
+		assert.NotNil(t, err)
+		round1, err := w.StartMultiSigKeyGen(participantSession, "AccountWallet 0", "12345678")
+		assert.Nil(t, err)
+		again, err := w.StartMultiSigKeyGen(participantSession, "AccountWallet 0", "12345678")
+		assert.Nil(t, err)
+		assert.Equal(t, round1, again)
+		_, err = w.FinishMultiSigKeyGen(participantSession, "treasury", "12345678")
+		assert.NotNil(t, err)
+
+		roundFile := filepath.Join(dir, "round1.json")
+		assert.Nil(t, WriteExchangeFile(roundFile, round1))
+		received := new(multisig.KeyGenRound1)
+		assert.Nil(t, ReadExchangeFile(roundFile, received))
+		assert.Nil(t, session.AddRound1(received), "participant %d", i)
+	}
+
+	addresses := map[string]bool{}
+	for _, w := range wallets {
+		account, err := w.FinishMultiSigKeyGen(session, "treasury", "12345678")
+		assert.Nil(t, err)
+		addresses[account.PaymentAddress()] = true
+		_, err = w.FinishMultiSigKeyGen(session, "treasury", "12345678")
+		assert.NotNil(t, err)
+
+		// the account and the signer states are saved with the wallet
+		state := w.MultiSigSignerState("session")
+		assert.Equal(t, "", state.SessionID)
+		state.SessionID = "session"
+		state.Round = multisig.SignRound2
+		assert.Nil(t, w.SaveMultiSigSignerState(state, "12345678"))
+		loaded := new(Wallet)
+		loaded.SetConfig(w.GetConfig())
+		assert.Nil(t, loaded.LoadWallet("12345678"))
+		assert.Equal(t, w.ListMultiSigAccounts(), loaded.ListMultiSigAccounts())
+		assert.Equal(t, multisig.SignRound2, loaded.MultiSigSignerState("session").Round)
+		assert.Empty(t, loaded.MultiSigKeyGens)
+
+		state.Round = multisig.SignRound6
+		assert.Nil(t, w.SaveMultiSigSignerState(state, "12345678"))
+		assert.Empty(t, w.MultiSigSigners)
+	}
+	assert.Len(t, addresses, 1)
+}
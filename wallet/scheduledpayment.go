@@ -0,0 +1,69 @@
+package wallet
+
+// ScheduledPayment is a pre-signed time-locked tx kept by the wallet, it is
+// broadcast once the beacon height reaches NotBefore
+type ScheduledPayment struct {
+	TxID            string
+	ShardID         byte
+	Base58CheckData string
+	IsPrivacyToken  bool
+	NotBefore       uint64
+	// NotAfter is the last beacon height where the tx is valid, 0 if it does
+	// not expire
+	NotAfter uint64
+}
+
+// IsDueAt returns whether the payment can be broadcast at beaconHeight
+func (payment ScheduledPayment) IsDueAt(beaconHeight uint64) bool {
+	return beaconHeight >= payment.NotBefore && !payment.IsExpiredAt(beaconHeight)
+}
+
+// IsExpiredAt returns whether the tx of the payment is no longer valid at
+// beaconHeight
+func (payment ScheduledPayment) IsExpiredAt(beaconHeight uint64) bool {
+	return payment.NotAfter > 0 && beaconHeight > payment.NotAfter
+}
+
+// AddScheduledPayment stores payment in the wallet and saves it
+func (wallet *Wallet) AddScheduledPayment(payment ScheduledPayment, passPhrase string) error {
+	if passPhrase != wallet.PassPhrase {
+		return NewWalletError(WrongPassphraseErr, nil)
+	}
+	for _, scheduled := range wallet.ScheduledPayments {
+		if scheduled.TxID == payment.TxID {
+			return NewWalletError(ExistedScheduledPaymentErr, nil)
+		}
+	}
+	wallet.ScheduledPayments = append(wallet.ScheduledPayments, payment)
+	return wallet.Save(passPhrase)
+}
+
+// RemoveScheduledPayments removes the payments of txIDs from the wallet and
+// saves it
+func (wallet *Wallet) RemoveScheduledPayments(txIDs []string, passPhrase string) error {
+	if passPhrase != wallet.PassPhrase {
+		return NewWalletError(WrongPassphraseErr, nil)
+	}
+	removed := map[string]bool{}
+	for _, txID := range txIDs {
+		removed[txID] = true
+	}
+	payments := make([]ScheduledPayment, 0, len(wallet.ScheduledPayments))
+	for _, payment := range wallet.ScheduledPayments {
+		if removed[payment.TxID] {
+			delete(removed, payment.TxID)
+			continue
+		}
+		payments = append(payments, payment)
+	}
+	if len(removed) > 0 {
+		return NewWalletError(NotFoundScheduledPaymentErr, nil)
+	}
+	wallet.ScheduledPayments = payments
+	return wallet.Save(passPhrase)
+}
+
+// ListScheduledPayments returns a copy of the payments of the wallet
+func (wallet *Wallet) ListScheduledPayments() []ScheduledPayment {
+	return append([]ScheduledPayment{}, wallet.ScheduledPayments...)
+}
@@ -0,0 +1,42 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledPayments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	w := new(Wallet)
+	w.SetConfig(&WalletConfig{DataDir: dir, DataFile: "wallet", DataPath: filepath.Join(dir, "wallet")})
+	assert.Nil(t, w.Init("12345678", 1, "Wallet"))
+
+	payment := ScheduledPayment{TxID: "tx1", NotBefore: 10, NotAfter: 20}
+	assert.False(t, payment.IsDueAt(9))
+	assert.True(t, payment.IsDueAt(10))
+	assert.True(t, payment.IsDueAt(20))
+	assert.True(t, payment.IsExpiredAt(21))
+	assert.False(t, ScheduledPayment{NotBefore: 10}.IsExpiredAt(1e9))
+
+	assert.NotNil(t, w.AddScheduledPayment(payment, "wrong"))
+	assert.Nil(t, w.AddScheduledPayment(payment, "12345678"))
+	assert.NotNil(t, w.AddScheduledPayment(payment, "12345678"))
+	assert.Nil(t, w.AddScheduledPayment(ScheduledPayment{TxID: "tx2", NotBefore: 30}, "12345678"))
+
+	// the payments are saved with the wallet
+	loaded := new(Wallet)
+	loaded.SetConfig(w.GetConfig())
+	assert.Nil(t, loaded.LoadWallet("12345678"))
+	assert.Equal(t, w.ListScheduledPayments(), loaded.ListScheduledPayments())
+
+	assert.NotNil(t, w.RemoveScheduledPayments([]string{"tx1", "tx3"}, "12345678"))
+	assert.Len(t, w.ListScheduledPayments(), 2)
+	assert.Nil(t, w.RemoveScheduledPayments([]string{"tx1"}, "12345678"))
+	assert.Equal(t, []ScheduledPayment{{TxID: "tx2", NotBefore: 30}}, w.ListScheduledPayments())
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/privacy/multisig"
 	"io/ioutil"
 )
 
@@ -15,6 +16,12 @@ type AccountWallet struct {
 	Key        KeyWallet
 	Child      []AccountWallet
 	IsImported bool
+	// Path is the derivation path of the key from the master key, empty for
+	// the accounts of Init, CreateNewAccount and the imported accounts
+	Path string `json:",omitempty"`
+	// IsWatchOnly accounts have a payment address and a readonly key but no
+	// private key, they can list their coins but not spend them
+	IsWatchOnly bool `json:",omitempty"`
 }
 
 type Wallet struct {
@@ -24,7 +31,18 @@ type Wallet struct {
 	Mnemonic      string
 	MasterAccount AccountWallet
 	Name          string
-	config        *WalletConfig
+	// ScheduledPayments are the pre-signed time-locked txs to broadcast
+	// once their window opens
+	ScheduledPayments []ScheduledPayment
+	// MultiSigAccounts hold the shares of the group keys of the wallet,
+	// MultiSigKeyGens and MultiSigSigners the state of the key generations
+	// and signings in progress
+	MultiSigAccounts []MultiSigAccount
+	MultiSigKeyGens  []MultiSigKeyGen
+	MultiSigSigners  []multisig.SignerState
+	config           *WalletConfig
+	// storeKey is the key of the wallet file
+	storeKey *keystoreKey
 }
 
 type WalletConfig struct {
@@ -167,20 +185,22 @@ func (wallet *Wallet) CreateNewAccount(accountName string, shardID *byte) (*Acco
 }
 
 // ExportAccount returns a private key string of account at childIndex in wallet
-// It is base58 check serialized
+// It is base58 check serialized, it is empty for a watch-only account
 func (wallet *Wallet) ExportAccount(childIndex uint32) string {
-	if int(childIndex) >= len(wallet.MasterAccount.Child) {
+	if int(childIndex) >= len(wallet.MasterAccount.Child) || wallet.MasterAccount.Child[childIndex].IsWatchOnly {
 		return ""
 	}
 	return wallet.MasterAccount.Child[childIndex].Key.Base58CheckSerialize(PriKeyType)
 }
 
+// RemoveAccount removes the account of privateKeyStr, the watch-only accounts
+// are removed by their payment address
 func (wallet *Wallet) RemoveAccount(privateKeyStr string, passPhrase string) error {
 	if passPhrase != wallet.PassPhrase {
 		return NewWalletError(WrongPassphraseErr, nil)
 	}
 	for i, account := range wallet.MasterAccount.Child {
-		if account.Key.Base58CheckSerialize(PriKeyType) == privateKeyStr {
+		if account.Key.Base58CheckSerialize(PriKeyType) == privateKeyStr || (account.IsWatchOnly && account.Key.Base58CheckSerialize(PaymentAddressType) == privateKeyStr) {
 			wallet.MasterAccount.Child = append(wallet.MasterAccount.Child[:i], wallet.MasterAccount.Child[i+1:]...)
 			err := wallet.Save(passPhrase)
 			if err != nil {
@@ -235,7 +255,57 @@ func (wallet *Wallet) ImportAccount(privateKeyStr string, accountName string, pa
 	return &account, nil
 }
 
-// Save saves encrypted wallet (using AES encryption scheme) in config data file of wallet
+// ImportWatchOnlyAccount adds the watch-only account of paymentAddressStr and
+// readonlyKeyStr, which must be the readonly key of the payment address
+// It returns AccountWallet which is imported and errors (if any)
+func (wallet *Wallet) ImportWatchOnlyAccount(paymentAddressStr string, readonlyKeyStr string, accountName string, passPhrase string) (*AccountWallet, error) {
+	if passPhrase != wallet.PassPhrase {
+		return nil, NewWalletError(WrongPassphraseErr, nil)
+	}
+	paymentAddress, err := Base58CheckDeserialize(paymentAddressStr)
+	if err != nil {
+		return nil, err
+	}
+	readonlyKey, err := Base58CheckDeserialize(readonlyKeyStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(paymentAddress.KeySet.PaymentAddress.Pk) == 0 || len(readonlyKey.KeySet.ReadonlyKey.Rk) == 0 {
+		return nil, NewWalletError(InvalidKeyTypeErr, nil)
+	}
+	if !bytes.Equal(paymentAddress.KeySet.PaymentAddress.Pk, readonlyKey.KeySet.ReadonlyKey.Pk) {
+		return nil, NewWalletError(MismatchedReadonlyKeyErr, nil)
+	}
+	for _, account := range wallet.MasterAccount.Child {
+		if bytes.Equal(account.Key.KeySet.PaymentAddress.Pk, paymentAddress.KeySet.PaymentAddress.Pk) {
+			return nil, NewWalletError(ExistedAccountErr, nil)
+		}
+		if account.Name == accountName {
+			return nil, NewWalletError(ExistedAccountNameErr, nil)
+		}
+	}
+
+	key := KeyWallet{}
+	key.KeySet.PaymentAddress = paymentAddress.KeySet.PaymentAddress
+	key.KeySet.ReadonlyKey = readonlyKey.KeySet.ReadonlyKey
+	account := AccountWallet{
+		Key:         key,
+		Child:       make([]AccountWallet, 0),
+		IsImported:  true,
+		IsWatchOnly: true,
+		Name:        accountName,
+	}
+	wallet.MasterAccount.Child = append(wallet.MasterAccount.Child, account)
+	err = wallet.Save(wallet.PassPhrase)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Save saves encrypted wallet in config data file of wallet, the accounts are
+// encrypted one by one with AES-GCM and a key derived from password with the
+// kdf of the wallet file, argon2id by default
 // It returns error if any
 func (wallet *Wallet) Save(password string) error {
 	if password == "" {
@@ -246,30 +316,32 @@ func (wallet *Wallet) Save(password string) error {
 		return NewWalletError(WrongPassphraseErr, nil)
 	}
 
-	// parse to byte[]
-	data, err := json.Marshal(*wallet)
-	if err != nil {
-		Logger.log.Error(err)
-		return NewWalletError(JsonMarshalErr, err)
+	// derive the key once, with the params of the loaded file if any
+	if wallet.storeKey == nil || wallet.storeKey.passPhrase != password {
+		params := DefaultKDFParams()
+		if wallet.storeKey != nil {
+			params = wallet.storeKey.kdf
+		}
+		storeKey, err := newKeystoreKey(password, params)
+		if err != nil {
+			return err
+		}
+		wallet.storeKey = storeKey
 	}
 
 	// encrypt data
-	cipherText, err := encryptByPassPhrase(password, data)
+	data, err := encryptWallet(wallet, wallet.storeKey)
 	if err != nil {
 		Logger.log.Error(err)
-		return NewWalletError(UnexpectedErr, err)
+		return err
 	}
 	// and
 	// save file
-	cipherTexInBytes := []byte(cipherText)
-	err = ioutil.WriteFile(wallet.config.DataPath, cipherTexInBytes, 0644)
-	if err != nil {
-		return NewWalletError(WriteFileErr, err)
-	}
-	return nil
+	return writeFileAtomic(wallet.config.DataPath, data)
 }
 
 // LoadWallet loads encrypted wallet from file and then decrypts it to wallet struct
+// A wallet file of the first version is saved again in the current version
 // It returns error if any
 func (wallet *Wallet) LoadWallet(password string) error {
 	// read file and decrypt
@@ -277,6 +349,15 @@ func (wallet *Wallet) LoadWallet(password string) error {
 	if err != nil {
 		return NewWalletError(ReadFileErr, err)
 	}
+	if isKeystoreFile(bytesData) {
+		storeKey, err := decryptWallet(bytesData, password, wallet)
+		if err != nil {
+			return err
+		}
+		wallet.storeKey = storeKey
+		return nil
+	}
+
 	bufBytes, err := decryptByPassPhrase(password, string(bytesData))
 	if err != nil {
 		return NewWalletError(AESDecryptErr, err)
@@ -287,6 +368,16 @@ func (wallet *Wallet) LoadWallet(password string) error {
 	if err != nil {
 		return NewWalletError(JsonUnmarshalErr, err)
 	}
+
+	// migrate to the current version
+	if password != wallet.PassPhrase {
+		Logger.log.Warnf("Wallet %s is not migrated, its passphrase does not match", wallet.config.DataPath)
+		return nil
+	}
+	if err := wallet.Save(password); err != nil {
+		return err
+	}
+	Logger.log.Infof("Wallet %s migrated to version %d", wallet.config.DataPath, KeystoreVersion)
 	return nil
 }
 
@@ -297,7 +388,7 @@ func (wallet *Wallet) LoadWallet(password string) error {
 func (wallet *Wallet) DumpPrivateKey(paymentAddrSerialized string) KeySerializedData {
 	for _, account := range wallet.MasterAccount.Child {
 		address := account.Key.Base58CheckSerialize(PaymentAddressType)
-		if address == paymentAddrSerialized {
+		if address == paymentAddrSerialized && !account.IsWatchOnly {
 			key := KeySerializedData{
 				PrivateKey: account.Key.Base58CheckSerialize(PriKeyType),
 			}
@@ -318,8 +409,10 @@ func (wallet *Wallet) GetAddressByAccName(accountName string, shardID *byte) Key
 				PaymentAddress: account.Key.Base58CheckSerialize(PaymentAddressType),
 				Pubkey:         hex.EncodeToString(account.Key.KeySet.PaymentAddress.Pk),
 				ReadonlyKey:    account.Key.Base58CheckSerialize(ReadonlyKeyType),
-				PrivateKey:     account.Key.Base58CheckSerialize(PriKeyType),
-				ValidatorKey:   base58.Base58Check{}.Encode(common.HashB(common.HashB(account.Key.KeySet.PrivateKey)), common.ZeroByte),
+			}
+			if !account.IsWatchOnly {
+				key.PrivateKey = account.Key.Base58CheckSerialize(PriKeyType)
+				key.ValidatorKey = base58.Base58Check{}.Encode(common.HashB(common.HashB(account.Key.KeySet.PrivateKey)), common.ZeroByte)
 			}
 			return key
 		}
@@ -345,7 +438,9 @@ func (wallet *Wallet) GetAddressesByAccName(accountName string) []KeySerializedD
 				PaymentAddress: account.Key.Base58CheckSerialize(PaymentAddressType),
 				Pubkey:         hex.EncodeToString(account.Key.KeySet.PaymentAddress.Pk),
 				ReadonlyKey:    account.Key.Base58CheckSerialize(ReadonlyKeyType),
-				ValidatorKey:   base58.Base58Check{}.Encode(common.HashB(common.HashB(account.Key.KeySet.PrivateKey)), common.ZeroByte),
+			}
+			if !account.IsWatchOnly {
+				item.ValidatorKey = base58.Base58Check{}.Encode(common.HashB(common.HashB(account.Key.KeySet.PrivateKey)), common.ZeroByte)
 			}
 			result = append(result, item)
 		}
@@ -472,7 +472,7 @@ func TestWalletLoadWalletWithUnmatchedPassPhrase(t *testing.T) {
 	wallet2.SetConfig(wallet.config)
 	err := wallet2.LoadWallet(passPhrase2)
 
-	assert.Equal(t, ErrCodeMessage[JsonUnmarshalErr].code, err.(*WalletError).GetCode())
+	assert.Equal(t, ErrCodeMessage[WrongPassphraseErr].code, err.(*WalletError).GetCode())
 }
 
 func TestWalletLoadWalletWithEmptyPassPhrase(t *testing.T) {
@@ -487,7 +487,7 @@ func TestWalletLoadWalletWithEmptyPassPhrase(t *testing.T) {
 	wallet2.SetConfig(wallet.config)
 	err := wallet2.LoadWallet(passPhrase2)
 
-	assert.Equal(t, ErrCodeMessage[JsonUnmarshalErr].code, err.(*WalletError).GetCode())
+	assert.Equal(t, ErrCodeMessage[WrongPassphraseErr].code, err.(*WalletError).GetCode())
 }
 
 func TestWalletLoadWalletWithWrongConfig(t *testing.T) {